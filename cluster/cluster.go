@@ -0,0 +1,162 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cluster provides a view on the Yorc servers sharing a same Consul cluster.
+//
+// Each running Yorc server registers itself as a member of the cluster. A member publishes its
+// state, the leaderships it holds (monitoring, scheduling, ...) and the work it currently owns
+// (task executions, monitoring checks, scheduled actions).
+// A member may be drained before a maintenance: it stops taking new task executions and gives up
+// its leaderships so that other members take over its work.
+package cluster
+
+import (
+	"encoding/json"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/helper/consulutil"
+)
+
+const (
+	// StateActive is the state of a member that takes work
+	StateActive = "active"
+	// StateDraining is the state of a member that has been requested to drain but still owns some work
+	StateDraining = "draining"
+	// StateDrained is the state of a drained member that doesn't own work anymore
+	StateDrained = "drained"
+)
+
+const (
+	// RoleMonitoringLeader is the role of the member that runs the monitoring checks
+	RoleMonitoringLeader = "monitoring_leader"
+	// RoleSchedulingLeader is the role of the member that runs the scheduled actions
+	RoleSchedulingLeader = "scheduling_leader"
+	// RoleSecretLeasesLeader is the role of the member that renews the secrets leases
+	RoleSecretLeasesLeader = "secret_leases_leader"
+	// RoleDeploymentsExpiryLeader is the role of the member that expires deployments having a time-to-live
+	RoleDeploymentsExpiryLeader = "deployments_expiry_leader"
+	// RoleTasksApprovalLeader is the role of the member that cancels tasks whose approval expired
+	RoleTasksApprovalLeader = "tasks_approval_leader"
+	// RoleRolloutsLeader is the role of the member that drives the rollouts of workflows on scalable nodes
+	RoleRolloutsLeader = "rollouts_leader"
+)
+
+// WorkKind is a kind of work that may be owned by a member
+type WorkKind string
+
+const (
+	// WorkTaskExecution is the kind of work for task executions
+	WorkTaskExecution WorkKind = "task_executions"
+	// WorkCheck is the kind of work for monitoring checks
+	WorkCheck WorkKind = "checks"
+	// WorkScheduledAction is the kind of work for scheduled actions
+	WorkScheduledAction WorkKind = "scheduled_actions"
+)
+
+var (
+	membersPrefix = path.Join(consulutil.YorcServicePrefix, "cluster", "members")
+	drainPrefix   = path.Join(consulutil.YorcServicePrefix, "cluster", "drain")
+)
+
+// Work is the work owned by a member
+type Work struct {
+	TaskExecutions   []string `json:"task_executions"`
+	Checks           []string `json:"checks"`
+	ScheduledActions []string `json:"scheduled_actions"`
+}
+
+// Member is a Yorc server member of the cluster
+type Member struct {
+	ServerID   string    `json:"server_id"`
+	NodeName   string    `json:"node_name"`
+	State      string    `json:"state"`
+	Roles      []string  `json:"roles"`
+	Work       Work      `json:"work"`
+	StartDate  time.Time `json:"start_date"`
+	LastUpdate time.Time `json:"last_update"`
+}
+
+// Members returns the live members of the cluster sorted by server ID
+func Members(cc *api.Client) ([]Member, error) {
+	kvps, _, err := cc.KV().List(membersPrefix+"/", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	members := make([]Member, 0, len(kvps))
+	for _, kvp := range kvps {
+		if kvp.Session == "" {
+			// Not held by a live server
+			continue
+		}
+		var m Member
+		err = json.Unmarshal(kvp.Value, &m)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read cluster member %q", path.Base(kvp.Key))
+		}
+		members = append(members, m)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].ServerID < members[j].ServerID
+	})
+	return members, nil
+}
+
+// GetMember returns the live member of the cluster with the given server ID
+//
+// It returns nil if there is no such member.
+func GetMember(cc *api.Client, serverID string) (*Member, error) {
+	kvp, _, err := cc.KV().Get(path.Join(membersPrefix, serverID), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp == nil || kvp.Session == "" {
+		return nil, nil
+	}
+	m := new(Member)
+	err = json.Unmarshal(kvp.Value, m)
+	return m, errors.Wrapf(err, "failed to read cluster member %q", serverID)
+}
+
+// Drain requests a server to be drained
+//
+// The drain request is persistent: a drained server that restarts stays drained until Resume is called.
+func Drain(cc *api.Client, serverID string) error {
+	_, err := cc.KV().Put(&api.KVPair{Key: path.Join(drainPrefix, serverID), Value: []byte("true")}, nil)
+	return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+}
+
+// Resume cancels a drain request on a server, it starts taking work again
+func Resume(cc *api.Client, serverID string) error {
+	_, err := cc.KV().Delete(path.Join(drainPrefix, serverID), nil)
+	return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+}
+
+// IsDrainRequested returns true if a drain of the given server has been requested
+func IsDrainRequested(cc *api.Client, serverID string) (bool, error) {
+	draining, _, err := isDrainRequested(cc, serverID, 0, 0)
+	return draining, err
+}
+
+func isDrainRequested(cc *api.Client, serverID string, waitIndex uint64, waitTime time.Duration) (bool, uint64, error) {
+	kvp, meta, err := cc.KV().Get(path.Join(drainPrefix, serverID), &api.QueryOptions{WaitIndex: waitIndex, WaitTime: waitTime})
+	if err != nil {
+		return false, 0, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	return kvp != nil && string(kvp.Value) == "true", meta.LastIndex, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/testutil"
+)
+
+// The aim of this function is to run all package tests with consul server dependency with only one consul server start
+func TestRunConsulClusterPackageTests(t *testing.T) {
+	cfg := testutil.SetupTestConfig(t)
+	srv, client := testutil.NewTestConsulInstance(t, &cfg)
+	defer func() {
+		srv.Stop()
+		os.RemoveAll(cfg.WorkingDirectory)
+	}()
+
+	t.Run("groupCluster", func(t *testing.T) {
+		t.Run("testMemberDrain", func(t *testing.T) {
+			testMemberDrain(t, cfg, client)
+		})
+	})
+}
+
+func waitForMemberState(t *testing.T, cc *api.Client, serverID, state string) *Member {
+	var m *Member
+	var err error
+	for i := 0; i < 50; i++ {
+		m, err = GetMember(cc, serverID)
+		require.NoError(t, err)
+		if m != nil && m.State == state {
+			return m
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	require.FailNowf(t, "unexpected member state", "member %q: %+v, expecting state %q", serverID, m, state)
+	return nil
+}
+
+func testMemberDrain(t *testing.T, cfg config.Configuration, cc *api.Client) {
+	cfg.ServerID = "testMemberDrain"
+	chShutdown := make(chan struct{})
+	err := Start(cfg, cc, chShutdown)
+	require.NoError(t, err)
+
+	AddWork(WorkTaskExecution, "exec1")
+	m := waitForMemberState(t, cc, cfg.ServerID, StateActive)
+	require.Equal(t, []string{"exec1"}, m.Work.TaskExecutions)
+
+	members, err := Members(cc)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	require.Equal(t, cfg.ServerID, members[0].ServerID)
+
+	err = Drain(cc, cfg.ServerID)
+	require.NoError(t, err)
+	waitForMemberState(t, cc, cfg.ServerID, StateDraining)
+	require.True(t, IsDraining())
+
+	RemoveWork(WorkTaskExecution, "exec1")
+	waitForMemberState(t, cc, cfg.ServerID, StateDrained)
+
+	err = Resume(cc, cfg.ServerID)
+	require.NoError(t, err)
+	waitForMemberState(t, cc, cfg.ServerID, StateActive)
+	draining, err := IsDrainRequested(cc, cfg.ServerID)
+	require.NoError(t, err)
+	require.False(t, draining)
+
+	close(chShutdown)
+	for i := 0; i < 50 && m != nil; i++ {
+		time.Sleep(100 * time.Millisecond)
+		m, err = GetMember(cc, cfg.ServerID)
+		require.NoError(t, err)
+	}
+	require.Nil(t, m, "member should leave the cluster on shutdown")
+}
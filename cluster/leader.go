@@ -0,0 +1,45 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"github.com/hashicorp/consul/api"
+
+	"github.com/ystia/yorc/v4/helper/consulutil"
+)
+
+// WatchLeaderElection watches the leader election of a service and publishes the given role while this
+// server is the leader.
+//
+// It works like consulutil.WatchLeaderElection except that a draining server never becomes leader
+// and gives up the leadership it holds, letting another member take over the service.
+func WatchLeaderElection(cc *api.Client, role, serviceKey string, chStop chan struct{}, leaderServiceStart func(), leaderServiceStop func()) {
+	start := func() {
+		local.setRole(role, true)
+		if leaderServiceStart != nil {
+			leaderServiceStart()
+		}
+	}
+	stop := func() {
+		if leaderServiceStop != nil {
+			leaderServiceStop()
+		}
+		local.setRole(role, false)
+	}
+	opts := consulutil.LeaderElectionOptions{
+		Eligible: func() bool { return !IsDraining() },
+	}
+	consulutil.WatchLeaderElectionWithOptions(cc, serviceKey, chStop, start, stop, opts)
+}
@@ -0,0 +1,298 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"encoding/json"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+)
+
+const (
+	// memberSessionTTL is the TTL of the Consul session holding the member key, the member
+	// disappears from the cluster when its server doesn't renew it
+	memberSessionTTL = "15s"
+	// memberRefreshInterval is the maximum duration between two publications of the member state
+	memberRefreshInterval = 30 * time.Second
+	// drainWatchWaitTime is the maximum duration of a blocking query on the drain request key
+	drainWatchWaitTime = time.Minute
+	retryDelay         = 5 * time.Second
+)
+
+// local is the member of the cluster corresponding to this Yorc server
+var local = newLocalMember()
+
+type localMember struct {
+	lock     sync.Mutex
+	draining bool
+	roles    map[string]struct{}
+	work     map[WorkKind]map[string]struct{}
+	chChange chan struct{}
+}
+
+func newLocalMember() *localMember {
+	return &localMember{
+		roles:    make(map[string]struct{}),
+		work:     make(map[WorkKind]map[string]struct{}),
+		chChange: make(chan struct{}, 1),
+	}
+}
+
+// notify signals that the member changed without blocking, pending notifications are merged
+func (l *localMember) notify() {
+	select {
+	case l.chChange <- struct{}{}:
+	default:
+	}
+}
+
+func (l *localMember) setDraining(draining bool) {
+	l.lock.Lock()
+	changed := l.draining != draining
+	l.draining = draining
+	l.lock.Unlock()
+	if changed {
+		if draining {
+			log.Printf("This server is draining: it stops taking new work and hands over its current one")
+		} else {
+			log.Printf("This server is active again: it takes work")
+		}
+		l.notify()
+	}
+}
+
+func (l *localMember) isDraining() bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.draining
+}
+
+func (l *localMember) setRole(role string, held bool) {
+	l.lock.Lock()
+	_, had := l.roles[role]
+	if held {
+		l.roles[role] = struct{}{}
+	} else {
+		delete(l.roles, role)
+	}
+	l.lock.Unlock()
+	if had != held {
+		l.notify()
+	}
+}
+
+func (l *localMember) addWork(kind WorkKind, id string) {
+	l.lock.Lock()
+	ids, ok := l.work[kind]
+	if !ok {
+		ids = make(map[string]struct{})
+		l.work[kind] = ids
+	}
+	ids[id] = struct{}{}
+	l.lock.Unlock()
+	l.notify()
+}
+
+func (l *localMember) removeWork(kind WorkKind, id string) {
+	l.lock.Lock()
+	_, had := l.work[kind][id]
+	delete(l.work[kind], id)
+	l.lock.Unlock()
+	if had {
+		l.notify()
+	}
+}
+
+// snapshot returns the current roles and work of the member and its state
+func (l *localMember) snapshot() (string, []string, Work) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	roles := sortedKeys(l.roles)
+	work := Work{
+		TaskExecutions:   sortedKeys(l.work[WorkTaskExecution]),
+		Checks:           sortedKeys(l.work[WorkCheck]),
+		ScheduledActions: sortedKeys(l.work[WorkScheduledAction]),
+	}
+	state := StateActive
+	if l.draining {
+		state = StateDrained
+		if len(roles)+len(work.TaskExecutions)+len(work.Checks)+len(work.ScheduledActions) > 0 {
+			state = StateDraining
+		}
+	}
+	return state, roles, work
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// AddWork records that this server owns the work of the given kind and identifier
+func AddWork(kind WorkKind, id string) {
+	local.addWork(kind, id)
+}
+
+// RemoveWork records that this server doesn't own anymore the work of the given kind and identifier
+func RemoveWork(kind WorkKind, id string) {
+	local.removeWork(kind, id)
+}
+
+// IsDraining returns true if this server has been requested to drain
+//
+// A draining server should not take new work.
+func IsDraining() bool {
+	return local.isDraining()
+}
+
+// Start registers this Yorc server as a member of the cluster.
+//
+// The member state is published until the shutdown channel is closed.
+func Start(cfg config.Configuration, cc *api.Client, chShutdown chan struct{}) error {
+	nodeName, err := cc.Agent().NodeName()
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	draining, waitIndex, err := isDrainRequested(cc, cfg.ServerID, 0, 0)
+	if err != nil {
+		return err
+	}
+	local.setDraining(draining)
+
+	p := &memberPublisher{
+		cc:        cc,
+		serverID:  cfg.ServerID,
+		nodeName:  nodeName,
+		startDate: time.Now(),
+	}
+	go watchDrainRequests(cc, cfg.ServerID, waitIndex, chShutdown)
+	go p.run(chShutdown)
+	return nil
+}
+
+func watchDrainRequests(cc *api.Client, serverID string, waitIndex uint64, chShutdown chan struct{}) {
+	for {
+		select {
+		case <-chShutdown:
+			return
+		default:
+		}
+		draining, lastIndex, err := isDrainRequested(cc, serverID, waitIndex, drainWatchWaitTime)
+		if err != nil {
+			log.Printf("[WARN] Failed to watch drain requests of server %q: %v", serverID, err)
+			select {
+			case <-chShutdown:
+				return
+			case <-time.After(retryDelay):
+			}
+			continue
+		}
+		waitIndex = lastIndex
+		local.setDraining(draining)
+	}
+}
+
+type memberPublisher struct {
+	cc        *api.Client
+	serverID  string
+	nodeName  string
+	startDate time.Time
+}
+
+func (p *memberPublisher) run(chShutdown chan struct{}) {
+	for {
+		session, _, err := p.cc.Session().Create(&api.SessionEntry{
+			Name:      "ClusterMember-" + p.serverID,
+			Behavior:  api.SessionBehaviorDelete,
+			TTL:       memberSessionTTL,
+			LockDelay: 1 * time.Nanosecond,
+		}, nil)
+		if err != nil {
+			log.Printf("[WARN] Failed to create cluster member session for server %q: %v", p.serverID, err)
+			select {
+			case <-chShutdown:
+				return
+			case <-time.After(retryDelay):
+			}
+			continue
+		}
+
+		chSessionLost := make(chan struct{})
+		go func() {
+			// RenewPeriodic destroys the session, and so the member key, on shutdown
+			err := p.cc.Session().RenewPeriodic(memberSessionTTL, session, nil, chShutdown)
+			if err != nil {
+				log.Printf("[WARN] Cluster member session of server %q lost: %v", p.serverID, err)
+			}
+			close(chSessionLost)
+		}()
+
+		for sessionLost := false; !sessionLost; {
+			if err := p.publish(session); err != nil {
+				log.Printf("[WARN] Failed to publish cluster member %q: %v", p.serverID, err)
+			}
+			select {
+			case <-chShutdown:
+				return
+			case <-chSessionLost:
+				sessionLost = true
+			case <-local.chChange:
+			case <-time.After(memberRefreshInterval):
+			}
+		}
+	}
+}
+
+func (p *memberPublisher) publish(session string) error {
+	m := p.member()
+	b, err := json.Marshal(m)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal cluster member")
+	}
+	acquired, _, err := p.cc.KV().Acquire(&api.KVPair{Key: path.Join(membersPrefix, p.serverID), Value: b, Session: session}, nil)
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if !acquired {
+		return errors.Errorf("member key is held by another session, check that server ID %q is not used by another server", p.serverID)
+	}
+	return nil
+}
+
+func (p *memberPublisher) member() Member {
+	state, roles, work := local.snapshot()
+	return Member{
+		ServerID:   p.serverID,
+		NodeName:   p.nodeName,
+		State:      state,
+		Roles:      roles,
+		Work:       work,
+		StartDate:  p.startDate,
+		LastUpdate: time.Now(),
+	}
+}
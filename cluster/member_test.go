@@ -0,0 +1,101 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalMemberSnapshot(t *testing.T) {
+	type work struct {
+		kind WorkKind
+		id   string
+	}
+	tests := []struct {
+		name      string
+		draining  bool
+		roles     []string
+		work      []work
+		wantState string
+		wantWork  Work
+	}{
+		{"ActiveWithoutWork", false, nil, nil, StateActive, Work{TaskExecutions: []string{}, Checks: []string{}, ScheduledActions: []string{}}},
+		{"ActiveWithWork", false, []string{RoleSchedulingLeader, RoleMonitoringLeader},
+			[]work{{WorkTaskExecution, "e2"}, {WorkTaskExecution, "e1"}, {WorkCheck, "c1"}, {WorkScheduledAction, "a1"}},
+			StateActive, Work{TaskExecutions: []string{"e1", "e2"}, Checks: []string{"c1"}, ScheduledActions: []string{"a1"}}},
+		{"DrainingWithExecutions", true, nil, []work{{WorkTaskExecution, "e1"}}, StateDraining,
+			Work{TaskExecutions: []string{"e1"}, Checks: []string{}, ScheduledActions: []string{}}},
+		{"DrainingWithRoles", true, []string{RoleMonitoringLeader}, nil, StateDraining,
+			Work{TaskExecutions: []string{}, Checks: []string{}, ScheduledActions: []string{}}},
+		{"Drained", true, nil, nil, StateDrained, Work{TaskExecutions: []string{}, Checks: []string{}, ScheduledActions: []string{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLocalMember()
+			l.setDraining(tt.draining)
+			for _, r := range tt.roles {
+				l.setRole(r, true)
+			}
+			for _, w := range tt.work {
+				l.addWork(w.kind, w.id)
+			}
+			state, roles, work := l.snapshot()
+			require.Equal(t, tt.wantState, state)
+			require.Len(t, roles, len(tt.roles))
+			require.Equal(t, tt.wantWork, work)
+		})
+	}
+}
+
+func TestLocalMemberReleaseWork(t *testing.T) {
+	l := newLocalMember()
+	l.setDraining(true)
+	l.setRole(RoleMonitoringLeader, true)
+	l.addWork(WorkCheck, "c1")
+	l.addWork(WorkTaskExecution, "e1")
+	state, _, _ := l.snapshot()
+	require.Equal(t, StateDraining, state)
+
+	l.setRole(RoleMonitoringLeader, false)
+	l.removeWork(WorkCheck, "c1")
+	l.removeWork(WorkTaskExecution, "e1")
+	// Removing unknown work is a no-op
+	l.removeWork(WorkScheduledAction, "unknown")
+	state, roles, _ := l.snapshot()
+	require.Equal(t, StateDrained, state)
+	require.Empty(t, roles)
+
+	l.setDraining(false)
+	state, _, _ = l.snapshot()
+	require.Equal(t, StateActive, state)
+}
+
+func TestLocalMemberNotifications(t *testing.T) {
+	l := newLocalMember()
+	l.addWork(WorkTaskExecution, "e1")
+	l.addWork(WorkTaskExecution, "e2")
+	// Notifications are merged
+	require.Len(t, l.chChange, 1)
+	<-l.chChange
+
+	l.setRole(RoleSchedulingLeader, false)
+	l.removeWork(WorkCheck, "unknown")
+	require.Len(t, l.chChange, 0, "unchanged member should not be notified")
+
+	l.setDraining(true)
+	require.Len(t, l.chChange, 1)
+}
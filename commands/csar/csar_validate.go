@@ -0,0 +1,186 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csar
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ystia/yorc/v4/commands/httputil"
+	"github.com/ystia/yorc/v4/csar"
+	"github.com/ystia/yorc/v4/helper/ziputil"
+	"github.com/ystia/yorc/v4/resources"
+)
+
+func init() {
+	var remote bool
+	var definitionsPaths []string
+	var validateCmd = &cobra.Command{
+		Use:   "validate <csar_path>",
+		Short: "Validate a CSAR without deploying it",
+		Long: `Validate a file or directory pointed by <csar_path> and report all detected errors with their location.
+	If <csar_path> point to a valid zip archive it is validated as it.
+	If <csar_path> point to a single file it should be TOSCA YAML description.
+
+	By default the validation is performed offline against TOSCA definitions builtin into this executable and
+	definitions provided using the --definitions flag.
+	Use the --remote flag to validate the CSAR on a Yorc server, taking into account definitions provided by its plugins.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.Errorf("Expecting a path to a file or directory (got %d parameters)", len(args))
+			}
+			var report *csar.Report
+			var err error
+			if remote {
+				var client httputil.HTTPClient
+				client, err = httputil.GetClient(ClientConfig)
+				if err != nil {
+					return err
+				}
+				report, err = validateRemote(client, args[0])
+			} else {
+				var definitions map[string][]byte
+				definitions, err = loadDefinitions(definitionsPaths)
+				if err != nil {
+					return err
+				}
+				report, err = validateLocal(args[0], definitions)
+			}
+			if err != nil {
+				return err
+			}
+			return printReport(report, !noColor)
+		},
+	}
+	validateCmd.Flags().BoolVarP(&remote, "remote", "r", false, "Validate the CSAR on the Yorc server instead of offline.")
+	validateCmd.Flags().StringSliceVarP(&definitionsPaths, "definitions", "d", nil, "Additional TOSCA definition files or directories that could be imported using the <definition_name> syntax (offline mode only).")
+	CSARCmd.AddCommand(validateCmd)
+}
+
+// loadDefinitions returns builtin TOSCA definitions and definitions from the given files or directories
+func loadDefinitions(paths []string) (map[string][]byte, error) {
+	definitions, err := resources.GetBuiltinTOSCADefinitions()
+	if err != nil {
+		// Executable without embedded resources, this is likely a development build
+		fmt.Fprintf(os.Stderr, "Warning: builtin TOSCA definitions are not available: %v\n", err)
+		definitions = make(map[string][]byte)
+	}
+	for _, p := range paths {
+		files := []string{p}
+		fileInfo, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if fileInfo.IsDir() {
+			files, err = filepath.Glob(filepath.Join(p, "*.y*ml"))
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, f := range files {
+			content, err := ioutil.ReadFile(f)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read TOSCA definition %q", f)
+			}
+			definitions[filepath.Base(f)] = content
+		}
+	}
+	return definitions, nil
+}
+
+func validateLocal(csarPath string, definitions map[string][]byte) (*csar.Report, error) {
+	archive, err := csar.LoadPath(csarPath)
+	if err != nil {
+		return nil, err
+	}
+	return csar.Validate(archive, definitions), nil
+}
+
+func validateRemote(client httputil.HTTPClient, csarPath string) (*csar.Report, error) {
+	absPath, err := filepath.Abs(csarPath)
+	if err != nil {
+		return nil, err
+	}
+	fileInfo, err := os.Stat(absPath)
+	if err != nil {
+		return nil, err
+	}
+	var csarZip []byte
+	if !fileInfo.IsDir() {
+		csarZip, err = ioutil.ReadFile(absPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if csarZip == nil || http.DetectContentType(csarZip) != "application/zip" {
+		csarZip, err = ziputil.ZipPath(absPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	request, err := client.NewRequest(http.MethodPost, "/csar/validate", bytes.NewReader(csarZip))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("Content-Type", "application/zip")
+	request.Header.Add("Accept", "application/json")
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		// Try to get the reason
+		httputil.PrintErrors(response.Body)
+		return nil, errors.Errorf("POST failed: Expecting HTTP Status code 200, got %d, reason %q", response.StatusCode, response.Status)
+	}
+	report := new(csar.Report)
+	err = json.NewDecoder(response.Body).Decode(report)
+	return report, errors.Wrap(err, "failed to decode validation report")
+}
+
+func printReport(report *csar.Report, colorize bool) error {
+	var nbErrors, nbWarnings int
+	for _, e := range report.Errors {
+		msg := e.Error()
+		if e.Severity == csar.SeverityWarning {
+			nbWarnings++
+			if colorize {
+				msg = color.New(color.FgYellow).Sprint(msg)
+			}
+		} else {
+			nbErrors++
+			if colorize {
+				msg = color.New(color.FgRed).Sprint(msg)
+			}
+		}
+		fmt.Println(msg)
+	}
+	if !report.Valid {
+		return errors.Errorf("CSAR validation failed: %d error(s), %d warning(s)", nbErrors, nbWarnings)
+	}
+	fmt.Printf("CSAR is valid (%d warning(s))\n", nbWarnings)
+	return nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type httpClientMockArchive struct {
+	statusCode int
+	body       []byte
+	requests   []*http.Request
+	received   []byte
+}
+
+func (c *httpClientMockArchive) Do(req *http.Request) (*http.Response, error) {
+	c.requests = append(c.requests, req)
+	if req.Body != nil {
+		c.received, _ = ioutil.ReadAll(req.Body)
+	}
+	res := httptest.NewRecorder()
+	res.WriteHeader(c.statusCode)
+	res.Write(c.body)
+	return res.Result(), nil
+}
+
+func (c *httpClientMockArchive) NewRequest(method, path string, body io.Reader) (*http.Request, error) {
+	return http.NewRequest(method, path, body)
+}
+
+func (c *httpClientMockArchive) Get(path string) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func (c *httpClientMockArchive) Head(path string) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func (c *httpClientMockArchive) Post(path string, contentType string, body io.Reader) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func (c *httpClientMockArchive) PostForm(path string, data url.Values) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func TestExportDeployment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yorc-export-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	client := &httpClientMockArchive{statusCode: http.StatusOK, body: []byte("archive")}
+	output := filepath.Join(dir, "dep.zip")
+	err = exportDeployment(client, "dep", output, true, false)
+	require.NoError(t, err)
+	require.Len(t, client.requests, 1)
+	require.Equal(t, "/deployments/dep/export", client.requests[0].URL.Path)
+	require.Equal(t, "events=true", client.requests[0].URL.RawQuery)
+	b, err := ioutil.ReadFile(output)
+	require.NoError(t, err)
+	require.Equal(t, "archive", string(b))
+
+	client = &httpClientMockArchive{statusCode: http.StatusNotFound}
+	err = exportDeployment(client, "unknown", filepath.Join(dir, "unknown.zip"), false, false)
+	require.Error(t, err)
+}
+
+func TestImportDeployment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yorc-import-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	w, err := zw.Create("manifest.json")
+	require.NoError(t, err)
+	_, err = w.Write([]byte(`{"format_version": 1, "deployment_id": "exported"}`))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	archivePath := filepath.Join(dir, "dep.zip")
+	require.NoError(t, ioutil.WriteFile(archivePath, buf.Bytes(), 0600))
+
+	tests := []struct {
+		name         string
+		deploymentID string
+		statusCode   int
+		wantID       string
+		wantErr      bool
+	}{
+		{"ExportedID", "", http.StatusCreated, "exported", false},
+		{"NewID", "newID", http.StatusCreated, "newID", false},
+		{"Conflict", "", http.StatusConflict, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &httpClientMockArchive{statusCode: tt.statusCode}
+			id, err := importDeployment(client, archivePath, tt.deploymentID)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantID, id)
+			require.Len(t, client.requests, 1)
+			require.Equal(t, "/deployments/"+tt.wantID+"/import", client.requests[0].URL.Path)
+			require.Equal(t, buf.Bytes(), client.received)
+		})
+	}
+
+	_, err = importDeployment(&httpClientMockArchive{statusCode: http.StatusCreated}, filepath.Join(dir, "missing.zip"), "")
+	require.Error(t, err)
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ystia/yorc/v4/commands/httputil"
+)
+
+func init() {
+	var output string
+	var withEvents bool
+	var withLogs bool
+	var exportCmd = &cobra.Command{
+		Use:   "export <DeploymentId>",
+		Short: "Export a deployment",
+		Long: `Export a deployment into an archive that could be imported into another Yorc cluster.
+	The archive contains the deployment CSAR, its topology, instances states and attributes, tasks history and hosts pool allocations.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.Errorf("Expecting a deployment id (got %d parameters)", len(args))
+			}
+			client, err := httputil.GetClient(ClientConfig)
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			if output == "" {
+				output = args[0] + ".zip"
+			}
+			err = exportDeployment(client, args[0], output, withEvents, withLogs)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Deployment %s exported into %s\n", args[0], output)
+			return nil
+		},
+	}
+	exportCmd.PersistentFlags().StringVarP(&output, "output", "o", "", "Path of the archive to create, defaults to <DeploymentId>.zip")
+	exportCmd.PersistentFlags().BoolVarP(&withEvents, "events", "e", false, "Include deployment events into the archive")
+	exportCmd.PersistentFlags().BoolVarP(&withLogs, "logs", "l", false, "Include deployment logs into the archive")
+	DeploymentsCmd.AddCommand(exportCmd)
+}
+
+func exportDeployment(client httputil.HTTPClient, deploymentID, output string, withEvents, withLogs bool) error {
+	request, err := client.NewRequest(http.MethodGet, path.Join("/deployments", deploymentID, "export"), nil)
+	if err != nil {
+		return err
+	}
+	q := url.Values{}
+	if withEvents {
+		q.Add("events", "true")
+	}
+	if withLogs {
+		q.Add("logs", "true")
+	}
+	request.URL.RawQuery = q.Encode()
+	request.Header.Add("Accept", "application/zip")
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		// Try to get the reason
+		httputil.PrintErrors(response.Body)
+		return errors.Errorf("Expecting HTTP Status code 200, got %d, reason %q", response.StatusCode, response.Status)
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create archive %q", output)
+	}
+	defer f.Close()
+	_, err = io.Copy(f, response.Body)
+	return errors.Wrapf(err, "failed to write archive %q", output)
+}
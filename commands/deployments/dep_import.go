@@ -0,0 +1,94 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"archive/zip"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ystia/yorc/v4/commands/httputil"
+	"github.com/ystia/yorc/v4/deployments/archive"
+	"github.com/ystia/yorc/v4/rest"
+)
+
+func init() {
+	var deploymentID string
+	var importCmd = &cobra.Command{
+		Use:   "import <archive_path>",
+		Short: "Import a deployment",
+		Long: `Import a deployment from an archive created by the export command on another Yorc cluster.
+	By default the deployment is imported with the same id than the exported one.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.Errorf("Expecting a path to a deployment archive (got %d parameters)", len(args))
+			}
+			client, err := httputil.GetClient(ClientConfig)
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			id, err := importDeployment(client, args[0], deploymentID)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Deployment imported. Deployment Id: %s\n", id)
+			return nil
+		},
+	}
+	importCmd.PersistentFlags().StringVarP(&deploymentID, "id", "", "", fmt.Sprintf("Specify a new id for the imported deployment. This id should not already exists and should respect the following format: %q", rest.YorcDeploymentIDPattern))
+	DeploymentsCmd.AddCommand(importCmd)
+}
+
+func importDeployment(client httputil.HTTPClient, archivePath, deploymentID string) (string, error) {
+	if deploymentID == "" {
+		zr, err := zip.OpenReader(archivePath)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to open archive %q", archivePath)
+		}
+		manifest, err := archive.ReadManifest(&zr.Reader)
+		zr.Close()
+		if err != nil {
+			return "", err
+		}
+		deploymentID = manifest.DeploymentID
+	}
+
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to open archive %q", archivePath)
+	}
+	defer f.Close()
+	request, err := client.NewRequest(http.MethodPut, path.Join("/deployments", deploymentID, "import"), f)
+	if err != nil {
+		return "", err
+	}
+	request.Header.Add("Content-Type", "application/zip")
+	response, err := client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusCreated {
+		// Try to get the reason
+		httputil.PrintErrors(response.Body)
+		return "", errors.Errorf("Expecting HTTP Status code 201, got %d, reason %q", response.StatusCode, response.Status)
+	}
+	return deploymentID, nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rollouts
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ystia/yorc/v4/commands/deployments"
+)
+
+var rolloutsCmd = &cobra.Command{
+	Use:     "rollouts",
+	Short:   "Perform commands on deployments rollouts",
+	Aliases: []string{"rollout", "ro"},
+	Long: `Rollouts progressively execute a workflow on the instances of a scalable node, batch by batch.
+    Health gates check the monitoring status and the state of updated instances between batches,
+    a failed rollout is halted and its optional rollback workflow is executed on the updated instances.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := cmd.Help()
+		if err != nil {
+			fmt.Print(err)
+		}
+	},
+}
+
+func init() {
+	deployments.DeploymentsCmd.AddCommand(rolloutsCmd)
+}
@@ -0,0 +1,58 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rollouts
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ystia/yorc/v4/commands/deployments"
+	"github.com/ystia/yorc/v4/commands/httputil"
+)
+
+func init() {
+	var cancelCmd = &cobra.Command{
+		Use:   "cancel <DeploymentId> <RolloutId>",
+		Short: "Cancel a rollout of a deployment",
+		Long: `Cancel a running or rolling back rollout, its running task is canceled.
+    Canceled rollouts are not rolled back.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.Errorf("Expecting a deployment id and a rollout id (got %d parameters)", len(args))
+			}
+			client, err := httputil.GetClient(deployments.ClientConfig)
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			request, err := client.NewRequest("DELETE", path.Join("/deployments", args[0], "rollouts", args[1]), nil)
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			response, err := client.Do(request)
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			defer response.Body.Close()
+			httputil.HandleHTTPStatusCode(response, args[1], "rollout", http.StatusAccepted)
+			fmt.Printf("Cancel submitted for rollout %q\n", args[1])
+			return nil
+		},
+	}
+	rolloutsCmd.AddCommand(cancelCmd)
+}
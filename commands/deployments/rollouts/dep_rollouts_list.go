@@ -0,0 +1,160 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rollouts
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ystia/yorc/v4/commands/deployments"
+	"github.com/ystia/yorc/v4/commands/httputil"
+	"github.com/ystia/yorc/v4/helper/tabutil"
+	"github.com/ystia/yorc/v4/rest"
+)
+
+func init() {
+	var listCmd = &cobra.Command{
+		Use:     "list <DeploymentId>",
+		Short:   "List rollouts of a deployment",
+		Aliases: []string{"ls"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.Errorf("Expecting a deployment id (got %d parameters)", len(args))
+			}
+			client, err := httputil.GetClient(deployments.ClientConfig)
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			rollouts, err := listRollouts(client, args[0])
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			if len(rollouts) == 0 {
+				fmt.Println("No rollouts")
+				return nil
+			}
+			fmt.Println(renderRollouts(rollouts))
+			return nil
+		},
+	}
+	var infoCmd = &cobra.Command{
+		Use:   "info <DeploymentId> <RolloutId>",
+		Short: "Show the progress of a rollout of a deployment",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return errors.Errorf("Expecting a deployment id and a rollout id (got %d parameters)", len(args))
+			}
+			client, err := httputil.GetClient(deployments.ClientConfig)
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			ro, err := getRollout(client, args[0], args[1])
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			fmt.Println(renderRolloutBatches(ro))
+			return nil
+		},
+	}
+	rolloutsCmd.AddCommand(listCmd)
+	rolloutsCmd.AddCommand(infoCmd)
+}
+
+func listRollouts(client httputil.HTTPClient, deploymentID string) ([]rest.Rollout, error) {
+	request, err := client.NewRequest("GET", "/deployments/"+deploymentID+"/rollouts", nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("Accept", "application/json")
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	var collection rest.RolloutsCollection
+	switch response.StatusCode {
+	case http.StatusOK:
+		err = json.NewDecoder(response.Body).Decode(&collection)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read rollouts list")
+		}
+	case http.StatusNoContent:
+	case http.StatusNotFound:
+		return nil, errors.Errorf("The deployment with the following id %q doesn't exist", deploymentID)
+	default:
+		httputil.PrintErrors(response.Body)
+		return nil, errors.Errorf("Expecting HTTP Status code 200, got %d, reason %q", response.StatusCode, response.Status)
+	}
+	return collection.Rollouts, nil
+}
+
+func getRollout(client httputil.HTTPClient, deploymentID, rolloutID string) (*rest.Rollout, error) {
+	request, err := client.NewRequest("GET", path.Join("/deployments", deploymentID, "rollouts", rolloutID), nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("Accept", "application/json")
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, errors.Errorf("The rollout with the following id %q doesn't exist for deployment %q", rolloutID, deploymentID)
+	default:
+		httputil.PrintErrors(response.Body)
+		return nil, errors.Errorf("Expecting HTTP Status code 200, got %d, reason %q", response.StatusCode, response.Status)
+	}
+	ro := new(rest.Rollout)
+	err = json.NewDecoder(response.Body).Decode(ro)
+	return ro, errors.Wrap(err, "failed to read rollout")
+}
+
+func renderRollouts(rollouts []rest.Rollout) string {
+	table := tabutil.NewTable()
+	table.AddHeaders("Id", "Workflow", "Node", "Status", "Batch", "Rollback Workflow", "Creation Date", "Error")
+	for _, ro := range rollouts {
+		table.AddRow(ro.ID, ro.Workflow, ro.NodeName, ro.Status, fmt.Sprintf("%d/%d", ro.CurrentBatch+1, len(ro.Batches)),
+			ro.RollbackWorkflow, ro.CreationDate.Local().Format(time.RFC3339), ro.Error)
+	}
+	return table.Render()
+}
+
+func renderRolloutBatches(ro *rest.Rollout) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Rollout %s of workflow %q on node %q: %s\n", ro.ID, ro.Workflow, ro.NodeName, ro.Status)
+	if ro.Error != "" {
+		fmt.Fprintf(&sb, "Error: %s\n", ro.Error)
+	}
+	if ro.RollbackTaskID != "" {
+		fmt.Fprintf(&sb, "Rollback task: %s\n", ro.RollbackTaskID)
+	}
+	table := tabutil.NewTable()
+	table.AddHeaders("Batch", "Instances", "Status", "Task")
+	for i, b := range ro.Batches {
+		table.AddRow(i+1, strings.Join(b.Instances, ","), b.Status, b.TaskID)
+	}
+	sb.WriteString(table.Render())
+	return sb.String()
+}
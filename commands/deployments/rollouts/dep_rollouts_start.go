@@ -0,0 +1,156 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rollouts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ystia/yorc/v4/commands/deployments"
+	"github.com/ystia/yorc/v4/commands/httputil"
+	"github.com/ystia/yorc/v4/rest"
+)
+
+// startOptions are the flags of the rollout start command
+type startOptions struct {
+	workflow         string
+	nodeName         string
+	inputs           []string
+	canary           int
+	batchSize        int
+	batchPercentage  int
+	pause            string
+	healthGateDelay  string
+	rollbackWorkflow string
+}
+
+func init() {
+	var opts startOptions
+	var startCmd = &cobra.Command{
+		Use:   "start <DeploymentId>",
+		Short: "Start a rollout of a workflow on a scalable node",
+		Long: `Start a rollout executing a workflow on the instances of a scalable node batch by batch.
+    Batches are defined by a number of instances (--batch-size) or a percentage of the node instances
+    (--batch-percentage), an optional canary batch (--canary) is executed first.
+    Once the workflow of a batch succeeds, a health gate checks after a delay (--health-gate-delay) that
+    updated instances don't have a failed monitoring check and are not in error, the next batch starts
+    after a pause (--pause). On failure the rollout is halted and the rollback workflow (--rollback) is
+    executed on the updated instances.`,
+		Example: `  yorc deployments rollouts start myapp --workflow upgrade --node Server --canary 1 --batch-percentage 25 --pause 5m --health-gate-delay 1m --rollback downgrade`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.Errorf("Expecting a deployment id (got %d parameters)", len(args))
+			}
+			req, err := buildRolloutRequest(opts)
+			if err != nil {
+				return err
+			}
+			client, err := httputil.GetClient(deployments.ClientConfig)
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			rolloutID, err := startRollout(client, args[0], req)
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			fmt.Printf("Rollout %s started\n", rolloutID)
+			return nil
+		},
+	}
+	startCmd.Flags().StringVarP(&opts.workflow, "workflow", "w", "", "Name of the workflow to roll out")
+	startCmd.Flags().StringVarP(&opts.nodeName, "node", "n", "", "Name of the scalable node on which instances the workflow is executed")
+	startCmd.Flags().StringArrayVarP(&opts.inputs, "input", "i", nil, "Input of the workflows as name=<JSON value>")
+	startCmd.Flags().IntVar(&opts.canary, "canary", 0, "Number of instances of the first batch")
+	startCmd.Flags().IntVar(&opts.batchSize, "batch-size", 0, "Number of instances of a batch")
+	startCmd.Flags().IntVar(&opts.batchPercentage, "batch-percentage", 0, "Percentage of the node instances in a batch")
+	startCmd.Flags().StringVar(&opts.pause, "pause", "", "Pause between two batches (e.g. \"5m\")")
+	startCmd.Flags().StringVar(&opts.healthGateDelay, "health-gate-delay", "", "Delay between the end of the workflow of a batch and the check of its instances health (e.g. \"30s\")")
+	startCmd.Flags().StringVar(&opts.rollbackWorkflow, "rollback", "", "Name of the workflow executed on updated instances if the rollout fails")
+	rolloutsCmd.AddCommand(startCmd)
+}
+
+func parseInputs(inputs []string) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(inputs))
+	for _, input := range inputs {
+		kv := strings.SplitN(input, "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.Errorf("invalid input %q, expecting name=<JSON value>", input)
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(kv[1])), &value); err != nil {
+			return nil, errors.Wrapf(err, "invalid value for input %q", kv[0])
+		}
+		values[strings.TrimSpace(kv[0])] = value
+	}
+	return values, nil
+}
+
+// buildRolloutRequest converts the command flags into a rollout request
+func buildRolloutRequest(opts startOptions) (*rest.RolloutRequest, error) {
+	if opts.workflow == "" || opts.nodeName == "" {
+		return nil, errors.New("\"workflow\" and \"node\" flags are mandatory")
+	}
+	if (opts.batchSize > 0) == (opts.batchPercentage > 0) {
+		return nil, errors.New("Exactly one of \"batch-size\" or \"batch-percentage\" flags should be provided")
+	}
+	inputs, err := parseInputs(opts.inputs)
+	if err != nil {
+		return nil, err
+	}
+	req := &rest.RolloutRequest{
+		Workflow:         opts.workflow,
+		NodeName:         opts.nodeName,
+		Canary:           opts.canary,
+		BatchSize:        opts.batchSize,
+		BatchPercentage:  opts.batchPercentage,
+		Pause:            opts.pause,
+		HealthGateDelay:  opts.healthGateDelay,
+		RollbackWorkflow: opts.rollbackWorkflow,
+	}
+	if len(inputs) > 0 {
+		req.Inputs = inputs
+	}
+	return req, nil
+}
+
+// startRollout submits a rollout request and returns the created rollout ID
+func startRollout(client httputil.HTTPClient, deploymentID string, req *rest.RolloutRequest) (string, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	request, err := client.NewRequest("POST", "/deployments/"+deploymentID+"/rollouts", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Add("Content-Type", "application/json")
+	response, err := client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusCreated {
+		httputil.PrintErrors(response.Body)
+		return "", errors.Errorf("Expecting HTTP Status code 201, got %d, reason %q", response.StatusCode, response.Status)
+	}
+	return path.Base(response.Header.Get("Location")), nil
+}
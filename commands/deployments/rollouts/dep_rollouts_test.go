@@ -0,0 +1,143 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rollouts
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/rest"
+)
+
+type httpClientRollouts struct {
+	statusCode int
+	body       interface{}
+	requests   []*http.Request
+	bodies     []rest.RolloutRequest
+}
+
+func (c *httpClientRollouts) Do(req *http.Request) (*http.Response, error) {
+	c.requests = append(c.requests, req)
+	if req.Body != nil {
+		var body rest.RolloutRequest
+		json.NewDecoder(req.Body).Decode(&body)
+		c.bodies = append(c.bodies, body)
+	}
+	w := httptest.NewRecorder()
+	if c.statusCode == http.StatusCreated {
+		w.Header().Set("Location", "/deployments/dep/rollouts/r1")
+	}
+	w.WriteHeader(c.statusCode)
+	if c.statusCode == http.StatusOK {
+		json.NewEncoder(w).Encode(c.body)
+	}
+	return w.Result(), nil
+}
+
+func (c *httpClientRollouts) NewRequest(method, path string, body io.Reader) (*http.Request, error) {
+	return http.NewRequest(method, path, body)
+}
+
+func (c *httpClientRollouts) Get(path string) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func (c *httpClientRollouts) Head(path string) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func (c *httpClientRollouts) Post(path string, contentType string, body io.Reader) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func (c *httpClientRollouts) PostForm(path string, data url.Values) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func TestBuildRolloutRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    startOptions
+		want    *rest.RolloutRequest
+		wantErr bool
+	}{
+		{"BatchSize", startOptions{workflow: "upgrade", nodeName: "Server", batchSize: 2, pause: "5m"},
+			&rest.RolloutRequest{Workflow: "upgrade", NodeName: "Server", BatchSize: 2, Pause: "5m"}, false},
+		{"Canary", startOptions{workflow: "upgrade", nodeName: "Server", canary: 1, batchPercentage: 25, healthGateDelay: "1m", rollbackWorkflow: "downgrade", inputs: []string{`version="2.0"`}},
+			&rest.RolloutRequest{Workflow: "upgrade", NodeName: "Server", Canary: 1, BatchPercentage: 25, HealthGateDelay: "1m", RollbackWorkflow: "downgrade", Inputs: map[string]interface{}{"version": "2.0"}}, false},
+		{"MissingWorkflow", startOptions{nodeName: "Server", batchSize: 2}, nil, true},
+		{"MissingNode", startOptions{workflow: "upgrade", batchSize: 2}, nil, true},
+		{"MissingBatch", startOptions{workflow: "upgrade", nodeName: "Server"}, nil, true},
+		{"SizeAndPercentage", startOptions{workflow: "upgrade", nodeName: "Server", batchSize: 2, batchPercentage: 25}, nil, true},
+		{"BadInput", startOptions{workflow: "upgrade", nodeName: "Server", batchSize: 2, inputs: []string{"version"}}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildRolloutRequest(tt.opts)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestStartRollout(t *testing.T) {
+	client := &httpClientRollouts{statusCode: http.StatusCreated}
+	id, err := startRollout(client, "dep", &rest.RolloutRequest{Workflow: "upgrade", NodeName: "Server", BatchSize: 1})
+	require.NoError(t, err)
+	require.Equal(t, "r1", id)
+	require.Equal(t, "/deployments/dep/rollouts", client.requests[0].URL.Path)
+	require.Equal(t, "upgrade", client.bodies[0].Workflow)
+
+	client = &httpClientRollouts{statusCode: http.StatusConflict}
+	_, err = startRollout(client, "dep", &rest.RolloutRequest{Workflow: "upgrade", NodeName: "Server", BatchSize: 1})
+	require.Error(t, err)
+}
+
+func TestListAndGetRollouts(t *testing.T) {
+	ro := rest.Rollout{ID: "r1", Workflow: "upgrade", NodeName: "Server", Status: "ROLLING_BACK", CurrentBatch: 1, Error: "health gate of batch 2 failed",
+		Batches: []rest.RolloutBatch{{Instances: []string{"0"}, Status: "DONE", TaskID: "t1"}, {Instances: []string{"1", "2"}, Status: "FAILED", TaskID: "t2"}}}
+	client := &httpClientRollouts{statusCode: http.StatusOK, body: rest.RolloutsCollection{Rollouts: []rest.Rollout{ro}}}
+	rollouts, err := listRollouts(client, "dep")
+	require.NoError(t, err)
+	require.Len(t, rollouts, 1)
+	require.Contains(t, renderRollouts(rollouts), "2/2")
+
+	client = &httpClientRollouts{statusCode: http.StatusNoContent}
+	rollouts, err = listRollouts(client, "dep")
+	require.NoError(t, err)
+	require.Len(t, rollouts, 0)
+
+	client = &httpClientRollouts{statusCode: http.StatusOK, body: ro}
+	got, err := getRollout(client, "dep", "r1")
+	require.NoError(t, err)
+	require.Equal(t, "/deployments/dep/rollouts/r1", client.requests[0].URL.Path)
+	out := renderRolloutBatches(got)
+	require.Contains(t, out, "1,2")
+	require.Contains(t, out, "health gate of batch 2 failed")
+
+	client = &httpClientRollouts{statusCode: http.StatusNotFound}
+	_, err = getRollout(client, "dep", "r1")
+	require.Error(t, err)
+}
@@ -0,0 +1,208 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ystia/yorc/v4/commands/deployments"
+	"github.com/ystia/yorc/v4/commands/httputil"
+	"github.com/ystia/yorc/v4/rest"
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+// createOptions are the flags of the schedule creation command
+type createOptions struct {
+	cron          string
+	at            string
+	timeZone      string
+	overlapPolicy string
+
+	workflow        string
+	continueOnError bool
+	customCommand   string
+	interfaceName   string
+	nodeName        string
+	instances       []string
+	inputs          []string
+	delta           int
+	undeploy        bool
+	stopOnError     bool
+}
+
+func init() {
+	var opts createOptions
+	var createCmd = &cobra.Command{
+		Use:     "create <DeploymentId>",
+		Short:   "Create a schedule for a deployment",
+		Aliases: []string{"add"},
+		Long: `Create a schedule running an operation on a deployment either once at a given date (--at)
+    or periodically using a cron expression (--cron).
+    The operation is one of:
+      - a custom workflow execution (--workflow)
+      - a custom command execution (--custom with --node and --interface)
+      - a node scaling (--node with a non zero --delta)
+      - an undeployment (--undeploy)
+    If the task of a previous run is still in progress when the schedule triggers, the run is skipped,
+    queued until the task ends or replaces the task depending on the overlap policy.`,
+		Example: `  yorc deployments schedules create myapp --cron "0 2 * * *" --workflow backup
+  yorc deployments schedules create myapp --at 2019-06-14T18:00:00+02:00 --undeploy`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.Errorf("Expecting a deployment id (got %d parameters)", len(args))
+			}
+			req, err := buildScheduleRequest(opts)
+			if err != nil {
+				return err
+			}
+			client, err := httputil.GetClient(deployments.ClientConfig)
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			scheduleID, err := createSchedule(client, args[0], req)
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			fmt.Printf("Schedule %s created\n", scheduleID)
+			return nil
+		},
+	}
+	createCmd.Flags().StringVar(&opts.cron, "cron", "", "Cron expression of a recurring schedule (e.g. \"0 2 * * *\" or \"@daily\")")
+	createCmd.Flags().StringVar(&opts.at, "at", "", "RFC3339 date of a one-shot schedule")
+	createCmd.Flags().StringVar(&opts.timeZone, "timezone", "", "Time zone of the cron expression (defaults to the Yorc server local time)")
+	createCmd.Flags().StringVar(&opts.overlapPolicy, "overlap", "", "What to do when the task of a previous run is still in progress: skip (default), queue or replace")
+	createCmd.Flags().StringVarP(&opts.workflow, "workflow", "w", "", "Name of the custom workflow to execute")
+	createCmd.Flags().BoolVar(&opts.continueOnError, "continue-on-error", false, "Continue the workflow execution even if an error occurs in a step")
+	createCmd.Flags().StringVar(&opts.customCommand, "custom", "", "Name of the custom command to execute")
+	createCmd.Flags().StringVar(&opts.interfaceName, "interface", "", "Interface name of the custom command")
+	createCmd.Flags().StringVarP(&opts.nodeName, "node", "n", "", "Node of the custom command or node to scale")
+	createCmd.Flags().StringSliceVar(&opts.instances, "instances", nil, "Node instances of the custom command (defaults to all instances when the command runs)")
+	createCmd.Flags().StringArrayVarP(&opts.inputs, "input", "i", nil, "Input of the workflow or the custom command as name=<JSON value>")
+	createCmd.Flags().IntVar(&opts.delta, "delta", 0, "Number of instances to add (if positive) or to remove (if negative) from the node")
+	createCmd.Flags().BoolVar(&opts.undeploy, "undeploy", false, "Undeploy the deployment")
+	createCmd.Flags().BoolVar(&opts.stopOnError, "stop-on-error", false, "Stop the undeployment on error")
+	schedulesCmd.AddCommand(createCmd)
+}
+
+func parseInputs(inputs []string) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(inputs))
+	for _, input := range inputs {
+		kv := strings.SplitN(input, "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.Errorf("invalid input %q, expecting name=<JSON value>", input)
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(kv[1])), &value); err != nil {
+			return nil, errors.Wrapf(err, "invalid value for input %q", kv[0])
+		}
+		values[strings.TrimSpace(kv[0])] = value
+	}
+	return values, nil
+}
+
+// buildScheduleRequest converts the command flags into a schedule request
+func buildScheduleRequest(opts createOptions) (*rest.ScheduleRequest, error) {
+	req := &rest.ScheduleRequest{
+		Cron:          opts.cron,
+		TimeZone:      opts.timeZone,
+		OverlapPolicy: opts.overlapPolicy,
+	}
+	if (opts.cron == "") == (opts.at == "") {
+		return nil, errors.New("Exactly one of \"cron\" or \"at\" flags should be provided")
+	}
+	if opts.at != "" {
+		at, err := time.Parse(time.RFC3339, opts.at)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid \"at\" date")
+		}
+		req.At = &at
+	}
+	nbOperations := 0
+	for _, defined := range []bool{opts.workflow != "", opts.customCommand != "", opts.delta != 0, opts.undeploy} {
+		if defined {
+			nbOperations++
+		}
+	}
+	if nbOperations != 1 {
+		return nil, errors.New("Exactly one operation should be provided using one of \"workflow\", \"custom\", \"delta\" or \"undeploy\" flags")
+	}
+	inputs, err := parseInputs(opts.inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case opts.workflow != "":
+		req.Workflow = &rest.ScheduledWorkflow{Name: opts.workflow, ContinueOnError: opts.continueOnError}
+		req.Workflow.Inputs = inputs
+	case opts.customCommand != "":
+		if opts.nodeName == "" || opts.interfaceName == "" {
+			return nil, errors.New("\"node\" and \"interface\" flags are mandatory for a custom command")
+		}
+		req.CustomCommand = &rest.CustomCommandRequest{
+			NodeName:          opts.nodeName,
+			CustomCommandName: opts.customCommand,
+			InterfaceName:     opts.interfaceName,
+			Instances:         opts.instances,
+			Inputs:            make(map[string]*tosca.ValueAssignment, len(inputs)),
+		}
+		for name, value := range inputs {
+			req.CustomCommand.Inputs[name], err = tosca.ToValueAssignment(value)
+			if err != nil {
+				return nil, err
+			}
+		}
+	case opts.delta != 0:
+		if opts.nodeName == "" {
+			return nil, errors.New("\"node\" flag is mandatory to scale a node")
+		}
+		req.Scale = &rest.ScheduledScale{NodeName: opts.nodeName, Delta: opts.delta}
+	case opts.undeploy:
+		req.Undeploy = &rest.ScheduledUndeploy{StopOnError: opts.stopOnError}
+	}
+	return req, nil
+}
+
+// createSchedule submits a schedule request and returns the created schedule ID
+func createSchedule(client httputil.HTTPClient, deploymentID string, req *rest.ScheduleRequest) (string, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	request, err := client.NewRequest("POST", "/deployments/"+deploymentID+"/schedules", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Add("Content-Type", "application/json")
+	response, err := client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusCreated {
+		httputil.PrintErrors(response.Body)
+		return "", errors.Errorf("Expecting HTTP Status code 201, got %d, reason %q", response.StatusCode, response.Status)
+	}
+	return path.Base(response.Header.Get("Location")), nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedules
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ystia/yorc/v4/commands/deployments"
+	"github.com/ystia/yorc/v4/commands/httputil"
+	"github.com/ystia/yorc/v4/helper/tabutil"
+	"github.com/ystia/yorc/v4/rest"
+)
+
+func init() {
+	var listCmd = &cobra.Command{
+		Use:     "list <DeploymentId>",
+		Short:   "List schedules of a deployment",
+		Aliases: []string{"ls"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.Errorf("Expecting a deployment id (got %d parameters)", len(args))
+			}
+			client, err := httputil.GetClient(deployments.ClientConfig)
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			schedules, err := listSchedules(client, args[0])
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			if len(schedules) == 0 {
+				fmt.Println("No schedules")
+				return nil
+			}
+			fmt.Println(renderSchedules(schedules))
+			return nil
+		},
+	}
+	schedulesCmd.AddCommand(listCmd)
+}
+
+func listSchedules(client httputil.HTTPClient, deploymentID string) ([]rest.Schedule, error) {
+	request, err := client.NewRequest("GET", "/deployments/"+deploymentID+"/schedules", nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("Accept", "application/json")
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	var collection rest.SchedulesCollection
+	switch response.StatusCode {
+	case http.StatusOK:
+		err = json.NewDecoder(response.Body).Decode(&collection)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read schedules list")
+		}
+	case http.StatusNoContent:
+	case http.StatusNotFound:
+		return nil, errors.Errorf("The deployment with the following id %q doesn't exist", deploymentID)
+	default:
+		httputil.PrintErrors(response.Body)
+		return nil, errors.Errorf("Expecting HTTP Status code 200, got %d, reason %q", response.StatusCode, response.Status)
+	}
+	return collection.Schedules, nil
+}
+
+func renderSchedules(schedules []rest.Schedule) string {
+	table := tabutil.NewTable()
+	table.AddHeaders("Id", "When", "Time Zone", "Task Type", "Overlap Policy", "Next Run", "Latest Task")
+	for _, s := range schedules {
+		when := s.Cron
+		if s.At != nil {
+			when = s.At.Local().Format(time.RFC3339)
+		}
+		nextRun := ""
+		if s.NextRun != nil {
+			nextRun = s.NextRun.Local().Format(time.RFC3339)
+		}
+		table.AddRow(s.ID, when, s.TimeZone, s.TaskType, s.OverlapPolicy, nextRun, s.LatestTaskID)
+	}
+	return table.Render()
+}
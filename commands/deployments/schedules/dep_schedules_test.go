@@ -0,0 +1,151 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedules
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/rest"
+)
+
+type httpClientSchedules struct {
+	statusCode int
+	schedules  rest.SchedulesCollection
+	requests   []*http.Request
+	bodies     []rest.ScheduleRequest
+}
+
+func (c *httpClientSchedules) Do(req *http.Request) (*http.Response, error) {
+	c.requests = append(c.requests, req)
+	if req.Body != nil {
+		var body rest.ScheduleRequest
+		json.NewDecoder(req.Body).Decode(&body)
+		c.bodies = append(c.bodies, body)
+	}
+	w := httptest.NewRecorder()
+	if c.statusCode == http.StatusCreated {
+		w.Header().Set("Location", "/deployments/dep/schedules/s1")
+	}
+	w.WriteHeader(c.statusCode)
+	if c.statusCode == http.StatusOK {
+		json.NewEncoder(w).Encode(c.schedules)
+	}
+	return w.Result(), nil
+}
+
+func (c *httpClientSchedules) NewRequest(method, path string, body io.Reader) (*http.Request, error) {
+	return http.NewRequest(method, path, body)
+}
+
+func (c *httpClientSchedules) Get(path string) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func (c *httpClientSchedules) Head(path string) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func (c *httpClientSchedules) Post(path string, contentType string, body io.Reader) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func (c *httpClientSchedules) PostForm(path string, data url.Values) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func TestBuildScheduleRequest(t *testing.T) {
+	at := time.Date(2019, time.June, 14, 18, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		opts    createOptions
+		want    *rest.ScheduleRequest
+		wantErr bool
+	}{
+		{"Workflow", createOptions{cron: "0 2 * * *", workflow: "backup", inputs: []string{`retention=7`}},
+			&rest.ScheduleRequest{Cron: "0 2 * * *", Workflow: &rest.ScheduledWorkflow{Name: "backup", WorkflowRequest: rest.WorkflowRequest{Inputs: map[string]interface{}{"retention": float64(7)}}}}, false},
+		{"Undeploy", createOptions{at: "2019-06-14T18:00:00Z", undeploy: true, overlapPolicy: "replace"},
+			&rest.ScheduleRequest{At: &at, OverlapPolicy: "replace", Undeploy: &rest.ScheduledUndeploy{}}, false},
+		{"ScaleIn", createOptions{cron: "@daily", timeZone: "Europe/Paris", nodeName: "Compute", delta: -2},
+			&rest.ScheduleRequest{Cron: "@daily", TimeZone: "Europe/Paris", Scale: &rest.ScheduledScale{NodeName: "Compute", Delta: -2}}, false},
+		{"MissingWhen", createOptions{undeploy: true}, nil, true},
+		{"CronAndAt", createOptions{cron: "@daily", at: "2019-06-14T18:00:00Z", undeploy: true}, nil, true},
+		{"BadDate", createOptions{at: "tomorrow", undeploy: true}, nil, true},
+		{"NoOperation", createOptions{cron: "@daily"}, nil, true},
+		{"SeveralOperations", createOptions{cron: "@daily", workflow: "backup", undeploy: true}, nil, true},
+		{"CustomWithoutNode", createOptions{cron: "@daily", customCommand: "restart", interfaceName: "custom"}, nil, true},
+		{"ScaleWithoutNode", createOptions{cron: "@daily", delta: 1}, nil, true},
+		{"BadInput", createOptions{cron: "@daily", workflow: "backup", inputs: []string{"retention"}}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildScheduleRequest(tt.opts)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	got, err := buildScheduleRequest(createOptions{cron: "@daily", customCommand: "restart", interfaceName: "custom", nodeName: "Server", instances: []string{"0"}, inputs: []string{`mode="fast"`}})
+	require.NoError(t, err)
+	require.NotNil(t, got.CustomCommand)
+	require.Equal(t, "Server", got.CustomCommand.NodeName)
+	require.Equal(t, []string{"0"}, got.CustomCommand.Instances)
+	require.Equal(t, "fast", got.CustomCommand.Inputs["mode"].String())
+}
+
+func TestCreateSchedule(t *testing.T) {
+	client := &httpClientSchedules{statusCode: http.StatusCreated}
+	id, err := createSchedule(client, "dep", &rest.ScheduleRequest{Cron: "@daily", Undeploy: &rest.ScheduledUndeploy{}})
+	require.NoError(t, err)
+	require.Equal(t, "s1", id)
+	require.Equal(t, "/deployments/dep/schedules", client.requests[0].URL.Path)
+	require.Equal(t, "@daily", client.bodies[0].Cron)
+
+	client = &httpClientSchedules{statusCode: http.StatusBadRequest}
+	_, err = createSchedule(client, "dep", &rest.ScheduleRequest{Cron: "@daily", Undeploy: &rest.ScheduledUndeploy{}})
+	require.Error(t, err)
+}
+
+func TestListSchedules(t *testing.T) {
+	next := time.Date(2019, time.June, 14, 18, 0, 0, 0, time.UTC)
+	client := &httpClientSchedules{statusCode: http.StatusOK, schedules: rest.SchedulesCollection{Schedules: []rest.Schedule{
+		{ID: "s1", Cron: "0 18 * * fri", TaskType: "UnDeploy", OverlapPolicy: "Skip", NextRun: &next},
+	}}}
+	schedules, err := listSchedules(client, "dep")
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	require.Equal(t, "s1", schedules[0].ID)
+	require.Contains(t, renderSchedules(schedules), "0 18 * * fri")
+
+	client = &httpClientSchedules{statusCode: http.StatusNoContent}
+	schedules, err = listSchedules(client, "dep")
+	require.NoError(t, err)
+	require.Len(t, schedules, 0)
+
+	client = &httpClientSchedules{statusCode: http.StatusNotFound}
+	_, err = listSchedules(client, "dep")
+	require.Error(t, err)
+}
@@ -0,0 +1,105 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tasks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ystia/yorc/v4/commands/deployments"
+	"github.com/ystia/yorc/v4/commands/httputil"
+	"github.com/ystia/yorc/v4/helper/tabutil"
+	"github.com/ystia/yorc/v4/rest"
+	"github.com/ystia/yorc/v4/tasks"
+)
+
+func init() {
+	var approver string
+	var approveTaskCmd = &cobra.Command{
+		Use:   "approve <DeploymentId> <TaskId>",
+		Short: "Approve a deployment task",
+		Long: `Approve a task specifying the deployment id and the task id.
+	The task should be in status "PENDING_APPROVAL" to be approved. It is executed once it received the number
+	of approvals required by its approval policy.
+	If the connection to Yorc is authenticated by a TLS client certificate, the certificate common name is used
+	as approver name and the --approver flag is ignored.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := httputil.GetClient(deployments.ClientConfig)
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			return approveTask(client, args, approver)
+		},
+	}
+	approveTaskCmd.Flags().StringVarP(&approver, "approver", "a", "", "Name of the approver, required if the connection to Yorc is not authenticated by a TLS client certificate")
+	tasksCmd.AddCommand(approveTaskCmd)
+}
+
+func approveTask(client httputil.HTTPClient, args []string, approver string) error {
+	if len(args) != 2 {
+		return errors.Errorf("Expecting a deployment id and a task id (got %d parameters)", len(args))
+	}
+	body, err := json.Marshal(rest.TaskApprovalRequest{Approver: approver})
+	if err != nil {
+		return err
+	}
+	url := path.Join("/deployments", args[0], "tasks", args[1], "approve")
+	request, err := client.NewRequest("POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Accept", "application/json")
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	ids := args[0] + "/" + args[1]
+	httputil.HandleHTTPStatusCode(response, ids, "deployment/task", http.StatusOK)
+	body, err = ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	var approval tasks.TaskApproval
+	err = json.Unmarshal(body, &approval)
+	if err != nil {
+		return err
+	}
+	printTaskApproval(&approval)
+	return nil
+}
+
+func printTaskApproval(approval *tasks.TaskApproval) {
+	fmt.Println("Task approval policy:", approval.Policy)
+	fmt.Printf("Task approvals: %d/%d\n", len(approval.Approvers), approval.Required)
+	fmt.Println("Task approval expiration date:", approval.ExpirationDate.Format(time.RFC3339))
+	if len(approval.Approvers) > 0 {
+		approversTable := tabutil.NewTable()
+		approversTable.AddHeaders("Approver", "Date")
+		for _, approver := range approval.Approvers {
+			approversTable.AddRow(approver.Name, approver.Date.Format(time.RFC3339))
+		}
+		fmt.Println(approversTable.Render())
+	}
+}
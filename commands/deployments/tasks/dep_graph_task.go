@@ -0,0 +1,93 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tasks
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ystia/yorc/v4/commands/deployments"
+	"github.com/ystia/yorc/v4/commands/httputil"
+)
+
+func init() {
+	var format string
+	var horizontal bool
+	var graphTaskCmd = &cobra.Command{
+		Use:   "graph <DeploymentId> <TaskId>",
+		Short: "Generate a representation of the execution graph of a deployment task",
+		Long: `Generate a representation of the execution graph of a workflow task specifying the deployment id and the task id.
+	Each step is colored according to its status and shows its timings, the instances it ran on and its error message if any.
+	Supported formats are "dot" (GraphViz), "mermaid" and "json". A dot output can be easily converted to an image by
+	making use of the dot command provided by GraphViz:
+		yorc deployments tasks graph <DeploymentId> <TaskId> | dot -Tpng > graph.png`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := httputil.GetClient(deployments.ClientConfig)
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			return taskGraph(client, args, format, horizontal)
+		},
+	}
+	graphTaskCmd.Flags().StringVarP(&format, "format", "f", "dot", "Output format, one of dot, mermaid or json")
+	graphTaskCmd.Flags().BoolVarP(&horizontal, "horizontal", "", false, "Draw graph with an horizontal layout (not applicable to json format)")
+	tasksCmd.AddCommand(graphTaskCmd)
+}
+
+func taskGraph(client httputil.HTTPClient, args []string, format string, horizontal bool) error {
+	if len(args) != 2 {
+		return errors.Errorf("Expecting a deployment id and a task id (got %d parameters)", len(args))
+	}
+	var accept string
+	switch format {
+	case "dot":
+		accept = "text/vnd.graphviz"
+	case "mermaid":
+		accept = "text/plain"
+	case "json":
+		accept = "application/json"
+	default:
+		return errors.Errorf("Unsupported graph format %q, expecting one of dot, mermaid or json", format)
+	}
+	query := url.Values{}
+	query.Set("format", format)
+	if horizontal {
+		query.Set("horizontal", "true")
+	}
+	request, err := client.NewRequest("GET", path.Join("/deployments", args[0], "tasks", args[1], "graph")+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Add("Accept", accept)
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	ids := args[0] + "/" + args[1]
+	httputil.HandleHTTPStatusCode(response, ids, "deployment/task", http.StatusOK)
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(body))
+	return nil
+}
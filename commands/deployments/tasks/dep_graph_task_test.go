@@ -0,0 +1,99 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tasks
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type httpClientMockGraph struct {
+	lastRequest *http.Request
+}
+
+func (c *httpClientMockGraph) Do(req *http.Request) (*http.Response, error) {
+	c.lastRequest = req
+	if strings.Contains(req.URL.String(), "fails") {
+		return nil, errors.New("a failure occurs")
+	}
+	w := httptest.NewRecorder()
+	w.WriteString("digraph {}")
+	return w.Result(), nil
+}
+
+func (c *httpClientMockGraph) NewRequest(method, path string, body io.Reader) (*http.Request, error) {
+	return http.NewRequest(method, path, body)
+}
+
+func (c *httpClientMockGraph) Get(path string) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func (c *httpClientMockGraph) Head(path string) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func (c *httpClientMockGraph) Post(path string, contentType string, body io.Reader) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func (c *httpClientMockGraph) PostForm(path string, data url.Values) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func TestTaskGraph(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		format     string
+		horizontal bool
+		wantQuery  string
+		wantAccept string
+		wantErr    bool
+	}{
+		{"DOT", []string{"deploymentID", "taskID"}, "dot", false, "format=dot", "text/vnd.graphviz", false},
+		{"MermaidHorizontal", []string{"deploymentID", "taskID"}, "mermaid", true, "format=mermaid&horizontal=true", "text/plain", false},
+		{"JSON", []string{"deploymentID", "taskID"}, "json", false, "format=json", "application/json", false},
+		{"UnsupportedFormat", []string{"deploymentID", "taskID"}, "png", false, "", "", true},
+		{"MissingTaskID", []string{"deploymentID"}, "dot", false, "", "", true},
+		{"HTTPFailure", []string{"fails", "taskID"}, "dot", false, "format=dot", "text/vnd.graphviz", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &httpClientMockGraph{}
+			err := taskGraph(client, tt.args, tt.format, tt.horizontal)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			if tt.wantQuery == "" {
+				require.Nil(t, client.lastRequest)
+				return
+			}
+			require.NotNil(t, client.lastRequest)
+			require.Equal(t, "/deployments/"+tt.args[0]+"/tasks/taskID/graph", client.lastRequest.URL.Path)
+			require.Equal(t, tt.wantQuery, client.lastRequest.URL.RawQuery)
+			require.Equal(t, tt.wantAccept, client.lastRequest.Header.Get("Accept"))
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/pkg/errors"
@@ -98,6 +99,20 @@ func taskInfo(client httputil.HTTPClient, args []string, withSteps bool) error {
 		fmt.Println("Task Error Message:", task.ErrorMessage)
 	}
 
+	if task.Approval != nil {
+		printTaskApproval(task.Approval)
+	}
+
+	if len(task.Signals) > 0 {
+		signalsTable := tabutil.NewTable()
+		signalsTable.AddHeaders("Step", "Signal", "Status", "Waiting Since")
+		for _, signal := range task.Signals {
+			signalsTable.AddRow(signal.Step, signal.Signal, signal.Status, signal.WaitingDate.Format(time.RFC3339))
+		}
+		fmt.Println("Task Signals:")
+		fmt.Println(signalsTable.Render())
+	}
+
 	if withSteps {
 		displayStepTables(client, args)
 	}
@@ -156,7 +171,7 @@ func getColoredTaskStepStatus(colorize bool, status string) string {
 	switch strings.ToLower(status) {
 	case "error":
 		return color.New(color.FgHiRed, color.Bold).SprintFunc()(status)
-	case "canceled", "running":
+	case "canceled", "running", "waiting":
 		return color.New(color.FgHiYellow, color.Bold).SprintFunc()(status)
 	case "done":
 		return color.New(color.FgHiGreen, color.Bold).SprintFunc()(status)
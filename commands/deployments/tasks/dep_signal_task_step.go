@@ -0,0 +1,69 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tasks
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ystia/yorc/v4/commands/deployments"
+	"github.com/ystia/yorc/v4/commands/httputil"
+)
+
+func init() {
+	var jsonParam string
+	var signalTaskStepCmd = &cobra.Command{
+		Use:   "signal <DeploymentId> <TaskId> <StepName>",
+		Short: "Send a signal to a deployment task step",
+		Long: `Send a signal to a workflow step waiting for it, specifying the deployment id, the task id and the step name.
+	The step should be in status "WAITING". Inputs provided in the signal payload are available to the next steps
+	of the workflow as workflow inputs.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := httputil.GetClient(deployments.ClientConfig)
+			if err != nil {
+				httputil.ErrExit(err)
+			}
+			return signalTaskStep(client, args, jsonParam)
+		},
+	}
+	signalTaskStepCmd.Flags().StringVarP(&jsonParam, "data", "d", "", `Provide the signal payload in JSON format, for example: {"inputs": {"version": "1.2"}}`)
+	tasksCmd.AddCommand(signalTaskStepCmd)
+}
+
+func signalTaskStep(client httputil.HTTPClient, args []string, jsonParam string) error {
+	if len(args) != 3 {
+		return errors.Errorf("Expecting a deployment id, a task id and a step name (got %d parameters)", len(args))
+	}
+	url := path.Join("/deployments", args[0], "tasks", args[1], "steps", args[2], "signal")
+	request, err := client.NewRequest("POST", url, bytes.NewBuffer([]byte(jsonParam)))
+	if err != nil {
+		return err
+	}
+	request.Header.Add("Content-Type", "application/json")
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	ids := args[0] + "/" + args[1] + "/" + args[2]
+	httputil.HandleHTTPStatusCode(response, ids, "deployment/task/step", http.StatusAccepted)
+	fmt.Printf("Signal sent to step %q of task %q\n", args[2], args[1])
+	return nil
+}
@@ -17,10 +17,11 @@ package tasks
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"net/http"
-	"os"
-	"path"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/pkg/errors"
@@ -34,15 +35,69 @@ import (
 )
 
 func init() {
+	tasksCmd.Flags().StringSliceVar(&tasksFilter.types, "type", nil, "Only list tasks of the given types (Deploy, UnDeploy, CustomWorkflow, ...)")
+	tasksCmd.Flags().StringSliceVar(&tasksFilter.statuses, "status", nil, "Only list tasks in the given statuses (INITIAL, RUNNING, DONE, FAILED, CANCELED)")
+	tasksCmd.Flags().StringSliceVar(&tasksFilter.workflows, "workflow", nil, "Only list tasks of the given workflows")
+	tasksCmd.Flags().StringVar(&tasksFilter.from, "from", "", "Only list tasks created at or after this RFC3339 date")
+	tasksCmd.Flags().StringVar(&tasksFilter.to, "to", "", "Only list tasks created before this RFC3339 date")
+	tasksCmd.Flags().StringVar(&tasksFilter.sort, "sort", "", "Sort tasks by creation_date, duration, type or status, prefix by '-' for a descending order (defaults to -creation_date)")
+	tasksCmd.Flags().IntVar(&tasksFilter.limit, "limit", 0, "Maximum number of tasks to list (defaults to the server page size)")
+	tasksCmd.Flags().StringVar(&tasksFilter.cursor, "cursor", "", "Cursor returned by a previous listing to get the next tasks")
+	tasksCmd.Flags().BoolVar(&tasksFilter.all, "all", false, "List all the tasks matching filters, retrieving all pages")
 	deployments.DeploymentsCmd.AddCommand(tasksCmd)
 }
 
+// tasksListOptions are the filters and pagination options of a tasks listing
+type tasksListOptions struct {
+	types     []string
+	statuses  []string
+	workflows []string
+	from      string
+	to        string
+	sort      string
+	limit     int
+	cursor    string
+	all       bool
+}
+
+func (o tasksListOptions) queryValues() url.Values {
+	values := url.Values{}
+	if len(o.types) > 0 {
+		values.Set("type", strings.Join(o.types, ","))
+	}
+	if len(o.statuses) > 0 {
+		values.Set("status", strings.Join(o.statuses, ","))
+	}
+	if len(o.workflows) > 0 {
+		values.Set("workflow", strings.Join(o.workflows, ","))
+	}
+	if o.from != "" {
+		values.Set("from", o.from)
+	}
+	if o.to != "" {
+		values.Set("to", o.to)
+	}
+	if o.sort != "" {
+		values.Set("sort", o.sort)
+	}
+	if o.limit > 0 {
+		values.Set("limit", strconv.Itoa(o.limit))
+	}
+	if o.cursor != "" {
+		values.Set("cursor", o.cursor)
+	}
+	return values
+}
+
+var tasksFilter tasksListOptions
+
 var commErrorMsg = httputil.YorcAPIDefaultErrorMsg
 var tasksCmd = &cobra.Command{
 	Use:   "tasks <DeploymentId>",
 	Short: "List tasks of a deployment",
 	Long: `Display info about the tasks related to a given deployment.
-    It prints the tasks ID, type and status.`,
+    It prints the tasks ID, type, workflow, status, creation date, duration and steps.
+    Tasks could be filtered by type, status, workflow and creation date range.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
 			return errors.Errorf("Expecting a deployment id (got %d parameters)", len(args))
@@ -53,57 +108,77 @@ var tasksCmd = &cobra.Command{
 		}
 		colorize := !deployments.NoColor
 		if colorize {
-			commErrorMsg = color.New(color.FgHiRed, color.Bold).SprintFunc()(commErrorMsg)
+			defer color.Unset()
 		}
-		request, err := client.NewRequest("GET", "/deployments/"+args[0], nil)
+		tasksList, nextCursor, err := listTasks(client, args[0], tasksFilter)
 		if err != nil {
 			httputil.ErrExit(err)
 		}
-		request.Header.Add("Accept", "application/json")
-		response, err := client.Do(request)
-		if err != nil {
-			httputil.ErrExit(err)
+		fmt.Println("Tasks:")
+		fmt.Println(renderTasks(tasksList, colorize))
+		if nextCursor != "" {
+			fmt.Printf("\nMore tasks are available, use --cursor %s to list them.\n", nextCursor)
 		}
-		defer response.Body.Close()
-		httputil.HandleHTTPStatusCode(response, args[0], "deployment", http.StatusOK)
-		var dep rest.Deployment
-		body, err := ioutil.ReadAll(response.Body)
+		return nil
+	},
+}
+
+// listTasks returns the tasks of a deployment matching the given options and the cursor of the next page if any
+func listTasks(client httputil.HTTPClient, deploymentID string, opts tasksListOptions) ([]rest.TaskSummary, string, error) {
+	values := opts.queryValues()
+	tasksList := make([]rest.TaskSummary, 0)
+	for {
+		request, err := client.NewRequest("GET", "/deployments/"+deploymentID+"/tasks", nil)
 		if err != nil {
-			httputil.ErrExit(err)
+			return nil, "", err
 		}
-		err = json.Unmarshal(body, &dep)
+		request.URL.RawQuery = values.Encode()
+		request.Header.Add("Accept", "application/json")
+		response, err := client.Do(request)
 		if err != nil {
-			httputil.ErrExit(err)
-		}
-		if colorize {
-			defer color.Unset()
+			return nil, "", err
 		}
-		fmt.Println("Tasks:")
-		tasksTable := tabutil.NewTable()
-		tasksTable.AddHeaders("Id", "Type", "Status")
-		errs := make([]error, 0)
-		for _, atomLink := range dep.Links {
-			if atomLink.Rel == rest.LinkRelTask {
-				var task rest.Task
-				err = httputil.GetJSONEntityFromAtomGetRequest(client, atomLink, &task)
-				if err != nil {
-					errs = append(errs, err)
-					tasksTable.AddRow(path.Base(atomLink.Href), commErrorMsg)
-					continue
-				}
-				// Ignore TaskTypeAction
-				if tasks.TaskTypeAction.String() != task.Type {
-					tasksTable.AddRow(task.ID, task.Type, deployments.GetColoredTaskStatus(colorize, task.Status))
-				}
+		collection := new(rest.TaskHistoryCollection)
+		switch response.StatusCode {
+		case http.StatusOK:
+			err = json.NewDecoder(response.Body).Decode(collection)
+			response.Body.Close()
+			if err != nil {
+				return nil, "", errors.Wrap(err, "failed to read tasks list")
 			}
+		case http.StatusNoContent:
+			response.Body.Close()
+		case http.StatusNotFound:
+			response.Body.Close()
+			return nil, "", errors.Errorf("The deployment with the following id %q doesn't exist", deploymentID)
+		default:
+			httputil.PrintErrors(response.Body)
+			response.Body.Close()
+			return nil, "", errors.Errorf("Expecting HTTP Status code 200, got %d, reason %q", response.StatusCode, response.Status)
 		}
-		fmt.Println(tasksTable.Render())
-		if len(errs) > 0 {
-			fmt.Fprintln(os.Stderr, "\n\nErrors encountered:")
-			for _, err := range errs {
-				fmt.Fprintln(os.Stderr, "###################\n", err)
+		for _, task := range collection.Tasks {
+			// Ignore TaskTypeAction
+			if tasks.TaskTypeAction.String() != task.Type {
+				tasksList = append(tasksList, task)
 			}
 		}
-		return nil
-	},
+		if !opts.all || collection.NextCursor == "" {
+			return tasksList, collection.NextCursor, nil
+		}
+		values.Set("cursor", collection.NextCursor)
+	}
+}
+
+func renderTasks(tasksList []rest.TaskSummary, colorize bool) string {
+	tasksTable := tabutil.NewTable()
+	tasksTable.AddHeaders("Id", "Type", "Workflow", "Status", "Created", "Duration", "Steps")
+	for _, task := range tasksList {
+		steps := ""
+		if task.Steps != nil {
+			steps = fmt.Sprintf("%d/%d done", task.Steps.ByStatus[strings.ToLower(tasks.TaskStepStatusDONE.String())], task.Steps.Total)
+		}
+		tasksTable.AddRow(task.ID, task.Type, task.WorkflowName, deployments.GetColoredTaskStatus(colorize, task.Status),
+			task.CreationDate.Local().Format(time.RFC3339), task.Duration, steps)
+	}
+	return tasksTable.Render()
 }
@@ -0,0 +1,116 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tasks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/rest"
+)
+
+type httpClientListTasks struct {
+	statusCode int
+	pages      map[string]rest.TaskHistoryCollection
+	requests   []*http.Request
+}
+
+func (c *httpClientListTasks) Do(req *http.Request) (*http.Response, error) {
+	c.requests = append(c.requests, req)
+	w := httptest.NewRecorder()
+	w.WriteHeader(c.statusCode)
+	if c.statusCode == http.StatusOK {
+		json.NewEncoder(w).Encode(c.pages[req.URL.Query().Get("cursor")])
+	}
+	return w.Result(), nil
+}
+
+func (c *httpClientListTasks) NewRequest(method, path string, body io.Reader) (*http.Request, error) {
+	return http.NewRequest(method, path, body)
+}
+
+func (c *httpClientListTasks) Get(path string) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func (c *httpClientListTasks) Head(path string) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func (c *httpClientListTasks) Post(path string, contentType string, body io.Reader) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func (c *httpClientListTasks) PostForm(path string, data url.Values) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func TestListTasks(t *testing.T) {
+	pages := map[string]rest.TaskHistoryCollection{
+		"": {Tasks: []rest.TaskSummary{{ID: "t1", Type: "Deploy"}, {ID: "a1", Type: "Action"}}, NextCursor: "c1"},
+		"c1": {Tasks: []rest.TaskSummary{{ID: "t2", Type: "CustomWorkflow",
+			Steps: &rest.TaskStepsCount{Total: 3, ByStatus: map[string]int{"done": 2, "error": 1}}}}},
+	}
+	opts := tasksListOptions{types: []string{"Deploy", "CustomWorkflow"}, statuses: []string{"DONE"}, from: "2019-06-12T10:00:00Z", sort: "duration", limit: 1}
+
+	client := &httpClientListTasks{statusCode: http.StatusOK, pages: pages}
+	tasksList, next, err := listTasks(client, "dep", opts)
+	require.NoError(t, err)
+	require.Equal(t, "c1", next)
+	require.Len(t, tasksList, 1)
+	require.Equal(t, "t1", tasksList[0].ID)
+	require.Len(t, client.requests, 1)
+	require.Equal(t, "/deployments/dep/tasks", client.requests[0].URL.Path)
+	query := client.requests[0].URL.Query()
+	require.Equal(t, "Deploy,CustomWorkflow", query.Get("type"))
+	require.Equal(t, "DONE", query.Get("status"))
+	require.Equal(t, "2019-06-12T10:00:00Z", query.Get("from"))
+	require.Equal(t, "duration", query.Get("sort"))
+	require.Equal(t, "1", query.Get("limit"))
+
+	opts.all = true
+	client = &httpClientListTasks{statusCode: http.StatusOK, pages: pages}
+	tasksList, next, err = listTasks(client, "dep", opts)
+	require.NoError(t, err)
+	require.Empty(t, next)
+	require.Len(t, tasksList, 2)
+	require.Len(t, client.requests, 2)
+	require.Equal(t, "c1", client.requests[1].URL.Query().Get("cursor"))
+	require.Contains(t, renderTasks(tasksList, false), "2/3 done")
+
+	client = &httpClientListTasks{statusCode: http.StatusNoContent}
+	tasksList, _, err = listTasks(client, "dep", tasksListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, tasksList)
+
+	client = &httpClientListTasks{statusCode: http.StatusNotFound}
+	_, _, err = listTasks(client, "unknown", tasksListOptions{})
+	require.Error(t, err)
+}
+
+func TestRenderTasks(t *testing.T) {
+	creation := time.Date(2019, 6, 12, 10, 0, 0, 0, time.UTC)
+	out := renderTasks([]rest.TaskSummary{{ID: "t1", Type: "Deploy", WorkflowName: "install", Status: "DONE", CreationDate: creation, Duration: "1m30s"}}, false)
+	require.Contains(t, out, "install")
+	require.Contains(t, out, "1m30s")
+	require.Contains(t, out, creation.Local().Format(time.RFC3339))
+}
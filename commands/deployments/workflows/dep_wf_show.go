@@ -95,6 +95,9 @@ func init() {
 					if activity.Inline != nil {
 						fmt.Println("      - Inline:", activity.Inline.Workflow)
 					}
+					if activity.WaitForSignal != nil {
+						fmt.Println("      - Wait For Signal:", activity.WaitForSignal.Signal)
+					}
 				}
 				if len(step.OnSuccess) > 0 {
 					fmt.Println("    On Success:")
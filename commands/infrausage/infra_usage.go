@@ -0,0 +1,232 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package infrausage provides the command querying infrastructures usage collectors
+package infrausage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ystia/yorc/v4/commands"
+	"github.com/ystia/yorc/v4/commands/httputil"
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/tabutil"
+	"github.com/ystia/yorc/v4/prov"
+	"github.com/ystia/yorc/v4/rest"
+)
+
+// queryPollInterval is the interval between two checks of a query task status
+var queryPollInterval = time.Second
+
+var infraUsageViper = viper.New()
+
+// ClientConfig is the Yorc client configuration resolved by cobra/viper
+var ClientConfig config.Client
+
+var noColor bool
+var cfgFile string
+
+// InfraUsageCmd is the command querying infrastructures usage
+var InfraUsageCmd = &cobra.Command{
+	Use:   "infra-usage <infrastructure> <location>",
+	Short: "Get the usage of an infrastructure",
+	Long: `Queries an infrastructure usage collector to get the usage of the resources of a location.
+
+Builtin collectors are "hostspool" (parameter: group_by), "slurm" (parameters: accounts, start, end)
+and "kubernetes" (parameter: namespaces). Other collectors may be provided by plugins.`,
+	SilenceErrors: true,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		ClientConfig = commands.GetYorcClientConfig(infraUsageViper, cfgFile)
+	},
+}
+
+func init() {
+	var params []string
+	var asJSON, keep bool
+	var timeout time.Duration
+	InfraUsageCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return errors.Errorf("Expecting an infrastructure and a location (got %d parameters)", len(args))
+		}
+		queryParams := make(map[string]string, len(params))
+		for _, p := range params {
+			kv := strings.SplitN(p, "=", 2)
+			if len(kv) != 2 {
+				return errors.Errorf("Invalid parameter %q, expecting key=value", p)
+			}
+			queryParams[kv[0]] = kv[1]
+		}
+		client, err := httputil.GetClient(ClientConfig)
+		if err != nil {
+			httputil.ErrExit(err)
+		}
+		task, err := queryInfraUsage(client, args[0], args[1], queryParams, timeout, keep)
+		if err != nil {
+			return err
+		}
+		return printInfraUsage(os.Stdout, task.ResultSet, asJSON)
+	}
+	InfraUsageCmd.Flags().StringArrayVarP(&params, "param", "p", nil, "Collector parameter as key=value, may be repeated")
+	InfraUsageCmd.Flags().BoolVar(&asJSON, "json", false, "Print the raw JSON result")
+	InfraUsageCmd.Flags().BoolVar(&keep, "keep", false, "Keep the query task on the Yorc server once done")
+	InfraUsageCmd.Flags().DurationVar(&timeout, "timeout", 5*time.Minute, "Maximum duration to wait for the query result")
+
+	commands.RootCmd.AddCommand(InfraUsageCmd)
+	commands.ConfigureYorcClientCommand(InfraUsageCmd, infraUsageViper, &cfgFile, &noColor)
+}
+
+// queryInfraUsage submits an infrastructure usage query and waits for its result
+func queryInfraUsage(client httputil.HTTPClient, infraName, locationName string, params map[string]string, timeout time.Duration, keep bool) (*rest.Task, error) {
+	request, err := client.NewRequest(http.MethodPost, path.Join("/infra_usage", infraName, locationName), nil)
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{}
+	for k, v := range params {
+		q.Set(k, v)
+	}
+	request.URL.RawQuery = q.Encode()
+	request.Header.Add("Content-Type", "application/json")
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusAccepted {
+		return nil, errors.Errorf("Expecting HTTP Status code 202, got %d, reason %q", response.StatusCode, response.Status)
+	}
+	taskPath := response.Header.Get("Location")
+	if taskPath == "" {
+		return nil, errors.New("Missing query task location in response")
+	}
+	if !keep {
+		defer deleteQuery(client, taskPath)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		task, err := getQueryTask(client, taskPath)
+		if err != nil {
+			return nil, err
+		}
+		switch task.Status {
+		case "DONE":
+			return task, nil
+		case "FAILED", "CANCELED":
+			return nil, errors.Errorf("Infrastructure usage query %s failed: %s", task.ID, task.ErrorMessage)
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.Errorf("Infrastructure usage query %s not done after %v", task.ID, timeout)
+		}
+		time.Sleep(queryPollInterval)
+	}
+}
+
+func getQueryTask(client httputil.HTTPClient, taskPath string) (*rest.Task, error) {
+	request, err := client.NewRequest(http.MethodGet, taskPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("Accept", "application/json")
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		httputil.PrintErrors(response.Body)
+		return nil, errors.Errorf("Expecting HTTP Status code 200, got %d, reason %q", response.StatusCode, response.Status)
+	}
+	task := new(rest.Task)
+	err = json.NewDecoder(response.Body).Decode(task)
+	return task, errors.Wrap(err, "failed to read query task")
+}
+
+func deleteQuery(client httputil.HTTPClient, taskPath string) {
+	request, err := client.NewRequest(http.MethodDelete, taskPath, nil)
+	if err != nil {
+		return
+	}
+	response, err := client.Do(request)
+	if err == nil {
+		response.Body.Close()
+	}
+}
+
+// printInfraUsage prints the result of a query, normalized usages are printed as a table
+func printInfraUsage(w io.Writer, resultSet json.RawMessage, asJSON bool) error {
+	var usage prov.InfraUsage
+	if !asJSON {
+		err := json.Unmarshal(resultSet, &usage)
+		asJSON = err != nil || (usage.Resources == nil && usage.Groups == nil)
+	}
+	if asJSON {
+		var out bytes.Buffer
+		if err := json.Indent(&out, resultSet, "", "  "); err != nil {
+			return errors.Wrap(err, "failed to format query result")
+		}
+		_, err := fmt.Fprintln(w, out.String())
+		return err
+	}
+
+	table := tabutil.NewTable()
+	table.AddHeaders("Group", "Resource", "Used", "Capacity")
+	for _, r := range usage.Resources {
+		table.AddRow("", r.Name, formatQuantity(r.Used, r.Unit), formatCapacity(r))
+	}
+	for _, g := range usage.Groups {
+		for i, r := range g.Resources {
+			group := ""
+			if i == 0 {
+				group = g.Kind + " " + g.Name
+			}
+			table.AddRow(group, r.Name, formatQuantity(r.Used, r.Unit), formatCapacity(r))
+		}
+	}
+	_, err := fmt.Fprintf(w, "Usage of %s location %q at %s:\n%s\n", usage.Infrastructure, usage.Location,
+		usage.CollectDate.Format(time.RFC3339), table.Render())
+	return err
+}
+
+func formatCapacity(r prov.ResourceUsage) string {
+	if r.Capacity == nil {
+		return "-"
+	}
+	return formatQuantity(*r.Capacity, r.Unit)
+}
+
+func formatQuantity(v float64, unit string) string {
+	switch unit {
+	case prov.UnitBytes:
+		return humanize.IBytes(uint64(v))
+	case "":
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64) + " " + strings.Replace(unit, "_", "-", -1)
+}
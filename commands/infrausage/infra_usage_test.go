@@ -0,0 +1,156 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infrausage
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/prov"
+	"github.com/ystia/yorc/v4/rest"
+)
+
+type httpClientMockInfraUsage struct {
+	submitStatus int
+	tasks        []rest.Task
+	requests     []*http.Request
+}
+
+func (c *httpClientMockInfraUsage) Do(req *http.Request) (*http.Response, error) {
+	c.requests = append(c.requests, req)
+	res := httptest.NewRecorder()
+	switch req.Method {
+	case http.MethodPost:
+		res.Header().Set("Location", "/infra_usage/myinfra/myloc/tasks/t1")
+		res.WriteHeader(c.submitStatus)
+	case http.MethodGet:
+		task := c.tasks[0]
+		if len(c.tasks) > 1 {
+			c.tasks = c.tasks[1:]
+		}
+		res.WriteHeader(http.StatusOK)
+		json.NewEncoder(res).Encode(task)
+	default:
+		res.WriteHeader(http.StatusAccepted)
+	}
+	return res.Result(), nil
+}
+
+func (c *httpClientMockInfraUsage) NewRequest(method, path string, body io.Reader) (*http.Request, error) {
+	return http.NewRequest(method, path, body)
+}
+
+func (c *httpClientMockInfraUsage) Get(path string) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func (c *httpClientMockInfraUsage) Head(path string) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func (c *httpClientMockInfraUsage) Post(path string, contentType string, body io.Reader) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func (c *httpClientMockInfraUsage) PostForm(path string, data url.Values) (*http.Response, error) {
+	return &http.Response{}, nil
+}
+
+func (c *httpClientMockInfraUsage) methods() []string {
+	res := make([]string, len(c.requests))
+	for i, r := range c.requests {
+		res[i] = r.Method
+	}
+	return res
+}
+
+func TestQueryInfraUsage(t *testing.T) {
+	queryPollInterval = time.Millisecond
+	result := json.RawMessage(`{"infrastructure":"myinfra"}`)
+	tests := []struct {
+		name        string
+		client      *httpClientMockInfraUsage
+		keep        bool
+		wantMethods []string
+		wantErr     bool
+	}{
+		{"Done", &httpClientMockInfraUsage{submitStatus: http.StatusAccepted, tasks: []rest.Task{
+			{ID: "t1", Status: "RUNNING"}, {ID: "t1", Status: "DONE", ResultSet: result}}},
+			false, []string{"POST", "GET", "GET", "DELETE"}, false},
+		{"DoneKept", &httpClientMockInfraUsage{submitStatus: http.StatusAccepted, tasks: []rest.Task{
+			{ID: "t1", Status: "DONE", ResultSet: result}}},
+			true, []string{"POST", "GET"}, false},
+		{"Failed", &httpClientMockInfraUsage{submitStatus: http.StatusAccepted, tasks: []rest.Task{
+			{ID: "t1", Status: "FAILED", ErrorMessage: "no such collector"}}},
+			false, []string{"POST", "GET", "DELETE"}, true},
+		{"Rejected", &httpClientMockInfraUsage{submitStatus: http.StatusBadRequest},
+			false, []string{"POST"}, true},
+		{"Timeout", &httpClientMockInfraUsage{submitStatus: http.StatusAccepted, tasks: []rest.Task{
+			{ID: "t1", Status: "RUNNING"}}},
+			false, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, err := queryInfraUsage(tt.client, "myinfra", "myloc", map[string]string{"group_by": "zone"}, 10*time.Millisecond, tt.keep)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.JSONEq(t, string(result), string(task.ResultSet))
+			}
+			require.Equal(t, "/infra_usage/myinfra/myloc", tt.client.requests[0].URL.Path)
+			require.Equal(t, "group_by=zone", tt.client.requests[0].URL.RawQuery)
+			if tt.wantMethods != nil {
+				require.Equal(t, tt.wantMethods, tt.client.methods())
+			}
+		})
+	}
+}
+
+func TestPrintInfraUsage(t *testing.T) {
+	usage := prov.InfraUsage{
+		Infrastructure: "hostspool",
+		Location:       "myloc",
+		Resources: []prov.ResourceUsage{
+			prov.NewResourceUsage(prov.ResourceMemory, prov.UnitBytes, 1024*1024*1024, 512*1024*1024),
+		},
+		Groups: []prov.InfraUsageGroup{
+			{Kind: "label", Name: "zone=a", Resources: []prov.ResourceUsage{{Name: prov.ResourceHosts, Used: 2}}},
+		},
+	}
+	b, err := json.Marshal(usage)
+	require.NoError(t, err)
+
+	out := new(bytes.Buffer)
+	require.NoError(t, printInfraUsage(out, b, false))
+	require.Contains(t, out.String(), "Usage of hostspool location \"myloc\"")
+	require.Contains(t, out.String(), "512 MiB")
+	require.Contains(t, out.String(), "1.0 GiB")
+	require.Contains(t, out.String(), "label zone=a")
+
+	out.Reset()
+	require.NoError(t, printInfraUsage(out, json.RawMessage(`{"custom":1}`), false))
+	require.Contains(t, out.String(), "\"custom\": 1")
+
+	require.Error(t, printInfraUsage(out, json.RawMessage(`not json`), true))
+}
@@ -245,6 +245,7 @@ func setConfig() {
 	//Flags definition for Yorc server
 	serverCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "config file (default is /etc/yorc/config.yorc.json)")
 	serverCmd.PersistentFlags().String("plugins_directory", config.DefaultPluginDir, "The name of the plugins directory of the Yorc server")
+	serverCmd.PersistentFlags().Duration("plugins_health_check_interval", config.DefaultPluginsHealthCheckInterval, "Interval between two checks of plugins health and of plugins directory changes. Crashed plugins are restarted and new or updated plugins are loaded.")
 	serverCmd.PersistentFlags().StringP("working_directory", "w", "", "The name of the working directory of the Yorc server")
 	serverCmd.PersistentFlags().Int("workers_number", config.DefaultWorkersNumber, "Number of workers in the Yorc server. If not set the default value will be used")
 	serverCmd.PersistentFlags().Duration("graceful_shutdown_timeout", config.DefaultServerGracefulShutdownTimeout, "Timeout to  wait for a graceful shutdown of the Yorc server. After this delay the server immediately exits.")
@@ -261,6 +262,12 @@ func setConfig() {
 
 	serverCmd.PersistentFlags().Duration("tasks_dispatcher_long_poll_wait_time", config.DefaultTasksDispatcherLongPollWaitTime, "Wait time when long polling for executions tasks to dispatch to workers")
 	serverCmd.PersistentFlags().Duration("tasks_dispatcher_lock_wait_time", config.DefaultTasksDispatcherLockWaitTime, "Wait time for acquiring a lock for an execution task")
+	serverCmd.PersistentFlags().Duration("tasks_approval_timeout", config.DefaultTasksApprovalTimeout, "Delay after which a task waiting for approvals is canceled")
+
+	serverCmd.PersistentFlags().Duration("deployments_expiry_check_interval", config.DefaultDeploymentsExpiryCheckInterval, "Interval between two checks of deployments expiry dates")
+	serverCmd.PersistentFlags().Duration("deployments_expiry_warning_delay", config.DefaultDeploymentsExpiryWarningDelay, "Delay before the expiry date of a deployment at which a warning is sent")
+	serverCmd.PersistentFlags().Duration("deployments_expiry_max_ttl", 0, "Maximum time-to-live of deployments, no limit is applied if not set")
+	serverCmd.PersistentFlags().StringSlice("deployments_expiry_webhooks", nil, "URLs notified of deployments expiry warnings and expirations")
 
 	// Flags definition for Yorc HTTP REST API
 	serverCmd.PersistentFlags().Int("http_port", config.DefaultHTTPPort, "Port number for the Yorc HTTP REST API. If omitted or set to '0' then the default port number is used, any positive integer will be used as it, and finally any negative value will let use a random port.")
@@ -313,6 +320,7 @@ func setConfig() {
 	//Bind Flags for Yorc server
 	viper.BindPFlag("working_directory", serverCmd.PersistentFlags().Lookup("working_directory"))
 	viper.BindPFlag("plugins_directory", serverCmd.PersistentFlags().Lookup("plugins_directory"))
+	viper.BindPFlag("plugins_health_check_interval", serverCmd.PersistentFlags().Lookup("plugins_health_check_interval"))
 	viper.BindPFlag("workers_number", serverCmd.PersistentFlags().Lookup("workers_number"))
 	viper.BindPFlag("server_graceful_shutdown_timeout", serverCmd.PersistentFlags().Lookup("graceful_shutdown_timeout"))
 	viper.BindPFlag("resources_prefix", serverCmd.PersistentFlags().Lookup("resources_prefix"))
@@ -328,6 +336,12 @@ func setConfig() {
 
 	viper.BindPFlag("tasks.dispatcher.long_poll_wait_time", serverCmd.PersistentFlags().Lookup("tasks_dispatcher_long_poll_wait_time"))
 	viper.BindPFlag("tasks.dispatcher.lock_wait_time", serverCmd.PersistentFlags().Lookup("tasks_dispatcher_lock_wait_time"))
+	viper.BindPFlag("tasks.approval.timeout", serverCmd.PersistentFlags().Lookup("tasks_approval_timeout"))
+
+	viper.BindPFlag("deployments_expiry.check_interval", serverCmd.PersistentFlags().Lookup("deployments_expiry_check_interval"))
+	viper.BindPFlag("deployments_expiry.warning_delay", serverCmd.PersistentFlags().Lookup("deployments_expiry_warning_delay"))
+	viper.BindPFlag("deployments_expiry.max_ttl", serverCmd.PersistentFlags().Lookup("deployments_expiry_max_ttl"))
+	viper.BindPFlag("deployments_expiry.webhooks", serverCmd.PersistentFlags().Lookup("deployments_expiry_webhooks"))
 
 	//Bind Flags Yorc HTTP REST API
 	viper.BindPFlag("http_port", serverCmd.PersistentFlags().Lookup("http_port"))
@@ -353,6 +367,7 @@ func setConfig() {
 	viper.AutomaticEnv() // read in environment variables that match
 	viper.BindEnv("working_directory")
 	viper.BindEnv("plugins_directory")
+	viper.BindEnv("plugins_health_check_interval")
 	viper.BindEnv("server_graceful_shutdown_timeout")
 	viper.BindEnv("workers_number")
 	viper.BindEnv("http_port")
@@ -380,6 +395,11 @@ func setConfig() {
 	viper.BindEnv("purged_deployments_eviction_timeout")
 	viper.BindEnv("tasks.dispatcher.long_poll_wait_time")
 	viper.BindEnv("tasks.dispatcher.lock_wait_time")
+	viper.BindEnv("tasks.approval.timeout")
+	viper.BindEnv("deployments_expiry.check_interval", toEnvVar("deployments_expiry.check_interval"))
+	viper.BindEnv("deployments_expiry.warning_delay", toEnvVar("deployments_expiry.warning_delay"))
+	viper.BindEnv("deployments_expiry.max_ttl", toEnvVar("deployments_expiry.max_ttl"))
+	viper.BindEnv("deployments_expiry.webhooks", toEnvVar("deployments_expiry.webhooks"))
 
 	//Bind Ansible environment variables flags
 	for key := range ansibleConfiguration {
@@ -395,6 +415,7 @@ func setConfig() {
 	viper.SetDefault("working_directory", "work")
 	viper.SetDefault("server_graceful_shutdown_timeout", config.DefaultServerGracefulShutdownTimeout)
 	viper.SetDefault("plugins_directory", config.DefaultPluginDir)
+	viper.SetDefault("plugins_health_check_interval", config.DefaultPluginsHealthCheckInterval)
 	viper.SetDefault("http_port", config.DefaultHTTPPort)
 	viper.SetDefault("http_address", config.DefaultHTTPAddress)
 	viper.SetDefault("resources_prefix", "yorc-")
@@ -410,6 +431,10 @@ func setConfig() {
 
 	viper.SetDefault("tasks.dispatcher.long_poll_wait_time", config.DefaultTasksDispatcherLongPollWaitTime)
 	viper.SetDefault("tasks.dispatcher.lock_wait_time", config.DefaultTasksDispatcherLockWaitTime)
+	viper.SetDefault("tasks.approval.timeout", config.DefaultTasksApprovalTimeout)
+
+	viper.SetDefault("deployments_expiry.check_interval", config.DefaultDeploymentsExpiryCheckInterval)
+	viper.SetDefault("deployments_expiry.warning_delay", config.DefaultDeploymentsExpiryWarningDelay)
 
 	// Consul configuration default settings
 	for key, value := range consulConfiguration {
@@ -0,0 +1,64 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ystia/yorc/v4/server"
+)
+
+func init() {
+	var output string
+	backupCmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Backup all Yorc data",
+		Long: `Takes a point-in-time consistent backup of all the data of the Yorc cluster.
+
+The backup contains the Yorc data stored in Consul (deployments, tasks, hosts pools,
+locations, monitoring checks, scheduled actions, ...), the content of the deployments,
+logs and events stores and the deployments files of the Yorc working directory.
+It uses the same configuration than the Yorc server.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output == "" {
+				return errors.New("an output file is required")
+			}
+			return server.Backup(GetConfig(), output)
+		},
+	}
+	backupCmd.Flags().StringVarP(&output, "output", "o", "", "Path of the backup file to create")
+
+	var force bool
+	restoreCmd := &cobra.Command{
+		Use:   "restore <backup_path>",
+		Short: "Restore Yorc data from a backup",
+		Long: `Restores a backup created by the backup command.
+
+Data of a backup taken with an older version of Yorc are upgraded to the current version.
+Yorc servers of the cluster should be stopped before restoring a backup.
+It uses the same configuration than the Yorc server.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return server.Restore(GetConfig(), args[0], force)
+		},
+	}
+	restoreCmd.Flags().BoolVarP(&force, "force", "f", false, "Replace the Yorc data already existing on the cluster by the backup ones")
+
+	serverCmd.AddCommand(backupCmd, restoreCmd)
+}
@@ -0,0 +1,125 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ystia/yorc/v4/cluster"
+	"github.com/ystia/yorc/v4/helper/tabutil"
+)
+
+// drainWaitInterval is the interval between two checks of a member state when waiting for it to be drained
+const drainWaitInterval = 2 * time.Second
+
+func init() {
+	membersCmd := &cobra.Command{
+		Use:   "members",
+		Short: "List the Yorc servers of the cluster",
+		Long: `Lists the live Yorc servers sharing the Consul cluster, their state, the leaderships
+they hold and the work they currently own.
+It uses the same configuration than the Yorc server.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cc, err := GetConfig().GetConsulClient()
+			if err != nil {
+				return errors.Wrap(err, "can't connect to Consul")
+			}
+			members, err := cluster.Members(cc)
+			if err != nil {
+				return err
+			}
+			table := tabutil.NewTable()
+			table.AddHeaders("Server ID", "Node", "State", "Roles", "Task Executions", "Checks", "Scheduled Actions")
+			for _, m := range members {
+				table.AddRow(m.ServerID, m.NodeName, m.State, strings.Join(m.Roles, ", "),
+					len(m.Work.TaskExecutions), len(m.Work.Checks), len(m.Work.ScheduledActions))
+			}
+			fmt.Println("Yorc cluster members:")
+			fmt.Println(table.Render())
+			return nil
+		},
+	}
+
+	var cancel, wait bool
+	var timeout time.Duration
+	drainCmd := &cobra.Command{
+		Use:   "drain <server_id>",
+		Short: "Drain a Yorc server before a maintenance",
+		Long: `Drains a Yorc server of the cluster.
+
+A draining server stops taking new task executions and gives up its leaderships
+(monitoring, scheduling, secrets leases renewal) to other servers of the cluster.
+Task executions already running on the server are completed. The server is drained
+when it doesn't own any work anymore, it stays drained across restarts until the
+drain is cancelled.
+It uses the same configuration than the Yorc server.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			serverID := args[0]
+			cc, err := GetConfig().GetConsulClient()
+			if err != nil {
+				return errors.Wrap(err, "can't connect to Consul")
+			}
+			if cancel {
+				err = cluster.Resume(cc, serverID)
+				if err == nil {
+					fmt.Printf("Server %q is now taking work\n", serverID)
+				}
+				return err
+			}
+			member, err := cluster.GetMember(cc, serverID)
+			if err != nil {
+				return err
+			}
+			if member == nil {
+				return errors.Errorf("no live Yorc server with ID %q in the cluster", serverID)
+			}
+			err = cluster.Drain(cc, serverID)
+			if err != nil || !wait {
+				return err
+			}
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+			for {
+				member, err = cluster.GetMember(cc, serverID)
+				if err != nil {
+					return err
+				}
+				if member == nil || member.State == cluster.StateDrained {
+					fmt.Printf("Server %q is drained\n", serverID)
+					return nil
+				}
+				select {
+				case <-timer.C:
+					return errors.Errorf("server %q is still draining after %v", serverID, timeout)
+				case <-time.After(drainWaitInterval):
+				}
+			}
+		},
+	}
+	drainCmd.Flags().BoolVar(&cancel, "cancel", false, "Cancel the drain of the server, it takes work again")
+	drainCmd.Flags().BoolVarP(&wait, "wait", "w", false, "Wait for the server to be drained")
+	drainCmd.Flags().DurationVar(&timeout, "timeout", 30*time.Minute, "Maximum duration to wait for the server to be drained")
+
+	serverCmd.AddCommand(membersCmd, drainCmd)
+}
@@ -0,0 +1,43 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ystia/yorc/v4/server"
+)
+
+func init() {
+	var dryRun bool
+	upgradeCmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade Yorc data schema",
+		Long: `Upgrades the schema of the Yorc data to the version of this Yorc version.
+
+This is done automatically when a Yorc server starts. Using the dry-run mode allows to
+report changes that would be done by the upgrade without applying them.
+It uses the same configuration than the Yorc server.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return server.UpgradeSchema(GetConfig(), dryRun, os.Stdout)
+		},
+	}
+	upgradeCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Report changes that would be done by the upgrade without applying them")
+	serverCmd.AddCommand(upgradeCmd)
+}
@@ -49,6 +49,9 @@ const DefaultHTTPAddress string = "0.0.0.0"
 // DefaultPluginDir is the default path for the plugin directory
 const DefaultPluginDir = "plugins"
 
+// DefaultPluginsHealthCheckInterval is the default interval between two checks of plugins health and of plugins directory changes
+const DefaultPluginsHealthCheckInterval = 30 * time.Second
+
 // DefaultServerGracefulShutdownTimeout is the default timeout for a graceful shutdown of a Yorc server before exiting
 const DefaultServerGracefulShutdownTimeout = 5 * time.Minute
 
@@ -69,6 +72,15 @@ const DefaultWfStepGracefulTerminationTimeout = 2 * time.Minute
 // DefaultPurgedDeploymentsEvictionTimeout is the default timeout after which final events and logs for a purged deployment are actually deleted.
 const DefaultPurgedDeploymentsEvictionTimeout = 30 * time.Minute
 
+// DefaultDeploymentsExpiryCheckInterval is the default interval between two checks of deployments expiry dates
+const DefaultDeploymentsExpiryCheckInterval = 1 * time.Minute
+
+// DefaultDeploymentsExpiryWarningDelay is the default delay before their expiry date at which a warning is sent for expiring deployments
+const DefaultDeploymentsExpiryWarningDelay = 1 * time.Hour
+
+// DefaultTasksApprovalTimeout is the default delay after which a task not approved is canceled
+const DefaultTasksApprovalTimeout = 24 * time.Hour
+
 // DefaultAnsibleJobMonInterval is the default monitoring interval for Jobs handled by Ansible
 const DefaultAnsibleJobMonInterval = 15 * time.Second
 
@@ -92,34 +104,36 @@ const DefaultSSHConnectionMaxRetries uint64 = 3
 
 // Configuration holds config information filled by Cobra and Viper (see commands package for more information)
 type Configuration struct {
-	Ansible                          Ansible       `yaml:"ansible,omitempty" mapstructure:"ansible"`
-	PluginsDirectory                 string        `yaml:"plugins_directory,omitempty" mapstructure:"plugins_directory"`
-	WorkingDirectory                 string        `yaml:"working_directory,omitempty" mapstructure:"working_directory"`
-	WorkersNumber                    int           `yaml:"workers_number,omitempty" mapstructure:"workers_number"`
-	ServerGracefulShutdownTimeout    time.Duration `yaml:"server_graceful_shutdown_timeout,omitempty" mapstructure:"server_graceful_shutdown_timeout"`
-	HTTPPort                         int           `yaml:"http_port,omitempty" mapstructure:"http_port"`
-	HTTPAddress                      string        `yaml:"http_address,omitempty" mapstructure:"http_address"`
-	KeyFile                          string        `yaml:"key_file,omitempty" mapstructure:"key_file"`
-	CertFile                         string        `yaml:"cert_file,omitempty" mapstructure:"cert_file"`
-	CAFile                           string        `yaml:"ca_file,omitempty" mapstructure:"ca_file"`
-	CAPath                           string        `yaml:"ca_path,omitempty" mapstructure:"ca_path"`
-	SSLVerify                        bool          `yaml:"ssl_verify,omitempty" mapstructure:"ssl_verify"`
-	ResourcesPrefix                  string        `yaml:"resources_prefix,omitempty" mapstructure:"resources_prefix"`
-	Consul                           Consul        `yaml:"consul,omitempty" mapstructure:"consul"`
-	Telemetry                        Telemetry     `yaml:"telemetry,omitempty" mapstructure:"telemetry"`
-	LocationsFilePath                string        `yaml:"locations_file_path,omitempty" mapstructure:"locations_file_path"`
-	Vault                            DynamicMap    `yaml:"vault,omitempty" mapstructure:"vault"`
-	WfStepGracefulTerminationTimeout time.Duration `yaml:"wf_step_graceful_termination_timeout,omitempty" mapstructure:"wf_step_graceful_termination_timeout"`
-	PurgedDeploymentsEvictionTimeout time.Duration `yaml:"purged_deployments_eviction_timeout,omitempty" mapstructure:"purged_deployments_eviction_timeout"`
-	ServerID                         string        `yaml:"server_id,omitempty" mapstructure:"server_id"`
-	Terraform                        Terraform     `yaml:"terraform,omitempty" mapstructure:"terraform"`
-	DisableSSHAgent                  bool          `yaml:"disable_ssh_agent,omitempty" mapstructure:"disable_ssh_agent"`
-	Tasks                            Tasks         `yaml:"tasks,omitempty" mapstructure:"tasks"`
-	Storage                          Storage       `yaml:"storage,omitempty" mapstructure:"storage"`
-	UpgradeConcurrencyLimit          int           `yaml:"concurrency_limit_for_upgrades,omitempty" mapstructure:"concurrency_limit_for_upgrades"`
-	SSHConnectionTimeout             time.Duration `yaml:"ssh_connection_timeout,omitempty" mapstructure:"ssh_connection_timeout"`
-	SSHConnectionRetryBackoff        time.Duration `yaml:"ssh_connection_retry_backoff,omitempty" mapstructure:"ssh_connection_retry_backoff"`
-	SSHConnectionMaxRetries          uint64        `yaml:"ssh_connection_max_retries,omitempty" mapstructure:"ssh_connection_max_retries"`
+	Ansible                          Ansible           `yaml:"ansible,omitempty" mapstructure:"ansible"`
+	PluginsDirectory                 string            `yaml:"plugins_directory,omitempty" mapstructure:"plugins_directory"`
+	PluginsHealthCheckInterval       time.Duration     `yaml:"plugins_health_check_interval,omitempty" mapstructure:"plugins_health_check_interval"`
+	WorkingDirectory                 string            `yaml:"working_directory,omitempty" mapstructure:"working_directory"`
+	WorkersNumber                    int               `yaml:"workers_number,omitempty" mapstructure:"workers_number"`
+	ServerGracefulShutdownTimeout    time.Duration     `yaml:"server_graceful_shutdown_timeout,omitempty" mapstructure:"server_graceful_shutdown_timeout"`
+	HTTPPort                         int               `yaml:"http_port,omitempty" mapstructure:"http_port"`
+	HTTPAddress                      string            `yaml:"http_address,omitempty" mapstructure:"http_address"`
+	KeyFile                          string            `yaml:"key_file,omitempty" mapstructure:"key_file"`
+	CertFile                         string            `yaml:"cert_file,omitempty" mapstructure:"cert_file"`
+	CAFile                           string            `yaml:"ca_file,omitempty" mapstructure:"ca_file"`
+	CAPath                           string            `yaml:"ca_path,omitempty" mapstructure:"ca_path"`
+	SSLVerify                        bool              `yaml:"ssl_verify,omitempty" mapstructure:"ssl_verify"`
+	ResourcesPrefix                  string            `yaml:"resources_prefix,omitempty" mapstructure:"resources_prefix"`
+	Consul                           Consul            `yaml:"consul,omitempty" mapstructure:"consul"`
+	Telemetry                        Telemetry         `yaml:"telemetry,omitempty" mapstructure:"telemetry"`
+	LocationsFilePath                string            `yaml:"locations_file_path,omitempty" mapstructure:"locations_file_path"`
+	Vault                            DynamicMap        `yaml:"vault,omitempty" mapstructure:"vault"`
+	WfStepGracefulTerminationTimeout time.Duration     `yaml:"wf_step_graceful_termination_timeout,omitempty" mapstructure:"wf_step_graceful_termination_timeout"`
+	PurgedDeploymentsEvictionTimeout time.Duration     `yaml:"purged_deployments_eviction_timeout,omitempty" mapstructure:"purged_deployments_eviction_timeout"`
+	DeploymentsExpiry                DeploymentsExpiry `yaml:"deployments_expiry,omitempty" mapstructure:"deployments_expiry"`
+	ServerID                         string            `yaml:"server_id,omitempty" mapstructure:"server_id"`
+	Terraform                        Terraform         `yaml:"terraform,omitempty" mapstructure:"terraform"`
+	DisableSSHAgent                  bool              `yaml:"disable_ssh_agent,omitempty" mapstructure:"disable_ssh_agent"`
+	Tasks                            Tasks             `yaml:"tasks,omitempty" mapstructure:"tasks"`
+	Storage                          Storage           `yaml:"storage,omitempty" mapstructure:"storage"`
+	UpgradeConcurrencyLimit          int               `yaml:"concurrency_limit_for_upgrades,omitempty" mapstructure:"concurrency_limit_for_upgrades"`
+	SSHConnectionTimeout             time.Duration     `yaml:"ssh_connection_timeout,omitempty" mapstructure:"ssh_connection_timeout"`
+	SSHConnectionRetryBackoff        time.Duration     `yaml:"ssh_connection_retry_backoff,omitempty" mapstructure:"ssh_connection_retry_backoff"`
+	SSHConnectionMaxRetries          uint64            `yaml:"ssh_connection_max_retries,omitempty" mapstructure:"ssh_connection_max_retries"`
 }
 
 // DockerSandbox holds the configuration for a docker sandbox
@@ -178,12 +192,13 @@ type Consul struct {
 
 // Telemetry holds the configuration for the telemetry service
 type Telemetry struct {
-	StatsdAddress           string `yaml:"statsd_address,omitempty" mapstructure:"statsd_address"`
-	StatsiteAddress         string `yaml:"statsite_address,omitempty" mapstructure:"statsite_address"`
-	PrometheusEndpoint      bool   `yaml:"expose_prometheus_endpoint,omitempty" mapstructure:"expose_prometheus_endpoint"`
-	ServiceName             string `yaml:"service_name,omitempty" mapstructure:"service_name"`
-	DisableHostName         bool   `yaml:"disable_hostname,omitempty" mapstructure:"disable_hostname"`
-	DisableGoRuntimeMetrics bool   `yaml:"disable_go_runtime_metrics,omitempty" mapstructure:"disable_go_runtime_metrics"`
+	StatsdAddress           string   `yaml:"statsd_address,omitempty" mapstructure:"statsd_address"`
+	StatsiteAddress         string   `yaml:"statsite_address,omitempty" mapstructure:"statsite_address"`
+	PrometheusEndpoint      bool     `yaml:"expose_prometheus_endpoint,omitempty" mapstructure:"expose_prometheus_endpoint"`
+	ServiceName             string   `yaml:"service_name,omitempty" mapstructure:"service_name"`
+	DisableHostName         bool     `yaml:"disable_hostname,omitempty" mapstructure:"disable_hostname"`
+	DisableGoRuntimeMetrics bool     `yaml:"disable_go_runtime_metrics,omitempty" mapstructure:"disable_go_runtime_metrics"`
+	DeploymentLabels        []string `yaml:"deployment_labels,omitempty" mapstructure:"deployment_labels"`
 }
 
 // Terraform configuration
@@ -198,7 +213,8 @@ type Terraform struct {
 
 // Tasks processing configuration
 type Tasks struct {
-	Dispatcher Dispatcher `yaml:"dispatcher,omitempty" mapstructure:"dispatcher" json:"dispatcher,omitempty"`
+	Dispatcher Dispatcher    `yaml:"dispatcher,omitempty" mapstructure:"dispatcher" json:"dispatcher,omitempty"`
+	Approval   TasksApproval `yaml:"approval,omitempty" mapstructure:"approval" json:"approval,omitempty"`
 }
 
 // Dispatcher configuration
@@ -207,6 +223,34 @@ type Dispatcher struct {
 	LockWaitTime     time.Duration `yaml:"lock_wait_time,omitempty" mapstructure:"lock_wait_time" json:"lock_wait_time,omitempty"`
 }
 
+// TasksApproval configures the tasks that should be approved before being executed
+type TasksApproval struct {
+	// Timeout is the delay after which a task not approved is canceled, it could be overridden by policies
+	Timeout  time.Duration        `yaml:"timeout,omitempty" mapstructure:"timeout" json:"timeout,omitempty"`
+	Policies []TaskApprovalPolicy `yaml:"policies,omitempty" mapstructure:"policies" json:"policies,omitempty"`
+}
+
+// TaskApprovalPolicy defines tasks requiring approvals
+type TaskApprovalPolicy struct {
+	Name string `yaml:"name" mapstructure:"name" json:"name"`
+	// TaskTypes are the names of the types of tasks requiring approvals like Purge, UnDeploy or ScaleIn
+	TaskTypes []string `yaml:"task_types" mapstructure:"task_types" json:"task_types"`
+	// Selector is a list of labels filters that deployments labels should all match, the policy applies to all deployments if empty
+	Selector []string `yaml:"selector,omitempty" mapstructure:"selector" json:"selector,omitempty"`
+	// Approvals is the number of distinct approvers required, one approval is required if not set
+	Approvals int           `yaml:"approvals,omitempty" mapstructure:"approvals" json:"approvals,omitempty"`
+	Timeout   time.Duration `yaml:"timeout,omitempty" mapstructure:"timeout" json:"timeout,omitempty"`
+}
+
+// DeploymentsExpiry configures the expiry of deployments having a time-to-live
+type DeploymentsExpiry struct {
+	CheckInterval time.Duration `yaml:"check_interval,omitempty" mapstructure:"check_interval" json:"check_interval,omitempty"`
+	WarningDelay  time.Duration `yaml:"warning_delay,omitempty" mapstructure:"warning_delay" json:"warning_delay,omitempty"`
+	// MaxTTL is the maximum duration between now and the expiry date of a deployment, no limit is applied if zero
+	MaxTTL   time.Duration `yaml:"max_ttl,omitempty" mapstructure:"max_ttl" json:"max_ttl,omitempty"`
+	Webhooks []string      `yaml:"webhooks,omitempty" mapstructure:"webhooks" json:"webhooks,omitempty"`
+}
+
 // Storage configuration
 type Storage struct {
 	Reset             bool       `yaml:"reset,omitempty" json:"reset,omitempty" mapstructure:"reset"`
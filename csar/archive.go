@@ -0,0 +1,126 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csar
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// An Archive is an in-memory representation of a CSAR content
+type Archive struct {
+	// Files contains the files content indexed by their slash separated path relative to the CSAR root
+	Files map[string][]byte
+	// EntryDefinitions is the path of the main TOSCA definition file
+	EntryDefinitions string
+}
+
+// LoadZip loads a CSAR from the content of a zip archive
+func LoadZip(content []byte) (*Archive, error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read CSAR zip archive")
+	}
+	files := make(map[string][]byte)
+	for _, f := range zipReader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		name := path.Clean(strings.TrimPrefix(f.Name, "/"))
+		if name == ".." || strings.HasPrefix(name, "../") {
+			return nil, errors.Errorf("invalid file path %q in CSAR zip archive", f.Name)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open file %q from CSAR zip archive", f.Name)
+		}
+		files[name], err = ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read file %q from CSAR zip archive", f.Name)
+		}
+	}
+	return newArchive(files)
+}
+
+// LoadPath loads a CSAR from the filesystem
+//
+// p could be a zip archive, a directory or a single TOSCA YAML file.
+func LoadPath(p string) (*Archive, error) {
+	fileInfo, err := os.Stat(p)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load CSAR %q", p)
+	}
+	if !fileInfo.IsDir() {
+		content, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load CSAR %q", p)
+		}
+		if http.DetectContentType(content) == "application/zip" {
+			return LoadZip(content)
+		}
+		return newArchive(map[string][]byte{filepath.Base(p): content})
+	}
+
+	files := make(map[string][]byte)
+	err = filepath.Walk(p, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(p, filePath)
+		if err != nil {
+			return err
+		}
+		content, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = content
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load CSAR %q", p)
+	}
+	return newArchive(files)
+}
+
+func newArchive(files map[string][]byte) (*Archive, error) {
+	a := &Archive{Files: files}
+	for name := range files {
+		if strings.Contains(name, "/") {
+			continue
+		}
+		ext := path.Ext(name)
+		if ext != ".yml" && ext != ".yaml" {
+			continue
+		}
+		if a.EntryDefinitions != "" {
+			return nil, errors.New("one and only one YAML (.yml or .yaml) file should be present at the root of the CSAR")
+		}
+		a.EntryDefinitions = name
+	}
+	if a.EntryDefinitions == "" {
+		return nil, errors.New("one and only one YAML (.yml or .yaml) file should be present at the root of the CSAR")
+	}
+	return a, nil
+}
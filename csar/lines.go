@@ -0,0 +1,146 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csar
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const pathSeparator = "\x1f"
+
+var yamlKeyRegexp = regexp.MustCompile(`^("[^"]*"|'[^']*'|[^\s#:"'{\[\-][^#]*?|-[^\s#:][^#]*?)\s*:(?:\s+(.*))?$`)
+
+// lineIndex maps YAML paths to the line where they are defined in a document
+//
+// gopkg.in/yaml.v2 does not expose nodes positions so this index relies on the
+// YAML indentation to rebuild paths. Paths are made of mapping keys and of
+// sequence indexes represented as "[i]".
+type lineIndex map[string]int
+
+type lineFrame struct {
+	indent  int
+	path    []string
+	isItem  bool
+	seqSize int
+}
+
+func joinPath(path []string) string {
+	return strings.Join(path, pathSeparator)
+}
+
+// subPath returns a new path made of parent followed by elems
+func subPath(parent []string, elems ...string) []string {
+	p := make([]string, len(parent), len(parent)+len(elems))
+	copy(p, parent)
+	return append(p, elems...)
+}
+
+func indexElem(i int) string {
+	return fmt.Sprintf("[%d]", i)
+}
+
+func newLineIndex(content []byte) lineIndex {
+	idx := make(lineIndex)
+	root := &lineFrame{indent: -1}
+	stack := []*lineFrame{root}
+	blockIndent := -1
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNb := 0
+	for scanner.Scan() {
+		lineNb++
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmed := strings.TrimLeft(line, " ")
+		indent := len(line) - len(trimmed)
+		if blockIndent >= 0 {
+			if trimmed == "" || indent > blockIndent {
+				// Within a block scalar
+				continue
+			}
+			blockIndent = -1
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" || trimmed == "..." {
+			continue
+		}
+
+		for strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			// Sequence item
+			for len(stack) > 1 {
+				top := stack[len(stack)-1]
+				if top.indent > indent || (top.indent == indent && top.isItem) {
+					stack = stack[:len(stack)-1]
+					continue
+				}
+				break
+			}
+			parent := stack[len(stack)-1]
+			itemPath := subPath(parent.path, indexElem(parent.seqSize))
+			parent.seqSize++
+			idx.add(itemPath, lineNb)
+			stack = append(stack, &lineFrame{indent: indent, path: itemPath, isItem: true})
+			if trimmed == "-" {
+				trimmed = ""
+				break
+			}
+			rest := strings.TrimLeft(trimmed[1:], " ")
+			indent += len(trimmed) - len(rest)
+			trimmed = rest
+		}
+		if trimmed == "" {
+			continue
+		}
+
+		m := yamlKeyRegexp.FindStringSubmatch(trimmed)
+		if m == nil {
+			continue
+		}
+		for len(stack) > 1 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1]
+		key := strings.Trim(m[1], `"'`)
+		keyPath := subPath(parent.path, key)
+		idx.add(keyPath, lineNb)
+		stack = append(stack, &lineFrame{indent: indent, path: keyPath})
+		value := strings.TrimSpace(m[2])
+		if strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">") {
+			blockIndent = indent
+		}
+	}
+	return idx
+}
+
+func (idx lineIndex) add(path []string, line int) {
+	p := joinPath(path)
+	if _, ok := idx[p]; !ok {
+		idx[p] = line
+	}
+}
+
+// line returns the line of the given path or of its closest defined ancestor.
+//
+// 0 is returned if no ancestor is defined.
+func (idx lineIndex) line(path ...string) int {
+	for i := len(path); i > 0; i-- {
+		if l, ok := idx[joinPath(path[:i])]; ok {
+			return l
+		}
+	}
+	return 0
+}
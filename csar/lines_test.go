@@ -0,0 +1,69 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineIndex(t *testing.T) {
+	content := []byte(`tosca_definitions_version: alien_dsl_2_0_0
+# A comment
+imports:
+  - <normative-types.yml>
+  - file: types.yml
+
+node_types:
+  yorc.nodes.Test:
+    description: |
+      A description with a fake key:
+        fake: value
+    properties:
+      port: {type: integer}
+topology_template:
+  node_templates:
+    Test:
+      type: yorc.nodes.Test
+      requirements:
+      - host:
+          node: Compute
+      - network:
+          node: Net
+`)
+	idx := newLineIndex(content)
+	tests := []struct {
+		name string
+		path []string
+		want int
+	}{
+		{"TopLevelKey", []string{"tosca_definitions_version"}, 1},
+		{"SequenceItem", []string{"imports", "[0]"}, 4},
+		{"SequenceItemMap", []string{"imports", "[1]", "file"}, 5},
+		{"KeyWithDots", []string{"node_types", "yorc.nodes.Test"}, 8},
+		{"BlockScalarSkipped", []string{"node_types", "yorc.nodes.Test", "description", "fake"}, 9},
+		{"AfterBlockScalar", []string{"node_types", "yorc.nodes.Test", "properties", "port"}, 13},
+		{"FlowMappingFallback", []string{"node_types", "yorc.nodes.Test", "properties", "port", "type"}, 13},
+		{"CompactSequence", []string{"topology_template", "node_templates", "Test", "requirements", "[1]", "network"}, 21},
+		{"CompactSequenceNested", []string{"topology_template", "node_templates", "Test", "requirements", "[0]", "host", "node"}, 20},
+		{"Unknown", []string{"unknown"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, idx.line(tt.path...))
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csar
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity of a validation Error
+type Severity string
+
+const (
+	// SeverityError is used for errors that prevent a CSAR to be deployed properly
+	SeverityError Severity = "error"
+	// SeverityWarning is used for deviations from the TOSCA specification that Yorc tolerates
+	SeverityWarning Severity = "warning"
+)
+
+// An Error is a validation error located into a file of a CSAR
+type Error struct {
+	Severity Severity `json:"severity"`
+	// File is the path of the file within the CSAR
+	File string `json:"file"`
+	// Line is the line in File where the error is located, 0 if unknown
+	Line int `json:"line,omitempty"`
+	// Path is the dot separated path of the faulty element within File
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e Error) Error() string {
+	var b strings.Builder
+	b.WriteString(e.File)
+	if e.Line > 0 {
+		fmt.Fprintf(&b, ":%d", e.Line)
+	}
+	b.WriteString(": ")
+	if e.Severity == SeverityWarning {
+		b.WriteString("warning: ")
+	}
+	if e.Path != "" {
+		fmt.Fprintf(&b, "%s: ", e.Path)
+	}
+	b.WriteString(e.Message)
+	return b.String()
+}
+
+// A Report is the result of a CSAR validation
+//
+// A CSAR is valid if it has no error of severity SeverityError.
+type Report struct {
+	Valid  bool    `json:"valid"`
+	Errors []Error `json:"errors"`
+}
+
+func (r *Report) addError(severity Severity, file string, line int, path []string, format string, args ...interface{}) {
+	r.Errors = append(r.Errors, Error{
+		Severity: severity,
+		File:     file,
+		Line:     line,
+		Path:     readablePath(path),
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// sort orders errors by file, line and message and computes the Valid flag
+func (r *Report) sort() {
+	if r.Errors == nil {
+		r.Errors = make([]Error, 0)
+	}
+	sort.SliceStable(r.Errors, func(i, j int) bool {
+		if r.Errors[i].File != r.Errors[j].File {
+			return r.Errors[i].File < r.Errors[j].File
+		}
+		if r.Errors[i].Line != r.Errors[j].Line {
+			return r.Errors[i].Line < r.Errors[j].Line
+		}
+		return r.Errors[i].Message < r.Errors[j].Message
+	})
+	r.Valid = true
+	for _, e := range r.Errors {
+		if e.Severity == SeverityError {
+			r.Valid = false
+		}
+	}
+}
+
+func readablePath(path []string) string {
+	var b strings.Builder
+	for i, p := range path {
+		if i > 0 && !strings.HasPrefix(p, "[") {
+			b.WriteString(".")
+		}
+		b.WriteString(p)
+	}
+	return b.String()
+}
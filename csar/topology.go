@@ -0,0 +1,295 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csar
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+func (v *validator) checkTopologyTemplate(doc *document, isEntry bool) {
+	tt := doc.topology.TopologyTemplate
+	if !isEntry && len(tt.NodeTemplates) == 0 && len(tt.Inputs) == 0 && len(tt.Outputs) == 0 {
+		return
+	}
+	v.template = &tt
+	defer func() { v.template = nil }()
+	p := []string{"topology_template"}
+
+	for name, input := range tt.Inputs {
+		inputPath := subPath(p, "inputs", name)
+		if input.Type != "" && v.checkValueTypeDefinition(doc, inputPath, input.Type, input.EntrySchema.Type) {
+			v.checkValueAssignment(doc, subPath(inputPath, "default"), input.Type, input.EntrySchema.Type, input.Default, "")
+		}
+	}
+
+	for name, nodeTemplate := range tt.NodeTemplates {
+		v.checkNodeTemplate(doc, subPath(p, "node_templates", name), name, nodeTemplate)
+	}
+
+	for name, output := range tt.Outputs {
+		v.checkValueAssignment(doc, subPath(p, "outputs", name, "value"), output.Type, output.EntrySchema.Type, output.Value, "")
+	}
+
+	if sm := tt.SubstitionMappings; sm != nil {
+		if _, ok := v.types.nodes[sm.NodeType]; !ok {
+			v.errorf(doc, subPath(p, "substitution_mappings", "node_type"), "unknown node type %q", sm.NodeType)
+		}
+	}
+
+	for i, policies := range tt.Policies {
+		for name, policy := range policies {
+			v.checkPolicy(doc, subPath(p, "policies", indexElem(i), name), policy)
+		}
+	}
+
+	v.checkWorkflows(doc, subPath(p, "workflows"), tt.Workflows)
+}
+
+func (v *validator) checkNodeTemplate(doc *document, p []string, name string, nodeTemplate tosca.NodeTemplate) {
+	if _, ok := v.types.nodes[nodeTemplate.Type]; !ok {
+		v.errorf(doc, subPath(p, "type"), "unknown node type %q", nodeTemplate.Type)
+		return
+	}
+	v.checkProperties(doc, subPath(p, "properties"), "node type", nodeTemplate.Type,
+		v.types.nodeProperties(nodeTemplate.Type), nodeTemplate.Properties, name)
+
+	for attrName, attr := range nodeTemplate.Attributes {
+		if attr != nil && attr.Type == tosca.ValueAssignmentFunction {
+			v.checkValueAssignment(doc, subPath(p, "attributes", attrName), "", "", attr, name)
+		}
+	}
+
+	capDefs := v.types.nodeCapabilities(nodeTemplate.Type)
+	for capName, capAssignment := range nodeTemplate.Capabilities {
+		capPath := subPath(p, "capabilities", capName)
+		capDef, ok := capDefs[capName]
+		if !ok {
+			v.errorf(doc, capPath, "unknown capability %q for node type %q", capName, nodeTemplate.Type)
+			continue
+		}
+		v.checkProperties(doc, subPath(capPath, "properties"), "capability type", capDef.Type,
+			v.types.capabilityProperties(capDef.Type), capAssignment.Properties, name)
+	}
+
+	reqDefs := v.types.nodeRequirements(nodeTemplate.Type)
+	for i, reqMap := range nodeTemplate.Requirements {
+		for reqName, req := range reqMap {
+			reqPath := subPath(p, "requirements", indexElem(i), reqName)
+			defName := reqName
+			if req.TypeRequirement != "" {
+				defName = req.TypeRequirement
+			}
+			reqDef, ok := reqDefs[defName]
+			if !ok {
+				// Yorc stores requirements not defined by the node type
+				v.warnf(doc, reqPath, "unknown requirement %q for node type %q", defName, nodeTemplate.Type)
+			}
+			if req.Node != "" {
+				target, ok := v.template.NodeTemplates[req.Node]
+				if !ok {
+					v.errorf(doc, reqPath, "requirement %q targets unknown node template %q", reqName, req.Node)
+				} else if _, isCapType := v.types.capabilities[reqDef.Capability]; isCapType && v.isKnownNodeType(target.Type) &&
+					!v.types.hasCapabilityOfType(target.Type, reqDef.Capability) {
+					v.errorf(doc, reqPath, "requirement %q expects a %q capability, node template %q of type %q does not provide it",
+						reqName, reqDef.Capability, req.Node, target.Type)
+				}
+			}
+			if req.Relationship != "" {
+				if _, ok := v.types.relationships[req.Relationship]; !ok {
+					v.errorf(doc, reqPath, "unknown relationship type %q", req.Relationship)
+				}
+			}
+			for propName, prop := range req.RelationshipProps {
+				v.checkValueAssignment(doc, subPath(reqPath, "relationship", "properties", propName), "", "", prop, "")
+			}
+		}
+	}
+
+	v.checkArtifacts(doc, subPath(p, "artifacts"), nodeTemplate.Artifacts)
+}
+
+// checkProperties checks property assignments against property definitions of a given type
+func (v *validator) checkProperties(doc *document, p []string, kind, typeName string, defs map[string]tosca.PropertyDefinition, props map[string]*tosca.ValueAssignment, self string) {
+	for propName, prop := range props {
+		propDef, ok := defs[propName]
+		if !ok {
+			v.errorf(doc, subPath(p, propName), "unknown property %q for %s %q", propName, kind, typeName)
+			continue
+		}
+		v.checkValueAssignment(doc, subPath(p, propName), propDef.Type, propDef.EntrySchema.Type, prop, self)
+	}
+	missing := make([]string, 0)
+	for propName, propDef := range defs {
+		if _, ok := props[propName]; !ok && isRequired(propDef) {
+			missing = append(missing, propName)
+		}
+	}
+	sort.Strings(missing)
+	for _, propName := range missing {
+		v.warnf(doc, p, "required property %q of %s %q is missing", propName, kind, typeName)
+	}
+}
+
+func (v *validator) checkPolicy(doc *document, p []string, policy tosca.Policy) {
+	if _, ok := v.types.policies[policy.Type]; !ok {
+		v.errorf(doc, subPath(p, "type"), "unknown policy type %q", policy.Type)
+		return
+	}
+	for _, target := range policy.Targets {
+		if _, ok := v.template.NodeTemplates[target]; !ok {
+			v.errorf(doc, subPath(p, "targets"), "policy targets unknown node template %q", target)
+		}
+	}
+	v.checkProperties(doc, subPath(p, "properties"), "policy type", policy.Type,
+		v.types.policyProperties(policy.Type), policy.Properties, "")
+}
+
+func (v *validator) checkWorkflows(doc *document, p []string, workflows map[string]tosca.Workflow) {
+	inlined := make(map[string][]string)
+	for wfName, wf := range workflows {
+		wfPath := subPath(p, wfName)
+		for stepName, step := range wf.Steps {
+			if step == nil {
+				continue
+			}
+			stepPath := subPath(wfPath, "steps", stepName)
+			if step.Target != "" && step.TargetRelationShip == "" {
+				if _, ok := v.template.NodeTemplates[step.Target]; !ok {
+					v.errorf(doc, subPath(stepPath, "target"), "step targets unknown node template %q", step.Target)
+				}
+			}
+			for i, activity := range step.Activities {
+				actPath := subPath(stepPath, "activities", indexElem(i))
+				if step.Target == "" && (activity.SetState != "" || activity.CallOperation != nil || activity.Delegate != nil) {
+					v.errorf(doc, actPath, "step %q has no target", stepName)
+				}
+				if activity.CallOperation != nil && !strings.Contains(activity.CallOperation.Operation, ".") {
+					v.errorf(doc, actPath, "operation %q should be in the <interface_name>.<operation_name> format", activity.CallOperation.Operation)
+				}
+				if activity.Inline != nil {
+					if _, ok := workflows[activity.Inline.Workflow]; !ok {
+						v.errorf(doc, actPath, "inline activity references unknown workflow %q", activity.Inline.Workflow)
+					} else {
+						inlined[wfName] = append(inlined[wfName], activity.Inline.Workflow)
+					}
+				}
+				if activity.WaitForSignal != nil && len(step.Activities) > 1 {
+					v.errorf(doc, actPath, "wait_for_signal activity should be the only activity of step %q", stepName)
+				}
+			}
+			for _, link := range []struct {
+				name  string
+				steps []string
+			}{{"on_success", step.OnSuccess}, {"on_failure", step.OnFailure}, {"on_cancel", step.OnCancel}} {
+				for _, next := range link.steps {
+					if _, ok := wf.Steps[next]; !ok {
+						v.errorf(doc, subPath(stepPath, link.name), "step %q references unknown step %q", stepName, next)
+					}
+				}
+			}
+		}
+		if cycle := findCycle(wf.Steps); cycle != nil {
+			v.errorf(doc, subPath(wfPath, "steps", cycle[0]), "cycle detected between steps: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	wfNames := make([]string, 0, len(workflows))
+	for wfName := range workflows {
+		wfNames = append(wfNames, wfName)
+	}
+	sort.Strings(wfNames)
+	for _, wfName := range wfNames {
+		if cycle := findInlineCycle(wfName, inlined, nil); cycle != nil {
+			v.errorf(doc, subPath(p, wfName), "cycle detected in nested inline workflows: %s", strings.Join(cycle, " -> "))
+			// Report a given cycle only once
+			for _, wf := range cycle {
+				delete(inlined, wf)
+			}
+		}
+	}
+}
+
+// findCycle returns a cycle in the on_success graph of workflow steps if any
+func findCycle(steps map[string]*tosca.Step) []string {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make(map[string]int)
+	var stack []string
+	var visit func(string) []string
+	visit = func(name string) []string {
+		state[name] = inProgress
+		stack = append(stack, name)
+		if step := steps[name]; step != nil {
+			for _, next := range step.OnSuccess {
+				if _, ok := steps[next]; !ok {
+					continue
+				}
+				switch state[next] {
+				case inProgress:
+					for i, s := range stack {
+						if s == next {
+							return append(append([]string{}, stack[i:]...), next)
+						}
+					}
+				case unvisited:
+					if c := visit(next); c != nil {
+						return c
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = done
+		return nil
+	}
+	names := make([]string, 0, len(steps))
+	for name := range steps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if state[name] == unvisited {
+			if c := visit(name); c != nil {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+func findInlineCycle(wfName string, inlined map[string][]string, path []string) []string {
+	for i, wf := range path {
+		if wf == wfName {
+			return append(append([]string{}, path[i:]...), wfName)
+		}
+	}
+	path = append(path, wfName)
+	for _, next := range inlined[wfName] {
+		if c := findInlineCycle(next, inlined, path); c != nil {
+			return c
+		}
+	}
+	return nil
+}
+
+func (v *validator) isKnownNodeType(name string) bool {
+	_, ok := v.types.nodes[name]
+	return ok
+}
@@ -0,0 +1,312 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csar
+
+import (
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+// hierarchy returns the given type followed by its parents
+//
+// It stops at the first unknown type or when a cycle is detected.
+func hierarchy(name string, parent func(string) (string, bool)) []string {
+	result := make([]string, 0)
+	visited := make(map[string]bool)
+	for name != "" && !visited[name] {
+		p, ok := parent(name)
+		if !ok {
+			break
+		}
+		visited[name] = true
+		result = append(result, name)
+		name = p
+	}
+	return result
+}
+
+func (t *types) nodeParent(name string) (string, bool) {
+	n, ok := t.nodes[name]
+	return n.DerivedFrom, ok
+}
+
+func (t *types) capabilityParent(name string) (string, bool) {
+	c, ok := t.capabilities[name]
+	return c.DerivedFrom, ok
+}
+
+func (t *types) relationshipParent(name string) (string, bool) {
+	r, ok := t.relationships[name]
+	return r.DerivedFrom, ok
+}
+
+func (t *types) dataParent(name string) (string, bool) {
+	d, ok := t.data[name]
+	return d.DerivedFrom, ok
+}
+
+func (t *types) policyParent(name string) (string, bool) {
+	p, ok := t.policies[name]
+	return p.DerivedFrom, ok
+}
+
+func (t *types) artifactParent(name string) (string, bool) {
+	a, ok := t.artifacts[name]
+	return a.DerivedFrom, ok
+}
+
+// hasCapabilityOfType checks if a node type provides a capability of the given
+// capability type or of one of its derived types
+func (t *types) hasCapabilityOfType(nodeType, capabilityType string) bool {
+	for _, capDef := range t.nodeCapabilities(nodeType) {
+		for _, c := range hierarchy(capDef.Type, t.capabilityParent) {
+			if c == capabilityType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (t *types) nodeProperties(name string) map[string]tosca.PropertyDefinition {
+	result := make(map[string]tosca.PropertyDefinition)
+	h := hierarchy(name, t.nodeParent)
+	for i := len(h) - 1; i >= 0; i-- {
+		for k, p := range t.nodes[h[i]].Properties {
+			result[k] = p
+		}
+	}
+	return result
+}
+
+func (t *types) nodeCapabilities(name string) map[string]tosca.CapabilityDefinition {
+	result := make(map[string]tosca.CapabilityDefinition)
+	h := hierarchy(name, t.nodeParent)
+	for i := len(h) - 1; i >= 0; i-- {
+		for k, c := range t.nodes[h[i]].Capabilities {
+			result[k] = c
+		}
+	}
+	return result
+}
+
+func (t *types) nodeRequirements(name string) map[string]tosca.RequirementDefinition {
+	result := make(map[string]tosca.RequirementDefinition)
+	h := hierarchy(name, t.nodeParent)
+	for i := len(h) - 1; i >= 0; i-- {
+		for _, reqMap := range t.nodes[h[i]].Requirements {
+			for k, r := range reqMap {
+				result[k] = r
+			}
+		}
+	}
+	return result
+}
+
+func (t *types) capabilityProperties(name string) map[string]tosca.PropertyDefinition {
+	result := make(map[string]tosca.PropertyDefinition)
+	h := hierarchy(name, t.capabilityParent)
+	for i := len(h) - 1; i >= 0; i-- {
+		for k, p := range t.capabilities[h[i]].Properties {
+			result[k] = p
+		}
+	}
+	return result
+}
+
+func (t *types) dataProperties(name string) map[string]tosca.PropertyDefinition {
+	result := make(map[string]tosca.PropertyDefinition)
+	h := hierarchy(name, t.dataParent)
+	for i := len(h) - 1; i >= 0; i-- {
+		for k, p := range t.data[h[i]].Properties {
+			result[k] = p
+		}
+	}
+	return result
+}
+
+func (t *types) policyProperties(name string) map[string]tosca.PropertyDefinition {
+	result := make(map[string]tosca.PropertyDefinition)
+	h := hierarchy(name, t.policyParent)
+	for i := len(h) - 1; i >= 0; i-- {
+		for k, p := range t.policies[h[i]].Properties {
+			result[k] = p
+		}
+	}
+	return result
+}
+
+// valueType returns the builtin type a value type is based on.
+//
+// An empty string is returned for complex data types.
+func (t *types) valueType(name string) string {
+	if tosca.IsBuiltinType(name) {
+		return name
+	}
+	for _, d := range hierarchy(name, t.dataParent) {
+		p := t.data[d].DerivedFrom
+		if tosca.IsBuiltinType(p) {
+			return p
+		}
+	}
+	return ""
+}
+
+func (t *types) isValueType(name string) bool {
+	if tosca.IsBuiltinType(name) {
+		return true
+	}
+	_, ok := t.data[name]
+	return ok
+}
+
+// checkTypes checks the types defined in a CSAR document
+func (v *validator) checkTypes(doc *document) {
+	topology := doc.topology
+	for name, nodeType := range topology.NodeTypes {
+		p := []string{"node_types", name}
+		v.checkDerivedFrom(doc, p, "node", name, nodeType.DerivedFrom, v.types.nodeParent)
+		v.checkPropertyDefinitions(doc, subPath(p, "properties"), nodeType.Properties)
+		v.checkAttributeDefinitions(doc, subPath(p, "attributes"), nodeType.Attributes)
+		for capName, capDef := range nodeType.Capabilities {
+			if _, ok := v.types.capabilities[capDef.Type]; !ok {
+				v.errorf(doc, subPath(subPath(p, "capabilities"), capName), "unknown capability type %q", capDef.Type)
+			}
+		}
+		for i, reqMap := range nodeType.Requirements {
+			for reqName, reqDef := range reqMap {
+				reqPath := subPath(p, "requirements", indexElem(i), reqName)
+				if _, ok := v.types.capabilities[reqDef.Capability]; !ok && reqDef.Capability != "" {
+					v.errorf(doc, reqPath, "unknown capability type %q", reqDef.Capability)
+				}
+				if _, ok := v.types.nodes[reqDef.Node]; !ok && reqDef.Node != "" {
+					v.errorf(doc, reqPath, "unknown node type %q", reqDef.Node)
+				}
+				if _, ok := v.types.relationships[reqDef.Relationship]; !ok && reqDef.Relationship != "" {
+					v.errorf(doc, reqPath, "unknown relationship type %q", reqDef.Relationship)
+				}
+			}
+		}
+		v.checkArtifacts(doc, subPath(p, "artifacts"), nodeType.Artifacts)
+	}
+	for name, relType := range topology.RelationshipTypes {
+		p := []string{"relationship_types", name}
+		v.checkDerivedFrom(doc, p, "relationship", name, relType.DerivedFrom, v.types.relationshipParent)
+		v.checkPropertyDefinitions(doc, subPath(p, "properties"), relType.Properties)
+		v.checkAttributeDefinitions(doc, subPath(p, "attributes"), relType.Attributes)
+		for _, target := range relType.ValidTargetTypes {
+			_, isCap := v.types.capabilities[target]
+			_, isNode := v.types.nodes[target]
+			if !isCap && !isNode {
+				v.errorf(doc, subPath(p, "valid_target_types"), "unknown capability or node type %q", target)
+			}
+		}
+		v.checkArtifacts(doc, subPath(p, "artifacts"), relType.Artifacts)
+	}
+	for name, capType := range topology.CapabilityTypes {
+		p := []string{"capability_types", name}
+		v.checkDerivedFrom(doc, p, "capability", name, capType.DerivedFrom, v.types.capabilityParent)
+		v.checkPropertyDefinitions(doc, subPath(p, "properties"), capType.Properties)
+		v.checkAttributeDefinitions(doc, subPath(p, "attributes"), capType.Attributes)
+	}
+	for name, dataType := range topology.DataTypes {
+		p := []string{"data_types", name}
+		if !tosca.IsBuiltinType(dataType.DerivedFrom) {
+			v.checkDerivedFrom(doc, p, "data", name, dataType.DerivedFrom, v.types.dataParent)
+		}
+		v.checkPropertyDefinitions(doc, subPath(p, "properties"), dataType.Properties)
+	}
+	for name, policyType := range topology.PolicyTypes {
+		p := []string{"policy_types", name}
+		v.checkDerivedFrom(doc, p, "policy", name, policyType.DerivedFrom, v.types.policyParent)
+		v.checkPropertyDefinitions(doc, subPath(p, "properties"), policyType.Properties)
+		for _, target := range policyType.Targets {
+			if _, ok := v.types.nodes[target]; !ok {
+				v.errorf(doc, subPath(p, "targets"), "unknown node type %q", target)
+			}
+		}
+	}
+	for name, artifactType := range topology.ArtifactTypes {
+		p := []string{"artifact_types", name}
+		v.checkDerivedFrom(doc, p, "artifact", name, artifactType.DerivedFrom, v.types.artifactParent)
+		v.checkPropertyDefinitions(doc, subPath(p, "properties"), artifactType.Properties)
+	}
+}
+
+func (v *validator) checkDerivedFrom(doc *document, p []string, kind, name, derivedFrom string, parent func(string) (string, bool)) {
+	if derivedFrom == "" {
+		return
+	}
+	if _, ok := parent(derivedFrom); !ok {
+		v.errorf(doc, subPath(p, "derived_from"), "unknown %s type %q", kind, derivedFrom)
+		return
+	}
+	for _, t := range hierarchy(derivedFrom, parent) {
+		if t == name {
+			v.errorf(doc, subPath(p, "derived_from"), "%s type %q derives from itself", kind, name)
+			return
+		}
+	}
+}
+
+func (v *validator) checkPropertyDefinitions(doc *document, p []string, props map[string]tosca.PropertyDefinition) {
+	for name, prop := range props {
+		propPath := subPath(p, name)
+		if !v.checkValueTypeDefinition(doc, propPath, prop.Type, prop.EntrySchema.Type) {
+			continue
+		}
+		v.checkValueAssignment(doc, subPath(propPath, "default"), prop.Type, prop.EntrySchema.Type, prop.Default, "")
+	}
+}
+
+func (v *validator) checkAttributeDefinitions(doc *document, p []string, attrs map[string]tosca.AttributeDefinition) {
+	for name, attr := range attrs {
+		if attr.Default != nil && attr.Type == attr.Default.Type.String() {
+			// Short notation where the attribute is defined by its value
+			continue
+		}
+		if attr.Type != "" {
+			v.checkValueTypeDefinition(doc, subPath(p, name), attr.Type, attr.EntrySchema.Type)
+		}
+	}
+}
+
+// checkValueTypeDefinition checks that a property, attribute or parameter type exists
+func (v *validator) checkValueTypeDefinition(doc *document, p []string, typeName, entrySchema string) bool {
+	if typeName == "" {
+		v.errorf(doc, p, "type is missing")
+		return false
+	}
+	if !v.types.isValueType(typeName) {
+		v.errorf(doc, subPath(p, "type"), "unknown data type %q", typeName)
+		return false
+	}
+	if entrySchema != "" && !v.types.isValueType(entrySchema) {
+		v.errorf(doc, subPath(p, "entry_schema"), "unknown data type %q", entrySchema)
+		return false
+	}
+	return true
+}
+
+func (v *validator) checkArtifacts(doc *document, p []string, artifacts tosca.ArtifactDefMap) {
+	for name, artifact := range artifacts {
+		artPath := subPath(p, name)
+		if _, ok := v.types.artifacts[artifact.Type]; !ok && artifact.Type != "" {
+			v.errorf(doc, artPath, "unknown artifact type %q", artifact.Type)
+		}
+		if artifact.Repository == "" && artifact.File != "" && !v.fileExists(doc, artifact.File) {
+			v.errorf(doc, artPath, "artifact file %q not found in CSAR", artifact.File)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csar
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+var yamlErrorLineRegexp = regexp.MustCompile(`line (\d+):`)
+
+type document struct {
+	file     string
+	topology tosca.Topology
+	lines    lineIndex
+}
+
+// types gathers all TOSCA types known during a validation
+type types struct {
+	nodes         map[string]tosca.NodeType
+	capabilities  map[string]tosca.CapabilityType
+	relationships map[string]tosca.RelationshipType
+	data          map[string]tosca.DataType
+	policies      map[string]tosca.PolicyType
+	artifacts     map[string]tosca.ArtifactType
+}
+
+func newTypes() *types {
+	return &types{
+		nodes:         make(map[string]tosca.NodeType),
+		capabilities:  make(map[string]tosca.CapabilityType),
+		relationships: make(map[string]tosca.RelationshipType),
+		data:          make(map[string]tosca.DataType),
+		policies:      make(map[string]tosca.PolicyType),
+		artifacts:     make(map[string]tosca.ArtifactType),
+	}
+}
+
+func (t *types) add(topology tosca.Topology) {
+	for k, v := range topology.NodeTypes {
+		t.nodes[k] = v
+	}
+	for k, v := range topology.CapabilityTypes {
+		t.capabilities[k] = v
+	}
+	for k, v := range topology.RelationshipTypes {
+		t.relationships[k] = v
+	}
+	for k, v := range topology.DataTypes {
+		t.data[k] = v
+	}
+	for k, v := range topology.PolicyTypes {
+		t.policies[k] = v
+	}
+	for k, v := range topology.ArtifactTypes {
+		t.artifacts[k] = v
+	}
+}
+
+type validator struct {
+	archive     *Archive
+	definitions map[string][]byte
+	report      *Report
+	documents   []*document
+	loaded      map[string]bool
+	types       *types
+	// template is the topology template being checked
+	template *tosca.TopologyTemplate
+}
+
+// Validate checks a CSAR and reports all detected errors
+//
+// definitions are the TOSCA definitions known by Yorc (builtin and plugins ones) indexed by their names.
+// They could be imported using the <definition_name> syntax and as in Yorc their types are always available.
+func Validate(archive *Archive, definitions map[string][]byte) *Report {
+	v := &validator{
+		archive:     archive,
+		definitions: definitions,
+		report:      &Report{},
+		loaded:      make(map[string]bool),
+		types:       newTypes(),
+	}
+	// Sort definitions to get a deterministic override order
+	defNames := make([]string, 0, len(definitions))
+	for name := range definitions {
+		defNames = append(defNames, name)
+	}
+	sort.Strings(defNames)
+	for _, name := range defNames {
+		var topology tosca.Topology
+		if err := yaml.Unmarshal(definitions[name], &topology); err == nil {
+			v.types.add(topology)
+		}
+	}
+
+	v.loadDocument(archive.EntryDefinitions)
+	for _, doc := range v.documents {
+		v.types.add(doc.topology)
+	}
+	for _, doc := range v.documents {
+		v.checkTypes(doc)
+		v.checkTopologyTemplate(doc, doc.file == archive.EntryDefinitions)
+	}
+	v.report.sort()
+	return v.report
+}
+
+func (v *validator) loadDocument(file string) {
+	v.loaded[file] = true
+	content := v.archive.Files[file]
+	doc := &document{file: file, lines: newLineIndex(content)}
+	if err := yaml.Unmarshal(content, &doc.topology); err != nil {
+		line := 0
+		if m := yamlErrorLineRegexp.FindStringSubmatch(err.Error()); m != nil {
+			line, _ = strconv.Atoi(m[1])
+		}
+		v.report.addError(SeverityError, file, line, nil, "failed to parse TOSCA definition: %s", strings.TrimPrefix(err.Error(), "yaml: "))
+		return
+	}
+	v.documents = append(v.documents, doc)
+	if doc.topology.TOSCAVersion == "" {
+		v.report.addError(SeverityError, file, 1, nil, "tosca_definitions_version is missing")
+	}
+
+	for i, imp := range doc.topology.Imports {
+		importPath := []string{"imports", indexElem(i)}
+		importURI := strings.Trim(imp.File, " \t")
+		if strings.HasPrefix(importURI, "<") && strings.HasSuffix(importURI, ">") {
+			defName := strings.TrimSpace(importURI[1 : len(importURI)-1])
+			if _, ok := v.definitions[defName]; !ok {
+				v.report.addError(SeverityError, file, doc.lines.line(importPath...), importPath, "unknown builtin or plugin definition %q", defName)
+			}
+			continue
+		}
+		importedFile := path.Join(path.Dir(file), importURI)
+		if _, ok := v.archive.Files[importedFile]; !ok {
+			v.report.addError(SeverityError, file, doc.lines.line(importPath...), importPath, "imported file %q not found in CSAR", importURI)
+			continue
+		}
+		if !v.loaded[importedFile] {
+			v.loadDocument(importedFile)
+		}
+	}
+}
+
+func (v *validator) errorf(doc *document, path []string, format string, args ...interface{}) {
+	v.report.addError(SeverityError, doc.file, doc.lines.line(path...), path, format, args...)
+}
+
+func (v *validator) warnf(doc *document, path []string, format string, args ...interface{}) {
+	v.report.addError(SeverityWarning, doc.file, doc.lines.line(path...), path, format, args...)
+}
+
+// fileExists checks if a file referenced relatively to a document exists in the CSAR
+func (v *validator) fileExists(doc *document, file string) bool {
+	_, ok := v.archive.Files[path.Join(path.Dir(doc.file), file)]
+	return ok
+}
@@ -0,0 +1,197 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csar
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/helper/ziputil"
+)
+
+func loadBuiltinDefinitions(t *testing.T) map[string][]byte {
+	files, err := filepath.Glob("../data/tosca/*.yml")
+	require.NoError(t, err)
+	defs := make(map[string][]byte, len(files))
+	for _, f := range files {
+		content, err := ioutil.ReadFile(f)
+		require.NoError(t, err)
+		defs[filepath.Base(f)] = content
+	}
+	return defs
+}
+
+func TestValidateValidCSAR(t *testing.T) {
+	defs := loadBuiltinDefinitions(t)
+
+	archive, err := LoadPath("testdata/valid")
+	require.NoError(t, err)
+	require.Equal(t, "topology.yml", archive.EntryDefinitions)
+	report := Validate(archive, defs)
+	require.True(t, report.Valid, "unexpected errors: %v", report.Errors)
+	require.Len(t, report.Errors, 0)
+
+	// Same CSAR as a zip archive
+	content, err := ziputil.ZipPath("testdata/valid")
+	require.NoError(t, err)
+	archive, err = LoadZip(content)
+	require.NoError(t, err)
+	report = Validate(archive, defs)
+	require.True(t, report.Valid, "unexpected errors: %v", report.Errors)
+}
+
+func TestValidateInvalidCSAR(t *testing.T) {
+	archive, err := LoadPath("testdata/invalid.yml")
+	require.NoError(t, err)
+	report := Validate(archive, loadBuiltinDefinitions(t))
+	require.False(t, report.Valid)
+
+	expected := []struct {
+		line     int
+		severity Severity
+		message  string
+	}{
+		{10, SeverityError, `unknown builtin or plugin definition "unknown-types.yml"`},
+		{11, SeverityError, `imported file "missing/types.yml" not found in CSAR`},
+		{15, SeverityError, `unknown data type "yorc.tests.csar.Unknown"`},
+		{19, SeverityError, `"many" is not a valid integer value`},
+		{23, SeverityError, `node type "yorc.tests.csar.Loop" derives from itself`},
+		{30, SeverityError, `unknown data type "strin"`},
+		{32, SeverityError, `unknown capability type "tosca.capabilities.Unknown"`},
+		{34, SeverityError, `artifact file "scripts/missing.sh" not found in CSAR`},
+		{49, SeverityError, `"not_a_number" is not a valid integer value`},
+		{50, SeverityError, `unknown property "unknown_prop" for node type "yorc.tests.csar.App"`},
+		{51, SeverityError, `get_input: unknown input "unknown_input"`},
+		{53, SeverityError, `requirement "host" targets unknown node template "Missing"`},
+		{58, SeverityError, `unknown node type "yorc.tests.csar.Missing"`},
+		{59, SeverityWarning, `required property "mode" of node type "yorc.tests.csar.App" is missing`},
+		{59, SeverityWarning, `required property "port" of node type "yorc.tests.csar.App" is missing`},
+		{64, SeverityError, `get_property: node template "Compute" has no property, capability or requirement named "unknown_prop"`},
+		{65, SeverityError, `get_attribute: unknown node template "Ghost"`},
+		{68, SeverityError, `get_property: node template "App" has no property, capability or requirement named "nope"`},
+		{71, SeverityError, `unknown policy type "yorc.policies.Unknown"`},
+		{76, SeverityError, `cycle detected between steps: step_a -> step_b -> step_a`},
+		{79, SeverityError, `operation "create" should be in the <interface_name>.<operation_name> format`},
+		{83, SeverityError, `step targets unknown node template "Nowhere"`},
+		{86, SeverityError, `step "step_b" references unknown step "step_c"`},
+		{89, SeverityError, `cycle detected in nested inline workflows: wf1 -> wf2 -> wf1`},
+		{103, SeverityError, `wait_for_signal activity should be the only activity of step "pause"`},
+	}
+	require.Len(t, report.Errors, len(expected), "errors: %v", report.Errors)
+	for i, e := range expected {
+		require.Equal(t, "invalid.yml", report.Errors[i].File)
+		require.Equal(t, e.line, report.Errors[i].Line, "error %d: %v", i, report.Errors[i])
+		require.Equal(t, e.severity, report.Errors[i].Severity, "error %d: %v", i, report.Errors[i])
+		require.Equal(t, e.message, report.Errors[i].Message)
+	}
+}
+
+func TestValidateParseError(t *testing.T) {
+	archive := &Archive{
+		EntryDefinitions: "topology.yml",
+		Files: map[string][]byte{
+			"topology.yml": []byte("tosca_definitions_version: alien_dsl_2_0_0\nnode_types:\n  - not a map\n"),
+		},
+	}
+	report := Validate(archive, nil)
+	require.False(t, report.Valid)
+	require.Len(t, report.Errors, 1)
+	require.Equal(t, 3, report.Errors[0].Line)
+	require.Contains(t, report.Errors[0].Message, "failed to parse TOSCA definition")
+}
+
+func TestValidateTOSCA13Features(t *testing.T) {
+	archive := &Archive{
+		EntryDefinitions: "topology.yml",
+		Files: map[string][]byte{
+			"topology.yml": []byte(`tosca_definitions_version: tosca_simple_yaml_1_3
+imports:
+  - <normative-types.yml>
+node_types:
+  yorc.tests.csar.Versioned:
+    derived_from: tosca.nodes.Root
+    properties:
+      version:
+        type: version
+      release_date:
+        type: timestamp
+      ports:
+        type: range
+      label:
+        type: string
+        required: false
+topology_template:
+  node_templates:
+    Good:
+      type: yorc.tests.csar.Versioned
+      properties:
+        version: 1.3.0.beta-1
+        release_date: 2019-11-04T10:00:00Z
+        ports: [8000, 8080]
+        label: { join: [[{ get_property: [SELF, version] }, { token: [a-b-c, "-", 1] }], "_"] }
+    Bad:
+      type: yorc.tests.csar.Versioned
+      properties:
+        version: v1
+        release_date: yesterday
+        ports: [10, 1]
+        label: { concat: [{ get_nodes_of_type: yorc.tests.csar.Unknown }, { token: [a-b, "-"] }, { join: [a, b] }] }
+`),
+		},
+	}
+	report := Validate(archive, loadBuiltinDefinitions(t))
+	require.False(t, report.Valid)
+	messages := make([]string, len(report.Errors))
+	for i, e := range report.Errors {
+		require.Equal(t, SeverityError, e.Severity, "unexpected error %v", e)
+		require.Contains(t, e.Path, "node_templates.Bad")
+		messages[i] = e.Message
+	}
+	require.ElementsMatch(t, []string{
+		`"v1" is not a valid version value`,
+		`"yesterday" is not a valid timestamp value`,
+		`Invalid range definition lower bound 10 is greater than upper bound 1`,
+		`get_nodes_of_type: unknown node type "yorc.tests.csar.Unknown"`,
+		`token: expecting 3 parameters`,
+		`join: expecting a list of string values as first parameter`,
+	}, messages)
+}
+
+func TestLoadZipErrors(t *testing.T) {
+	_, err := LoadZip([]byte("not a zip"))
+	require.Error(t, err)
+
+	content, err := ziputil.ZipPath("testdata")
+	require.NoError(t, err)
+	// testdata contains only a single yml at its root but valid/topology.yml is not at the root
+	archive, err := LoadZip(content)
+	require.NoError(t, err)
+	require.Equal(t, "invalid.yml", archive.EntryDefinitions)
+
+	_, err = newArchive(map[string][]byte{"a.yml": nil, "b.yaml": nil})
+	require.Error(t, err)
+	_, err = newArchive(map[string][]byte{"dir/a.yml": nil})
+	require.Error(t, err)
+}
+
+func TestErrorString(t *testing.T) {
+	e := Error{Severity: SeverityError, File: "topology.yml", Line: 12, Path: "topology_template.node_templates.Compute", Message: "oops"}
+	require.Equal(t, "topology.yml:12: topology_template.node_templates.Compute: oops", e.Error())
+	e = Error{Severity: SeverityWarning, File: "topology.yml", Message: "oops"}
+	require.Equal(t, "topology.yml: warning: oops", e.Error())
+}
@@ -0,0 +1,260 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csar
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+// checkValueAssignment checks that a value assignment matches the given type.
+//
+// self is the name of the node template the value is assigned into, if any.
+func (v *validator) checkValueAssignment(doc *document, p []string, typeName, entrySchema string, va *tosca.ValueAssignment, self string) {
+	if va == nil || va.Value == nil {
+		return
+	}
+	switch va.Type {
+	case tosca.ValueAssignmentFunction:
+		if f := va.GetFunction(); f != nil {
+			v.checkFunction(doc, p, f, self)
+		}
+	case tosca.ValueAssignmentLiteral:
+		v.checkValue(doc, p, typeName, entrySchema, va.GetLiteral())
+	case tosca.ValueAssignmentList:
+		v.checkValue(doc, p, typeName, entrySchema, va.GetList())
+	case tosca.ValueAssignmentMap:
+		v.checkValue(doc, p, typeName, entrySchema, va.GetMap())
+	}
+}
+
+func (v *validator) checkValue(doc *document, p []string, typeName, entrySchema string, value interface{}) {
+	if typeName == "" || value == nil || !v.types.isValueType(typeName) {
+		return
+	}
+	baseType := v.types.valueType(typeName)
+	switch val := value.(type) {
+	case []interface{}:
+		if baseType == "range" {
+			if err := tosca.CheckPrimitiveValue(baseType, val); err != nil {
+				v.errorf(doc, p, "%v", err)
+			}
+			return
+		}
+		if !strings.HasPrefix(baseType, "list") {
+			v.errorf(doc, p, "expecting a value of type %q, got a list", typeName)
+			return
+		}
+		for i, elem := range val {
+			v.checkValue(doc, subPath(p, indexElem(i)), entrySchema, "", elem)
+		}
+	case map[string]interface{}:
+		if isFunctionValue(val) {
+			return
+		}
+		switch {
+		case strings.HasPrefix(baseType, "map"):
+			for k, elem := range val {
+				v.checkValue(doc, subPath(p, k), entrySchema, "", elem)
+			}
+		case baseType == "":
+			v.checkComplexValue(doc, p, typeName, val)
+		default:
+			v.errorf(doc, p, "expecting a value of type %q, got a map", typeName)
+		}
+	default:
+		s := fmt.Sprint(val)
+		if s == "" {
+			return
+		}
+		if baseType == "" || strings.HasPrefix(baseType, "list") || strings.HasPrefix(baseType, "map") {
+			v.errorf(doc, p, "expecting a value of type %q, got %q", typeName, s)
+			return
+		}
+		if !isValidLiteral(baseType, s) {
+			v.errorf(doc, p, "%q is not a valid %s value", s, baseType)
+		}
+	}
+}
+
+// checkComplexValue checks a map value against a complex data type definition
+//
+// Missing nested properties are not reported as Yorc allows partial complex values.
+func (v *validator) checkComplexValue(doc *document, p []string, typeName string, value map[string]interface{}) {
+	props := v.types.dataProperties(typeName)
+	for k, elem := range value {
+		propDef, ok := props[k]
+		if !ok {
+			v.errorf(doc, subPath(p, k), "unknown property %q for data type %q", k, typeName)
+			continue
+		}
+		v.checkValue(doc, subPath(p, k), propDef.Type, propDef.EntrySchema.Type, elem)
+	}
+}
+
+// isRequired checks if a property should be assigned.
+//
+// As per the TOSCA specification a property is considered as required by default.
+func isRequired(propDef tosca.PropertyDefinition) bool {
+	return (propDef.Required == nil || *propDef.Required) && propDef.Default == nil
+}
+
+func isFunctionValue(m map[string]interface{}) bool {
+	if len(m) != 1 {
+		return false
+	}
+	for k := range m {
+		return tosca.IsOperator(k)
+	}
+	return false
+}
+
+func isValidLiteral(typeName, value string) bool {
+	return tosca.CheckLiteralValue(typeName, value) == nil
+}
+
+// checkFunction checks TOSCA functions references
+func (v *validator) checkFunction(doc *document, p []string, f *tosca.Function, self string) {
+	if v.template == nil {
+		// functions can't be resolved outside of a topology template
+		return
+	}
+	switch f.Operator {
+	case tosca.ConcatOperator:
+		v.checkFunctionOperands(doc, p, f.Operands, self)
+	case tosca.TokenOperator:
+		if len(f.Operands) != 3 {
+			v.errorf(doc, p, "%s: expecting 3 parameters", f.Operator)
+			return
+		}
+		v.checkFunctionOperands(doc, p, f.Operands, self)
+		if f.Operands[2].IsLiteral() {
+			if i, err := strconv.Atoi(literal(f.Operands[2])); err != nil || i < 0 {
+				v.errorf(doc, p, "%s: expecting a positive integer as substring index, got %q", f.Operator, literal(f.Operands[2]))
+			}
+		}
+	case tosca.JoinOperator:
+		if len(f.Operands) < 1 || len(f.Operands) > 2 {
+			v.errorf(doc, p, "%s: expecting 1 or 2 parameters", f.Operator)
+			return
+		}
+		if _, ok := f.Operands[0].(tosca.ListOperand); !ok {
+			v.errorf(doc, p, "%s: expecting a list of string values as first parameter", f.Operator)
+			return
+		}
+		v.checkFunctionOperands(doc, p, f.Operands, self)
+	case tosca.GetNodesOfTypeOperator:
+		if len(f.Operands) != 1 {
+			v.errorf(doc, p, "%s: expecting 1 parameter", f.Operator)
+			return
+		}
+		if !f.Operands[0].IsLiteral() {
+			return
+		}
+		if _, ok := v.types.nodes[literal(f.Operands[0])]; !ok {
+			v.errorf(doc, p, "%s: unknown node type %q", f.Operator, literal(f.Operands[0]))
+		}
+	case tosca.GetArtifactOperator:
+		if len(f.Operands) < 2 || len(f.Operands) > 4 {
+			v.errorf(doc, p, "%s: expecting between 2 and 4 parameters", f.Operator)
+			return
+		}
+		v.checkFunctionEntity(doc, p, f, self)
+	case tosca.GetInputOperator:
+		if len(f.Operands) < 1 {
+			v.errorf(doc, p, "%s: expecting at least 1 parameter", f.Operator)
+			return
+		}
+		if f.Operands[0].IsLiteral() {
+			input := literal(f.Operands[0])
+			if _, ok := v.template.Inputs[input]; !ok {
+				v.errorf(doc, p, "%s: unknown input %q", f.Operator, input)
+			}
+		}
+	case tosca.GetPropertyOperator, tosca.GetAttributeOperator:
+		if len(f.Operands) < 2 {
+			v.errorf(doc, p, "%s: expecting at least 2 parameters", f.Operator)
+			return
+		}
+		nodeName := v.checkFunctionEntity(doc, p, f, self)
+		if nodeName == "" || f.Operator != tosca.GetPropertyOperator || !f.Operands[1].IsLiteral() {
+			return
+		}
+		nodeType := v.template.NodeTemplates[nodeName].Type
+		name := literal(f.Operands[1])
+		if _, ok := v.types.nodeProperties(nodeType)[name]; ok {
+			return
+		}
+		if _, ok := v.types.nodeRequirements(nodeType)[name]; ok {
+			return
+		}
+		capDef, ok := v.types.nodeCapabilities(nodeType)[name]
+		if !ok {
+			v.errorf(doc, p, "%s: node template %q has no property, capability or requirement named %q", f.Operator, nodeName, name)
+			return
+		}
+		if len(f.Operands) > 2 && f.Operands[2].IsLiteral() {
+			capProp := literal(f.Operands[2])
+			if _, ok := v.types.capabilityProperties(capDef.Type)[capProp]; !ok {
+				v.errorf(doc, p, "%s: capability %q of node template %q has no property named %q", f.Operator, name, nodeName, capProp)
+			}
+		}
+	case tosca.GetOperationOutputOperator:
+		if len(f.Operands) != 4 {
+			v.errorf(doc, p, "%s: expecting 4 parameters", f.Operator)
+			return
+		}
+		v.checkFunctionEntity(doc, p, f, self)
+	}
+}
+
+// checkFunctionOperands checks functions nested into the given operands
+func (v *validator) checkFunctionOperands(doc *document, p []string, operands []tosca.Operand, self string) {
+	for _, op := range operands {
+		switch o := op.(type) {
+		case *tosca.Function:
+			v.checkFunction(doc, p, o, self)
+		case tosca.ListOperand:
+			v.checkFunctionOperands(doc, p, o, self)
+		}
+	}
+}
+
+// checkFunctionEntity checks the modelable entity of a function and returns the
+// referenced node template name if it could be statically determined
+func (v *validator) checkFunctionEntity(doc *document, p []string, f *tosca.Function, self string) string {
+	if !f.Operands[0].IsLiteral() {
+		return ""
+	}
+	entity := literal(f.Operands[0])
+	switch entity {
+	case "SELF":
+		return self
+	case "SOURCE", "TARGET", "HOST", "REQ_TARGET":
+		return ""
+	}
+	if _, ok := v.template.NodeTemplates[entity]; !ok {
+		v.errorf(doc, p, "%s: unknown node template %q", f.Operator, entity)
+		return ""
+	}
+	return entity
+}
+
+func literal(op tosca.Operand) string {
+	return string(op.(tosca.LiteralOperand))
+}
@@ -0,0 +1,169 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package archive allows to export a deployment into a zip archive and to import it
+// into another Yorc cluster.
+//
+// An archive contains:
+//   - a manifest describing the archive content
+//   - the original CSAR uploaded to create the deployment and the deployment overlay
+//   - the stored topology
+//   - the deployment runtime data like instances states, attributes and Terraform states
+//   - the deployment tasks history
+//   - the hosts pool allocations of the deployment
+//   - optionally the deployment events and logs
+package archive
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/prov/hostspool"
+)
+
+// FormatVersion is the version of the archive format produced by this package
+const FormatVersion = 1
+
+const (
+	manifestFile    = "manifest.json"
+	csarFile        = "deployment.zip"
+	overlayDir      = "overlay/"
+	topologyFile    = "topology.json"
+	runtimeFile     = "runtime.json"
+	tasksFile       = "tasks.json"
+	allocationsFile = "hosts_pool_allocations.json"
+	eventsFile      = "events.json"
+	logsFile        = "logs.json"
+)
+
+// Manifest describes the content of a deployment archive
+type Manifest struct {
+	FormatVersion int       `json:"format_version"`
+	DeploymentID  string    `json:"deployment_id"`
+	Status        string    `json:"status"`
+	YorcVersion   string    `json:"yorc_version,omitempty"`
+	ExportDate    time.Time `json:"export_date"`
+	WithEvents    bool      `json:"with_events"`
+	WithLogs      bool      `json:"with_logs"`
+}
+
+// task holds the Consul keys of a task and of its workflow steps relative to their prefixes
+type task struct {
+	Task     map[string][]byte `json:"task"`
+	Workflow map[string][]byte `json:"workflow,omitempty"`
+}
+
+// allocation is a hosts pool allocation of a deployment
+type allocation struct {
+	Location   string               `json:"location"`
+	Hostname   string               `json:"hostname"`
+	Allocation hostspool.Allocation `json:"allocation"`
+}
+
+// entry is an event or a log entry keyed by its timestamp
+type entry struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+type badArchiveError struct {
+	msg string
+}
+
+func (e badArchiveError) Error() string {
+	return e.msg
+}
+
+// IsBadArchiveError checks if an error is caused by an invalid archive
+func IsBadArchiveError(err error) bool {
+	_, ok := errors.Cause(err).(badArchiveError)
+	return ok
+}
+
+type alreadyExistsError struct {
+	msg string
+}
+
+func (e alreadyExistsError) Error() string {
+	return e.msg
+}
+
+// IsAlreadyExistsError checks if an error is caused by data of the imported deployment that already exist in this cluster
+func IsAlreadyExistsError(err error) bool {
+	_, ok := errors.Cause(err).(alreadyExistsError)
+	return ok
+}
+
+// ReadManifest reads the manifest of an archive
+func ReadManifest(r *zip.Reader) (*Manifest, error) {
+	manifest := new(Manifest)
+	found, err := readJSONFile(r, manifestFile, manifest)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errors.WithStack(badArchiveError{"missing manifest in deployment archive"})
+	}
+	if manifest.FormatVersion < 1 || manifest.FormatVersion > FormatVersion {
+		return nil, errors.WithStack(badArchiveError{fmt.Sprintf("unsupported deployment archive format version %d", manifest.FormatVersion)})
+	}
+	return manifest, nil
+}
+
+func readJSONFile(r *zip.Reader, name string, v interface{}) (bool, error) {
+	for _, f := range r.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return true, errors.Wrapf(err, "failed to read %q from deployment archive", name)
+		}
+		defer rc.Close()
+		err = json.NewDecoder(rc).Decode(v)
+		if err != nil {
+			return true, errors.WithStack(badArchiveError{fmt.Sprintf("invalid %q in deployment archive: %v", name, err)})
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func writeJSONFile(w *zip.Writer, name string, v interface{}) error {
+	f, err := w.Create(name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to add %q to deployment archive", name)
+	}
+	return errors.Wrapf(json.NewEncoder(f).Encode(v), "failed to add %q to deployment archive", name)
+}
+
+func deploymentPrefix(deploymentID string) string {
+	return path.Join(consulutil.DeploymentKVPrefix, deploymentID)
+}
+
+// relativeKeys returns keys of kvs relative to prefix
+func relativeKeys(prefix string, kvs map[string][]byte) map[string][]byte {
+	result := make(map[string][]byte, len(kvs))
+	for k, v := range kvs {
+		result[strings.TrimPrefix(strings.TrimPrefix(k, prefix), "/")] = v
+	}
+	return result
+}
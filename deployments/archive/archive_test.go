@@ -0,0 +1,204 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/tasks"
+	"github.com/ystia/yorc/v4/testutil"
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+func buildArchive(t *testing.T, files map[string]string) *zip.Reader {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	return r
+}
+
+func TestReadManifest(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		wantErr    bool
+		badArchive bool
+	}{
+		{"Valid", map[string]string{manifestFile: `{"format_version": 1, "deployment_id": "dep"}`}, false, false},
+		{"Missing", map[string]string{topologyFile: `{}`}, true, true},
+		{"Invalid", map[string]string{manifestFile: `{"format_version": `}, true, true},
+		{"UnsupportedVersion", map[string]string{manifestFile: `{"format_version": 42, "deployment_id": "dep"}`}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest, err := ReadManifest(buildArchive(t, tt.files))
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Equal(t, tt.badArchive, IsBadArchiveError(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "dep", manifest.DeploymentID)
+		})
+	}
+}
+
+func TestImportFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yorc-archive-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	r := buildArchive(t, map[string]string{
+		csarFile:                     "csar",
+		overlayDir + "topology.yaml": "topology",
+		overlayDir + "scripts/a.sh":  "script",
+		topologyFile:                 "{}",
+	})
+	err = importFiles(r, dir)
+	require.NoError(t, err)
+	for name, expected := range map[string]string{"deployment.zip": "csar", "overlay/topology.yaml": "topology", "overlay/scripts/a.sh": "script"} {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		require.Equal(t, expected, string(b))
+	}
+	_, err = os.Stat(filepath.Join(dir, topologyFile))
+	require.True(t, os.IsNotExist(err), "topology file should not be imported in the deployment directory")
+
+	r = buildArchive(t, map[string]string{overlayDir + "../../evil.sh": "evil"})
+	err = importFiles(r, dir)
+	require.Error(t, err)
+	require.True(t, IsBadArchiveError(err))
+}
+
+func TestIsExcludedRuntimeKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"status", false},
+		{"topology/instances/Compute/0/state", false},
+		{"terraform-state/Compute", false},
+		{"terraform-state/Compute/.lock", true},
+		{"terraform-state/Compute/.lockinfo", true},
+		{"secrets/leases/abc", true},
+		{".blockingOp", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			require.Equal(t, tt.want, isExcludedRuntimeKey(tt.key))
+		})
+	}
+}
+
+func TestRenewTaskIDs(t *testing.T) {
+	c := &content{
+		runtime: map[string][]byte{"status": []byte("DEPLOYED"), "tasks/t1": []byte("")},
+		tasks:   map[string]task{"t1": {Task: map[string][]byte{"targetId": []byte("d1")}}},
+	}
+	c.renewTaskIDs()
+	require.Len(t, c.taskIDs, 1)
+	newID := c.taskIDs["t1"]
+	require.NotEmpty(t, newID)
+	require.NotEqual(t, "t1", newID)
+	require.Len(t, c.tasks, 1)
+	require.Equal(t, []byte("d1"), c.tasks[newID].Task["targetId"])
+	require.Equal(t, map[string][]byte{"status": []byte("DEPLOYED"), "tasks/" + newID: []byte("")}, c.runtime)
+}
+
+func testExportImport(t *testing.T, workingDir string) {
+	ctx := context.Background()
+	deploymentID := testutil.BuildDeploymentID(t)
+	taskID := deploymentID + "-task"
+
+	err := deployments.StoreDeploymentDefinition(ctx, deploymentID, "testdata/topology.yaml")
+	require.NoError(t, err)
+	err = deployments.SetInstanceStateWithContextualLogs(ctx, deploymentID, "Compute", "0", tosca.NodeStateStarted)
+	require.NoError(t, err)
+	err = deployments.SetDeploymentStatus(ctx, deploymentID, deployments.DEPLOYED)
+	require.NoError(t, err)
+	err = consulutil.StoreConsulKeyAsString(path.Join(consulutil.TasksPrefix, taskID, "targetId"), deploymentID)
+	require.NoError(t, err)
+	err = consulutil.StoreConsulKeyAsString(path.Join(consulutil.DeploymentKVPrefix, deploymentID, "tasks", taskID), "")
+	require.NoError(t, err)
+	err = consulutil.StoreConsulKeyAsString(path.Join(consulutil.WorkflowsPrefix, taskID, "Compute_install"), "done")
+	require.NoError(t, err)
+	events.SimpleLogEntry(ctx, events.LogLevelINFO, deploymentID).RegisterAsString("some log")
+
+	buf := new(bytes.Buffer)
+	err = Export(ctx, buf, deploymentID, ExportOptions{WorkingDirectory: workingDir, IncludeLogs: true})
+	require.NoError(t, err)
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	// Tasks are imported under new IDs so the original deployment may still exist
+	newID, err := Import(ctx, r, deploymentID+"-imported", ImportOptions{WorkingDirectory: workingDir})
+	require.NoError(t, err)
+	require.Equal(t, deploymentID+"-imported", newID)
+	target, err := tasks.GetTaskTarget(taskID)
+	require.NoError(t, err)
+	require.Equal(t, deploymentID, target)
+
+	status, err := deployments.GetDeploymentStatus(ctx, newID)
+	require.NoError(t, err)
+	require.Equal(t, deployments.DEPLOYED, status)
+	nodes, err := deployments.GetNodes(ctx, newID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Compute"}, nodes)
+	wfs, err := deployments.GetWorkflows(ctx, newID)
+	require.NoError(t, err)
+	require.Contains(t, wfs, "install")
+	state, err := deployments.GetInstanceState(ctx, newID, "Compute", "0")
+	require.NoError(t, err)
+	require.Equal(t, tosca.NodeStateStarted, state)
+	taskIDs, err := deployments.GetDeploymentTaskList(ctx, newID)
+	require.NoError(t, err)
+	require.Len(t, taskIDs, 1)
+	require.NotEqual(t, taskID, taskIDs[0])
+	target, err = tasks.GetTaskTarget(taskIDs[0])
+	require.NoError(t, err)
+	require.Equal(t, newID, target)
+	stepStatus, err := tasks.GetTaskStepStatus(taskIDs[0], "Compute_install")
+	require.NoError(t, err)
+	require.Equal(t, tasks.TaskStepStatusDONE, stepStatus)
+	logs, _, err := events.LogsEvents(ctx, newID, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.Contains(t, string(logs[0]), newID)
+
+	// Deployment ID already used
+	_, err = Import(ctx, r, newID, ImportOptions{WorkingDirectory: workingDir})
+	require.Error(t, err)
+	require.True(t, IsAlreadyExistsError(err))
+}
@@ -0,0 +1,293 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archive
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/collections"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/helper/ziputil"
+	"github.com/ystia/yorc/v4/prov/hostspool"
+	"github.com/ystia/yorc/v4/server/info"
+	"github.com/ystia/yorc/v4/storage"
+	"github.com/ystia/yorc/v4/storage/types"
+)
+
+// ExportOptions allows to customize a deployment export
+type ExportOptions struct {
+	// WorkingDirectory is the Yorc working directory containing deployments files
+	WorkingDirectory string
+	// HostsPoolManager is used to retrieve hosts pool allocations of the deployment, allocations are not exported if nil
+	HostsPoolManager hostspool.Manager
+	// IncludeEvents allows to export the deployment events
+	IncludeEvents bool
+	// IncludeLogs allows to export the deployment logs
+	IncludeLogs bool
+}
+
+// Export writes an archive of the given deployment
+func Export(ctx context.Context, w io.Writer, deploymentID string, opts ExportOptions) error {
+	status, err := deployments.GetDeploymentStatus(ctx, deploymentID)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	manifest := Manifest{
+		FormatVersion: FormatVersion,
+		DeploymentID:  deploymentID,
+		Status:        status.String(),
+		YorcVersion:   info.YorcVersion,
+		ExportDate:    time.Now(),
+		WithEvents:    opts.IncludeEvents,
+		WithLogs:      opts.IncludeLogs,
+	}
+	err = writeJSONFile(zw, manifestFile, manifest)
+	if err != nil {
+		return err
+	}
+
+	err = exportFiles(zw, filepath.Join(opts.WorkingDirectory, "deployments", deploymentID))
+	if err != nil {
+		return err
+	}
+
+	topology, err := exportTopology(deploymentID)
+	if err != nil {
+		return err
+	}
+	err = writeJSONFile(zw, topologyFile, topology)
+	if err != nil {
+		return err
+	}
+
+	runtime, err := exportRuntime(deploymentID, topology)
+	if err != nil {
+		return err
+	}
+	err = writeJSONFile(zw, runtimeFile, runtime)
+	if err != nil {
+		return err
+	}
+
+	tasksHistory, err := exportTasks(ctx, deploymentID)
+	if err != nil {
+		return err
+	}
+	err = writeJSONFile(zw, tasksFile, tasksHistory)
+	if err != nil {
+		return err
+	}
+
+	if opts.HostsPoolManager != nil {
+		allocations, err := exportAllocations(opts.HostsPoolManager, deploymentID)
+		if err != nil {
+			return err
+		}
+		err = writeJSONFile(zw, allocationsFile, allocations)
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.IncludeEvents {
+		events, err := exportEntries(ctx, types.StoreTypeEvent, path.Join(consulutil.EventsPrefix, deploymentID))
+		if err != nil {
+			return err
+		}
+		err = writeJSONFile(zw, eventsFile, events)
+		if err != nil {
+			return err
+		}
+	}
+	if opts.IncludeLogs {
+		logs, err := exportEntries(ctx, types.StoreTypeLog, path.Join(consulutil.LogsPrefix, deploymentID))
+		if err != nil {
+			return err
+		}
+		err = writeJSONFile(zw, logsFile, logs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return errors.Wrap(zw.Close(), "failed to write deployment archive")
+}
+
+// exportFiles adds the original CSAR and the overlay of the deployment to the archive
+func exportFiles(zw *zip.Writer, deploymentDir string) error {
+	err := ziputil.AddFile(zw, filepath.Join(deploymentDir, csarFile), csarFile)
+	if err != nil && !os.IsNotExist(errors.Cause(err)) {
+		return err
+	}
+	overlayPath := filepath.Join(deploymentDir, strings.TrimSuffix(overlayDir, "/"))
+	err = filepath.Walk(overlayPath, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(overlayPath, p)
+		if err != nil {
+			return err
+		}
+		return ziputil.AddFile(zw, p, overlayDir+filepath.ToSlash(rel))
+	})
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return errors.Wrap(err, "failed to add deployment overlay to archive")
+}
+
+// exportTopology returns values of the deployment store with keys relative to the deployment prefix
+func exportTopology(deploymentID string) (map[string]json.RawMessage, error) {
+	s := storage.GetStore(types.StoreTypeDeployment)
+	prefix := deploymentPrefix(deploymentID)
+	result := make(map[string]json.RawMessage)
+
+	var walk func(key string) error
+	walk = func(key string) error {
+		rel := strings.TrimPrefix(key, prefix+"/")
+		if collections.ContainsString(deployments.RuntimeTopologyKeys, rel) {
+			return nil
+		}
+		var value json.RawMessage
+		exist, err := s.Get(key, &value)
+		if err != nil {
+			return errors.Wrapf(err, "failed to export topology key %q", key)
+		}
+		if exist {
+			result[rel] = value
+		}
+		keys, err := s.Keys(key)
+		if err != nil {
+			return errors.Wrapf(err, "failed to export topology key %q", key)
+		}
+		for _, k := range keys {
+			err = walk(k)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, root := range deployments.DefinitionStoreRoots {
+		err := walk(path.Join(prefix, root))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// exportRuntime returns deployment keys stored in Consul except the ones already exported with the topology
+func exportRuntime(deploymentID string, topology map[string]json.RawMessage) (map[string][]byte, error) {
+	prefix := deploymentPrefix(deploymentID)
+	kvs, err := consulutil.List(prefix + "/")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to export deployment %q", deploymentID)
+	}
+	result := relativeKeys(prefix, kvs)
+	for k := range result {
+		if _, ok := topology[k]; ok || isExcludedRuntimeKey(k) {
+			delete(result, k)
+		}
+	}
+	return result, nil
+}
+
+// isExcludedRuntimeKey returns true for keys that are specific to the cluster running the deployment
+func isExcludedRuntimeKey(key string) bool {
+	// Secrets leases are related to the vault used by this cluster,
+	// Terraform states locks and blocking operation flags to processes running on this cluster
+	return strings.HasPrefix(key, "secrets/") || key == ".blockingOp" ||
+		strings.HasSuffix(key, "/.lock") || strings.HasSuffix(key, "/.lockinfo")
+}
+
+func exportTasks(ctx context.Context, deploymentID string) (map[string]task, error) {
+	taskIDs, err := deployments.GetDeploymentTaskList(ctx, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]task, len(taskIDs))
+	for _, taskID := range taskIDs {
+		taskPrefix := path.Join(consulutil.TasksPrefix, taskID)
+		kvs, err := consulutil.List(taskPrefix + "/")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to export task %q", taskID)
+		}
+		if len(kvs) == 0 {
+			continue
+		}
+		wfPrefix := path.Join(consulutil.WorkflowsPrefix, taskID)
+		wfKVs, err := consulutil.List(wfPrefix + "/")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to export task %q", taskID)
+		}
+		result[taskID] = task{Task: relativeKeys(taskPrefix, kvs), Workflow: relativeKeys(wfPrefix, wfKVs)}
+	}
+	return result, nil
+}
+
+func exportAllocations(hpManager hostspool.Manager, deploymentID string) ([]allocation, error) {
+	result := make([]allocation, 0)
+	locations, err := hpManager.ListLocations()
+	if err != nil {
+		return nil, err
+	}
+	for _, location := range locations {
+		hostnames, _, _, err := hpManager.List(location)
+		if err != nil {
+			return nil, err
+		}
+		for _, hostname := range hostnames {
+			host, err := hpManager.GetHost(location, hostname)
+			if err != nil {
+				return nil, err
+			}
+			for _, alloc := range host.Allocations {
+				if alloc.DeploymentID == deploymentID {
+					result = append(result, allocation{Location: location, Hostname: hostname, Allocation: alloc})
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+func exportEntries(ctx context.Context, storeType types.StoreType, prefix string) ([]entry, error) {
+	kvs, _, err := storage.GetStore(storeType).List(ctx, prefix+"/", 0, 0)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to export %s", strings.ToLower(storeType.String()))
+	}
+	result := make([]entry, 0, len(kvs))
+	for _, kv := range kvs {
+		result = append(result, entry{Key: strings.TrimPrefix(strings.TrimPrefix(kv.Key, prefix), "/"), Value: kv.RawValue})
+	}
+	return result, nil
+}
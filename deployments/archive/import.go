@@ -0,0 +1,335 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archive
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/hostspool"
+	"github.com/ystia/yorc/v4/storage"
+	"github.com/ystia/yorc/v4/storage/store"
+	"github.com/ystia/yorc/v4/storage/types"
+	"github.com/ystia/yorc/v4/tasks"
+)
+
+// ImportOptions allows to customize a deployment import
+type ImportOptions struct {
+	// WorkingDirectory is the Yorc working directory containing deployments files
+	WorkingDirectory string
+	// HostsPoolManager is used to restore hosts pool allocations of the deployment
+	HostsPoolManager hostspool.Manager
+}
+
+// content is the content of an archive
+type content struct {
+	topology    map[string]json.RawMessage
+	runtime     map[string][]byte
+	tasks       map[string]task
+	allocations []allocation
+	events      []entry
+	logs        []entry
+	// taskIDs maps exported tasks IDs to the IDs of imported tasks
+	taskIDs map[string]string
+}
+
+// Import restores a deployment from an archive under the given deployment ID and returns this ID.
+//
+// If deploymentID is empty the ID of the exported deployment is used.
+// Tasks of the deployment are imported under new IDs so they never collide with existing tasks.
+// Data already imported are removed if the import fails.
+func Import(ctx context.Context, r *zip.Reader, deploymentID string, opts ImportOptions) (string, error) {
+	manifest, err := ReadManifest(r)
+	if err != nil {
+		return "", err
+	}
+	if deploymentID == "" {
+		deploymentID = manifest.DeploymentID
+	}
+
+	c, err := readContent(r)
+	if err != nil {
+		return "", err
+	}
+	c.renewTaskIDs()
+	err = checkImport(ctx, deploymentID, c, opts)
+	if err != nil {
+		return "", err
+	}
+
+	err = importContent(ctx, r, manifest.DeploymentID, deploymentID, c, opts)
+	if err != nil {
+		cleanupImport(ctx, deploymentID, c, opts)
+		return "", err
+	}
+	return deploymentID, nil
+}
+
+func readContent(r *zip.Reader) (*content, error) {
+	c := &content{
+		topology: make(map[string]json.RawMessage),
+		runtime:  make(map[string][]byte),
+		tasks:    make(map[string]task),
+	}
+	files := []struct {
+		name     string
+		v        interface{}
+		required bool
+	}{
+		{topologyFile, &c.topology, true},
+		{runtimeFile, &c.runtime, true},
+		{tasksFile, &c.tasks, false},
+		{allocationsFile, &c.allocations, false},
+		{eventsFile, &c.events, false},
+		{logsFile, &c.logs, false},
+	}
+	for _, f := range files {
+		found, err := readJSONFile(r, f.name, f.v)
+		if err != nil {
+			return nil, err
+		}
+		if !found && f.required {
+			return nil, errors.WithStack(badArchiveError{fmt.Sprintf("missing %q in deployment archive", f.name)})
+		}
+	}
+	return c, nil
+}
+
+// renewTaskIDs generates new IDs for imported tasks and updates references to exported tasks IDs
+func (c *content) renewTaskIDs() {
+	c.taskIDs = make(map[string]string, len(c.tasks))
+	renewed := make(map[string]task, len(c.tasks))
+	for taskID, t := range c.tasks {
+		newID := fmt.Sprint(uuid.NewV4())
+		c.taskIDs[taskID] = newID
+		renewed[newID] = t
+		key := path.Join("tasks", taskID)
+		if v, ok := c.runtime[key]; ok {
+			delete(c.runtime, key)
+			c.runtime[path.Join("tasks", newID)] = v
+		}
+	}
+	c.tasks = renewed
+}
+
+// checkImport checks that imported data do not conflict with existing ones
+func checkImport(ctx context.Context, deploymentID string, c *content, opts ImportOptions) error {
+	exists, err := deployments.DoesDeploymentExists(ctx, deploymentID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.WithStack(alreadyExistsError{fmt.Sprintf("deployment with id %q already exists", deploymentID)})
+	}
+	if len(c.allocations) > 0 && opts.HostsPoolManager == nil {
+		return errors.New("hosts pool allocations of the deployment can't be restored as there is no hosts pool manager")
+	}
+	for _, alloc := range c.allocations {
+		_, err = opts.HostsPoolManager.GetHost(alloc.Location, alloc.Hostname)
+		if err != nil {
+			return errors.Wrapf(err, "hosts pool allocation of node %q instance %q can't be restored on host %q of location %q",
+				alloc.Allocation.NodeName, alloc.Allocation.Instance, alloc.Hostname, alloc.Location)
+		}
+	}
+	return nil
+}
+
+func importContent(ctx context.Context, r *zip.Reader, exportedID, deploymentID string, c *content, opts ImportOptions) error {
+	err := importFiles(r, filepath.Join(opts.WorkingDirectory, "deployments", deploymentID))
+	if err != nil {
+		return err
+	}
+
+	prefix := deploymentPrefix(deploymentID)
+	kvs := make([]store.KeyValueIn, 0, len(c.topology))
+	for k, v := range c.topology {
+		kvs = append(kvs, store.KeyValueIn{Key: path.Join(prefix, k), Value: v})
+	}
+	err = storage.GetStore(types.StoreTypeDeployment).SetCollection(ctx, kvs)
+	if err != nil {
+		return errors.Wrapf(err, "failed to import topology of deployment %q", deploymentID)
+	}
+
+	_, errGroup, consulStore := consulutil.WithContext(ctx)
+	for k, v := range c.runtime {
+		consulStore.StoreConsulKey(path.Join(prefix, k), v)
+	}
+	for taskID, t := range c.tasks {
+		for k, v := range t.Task {
+			if k == "targetId" {
+				v = []byte(deploymentID)
+			}
+			consulStore.StoreConsulKey(path.Join(consulutil.TasksPrefix, taskID, k), v)
+		}
+		for k, v := range t.Workflow {
+			consulStore.StoreConsulKey(path.Join(consulutil.WorkflowsPrefix, taskID, k), v)
+		}
+	}
+	err = errGroup.Wait()
+	if err != nil {
+		return errors.Wrapf(err, "failed to import runtime data of deployment %q", deploymentID)
+	}
+
+	err = importEntries(ctx, types.StoreTypeEvent, path.Join(consulutil.EventsPrefix, deploymentID), exportedID, deploymentID, c.taskIDs, c.events)
+	if err != nil {
+		return err
+	}
+	err = importEntries(ctx, types.StoreTypeLog, path.Join(consulutil.LogsPrefix, deploymentID), exportedID, deploymentID, c.taskIDs, c.logs)
+	if err != nil {
+		return err
+	}
+
+	// Allocations are restored last as they are the only data shared with other deployments
+	for i, alloc := range c.allocations {
+		a := alloc.Allocation
+		a.DeploymentID = deploymentID
+		// let the manager build the allocation ID based on the new deployment ID
+		a.ID = ""
+		err = opts.HostsPoolManager.Restore(alloc.Location, alloc.Hostname, &a)
+		if err != nil {
+			releaseAllocations(opts.HostsPoolManager, deploymentID, c.allocations[:i])
+			return errors.Wrapf(err, "failed to restore hosts pool allocation of node %q instance %q on host %q of location %q",
+				a.NodeName, a.Instance, alloc.Hostname, alloc.Location)
+		}
+	}
+	return nil
+}
+
+// releaseAllocations releases allocations restored by a failed import.
+//
+// Hosts resources labels consumed by these allocations are not given back.
+func releaseAllocations(hpManager hostspool.Manager, deploymentID string, allocations []allocation) {
+	for _, alloc := range allocations {
+		_, err := hpManager.Release(alloc.Location, alloc.Hostname, deploymentID, alloc.Allocation.NodeName, alloc.Allocation.Instance)
+		if err != nil {
+			log.Printf("Failed to release hosts pool allocation on host %q during import cleanup of deployment %q: %v", alloc.Hostname, deploymentID, err)
+		}
+	}
+}
+
+// importFiles extracts the original CSAR and the overlay of the deployment
+func importFiles(r *zip.Reader, deploymentDir string) error {
+	for _, f := range r.File {
+		if f.Name != csarFile && !strings.HasPrefix(f.Name, overlayDir) {
+			continue
+		}
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		filePath := filepath.Join(deploymentDir, filepath.FromSlash(f.Name))
+		if !strings.HasPrefix(filePath, filepath.Clean(deploymentDir)+string(os.PathSeparator)) {
+			return errors.WithStack(badArchiveError{fmt.Sprintf("invalid file path %q in deployment archive", f.Name)})
+		}
+		err := extractFile(f, filePath)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractFile(f *zip.File, filePath string) error {
+	err := os.MkdirAll(filepath.Dir(filePath), 0775)
+	if err != nil {
+		return errors.Wrapf(err, "failed to extract %q from deployment archive", f.Name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return errors.Wrapf(err, "failed to extract %q from deployment archive", f.Name)
+	}
+	defer rc.Close()
+	target, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode()|0600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to extract %q from deployment archive", f.Name)
+	}
+	defer target.Close()
+	_, err = io.Copy(target, rc)
+	return errors.Wrapf(err, "failed to extract %q from deployment archive", f.Name)
+}
+
+// importEntries stores events or logs entries updating the deployment and tasks IDs they refer to
+func importEntries(ctx context.Context, storeType types.StoreType, prefix, exportedID, deploymentID string, taskIDs map[string]string, entries []entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	kvs := make([]store.KeyValueIn, 0, len(entries))
+	for _, e := range entries {
+		var flat map[string]interface{}
+		err := json.Unmarshal(e.Value, &flat)
+		if err != nil {
+			return errors.WithStack(badArchiveError{fmt.Sprintf("invalid %s entry %q in deployment archive: %v", strings.ToLower(storeType.String()), e.Key, err)})
+		}
+		value := e.Value
+		taskID, _ := flat[events.ETaskID.String()].(string)
+		newTaskID, renewed := taskIDs[taskID]
+		if exportedID != deploymentID || renewed {
+			flat[events.EDeploymentID.String()] = deploymentID
+			if renewed {
+				flat[events.ETaskID.String()] = newTaskID
+			}
+			value, err = json.Marshal(flat)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		kvs = append(kvs, store.KeyValueIn{Key: path.Join(prefix, e.Key), Value: value})
+	}
+	err := storage.GetStore(storeType).SetCollection(ctx, kvs)
+	return errors.Wrapf(err, "failed to import %s of deployment %q", strings.ToLower(storeType.String()), deploymentID)
+}
+
+// cleanupImport removes data of a failed import
+func cleanupImport(ctx context.Context, deploymentID string, c *content, opts ImportOptions) {
+	for taskID := range c.tasks {
+		err := tasks.DeleteTask(taskID)
+		if err != nil {
+			log.Printf("Failed to cleanup task %q of imported deployment %q: %v", taskID, deploymentID, err)
+		}
+		err = consulutil.Delete(path.Join(consulutil.WorkflowsPrefix, taskID)+"/", true)
+		if err != nil {
+			log.Printf("Failed to cleanup task %q of imported deployment %q: %v", taskID, deploymentID, err)
+		}
+	}
+	err := events.PurgeDeploymentEvents(ctx, deploymentID)
+	if err != nil {
+		log.Printf("Failed to cleanup events of imported deployment %q: %v", deploymentID, err)
+	}
+	err = events.PurgeDeploymentLogs(ctx, deploymentID)
+	if err != nil {
+		log.Printf("Failed to cleanup logs of imported deployment %q: %v", deploymentID, err)
+	}
+	err = deployments.DeleteDeployment(ctx, deploymentID)
+	if err != nil {
+		log.Printf("Failed to cleanup imported deployment %q: %v", deploymentID, err)
+	}
+	err = os.RemoveAll(filepath.Join(opts.WorkingDirectory, "deployments", deploymentID))
+	if err != nil {
+		log.Printf("Failed to cleanup files of imported deployment %q: %v", deploymentID, err)
+	}
+}
@@ -67,6 +67,15 @@ func TestRunConsulDeploymentsPackageTests(t *testing.T) {
 		t.Run("testRelationshipWorkflow", func(t *testing.T) {
 			testRelationshipWorkflow(t)
 		})
+		t.Run("testValidateWorkflowInputs", func(t *testing.T) {
+			testValidateWorkflowInputs(t)
+		})
+		t.Run("testCheckWorkflowOutputs", func(t *testing.T) {
+			testCheckWorkflowOutputs(t)
+		})
+		t.Run("testCheckWorkflowPreconditionsOnRelationship", func(t *testing.T) {
+			testCheckWorkflowPreconditionsOnRelationship(t)
+		})
 		t.Run("testGlobalInputs", func(t *testing.T) {
 			testGlobalInputs(t)
 		})
@@ -79,6 +88,18 @@ func TestRunConsulDeploymentsPackageTests(t *testing.T) {
 		t.Run("testCheckCycleInNestedWorkflows", func(t *testing.T) {
 			testCheckCycleInNestedWorkflows(t)
 		})
+		t.Run("testSecretLeases", func(t *testing.T) {
+			testSecretLeases(t)
+		})
+		t.Run("testSecretReferences", func(t *testing.T) {
+			testSecretReferences(t)
+		})
+		t.Run("testDeploymentMetadata", func(t *testing.T) {
+			testDeploymentMetadata(t)
+		})
+		t.Run("testDeploymentExpiry", func(t *testing.T) {
+			testDeploymentExpiry(t)
+		})
 		t.Run("testGetCapabilityProperties", func(t *testing.T) {
 			testGetCapabilityProperties(t)
 		})
@@ -38,6 +38,13 @@ import (
 
 const blockingOperationOnDeploymentFlagName = ".blockingOp"
 
+// DefinitionStoreRoots are the deployment sub-keys held by the deployments store
+var DefinitionStoreRoots = []string{"topology", "workflows"}
+
+// RuntimeTopologyKeys are the deployment topology sub-keys holding runtime data in Consul
+// instead of the deployments store
+var RuntimeTopologyKeys = []string{"topology/instances", "topology/relationship_instances"}
+
 // AddBlockingOperationOnDeploymentFlag set a flag on a given deployment to specify that an operation is ongoing and no other tasks should be run on this deployment
 func AddBlockingOperationOnDeploymentFlag(ctx context.Context, deploymentID string) error {
 	return consulutil.StoreConsulKey(path.Join(consulutil.DeploymentKVPrefix, deploymentID, blockingOperationOnDeploymentFlagName), nil)
@@ -127,6 +134,33 @@ func PostDeploymentDefinitionStorageProcess(ctx context.Context, deploymentID st
 			if err != nil {
 				return err
 			}
+			err = checkPrimitiveTypesValues(ctx, deploymentID, nodeName)
+			if err != nil {
+				return handleDeploymentStatus(ctx, deploymentID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkPrimitiveTypesValues checks that node template properties of type timestamp, version or range
+// have a valid syntax
+func checkPrimitiveTypesValues(ctx context.Context, deploymentID, nodeName string) error {
+	node, err := getNodeTemplate(ctx, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+	for propName, va := range node.Properties {
+		if va == nil || (va.Type != tosca.ValueAssignmentLiteral && va.Type != tosca.ValueAssignmentList) {
+			continue
+		}
+		dataType, err := GetTypePropertyDataType(ctx, deploymentID, node.Type, propName)
+		if err != nil {
+			return err
+		}
+		err = tosca.CheckPrimitiveValue(dataType, va.Value)
+		if err != nil {
+			return errors.Wrapf(err, "invalid value for property %q of node %q", propName, nodeName)
 		}
 	}
 	return nil
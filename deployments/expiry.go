@@ -0,0 +1,109 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"context"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/helper/consulutil"
+)
+
+// DeploymentExpiry is the expiry of a deployment having a time-to-live
+type DeploymentExpiry struct {
+	// Date is the date at which the deployment expires
+	Date time.Time
+	// Purge indicates that the deployment should be purged and not only undeployed when it expires
+	Purge bool
+	// Warned is true once a warning has been sent for the current expiry date
+	Warned bool
+	// TaskID is the ID of the task registered when the deployment expired for the current expiry date
+	TaskID string
+}
+
+func expiryPath(deploymentID string) string {
+	return path.Join(consulutil.DeploymentKVPrefix, deploymentID, "expiry")
+}
+
+// SetDeploymentExpiry sets or replaces the expiry date of a deployment
+//
+// A new expiry date resets the sent warning and the registered task, so an extended deployment will be warned again
+// before its new expiry date.
+func SetDeploymentExpiry(ctx context.Context, deploymentID string, date time.Time, purge bool) error {
+	ops := api.KVTxnOps{
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(expiryPath(deploymentID), "date"),
+			Value: []byte(date.UTC().Format(time.RFC3339)),
+		},
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(expiryPath(deploymentID), "purge"),
+			Value: []byte(strconv.FormatBool(purge)),
+		},
+	}
+	return consulutil.ExecuteSplittableTransaction(ops, nil, nil)
+}
+
+// GetDeploymentExpiry returns the expiry of a deployment or nil if the deployment has no expiry date
+func GetDeploymentExpiry(ctx context.Context, deploymentID string) (*DeploymentExpiry, error) {
+	kvs, err := consulutil.List(expiryPath(deploymentID) + "/")
+	if err != nil {
+		return nil, err
+	}
+	value, ok := kvs[path.Join(expiryPath(deploymentID), "date")]
+	if !ok {
+		return nil, nil
+	}
+	date := string(value)
+	expiry := &DeploymentExpiry{}
+	expiry.Date, err = time.Parse(time.RFC3339, date)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid expiry date for deployment %q", deploymentID)
+	}
+	if value, ok = kvs[path.Join(expiryPath(deploymentID), "purge")]; ok {
+		expiry.Purge, err = strconv.ParseBool(string(value))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid expiry purge flag for deployment %q", deploymentID)
+		}
+	}
+	// Warnings and tasks are recorded along with the expiry date they relate to, this way they do not apply to
+	// an extended expiry date even if the deployment was extended concurrently
+	expiry.Warned = string(kvs[path.Join(expiryPath(deploymentID), "warned")]) == date
+	if value, ok = kvs[path.Join(expiryPath(deploymentID), "task", date)]; ok {
+		expiry.TaskID = string(value)
+	}
+	return expiry, nil
+}
+
+// RemoveDeploymentExpiry removes the expiry date of a deployment, the deployment will no longer expire
+func RemoveDeploymentExpiry(ctx context.Context, deploymentID string) error {
+	return consulutil.Delete(expiryPath(deploymentID)+"/", true)
+}
+
+// SetDeploymentExpiryWarned records that a warning has been sent for the given expiry date of a deployment
+func SetDeploymentExpiryWarned(ctx context.Context, deploymentID string, date time.Time) error {
+	return consulutil.StoreConsulKeyAsString(path.Join(expiryPath(deploymentID), "warned"), date.UTC().Format(time.RFC3339))
+}
+
+// SetDeploymentExpiryTaskID records the task registered when the deployment expired at the given expiry date
+func SetDeploymentExpiryTaskID(ctx context.Context, deploymentID string, date time.Time, taskID string) error {
+	return consulutil.StoreConsulKeyAsString(path.Join(expiryPath(deploymentID), "task", date.UTC().Format(time.RFC3339)), taskID)
+}
@@ -0,0 +1,244 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package expiry undeploys or purges deployments once their expiry date is reached.
+//
+// A warning is published as a deployment event and notified to the configured webhooks
+// before a deployment expires, the deployment is then undeployed, or purged if requested,
+// when its expiry date is reached.
+//
+// Only the leader Yorc server of the cluster checks deployments expiry dates.
+package expiry
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+
+	"github.com/ystia/yorc/v4/cluster"
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/tasks"
+	"github.com/ystia/yorc/v4/tasks/collector"
+)
+
+// Expiry statuses published in events and notifications
+const (
+	// StatusExpiring is the status of a deployment that will expire within the warning delay
+	StatusExpiring = "expiring"
+	// StatusExpired is the status of a deployment that reached its expiry date
+	StatusExpired = "expired"
+)
+
+// webhookTimeout is the timeout of a notification sent to a webhook
+const webhookTimeout = 10 * time.Second
+
+var defaultJanitor *janitor
+
+// janitor periodically checks deployments expiry dates
+type janitor struct {
+	cc         *api.Client
+	collector  *collector.Collector
+	cfg        config.Configuration
+	httpClient *http.Client
+	serviceKey string
+	chShutdown chan struct{}
+	lock       sync.Mutex
+	chStop     chan struct{}
+}
+
+// action is what should be done for a deployment having an expiry date
+type action int
+
+const (
+	actionNone action = iota
+	actionWarn
+	actionExpire
+)
+
+// Start starts to watch the leader election of the deployments expiry janitor,
+// deployments expiry dates are checked while this server is the leader
+func Start(cfg config.Configuration, cc *api.Client) {
+	defaultJanitor = &janitor{
+		cc:         cc,
+		collector:  collector.NewCollectorWithApprovals(cc, cfg.Tasks.Approval),
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: webhookTimeout},
+		serviceKey: path.Join(consulutil.YorcServicePrefix, "/deployments/expiry/leader"),
+		chShutdown: make(chan struct{}),
+	}
+	go cluster.WatchLeaderElection(cc, cluster.RoleDeploymentsExpiryLeader, defaultJanitor.serviceKey, defaultJanitor.chShutdown, defaultJanitor.startChecks, defaultJanitor.stopChecks)
+}
+
+// Stop stops checking deployments expiry dates
+func Stop() {
+	defaultJanitor.stopChecks()
+	close(defaultJanitor.chShutdown)
+}
+
+func (j *janitor) checkInterval() time.Duration {
+	if j.cfg.DeploymentsExpiry.CheckInterval <= 0 {
+		return config.DefaultDeploymentsExpiryCheckInterval
+	}
+	return j.cfg.DeploymentsExpiry.CheckInterval
+}
+
+func (j *janitor) startChecks() {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if j.chStop != nil {
+		return
+	}
+	chStop := make(chan struct{})
+	j.chStop = chStop
+	go func() {
+		ticker := time.NewTicker(j.checkInterval())
+		defer ticker.Stop()
+		for {
+			j.checkDeployments()
+			select {
+			case <-chStop:
+				return
+			case <-j.chShutdown:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (j *janitor) stopChecks() {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if j.chStop != nil {
+		close(j.chStop)
+		j.chStop = nil
+	}
+}
+
+func (j *janitor) checkDeployments() {
+	ctx := context.Background()
+	deploymentsIDs, err := deployments.GetDeploymentsIDs(ctx)
+	if err != nil {
+		log.Printf("[WARN] Failed to list deployments to check their expiry: %v", err)
+		return
+	}
+	now := time.Now()
+	for _, deploymentID := range deploymentsIDs {
+		err = j.checkDeployment(ctx, deploymentID, now)
+		if err != nil {
+			log.Printf("[WARN] Failed to check expiry of deployment %q: %v", deploymentID, err)
+		}
+	}
+	// Expired deployments could be purged, evict them once their purge events retention is over
+	err = deployments.CleanupPurgedDeployments(ctx, j.cc, j.cfg.PurgedDeploymentsEvictionTimeout)
+	if err != nil {
+		log.Printf("[WARN] Failed to cleanup purged deployments: %v", err)
+	}
+}
+
+func (j *janitor) checkDeployment(ctx context.Context, deploymentID string, now time.Time) error {
+	expiry, err := deployments.GetDeploymentExpiry(ctx, deploymentID)
+	if err != nil || expiry == nil {
+		return err
+	}
+	status, err := deployments.GetDeploymentStatus(ctx, deploymentID)
+	if err != nil {
+		return err
+	}
+	warningDelay := j.cfg.DeploymentsExpiry.WarningDelay
+	if warningDelay <= 0 {
+		warningDelay = config.DefaultDeploymentsExpiryWarningDelay
+	}
+	switch nextAction(expiry, status, now, warningDelay) {
+	case actionWarn:
+		return j.warn(ctx, deploymentID, expiry)
+	case actionExpire:
+		return j.expire(ctx, deploymentID, expiry)
+	}
+	return nil
+}
+
+// nextAction returns the action to run at a given time for a deployment having an expiry date
+func nextAction(expiry *deployments.DeploymentExpiry, status deployments.DeploymentStatus, now time.Time, warningDelay time.Duration) action {
+	if expiry.TaskID != "" {
+		// Already expired
+		return actionNone
+	}
+	switch status {
+	case deployments.PURGE_IN_PROGRESS, deployments.PURGED:
+		return actionNone
+	case deployments.UNDEPLOYED:
+		// Nothing left to undeploy, an undeployed deployment could still be purged
+		if !expiry.Purge {
+			return actionNone
+		}
+	}
+	if !now.Before(expiry.Date) {
+		return actionExpire
+	}
+	if !expiry.Warned && !now.Before(expiry.Date.Add(-warningDelay)) {
+		return actionWarn
+	}
+	return actionNone
+}
+
+func (j *janitor) warn(ctx context.Context, deploymentID string, expiry *deployments.DeploymentExpiry) error {
+	_, err := events.PublishAndLogDeploymentExpiry(ctx, deploymentID, expiry.Date, StatusExpiring)
+	if err != nil {
+		return err
+	}
+	j.notify(ctx, deploymentID, expiry, StatusExpiring, "")
+	return deployments.SetDeploymentExpiryWarned(ctx, deploymentID, expiry.Date)
+}
+
+func (j *janitor) expire(ctx context.Context, deploymentID string, expiry *deployments.DeploymentExpiry) error {
+	taskType := tasks.TaskTypeUnDeploy
+	if expiry.Purge {
+		taskType = tasks.TaskTypePurge
+	}
+	data := map[string]string{
+		"workflowName":    "uninstall",
+		"continueOnError": "true",
+	}
+	taskID, err := j.collector.RegisterTaskWithData(deploymentID, taskType, data)
+	if err != nil {
+		if ok, _ := tasks.IsAnotherLivingTaskAlreadyExistsError(err); ok {
+			// The deployment will be expired at next check once the running task is done
+			log.Debugf("Deployment %q expired but another task is running, its expiry is delayed", deploymentID)
+			return nil
+		}
+		if !expiry.Purge || !deployments.IsInconsistentDeploymentError(err) {
+			return err
+		}
+		log.Debugf("Inconsistent expired deployment with ID:%q. We force purge it.", deploymentID)
+		taskID, err = j.collector.RegisterTask(deploymentID, tasks.TaskTypeForcePurge)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = events.PublishAndLogDeploymentExpiry(ctx, deploymentID, expiry.Date, StatusExpired)
+	if err != nil {
+		log.Printf("[WARN] Failed to publish expiry of deployment %q: %v", deploymentID, err)
+	}
+	j.notify(ctx, deploymentID, expiry, StatusExpired, taskID)
+	return deployments.SetDeploymentExpiryTaskID(ctx, deploymentID, expiry.Date, taskID)
+}
@@ -0,0 +1,83 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expiry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/deployments"
+)
+
+func TestNextAction(t *testing.T) {
+	date := time.Date(2019, time.November, 4, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		expiry deployments.DeploymentExpiry
+		status deployments.DeploymentStatus
+		now    time.Time
+		want   action
+	}{
+		{"NotExpiringYet", deployments.DeploymentExpiry{Date: date}, deployments.DEPLOYED, date.Add(-2 * time.Hour), actionNone},
+		{"Expiring", deployments.DeploymentExpiry{Date: date}, deployments.DEPLOYED, date.Add(-time.Hour), actionWarn},
+		{"AlreadyWarned", deployments.DeploymentExpiry{Date: date, Warned: true}, deployments.DEPLOYED, date.Add(-time.Minute), actionNone},
+		{"Expired", deployments.DeploymentExpiry{Date: date, Warned: true}, deployments.DEPLOYED, date, actionExpire},
+		{"ExpiredWithoutWarning", deployments.DeploymentExpiry{Date: date}, deployments.DEPLOYMENT_FAILED, date.Add(time.Hour), actionExpire},
+		{"AlreadyExpired", deployments.DeploymentExpiry{Date: date, TaskID: "task1"}, deployments.UNDEPLOYMENT_IN_PROGRESS, date.Add(time.Hour), actionNone},
+		{"Undeployed", deployments.DeploymentExpiry{Date: date}, deployments.UNDEPLOYED, date.Add(time.Hour), actionNone},
+		{"UndeployedToPurge", deployments.DeploymentExpiry{Date: date, Purge: true}, deployments.UNDEPLOYED, date.Add(time.Hour), actionExpire},
+		{"Purging", deployments.DeploymentExpiry{Date: date, Purge: true}, deployments.PURGE_IN_PROGRESS, date.Add(time.Hour), actionNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, nextAction(&tt.expiry, tt.status, tt.now, time.Hour))
+		})
+	}
+}
+
+func TestPostNotification(t *testing.T) {
+	var received Notification
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fails" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	n := Notification{
+		DeploymentID: "dep1",
+		Status:       StatusExpired,
+		ExpiryDate:   time.Date(2019, time.November, 4, 10, 0, 0, 0, time.UTC),
+		Purge:        true,
+		TaskID:       "task1",
+		Labels:       map[string]string{"env": "dev"},
+	}
+	err := postNotification(context.Background(), ts.Client(), ts.URL+"/hook", n)
+	require.NoError(t, err)
+	require.Equal(t, n, received)
+
+	err = postNotification(context.Background(), ts.Client(), ts.URL+"/fails", n)
+	require.Error(t, err)
+}
@@ -0,0 +1,87 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expiry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/log"
+)
+
+// Notification is posted as JSON to the configured webhooks when a deployment is about to expire and when it expires
+type Notification struct {
+	DeploymentID string            `json:"deployment_id"`
+	Status       string            `json:"status"`
+	ExpiryDate   time.Time         `json:"expiry_date"`
+	Purge        bool              `json:"purge"`
+	TaskID       string            `json:"task_id,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+}
+
+func (j *janitor) notify(ctx context.Context, deploymentID string, expiry *deployments.DeploymentExpiry, status, taskID string) {
+	if len(j.cfg.DeploymentsExpiry.Webhooks) == 0 {
+		return
+	}
+	n := Notification{
+		DeploymentID: deploymentID,
+		Status:       status,
+		ExpiryDate:   expiry.Date,
+		Purge:        expiry.Purge,
+		TaskID:       taskID,
+	}
+	labels, err := deployments.GetDeploymentLabels(ctx, deploymentID)
+	if err != nil {
+		log.Printf("[WARN] Failed to get labels of deployment %q: %v", deploymentID, err)
+	}
+	if len(labels) > 0 {
+		n.Labels = labels
+	}
+	for _, url := range j.cfg.DeploymentsExpiry.Webhooks {
+		err = postNotification(ctx, j.httpClient, url, n)
+		if err != nil {
+			log.Printf("[WARN] Failed to notify webhook %q of deployment %q expiry: %v", url, deploymentID, err)
+		}
+	}
+}
+
+// postNotification posts a notification to a webhook, webhooks are expected to answer with a 2xx status code
+func postNotification(ctx context.Context, client *http.Client, url string, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal notification")
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "invalid webhook request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "webhook request failed")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("unexpected webhook response status %q", resp.Status)
+	}
+	return nil
+}
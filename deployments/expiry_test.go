@@ -0,0 +1,63 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/testutil"
+)
+
+func testDeploymentExpiry(t *testing.T) {
+	ctx := context.Background()
+	deploymentID := testutil.BuildDeploymentID(t)
+
+	expiry, err := GetDeploymentExpiry(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Nil(t, expiry)
+
+	date := time.Date(2019, time.November, 4, 10, 0, 0, 0, time.UTC)
+	err = SetDeploymentExpiry(ctx, deploymentID, date, true)
+	require.NoError(t, err)
+	expiry, err = GetDeploymentExpiry(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Equal(t, &DeploymentExpiry{Date: date, Purge: true}, expiry)
+
+	err = SetDeploymentExpiryWarned(ctx, deploymentID, date)
+	require.NoError(t, err)
+	err = SetDeploymentExpiryTaskID(ctx, deploymentID, date, "task1")
+	require.NoError(t, err)
+	expiry, err = GetDeploymentExpiry(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Equal(t, &DeploymentExpiry{Date: date, Purge: true, Warned: true, TaskID: "task1"}, expiry)
+
+	// Extending the deployment resets the warning and the task
+	extended := date.Add(24 * time.Hour)
+	err = SetDeploymentExpiry(ctx, deploymentID, extended, false)
+	require.NoError(t, err)
+	expiry, err = GetDeploymentExpiry(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Equal(t, &DeploymentExpiry{Date: extended}, expiry)
+
+	err = RemoveDeploymentExpiry(ctx, deploymentID)
+	require.NoError(t, err)
+	expiry, err = GetDeploymentExpiry(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Nil(t, expiry)
+}
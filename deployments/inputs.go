@@ -16,11 +16,36 @@ package deployments
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/ystia/yorc/v4/tosca"
 )
 
+type invalidParameterValueError struct {
+	kind   string
+	name   string
+	reason string
+}
+
+func (e invalidParameterValueError) Error() string {
+	if e.reason == "" {
+		return fmt.Sprintf("Missing value for required %s parameter %s", e.kind, e.name)
+	}
+	return fmt.Sprintf("Invalid value for %s parameter %s: %s", e.kind, e.name, e.reason)
+}
+
+// IsInvalidParameterValueError checks if an error is due to a parameter value that doesn't
+// match its definition (missing required value, wrong type or unmet constraint)
+func IsInvalidParameterValueError(err error) bool {
+	_, ok := errors.Cause(err).(invalidParameterValueError)
+	return ok
+}
+
 // GetInputValue tries to retrieve the value of the given input name.
 //
 // GetInputValue first checks if a non-empty field value exists for this input, if it doesn't then it checks for a non-empty field default.
@@ -60,3 +85,203 @@ func GetInputValue(ctx context.Context, inputs map[string]tosca.ParameterDefinit
 	}
 	return result.RawString(), nil
 }
+
+// ValidateWorkflowInputs checks the given inputs values against the inputs definitions
+// of a workflow and returns them encoded as strings.
+//
+// Required inputs without default value should be provided. Values should match
+// the type of their definition and satisfy its constraints. Lists, maps and complex
+// values are JSON encoded. Null values are ignored. Inputs that are not defined
+// by the workflow are kept as is for backward compatibility.
+// An error checkable using IsInvalidParameterValueError is returned if an input is not valid.
+func ValidateWorkflowInputs(ctx context.Context, deploymentID, workflowName string, inputs map[string]interface{}) (map[string]string, error) {
+	wf, err := GetWorkflow(ctx, deploymentID, workflowName)
+	if err != nil {
+		return nil, err
+	}
+	if wf == nil {
+		return nil, errors.Errorf("Can't check inputs of workflow %q in deployment %q, workflow definition not found", workflowName, deploymentID)
+	}
+	defs := make(map[string]tosca.ParameterDefinition, len(wf.Inputs))
+	for inputName, propDef := range wf.Inputs {
+		defs[inputName] = tosca.ParameterDefinition{
+			Type:        propDef.Type,
+			Required:    propDef.Required,
+			Default:     propDef.Default,
+			Constraints: propDef.Constraints,
+			EntrySchema: propDef.EntrySchema,
+		}
+	}
+	return validateParametersValues(ctx, deploymentID, "workflow input", defs, inputs)
+}
+
+// ValidateSignalInputs checks the given inputs values against the inputs definitions
+// of a wait_for_signal activity and returns them encoded as strings.
+//
+// See ValidateWorkflowInputs for details on the checks that are performed.
+func ValidateSignalInputs(ctx context.Context, deploymentID string, signal *tosca.SignalActivity, inputs map[string]interface{}) (map[string]string, error) {
+	return validateParametersValues(ctx, deploymentID, "signal input", signal.Inputs, inputs)
+}
+
+func validateParametersValues(ctx context.Context, deploymentID, kind string, defs map[string]tosca.ParameterDefinition, values map[string]interface{}) (map[string]string, error) {
+	// Sort names to report errors in a deterministic way
+	names := make([]string, 0, len(defs))
+	for name := range defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		def := defs[name]
+		value, found := values[name]
+		if !found || value == nil {
+			// A property is considered as required by default, unless def.Required
+			// is set to false
+			if (def.Required == nil || *def.Required) && def.Default == nil {
+				return nil, errors.WithStack(invalidParameterValueError{kind: kind, name: name})
+			}
+			continue
+		}
+		reason, err := checkInputValue(ctx, deploymentID, def.Type, def.EntrySchema.Type, def.Constraints, value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to check value of %s %q", kind, name)
+		}
+		if reason != "" {
+			return nil, errors.WithStack(invalidParameterValueError{kind: kind, name: name, reason: reason})
+		}
+	}
+
+	results := make(map[string]string, len(values))
+	for name, value := range values {
+		if value == nil {
+			continue
+		}
+		s, err := encodeInputValue(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to encode value of %s %q", kind, name)
+		}
+		results[name] = s
+	}
+	return results, nil
+}
+
+// checkInputValue checks that a value matches a given type and satisfies constraints.
+//
+// It returns a non-empty reason if the value is not valid. An error is returned
+// only if the check itself failed.
+func checkInputValue(ctx context.Context, deploymentID, typeName, entrySchema string, constraints []tosca.ConstraintClause, value interface{}) (string, error) {
+	if typeName == "" || value == nil {
+		return "", nil
+	}
+	baseType, err := getInputBaseType(ctx, deploymentID, typeName)
+	if err != nil {
+		return "", err
+	}
+	switch val := value.(type) {
+	case []interface{}:
+		if baseType == "range" {
+			if _, err := tosca.ParseRange(val); err != nil {
+				return err.Error(), nil
+			}
+			return "", nil
+		}
+		if baseType != "list" {
+			return fmt.Sprintf("expecting a value of type %q, got a list", typeName), nil
+		}
+		for i, elem := range val {
+			reason, err := checkInputValue(ctx, deploymentID, entrySchema, "", nil, elem)
+			if err != nil || reason != "" {
+				return prefixReason(fmt.Sprintf("[%d]", i), reason), err
+			}
+		}
+	case map[string]interface{}:
+		switch baseType {
+		case "map":
+			for k, elem := range val {
+				reason, err := checkInputValue(ctx, deploymentID, entrySchema, "", nil, elem)
+				if err != nil || reason != "" {
+					return prefixReason(k, reason), err
+				}
+			}
+		case "":
+			for k, elem := range val {
+				propDef, err := getTypePropertyDefinition(ctx, deploymentID, typeName, k)
+				if err != nil {
+					return "", err
+				}
+				if propDef == nil {
+					return fmt.Sprintf("unknown property %q for data type %q", k, typeName), nil
+				}
+				reason, err := checkInputValue(ctx, deploymentID, propDef.Type, propDef.EntrySchema.Type, propDef.Constraints, elem)
+				if err != nil || reason != "" {
+					return prefixReason(k, reason), err
+				}
+			}
+		default:
+			return fmt.Sprintf("expecting a value of type %q, got a map", typeName), nil
+		}
+	default:
+		if baseType == "" || baseType == "list" || baseType == "map" {
+			return fmt.Sprintf("expecting a value of type %q, got %q", typeName, fmt.Sprint(val)), nil
+		}
+		s, err := encodeInputValue(val)
+		if err != nil {
+			return "", err
+		}
+		if err = tosca.CheckLiteralValue(baseType, s); err != nil {
+			return err.Error(), nil
+		}
+		for _, constraint := range constraints {
+			ok, err := constraint.Evaluate(s)
+			if err != nil {
+				return "", err
+			}
+			if !ok {
+				return fmt.Sprintf("value %q doesn't satisfy constraint %s: %v", s, constraint.Operator, constraint.Value), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// getInputBaseType returns the primitive type a given type derives from,
+// list or map for those types or an empty string for complex data types
+func getInputBaseType(ctx context.Context, deploymentID, typeName string) (string, error) {
+	for typeName != "" {
+		if strings.HasPrefix(typeName, "list") {
+			return "list", nil
+		}
+		if strings.HasPrefix(typeName, "map") {
+			return "map", nil
+		}
+		if tosca.IsPrimitiveType(typeName) {
+			return typeName, nil
+		}
+		parent, err := GetParentType(ctx, deploymentID, typeName)
+		if err != nil {
+			return "", err
+		}
+		typeName = parent
+	}
+	return "", nil
+}
+
+func prefixReason(prefix, reason string) string {
+	if reason == "" {
+		return ""
+	}
+	return prefix + ": " + reason
+}
+
+func encodeInputValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case float64:
+		// Avoid exponent notation for numbers decoded from JSON
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case []interface{}, map[string]interface{}:
+		b, err := json.Marshal(v)
+		return string(b), errors.WithStack(err)
+	}
+	return fmt.Sprint(value), nil
+}
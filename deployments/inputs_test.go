@@ -0,0 +1,80 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testValidateWorkflowInputs(t *testing.T) {
+	ctx := context.Background()
+	deploymentID := strings.Replace(t.Name(), "/", "_", -1)
+	err := StoreDeploymentDefinition(ctx, deploymentID, "testdata/workflow_inputs.yaml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		inputs         map[string]interface{}
+		want           map[string]string
+		wantInvalidErr bool
+	}{
+		{"RequiredOnly", map[string]interface{}{"replicas": float64(3)}, map[string]string{"replicas": "3"}, false},
+		{"AllInputs", map[string]interface{}{
+			"replicas": "2",
+			"mode":     "recreate",
+			"hosts":    []interface{}{"h1", "h2"},
+			"endpoint": map[string]interface{}{"host": "h1", "port": float64(8080)},
+			"release":  "1.2.3",
+			"unknown":  true,
+		}, map[string]string{
+			"replicas": "2",
+			"mode":     "recreate",
+			"hosts":    `["h1","h2"]`,
+			"endpoint": `{"host":"h1","port":8080}`,
+			"release":  "1.2.3",
+			"unknown":  "true",
+		}, false},
+		{"MissingRequired", map[string]interface{}{"mode": "rolling"}, nil, true},
+		{"NullRequired", map[string]interface{}{"replicas": nil}, nil, true},
+		{"WrongType", map[string]interface{}{"replicas": "three"}, nil, true},
+		{"UnmetConstraint", map[string]interface{}{"replicas": float64(0)}, nil, true},
+		{"NotAValidValue", map[string]interface{}{"replicas": float64(1), "mode": "blue-green"}, nil, true},
+		{"ListExpected", map[string]interface{}{"replicas": float64(1), "hosts": "h1"}, nil, true},
+		{"WrongListEntry", map[string]interface{}{"replicas": float64(1), "hosts": []interface{}{"h1", []interface{}{"h2"}}}, nil, true},
+		{"UnknownComplexProperty", map[string]interface{}{"replicas": float64(1), "endpoint": map[string]interface{}{"url": "h1"}}, nil, true},
+		{"UnmetComplexPropertyConstraint", map[string]interface{}{"replicas": float64(1), "endpoint": map[string]interface{}{"port": float64(0)}}, nil, true},
+		{"DerivedPrimitiveType", map[string]interface{}{"replicas": float64(1), "release": "latest"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateWorkflowInputs(ctx, deploymentID, "maintenance", tt.inputs)
+			if tt.wantInvalidErr {
+				require.Error(t, err)
+				require.True(t, IsInvalidParameterValueError(err), "unexpected error %v", err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err = ValidateWorkflowInputs(ctx, deploymentID, "missing", nil)
+	require.Error(t, err)
+	require.False(t, IsInvalidParameterValueError(err))
+}
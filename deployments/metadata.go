@@ -0,0 +1,122 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/helper/consulutil"
+)
+
+// Kinds of deployment metadata
+const (
+	// labels are user-defined key/value pairs used to select deployments
+	metadataLabels = "labels"
+	// annotations are user-defined key/value pairs not used to select deployments
+	metadataAnnotations = "annotations"
+)
+
+// metadataTxnMaxOps is the maximum number of labels or annotations changed in a single Consul transaction
+const metadataTxnMaxOps = 64
+
+func metadataPath(deploymentID, kind string) string {
+	return path.Join(consulutil.DeploymentKVPrefix, deploymentID, "metadata", kind)
+}
+
+// GetDeploymentLabels returns the labels of a deployment
+func GetDeploymentLabels(ctx context.Context, deploymentID string) (map[string]string, error) {
+	return getDeploymentMetadata(deploymentID, metadataLabels)
+}
+
+// GetDeploymentAnnotations returns the annotations of a deployment
+func GetDeploymentAnnotations(ctx context.Context, deploymentID string) (map[string]string, error) {
+	return getDeploymentMetadata(deploymentID, metadataAnnotations)
+}
+
+// UpdateDeploymentLabels adds or replaces the given labels of a deployment and removes the labels named in removed
+func UpdateDeploymentLabels(ctx context.Context, deploymentID string, labels map[string]string, removed []string) error {
+	return updateDeploymentMetadata(deploymentID, metadataLabels, labels, removed)
+}
+
+// UpdateDeploymentAnnotations adds or replaces the given annotations of a deployment and removes the annotations named in removed
+func UpdateDeploymentAnnotations(ctx context.Context, deploymentID string, annotations map[string]string, removed []string) error {
+	return updateDeploymentMetadata(deploymentID, metadataAnnotations, annotations, removed)
+}
+
+// FormatLabels returns labels as a comma-separated list of name=value pairs sorted by name
+func FormatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func getDeploymentMetadata(deploymentID, kind string) (map[string]string, error) {
+	kvs, err := consulutil.List(metadataPath(deploymentID, kind) + "/")
+	if err != nil {
+		return nil, err
+	}
+	metadata := make(map[string]string, len(kvs))
+	for key, value := range kvs {
+		name, err := url.PathUnescape(path.Base(key))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s name %q for deployment %q", kind, path.Base(key), deploymentID)
+		}
+		metadata[name] = string(value)
+	}
+	return metadata, nil
+}
+
+func updateDeploymentMetadata(deploymentID, kind string, added map[string]string, removed []string) error {
+	ops := make(api.KVTxnOps, 0, len(added)+len(removed))
+	for _, name := range removed {
+		if name == "" {
+			return errors.Errorf("empty %s names are not allowed", kind)
+		}
+		ops = append(ops, &api.KVTxnOp{
+			Verb: api.KVDelete,
+			Key:  path.Join(metadataPath(deploymentID, kind), url.PathEscape(name)),
+		})
+	}
+	for name, value := range added {
+		if name == "" {
+			return errors.Errorf("empty %s names are not allowed", kind)
+		}
+		ops = append(ops, &api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(metadataPath(deploymentID, kind), url.PathEscape(name)),
+			Value: []byte(value),
+		})
+	}
+	for begin := 0; begin < len(ops); begin += metadataTxnMaxOps {
+		end := begin + metadataTxnMaxOps
+		if end > len(ops) {
+			end = len(ops)
+		}
+		if err := consulutil.ExecuteSplittableTransaction(ops[begin:end], nil, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/testutil"
+)
+
+func TestFormatLabels(t *testing.T) {
+	require.Equal(t, "", FormatLabels(nil))
+	require.Equal(t, "env=prod,team=hpc", FormatLabels(map[string]string{"team": "hpc", "env": "prod"}))
+}
+
+func testDeploymentMetadata(t *testing.T) {
+	ctx := context.Background()
+	deploymentID := testutil.BuildDeploymentID(t)
+
+	labels, err := GetDeploymentLabels(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Empty(t, labels)
+
+	err = UpdateDeploymentLabels(ctx, deploymentID, map[string]string{"env": "dev", "team": "hpc", "app.kubernetes.io/name": "app"}, nil)
+	require.NoError(t, err)
+	err = UpdateDeploymentLabels(ctx, deploymentID, map[string]string{"env": "prod"}, []string{"team", "unknown"})
+	require.NoError(t, err)
+	labels, err = GetDeploymentLabels(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"env": "prod", "app.kubernetes.io/name": "app"}, labels)
+
+	err = UpdateDeploymentLabels(ctx, deploymentID, map[string]string{"": "empty"}, nil)
+	require.Error(t, err)
+
+	// More changes than a single transaction allows
+	annotations := make(map[string]string)
+	for i := 0; i < 100; i++ {
+		annotations[fmt.Sprintf("annotation%d", i)] = fmt.Sprint(i)
+	}
+	err = UpdateDeploymentAnnotations(ctx, deploymentID, annotations, nil)
+	require.NoError(t, err)
+	got, err := GetDeploymentAnnotations(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Equal(t, annotations, got)
+
+	// Labels and annotations are distinct
+	labels, err = GetDeploymentLabels(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Len(t, labels, 2)
+}
@@ -49,6 +49,22 @@ func IsNodeNotFoundError(err error) bool {
 	return ok
 }
 
+type instancesLimitReachedError struct {
+	msg string
+}
+
+func (e instancesLimitReachedError) Error() string {
+	return e.msg
+}
+
+// IsInstancesLimitReachedError checks if the given error is returned when a node can't be scaled as it reached
+// its maximum or minimum number of instances
+func IsInstancesLimitReachedError(err error) bool {
+	cause := errors.Cause(err)
+	_, ok := cause.(instancesLimitReachedError)
+	return ok
+}
+
 func getNodeTemplate(ctx context.Context, deploymentID, nodeName string) (*tosca.NodeTemplate, error) {
 	node := new(tosca.NodeTemplate)
 	nodePath := path.Join(consulutil.DeploymentKVPrefix, deploymentID, "topology", "nodes", nodeName)
@@ -155,6 +171,52 @@ func GetNbInstancesForNode(ctx context.Context, deploymentID, nodeName string) (
 	return uint32(len(keys)), nil
 }
 
+// GetScaleOutInstancesDelta returns the number of instances to add to a node for a scale out of instancesDelta instances.
+//
+// The returned delta is lowered if the node would exceed its maximum number of instances. An error checked by
+// IsInstancesLimitReachedError is returned if the node already reached its maximum number of instances.
+func GetScaleOutInstancesDelta(ctx context.Context, deploymentID, nodeName string, instancesDelta uint32) (uint32, error) {
+	maxInstances, err := GetMaxNbInstancesForNode(ctx, deploymentID, nodeName)
+	if err != nil {
+		return 0, err
+	}
+	currentNbInstance, err := GetNbInstancesForNode(ctx, deploymentID, nodeName)
+	if err != nil {
+		return 0, err
+	}
+	if currentNbInstance >= maxInstances {
+		return 0, errors.WithStack(instancesLimitReachedError{"Maximum number of instances reached"})
+	}
+	if currentNbInstance+instancesDelta > maxInstances {
+		log.Debug("The delta is too high, the max instances number is chosen")
+		instancesDelta = maxInstances - currentNbInstance
+	}
+	return instancesDelta, nil
+}
+
+// GetScaleInInstancesDelta returns the number of instances to remove from a node for a scale in of instancesDelta instances.
+//
+// The returned delta is lowered if the node would go below its minimum number of instances. An error checked by
+// IsInstancesLimitReachedError is returned if the node already reached its minimum number of instances.
+func GetScaleInInstancesDelta(ctx context.Context, deploymentID, nodeName string, instancesDelta uint32) (uint32, error) {
+	minInstances, err := GetMinNbInstancesForNode(ctx, deploymentID, nodeName)
+	if err != nil {
+		return 0, err
+	}
+	currentNbInstance, err := GetNbInstancesForNode(ctx, deploymentID, nodeName)
+	if err != nil {
+		return 0, err
+	}
+	if currentNbInstance <= minInstances {
+		return 0, errors.WithStack(instancesLimitReachedError{"Minimum number of instances reached"})
+	}
+	if currentNbInstance < minInstances+instancesDelta {
+		log.Debug("The delta is too low, the min instances number is chosen")
+		instancesDelta = currentNbInstance - minInstances
+	}
+	return instancesDelta, nil
+}
+
 // GetNodeInstancesIds returns the names of the different instances for a given node.
 //
 // It may be an empty array if the given node is not HostedOn a scalable node.
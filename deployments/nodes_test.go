@@ -385,6 +385,9 @@ func testDeploymentNodes(t *testing.T, srv1 *ctu.TestServer) {
 		t.Run("TesttestGetMinNbInstancesForNode", func(t *testing.T) {
 			testGetMinNbInstancesForNode(t)
 		})
+		t.Run("TestGetScaleInstancesDelta", func(t *testing.T) {
+			testGetScaleInstancesDelta(t)
+		})
 		t.Run("TestGetNodeProperty", func(t *testing.T) {
 			testGetNodeProperty(t)
 		})
@@ -505,6 +508,29 @@ func testGetMinNbInstancesForNode(t *testing.T) {
 	require.Equal(t, uint32(1), nb)
 }
 
+func testGetScaleInstancesDelta(t *testing.T) {
+	ctx := context.Background()
+	_, err := GetScaleOutInstancesDelta(ctx, "testGetNbInstancesForNode", "Compute1", 1)
+	require.Error(t, err)
+	require.True(t, IsInstancesLimitReachedError(err))
+
+	delta, err := GetScaleOutInstancesDelta(ctx, "testGetNbInstancesForNode", "Compute2", 5)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), delta)
+
+	delta, err = GetScaleInInstancesDelta(ctx, "testGetNbInstancesForNode", "Compute1", 5)
+	require.NoError(t, err)
+	require.Equal(t, uint32(5), delta)
+
+	delta, err = GetScaleInInstancesDelta(ctx, "testGetNbInstancesForNode", "Compute1", 30)
+	require.NoError(t, err)
+	require.Equal(t, uint32(18), delta)
+
+	_, err = GetScaleInInstancesDelta(ctx, "testGetNbInstancesForNode", "Compute2", 1)
+	require.Error(t, err)
+	require.True(t, IsInstancesLimitReachedError(err))
+}
+
 func testGetNodeProperty(t *testing.T) {
 	// t.Parallel()
 	ctx := context.Background()
@@ -198,6 +198,51 @@ func getOperationAndInterfaceDefinitions(ctx context.Context, deploymentID, node
 	return nil, nil, nil
 }
 
+// GetInterfaceNotifications returns the notifications definitions of a given interface for a node
+//
+// Notifications are looked up in the node type hierarchy (parent types first so they could
+// be overridden by child types) and then in the node template itself.
+func GetInterfaceNotifications(ctx context.Context, deploymentID, nodeName, interfaceName string) (map[string]tosca.NotificationDefinition, error) {
+	node, err := getNodeTemplate(ctx, deploymentID, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	notifications, err := getTypeInterfaceNotifications(ctx, deploymentID, node.Type, interfaceName)
+	if err != nil {
+		return nil, err
+	}
+	if ifDef := getInterface(interfaceName, node.Interfaces); ifDef != nil {
+		for k, v := range ifDef.Notifications {
+			notifications[k] = v
+		}
+	}
+	return notifications, nil
+}
+
+func getTypeInterfaceNotifications(ctx context.Context, deploymentID, typeName, interfaceName string) (map[string]tosca.NotificationDefinition, error) {
+	parentType, err := GetParentType(ctx, deploymentID, typeName)
+	if err != nil {
+		return nil, err
+	}
+	notifications := make(map[string]tosca.NotificationDefinition)
+	if parentType != "" {
+		notifications, err = getTypeInterfaceNotifications(ctx, deploymentID, parentType, interfaceName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	interfaces, err := getTypeInterfaces(ctx, deploymentID, typeName)
+	if err != nil {
+		return nil, err
+	}
+	if ifDef := getInterface(interfaceName, interfaces); ifDef != nil {
+		for k, v := range ifDef.Notifications {
+			notifications[k] = v
+		}
+	}
+	return notifications, nil
+}
+
 func getInterface(key string, m map[string]tosca.InterfaceDefinition) *tosca.InterfaceDefinition {
 
 	for k, v := range m {
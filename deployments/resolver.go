@@ -17,12 +17,14 @@ package deployments
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/ystia/yorc/v4/events"
 	"github.com/ystia/yorc/v4/helper/collections"
+	"github.com/ystia/yorc/v4/helper/redactutil"
 	"github.com/ystia/yorc/v4/log"
 	"github.com/ystia/yorc/v4/tosca"
 	"github.com/ystia/yorc/v4/vault"
@@ -91,32 +93,9 @@ func (fr *functionResolver) resolveFunction(ctx context.Context, fn *tosca.Funct
 	if fn == nil {
 		return nil, errors.Errorf("Trying to resolve a nil function")
 	}
-	operands := make([]string, len(fn.Operands))
-	var hasSecret bool
-	for i, op := range fn.Operands {
-		if op.IsLiteral() {
-			var err error
-			s := op.String()
-			if isQuoted(s) {
-				s, err = strconv.Unquote(s)
-				if err != nil {
-					return nil, errors.Wrapf(err, "failed to unquote literal operand of function %v", fn)
-				}
-			}
-			operands[i] = s
-		} else {
-			subFn := op.(*tosca.Function)
-			r, err := fr.resolveFunction(ctx, subFn)
-			if err != nil {
-				return nil, err
-			}
-			if r != nil {
-				if r.IsSecret {
-					hasSecret = true
-				}
-				operands[i] = r.RawString()
-			}
-		}
+	operands, lists, hasSecret, err := fr.resolveOperands(ctx, fn, fn.Operands)
+	if err != nil {
+		return nil, err
 	}
 	switch fn.Operator {
 	case tosca.ConcatOperator:
@@ -125,7 +104,7 @@ func (fr *functionResolver) resolveFunction(ctx context.Context, fn *tosca.Funct
 		res, err := fr.resolveGetInput(ctx, operands)
 		return &TOSCAValue{Value: res}, err
 	case tosca.GetSecretOperator:
-		res, err := fr.resolveGetSecret(operands)
+		res, err := fr.resolveGetSecret(ctx, operands)
 		return &TOSCAValue{Value: res, IsSecret: true}, err
 	case tosca.GetOperationOutputOperator:
 		res, err := fr.resolveGetOperationOutput(ctx, operands)
@@ -142,10 +121,166 @@ func (fr *functionResolver) resolveFunction(ctx context.Context, fn *tosca.Funct
 			res.IsSecret = true
 		}
 		return res, err
+	case tosca.TokenOperator:
+		res, err := fr.resolveToken(operands)
+		return &TOSCAValue{Value: res, IsSecret: hasSecret}, err
+	case tosca.JoinOperator:
+		res, err := fr.resolveJoin(operands, lists)
+		return &TOSCAValue{Value: res, IsSecret: hasSecret}, err
+	case tosca.GetNodesOfTypeOperator:
+		res, err := fr.resolveGetNodesOfType(ctx, operands)
+		return &TOSCAValue{Value: res}, err
+	case tosca.GetArtifactOperator:
+		res, err := fr.resolveGetArtifact(ctx, operands)
+		return &TOSCAValue{Value: res}, err
 	}
 	return nil, errors.Errorf("Unsupported function %q", string(fn.Operator))
 }
 
+// resolveOperands resolves the given operands as strings.
+//
+// Operands that are lists are also returned resolved as a list of strings indexed by their position
+// in the operands list.
+func (fr *functionResolver) resolveOperands(ctx context.Context, fn *tosca.Function, ops []tosca.Operand) ([]string, map[int][]string, bool, error) {
+	operands := make([]string, len(ops))
+	lists := make(map[int][]string)
+	var hasSecret bool
+	for i, op := range ops {
+		switch v := op.(type) {
+		case tosca.ListOperand:
+			l, _, secret, err := fr.resolveOperands(ctx, fn, v)
+			if err != nil {
+				return nil, nil, false, err
+			}
+			hasSecret = hasSecret || secret
+			lists[i] = l
+			operands[i] = strings.Join(l, ",")
+		case *tosca.Function:
+			r, err := fr.resolveFunction(ctx, v)
+			if err != nil {
+				return nil, nil, false, err
+			}
+			if r != nil {
+				if r.IsSecret {
+					hasSecret = true
+				}
+				operands[i] = r.RawString()
+			}
+		default:
+			var err error
+			s := op.String()
+			if isQuoted(s) {
+				s, err = strconv.Unquote(s)
+				if err != nil {
+					return nil, nil, false, errors.Wrapf(err, "failed to unquote literal operand of function %v", fn)
+				}
+			}
+			operands[i] = s
+		}
+	}
+	return operands, lists, hasSecret, nil
+}
+
+func (fr *functionResolver) resolveToken(operands []string) (string, error) {
+	if len(operands) != 3 {
+		return "", errors.Errorf("expecting exactly three parameters for a token function")
+	}
+	index, err := strconv.Atoi(operands[2])
+	if err != nil || index < 0 {
+		return "", errors.Errorf("expecting a positive integer as substring index of a token function, got %q", operands[2])
+	}
+	tokens := strings.FieldsFunc(operands[0], func(r rune) bool {
+		return strings.ContainsRune(operands[1], r)
+	})
+	if index >= len(tokens) {
+		return "", errors.Errorf("token function: index %d out of range, %q has only %d tokens", index, operands[0], len(tokens))
+	}
+	return tokens[index], nil
+}
+
+func (fr *functionResolver) resolveJoin(operands []string, lists map[int][]string) (string, error) {
+	if len(operands) < 1 || len(operands) > 2 {
+		return "", errors.Errorf("expecting one or two parameters for a join function")
+	}
+	values, ok := lists[0]
+	if !ok {
+		return "", errors.Errorf("expecting a list of string value expressions as first parameter of a join function")
+	}
+	var delimiter string
+	if len(operands) == 2 {
+		delimiter = operands[1]
+	}
+	return strings.Join(values, delimiter), nil
+}
+
+func (fr *functionResolver) resolveGetNodesOfType(ctx context.Context, operands []string) ([]string, error) {
+	if len(operands) != 1 {
+		return nil, errors.Errorf("expecting exactly one parameter for a get_nodes_of_type function")
+	}
+	nodes, err := GetNodes(ctx, fr.deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, 0)
+	for _, node := range nodes {
+		ok, err := IsNodeDerivedFrom(ctx, fr.deploymentID, node, operands[0])
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result = append(result, node)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func (fr *functionResolver) resolveGetArtifact(ctx context.Context, operands []string) (string, error) {
+	if len(operands) < 2 || len(operands) > 4 {
+		return "", errors.Errorf("expecting between two and four parameters for a get_artifact function")
+	}
+	if len(operands) > 2 && operands[2] != "" && operands[2] != "LOCAL_FILE" {
+		return "", errors.Errorf("get_artifact function: only LOCAL_FILE location is supported, got %q", operands[2])
+	}
+	entity := operands[0]
+	var err error
+	var actualNode string
+	switch entity {
+	case funcKeywordSELF:
+		actualNode = fr.nodeName
+	case funcKeywordHOST:
+		actualNode, err = GetHostedOnNode(ctx, fr.deploymentID, fr.nodeName)
+		if err != nil {
+			return "", err
+		}
+	case funcKeywordSOURCE, funcKeywordTARGET, funcKeywordRTARGET:
+		if fr.requirementIndex == "" {
+			return "", errors.Errorf("get_artifact function: %s keyword is supported only in the context of a relationship", entity)
+		}
+		actualNode = fr.nodeName
+		if entity != funcKeywordSOURCE {
+			actualNode, err = GetTargetNodeForRequirement(ctx, fr.deploymentID, fr.nodeName, fr.requirementIndex)
+			if err != nil {
+				return "", err
+			}
+		}
+	default:
+		actualNode = entity
+	}
+	if actualNode == "" {
+		return "", errors.Errorf("get_artifact function: can't resolve entity %q without a specified node name", entity)
+	}
+	artifacts, err := GetFileArtifactsForNode(ctx, fr.deploymentID, actualNode)
+	if err != nil {
+		return "", err
+	}
+	artifact, ok := artifacts[operands[1]]
+	if !ok {
+		return "", errors.Errorf("get_artifact function: no artifact named %q found for node %q", operands[1], actualNode)
+	}
+	return artifact, nil
+}
+
 func (fr *functionResolver) resolveGetInput(ctx context.Context, operands []string) (string, error) {
 	if len(operands) < 1 {
 		return "", errors.Errorf("expecting at least one parameter for a get_input function")
@@ -352,7 +487,7 @@ func isQuoted(s string) bool {
 	return len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"'
 }
 
-func (fr *functionResolver) resolveGetSecret(operands []string) (string, error) {
+func (fr *functionResolver) resolveGetSecret(ctx context.Context, operands []string) (string, error) {
 	if len(operands) < 1 {
 		return "", errors.New("expecting at least one parameter for a get_secret function")
 	}
@@ -368,5 +503,20 @@ func (fr *functionResolver) resolveGetSecret(operands []string) (string, error)
 	if err != nil {
 		return "", err
 	}
-	return secret.String(), nil
+	value := secret.String()
+	// Ensure this value never shows up in deployment logs
+	redactutil.RegisterSecret(fr.deploymentID, value)
+	if leased, ok := secret.(vault.LeasedSecret); ok && leased.LeaseID() != "" {
+		// Reading a leased secret again would return a new value, its value is only redacted by this process
+		err = RegisterSecretLease(ctx, fr.deploymentID, fr.nodeName, fr.instanceName, leased)
+		if err != nil {
+			return "", err
+		}
+		return value, nil
+	}
+	err = registerSecretReference(ctx, fr.deploymentID, operands[0], options, value)
+	if err != nil {
+		return "", err
+	}
+	return value, nil
 }
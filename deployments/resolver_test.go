@@ -309,3 +309,39 @@ func testResolveSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveTokenAndJoinFunctions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		fn      string
+		want    string
+		wantErr bool
+	}{
+		{"Token", `token: ["192.168.0.1,192.168.0.2", ",", 1]`, "192.168.0.2", false},
+		{"TokenSeveralChars", `token: ["a-b_c", "-_", 2]`, "c", false},
+		{"TokenOutOfRange", `token: ["a-b", "-", 2]`, "", true},
+		{"TokenInvalidIndex", `token: ["a-b", "-", x]`, "", true},
+		{"Join", `join: [[a, b, c], "-"]`, "a-b-c", false},
+		{"JoinNestedFunctions", `join: [[a, concat: [b, c], token: ["d:e", ":", 1]], "/"]`, "a/bc/e", false},
+		{"JoinWithoutDelimiter", `join: [[a, b]]`, "ab", false},
+		{"JoinSpaceDelimiter", `join: [[a, b], " "]`, "a b", false},
+		{"JoinNotAList", `join: [a, b]`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := tosca.ParseFunction(tt.fn)
+			require.NoError(t, err)
+			// Function representation should be stable for storage
+			f, err = tosca.ParseFunction(f.String())
+			require.NoError(t, err)
+			res, err := resolver("dep").resolveFunction(context.Background(), f)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, res.RawString())
+		})
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"path"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/vault"
+)
+
+// SecretLease describes the lease of a secret retrieved by a get_secret function
+// for a given deployment.
+type SecretLease struct {
+	LeaseID   string        `json:"lease_id"`
+	NodeName  string        `json:"node_name,omitempty"`
+	Instance  string        `json:"instance,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	Renewable bool          `json:"renewable"`
+	ExpiresAt time.Time     `json:"expires_at"`
+}
+
+func secretLeasesPath(deploymentID string) string {
+	return path.Join(consulutil.DeploymentKVPrefix, deploymentID, "secrets", "leases")
+}
+
+func secretLeaseKey(deploymentID, leaseID string) string {
+	// Lease IDs contain slashes, hash them to get a flat key
+	h := sha1.Sum([]byte(leaseID))
+	return path.Join(secretLeasesPath(deploymentID), hex.EncodeToString(h[:]))
+}
+
+// RegisterSecretLease tracks the lease of a secret used by the given node instance of a deployment
+//
+// nodeName and instanceName may be empty if the secret is not related to a particular node instance.
+func RegisterSecretLease(ctx context.Context, deploymentID, nodeName, instanceName string, secret vault.LeasedSecret) error {
+	if secret.LeaseID() == "" {
+		return nil
+	}
+	lease := SecretLease{
+		LeaseID:   secret.LeaseID(),
+		NodeName:  nodeName,
+		Instance:  instanceName,
+		Duration:  secret.LeaseDuration(),
+		Renewable: secret.Renewable(),
+		ExpiresAt: time.Now().Add(secret.LeaseDuration()),
+	}
+	return storeSecretLease(deploymentID, lease)
+}
+
+func storeSecretLease(deploymentID string, lease SecretLease) error {
+	err := consulutil.StoreConsulKeyWithJSONValue(secretLeaseKey(deploymentID, lease.LeaseID), lease)
+	return errors.Wrapf(err, "failed to store secret lease for deployment %q", deploymentID)
+}
+
+// GetSecretLeases returns secret leases tracked for the given deployment
+func GetSecretLeases(ctx context.Context, deploymentID string) ([]SecretLease, error) {
+	kvs, err := consulutil.List(secretLeasesPath(deploymentID) + "/")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list secret leases for deployment %q", deploymentID)
+	}
+	leases := make([]SecretLease, 0, len(kvs))
+	for key, value := range kvs {
+		var lease SecretLease
+		err = json.Unmarshal(value, &lease)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal secret lease %q", key)
+		}
+		leases = append(leases, lease)
+	}
+	return leases, nil
+}
+
+func deleteSecretLease(deploymentID, leaseID string) error {
+	return consulutil.Delete(secretLeaseKey(deploymentID, leaseID), false)
+}
+
+// RenewSecretLeases renews leases of the given deployment that passed half of their duration
+//
+// Leases that can't be renewed anymore and are expired are no longer tracked.
+func RenewSecretLeases(ctx context.Context, deploymentID string) error {
+	leases, err := GetSecretLeases(ctx, deploymentID)
+	if err != nil || len(leases) == 0 {
+		return err
+	}
+	lm, ok := DefaultVaultClient.(vault.LeaseManager)
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	for _, lease := range leases {
+		if !lease.Renewable || lease.ExpiresAt.Sub(now) > lease.Duration/2 {
+			continue
+		}
+		duration, err := lm.RenewLease(lease.LeaseID, lease.Duration)
+		if err != nil {
+			if now.After(lease.ExpiresAt) {
+				events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, deploymentID).Registerf("[WARNING] Secret lease used by node %q instance %q expired and can't be renewed: %v", lease.NodeName, lease.Instance, err)
+				err = deleteSecretLease(deploymentID, lease.LeaseID)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			log.Printf("Failed to renew secret lease for deployment %q, node %q, instance %q, will retry later: %v", deploymentID, lease.NodeName, lease.Instance, err)
+			continue
+		}
+		lease.Duration = duration
+		lease.ExpiresAt = now.Add(duration)
+		err = storeSecretLease(deploymentID, lease)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RevokeSecretLeases revokes leases of secrets used by a deployment
+//
+// If nodeName is not empty only leases related to this node are revoked and if instanceName is
+// also not empty only leases related to this node instance are revoked.
+// Leases are no longer tracked even if they could not be revoked, an error is then returned.
+func RevokeSecretLeases(ctx context.Context, deploymentID, nodeName, instanceName string) error {
+	leases, err := GetSecretLeases(ctx, deploymentID)
+	if err != nil || len(leases) == 0 {
+		return err
+	}
+	lm, _ := DefaultVaultClient.(vault.LeaseManager)
+	var revokeErr error
+	for _, lease := range leases {
+		if nodeName != "" && lease.NodeName != nodeName {
+			continue
+		}
+		if instanceName != "" && lease.Instance != instanceName {
+			continue
+		}
+		if lm != nil {
+			err = lm.RevokeLease(lease.LeaseID)
+			if err != nil && revokeErr == nil {
+				revokeErr = errors.Wrapf(err, "failed to revoke secret lease used by node %q instance %q of deployment %q", lease.NodeName, lease.Instance, deploymentID)
+			}
+		}
+		err = deleteSecretLease(deploymentID, lease.LeaseID)
+		if err != nil {
+			return err
+		}
+	}
+	return revokeErr
+}
@@ -0,0 +1,118 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/testutil"
+	"github.com/ystia/yorc/v4/vault"
+)
+
+type mockLeasedSecret struct {
+	value     string
+	leaseID   string
+	duration  time.Duration
+	renewable bool
+}
+
+func (s *mockLeasedSecret) String() string               { return s.value }
+func (s *mockLeasedSecret) Raw() interface{}             { return s.value }
+func (s *mockLeasedSecret) LeaseID() string              { return s.leaseID }
+func (s *mockLeasedSecret) LeaseDuration() time.Duration { return s.duration }
+func (s *mockLeasedSecret) Renewable() bool              { return s.renewable }
+
+type mockLeaseManagerClient struct {
+	renewed []string
+	revoked []string
+}
+
+func (c *mockLeaseManagerClient) GetSecret(id string, options ...string) (vault.Secret, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *mockLeaseManagerClient) Shutdown() error {
+	return nil
+}
+
+func (c *mockLeaseManagerClient) RenewLease(leaseID string, increment time.Duration) (time.Duration, error) {
+	if leaseID == "failing" {
+		return 0, errors.New("renew failure")
+	}
+	c.renewed = append(c.renewed, leaseID)
+	return increment, nil
+}
+
+func (c *mockLeaseManagerClient) RevokeLease(leaseID string) error {
+	c.revoked = append(c.revoked, leaseID)
+	return nil
+}
+
+func testSecretLeases(t *testing.T) {
+	ctx := context.Background()
+	deploymentID := testutil.BuildDeploymentID(t)
+
+	client := new(mockLeaseManagerClient)
+	oldClient := DefaultVaultClient
+	DefaultVaultClient = client
+	defer func() {
+		DefaultVaultClient = oldClient
+	}()
+
+	secrets := []struct {
+		node, instance string
+		secret         *mockLeasedSecret
+	}{
+		{"Compute", "0", &mockLeasedSecret{"v1", "database/creds/role/1", time.Hour, true}},
+		{"Compute", "1", &mockLeasedSecret{"v2", "database/creds/role/2", time.Millisecond, true}},
+		{"Compute", "1", &mockLeasedSecret{"v3", "database/creds/role/3", time.Millisecond, false}},
+		{"DB", "0", &mockLeasedSecret{"v4", "failing", time.Millisecond, true}},
+		{"DB", "0", &mockLeasedSecret{"v5", "", time.Hour, true}},
+	}
+	for _, s := range secrets {
+		err := RegisterSecretLease(ctx, deploymentID, s.node, s.instance, s.secret)
+		require.NoError(t, err)
+	}
+	leases, err := GetSecretLeases(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Len(t, leases, 4)
+
+	time.Sleep(5 * time.Millisecond)
+	err = RenewSecretLeases(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"database/creds/role/2"}, client.renewed)
+	leases, err = GetSecretLeases(ctx, deploymentID)
+	require.NoError(t, err)
+	// expired lease that failed to be renewed is not tracked anymore
+	require.Len(t, leases, 3)
+
+	err = RevokeSecretLeases(ctx, deploymentID, "Compute", "1")
+	require.NoError(t, err)
+	sort.Strings(client.revoked)
+	require.Equal(t, []string{"database/creds/role/2", "database/creds/role/3"}, client.revoked)
+
+	err = RevokeSecretLeases(ctx, deploymentID, "", "")
+	require.NoError(t, err)
+	require.Len(t, client.revoked, 3)
+	leases, err = GetSecretLeases(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Len(t, leases, 0)
+}
@@ -0,0 +1,94 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/helper/redactutil"
+)
+
+func init() {
+	redactutil.SetSecretsLoader(loadSecretsValues)
+}
+
+// secretReference references a secret resolved by a get_secret function for a deployment, its value is not stored
+type secretReference struct {
+	Path    string   `json:"path"`
+	Options []string `json:"options,omitempty"`
+}
+
+// secretsValues caches the values of referenced secrets by reference key so that they are read once from the vault
+var secretsValues sync.Map
+
+func secretReferencesPath(deploymentID string) string {
+	return path.Join(consulutil.DeploymentKVPrefix, deploymentID, "secrets", "references")
+}
+
+func secretReferenceKey(deploymentID string, ref secretReference) string {
+	h := sha1.Sum([]byte(strings.Join(append([]string{ref.Path}, ref.Options...), "\n")))
+	return path.Join(secretReferencesPath(deploymentID), hex.EncodeToString(h[:]))
+}
+
+// registerSecretReference records a secret used by a deployment so that its value could be redacted by other Yorc
+// servers or after a restart
+func registerSecretReference(ctx context.Context, deploymentID, secretPath string, options []string, value string) error {
+	ref := secretReference{Path: secretPath, Options: options}
+	key := secretReferenceKey(deploymentID, ref)
+	secretsValues.Store(key, value)
+	err := consulutil.StoreConsulKeyWithJSONValue(key, ref)
+	return errors.Wrapf(err, "failed to store secret reference for deployment %q", deploymentID)
+}
+
+// loadSecretsValues returns the values of the secrets referenced by a deployment
+//
+// Values which are not known by this process are read from the vault.
+func loadSecretsValues(deploymentID string) ([]string, error) {
+	kvs, err := consulutil.List(secretReferencesPath(deploymentID) + "/")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list secret references for deployment %q", deploymentID)
+	}
+	values := make([]string, 0, len(kvs))
+	for key, value := range kvs {
+		if v, ok := secretsValues.Load(key); ok {
+			values = append(values, v.(string))
+			continue
+		}
+		if DefaultVaultClient == nil {
+			return values, errors.New("no vault client configured to read secrets")
+		}
+		var ref secretReference
+		err = json.Unmarshal(value, &ref)
+		if err != nil {
+			return values, errors.Wrapf(err, "failed to unmarshal secret reference %q", key)
+		}
+		secret, err := DefaultVaultClient.GetSecret(ref.Path, ref.Options...)
+		if err != nil {
+			return values, errors.Wrapf(err, "failed to read secret %q", ref.Path)
+		}
+		secretsValues.Store(key, secret.String())
+		values = append(values, secret.String())
+	}
+	return values, nil
+}
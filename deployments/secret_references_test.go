@@ -0,0 +1,75 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/testutil"
+	"github.com/ystia/yorc/v4/vault"
+)
+
+type mockSecret string
+
+func (s mockSecret) String() string   { return string(s) }
+func (s mockSecret) Raw() interface{} { return string(s) }
+
+type mockSecretsClient struct {
+	reads int
+}
+
+func (c *mockSecretsClient) GetSecret(id string, options ...string) (vault.Secret, error) {
+	c.reads++
+	return mockSecret(id + "/value"), nil
+}
+
+func (c *mockSecretsClient) Shutdown() error {
+	return nil
+}
+
+func testSecretReferences(t *testing.T) {
+	ctx := context.Background()
+	deploymentID := testutil.BuildDeploymentID(t)
+
+	client := new(mockSecretsClient)
+	oldClient := DefaultVaultClient
+	DefaultVaultClient = client
+	defer func() {
+		DefaultVaultClient = oldClient
+	}()
+
+	err := registerSecretReference(ctx, deploymentID, "secret/known", nil, "secret/known/value")
+	require.NoError(t, err)
+	err = registerSecretReference(ctx, deploymentID, "secret/other", []string{"data=password"}, "secret/other/value")
+	require.NoError(t, err)
+
+	values, err := loadSecretsValues(deploymentID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"secret/known/value", "secret/other/value"}, values)
+	require.Equal(t, 0, client.reads, "values known by this process should not be read again")
+
+	// Simulates a restart of the Yorc server
+	secretsValues.Delete(secretReferenceKey(deploymentID, secretReference{Path: "secret/other", Options: []string{"data=password"}}))
+	values, err = loadSecretsValues(deploymentID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"secret/known/value", "secret/other/value"}, values)
+	require.Equal(t, 1, client.reads)
+
+	_, err = loadSecretsValues("unknownDeployment")
+	require.NoError(t, err)
+}
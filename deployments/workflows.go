@@ -16,6 +16,8 @@ package deployments
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"path"
 	"strings"
 
@@ -64,6 +66,114 @@ func GetWorkflow(ctx context.Context, deploymentID, workflowName string) (*tosca
 	return wf, nil
 }
 
+type workflowPreconditionsNotMetError struct {
+	workflowName string
+	target       string
+}
+
+func (e workflowPreconditionsNotMetError) Error() string {
+	return fmt.Sprintf("preconditions of workflow %q are not met on target %q", e.workflowName, e.target)
+}
+
+// IsWorkflowPreconditionsNotMetError checks if an error is due to workflow preconditions not met
+func IsWorkflowPreconditionsNotMetError(err error) bool {
+	_, ok := errors.Cause(err).(workflowPreconditionsNotMetError)
+	return ok
+}
+
+// CheckWorkflowPreconditions checks that all preconditions of a workflow are met
+//
+// Preconditions with a target_relationship are evaluated against the target nodes of
+// the requirements of the precondition target with this name.
+//
+// An error checkable using IsWorkflowPreconditionsNotMetError is returned if
+// one of the preconditions is not met.
+func CheckWorkflowPreconditions(ctx context.Context, deploymentID, workflowName string) error {
+	wf, err := GetWorkflow(ctx, deploymentID, workflowName)
+	if err != nil {
+		return err
+	}
+	if wf == nil {
+		return errors.Errorf("Can't check preconditions of workflow %q in deployment %q, workflow definition not found", workflowName, deploymentID)
+	}
+	for _, precondition := range wf.Preconditions {
+		targets, err := getPreconditionTargets(ctx, deploymentID, precondition)
+		if err != nil {
+			return errors.Wrapf(err, "Can't check preconditions of workflow %q in deployment %q", workflowName, deploymentID)
+		}
+		for _, target := range targets {
+			ok, err := EvaluateNodeConditions(ctx, deploymentID, target, precondition.Condition)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return errors.WithStack(workflowPreconditionsNotMetError{workflowName: workflowName, target: target})
+			}
+		}
+	}
+	return nil
+}
+
+// getPreconditionTargets returns the nodes a precondition should be evaluated against
+func getPreconditionTargets(ctx context.Context, deploymentID string, precondition tosca.Precondition) ([]string, error) {
+	if precondition.TargetRelationShip == "" {
+		return []string{precondition.Target}, nil
+	}
+	indexes, err := GetRequirementsIndexes(ctx, deploymentID, precondition.Target)
+	if err != nil {
+		return nil, err
+	}
+	var targets []string
+	for _, index := range indexes {
+		name, err := GetRequirementNameByIndexForNode(ctx, deploymentID, precondition.Target, index)
+		if err != nil {
+			return nil, err
+		}
+		if name != precondition.TargetRelationShip {
+			continue
+		}
+		target, err := GetTargetNodeForRequirement(ctx, deploymentID, precondition.Target, index)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+	if len(targets) == 0 {
+		return nil, errors.Errorf("node %q has no requirement named %q", precondition.Target, precondition.TargetRelationShip)
+	}
+	return targets, nil
+}
+
+// EvaluateNodeConditions evaluates a list of condition clauses against the attributes of each instance of a given node
+//
+// Conditions are met if they are satisfied by all instances of the node.
+func EvaluateNodeConditions(ctx context.Context, deploymentID, nodeName string, conditions []tosca.ConditionClause) (bool, error) {
+	if len(conditions) == 0 {
+		return true, nil
+	}
+	instances, err := GetNodeInstancesIds(ctx, deploymentID, nodeName)
+	if err != nil {
+		return false, err
+	}
+	for _, instance := range instances {
+		getValue := func(name string) (string, error) {
+			value, err := GetInstanceAttributeValue(ctx, deploymentID, nodeName, instance, name)
+			if err != nil || value == nil {
+				return "", err
+			}
+			return value.RawString(), nil
+		}
+		ok, err := tosca.EvaluateConditions(conditions, getValue, true)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to evaluate conditions on node %q instance %q", nodeName, instance)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 // DeleteWorkflow deletes the given workflow from the Consul store
 func DeleteWorkflow(ctx context.Context, deploymentID, workflowName string) error {
 	return storage.GetStore(types.StoreTypeDeployment).Delete(ctx, path.Join(consulutil.DeploymentKVPrefix, deploymentID,
@@ -153,3 +263,41 @@ func ResolveWorkflowOutputs(ctx context.Context, deploymentID, workflowName stri
 
 	return outputs, nil
 }
+
+// CheckWorkflowOutputs checks resolved outputs values against the outputs definitions of a workflow
+//
+// Values should match the type of their definition and satisfy its constraints.
+// An error checkable using IsInvalidParameterValueError is returned if an output
+// value is not valid.
+func CheckWorkflowOutputs(ctx context.Context, deploymentID, workflowName string, outputs map[string]*TOSCAValue) error {
+	wf, err := GetWorkflow(ctx, deploymentID, workflowName)
+	if err != nil {
+		return err
+	}
+	if wf == nil {
+		return errors.Errorf("Can't check outputs of workflow %q in deployment %q, workflow definition not found", workflowName, deploymentID)
+	}
+	values := make(map[string]interface{}, len(outputs))
+	for outputName, outputValue := range outputs {
+		if outputValue == nil {
+			continue
+		}
+		value := outputValue.Value
+		if _, ok := value.(string); !ok {
+			// Normalize complex values as they would be decoded from their JSON representation
+			err = json.Unmarshal([]byte(outputValue.RawString()), &value)
+			if err != nil {
+				return errors.Wrapf(err, "failed to decode value of workflow output %q", outputName)
+			}
+		}
+		values[outputName] = value
+	}
+	defs := make(map[string]tosca.ParameterDefinition, len(wf.Outputs))
+	for outputName, def := range wf.Outputs {
+		// Outputs are not required
+		def.Required = new(bool)
+		defs[outputName] = def
+	}
+	_, err = validateParametersValues(ctx, deploymentID, "workflow output", defs, values)
+	return err
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployments
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testCheckWorkflowOutputs(t *testing.T) {
+	ctx := context.Background()
+	deploymentID := strings.Replace(t.Name(), "/", "_", -1)
+	err := StoreDeploymentDefinition(ctx, deploymentID, "testdata/workflow_inputs.yaml")
+	require.NoError(t, err)
+
+	outputs, err := ResolveWorkflowOutputs(ctx, deploymentID, "maintenance")
+	require.NoError(t, err)
+	require.Contains(t, outputs, "nb_hosts")
+	require.Equal(t, "3", outputs["nb_hosts"].RawString())
+	require.NoError(t, CheckWorkflowOutputs(ctx, deploymentID, "maintenance", outputs))
+
+	err = CheckWorkflowOutputs(ctx, deploymentID, "maintenance", map[string]*TOSCAValue{"nb_hosts": {Value: "12"}})
+	require.Error(t, err)
+	require.True(t, IsInvalidParameterValueError(err), "unexpected error %v", err)
+
+	err = CheckWorkflowOutputs(ctx, deploymentID, "maintenance", map[string]*TOSCAValue{"nb_hosts": {Value: []interface{}{"3"}}})
+	require.Error(t, err)
+	require.True(t, IsInvalidParameterValueError(err), "unexpected error %v", err)
+
+	// outputs are not required
+	require.NoError(t, CheckWorkflowOutputs(ctx, deploymentID, "maintenance", map[string]*TOSCAValue{"nb_hosts": nil}))
+}
+
+func testCheckWorkflowPreconditionsOnRelationship(t *testing.T) {
+	ctx := context.Background()
+	deploymentID := strings.Replace(t.Name(), "/", "_", -1)
+	err := StoreDeploymentDefinition(ctx, deploymentID, "testdata/workflow_preconditions.yaml")
+	require.NoError(t, err)
+	require.NoError(t, SetInstanceStateStringWithContextualLogs(ctx, deploymentID, "App", "0", "started"))
+	require.NoError(t, SetInstanceStateStringWithContextualLogs(ctx, deploymentID, "Compute", "0", "configured"))
+
+	// Conditions are evaluated on the relationship target which is not started
+	err = CheckWorkflowPreconditions(ctx, deploymentID, "maintenance")
+	require.Error(t, err)
+	require.True(t, IsWorkflowPreconditionsNotMetError(err), "unexpected error %v", err)
+
+	require.NoError(t, SetInstanceStateStringWithContextualLogs(ctx, deploymentID, "Compute", "0", "started"))
+	require.NoError(t, CheckWorkflowPreconditions(ctx, deploymentID, "maintenance"))
+
+	err = CheckWorkflowPreconditions(ctx, deploymentID, "unknown_requirement")
+	require.Error(t, err)
+	require.False(t, IsWorkflowPreconditionsNotMetError(err), "unexpected error %v", err)
+}
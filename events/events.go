@@ -102,6 +102,26 @@ func PublishAndLogDeploymentStatusChange(ctx context.Context, deploymentID, stat
 	return id, nil
 }
 
+// PublishAndLogDeploymentExpiry publishes an expiry status for a given deployment and log it into the log API
+//
+// Status is either "expiring" when the deployment expiry date is close or "expired" when it is reached.
+//
+// PublishAndLogDeploymentExpiry returns the published event id
+func PublishAndLogDeploymentExpiry(ctx context.Context, deploymentID string, expiryDate time.Time, status string) (string, error) {
+	info := buildInfoFromContext(ctx)
+	info[EExpiryDate] = expiryDate.Format(time.RFC3339)
+	e, err := newStatusChange(ctx, StatusChangeTypeDeploymentExpiry, info, deploymentID, strings.ToLower(status))
+	if err != nil {
+		return "", err
+	}
+	id, err := e.register()
+	if err != nil {
+		return "", err
+	}
+	WithContextOptionalFields(ctx).NewLogEntry(LogLevelWARN, deploymentID).Registerf("Deployment %q is %s, its expiry date is %s", deploymentID, status, expiryDate.Format(time.RFC3339))
+	return id, nil
+}
+
 // PublishAndLogCustomCommandStatusChange publishes a status change for a custom command and log this change into the log API
 //
 // PublishAndLogCustomCommandStatusChange returns the published event id
@@ -322,6 +342,8 @@ func bindEventInfoWithContext(f FieldType) (InfoType, bool) {
 		i = EOperationName
 	case TaskExecutionID:
 		i = ETaskExecutionID
+	case DeploymentLabels:
+		i = EDeploymentLabels
 	default:
 		has = false
 	}
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/helper/redactutil"
 	"github.com/ystia/yorc/v4/log"
 	"github.com/ystia/yorc/v4/storage"
 	"github.com/ystia/yorc/v4/storage/types"
@@ -76,6 +77,10 @@ const (
 
 	// TaskExecutionID is the field type representing the task execution ID in log entry
 	TaskExecutionID
+
+	// DeploymentLabels is the field type representing the deployment labels in log entry
+	// as a comma-separated list of name=value pairs
+	DeploymentLabels
 )
 
 // String allows to stringify the field type enumeration in JSON standard
@@ -97,6 +102,8 @@ func (ft FieldType) String() string {
 		return "type"
 	case TaskExecutionID:
 		return "alienTaskId"
+	case DeploymentLabels:
+		return "deploymentLabels"
 	}
 	return ""
 }
@@ -155,7 +162,8 @@ func (e LogEntry) Register(content []byte) {
 	if e.deploymentID == "" {
 		log.Panic("The deploymentID parameter must be filled")
 	}
-	e.content = content
+	// Hide secrets from logs
+	e.content = []byte(redactutil.Redact(e.deploymentID, string(content)))
 
 	// Get the timestamp
 	e.timestamp = time.Now()
@@ -42,6 +42,7 @@ Workflow
 WorkflowStep
 AlienTask
 AttributeValue
+DeploymentExpiry
 )
 */
 type StatusChangeType int
@@ -83,6 +84,10 @@ const (
 	EAttributeName
 	// EAttributeValue is event information related to attribute value
 	EAttributeValue
+	// EDeploymentLabels is event information related to deployment labels
+	EDeploymentLabels
+	// EExpiryDate is event information related to deployment expiry date
+	EExpiryDate
 )
 
 func (i InfoType) String() string {
@@ -121,6 +126,10 @@ func (i InfoType) String() string {
 		return "attribute"
 	case EAttributeValue:
 		return "value"
+	case EDeploymentLabels:
+		return "deploymentLabels"
+	case EExpiryDate:
+		return "expiryDate"
 	}
 	return ""
 }
@@ -201,13 +210,14 @@ func (e *statusChange) check() error {
 	}
 
 	mandatoryMap := map[StatusChangeType][]InfoType{
-		StatusChangeTypeInstance:       {ENodeID, EInstanceID},
-		StatusChangeTypeAttributeValue: {ENodeID, EAttributeName, EAttributeValue},
-		StatusChangeTypeCustomCommand:  {ETaskID},
-		StatusChangeTypeScaling:        {ETaskID},
-		StatusChangeTypeWorkflow:       {ETaskID},
-		StatusChangeTypeWorkflowStep:   {ETaskID, EWorkflowID, ENodeID, EWorkflowStepID, EInstanceID},
-		StatusChangeTypeAlienTask:      {ETaskID, EWorkflowID, ENodeID, EWorkflowStepID, EInstanceID, ETaskExecutionID},
+		StatusChangeTypeInstance:         {ENodeID, EInstanceID},
+		StatusChangeTypeAttributeValue:   {ENodeID, EAttributeName, EAttributeValue},
+		StatusChangeTypeCustomCommand:    {ETaskID},
+		StatusChangeTypeScaling:          {ETaskID},
+		StatusChangeTypeWorkflow:         {ETaskID},
+		StatusChangeTypeWorkflowStep:     {ETaskID, EWorkflowID, ENodeID, EWorkflowStepID, EInstanceID},
+		StatusChangeTypeAlienTask:        {ETaskID, EWorkflowID, ENodeID, EWorkflowStepID, EInstanceID, ETaskExecutionID},
+		StatusChangeTypeDeploymentExpiry: {EExpiryDate},
 	}
 	// Check mandatory info in function of status change type
 	if mandatoryInfos, is := mandatoryMap[e.eventType]; is {
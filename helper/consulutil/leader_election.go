@@ -91,7 +91,11 @@ func handleError(err error, serviceKey string) {
 
 // IsAnyLeader allows to return true if any leader exists for a defined service key
 func IsAnyLeader(cc *api.Client, serviceKey string, waitIndex uint64) (bool, string, uint64, error) {
-	q := &api.QueryOptions{WaitIndex: waitIndex}
+	return isAnyLeader(cc, serviceKey, waitIndex, 0)
+}
+
+func isAnyLeader(cc *api.Client, serviceKey string, waitIndex uint64, waitTime time.Duration) (bool, string, uint64, error) {
+	q := &api.QueryOptions{WaitIndex: waitIndex, WaitTime: waitTime}
 	kvPair, rMeta, err := cc.KV().Get(serviceKey, q)
 	if err != nil {
 		return false, "", 0, err
@@ -104,20 +108,56 @@ func IsAnyLeader(cc *api.Client, serviceKey string, waitIndex uint64) (bool, str
 	return true, string(kvPair.Value), rMeta.LastIndex, nil
 }
 
+// LeaderElectionOptions allows to tune the way a leader election is watched
+type LeaderElectionOptions struct {
+	// Eligible, if set, is called to know if this node may be leader for the service.
+	// A node that is not eligible never tries to acquire the leadership and releases it if it holds it.
+	Eligible func() bool
+	// EligibilityCheckInterval is the maximum duration between two checks of the node eligibility.
+	// It defaults to DefaultEligibilityCheckInterval.
+	EligibilityCheckInterval time.Duration
+}
+
+// DefaultEligibilityCheckInterval is the default maximum duration between two checks of a node eligibility to a service leadership
+const DefaultEligibilityCheckInterval = 10 * time.Second
+
 // WatchLeaderElection allows to watch for leader election for defined service. It elects leader if needed and can start the related service or stop it if node is no longer leader
 func WatchLeaderElection(cc *api.Client, serviceKey string, chStop chan struct{}, leaderServiceStart func(), leaderServiceStop func()) {
+	WatchLeaderElectionWithOptions(cc, serviceKey, chStop, leaderServiceStart, leaderServiceStop, LeaderElectionOptions{})
+}
+
+// ReleaseLeadership allows to release the leadership of a service if this node holds it
+func ReleaseLeadership(cc *api.Client, serviceKey, agentName string) error {
+	session, err := GetSession(cc, serviceKey, agentName)
+	if err != nil {
+		return err
+	}
+	_, _, err = cc.KV().Release(&api.KVPair{Key: serviceKey, Value: []byte(agentName), Session: session}, nil)
+	return errors.Wrapf(err, "Failed to release leadership for service:%q", serviceKey)
+}
+
+// WatchLeaderElectionWithOptions is like WatchLeaderElection but allows to define options on the way the leader is elected
+func WatchLeaderElectionWithOptions(cc *api.Client, serviceKey string, chStop chan struct{}, leaderServiceStart func(), leaderServiceStop func(), opts LeaderElectionOptions) {
 	log.Debugf("WatchLeaderElection for service:%q", serviceKey)
 	var (
 		waitIndex uint64
 		lastIndex uint64
 		isAny     bool
 		leader    string
+		waitTime  time.Duration
 	)
 	agentName, err := GetAgentName(cc)
 	if err != nil {
 		handleError(err, serviceKey)
 		return
 	}
+	if opts.Eligible != nil {
+		waitTime = opts.EligibilityCheckInterval
+		if waitTime <= 0 {
+			waitTime = DefaultEligibilityCheckInterval
+		}
+	}
+	wasEligible := true
 
 	for {
 		select {
@@ -127,15 +167,32 @@ func WatchLeaderElection(cc *api.Client, serviceKey string, chStop chan struct{}
 		default:
 		}
 
-		isAny, leader, lastIndex, err = IsAnyLeader(cc, serviceKey, waitIndex)
+		isAny, leader, lastIndex, err = isAnyLeader(cc, serviceKey, waitIndex, waitTime)
 		log.Debugf("Wait Index is %d for watching leader election for service:%q", lastIndex, serviceKey)
 		if err != nil {
 			handleError(err, serviceKey)
 			continue
 		}
-		if waitIndex == lastIndex {
+		eligible := opts.Eligible == nil || opts.Eligible()
+		if !eligible {
+			waitIndex = lastIndex
+			wasEligible = false
+			if isAny && leader == agentName {
+				log.Printf("I am no longer eligible to be leader for service:%q as %q, releasing the leadership", serviceKey, agentName)
+				if leaderServiceStop != nil {
+					leaderServiceStop()
+				}
+				err = ReleaseLeadership(cc, serviceKey, agentName)
+				if err != nil {
+					handleError(err, serviceKey)
+				}
+			}
+			continue
+		}
+		if waitIndex == lastIndex && wasEligible {
 			continue
 		}
+		wasEligible = true
 		waitIndex = lastIndex
 		if !isAny {
 			// No leader has been elected : try to acquire leadership on service
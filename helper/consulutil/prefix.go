@@ -16,6 +16,9 @@ package consulutil
 
 const yorcPrefix string = "_yorc"
 
+// YorcKVPrefix is the prefix in Consul KV store of all the data owned by Yorc
+const YorcKVPrefix string = yorcPrefix
+
 // DeploymentKVPrefix is the prefix in Consul KV store for deployments
 const DeploymentKVPrefix string = yorcPrefix + "/deployments"
 
@@ -61,3 +64,12 @@ const PurgedDeploymentKVPrefix string = yorcPrefix + "/purged"
 
 // StoresPrefix is the prefix in Consul KV store for stores
 const StoresPrefix string = yorcPrefix + "/stores"
+
+// QuotasKVPrefix is the prefix in Consul KV store for quotas definitions
+const QuotasKVPrefix string = yorcPrefix + "/quotas"
+
+// QuotasUsageKVPrefix is the prefix in Consul KV store for the resources accounted by quotas for each deployment
+const QuotasUsageKVPrefix string = yorcPrefix + "/quotas_usage"
+
+// RolloutsKVPrefix is the prefix in Consul KV store for rollouts of workflows on scalable nodes
+const RolloutsKVPrefix string = yorcPrefix + "/rollouts"
@@ -0,0 +1,215 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cronutil parses cron expressions and computes their activation times.
+package cronutil
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// maxLookAhead is the maximum duration after which we consider that an expression will never match (e.g. on February 30th)
+const maxLookAhead = 5 * 366 * 24 * time.Hour
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthsNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var daysNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+type field struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var fields = []field{
+	{"minute", 0, 59, nil},
+	{"hour", 0, 23, nil},
+	{"day of month", 1, 31, nil},
+	{"month", 1, 12, monthsNames},
+	// 7 is allowed as an alias for sunday
+	{"day of week", 0, 7, daysNames},
+}
+
+// An Expression is a parsed cron expression
+type Expression struct {
+	minutes     uint64
+	hours       uint64
+	daysOfMonth uint64
+	months      uint64
+	daysOfWeek  uint64
+	// anyDay is true if either the day of month or the day of week is not restricted,
+	// in this case both should match otherwise only one of them
+	anyDay bool
+}
+
+// Parse parses a standard cron expression
+//
+// Expressions are composed of 5 space-separated fields: minute, hour, day of month, month and day of week.
+// Each field accepts '*', values, ranges ('1-5'), steps ('*/15' or '0-30/10') and comma-separated lists
+// of those. Months and days of week could also be given by their 3 first letters (e.g. 'jan' or 'mon').
+// The '@yearly', '@annually', '@monthly', '@weekly', '@daily', '@midnight' and '@hourly' macros are also supported.
+func Parse(expression string) (*Expression, error) {
+	expr := strings.TrimSpace(expression)
+	if m, ok := macros[strings.ToLower(expr)]; ok {
+		expr = m
+	}
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, errors.Errorf("invalid cron expression %q: expecting %d fields, got %d", expression, len(fields), len(parts))
+	}
+	values := make([]uint64, len(fields))
+	for i, f := range fields {
+		var err error
+		values[i], err = parseField(parts[i], f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid cron expression %q", expression)
+		}
+	}
+	e := &Expression{
+		minutes:     values[0],
+		hours:       values[1],
+		daysOfMonth: values[2],
+		months:      values[3],
+		daysOfWeek:  values[4],
+		anyDay:      strings.HasPrefix(parts[2], "*") || strings.HasPrefix(parts[4], "*"),
+	}
+	// sunday could be given as 0 or 7
+	if e.daysOfWeek&(1<<7) != 0 {
+		e.daysOfWeek |= 1
+	}
+	return e, nil
+}
+
+func parseField(value string, f field) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(value, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			rangePart = part[:i]
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, errors.Errorf("invalid step %q for %s field", part[i+1:], f.name)
+			}
+		}
+		start, end := f.min, f.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			start, err = parseValue(bounds[0], f)
+			if err != nil {
+				return 0, err
+			}
+			end, err = parseValue(bounds[1], f)
+			if err != nil {
+				return 0, err
+			}
+			if end < start {
+				return 0, errors.Errorf("invalid range %q for %s field", rangePart, f.name)
+			}
+		default:
+			var err error
+			start, err = parseValue(rangePart, f)
+			if err != nil {
+				return 0, err
+			}
+			if step == 1 {
+				end = start
+			}
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(value string, f field) (int, error) {
+	if v, ok := f.names[strings.ToLower(value)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.Errorf("invalid value %q for %s field", value, f.name)
+	}
+	if v < f.min || v > f.max {
+		return 0, errors.Errorf("value %d out of range [%d-%d] for %s field", v, f.min, f.max, f.name)
+	}
+	return v, nil
+}
+
+func has(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) != 0
+}
+
+func (e *Expression) dayMatches(t time.Time) bool {
+	domMatch := has(e.daysOfMonth, t.Day())
+	dowMatch := has(e.daysOfWeek, int(t.Weekday()))
+	if e.anyDay {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// Next returns the first activation time of the expression strictly after the given time
+//
+// Activation times are computed in the location of the given time. A zero time is returned
+// if the expression never matches.
+func (e *Expression) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.Add(maxLookAhead)
+	for t.Before(limit) {
+		if !has(e.months, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !e.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !has(e.hours, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if !has(e.minutes, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
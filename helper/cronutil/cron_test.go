@@ -0,0 +1,90 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cronutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    bool
+	}{
+		{"EveryMinute", "* * * * *", false},
+		{"Nightly", "0 2 * * *", false},
+		{"StepsAndRanges", "*/15 8-18 * * mon-fri", false},
+		{"Lists", "0,30 1,13 1,15 jan,jul *", false},
+		{"SundayAsSeven", "0 0 * * 7", false},
+		{"Macro", "@daily", false},
+		{"MissingField", "0 2 * *", true},
+		{"TooManyFields", "0 2 * * * *", true},
+		{"OutOfRange", "60 * * * *", true},
+		{"BadName", "0 0 * foo *", true},
+		{"ReversedRange", "0 10-8 * * *", true},
+		{"BadStep", "*/0 * * * *", true},
+		{"UnknownMacro", "@fortnightly", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.expression)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestExpressionNext(t *testing.T) {
+	t.Parallel()
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	ref := time.Date(2019, time.March, 14, 10, 27, 42, 0, time.UTC)
+	tests := []struct {
+		name       string
+		expression string
+		from       time.Time
+		want       time.Time
+	}{
+		{"EveryMinute", "* * * * *", ref, time.Date(2019, time.March, 14, 10, 28, 0, 0, time.UTC)},
+		{"Nightly", "0 2 * * *", ref, time.Date(2019, time.March, 15, 2, 0, 0, 0, time.UTC)},
+		{"Quarters", "*/15 * * * *", ref, time.Date(2019, time.March, 14, 10, 30, 0, 0, time.UTC)},
+		{"StrictlyAfter", "30 10 * * *", time.Date(2019, time.March, 14, 10, 30, 0, 0, time.UTC), time.Date(2019, time.March, 15, 10, 30, 0, 0, time.UTC)},
+		{"FridayEvening", "0 18 * * fri", ref, time.Date(2019, time.March, 15, 18, 0, 0, 0, time.UTC)},
+		{"SundayAsSeven", "0 0 * * 7", ref, time.Date(2019, time.March, 17, 0, 0, 0, 0, time.UTC)},
+		{"NextYear", "0 0 1 jan *", ref, time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"LeapDay", "0 0 29 2 *", ref, time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"DayOfMonthOrDayOfWeek", "0 0 1 * mon", ref, time.Date(2019, time.March, 18, 0, 0, 0, 0, time.UTC)},
+		{"DayOfWeekStepIsUnrestrictedDay", "0 0 20 * */1", ref, time.Date(2019, time.March, 20, 0, 0, 0, 0, time.UTC)},
+		{"Never", "0 0 30 feb *", ref, time.Time{}},
+		{"Location", "0 2 * * *", ref.In(paris), time.Date(2019, time.March, 15, 2, 0, 0, 0, paris)},
+		{"SkippedByDaylightSaving", "30 2 * * *", time.Date(2019, time.March, 30, 12, 0, 0, 0, paris), time.Date(2019, time.April, 1, 2, 30, 0, 0, paris)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := Parse(tt.expression)
+			require.NoError(t, err)
+			got := e.Next(tt.from)
+			require.True(t, tt.want.Equal(got), "expected %v, got %v", tt.want, got)
+		})
+	}
+}
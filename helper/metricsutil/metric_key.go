@@ -15,9 +15,12 @@
 package metricsutil
 
 import (
+	"regexp"
 	"strings"
 )
 
+var labelNameForbiddenChars = regexp.MustCompile("[^a-zA-Z0-9_]")
+
 // CleanupMetricKey replaces any reserved characters in statsd/statsite and prometheus by '-'.
 func CleanupMetricKey(key []string) []string {
 	res := make([]string, len(key))
@@ -33,3 +36,8 @@ func CleanupMetricKey(key []string) []string {
 	}
 	return res
 }
+
+// CleanupLabelName replaces any character not allowed in prometheus labels names by '_'.
+func CleanupLabelName(name string) string {
+	return labelNameForbiddenChars.ReplaceAllString(name, "_")
+}
@@ -0,0 +1,241 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package redactutil allows to hide sensitive values, like resolved secrets, from logs and generated files.
+package redactutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/log"
+)
+
+// RedactedValue is the value replacing secrets
+const RedactedValue = "<redacted>"
+
+// MinSecretLength is the minimum length of a value to be redacted.
+//
+// Shorter values are ignored to prevent hiding unrelated content.
+const MinSecretLength = 4
+
+// SecretsLoader returns the secrets values of a deployment, including the ones resolved by other Yorc servers of
+// the cluster or before a restart
+type SecretsLoader func(deploymentID string) ([]string, error)
+
+// reloadInterval is the minimum interval between two loads of the secrets of a deployment
+var reloadInterval = 30 * time.Second
+
+type registry struct {
+	lock      sync.RWMutex
+	secrets   map[string]map[string]struct{}
+	replacers map[string]*strings.Replacer
+	loader    SecretsLoader
+	// loaded are the dates of the last loads of deployments secrets
+	loaded map[string]time.Time
+}
+
+func newRegistry() *registry {
+	return &registry{
+		secrets:   make(map[string]map[string]struct{}),
+		replacers: make(map[string]*strings.Replacer),
+		loaded:    make(map[string]time.Time),
+	}
+}
+
+var defaultRegistry = newRegistry()
+
+// SetSecretsLoader sets the function used to rebuild the secrets of a deployment which are not known by this process.
+//
+// Secrets of a deployment are loaded before they are first redacted and then reloaded periodically to get the
+// secrets resolved by other Yorc servers.
+func SetSecretsLoader(loader SecretsLoader) {
+	defaultRegistry.lock.Lock()
+	defer defaultRegistry.lock.Unlock()
+	defaultRegistry.loader = loader
+}
+
+// RegisterSecret registers a secret value to be redacted for the given deployment.
+//
+// A secret registered with an empty deploymentID is redacted for all deployments.
+func RegisterSecret(deploymentID, value string) {
+	defaultRegistry.register(deploymentID, value)
+}
+
+// ForgetDeployment removes secrets registered for the given deployment
+func ForgetDeployment(deploymentID string) {
+	defaultRegistry.forget(deploymentID)
+}
+
+// Redact replaces secrets registered for the given deployment by RedactedValue
+func Redact(deploymentID, content string) string {
+	defaultRegistry.load(deploymentID)
+	r := defaultRegistry.replacer(deploymentID)
+	if r == nil {
+		return content
+	}
+	return r.Replace(content)
+}
+
+// RedactFiles replaces secrets registered for the given deployment by RedactedValue in all
+// regular files of the given directory, recursively.
+func RedactFiles(deploymentID, dir string) error {
+	defaultRegistry.load(deploymentID)
+	r := defaultRegistry.replacer(deploymentID)
+	if r == nil {
+		return nil
+	}
+	return filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return errors.Wrapf(err, "failed to redact secrets from file %q", p)
+		}
+		redacted := []byte(r.Replace(string(b)))
+		if bytes.Equal(b, redacted) {
+			return nil
+		}
+		return errors.Wrapf(ioutil.WriteFile(p, redacted, info.Mode().Perm()), "failed to redact secrets from file %q", p)
+	})
+}
+
+// variants returns the forms of a value as it could appear in logs or files
+func variants(value string) []string {
+	res := []string{value}
+	// Values containing special characters like new lines are escaped in JSON or YAML files
+	if b, err := json.Marshal(value); err == nil {
+		if escaped := string(b[1 : len(b)-1]); escaped != value {
+			res = append(res, escaped)
+		}
+	}
+	return res
+}
+
+func (r *registry) register(deploymentID, value string) {
+	if len(value) < MinSecretLength {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	secrets, ok := r.secrets[deploymentID]
+	if !ok {
+		secrets = make(map[string]struct{})
+		r.secrets[deploymentID] = secrets
+	}
+	if _, ok := secrets[value]; ok {
+		return
+	}
+	for _, v := range variants(value) {
+		secrets[v] = struct{}{}
+	}
+	if deploymentID == "" {
+		// Global secrets apply to all deployments
+		r.replacers = make(map[string]*strings.Replacer)
+	} else {
+		delete(r.replacers, deploymentID)
+	}
+}
+
+// load registers the secrets returned by the loader if they were not loaded recently
+func (r *registry) load(deploymentID string) {
+	if deploymentID == "" {
+		return
+	}
+	r.lock.RLock()
+	last, ok := r.loaded[deploymentID]
+	loaded := r.loader == nil || ok && time.Since(last) < reloadInterval
+	r.lock.RUnlock()
+	if loaded {
+		return
+	}
+
+	r.lock.Lock()
+	last, ok = r.loaded[deploymentID]
+	if r.loader == nil || ok && time.Since(last) < reloadInterval {
+		r.lock.Unlock()
+		return
+	}
+	loader := r.loader
+	// Set before loading to prevent concurrent loads, and loops if the loader logs
+	r.loaded[deploymentID] = time.Now()
+	r.lock.Unlock()
+
+	values, err := loader(deploymentID)
+	if err != nil {
+		log.Printf("[WARNING] Failed to load secrets of deployment %q, they may not be redacted: %v", deploymentID, err)
+		return
+	}
+	for _, v := range values {
+		r.register(deploymentID, v)
+	}
+}
+
+func (r *registry) forget(deploymentID string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.secrets, deploymentID)
+	delete(r.replacers, deploymentID)
+	delete(r.loaded, deploymentID)
+}
+
+func (r *registry) replacer(deploymentID string) *strings.Replacer {
+	r.lock.RLock()
+	replacer, ok := r.replacers[deploymentID]
+	r.lock.RUnlock()
+	if ok {
+		return replacer
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	var values []string
+	for v := range r.secrets[deploymentID] {
+		values = append(values, v)
+	}
+	if deploymentID != "" {
+		for v := range r.secrets[""] {
+			values = append(values, v)
+		}
+	}
+	if len(values) > 0 {
+		// Longest values first so that a secret containing another one is fully redacted
+		sort.Slice(values, func(i, j int) bool {
+			if len(values[i]) != len(values[j]) {
+				return len(values[i]) > len(values[j])
+			}
+			return values[i] < values[j]
+		})
+		oldnew := make([]string, 0, 2*len(values))
+		for _, v := range values {
+			oldnew = append(oldnew, v, RedactedValue)
+		}
+		replacer = strings.NewReplacer(oldnew...)
+	}
+	r.replacers[deploymentID] = replacer
+	return replacer
+}
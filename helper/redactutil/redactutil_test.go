@@ -0,0 +1,115 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	RegisterSecret("dep1", "mypassword")
+	RegisterSecret("dep1", "mypasswordlonger")
+	RegisterSecret("dep1", "abc")
+	RegisterSecret("dep1", "line1\nline2")
+	RegisterSecret("dep2", "otherpassword")
+	RegisterSecret("", "globaltoken")
+	defer func() {
+		ForgetDeployment("dep1")
+		ForgetDeployment("dep2")
+		ForgetDeployment("")
+	}()
+
+	tests := []struct {
+		name         string
+		deploymentID string
+		content      string
+		want         string
+	}{
+		{"Secret", "dep1", "password is mypassword.", "password is <redacted>."},
+		{"LongestFirst", "dep1", "password is mypasswordlonger", "password is <redacted>"},
+		{"TooShort", "dep1", "abc", "abc"},
+		{"Escaped", "dep1", `{"key": "line1\nline2"}`, `{"key": "<redacted>"}`},
+		{"OtherDeployment", "dep2", "mypassword otherpassword", "mypassword <redacted>"},
+		{"Global", "dep2", "token: globaltoken", "token: <redacted>"},
+		{"NoSecrets", "dep3", "mypassword globaltoken", "mypassword <redacted>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Redact(tt.deploymentID, tt.content))
+		})
+	}
+
+	ForgetDeployment("dep1")
+	require.Equal(t, "mypassword", Redact("dep1", "mypassword"))
+	RegisterSecret("dep1", "newpassword")
+	require.Equal(t, "<redacted>", Redact("dep1", "newpassword"))
+}
+
+func TestRedactFiles(t *testing.T) {
+	RegisterSecret("depFiles", "s3cr3tvalue")
+	defer ForgetDeployment("depFiles")
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "host_vars"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "host_vars", "host.yml"), []byte("password: s3cr3tvalue\n"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "run.sh"), []byte("echo hello"), 0700))
+
+	require.NoError(t, RedactFiles("depFiles", dir))
+	b, err := ioutil.ReadFile(filepath.Join(dir, "host_vars", "host.yml"))
+	require.NoError(t, err)
+	require.Equal(t, "password: <redacted>\n", string(b))
+	fi, err := os.Stat(filepath.Join(dir, "run.sh"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0700), fi.Mode().Perm())
+
+	require.NoError(t, RedactFiles("otherDep", dir))
+	require.Error(t, RedactFiles("depFiles", filepath.Join(dir, "missing")))
+}
+
+func TestRegistryLoad(t *testing.T) {
+	r := newRegistry()
+	var loads int
+	r.loader = func(deploymentID string) ([]string, error) {
+		loads++
+		if deploymentID == "failing" {
+			return nil, errors.New("expected failure")
+		}
+		return []string{"loadedsecret" + strconv.Itoa(loads)}, nil
+	}
+
+	r.load("dep1")
+	require.Equal(t, "<redacted>", r.replacer("dep1").Replace("loadedsecret1"))
+	r.load("dep1")
+	require.Equal(t, 1, loads, "secrets should not be reloaded before the reload interval")
+
+	defer func(interval time.Duration) { reloadInterval = interval }(reloadInterval)
+	reloadInterval = 0
+	r.load("dep1")
+	require.Equal(t, 2, loads)
+	require.Equal(t, "<redacted> <redacted>", r.replacer("dep1").Replace("loadedsecret1 loadedsecret2"))
+
+	r.load("")
+	require.Equal(t, 2, loads, "global secrets should not be loaded")
+	r.load("failing")
+	require.Nil(t, r.replacer("failing"))
+}
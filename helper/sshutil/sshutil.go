@@ -217,6 +217,37 @@ func (sw *SSHSessionWrapper) RunCommand(ctx context.Context, cmd string) error {
 	return sw.session.Run(cmd)
 }
 
+// DialUnix opens a connection to the given unix socket on the remote host through a dedicated SSH connection
+//
+// Closing the returned connection also closes the underlying SSH connection.
+func (client *SSHClient) DialUnix(ctx context.Context, socketPath string) (net.Conn, error) {
+	addr := net.JoinHostPort(client.Host, strconv.Itoa(client.Port))
+	netC, sshC, err := sessionsPool.dial(ctx, "tcp", addr, client.Config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open connection on %s", addr)
+	}
+	c, err := sshC.Dial("unix", socketPath)
+	if err != nil {
+		sshC.Close()
+		netC.Close()
+		return nil, errors.Wrapf(err, "failed to connect to remote socket %q on %s", socketPath, addr)
+	}
+	return &unixSocketConn{Conn: c, sshC: sshC}, nil
+}
+
+type unixSocketConn struct {
+	net.Conn
+	sshC *ssh.Client
+}
+
+func (c *unixSocketConn) Close() error {
+	err := c.Conn.Close()
+	if errC := c.sshC.Close(); err == nil {
+		err = errC
+	}
+	return err
+}
+
 // CopyFile allows to copy a reader over SSH with defined remote path and specific permissions
 // CopyFile allows to copy a reader over SSH with defined remote path and specific permissions
 func (client *SSHClient) CopyFile(source io.Reader, remotePath string, permissions string) error {
@@ -0,0 +1,50 @@
+// Copyright 2018 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ziputil
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// AddFile adds the content of the file at filePath to a zip archive as a compressed entry with the given name.
+//
+// Errors opening the file are returned with their original cause, allowing callers to ignore missing files.
+func AddFile(zw *zip.Writer, filePath, name string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	header, err := zip.FileInfoHeader(fi)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	header.Name = name
+	header.Method = zip.Deflate
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return errors.Wrapf(err, "failed to add %q to archive", name)
+	}
+	_, err = io.Copy(w, f)
+	return errors.Wrapf(err, "failed to add %q to archive", name)
+}
@@ -17,10 +17,14 @@ package main
 import (
 	"github.com/ystia/yorc/v4/commands"
 	_ "github.com/ystia/yorc/v4/commands/bootstrap"
+	_ "github.com/ystia/yorc/v4/commands/csar"
 	_ "github.com/ystia/yorc/v4/commands/deployments"
+	_ "github.com/ystia/yorc/v4/commands/deployments/rollouts"
+	_ "github.com/ystia/yorc/v4/commands/deployments/schedules"
 	_ "github.com/ystia/yorc/v4/commands/deployments/tasks"
 	_ "github.com/ystia/yorc/v4/commands/deployments/workflows"
 	_ "github.com/ystia/yorc/v4/commands/hostspool"
+	_ "github.com/ystia/yorc/v4/commands/infrausage"
 	_ "github.com/ystia/yorc/v4/commands/locations"
 	"github.com/ystia/yorc/v4/log"
 )
@@ -47,9 +47,10 @@ func NewClient(pluginPath string) *gplugin.Client {
 	SetupPluginCommunication()
 
 	return gplugin.NewClient(&gplugin.ClientConfig{
-		HandshakeConfig: HandshakeConfig,
-		Plugins:         getPlugins(nil),
-		Cmd:             exec.Command(pluginPath),
-		Logger:          logger,
+		HandshakeConfig:  HandshakeConfig,
+		VersionedPlugins: getVersionedPlugins(nil),
+		AllowedProtocols: []gplugin.Protocol{gplugin.ProtocolNetRPC, gplugin.ProtocolGRPC},
+		Cmd:              exec.Command(pluginPath),
+		Logger:           logger,
 	})
 }
@@ -0,0 +1,97 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/plugin/proto"
+)
+
+func getGRPCPlugins(opts *ServeOpts) map[string]plugin.Plugin {
+	if opts == nil {
+		opts = new(ServeOpts)
+	}
+	return map[string]plugin.Plugin{
+		DelegatePluginName:            &DelegateGRPCPlugin{F: opts.DelegateFunc, SupportedTypes: opts.DelegateSupportedTypes},
+		OperationPluginName:           &OperationGRPCPlugin{F: opts.OperationFunc, SupportedTypes: opts.OperationSupportedArtifactTypes},
+		ActionPluginName:              &ActionGRPCPlugin{F: opts.ActionFunc, ActionTypes: opts.ActionTypes},
+		DefinitionsPluginName:         &DefinitionsGRPCPlugin{Definitions: opts.Definitions},
+		ConfigManagerPluginName:       &ConfigManagerGRPCPlugin{PluginConfigManager: &defaultConfigManager{}},
+		InfraUsageCollectorPluginName: &InfraUsageCollectorGRPCPlugin{F: opts.InfraUsageCollectorFunc, SupportedInfras: opts.InfraUsageCollectorSupportedInfras},
+		StorePluginName:               &StoreGRPCPlugin{Builders: opts.StoreBuilders},
+	}
+}
+
+// getVersionedPlugins returns plugins sets for each supported protocol version
+func getVersionedPlugins(opts *ServeOpts) map[int]plugin.PluginSet {
+	return map[int]plugin.PluginSet{
+		NetRPCProtocolVersion: getPlugins(opts),
+		GRPCProtocolVersion:   getGRPCPlugins(opts),
+	}
+}
+
+func toProtoError(err error) *proto.Error {
+	if err == nil {
+		return nil
+	}
+	rpcErr, ok := err.(*RPCError)
+	if !ok {
+		rpcErr = NewRPCError(err)
+	}
+	return &proto.Error{Message: rpcErr.Message, Stack: rpcErr.Stack}
+}
+
+func fromProtoError(pErr *proto.Error) error {
+	if pErr == nil {
+		return nil
+	}
+	return &RPCError{Message: pErr.Message, Stack: pErr.Stack}
+}
+
+func encodeConfig(cfg config.Configuration) ([]byte, error) {
+	b, err := json.Marshal(cfg)
+	return b, errors.Wrap(err, "failed to encode configuration")
+}
+
+func decodeConfig(b []byte) (config.Configuration, error) {
+	var cfg config.Configuration
+	err := json.Unmarshal(b, &cfg)
+	return cfg, errors.Wrap(err, "failed to decode configuration")
+}
+
+func toProtoLogOptionalFields(lof events.LogOptionalFields) map[string]string {
+	fields := make(map[string]string, len(lof))
+	for k, v := range lof {
+		fields[k.String()] = fmt.Sprint(v)
+	}
+	return fields
+}
+
+func fromProtoLogOptionalFields(fields map[string]string) events.LogOptionalFields {
+	lof := make(events.LogOptionalFields, len(fields))
+	for ft := events.WorkFlowID; ft <= events.DeploymentLabels; ft++ {
+		if v, ok := fields[ft.String()]; ok {
+			lof[ft] = v
+		}
+	}
+	return lof
+}
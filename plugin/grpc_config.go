@@ -0,0 +1,225 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"text/template"
+
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/plugin/proto"
+	"github.com/ystia/yorc/v4/storage"
+	"github.com/ystia/yorc/v4/storage/store"
+	"github.com/ystia/yorc/v4/storage/types"
+)
+
+// ConfigManagerGRPCPlugin is the gRPC implementation of the ConfigManager plugin
+type ConfigManagerGRPCPlugin struct {
+	plugin.NetRPCUnsupportedPlugin
+	PluginConfigManager ConfigManager
+}
+
+// GRPCServer registers the ConfigManager gRPC service
+func (p *ConfigManagerGRPCPlugin) GRPCServer(b *plugin.GRPCBroker, s *grpc.Server) error {
+	proto.RegisterConfigManagerServer(s, &configManagerGRPCServer{impl: p.PluginConfigManager, broker: b})
+	return nil
+}
+
+// GRPCClient returns a ConfigManager implementation using gRPC
+func (p *ConfigManagerGRPCPlugin) GRPCClient(ctx context.Context, b *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &configManagerGRPCClient{client: proto.NewConfigManagerClient(c), broker: b}, nil
+}
+
+type configManagerGRPCClient struct {
+	client proto.ConfigManagerClient
+	broker *plugin.GRPCBroker
+}
+
+func (c *configManagerGRPCClient) SetupConfig(cfg config.Configuration) error {
+	b, err := encodeConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Serve to the plugin the services it may call back
+	id := c.broker.NextId()
+	// TODO: Check how and when to stop this routine when plugin stops ? using a custom operation ?
+	go c.broker.AcceptAndServe(id, func(opts []grpc.ServerOption) *grpc.Server {
+		s := grpc.NewServer(opts...)
+		proto.RegisterConfigTemplateResolverServer(s, &configTemplateResolverGRPCServer{})
+		proto.RegisterLogForwarderServer(s, &logForwarderGRPCServer{})
+		return s
+	})
+
+	resp, err := c.client.SetupConfig(context.Background(), &proto.SetupConfigRequest{Config: b, ServerId: id})
+	if err != nil {
+		return errors.Wrap(err, "Failed call ConfigManager setup for plugin")
+	}
+	return fromProtoError(resp.Error)
+}
+
+type configManagerGRPCServer struct {
+	proto.UnimplementedConfigManagerServer
+	impl   ConfigManager
+	broker *plugin.GRPCBroker
+}
+
+func (s *configManagerGRPCServer) SetupConfig(ctx context.Context, req *proto.SetupConfigRequest) (*proto.ErrorResponse, error) {
+	cfg, err := decodeConfig(req.Config)
+	if err != nil {
+		return &proto.ErrorResponse{Error: toProtoError(err)}, nil
+	}
+	conn, err := s.broker.Dial(req.ServerId)
+	if err != nil {
+		return &proto.ErrorResponse{Error: toProtoError(err)}, nil
+	}
+	config.DefaultConfigTemplateResolver = &configTemplateResolverGRPCClient{client: proto.NewConfigTemplateResolverClient(conn)}
+
+	err = s.impl.SetupConfig(cfg)
+	if err != nil {
+		return &proto.ErrorResponse{Error: toProtoError(err)}, nil
+	}
+
+	// Deployments logs are forwarded to the Yorc server that stores them
+	storage.OverrideStore(types.StoreTypeLog, &logForwardingStore{
+		Store:  storage.GetStore(types.StoreTypeLog),
+		client: proto.NewLogForwarderClient(conn),
+	})
+	return &proto.ErrorResponse{}, nil
+}
+
+type configTemplateResolverGRPCClient struct {
+	client proto.ConfigTemplateResolverClient
+}
+
+func (c *configTemplateResolverGRPCClient) SetTemplatesFunctions(fm template.FuncMap) {
+	// Not Implemented
+}
+
+func (c *configTemplateResolverGRPCClient) Disable() {
+	// Not implemented
+}
+
+func (c *configTemplateResolverGRPCClient) Enable() {
+	// Not implemented
+}
+
+func (c *configTemplateResolverGRPCClient) ResolveValueWithTemplates(key string, value interface{}) interface{} {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	resp, err := c.client.ResolveValueWithTemplates(context.Background(), &proto.ResolveValueRequest{Key: key, Value: b})
+	if err != nil {
+		return nil
+	}
+	var res interface{}
+	if json.Unmarshal(resp.Value, &res) != nil {
+		return nil
+	}
+	return res
+}
+
+type configTemplateResolverGRPCServer struct {
+	proto.UnimplementedConfigTemplateResolverServer
+}
+
+func (s *configTemplateResolverGRPCServer) ResolveValueWithTemplates(ctx context.Context, req *proto.ResolveValueRequest) (*proto.ResolveValueResponse, error) {
+	var value interface{}
+	err := json.Unmarshal(req.Value, &value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode value of key %q", req.Key)
+	}
+	res := config.DefaultConfigTemplateResolver.ResolveValueWithTemplates(req.Key, value)
+	b, err := json.Marshal(res)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to encode resolved value of key %q", req.Key)
+	}
+	return &proto.ResolveValueResponse{Value: b}, nil
+}
+
+// logForwarderGRPCServer stores logs entries sent by a plugin into the Yorc server log store
+type logForwarderGRPCServer struct {
+	proto.UnimplementedLogForwarderServer
+}
+
+func (s *logForwarderGRPCServer) ForwardLogs(stream proto.LogForwarder_ForwardLogsServer) error {
+	for {
+		entry, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&proto.Empty{})
+		}
+		if err != nil {
+			return err
+		}
+		err = storage.GetStore(types.StoreTypeLog).Set(stream.Context(), entry.Key, json.RawMessage(entry.Value))
+		if err != nil {
+			log.Printf("Failed to store log entry %q forwarded by plugin: %+v", entry.Key, err)
+		}
+	}
+}
+
+// logForwardingStore is the plugin side log store, it streams log entries
+// to the Yorc server instead of storing them.
+//
+// Other store operations are delegated to the store loaded by the plugin.
+// If the stream to the Yorc server is broken log entries are stored directly.
+type logForwardingStore struct {
+	store.Store
+	client proto.LogForwarderClient
+	lock   sync.Mutex
+	stream proto.LogForwarder_ForwardLogsClient
+}
+
+func (s *logForwardingStore) Set(ctx context.Context, k string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode log entry %q", k)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.stream == nil {
+		s.stream, err = s.client.ForwardLogs(context.Background())
+		if err != nil {
+			s.stream = nil
+			return s.Store.Set(ctx, k, v)
+		}
+	}
+	err = s.stream.Send(&proto.LogEntry{Key: k, Value: b})
+	if err != nil {
+		s.stream = nil
+		return s.Store.Set(ctx, k, v)
+	}
+	return nil
+}
+
+func (s *logForwardingStore) SetCollection(ctx context.Context, keyValues []store.KeyValueIn) error {
+	for _, kv := range keyValues {
+		err := s.Set(ctx, kv.Key, kv.Value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,486 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/plugin/proto"
+	"github.com/ystia/yorc/v4/prov"
+)
+
+// DelegateGRPCPlugin is the gRPC implementation of the delegate plugin
+type DelegateGRPCPlugin struct {
+	plugin.NetRPCUnsupportedPlugin
+	F              func() prov.DelegateExecutor
+	SupportedTypes []string
+}
+
+// GRPCServer registers the DelegateExecutor gRPC service
+func (p *DelegateGRPCPlugin) GRPCServer(b *plugin.GRPCBroker, s *grpc.Server) error {
+	srv := &delegateExecutorGRPCServer{supportedTypes: p.SupportedTypes}
+	if p.F != nil {
+		srv.impl = p.F()
+	}
+	proto.RegisterDelegateExecutorServer(s, srv)
+	return nil
+}
+
+// GRPCClient returns a DelegateExecutor implementation using gRPC
+func (p *DelegateGRPCPlugin) GRPCClient(ctx context.Context, b *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &delegateExecutorGRPCClient{client: proto.NewDelegateExecutorClient(c)}, nil
+}
+
+type delegateExecutorGRPCClient struct {
+	client proto.DelegateExecutorClient
+}
+
+func (c *delegateExecutorGRPCClient) ExecDelegate(ctx context.Context, conf config.Configuration, taskID, deploymentID, nodeName, delegateOperation string) error {
+	lof, ok := events.FromContext(ctx)
+	if !ok {
+		return errors.New("Missing contextual log optionnal fields")
+	}
+	b, err := encodeConfig(conf)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.ExecDelegate(ctx, &proto.ExecDelegateRequest{
+		Config:            b,
+		TaskId:            taskID,
+		DeploymentId:      deploymentID,
+		NodeName:          nodeName,
+		DelegateOperation: delegateOperation,
+		LogOptionalFields: toProtoLogOptionalFields(lof),
+	})
+	if err != nil {
+		return err
+	}
+	return fromProtoError(resp.Error)
+}
+
+func (c *delegateExecutorGRPCClient) GetSupportedTypes() ([]string, error) {
+	resp, err := c.client.GetSupportedTypes(context.Background(), &proto.Empty{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get supported types for delegate plugin")
+	}
+	return resp.Types, fromProtoError(resp.Error)
+}
+
+type delegateExecutorGRPCServer struct {
+	proto.UnimplementedDelegateExecutorServer
+	impl           prov.DelegateExecutor
+	supportedTypes []string
+}
+
+func (s *delegateExecutorGRPCServer) GetSupportedTypes(ctx context.Context, _ *proto.Empty) (*proto.TypesResponse, error) {
+	if s.impl == nil && len(s.supportedTypes) > 0 {
+		return &proto.TypesResponse{Error: toProtoError(NewRPCErrorFromMessage("If DelegateSupportedTypes is defined then you have to defined a DelegateFunc"))}, nil
+	}
+	return &proto.TypesResponse{Types: s.supportedTypes}, nil
+}
+
+func (s *delegateExecutorGRPCServer) ExecDelegate(ctx context.Context, req *proto.ExecDelegateRequest) (*proto.ErrorResponse, error) {
+	if s.impl == nil {
+		return &proto.ErrorResponse{Error: toProtoError(NewRPCErrorFromMessage("No delegate executor defined in this plugin"))}, nil
+	}
+	conf, err := decodeConfig(req.Config)
+	if err != nil {
+		return &proto.ErrorResponse{Error: toProtoError(err)}, nil
+	}
+	ctx = events.NewContext(ctx, fromProtoLogOptionalFields(req.LogOptionalFields))
+	err = s.impl.ExecDelegate(ctx, conf, req.TaskId, req.DeploymentId, req.NodeName, req.DelegateOperation)
+	return &proto.ErrorResponse{Error: toProtoError(err)}, nil
+}
+
+// OperationGRPCPlugin is the gRPC implementation of the operation plugin
+type OperationGRPCPlugin struct {
+	plugin.NetRPCUnsupportedPlugin
+	F              func() prov.OperationExecutor
+	SupportedTypes []string
+}
+
+// GRPCServer registers the OperationExecutor gRPC service
+func (p *OperationGRPCPlugin) GRPCServer(b *plugin.GRPCBroker, s *grpc.Server) error {
+	srv := &operationExecutorGRPCServer{supportedTypes: p.SupportedTypes}
+	if p.F != nil {
+		srv.impl = p.F()
+	}
+	proto.RegisterOperationExecutorServer(s, srv)
+	return nil
+}
+
+// GRPCClient returns an OperationExecutor implementation using gRPC
+func (p *OperationGRPCPlugin) GRPCClient(ctx context.Context, b *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &operationExecutorGRPCClient{client: proto.NewOperationExecutorClient(c)}, nil
+}
+
+type operationExecutorGRPCClient struct {
+	client proto.OperationExecutorClient
+}
+
+func (c *operationExecutorGRPCClient) newRequest(ctx context.Context, conf config.Configuration, taskID, deploymentID, nodeName string, operation prov.Operation, stepName string) (*proto.ExecOperationRequest, error) {
+	lof, ok := events.FromContext(ctx)
+	if !ok {
+		return nil, errors.New("Missing contextual log optionnal fields")
+	}
+	b, err := encodeConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+	op, err := json.Marshal(operation)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode operation")
+	}
+	return &proto.ExecOperationRequest{
+		Config:            b,
+		TaskId:            taskID,
+		DeploymentId:      deploymentID,
+		NodeName:          nodeName,
+		Operation:         op,
+		StepName:          stepName,
+		LogOptionalFields: toProtoLogOptionalFields(lof),
+	}, nil
+}
+
+func (c *operationExecutorGRPCClient) ExecOperation(ctx context.Context, conf config.Configuration, taskID, deploymentID, nodeName string, operation prov.Operation) error {
+	req, err := c.newRequest(ctx, conf, taskID, deploymentID, nodeName, operation, "")
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.ExecOperation(ctx, req)
+	if err != nil {
+		return err
+	}
+	return fromProtoError(resp.Error)
+}
+
+func (c *operationExecutorGRPCClient) ExecAsyncOperation(ctx context.Context, conf config.Configuration, taskID, deploymentID, nodeName string, operation prov.Operation, stepName string) (*prov.Action, time.Duration, error) {
+	req, err := c.newRequest(ctx, conf, taskID, deploymentID, nodeName, operation, stepName)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, err := c.client.ExecAsyncOperation(ctx, req)
+	if err != nil {
+		return nil, 0, err
+	}
+	var action *prov.Action
+	if len(resp.Action) > 0 {
+		action = new(prov.Action)
+		err = json.Unmarshal(resp.Action, action)
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "failed to decode monitoring action")
+		}
+	}
+	return action, time.Duration(resp.MonitoringInterval), fromProtoError(resp.Error)
+}
+
+func (c *operationExecutorGRPCClient) GetSupportedArtifactTypes() ([]string, error) {
+	resp, err := c.client.GetSupportedArtifactTypes(context.Background(), &proto.Empty{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get supported artifact types for operation plugin")
+	}
+	return resp.Types, fromProtoError(resp.Error)
+}
+
+type operationExecutorGRPCServer struct {
+	proto.UnimplementedOperationExecutorServer
+	impl           prov.OperationExecutor
+	supportedTypes []string
+}
+
+func (s *operationExecutorGRPCServer) GetSupportedArtifactTypes(ctx context.Context, _ *proto.Empty) (*proto.TypesResponse, error) {
+	if s.impl == nil && len(s.supportedTypes) > 0 {
+		return &proto.TypesResponse{Error: toProtoError(NewRPCErrorFromMessage("If OperationSupportedArtifactTypes is defined then you have to defined an OperationFunc"))}, nil
+	}
+	return &proto.TypesResponse{Types: s.supportedTypes}, nil
+}
+
+func (s *operationExecutorGRPCServer) decodeRequest(ctx context.Context, req *proto.ExecOperationRequest) (context.Context, config.Configuration, prov.Operation, error) {
+	var operation prov.Operation
+	if s.impl == nil {
+		return ctx, config.Configuration{}, operation, NewRPCErrorFromMessage("No operation executor defined in this plugin")
+	}
+	conf, err := decodeConfig(req.Config)
+	if err != nil {
+		return ctx, conf, operation, err
+	}
+	err = json.Unmarshal(req.Operation, &operation)
+	if err != nil {
+		return ctx, conf, operation, errors.Wrap(err, "failed to decode operation")
+	}
+	return events.NewContext(ctx, fromProtoLogOptionalFields(req.LogOptionalFields)), conf, operation, nil
+}
+
+func (s *operationExecutorGRPCServer) ExecOperation(ctx context.Context, req *proto.ExecOperationRequest) (*proto.ErrorResponse, error) {
+	ctx, conf, operation, err := s.decodeRequest(ctx, req)
+	if err != nil {
+		return &proto.ErrorResponse{Error: toProtoError(err)}, nil
+	}
+	err = s.impl.ExecOperation(ctx, conf, req.TaskId, req.DeploymentId, req.NodeName, operation)
+	return &proto.ErrorResponse{Error: toProtoError(err)}, nil
+}
+
+func (s *operationExecutorGRPCServer) ExecAsyncOperation(ctx context.Context, req *proto.ExecOperationRequest) (*proto.ExecAsyncOperationResponse, error) {
+	ctx, conf, operation, err := s.decodeRequest(ctx, req)
+	if err != nil {
+		return &proto.ExecAsyncOperationResponse{Error: toProtoError(err)}, nil
+	}
+	action, interval, err := s.impl.ExecAsyncOperation(ctx, conf, req.TaskId, req.DeploymentId, req.NodeName, operation, req.StepName)
+	resp := &proto.ExecAsyncOperationResponse{MonitoringInterval: int64(interval), Error: toProtoError(err)}
+	if action != nil {
+		resp.Action, err = json.Marshal(action)
+		if err != nil {
+			return &proto.ExecAsyncOperationResponse{Error: toProtoError(errors.Wrap(err, "failed to encode monitoring action"))}, nil
+		}
+	}
+	return resp, nil
+}
+
+// ActionGRPCPlugin is the gRPC implementation of the action plugin
+type ActionGRPCPlugin struct {
+	plugin.NetRPCUnsupportedPlugin
+	F           func() prov.ActionOperator
+	ActionTypes []string
+}
+
+// GRPCServer registers the ActionOperator gRPC service
+func (p *ActionGRPCPlugin) GRPCServer(b *plugin.GRPCBroker, s *grpc.Server) error {
+	srv := &actionOperatorGRPCServer{actionTypes: p.ActionTypes}
+	if p.F != nil {
+		srv.impl = p.F()
+	}
+	proto.RegisterActionOperatorServer(s, srv)
+	return nil
+}
+
+// GRPCClient returns an ActionOperator implementation using gRPC
+func (p *ActionGRPCPlugin) GRPCClient(ctx context.Context, b *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &actionOperatorGRPCClient{client: proto.NewActionOperatorClient(c)}, nil
+}
+
+type actionOperatorGRPCClient struct {
+	client proto.ActionOperatorClient
+}
+
+func (c *actionOperatorGRPCClient) ExecAction(ctx context.Context, conf config.Configuration, taskID, deploymentID string, action *prov.Action) (bool, error) {
+	lof, ok := events.FromContext(ctx)
+	if !ok {
+		return false, errors.New("Missing contextual log optional fields")
+	}
+	b, err := encodeConfig(conf)
+	if err != nil {
+		return false, err
+	}
+	a, err := json.Marshal(action)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to encode action")
+	}
+	resp, err := c.client.ExecAction(ctx, &proto.ExecActionRequest{
+		Config:            b,
+		TaskId:            taskID,
+		DeploymentId:      deploymentID,
+		Action:            a,
+		LogOptionalFields: toProtoLogOptionalFields(lof),
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to call ExecAction for plugin")
+	}
+	return resp.Deregister, fromProtoError(resp.Error)
+}
+
+func (c *actionOperatorGRPCClient) GetActionTypes() ([]string, error) {
+	resp, err := c.client.GetActionTypes(context.Background(), &proto.Empty{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get action types for plugin")
+	}
+	return resp.Types, fromProtoError(resp.Error)
+}
+
+type actionOperatorGRPCServer struct {
+	proto.UnimplementedActionOperatorServer
+	impl        prov.ActionOperator
+	actionTypes []string
+}
+
+func (s *actionOperatorGRPCServer) GetActionTypes(ctx context.Context, _ *proto.Empty) (*proto.TypesResponse, error) {
+	if s.impl == nil && len(s.actionTypes) > 0 {
+		return &proto.TypesResponse{Error: toProtoError(NewRPCErrorFromMessage("If ActionTypes is defined then you have to defined an ActionFunc"))}, nil
+	}
+	return &proto.TypesResponse{Types: s.actionTypes}, nil
+}
+
+func (s *actionOperatorGRPCServer) ExecAction(ctx context.Context, req *proto.ExecActionRequest) (*proto.ExecActionResponse, error) {
+	if s.impl == nil {
+		return &proto.ExecActionResponse{Error: toProtoError(NewRPCErrorFromMessage("No action operator defined in this plugin"))}, nil
+	}
+	conf, err := decodeConfig(req.Config)
+	if err != nil {
+		return &proto.ExecActionResponse{Error: toProtoError(err)}, nil
+	}
+	action := new(prov.Action)
+	err = json.Unmarshal(req.Action, action)
+	if err != nil {
+		return &proto.ExecActionResponse{Error: toProtoError(errors.Wrap(err, "failed to decode action"))}, nil
+	}
+	ctx = events.NewContext(ctx, fromProtoLogOptionalFields(req.LogOptionalFields))
+	deregister, err := s.impl.ExecAction(ctx, conf, req.TaskId, req.DeploymentId, action)
+	return &proto.ExecActionResponse{Deregister: deregister, Error: toProtoError(err)}, nil
+}
+
+// InfraUsageCollectorGRPCPlugin is the gRPC implementation of the infrastructure usage collector plugin
+type InfraUsageCollectorGRPCPlugin struct {
+	plugin.NetRPCUnsupportedPlugin
+	F               func() prov.InfraUsageCollector
+	SupportedInfras []string
+}
+
+// GRPCServer registers the InfraUsageCollector gRPC service
+func (p *InfraUsageCollectorGRPCPlugin) GRPCServer(b *plugin.GRPCBroker, s *grpc.Server) error {
+	srv := &infraUsageCollectorGRPCServer{supportedInfras: p.SupportedInfras}
+	if p.F != nil {
+		srv.impl = p.F()
+	}
+	proto.RegisterInfraUsageCollectorServer(s, srv)
+	return nil
+}
+
+// GRPCClient returns an InfraUsageCollector implementation using gRPC
+func (p *InfraUsageCollectorGRPCPlugin) GRPCClient(ctx context.Context, b *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &infraUsageCollectorGRPCClient{client: proto.NewInfraUsageCollectorClient(c)}, nil
+}
+
+type infraUsageCollectorGRPCClient struct {
+	client proto.InfraUsageCollectorClient
+}
+
+func (c *infraUsageCollectorGRPCClient) GetUsageInfo(ctx context.Context, conf config.Configuration, taskID, infraName, locationName string, params map[string]string) (map[string]interface{}, error) {
+	lof, ok := events.FromContext(ctx)
+	if !ok {
+		return nil, errors.New("Missing contextual log optionnal fields")
+	}
+	b, err := encodeConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.GetUsageInfo(ctx, &proto.GetUsageInfoRequest{
+		Config:            b,
+		TaskId:            taskID,
+		InfraName:         infraName,
+		LocationName:      locationName,
+		Params:            params,
+		LogOptionalFields: toProtoLogOptionalFields(lof),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fromProtoError(resp.Error)
+	}
+	var usage map[string]interface{}
+	if len(resp.UsageInfo) > 0 {
+		err = json.Unmarshal(resp.UsageInfo, &usage)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode usage information")
+		}
+	}
+	return usage, nil
+}
+
+func (c *infraUsageCollectorGRPCClient) GetSupportedInfras() ([]string, error) {
+	resp, err := c.client.GetSupportedInfras(context.Background(), &proto.Empty{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get supported infrastructures for plugin")
+	}
+	return resp.Types, fromProtoError(resp.Error)
+}
+
+type infraUsageCollectorGRPCServer struct {
+	proto.UnimplementedInfraUsageCollectorServer
+	impl            prov.InfraUsageCollector
+	supportedInfras []string
+}
+
+func (s *infraUsageCollectorGRPCServer) GetSupportedInfras(ctx context.Context, _ *proto.Empty) (*proto.TypesResponse, error) {
+	if s.impl == nil && len(s.supportedInfras) > 0 {
+		return &proto.TypesResponse{Error: toProtoError(NewRPCErrorFromMessage("If InfraUsageCollectorSupportedInfras is defined then you have to defined an InfraUsageCollectorFunc"))}, nil
+	}
+	return &proto.TypesResponse{Types: s.supportedInfras}, nil
+}
+
+func (s *infraUsageCollectorGRPCServer) GetUsageInfo(ctx context.Context, req *proto.GetUsageInfoRequest) (*proto.GetUsageInfoResponse, error) {
+	if s.impl == nil {
+		return &proto.GetUsageInfoResponse{Error: toProtoError(NewRPCErrorFromMessage("No infrastructure usage collector defined in this plugin"))}, nil
+	}
+	conf, err := decodeConfig(req.Config)
+	if err != nil {
+		return &proto.GetUsageInfoResponse{Error: toProtoError(err)}, nil
+	}
+	ctx = events.NewContext(ctx, fromProtoLogOptionalFields(req.LogOptionalFields))
+	usage, err := s.impl.GetUsageInfo(ctx, conf, req.TaskId, req.InfraName, req.LocationName, req.Params)
+	if err != nil {
+		return &proto.GetUsageInfoResponse{Error: toProtoError(err)}, nil
+	}
+	b, err := json.Marshal(usage)
+	if err != nil {
+		return &proto.GetUsageInfoResponse{Error: toProtoError(errors.Wrap(err, "failed to encode usage information"))}, nil
+	}
+	return &proto.GetUsageInfoResponse{UsageInfo: b}, nil
+}
+
+// DefinitionsGRPCPlugin is the gRPC implementation of the definitions plugin
+type DefinitionsGRPCPlugin struct {
+	plugin.NetRPCUnsupportedPlugin
+	Definitions map[string][]byte
+}
+
+// GRPCServer registers the Definitions gRPC service
+func (p *DefinitionsGRPCPlugin) GRPCServer(b *plugin.GRPCBroker, s *grpc.Server) error {
+	proto.RegisterDefinitionsServer(s, &definitionsGRPCServer{definitions: p.Definitions})
+	return nil
+}
+
+// GRPCClient returns a Definitions implementation using gRPC
+func (p *DefinitionsGRPCPlugin) GRPCClient(ctx context.Context, b *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &definitionsGRPCClient{client: proto.NewDefinitionsClient(c)}, nil
+}
+
+type definitionsGRPCClient struct {
+	client proto.DefinitionsClient
+}
+
+func (c *definitionsGRPCClient) GetDefinitions() (map[string][]byte, error) {
+	resp, err := c.client.GetDefinitions(context.Background(), &proto.Empty{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get definitions for plugin")
+	}
+	return resp.Definitions, fromProtoError(resp.Error)
+}
+
+type definitionsGRPCServer struct {
+	proto.UnimplementedDefinitionsServer
+	definitions map[string][]byte
+}
+
+func (s *definitionsGRPCServer) GetDefinitions(ctx context.Context, _ *proto.Empty) (*proto.DefinitionsResponse, error) {
+	return &proto.DefinitionsResponse{Definitions: s.definitions}, nil
+}
@@ -0,0 +1,232 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/plugin/proto"
+	"github.com/ystia/yorc/v4/storage/store"
+)
+
+// StoreGRPCPlugin is the gRPC implementation of the store plugin
+type StoreGRPCPlugin struct {
+	plugin.NetRPCUnsupportedPlugin
+	Builders map[string]store.Builder
+}
+
+// GRPCServer registers the Store gRPC service
+func (p *StoreGRPCPlugin) GRPCServer(b *plugin.GRPCBroker, s *grpc.Server) error {
+	proto.RegisterStoreServer(s, &storeGRPCServer{manager: newStoresManager(p.Builders)})
+	return nil
+}
+
+// GRPCClient returns a StoreBuilder implementation using gRPC
+func (p *StoreGRPCPlugin) GRPCClient(ctx context.Context, b *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &storeBuilderGRPCClient{client: proto.NewStoreClient(c)}, nil
+}
+
+type storeBuilderGRPCClient struct {
+	client proto.StoreClient
+}
+
+func (c *storeBuilderGRPCClient) GetStoreImplementations() ([]string, error) {
+	resp, err := c.client.GetStoreImplementations(context.Background(), &proto.Empty{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get store implementations for store plugin")
+	}
+	return resp.Types, fromProtoError(resp.Error)
+}
+
+func (c *storeBuilderGRPCClient) NewStore(cfg config.Configuration, storeConfig config.Store) (store.Store, error) {
+	b, err := encodeConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	sc, err := json.Marshal(storeConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode store configuration")
+	}
+	resp, err := c.client.NewStore(context.Background(), &proto.NewStoreRequest{Config: b, StoreConfig: sc})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create store for store plugin")
+	}
+	if err = fromProtoError(resp.Error); err != nil {
+		return nil, err
+	}
+	return &storeGRPCClient{client: c.client, name: storeConfig.Name}, nil
+}
+
+// storeGRPCClient is a store.Store implementation calling a store served by a plugin
+type storeGRPCClient struct {
+	client proto.StoreClient
+	name   string
+}
+
+func (s *storeGRPCClient) Set(ctx context.Context, k string, v interface{}) error {
+	return s.SetCollection(ctx, []store.KeyValueIn{{Key: k, Value: v}})
+}
+
+func (s *storeGRPCClient) SetCollection(ctx context.Context, keyValues []store.KeyValueIn) error {
+	kvs, err := toStoreKeyValues(keyValues)
+	if err != nil {
+		return err
+	}
+	req := &proto.StoreSetRequest{Store: s.name, KeyValues: make([]*proto.KeyValue, len(kvs))}
+	for i, kv := range kvs {
+		req.KeyValues[i] = &proto.KeyValue{Key: kv.Key, Value: kv.Value}
+	}
+	resp, err := s.client.Set(ctx, req)
+	if err != nil {
+		return err
+	}
+	return fromProtoError(resp.Error)
+}
+
+func (s *storeGRPCClient) Get(k string, v interface{}) (bool, error) {
+	resp, err := s.client.Get(context.Background(), &proto.StoreKeyRequest{Store: s.name, Key: k})
+	if err != nil {
+		return false, err
+	}
+	if err = fromProtoError(resp.Error); err != nil {
+		return false, err
+	}
+	return decodeStoreValue(resp.Found, resp.Value, v)
+}
+
+func (s *storeGRPCClient) Exist(k string) (bool, error) {
+	resp, err := s.client.Exist(context.Background(), &proto.StoreKeyRequest{Store: s.name, Key: k})
+	if err != nil {
+		return false, err
+	}
+	return resp.Exist, fromProtoError(resp.Error)
+}
+
+func (s *storeGRPCClient) Keys(k string) ([]string, error) {
+	resp, err := s.client.Keys(context.Background(), &proto.StoreKeyRequest{Store: s.name, Key: k})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Keys, fromProtoError(resp.Error)
+}
+
+func (s *storeGRPCClient) Delete(ctx context.Context, k string, recursive bool) error {
+	resp, err := s.client.Delete(ctx, &proto.StoreDeleteRequest{Store: s.name, Key: k, Recursive: recursive})
+	if err != nil {
+		return err
+	}
+	return fromProtoError(resp.Error)
+}
+
+func (s *storeGRPCClient) GetLastModifyIndex(k string) (uint64, error) {
+	resp, err := s.client.GetLastModifyIndex(context.Background(), &proto.StoreKeyRequest{Store: s.name, Key: k})
+	if err != nil {
+		return 0, err
+	}
+	return resp.Index, fromProtoError(resp.Error)
+}
+
+func (s *storeGRPCClient) List(ctx context.Context, k string, waitIndex uint64, timeout time.Duration) ([]store.KeyValueOut, uint64, error) {
+	resp, err := s.client.List(ctx, &proto.StoreListRequest{Store: s.name, Key: k, WaitIndex: waitIndex, Timeout: int64(timeout)})
+	if err != nil {
+		return nil, 0, err
+	}
+	if err = fromProtoError(resp.Error); err != nil {
+		return nil, resp.LastIndex, err
+	}
+	kvs, err := decodeStoreKeyValues(resp.KeyValues)
+	return kvs, resp.LastIndex, err
+}
+
+type storeGRPCServer struct {
+	proto.UnimplementedStoreServer
+	manager *storesManager
+}
+
+func (s *storeGRPCServer) GetStoreImplementations(ctx context.Context, _ *proto.Empty) (*proto.TypesResponse, error) {
+	return &proto.TypesResponse{Types: s.manager.implementations()}, nil
+}
+
+func (s *storeGRPCServer) NewStore(ctx context.Context, req *proto.NewStoreRequest) (*proto.ErrorResponse, error) {
+	conf, err := decodeConfig(req.Config)
+	if err != nil {
+		return &proto.ErrorResponse{Error: toProtoError(err)}, nil
+	}
+	var storeConfig config.Store
+	if err = json.Unmarshal(req.StoreConfig, &storeConfig); err != nil {
+		return &proto.ErrorResponse{Error: toProtoError(errors.Wrap(err, "failed to decode store configuration"))}, nil
+	}
+	return &proto.ErrorResponse{Error: toProtoError(s.manager.newStore(conf, storeConfig))}, nil
+}
+
+func (s *storeGRPCServer) Set(ctx context.Context, req *proto.StoreSetRequest) (*proto.ErrorResponse, error) {
+	kvs := make([]StoreKeyValue, len(req.KeyValues))
+	for i, kv := range req.KeyValues {
+		kvs[i] = StoreKeyValue{Key: kv.Key, Value: kv.Value}
+	}
+	return &proto.ErrorResponse{Error: toProtoError(s.manager.set(ctx, req.Store, kvs))}, nil
+}
+
+func (s *storeGRPCServer) Get(ctx context.Context, req *proto.StoreKeyRequest) (*proto.StoreGetResponse, error) {
+	found, value, err := s.manager.getValue(req.Store, req.Key)
+	return &proto.StoreGetResponse{Found: found, Value: value, Error: toProtoError(err)}, nil
+}
+
+func (s *storeGRPCServer) Exist(ctx context.Context, req *proto.StoreKeyRequest) (*proto.StoreExistResponse, error) {
+	st, err := s.manager.get(req.Store)
+	var exist bool
+	if err == nil {
+		exist, err = st.Exist(req.Key)
+	}
+	return &proto.StoreExistResponse{Exist: exist, Error: toProtoError(err)}, nil
+}
+
+func (s *storeGRPCServer) Keys(ctx context.Context, req *proto.StoreKeyRequest) (*proto.StoreKeysResponse, error) {
+	st, err := s.manager.get(req.Store)
+	var keys []string
+	if err == nil {
+		keys, err = st.Keys(req.Key)
+	}
+	return &proto.StoreKeysResponse{Keys: keys, Error: toProtoError(err)}, nil
+}
+
+func (s *storeGRPCServer) Delete(ctx context.Context, req *proto.StoreDeleteRequest) (*proto.ErrorResponse, error) {
+	st, err := s.manager.get(req.Store)
+	if err == nil {
+		err = st.Delete(ctx, req.Key, req.Recursive)
+	}
+	return &proto.ErrorResponse{Error: toProtoError(err)}, nil
+}
+
+func (s *storeGRPCServer) GetLastModifyIndex(ctx context.Context, req *proto.StoreKeyRequest) (*proto.StoreIndexResponse, error) {
+	st, err := s.manager.get(req.Store)
+	var index uint64
+	if err == nil {
+		index, err = st.GetLastModifyIndex(req.Key)
+	}
+	return &proto.StoreIndexResponse{Index: index, Error: toProtoError(err)}, nil
+}
+
+func (s *storeGRPCServer) List(ctx context.Context, req *proto.StoreListRequest) (*proto.StoreListResponse, error) {
+	kvs, lastIndex, err := s.manager.list(ctx, req.Store, req.Key, req.WaitIndex, time.Duration(req.Timeout))
+	return &proto.StoreListResponse{KeyValues: kvs, LastIndex: lastIndex, Error: toProtoError(err)}, nil
+}
@@ -0,0 +1,384 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/plugin/proto"
+	"github.com/ystia/yorc/v4/prov"
+	"github.com/ystia/yorc/v4/storage"
+	"github.com/ystia/yorc/v4/storage/store"
+	"github.com/ystia/yorc/v4/storage/types"
+)
+
+type mockLogStore struct {
+	store.Store
+	lock    sync.Mutex
+	entries map[string]json.RawMessage
+}
+
+func (s *mockLogStore) Set(ctx context.Context, k string, v interface{}) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.entries == nil {
+		s.entries = make(map[string]json.RawMessage)
+	}
+	s.entries[k] = v.(json.RawMessage)
+	return nil
+}
+
+func (s *mockLogStore) get(k string) (json.RawMessage, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	v, ok := s.entries[k]
+	return v, ok
+}
+
+func testLogOptionalFields() events.LogOptionalFields {
+	return events.LogOptionalFields{
+		events.WorkFlowID:    "testWF",
+		events.InterfaceName: "delegate",
+		events.OperationName: "myTest",
+	}
+}
+
+func TestVersionedPlugins(t *testing.T) {
+	t.Parallel()
+	versions := getVersionedPlugins(nil)
+	require.Len(t, versions, 2)
+	for name, p := range versions[NetRPCProtocolVersion] {
+		_, isGRPC := p.(plugin.GRPCPlugin)
+		assert.False(t, isGRPC, "plugin %q of the net/rpc protocol version should not be a gRPC plugin", name)
+	}
+	require.Len(t, versions[GRPCProtocolVersion], len(versions[NetRPCProtocolVersion]))
+	for name, p := range versions[GRPCProtocolVersion] {
+		_, isGRPC := p.(plugin.GRPCPlugin)
+		assert.True(t, isGRPC, "plugin %q of the gRPC protocol version should be a gRPC plugin", name)
+	}
+}
+
+func TestLogOptionalFieldsConversion(t *testing.T) {
+	t.Parallel()
+	lof := testLogOptionalFields()
+	lof[events.TaskExecutionID] = "taskExec"
+	lof[events.DeploymentLabels] = "env=prod,team=hpc"
+	fields := toProtoLogOptionalFields(lof)
+	require.Equal(t, "testWF", fields["workflowId"])
+	require.Equal(t, "taskExec", fields["alienTaskId"])
+	require.Equal(t, lof, fromProtoLogOptionalFields(fields))
+}
+
+type mockGRPCDelegateExecutor struct {
+	conf                                              config.Configuration
+	taskID, deploymentID, nodeName, delegateOperation string
+	lof                                               events.LogOptionalFields
+	started                                           chan struct{}
+}
+
+func (m *mockGRPCDelegateExecutor) ExecDelegate(ctx context.Context, conf config.Configuration, taskID, deploymentID, nodeName, delegateOperation string) error {
+	m.conf = conf
+	m.taskID = taskID
+	m.deploymentID = deploymentID
+	m.nodeName = nodeName
+	m.delegateOperation = delegateOperation
+	m.lof, _ = events.FromContext(ctx)
+
+	switch deploymentID {
+	case "TestCancel":
+		close(m.started)
+		<-ctx.Done()
+		return ctx.Err()
+	case "TestFailure":
+		return NewRPCError(errors.New("a failure occurred during plugin exec operation"))
+	}
+	return nil
+}
+
+func createMockGRPCDelegateExecutor(t *testing.T) (*mockGRPCDelegateExecutor, *plugin.GRPCClient, DelegateExecutor) {
+	mock := &mockGRPCDelegateExecutor{started: make(chan struct{})}
+	client, _ := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
+		DelegatePluginName: &DelegateGRPCPlugin{
+			F:              func() prov.DelegateExecutor { return mock },
+			SupportedTypes: []string{"tosca.my.types", "test"},
+		},
+	})
+	raw, err := client.Dispense(DelegatePluginName)
+	require.NoError(t, err)
+	return mock, client, raw.(DelegateExecutor)
+}
+
+func TestGRPCDelegateExecutor(t *testing.T) {
+	t.Parallel()
+	mock, client, delegate := createMockGRPCDelegateExecutor(t)
+	defer client.Close()
+
+	supportedTypes, err := delegate.GetSupportedTypes()
+	require.NoError(t, err)
+	require.Equal(t, []string{"tosca.my.types", "test"}, supportedTypes)
+
+	lof := testLogOptionalFields()
+	ctx := events.NewContext(context.Background(), lof)
+	conf := config.Configuration{Consul: config.Consul{Address: "test", Datacenter: "testdc"}}
+	err = delegate.ExecDelegate(ctx, conf, "TestTaskID", "TestDepID", "TestNodeName", "TestDelegateOP")
+	require.NoError(t, err)
+	require.Equal(t, "test", mock.conf.Consul.Address)
+	require.Equal(t, "testdc", mock.conf.Consul.Datacenter)
+	require.Equal(t, "TestTaskID", mock.taskID)
+	require.Equal(t, "TestDepID", mock.deploymentID)
+	require.Equal(t, "TestNodeName", mock.nodeName)
+	require.Equal(t, "TestDelegateOP", mock.delegateOperation)
+	assert.Equal(t, lof, mock.lof)
+
+	err = delegate.ExecDelegate(ctx, conf, "TestTaskID", "TestFailure", "TestNodeName", "TestDelegateOP")
+	require.EqualError(t, err, "a failure occurred during plugin exec operation")
+
+	err = delegate.ExecDelegate(context.Background(), conf, "TestTaskID", "TestDepID", "TestNodeName", "TestDelegateOP")
+	require.Error(t, err, "an error is expected when log optional fields are missing")
+}
+
+func TestGRPCDelegateExecutorWithCancel(t *testing.T) {
+	t.Parallel()
+	mock, client, delegate := createMockGRPCDelegateExecutor(t)
+	defer client.Close()
+
+	ctx, cancelF := context.WithCancel(events.NewContext(context.Background(), testLogOptionalFields()))
+	done := make(chan error)
+	go func() {
+		done <- delegate.ExecDelegate(ctx, config.Configuration{}, "TestTaskID", "TestCancel", "TestNodeName", "TestDelegateOP")
+	}()
+	<-mock.started
+	cancelF()
+	select {
+	case err := <-done:
+		require.Error(t, err, "a cancellation error is expected")
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "delegate execution not cancelled")
+	}
+}
+
+func TestGRPCDelegateExecutorMissingFunc(t *testing.T) {
+	t.Parallel()
+	client, _ := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
+		DelegatePluginName: &DelegateGRPCPlugin{SupportedTypes: []string{"test"}},
+	})
+	defer client.Close()
+	raw, err := client.Dispense(DelegatePluginName)
+	require.NoError(t, err)
+	_, err = raw.(DelegateExecutor).GetSupportedTypes()
+	require.Error(t, err)
+}
+
+type mockGRPCOperationExecutor struct {
+	operation prov.Operation
+	stepName  string
+}
+
+func (m *mockGRPCOperationExecutor) ExecOperation(ctx context.Context, conf config.Configuration, taskID, deploymentID, nodeName string, operation prov.Operation) error {
+	m.operation = operation
+	if deploymentID == "TestFailure" {
+		return errors.New("operation failure")
+	}
+	return nil
+}
+
+func (m *mockGRPCOperationExecutor) ExecAsyncOperation(ctx context.Context, conf config.Configuration, taskID, deploymentID, nodeName string, operation prov.Operation, stepName string) (*prov.Action, time.Duration, error) {
+	m.operation = operation
+	m.stepName = stepName
+	return &prov.Action{ID: "actionID", ActionType: "monitoring", Data: map[string]string{"k": "v"}}, 2 * time.Second, nil
+}
+
+func TestGRPCOperationExecutor(t *testing.T) {
+	t.Parallel()
+	mock := new(mockGRPCOperationExecutor)
+	client, _ := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
+		OperationPluginName: &OperationGRPCPlugin{
+			F:              func() prov.OperationExecutor { return mock },
+			SupportedTypes: []string{"tosca.artifacts.Implementation.Bash"},
+		},
+	})
+	defer client.Close()
+	raw, err := client.Dispense(OperationPluginName)
+	require.NoError(t, err)
+	opExec := raw.(OperationExecutor)
+
+	types, err := opExec.GetSupportedArtifactTypes()
+	require.NoError(t, err)
+	require.Equal(t, []string{"tosca.artifacts.Implementation.Bash"}, types)
+
+	ctx := events.NewContext(context.Background(), testLogOptionalFields())
+	op := prov.Operation{Name: "standard.create", ImplementedInType: "yorc.types.A", RelOp: prov.RelationshipOperation{TargetNodeName: "node2"}}
+	err = opExec.ExecOperation(ctx, config.Configuration{}, "TestTaskID", "TestDepID", "TestNodeName", op)
+	require.NoError(t, err)
+	require.Equal(t, op, mock.operation)
+
+	err = opExec.ExecOperation(ctx, config.Configuration{}, "TestTaskID", "TestFailure", "TestNodeName", op)
+	require.EqualError(t, err, "operation failure")
+
+	action, interval, err := opExec.ExecAsyncOperation(ctx, config.Configuration{}, "TestTaskID", "TestDepID", "TestNodeName", op, "step")
+	require.NoError(t, err)
+	require.Equal(t, 2*time.Second, interval)
+	require.Equal(t, "step", mock.stepName)
+	require.NotNil(t, action)
+	require.Equal(t, "actionID", action.ID)
+	require.Equal(t, "monitoring", action.ActionType)
+	require.Equal(t, map[string]string{"k": "v"}, action.Data)
+}
+
+type mockGRPCActionOperator struct {
+	action *prov.Action
+}
+
+func (m *mockGRPCActionOperator) ExecAction(ctx context.Context, conf config.Configuration, taskID, deploymentID string, action *prov.Action) (bool, error) {
+	m.action = action
+	return true, nil
+}
+
+func TestGRPCActionOperator(t *testing.T) {
+	t.Parallel()
+	mock := new(mockGRPCActionOperator)
+	client, _ := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
+		ActionPluginName: &ActionGRPCPlugin{
+			F:           func() prov.ActionOperator { return mock },
+			ActionTypes: []string{"monitoring"},
+		},
+	})
+	defer client.Close()
+	raw, err := client.Dispense(ActionPluginName)
+	require.NoError(t, err)
+	operator := raw.(ActionOperator)
+
+	actionTypes, err := operator.GetActionTypes()
+	require.NoError(t, err)
+	require.Equal(t, []string{"monitoring"}, actionTypes)
+
+	ctx := events.NewContext(context.Background(), testLogOptionalFields())
+	deregister, err := operator.ExecAction(ctx, config.Configuration{}, "TestTaskID", "TestDepID", &prov.Action{ID: "actionID", Data: map[string]string{"k": "v"}})
+	require.NoError(t, err)
+	require.True(t, deregister)
+	require.Equal(t, "actionID", mock.action.ID)
+	require.Equal(t, map[string]string{"k": "v"}, mock.action.Data)
+}
+
+type mockGRPCInfraUsageCollector struct {
+	params map[string]string
+}
+
+func (m *mockGRPCInfraUsageCollector) GetUsageInfo(ctx context.Context, cfg config.Configuration, taskID, infraName, locationName string, params map[string]string) (map[string]interface{}, error) {
+	m.params = params
+	return map[string]interface{}{"nodes": map[string]interface{}{"free": 2.0}}, nil
+}
+
+func TestGRPCInfraUsageCollector(t *testing.T) {
+	t.Parallel()
+	mock := new(mockGRPCInfraUsageCollector)
+	client, _ := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
+		InfraUsageCollectorPluginName: &InfraUsageCollectorGRPCPlugin{
+			F:               func() prov.InfraUsageCollector { return mock },
+			SupportedInfras: []string{"myInfra"},
+		},
+	})
+	defer client.Close()
+	raw, err := client.Dispense(InfraUsageCollectorPluginName)
+	require.NoError(t, err)
+	collector := raw.(InfraUsageCollector)
+
+	infras, err := collector.GetSupportedInfras()
+	require.NoError(t, err)
+	require.Equal(t, []string{"myInfra"}, infras)
+
+	ctx := events.NewContext(context.Background(), testLogOptionalFields())
+	usage, err := collector.GetUsageInfo(ctx, config.Configuration{}, "TestTaskID", "myInfra", "myLocation", map[string]string{"p": "v"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"nodes": map[string]interface{}{"free": 2.0}}, usage)
+	require.Equal(t, map[string]string{"p": "v"}, mock.params)
+}
+
+func TestGRPCDefinitions(t *testing.T) {
+	t.Parallel()
+	client, _ := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
+		DefinitionsPluginName: &DefinitionsGRPCPlugin{Definitions: map[string][]byte{"def.yaml": []byte("content")}},
+	})
+	defer client.Close()
+	raw, err := client.Dispense(DefinitionsPluginName)
+	require.NoError(t, err)
+	defs, err := raw.(Definitions).GetDefinitions()
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{"def.yaml": []byte("content")}, defs)
+}
+
+func TestGRPCConfigManagerAndLogForwarding(t *testing.T) {
+	// Not parallel as it changes stores
+	logStore := new(mockLogStore)
+	storage.OverrideStore(types.StoreTypeLog, logStore)
+
+	mock := new(mockConfigManager)
+	client, _ := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
+		ConfigManagerPluginName: &ConfigManagerGRPCPlugin{PluginConfigManager: mock},
+	})
+	defer client.Close()
+	raw, err := client.Dispense(ConfigManagerPluginName)
+	require.NoError(t, err)
+	cm := raw.(ConfigManager)
+
+	err = cm.SetupConfig(config.Configuration{Consul: config.Consul{Address: "testFailure"}})
+	require.Error(t, err)
+
+	err = cm.SetupConfig(config.Configuration{Consul: config.Consul{Address: "test", Datacenter: "testdc"}})
+	require.NoError(t, err)
+	require.True(t, mock.setupConfigCalled)
+	require.Equal(t, "test", mock.conf.Consul.Address)
+	require.Equal(t, "testdc", mock.conf.Consul.Datacenter)
+
+	// Log store is now a forwarding store as it is in a plugin
+	fwd, ok := storage.GetStore(types.StoreTypeLog).(*logForwardingStore)
+	require.True(t, ok, "log store should be a log forwarding store")
+	require.Equal(t, logStore, fwd.Store)
+	// In this test both the plugin and the Yorc server share the same stores,
+	// restore the Yorc server log store that receives forwarded entries.
+	storage.OverrideStore(types.StoreTypeLog, logStore)
+	err = fwd.Set(context.Background(), "_yorc/logs/dep/1", json.RawMessage(`{"content":"msg"}`))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		v, ok := logStore.get("_yorc/logs/dep/1")
+		return ok && string(v) == `{"content":"msg"}`
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestLogForwardingStoreFallback(t *testing.T) {
+	t.Parallel()
+	conn, server := plugin.TestGRPCConn(t, func(s *grpc.Server) {})
+	fallback := new(mockLogStore)
+	fwd := &logForwardingStore{Store: fallback, client: proto.NewLogForwarderClient(conn)}
+	server.Stop()
+	conn.Close()
+
+	err := fwd.SetCollection(context.Background(), []store.KeyValueIn{{Key: "k1", Value: json.RawMessage(`{}`)}})
+	require.NoError(t, err)
+	_, ok := fallback.get("k1")
+	require.True(t, ok, "log entry should have been stored directly")
+}
@@ -0,0 +1,2794 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: plugin.proto
+
+// Package yorc.plugin defines the gRPC protocol (protocol version 4) used
+// between the Yorc server and its plugins.
+//
+// Complex Yorc structures (server configuration, TOSCA operations, actions,
+// usage information...) are exchanged as JSON documents.
+
+package proto
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Empty is used for requests and responses without content.
+type Empty struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Empty) Reset()         { *m = Empty{} }
+func (m *Empty) String() string { return proto.CompactTextString(m) }
+func (*Empty) ProtoMessage()    {}
+func (*Empty) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{0}
+}
+
+func (m *Empty) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Empty.Unmarshal(m, b)
+}
+func (m *Empty) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Empty.Marshal(b, m, deterministic)
+}
+func (m *Empty) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Empty.Merge(m, src)
+}
+func (m *Empty) XXX_Size() int {
+	return xxx_messageInfo_Empty.Size(m)
+}
+func (m *Empty) XXX_DiscardUnknown() {
+	xxx_messageInfo_Empty.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Empty proto.InternalMessageInfo
+
+// Error is an error returned by a plugin.
+type Error struct {
+	Message              string   `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Stack                string   `protobuf:"bytes,2,opt,name=stack,proto3" json:"stack,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Error) Reset()         { *m = Error{} }
+func (m *Error) String() string { return proto.CompactTextString(m) }
+func (*Error) ProtoMessage()    {}
+func (*Error) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{1}
+}
+
+func (m *Error) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Error.Unmarshal(m, b)
+}
+func (m *Error) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Error.Marshal(b, m, deterministic)
+}
+func (m *Error) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Error.Merge(m, src)
+}
+func (m *Error) XXX_Size() int {
+	return xxx_messageInfo_Error.Size(m)
+}
+func (m *Error) XXX_DiscardUnknown() {
+	xxx_messageInfo_Error.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Error proto.InternalMessageInfo
+
+func (m *Error) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *Error) GetStack() string {
+	if m != nil {
+		return m.Stack
+	}
+	return ""
+}
+
+// ErrorResponse is a response that may only carry an error.
+type ErrorResponse struct {
+	Error                *Error   `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ErrorResponse) Reset()         { *m = ErrorResponse{} }
+func (m *ErrorResponse) String() string { return proto.CompactTextString(m) }
+func (*ErrorResponse) ProtoMessage()    {}
+func (*ErrorResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{2}
+}
+
+func (m *ErrorResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ErrorResponse.Unmarshal(m, b)
+}
+func (m *ErrorResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ErrorResponse.Marshal(b, m, deterministic)
+}
+func (m *ErrorResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ErrorResponse.Merge(m, src)
+}
+func (m *ErrorResponse) XXX_Size() int {
+	return xxx_messageInfo_ErrorResponse.Size(m)
+}
+func (m *ErrorResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ErrorResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ErrorResponse proto.InternalMessageInfo
+
+func (m *ErrorResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+// TypesResponse lists types supported by a plugin.
+type TypesResponse struct {
+	Types                []string `protobuf:"bytes,1,rep,name=types,proto3" json:"types,omitempty"`
+	Error                *Error   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TypesResponse) Reset()         { *m = TypesResponse{} }
+func (m *TypesResponse) String() string { return proto.CompactTextString(m) }
+func (*TypesResponse) ProtoMessage()    {}
+func (*TypesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{3}
+}
+
+func (m *TypesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TypesResponse.Unmarshal(m, b)
+}
+func (m *TypesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TypesResponse.Marshal(b, m, deterministic)
+}
+func (m *TypesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TypesResponse.Merge(m, src)
+}
+func (m *TypesResponse) XXX_Size() int {
+	return xxx_messageInfo_TypesResponse.Size(m)
+}
+func (m *TypesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TypesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TypesResponse proto.InternalMessageInfo
+
+func (m *TypesResponse) GetTypes() []string {
+	if m != nil {
+		return m.Types
+	}
+	return nil
+}
+
+func (m *TypesResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+// SetupConfigRequest sends the Yorc server configuration to a plugin.
+type SetupConfigRequest struct {
+	// JSON encoded server configuration
+	Config []byte `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	// Broker ID on which the Yorc server serves the ConfigTemplateResolver
+	// and LogForwarder services
+	ServerId             uint32   `protobuf:"varint,2,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetupConfigRequest) Reset()         { *m = SetupConfigRequest{} }
+func (m *SetupConfigRequest) String() string { return proto.CompactTextString(m) }
+func (*SetupConfigRequest) ProtoMessage()    {}
+func (*SetupConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{4}
+}
+
+func (m *SetupConfigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetupConfigRequest.Unmarshal(m, b)
+}
+func (m *SetupConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetupConfigRequest.Marshal(b, m, deterministic)
+}
+func (m *SetupConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetupConfigRequest.Merge(m, src)
+}
+func (m *SetupConfigRequest) XXX_Size() int {
+	return xxx_messageInfo_SetupConfigRequest.Size(m)
+}
+func (m *SetupConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetupConfigRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetupConfigRequest proto.InternalMessageInfo
+
+func (m *SetupConfigRequest) GetConfig() []byte {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+func (m *SetupConfigRequest) GetServerId() uint32 {
+	if m != nil {
+		return m.ServerId
+	}
+	return 0
+}
+
+// DefinitionsResponse contains TOSCA definitions exported by a plugin.
+type DefinitionsResponse struct {
+	Definitions          map[string][]byte `protobuf:"bytes,1,rep,name=definitions,proto3" json:"definitions,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Error                *Error            `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *DefinitionsResponse) Reset()         { *m = DefinitionsResponse{} }
+func (m *DefinitionsResponse) String() string { return proto.CompactTextString(m) }
+func (*DefinitionsResponse) ProtoMessage()    {}
+func (*DefinitionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{5}
+}
+
+func (m *DefinitionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DefinitionsResponse.Unmarshal(m, b)
+}
+func (m *DefinitionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DefinitionsResponse.Marshal(b, m, deterministic)
+}
+func (m *DefinitionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DefinitionsResponse.Merge(m, src)
+}
+func (m *DefinitionsResponse) XXX_Size() int {
+	return xxx_messageInfo_DefinitionsResponse.Size(m)
+}
+func (m *DefinitionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DefinitionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DefinitionsResponse proto.InternalMessageInfo
+
+func (m *DefinitionsResponse) GetDefinitions() map[string][]byte {
+	if m != nil {
+		return m.Definitions
+	}
+	return nil
+}
+
+func (m *DefinitionsResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+// ExecDelegateRequest is the request to execute a delegate operation.
+type ExecDelegateRequest struct {
+	Config               []byte            `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	TaskId               string            `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	DeploymentId         string            `protobuf:"bytes,3,opt,name=deployment_id,json=deploymentId,proto3" json:"deployment_id,omitempty"`
+	NodeName             string            `protobuf:"bytes,4,opt,name=node_name,json=nodeName,proto3" json:"node_name,omitempty"`
+	DelegateOperation    string            `protobuf:"bytes,5,opt,name=delegate_operation,json=delegateOperation,proto3" json:"delegate_operation,omitempty"`
+	LogOptionalFields    map[string]string `protobuf:"bytes,6,rep,name=log_optional_fields,json=logOptionalFields,proto3" json:"log_optional_fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ExecDelegateRequest) Reset()         { *m = ExecDelegateRequest{} }
+func (m *ExecDelegateRequest) String() string { return proto.CompactTextString(m) }
+func (*ExecDelegateRequest) ProtoMessage()    {}
+func (*ExecDelegateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{6}
+}
+
+func (m *ExecDelegateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecDelegateRequest.Unmarshal(m, b)
+}
+func (m *ExecDelegateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecDelegateRequest.Marshal(b, m, deterministic)
+}
+func (m *ExecDelegateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecDelegateRequest.Merge(m, src)
+}
+func (m *ExecDelegateRequest) XXX_Size() int {
+	return xxx_messageInfo_ExecDelegateRequest.Size(m)
+}
+func (m *ExecDelegateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecDelegateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecDelegateRequest proto.InternalMessageInfo
+
+func (m *ExecDelegateRequest) GetConfig() []byte {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+func (m *ExecDelegateRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *ExecDelegateRequest) GetDeploymentId() string {
+	if m != nil {
+		return m.DeploymentId
+	}
+	return ""
+}
+
+func (m *ExecDelegateRequest) GetNodeName() string {
+	if m != nil {
+		return m.NodeName
+	}
+	return ""
+}
+
+func (m *ExecDelegateRequest) GetDelegateOperation() string {
+	if m != nil {
+		return m.DelegateOperation
+	}
+	return ""
+}
+
+func (m *ExecDelegateRequest) GetLogOptionalFields() map[string]string {
+	if m != nil {
+		return m.LogOptionalFields
+	}
+	return nil
+}
+
+// ExecOperationRequest is the request to execute a TOSCA operation.
+type ExecOperationRequest struct {
+	Config       []byte `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	TaskId       string `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	DeploymentId string `protobuf:"bytes,3,opt,name=deployment_id,json=deploymentId,proto3" json:"deployment_id,omitempty"`
+	NodeName     string `protobuf:"bytes,4,opt,name=node_name,json=nodeName,proto3" json:"node_name,omitempty"`
+	// JSON encoded operation
+	Operation            []byte            `protobuf:"bytes,5,opt,name=operation,proto3" json:"operation,omitempty"`
+	StepName             string            `protobuf:"bytes,6,opt,name=step_name,json=stepName,proto3" json:"step_name,omitempty"`
+	LogOptionalFields    map[string]string `protobuf:"bytes,7,rep,name=log_optional_fields,json=logOptionalFields,proto3" json:"log_optional_fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ExecOperationRequest) Reset()         { *m = ExecOperationRequest{} }
+func (m *ExecOperationRequest) String() string { return proto.CompactTextString(m) }
+func (*ExecOperationRequest) ProtoMessage()    {}
+func (*ExecOperationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{7}
+}
+
+func (m *ExecOperationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecOperationRequest.Unmarshal(m, b)
+}
+func (m *ExecOperationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecOperationRequest.Marshal(b, m, deterministic)
+}
+func (m *ExecOperationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecOperationRequest.Merge(m, src)
+}
+func (m *ExecOperationRequest) XXX_Size() int {
+	return xxx_messageInfo_ExecOperationRequest.Size(m)
+}
+func (m *ExecOperationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecOperationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecOperationRequest proto.InternalMessageInfo
+
+func (m *ExecOperationRequest) GetConfig() []byte {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+func (m *ExecOperationRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *ExecOperationRequest) GetDeploymentId() string {
+	if m != nil {
+		return m.DeploymentId
+	}
+	return ""
+}
+
+func (m *ExecOperationRequest) GetNodeName() string {
+	if m != nil {
+		return m.NodeName
+	}
+	return ""
+}
+
+func (m *ExecOperationRequest) GetOperation() []byte {
+	if m != nil {
+		return m.Operation
+	}
+	return nil
+}
+
+func (m *ExecOperationRequest) GetStepName() string {
+	if m != nil {
+		return m.StepName
+	}
+	return ""
+}
+
+func (m *ExecOperationRequest) GetLogOptionalFields() map[string]string {
+	if m != nil {
+		return m.LogOptionalFields
+	}
+	return nil
+}
+
+// ExecAsyncOperationResponse is the response of an asynchronous operation execution.
+type ExecAsyncOperationResponse struct {
+	// JSON encoded monitoring action
+	Action []byte `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	// Monitoring interval in nanoseconds
+	MonitoringInterval   int64    `protobuf:"varint,2,opt,name=monitoring_interval,json=monitoringInterval,proto3" json:"monitoring_interval,omitempty"`
+	Error                *Error   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExecAsyncOperationResponse) Reset()         { *m = ExecAsyncOperationResponse{} }
+func (m *ExecAsyncOperationResponse) String() string { return proto.CompactTextString(m) }
+func (*ExecAsyncOperationResponse) ProtoMessage()    {}
+func (*ExecAsyncOperationResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{8}
+}
+
+func (m *ExecAsyncOperationResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecAsyncOperationResponse.Unmarshal(m, b)
+}
+func (m *ExecAsyncOperationResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecAsyncOperationResponse.Marshal(b, m, deterministic)
+}
+func (m *ExecAsyncOperationResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecAsyncOperationResponse.Merge(m, src)
+}
+func (m *ExecAsyncOperationResponse) XXX_Size() int {
+	return xxx_messageInfo_ExecAsyncOperationResponse.Size(m)
+}
+func (m *ExecAsyncOperationResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecAsyncOperationResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecAsyncOperationResponse proto.InternalMessageInfo
+
+func (m *ExecAsyncOperationResponse) GetAction() []byte {
+	if m != nil {
+		return m.Action
+	}
+	return nil
+}
+
+func (m *ExecAsyncOperationResponse) GetMonitoringInterval() int64 {
+	if m != nil {
+		return m.MonitoringInterval
+	}
+	return 0
+}
+
+func (m *ExecAsyncOperationResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+// ExecActionRequest is the request to execute an action.
+type ExecActionRequest struct {
+	Config       []byte `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	TaskId       string `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	DeploymentId string `protobuf:"bytes,3,opt,name=deployment_id,json=deploymentId,proto3" json:"deployment_id,omitempty"`
+	// JSON encoded action
+	Action               []byte            `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	LogOptionalFields    map[string]string `protobuf:"bytes,5,rep,name=log_optional_fields,json=logOptionalFields,proto3" json:"log_optional_fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ExecActionRequest) Reset()         { *m = ExecActionRequest{} }
+func (m *ExecActionRequest) String() string { return proto.CompactTextString(m) }
+func (*ExecActionRequest) ProtoMessage()    {}
+func (*ExecActionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{9}
+}
+
+func (m *ExecActionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecActionRequest.Unmarshal(m, b)
+}
+func (m *ExecActionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecActionRequest.Marshal(b, m, deterministic)
+}
+func (m *ExecActionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecActionRequest.Merge(m, src)
+}
+func (m *ExecActionRequest) XXX_Size() int {
+	return xxx_messageInfo_ExecActionRequest.Size(m)
+}
+func (m *ExecActionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecActionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecActionRequest proto.InternalMessageInfo
+
+func (m *ExecActionRequest) GetConfig() []byte {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+func (m *ExecActionRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *ExecActionRequest) GetDeploymentId() string {
+	if m != nil {
+		return m.DeploymentId
+	}
+	return ""
+}
+
+func (m *ExecActionRequest) GetAction() []byte {
+	if m != nil {
+		return m.Action
+	}
+	return nil
+}
+
+func (m *ExecActionRequest) GetLogOptionalFields() map[string]string {
+	if m != nil {
+		return m.LogOptionalFields
+	}
+	return nil
+}
+
+// ExecActionResponse is the response of an action execution.
+type ExecActionResponse struct {
+	Deregister           bool     `protobuf:"varint,1,opt,name=deregister,proto3" json:"deregister,omitempty"`
+	Error                *Error   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExecActionResponse) Reset()         { *m = ExecActionResponse{} }
+func (m *ExecActionResponse) String() string { return proto.CompactTextString(m) }
+func (*ExecActionResponse) ProtoMessage()    {}
+func (*ExecActionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{10}
+}
+
+func (m *ExecActionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecActionResponse.Unmarshal(m, b)
+}
+func (m *ExecActionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecActionResponse.Marshal(b, m, deterministic)
+}
+func (m *ExecActionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecActionResponse.Merge(m, src)
+}
+func (m *ExecActionResponse) XXX_Size() int {
+	return xxx_messageInfo_ExecActionResponse.Size(m)
+}
+func (m *ExecActionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecActionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecActionResponse proto.InternalMessageInfo
+
+func (m *ExecActionResponse) GetDeregister() bool {
+	if m != nil {
+		return m.Deregister
+	}
+	return false
+}
+
+func (m *ExecActionResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+// GetUsageInfoRequest is the request to collect infrastructure usage.
+type GetUsageInfoRequest struct {
+	Config               []byte            `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	TaskId               string            `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	InfraName            string            `protobuf:"bytes,3,opt,name=infra_name,json=infraName,proto3" json:"infra_name,omitempty"`
+	LocationName         string            `protobuf:"bytes,4,opt,name=location_name,json=locationName,proto3" json:"location_name,omitempty"`
+	Params               map[string]string `protobuf:"bytes,5,rep,name=params,proto3" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	LogOptionalFields    map[string]string `protobuf:"bytes,6,rep,name=log_optional_fields,json=logOptionalFields,proto3" json:"log_optional_fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *GetUsageInfoRequest) Reset()         { *m = GetUsageInfoRequest{} }
+func (m *GetUsageInfoRequest) String() string { return proto.CompactTextString(m) }
+func (*GetUsageInfoRequest) ProtoMessage()    {}
+func (*GetUsageInfoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{11}
+}
+
+func (m *GetUsageInfoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetUsageInfoRequest.Unmarshal(m, b)
+}
+func (m *GetUsageInfoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetUsageInfoRequest.Marshal(b, m, deterministic)
+}
+func (m *GetUsageInfoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetUsageInfoRequest.Merge(m, src)
+}
+func (m *GetUsageInfoRequest) XXX_Size() int {
+	return xxx_messageInfo_GetUsageInfoRequest.Size(m)
+}
+func (m *GetUsageInfoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetUsageInfoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetUsageInfoRequest proto.InternalMessageInfo
+
+func (m *GetUsageInfoRequest) GetConfig() []byte {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+func (m *GetUsageInfoRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *GetUsageInfoRequest) GetInfraName() string {
+	if m != nil {
+		return m.InfraName
+	}
+	return ""
+}
+
+func (m *GetUsageInfoRequest) GetLocationName() string {
+	if m != nil {
+		return m.LocationName
+	}
+	return ""
+}
+
+func (m *GetUsageInfoRequest) GetParams() map[string]string {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
+func (m *GetUsageInfoRequest) GetLogOptionalFields() map[string]string {
+	if m != nil {
+		return m.LogOptionalFields
+	}
+	return nil
+}
+
+// GetUsageInfoResponse contains collected infrastructure usage.
+type GetUsageInfoResponse struct {
+	// JSON encoded usage information
+	UsageInfo            []byte   `protobuf:"bytes,1,opt,name=usage_info,json=usageInfo,proto3" json:"usage_info,omitempty"`
+	Error                *Error   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetUsageInfoResponse) Reset()         { *m = GetUsageInfoResponse{} }
+func (m *GetUsageInfoResponse) String() string { return proto.CompactTextString(m) }
+func (*GetUsageInfoResponse) ProtoMessage()    {}
+func (*GetUsageInfoResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{12}
+}
+
+func (m *GetUsageInfoResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetUsageInfoResponse.Unmarshal(m, b)
+}
+func (m *GetUsageInfoResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetUsageInfoResponse.Marshal(b, m, deterministic)
+}
+func (m *GetUsageInfoResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetUsageInfoResponse.Merge(m, src)
+}
+func (m *GetUsageInfoResponse) XXX_Size() int {
+	return xxx_messageInfo_GetUsageInfoResponse.Size(m)
+}
+func (m *GetUsageInfoResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetUsageInfoResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetUsageInfoResponse proto.InternalMessageInfo
+
+func (m *GetUsageInfoResponse) GetUsageInfo() []byte {
+	if m != nil {
+		return m.UsageInfo
+	}
+	return nil
+}
+
+func (m *GetUsageInfoResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+// ResolveValueRequest asks the Yorc server to resolve configuration templates.
+type ResolveValueRequest struct {
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// JSON encoded value
+	Value                []byte   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResolveValueRequest) Reset()         { *m = ResolveValueRequest{} }
+func (m *ResolveValueRequest) String() string { return proto.CompactTextString(m) }
+func (*ResolveValueRequest) ProtoMessage()    {}
+func (*ResolveValueRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{13}
+}
+
+func (m *ResolveValueRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResolveValueRequest.Unmarshal(m, b)
+}
+func (m *ResolveValueRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResolveValueRequest.Marshal(b, m, deterministic)
+}
+func (m *ResolveValueRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResolveValueRequest.Merge(m, src)
+}
+func (m *ResolveValueRequest) XXX_Size() int {
+	return xxx_messageInfo_ResolveValueRequest.Size(m)
+}
+func (m *ResolveValueRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResolveValueRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResolveValueRequest proto.InternalMessageInfo
+
+func (m *ResolveValueRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *ResolveValueRequest) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+// ResolveValueResponse contains a resolved value.
+type ResolveValueResponse struct {
+	// JSON encoded value
+	Value                []byte   `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResolveValueResponse) Reset()         { *m = ResolveValueResponse{} }
+func (m *ResolveValueResponse) String() string { return proto.CompactTextString(m) }
+func (*ResolveValueResponse) ProtoMessage()    {}
+func (*ResolveValueResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{14}
+}
+
+func (m *ResolveValueResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResolveValueResponse.Unmarshal(m, b)
+}
+func (m *ResolveValueResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResolveValueResponse.Marshal(b, m, deterministic)
+}
+func (m *ResolveValueResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResolveValueResponse.Merge(m, src)
+}
+func (m *ResolveValueResponse) XXX_Size() int {
+	return xxx_messageInfo_ResolveValueResponse.Size(m)
+}
+func (m *ResolveValueResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResolveValueResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResolveValueResponse proto.InternalMessageInfo
+
+func (m *ResolveValueResponse) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+// LogEntry is a deployment log entry emitted by a plugin.
+type LogEntry struct {
+	// Storage key of the log entry
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// JSON encoded log entry
+	Value                []byte   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogEntry) Reset()         { *m = LogEntry{} }
+func (m *LogEntry) String() string { return proto.CompactTextString(m) }
+func (*LogEntry) ProtoMessage()    {}
+func (*LogEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{15}
+}
+
+func (m *LogEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogEntry.Unmarshal(m, b)
+}
+func (m *LogEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogEntry.Marshal(b, m, deterministic)
+}
+func (m *LogEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogEntry.Merge(m, src)
+}
+func (m *LogEntry) XXX_Size() int {
+	return xxx_messageInfo_LogEntry.Size(m)
+}
+func (m *LogEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogEntry proto.InternalMessageInfo
+
+func (m *LogEntry) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *LogEntry) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+// NewStoreRequest is the request to create a store.
+type NewStoreRequest struct {
+	Config []byte `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	// JSON encoded store configuration
+	StoreConfig          []byte   `protobuf:"bytes,2,opt,name=store_config,json=storeConfig,proto3" json:"store_config,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NewStoreRequest) Reset()         { *m = NewStoreRequest{} }
+func (m *NewStoreRequest) String() string { return proto.CompactTextString(m) }
+func (*NewStoreRequest) ProtoMessage()    {}
+func (*NewStoreRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{16}
+}
+
+func (m *NewStoreRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NewStoreRequest.Unmarshal(m, b)
+}
+func (m *NewStoreRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NewStoreRequest.Marshal(b, m, deterministic)
+}
+func (m *NewStoreRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NewStoreRequest.Merge(m, src)
+}
+func (m *NewStoreRequest) XXX_Size() int {
+	return xxx_messageInfo_NewStoreRequest.Size(m)
+}
+func (m *NewStoreRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NewStoreRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NewStoreRequest proto.InternalMessageInfo
+
+func (m *NewStoreRequest) GetConfig() []byte {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+func (m *NewStoreRequest) GetStoreConfig() []byte {
+	if m != nil {
+		return m.StoreConfig
+	}
+	return nil
+}
+
+// KeyValue is a store key and its JSON encoded value.
+type KeyValue struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value                []byte   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KeyValue) Reset()         { *m = KeyValue{} }
+func (m *KeyValue) String() string { return proto.CompactTextString(m) }
+func (*KeyValue) ProtoMessage()    {}
+func (*KeyValue) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{17}
+}
+
+func (m *KeyValue) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeyValue.Unmarshal(m, b)
+}
+func (m *KeyValue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeyValue.Marshal(b, m, deterministic)
+}
+func (m *KeyValue) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeyValue.Merge(m, src)
+}
+func (m *KeyValue) XXX_Size() int {
+	return xxx_messageInfo_KeyValue.Size(m)
+}
+func (m *KeyValue) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeyValue.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeyValue proto.InternalMessageInfo
+
+func (m *KeyValue) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *KeyValue) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+// StoreSetRequest is the request to store values.
+type StoreSetRequest struct {
+	// Store name
+	Store                string      `protobuf:"bytes,1,opt,name=store,proto3" json:"store,omitempty"`
+	KeyValues            []*KeyValue `protobuf:"bytes,2,rep,name=key_values,json=keyValues,proto3" json:"key_values,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *StoreSetRequest) Reset()         { *m = StoreSetRequest{} }
+func (m *StoreSetRequest) String() string { return proto.CompactTextString(m) }
+func (*StoreSetRequest) ProtoMessage()    {}
+func (*StoreSetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{18}
+}
+
+func (m *StoreSetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StoreSetRequest.Unmarshal(m, b)
+}
+func (m *StoreSetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StoreSetRequest.Marshal(b, m, deterministic)
+}
+func (m *StoreSetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoreSetRequest.Merge(m, src)
+}
+func (m *StoreSetRequest) XXX_Size() int {
+	return xxx_messageInfo_StoreSetRequest.Size(m)
+}
+func (m *StoreSetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoreSetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoreSetRequest proto.InternalMessageInfo
+
+func (m *StoreSetRequest) GetStore() string {
+	if m != nil {
+		return m.Store
+	}
+	return ""
+}
+
+func (m *StoreSetRequest) GetKeyValues() []*KeyValue {
+	if m != nil {
+		return m.KeyValues
+	}
+	return nil
+}
+
+// StoreKeyRequest is a request on a store key.
+type StoreKeyRequest struct {
+	Store                string   `protobuf:"bytes,1,opt,name=store,proto3" json:"store,omitempty"`
+	Key                  string   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StoreKeyRequest) Reset()         { *m = StoreKeyRequest{} }
+func (m *StoreKeyRequest) String() string { return proto.CompactTextString(m) }
+func (*StoreKeyRequest) ProtoMessage()    {}
+func (*StoreKeyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{19}
+}
+
+func (m *StoreKeyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StoreKeyRequest.Unmarshal(m, b)
+}
+func (m *StoreKeyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StoreKeyRequest.Marshal(b, m, deterministic)
+}
+func (m *StoreKeyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoreKeyRequest.Merge(m, src)
+}
+func (m *StoreKeyRequest) XXX_Size() int {
+	return xxx_messageInfo_StoreKeyRequest.Size(m)
+}
+func (m *StoreKeyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoreKeyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoreKeyRequest proto.InternalMessageInfo
+
+func (m *StoreKeyRequest) GetStore() string {
+	if m != nil {
+		return m.Store
+	}
+	return ""
+}
+
+func (m *StoreKeyRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+// StoreDeleteRequest is the request to delete a store key.
+type StoreDeleteRequest struct {
+	Store                string   `protobuf:"bytes,1,opt,name=store,proto3" json:"store,omitempty"`
+	Key                  string   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Recursive            bool     `protobuf:"varint,3,opt,name=recursive,proto3" json:"recursive,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StoreDeleteRequest) Reset()         { *m = StoreDeleteRequest{} }
+func (m *StoreDeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*StoreDeleteRequest) ProtoMessage()    {}
+func (*StoreDeleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{20}
+}
+
+func (m *StoreDeleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StoreDeleteRequest.Unmarshal(m, b)
+}
+func (m *StoreDeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StoreDeleteRequest.Marshal(b, m, deterministic)
+}
+func (m *StoreDeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoreDeleteRequest.Merge(m, src)
+}
+func (m *StoreDeleteRequest) XXX_Size() int {
+	return xxx_messageInfo_StoreDeleteRequest.Size(m)
+}
+func (m *StoreDeleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoreDeleteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoreDeleteRequest proto.InternalMessageInfo
+
+func (m *StoreDeleteRequest) GetStore() string {
+	if m != nil {
+		return m.Store
+	}
+	return ""
+}
+
+func (m *StoreDeleteRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *StoreDeleteRequest) GetRecursive() bool {
+	if m != nil {
+		return m.Recursive
+	}
+	return false
+}
+
+// StoreGetResponse is the response of a store Get.
+type StoreGetResponse struct {
+	Found bool `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	// JSON encoded value
+	Value                []byte   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Error                *Error   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StoreGetResponse) Reset()         { *m = StoreGetResponse{} }
+func (m *StoreGetResponse) String() string { return proto.CompactTextString(m) }
+func (*StoreGetResponse) ProtoMessage()    {}
+func (*StoreGetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{21}
+}
+
+func (m *StoreGetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StoreGetResponse.Unmarshal(m, b)
+}
+func (m *StoreGetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StoreGetResponse.Marshal(b, m, deterministic)
+}
+func (m *StoreGetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoreGetResponse.Merge(m, src)
+}
+func (m *StoreGetResponse) XXX_Size() int {
+	return xxx_messageInfo_StoreGetResponse.Size(m)
+}
+func (m *StoreGetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoreGetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoreGetResponse proto.InternalMessageInfo
+
+func (m *StoreGetResponse) GetFound() bool {
+	if m != nil {
+		return m.Found
+	}
+	return false
+}
+
+func (m *StoreGetResponse) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *StoreGetResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+// StoreExistResponse is the response of a store Exist.
+type StoreExistResponse struct {
+	Exist                bool     `protobuf:"varint,1,opt,name=exist,proto3" json:"exist,omitempty"`
+	Error                *Error   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StoreExistResponse) Reset()         { *m = StoreExistResponse{} }
+func (m *StoreExistResponse) String() string { return proto.CompactTextString(m) }
+func (*StoreExistResponse) ProtoMessage()    {}
+func (*StoreExistResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{22}
+}
+
+func (m *StoreExistResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StoreExistResponse.Unmarshal(m, b)
+}
+func (m *StoreExistResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StoreExistResponse.Marshal(b, m, deterministic)
+}
+func (m *StoreExistResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoreExistResponse.Merge(m, src)
+}
+func (m *StoreExistResponse) XXX_Size() int {
+	return xxx_messageInfo_StoreExistResponse.Size(m)
+}
+func (m *StoreExistResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoreExistResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoreExistResponse proto.InternalMessageInfo
+
+func (m *StoreExistResponse) GetExist() bool {
+	if m != nil {
+		return m.Exist
+	}
+	return false
+}
+
+func (m *StoreExistResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+// StoreKeysResponse is the response of a store Keys.
+type StoreKeysResponse struct {
+	Keys                 []string `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	Error                *Error   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StoreKeysResponse) Reset()         { *m = StoreKeysResponse{} }
+func (m *StoreKeysResponse) String() string { return proto.CompactTextString(m) }
+func (*StoreKeysResponse) ProtoMessage()    {}
+func (*StoreKeysResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{23}
+}
+
+func (m *StoreKeysResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StoreKeysResponse.Unmarshal(m, b)
+}
+func (m *StoreKeysResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StoreKeysResponse.Marshal(b, m, deterministic)
+}
+func (m *StoreKeysResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoreKeysResponse.Merge(m, src)
+}
+func (m *StoreKeysResponse) XXX_Size() int {
+	return xxx_messageInfo_StoreKeysResponse.Size(m)
+}
+func (m *StoreKeysResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoreKeysResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoreKeysResponse proto.InternalMessageInfo
+
+func (m *StoreKeysResponse) GetKeys() []string {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+func (m *StoreKeysResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+// StoreIndexResponse is the response of a store GetLastModifyIndex.
+type StoreIndexResponse struct {
+	Index                uint64   `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Error                *Error   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StoreIndexResponse) Reset()         { *m = StoreIndexResponse{} }
+func (m *StoreIndexResponse) String() string { return proto.CompactTextString(m) }
+func (*StoreIndexResponse) ProtoMessage()    {}
+func (*StoreIndexResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{24}
+}
+
+func (m *StoreIndexResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StoreIndexResponse.Unmarshal(m, b)
+}
+func (m *StoreIndexResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StoreIndexResponse.Marshal(b, m, deterministic)
+}
+func (m *StoreIndexResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoreIndexResponse.Merge(m, src)
+}
+func (m *StoreIndexResponse) XXX_Size() int {
+	return xxx_messageInfo_StoreIndexResponse.Size(m)
+}
+func (m *StoreIndexResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoreIndexResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoreIndexResponse proto.InternalMessageInfo
+
+func (m *StoreIndexResponse) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *StoreIndexResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+// StoreListRequest is the request to list, and eventually wait for, store keys and values.
+type StoreListRequest struct {
+	Store     string `protobuf:"bytes,1,opt,name=store,proto3" json:"store,omitempty"`
+	Key       string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	WaitIndex uint64 `protobuf:"varint,3,opt,name=wait_index,json=waitIndex,proto3" json:"wait_index,omitempty"`
+	// Timeout in nanoseconds
+	Timeout              int64    `protobuf:"varint,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StoreListRequest) Reset()         { *m = StoreListRequest{} }
+func (m *StoreListRequest) String() string { return proto.CompactTextString(m) }
+func (*StoreListRequest) ProtoMessage()    {}
+func (*StoreListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{25}
+}
+
+func (m *StoreListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StoreListRequest.Unmarshal(m, b)
+}
+func (m *StoreListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StoreListRequest.Marshal(b, m, deterministic)
+}
+func (m *StoreListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoreListRequest.Merge(m, src)
+}
+func (m *StoreListRequest) XXX_Size() int {
+	return xxx_messageInfo_StoreListRequest.Size(m)
+}
+func (m *StoreListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoreListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoreListRequest proto.InternalMessageInfo
+
+func (m *StoreListRequest) GetStore() string {
+	if m != nil {
+		return m.Store
+	}
+	return ""
+}
+
+func (m *StoreListRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *StoreListRequest) GetWaitIndex() uint64 {
+	if m != nil {
+		return m.WaitIndex
+	}
+	return 0
+}
+
+func (m *StoreListRequest) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+// StoreListResponse is the response of a store List.
+type StoreListResponse struct {
+	// JSON encoded list of key values
+	KeyValues            []byte   `protobuf:"bytes,1,opt,name=key_values,json=keyValues,proto3" json:"key_values,omitempty"`
+	LastIndex            uint64   `protobuf:"varint,2,opt,name=last_index,json=lastIndex,proto3" json:"last_index,omitempty"`
+	Error                *Error   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StoreListResponse) Reset()         { *m = StoreListResponse{} }
+func (m *StoreListResponse) String() string { return proto.CompactTextString(m) }
+func (*StoreListResponse) ProtoMessage()    {}
+func (*StoreListResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{26}
+}
+
+func (m *StoreListResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StoreListResponse.Unmarshal(m, b)
+}
+func (m *StoreListResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StoreListResponse.Marshal(b, m, deterministic)
+}
+func (m *StoreListResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoreListResponse.Merge(m, src)
+}
+func (m *StoreListResponse) XXX_Size() int {
+	return xxx_messageInfo_StoreListResponse.Size(m)
+}
+func (m *StoreListResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoreListResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoreListResponse proto.InternalMessageInfo
+
+func (m *StoreListResponse) GetKeyValues() []byte {
+	if m != nil {
+		return m.KeyValues
+	}
+	return nil
+}
+
+func (m *StoreListResponse) GetLastIndex() uint64 {
+	if m != nil {
+		return m.LastIndex
+	}
+	return 0
+}
+
+func (m *StoreListResponse) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Empty)(nil), "yorc.plugin.Empty")
+	proto.RegisterType((*Error)(nil), "yorc.plugin.Error")
+	proto.RegisterType((*ErrorResponse)(nil), "yorc.plugin.ErrorResponse")
+	proto.RegisterType((*TypesResponse)(nil), "yorc.plugin.TypesResponse")
+	proto.RegisterType((*SetupConfigRequest)(nil), "yorc.plugin.SetupConfigRequest")
+	proto.RegisterType((*DefinitionsResponse)(nil), "yorc.plugin.DefinitionsResponse")
+	proto.RegisterMapType((map[string][]byte)(nil), "yorc.plugin.DefinitionsResponse.DefinitionsEntry")
+	proto.RegisterType((*ExecDelegateRequest)(nil), "yorc.plugin.ExecDelegateRequest")
+	proto.RegisterMapType((map[string]string)(nil), "yorc.plugin.ExecDelegateRequest.LogOptionalFieldsEntry")
+	proto.RegisterType((*ExecOperationRequest)(nil), "yorc.plugin.ExecOperationRequest")
+	proto.RegisterMapType((map[string]string)(nil), "yorc.plugin.ExecOperationRequest.LogOptionalFieldsEntry")
+	proto.RegisterType((*ExecAsyncOperationResponse)(nil), "yorc.plugin.ExecAsyncOperationResponse")
+	proto.RegisterType((*ExecActionRequest)(nil), "yorc.plugin.ExecActionRequest")
+	proto.RegisterMapType((map[string]string)(nil), "yorc.plugin.ExecActionRequest.LogOptionalFieldsEntry")
+	proto.RegisterType((*ExecActionResponse)(nil), "yorc.plugin.ExecActionResponse")
+	proto.RegisterType((*GetUsageInfoRequest)(nil), "yorc.plugin.GetUsageInfoRequest")
+	proto.RegisterMapType((map[string]string)(nil), "yorc.plugin.GetUsageInfoRequest.LogOptionalFieldsEntry")
+	proto.RegisterMapType((map[string]string)(nil), "yorc.plugin.GetUsageInfoRequest.ParamsEntry")
+	proto.RegisterType((*GetUsageInfoResponse)(nil), "yorc.plugin.GetUsageInfoResponse")
+	proto.RegisterType((*ResolveValueRequest)(nil), "yorc.plugin.ResolveValueRequest")
+	proto.RegisterType((*ResolveValueResponse)(nil), "yorc.plugin.ResolveValueResponse")
+	proto.RegisterType((*LogEntry)(nil), "yorc.plugin.LogEntry")
+	proto.RegisterType((*NewStoreRequest)(nil), "yorc.plugin.NewStoreRequest")
+	proto.RegisterType((*KeyValue)(nil), "yorc.plugin.KeyValue")
+	proto.RegisterType((*StoreSetRequest)(nil), "yorc.plugin.StoreSetRequest")
+	proto.RegisterType((*StoreKeyRequest)(nil), "yorc.plugin.StoreKeyRequest")
+	proto.RegisterType((*StoreDeleteRequest)(nil), "yorc.plugin.StoreDeleteRequest")
+	proto.RegisterType((*StoreGetResponse)(nil), "yorc.plugin.StoreGetResponse")
+	proto.RegisterType((*StoreExistResponse)(nil), "yorc.plugin.StoreExistResponse")
+	proto.RegisterType((*StoreKeysResponse)(nil), "yorc.plugin.StoreKeysResponse")
+	proto.RegisterType((*StoreIndexResponse)(nil), "yorc.plugin.StoreIndexResponse")
+	proto.RegisterType((*StoreListRequest)(nil), "yorc.plugin.StoreListRequest")
+	proto.RegisterType((*StoreListResponse)(nil), "yorc.plugin.StoreListResponse")
+}
+
+func init() { proto.RegisterFile("plugin.proto", fileDescriptor_22a625af4bc1cc87) }
+
+var fileDescriptor_22a625af4bc1cc87 = []byte{
+	// 1470 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x58, 0xdd, 0x6e, 0x1b, 0x45,
+	0x14, 0x96, 0xed, 0xd8, 0x89, 0x8f, 0xed, 0x36, 0x1e, 0x87, 0xd4, 0x35, 0x4d, 0x9a, 0xb8, 0x42,
+	0xed, 0x45, 0x71, 0x84, 0x29, 0xea, 0x8f, 0xc4, 0x4f, 0x5b, 0x3b, 0x91, 0x5b, 0xb7, 0x85, 0x75,
+	0x0b, 0x52, 0x11, 0xb8, 0x5b, 0xef, 0xf1, 0x66, 0x95, 0xf5, 0xce, 0x32, 0x3b, 0x4e, 0x6b, 0x78,
+	0x07, 0x90, 0x78, 0x03, 0x2e, 0xb8, 0x45, 0x5c, 0xf3, 0x1c, 0x3c, 0x03, 0x4f, 0xc0, 0x03, 0xa0,
+	0x99, 0xd9, 0xcd, 0xee, 0xda, 0x5b, 0x3b, 0x8e, 0x80, 0x5e, 0xc5, 0x73, 0xe6, 0x9c, 0x33, 0xdf,
+	0xf9, 0x99, 0x39, 0xdf, 0x06, 0x8a, 0xae, 0x3d, 0x36, 0x2d, 0xa7, 0xe1, 0x32, 0xca, 0x29, 0x29,
+	0x4c, 0x28, 0x1b, 0x34, 0x94, 0xa8, 0xbe, 0x0a, 0xd9, 0xf6, 0xc8, 0xe5, 0x93, 0xfa, 0x4d, 0xc8,
+	0xb6, 0x19, 0xa3, 0x8c, 0x54, 0x61, 0x75, 0x84, 0x9e, 0xa7, 0x9b, 0x58, 0x4d, 0xed, 0xa4, 0xae,
+	0xe5, 0xb5, 0x60, 0x49, 0x36, 0x20, 0xeb, 0x71, 0x7d, 0x70, 0x54, 0x4d, 0x4b, 0xb9, 0x5a, 0xd4,
+	0x6f, 0x43, 0x49, 0x1a, 0x6a, 0xe8, 0xb9, 0xd4, 0xf1, 0x90, 0x5c, 0x83, 0x2c, 0x0a, 0x81, 0x34,
+	0x2f, 0x34, 0x49, 0x23, 0x72, 0x5e, 0x43, 0xa9, 0x2a, 0x85, 0xfa, 0x13, 0x28, 0x3d, 0x9d, 0xb8,
+	0xe8, 0x9d, 0x98, 0x6e, 0x40, 0x96, 0x0b, 0x41, 0x35, 0xb5, 0x93, 0x11, 0x27, 0xc8, 0x45, 0xe8,
+	0x30, 0xbd, 0xc8, 0x61, 0x07, 0x48, 0x0f, 0xf9, 0xd8, 0xbd, 0x4f, 0x9d, 0xa1, 0x65, 0x6a, 0xf8,
+	0xdd, 0x18, 0x3d, 0x4e, 0x36, 0x21, 0x37, 0x90, 0x02, 0x89, 0xa8, 0xa8, 0xf9, 0x2b, 0xf2, 0x2e,
+	0xe4, 0x3d, 0x64, 0xc7, 0xc8, 0xfa, 0x96, 0x21, 0x7d, 0x97, 0xb4, 0x35, 0x25, 0xe8, 0x18, 0xf5,
+	0x3f, 0x53, 0x50, 0x69, 0xe1, 0xd0, 0x72, 0x2c, 0x6e, 0x51, 0x27, 0x84, 0xd8, 0x83, 0x82, 0x11,
+	0x8a, 0x25, 0xd0, 0x42, 0xf3, 0x83, 0x18, 0xa4, 0x04, 0xb3, 0xa8, 0xac, 0xed, 0x70, 0x36, 0xd1,
+	0xa2, 0x5e, 0x4e, 0x1f, 0x61, 0xed, 0x13, 0x58, 0x9f, 0x76, 0x45, 0xd6, 0x21, 0x73, 0x84, 0x13,
+	0xbf, 0x5a, 0xe2, 0xa7, 0xc8, 0xe3, 0xb1, 0x6e, 0x8f, 0x51, 0xfa, 0x2b, 0x6a, 0x6a, 0x71, 0x27,
+	0x7d, 0x2b, 0x55, 0xff, 0x2b, 0x0d, 0x95, 0xf6, 0x6b, 0x1c, 0xb4, 0xd0, 0x46, 0x53, 0xe7, 0xb8,
+	0x28, 0x47, 0x17, 0x60, 0x95, 0xeb, 0xde, 0x51, 0x90, 0xa1, 0xbc, 0x96, 0x13, 0xcb, 0x8e, 0x41,
+	0xae, 0x40, 0xc9, 0x40, 0xd7, 0xa6, 0x93, 0x11, 0x3a, 0x5c, 0x6c, 0x67, 0xe4, 0x76, 0x31, 0x14,
+	0x76, 0x0c, 0x91, 0x61, 0x87, 0x1a, 0xd8, 0x77, 0xf4, 0x11, 0x56, 0x57, 0xa4, 0xc2, 0x9a, 0x10,
+	0x3c, 0xd6, 0x47, 0x48, 0xde, 0x07, 0x62, 0xf8, 0x28, 0xfa, 0xd4, 0x45, 0xa6, 0x8b, 0x90, 0xaa,
+	0x59, 0xa9, 0x55, 0x0e, 0x76, 0x9e, 0x04, 0x1b, 0xc4, 0x84, 0x8a, 0x4d, 0xcd, 0x3e, 0x75, 0xc5,
+	0x4a, 0xb7, 0xfb, 0x43, 0x0b, 0x6d, 0xc3, 0xab, 0xe6, 0x64, 0x01, 0x6e, 0xc6, 0x33, 0x36, 0x1b,
+	0x60, 0xa3, 0x4b, 0xcd, 0x27, 0xbe, 0xe9, 0xbe, 0xb4, 0x54, 0x65, 0x28, 0xdb, 0xd3, 0xf2, 0x5a,
+	0x0b, 0x36, 0x93, 0x95, 0x17, 0x25, 0x3a, 0x1f, 0x4d, 0xf4, 0xdf, 0x69, 0xd8, 0x10, 0x38, 0x4e,
+	0x02, 0x78, 0x8b, 0x99, 0xbe, 0x04, 0xf9, 0x78, 0x82, 0x8b, 0x5a, 0x28, 0x90, 0xd7, 0x80, 0xa3,
+	0xab, 0x4c, 0x73, 0xca, 0x54, 0x08, 0xa4, 0xe9, 0x61, 0x72, 0xd6, 0x57, 0x65, 0xd6, 0x6f, 0xcd,
+	0x64, 0x7d, 0x3a, 0xda, 0xff, 0x3d, 0xed, 0x3f, 0xa5, 0xa0, 0x26, 0x80, 0xdc, 0xf5, 0x26, 0x4e,
+	0x14, 0x8d, 0x7f, 0x7b, 0x37, 0x21, 0xa7, 0x0f, 0x64, 0x1a, 0xfc, 0xe4, 0xab, 0x15, 0xd9, 0x83,
+	0xca, 0x88, 0x3a, 0x16, 0xa7, 0xcc, 0x72, 0xcc, 0xbe, 0xe5, 0x70, 0x64, 0xc7, 0xba, 0x2d, 0xdd,
+	0x67, 0x34, 0x12, 0x6e, 0x75, 0xfc, 0x9d, 0xf0, 0xc6, 0x66, 0x16, 0xbd, 0x49, 0xbf, 0xa5, 0xa1,
+	0x2c, 0x11, 0x0d, 0xfe, 0xfb, 0x2e, 0x08, 0xc3, 0x5b, 0x89, 0x85, 0x87, 0xc9, 0x55, 0xcc, 0xca,
+	0x2a, 0x7e, 0x34, 0x53, 0xc5, 0xbb, 0x83, 0xb7, 0x59, 0xc2, 0x6f, 0x81, 0x44, 0x41, 0xf8, 0x95,
+	0xdb, 0x06, 0x30, 0x90, 0xa1, 0x69, 0x79, 0x1c, 0xd5, 0x68, 0x59, 0xd3, 0x22, 0x92, 0x25, 0x86,
+	0xc4, 0x1f, 0x19, 0xa8, 0x1c, 0x20, 0x7f, 0x26, 0x66, 0x5a, 0xc7, 0x19, 0xd2, 0x33, 0x97, 0x64,
+	0x0b, 0xc0, 0x72, 0x86, 0x4c, 0x57, 0x37, 0x47, 0xd5, 0x23, 0x2f, 0x25, 0xf2, 0xea, 0x5c, 0x81,
+	0x92, 0x4d, 0x07, 0xb2, 0xff, 0xa2, 0xd7, 0xb2, 0x18, 0x08, 0xa5, 0x52, 0x0b, 0x72, 0xae, 0xce,
+	0xf4, 0x51, 0x50, 0x8c, 0xeb, 0x31, 0xdc, 0x09, 0x30, 0x1b, 0x9f, 0x4b, 0x75, 0x55, 0x03, 0xdf,
+	0x76, 0x99, 0xb7, 0x31, 0xc9, 0xe5, 0xe9, 0x2b, 0x7c, 0x1b, 0x0a, 0x91, 0xf3, 0x97, 0x29, 0xeb,
+	0xbf, 0xd4, 0x1c, 0x7d, 0xd8, 0x88, 0x47, 0xe0, 0xb7, 0xc7, 0x16, 0xc0, 0x58, 0x08, 0xfb, 0x96,
+	0x33, 0xa4, 0x7e, 0x01, 0xf3, 0xe3, 0x40, 0x6d, 0x89, 0xee, 0xf8, 0x18, 0x2a, 0x1a, 0x7a, 0xd4,
+	0x3e, 0xc6, 0x2f, 0xc5, 0xa1, 0x41, 0x73, 0x9c, 0x72, 0xc6, 0xd6, 0xaf, 0xc3, 0x46, 0xdc, 0x3c,
+	0x64, 0x36, 0x4a, 0x3b, 0x15, 0xd5, 0x6e, 0xc2, 0x5a, 0x97, 0x9a, 0x4b, 0x4d, 0xf1, 0x7a, 0x17,
+	0xce, 0x3f, 0xc6, 0x57, 0x3d, 0x4e, 0xd9, 0xc2, 0xe1, 0xbd, 0x0b, 0x45, 0x4f, 0xe8, 0xf5, 0xfd,
+	0x5d, 0xe5, 0xa7, 0x20, 0x65, 0x8a, 0x22, 0x09, 0x04, 0x0f, 0x71, 0x22, 0xb1, 0x9e, 0x1a, 0xc1,
+	0x37, 0x70, 0x5e, 0x1e, 0xdf, 0x43, 0x1e, 0x20, 0x90, 0xd4, 0x90, 0xb2, 0x80, 0x32, 0xaa, 0x05,
+	0xb9, 0x01, 0x70, 0x84, 0x93, 0xbe, 0xb4, 0xf2, 0xaa, 0x69, 0xd9, 0x8d, 0xef, 0xc4, 0x52, 0x1f,
+	0x9c, 0xad, 0xe5, 0x8f, 0xfc, 0x5f, 0x5e, 0xfd, 0xb6, 0xef, 0xfe, 0x21, 0x4e, 0xe6, 0xbb, 0xf7,
+	0xf1, 0xa6, 0x4f, 0xf0, 0xd6, 0x9f, 0x03, 0x91, 0xa6, 0x62, 0xf8, 0x73, 0x5c, 0xd2, 0x5a, 0x8c,
+	0x49, 0x86, 0x83, 0x31, 0xf3, 0xac, 0x63, 0x75, 0x9d, 0xd7, 0xb4, 0x50, 0x50, 0x3f, 0x84, 0x75,
+	0xe9, 0xfb, 0x00, 0x79, 0xb4, 0xaa, 0x43, 0x3a, 0x76, 0x0c, 0xff, 0x3d, 0x52, 0x8b, 0xe4, 0xac,
+	0x2d, 0x31, 0x31, 0x9e, 0xfa, 0x51, 0xb4, 0x5f, 0x5b, 0x5e, 0xec, 0x2c, 0x14, 0x82, 0xe0, 0x2c,
+	0xb9, 0x58, 0xa2, 0xb1, 0xbf, 0x80, 0x72, 0x90, 0xd6, 0x90, 0xcd, 0x12, 0x58, 0x39, 0xc2, 0x49,
+	0xc0, 0xb7, 0xe5, 0xef, 0x25, 0x5c, 0x06, 0x40, 0x3b, 0x8e, 0x81, 0xaf, 0xa3, 0x40, 0x2d, 0x21,
+	0x90, 0x40, 0x57, 0x34, 0xb5, 0x58, 0xc2, 0xab, 0xe7, 0x27, 0xba, 0x2b, 0xa3, 0x5f, 0xae, 0x84,
+	0x5b, 0x00, 0xaf, 0x74, 0x8b, 0xf7, 0x15, 0x80, 0x8c, 0x04, 0x90, 0x17, 0x12, 0x09, 0x51, 0x7c,
+	0xdb, 0x70, 0x6b, 0x84, 0x74, 0xcc, 0xe5, 0x63, 0x9c, 0xd1, 0x82, 0x65, 0xfd, 0x07, 0x28, 0x47,
+	0x0e, 0x0d, 0x1f, 0x95, 0x48, 0xff, 0xfa, 0x8f, 0xca, 0x49, 0xa3, 0x8a, 0x6d, 0x5b, 0xf7, 0x82,
+	0xc3, 0xd2, 0xea, 0x30, 0x21, 0xe9, 0xc4, 0x23, 0x5e, 0x54, 0xf0, 0xe6, 0xd7, 0x50, 0x52, 0xd7,
+	0xf1, 0x91, 0xee, 0xe8, 0x26, 0x32, 0xf2, 0x00, 0x0a, 0x91, 0xef, 0x18, 0x72, 0x39, 0x66, 0x3a,
+	0xfb, 0x85, 0x53, 0xab, 0x25, 0xf8, 0xf6, 0x83, 0x68, 0x3e, 0x83, 0x42, 0xe4, 0x8b, 0x81, 0xec,
+	0xc3, 0xb9, 0x03, 0xe4, 0x51, 0xc9, 0x14, 0x30, 0xf1, 0x35, 0x58, 0xdb, 0x59, 0xf4, 0x41, 0xd3,
+	0xfc, 0x35, 0x05, 0xeb, 0x01, 0xc7, 0x16, 0xe3, 0x7a, 0xcc, 0x29, 0x23, 0xf7, 0xa1, 0x7c, 0x80,
+	0xbc, 0x37, 0x76, 0x5d, 0xca, 0x38, 0x1a, 0xf2, 0xe3, 0x2e, 0xd1, 0x7f, 0x1c, 0x70, 0xfc, 0x23,
+	0xb0, 0x0b, 0xc5, 0x28, 0x81, 0x27, 0x3b, 0x8b, 0xb8, 0xfd, 0xdc, 0xf0, 0x7f, 0x4e, 0x43, 0xf9,
+	0x84, 0x07, 0x9e, 0x00, 0x7d, 0x08, 0x17, 0xa3, 0x40, 0xef, 0x32, 0x6e, 0x0d, 0xf5, 0x01, 0x3f,
+	0x1b, 0xe0, 0xc7, 0x50, 0x8a, 0x71, 0x5f, 0xb2, 0xbb, 0x90, 0x17, 0xcf, 0x83, 0x4c, 0x5e, 0x00,
+	0x99, 0xa5, 0xb0, 0xa7, 0x71, 0x7a, 0x75, 0x96, 0xc9, 0x25, 0xd2, 0xe0, 0xe6, 0x2f, 0x29, 0x38,
+	0xa7, 0xf8, 0x95, 0xda, 0xa3, 0x8c, 0x7c, 0x26, 0xfb, 0x42, 0x09, 0xcf, 0x96, 0x86, 0x47, 0x00,
+	0x21, 0x6f, 0x23, 0xdb, 0xf3, 0x59, 0x65, 0xed, 0xf2, 0x1b, 0xf7, 0x7d, 0x8c, 0xbf, 0xa7, 0xa0,
+	0xd2, 0x11, 0x64, 0x4a, 0x0e, 0xfb, 0xfb, 0xd4, 0xb6, 0x71, 0x20, 0x80, 0xb6, 0x80, 0x44, 0x4b,
+	0x27, 0x55, 0x96, 0x07, 0xdb, 0x83, 0x62, 0x94, 0x47, 0x4c, 0x35, 0x59, 0x02, 0x49, 0xaa, 0xed,
+	0xce, 0xd1, 0xf0, 0x21, 0xff, 0x98, 0x85, 0xac, 0x7c, 0x45, 0x48, 0x07, 0x2e, 0x08, 0x90, 0xf2,
+	0x71, 0x1c, 0xb9, 0x36, 0x0a, 0x7e, 0xae, 0xbf, 0xf9, 0xba, 0xcd, 0x43, 0xda, 0x82, 0xb5, 0x60,
+	0xde, 0x93, 0x4b, 0x31, 0xbd, 0x29, 0x1a, 0x30, 0xb7, 0xa7, 0x3e, 0x85, 0x4c, 0x0f, 0xf9, 0x94,
+	0x83, 0xa9, 0x29, 0x3e, 0xd7, 0xc1, 0x3d, 0xc8, 0x1c, 0x24, 0x3b, 0x08, 0xe7, 0x74, 0x6d, 0x6b,
+	0x76, 0x37, 0x3a, 0x2e, 0xf7, 0x21, 0x2b, 0x67, 0xda, 0x02, 0x2f, 0x97, 0x67, 0x77, 0xe3, 0xa3,
+	0xb0, 0x05, 0x2b, 0x62, 0x8a, 0x2d, 0x70, 0xb3, 0x9d, 0xb8, 0x1b, 0x26, 0xb6, 0x0d, 0x39, 0xc5,
+	0x13, 0x48, 0xc2, 0x81, 0x31, 0x06, 0x31, 0x37, 0x31, 0x3d, 0xd9, 0x8f, 0x5d, 0xdd, 0xe3, 0x8f,
+	0xa8, 0x61, 0x0d, 0x27, 0xea, 0xf1, 0x5f, 0x3a, 0xc2, 0xf8, 0x0c, 0x6d, 0xc3, 0x8a, 0x98, 0x44,
+	0x24, 0x21, 0xa1, 0x91, 0xb1, 0x58, 0xdb, 0x7e, 0xd3, 0xb6, 0xdf, 0x90, 0xdf, 0xc3, 0xa6, 0x1a,
+	0x14, 0x4f, 0x71, 0xe4, 0xda, 0xf2, 0xc5, 0x94, 0xdc, 0x94, 0x91, 0x17, 0x70, 0x31, 0xca, 0x53,
+	0xbf, 0xb2, 0xf8, 0x61, 0xa0, 0xe3, 0x4d, 0x5d, 0x86, 0x04, 0x3a, 0x5c, 0xdb, 0x9d, 0xa3, 0xe1,
+	0x9f, 0xfd, 0x00, 0x8a, 0x5d, 0x6a, 0xee, 0x53, 0xf6, 0x4a, 0x67, 0x06, 0x32, 0x72, 0x07, 0x0a,
+	0xfe, 0xa2, 0x4b, 0x4d, 0x8f, 0xc4, 0x79, 0x60, 0xc0, 0x82, 0x6b, 0x09, 0xb7, 0xe3, 0x5a, 0xea,
+	0xde, 0xd5, 0xe7, 0xef, 0x99, 0x16, 0x3f, 0x1c, 0xbf, 0x6c, 0x0c, 0xe8, 0x68, 0x6f, 0xe2, 0x71,
+	0x4b, 0xdf, 0x13, 0x7a, 0x7b, 0xc7, 0x37, 0xf6, 0x94, 0xea, 0x9e, 0xfc, 0xdf, 0xe6, 0xcb, 0x9c,
+	0xfc, 0xf3, 0xe1, 0x3f, 0x03, 0x00, 0x77, 0x42, 0x62, 0x50, 0xf2, 0x14, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ConfigManagerClient is the client API for ConfigManager service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ConfigManagerClient interface {
+	SetupConfig(ctx context.Context, in *SetupConfigRequest, opts ...grpc.CallOption) (*ErrorResponse, error)
+}
+
+type configManagerClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewConfigManagerClient(cc *grpc.ClientConn) ConfigManagerClient {
+	return &configManagerClient{cc}
+}
+
+func (c *configManagerClient) SetupConfig(ctx context.Context, in *SetupConfigRequest, opts ...grpc.CallOption) (*ErrorResponse, error) {
+	out := new(ErrorResponse)
+	err := c.cc.Invoke(ctx, "/yorc.plugin.ConfigManager/SetupConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ConfigManagerServer is the server API for ConfigManager service.
+type ConfigManagerServer interface {
+	SetupConfig(context.Context, *SetupConfigRequest) (*ErrorResponse, error)
+}
+
+// UnimplementedConfigManagerServer can be embedded to have forward compatible implementations.
+type UnimplementedConfigManagerServer struct {
+}
+
+func (*UnimplementedConfigManagerServer) SetupConfig(ctx context.Context, req *SetupConfigRequest) (*ErrorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetupConfig not implemented")
+}
+
+func RegisterConfigManagerServer(s *grpc.Server, srv ConfigManagerServer) {
+	s.RegisterService(&_ConfigManager_serviceDesc, srv)
+}
+
+func _ConfigManager_SetupConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetupConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigManagerServer).SetupConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yorc.plugin.ConfigManager/SetupConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigManagerServer).SetupConfig(ctx, req.(*SetupConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ConfigManager_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "yorc.plugin.ConfigManager",
+	HandlerType: (*ConfigManagerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetupConfig",
+			Handler:    _ConfigManager_SetupConfig_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin.proto",
+}
+
+// DefinitionsClient is the client API for Definitions service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type DefinitionsClient interface {
+	GetDefinitions(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*DefinitionsResponse, error)
+}
+
+type definitionsClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDefinitionsClient(cc *grpc.ClientConn) DefinitionsClient {
+	return &definitionsClient{cc}
+}
+
+func (c *definitionsClient) GetDefinitions(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*DefinitionsResponse, error) {
+	out := new(DefinitionsResponse)
+	err := c.cc.Invoke(ctx, "/yorc.plugin.Definitions/GetDefinitions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DefinitionsServer is the server API for Definitions service.
+type DefinitionsServer interface {
+	GetDefinitions(context.Context, *Empty) (*DefinitionsResponse, error)
+}
+
+// UnimplementedDefinitionsServer can be embedded to have forward compatible implementations.
+type UnimplementedDefinitionsServer struct {
+}
+
+func (*UnimplementedDefinitionsServer) GetDefinitions(ctx context.Context, req *Empty) (*DefinitionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDefinitions not implemented")
+}
+
+func RegisterDefinitionsServer(s *grpc.Server, srv DefinitionsServer) {
+	s.RegisterService(&_Definitions_serviceDesc, srv)
+}
+
+func _Definitions_GetDefinitions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DefinitionsServer).GetDefinitions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yorc.plugin.Definitions/GetDefinitions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DefinitionsServer).GetDefinitions(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Definitions_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "yorc.plugin.Definitions",
+	HandlerType: (*DefinitionsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetDefinitions",
+			Handler:    _Definitions_GetDefinitions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin.proto",
+}
+
+// DelegateExecutorClient is the client API for DelegateExecutor service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type DelegateExecutorClient interface {
+	GetSupportedTypes(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TypesResponse, error)
+	ExecDelegate(ctx context.Context, in *ExecDelegateRequest, opts ...grpc.CallOption) (*ErrorResponse, error)
+}
+
+type delegateExecutorClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDelegateExecutorClient(cc *grpc.ClientConn) DelegateExecutorClient {
+	return &delegateExecutorClient{cc}
+}
+
+func (c *delegateExecutorClient) GetSupportedTypes(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TypesResponse, error) {
+	out := new(TypesResponse)
+	err := c.cc.Invoke(ctx, "/yorc.plugin.DelegateExecutor/GetSupportedTypes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *delegateExecutorClient) ExecDelegate(ctx context.Context, in *ExecDelegateRequest, opts ...grpc.CallOption) (*ErrorResponse, error) {
+	out := new(ErrorResponse)
+	err := c.cc.Invoke(ctx, "/yorc.plugin.DelegateExecutor/ExecDelegate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DelegateExecutorServer is the server API for DelegateExecutor service.
+type DelegateExecutorServer interface {
+	GetSupportedTypes(context.Context, *Empty) (*TypesResponse, error)
+	ExecDelegate(context.Context, *ExecDelegateRequest) (*ErrorResponse, error)
+}
+
+// UnimplementedDelegateExecutorServer can be embedded to have forward compatible implementations.
+type UnimplementedDelegateExecutorServer struct {
+}
+
+func (*UnimplementedDelegateExecutorServer) GetSupportedTypes(ctx context.Context, req *Empty) (*TypesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSupportedTypes not implemented")
+}
+func (*UnimplementedDelegateExecutorServer) ExecDelegate(ctx context.Context, req *ExecDelegateRequest) (*ErrorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecDelegate not implemented")
+}
+
+func RegisterDelegateExecutorServer(s *grpc.Server, srv DelegateExecutorServer) {
+	s.RegisterService(&_DelegateExecutor_serviceDesc, srv)
+}
+
+func _DelegateExecutor_GetSupportedTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DelegateExecutorServer).GetSupportedTypes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yorc.plugin.DelegateExecutor/GetSupportedTypes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DelegateExecutorServer).GetSupportedTypes(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DelegateExecutor_ExecDelegate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecDelegateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DelegateExecutorServer).ExecDelegate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yorc.plugin.DelegateExecutor/ExecDelegate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DelegateExecutorServer).ExecDelegate(ctx, req.(*ExecDelegateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _DelegateExecutor_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "yorc.plugin.DelegateExecutor",
+	HandlerType: (*DelegateExecutorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetSupportedTypes",
+			Handler:    _DelegateExecutor_GetSupportedTypes_Handler,
+		},
+		{
+			MethodName: "ExecDelegate",
+			Handler:    _DelegateExecutor_ExecDelegate_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin.proto",
+}
+
+// OperationExecutorClient is the client API for OperationExecutor service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type OperationExecutorClient interface {
+	GetSupportedArtifactTypes(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TypesResponse, error)
+	ExecOperation(ctx context.Context, in *ExecOperationRequest, opts ...grpc.CallOption) (*ErrorResponse, error)
+	ExecAsyncOperation(ctx context.Context, in *ExecOperationRequest, opts ...grpc.CallOption) (*ExecAsyncOperationResponse, error)
+}
+
+type operationExecutorClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewOperationExecutorClient(cc *grpc.ClientConn) OperationExecutorClient {
+	return &operationExecutorClient{cc}
+}
+
+func (c *operationExecutorClient) GetSupportedArtifactTypes(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TypesResponse, error) {
+	out := new(TypesResponse)
+	err := c.cc.Invoke(ctx, "/yorc.plugin.OperationExecutor/GetSupportedArtifactTypes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *operationExecutorClient) ExecOperation(ctx context.Context, in *ExecOperationRequest, opts ...grpc.CallOption) (*ErrorResponse, error) {
+	out := new(ErrorResponse)
+	err := c.cc.Invoke(ctx, "/yorc.plugin.OperationExecutor/ExecOperation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *operationExecutorClient) ExecAsyncOperation(ctx context.Context, in *ExecOperationRequest, opts ...grpc.CallOption) (*ExecAsyncOperationResponse, error) {
+	out := new(ExecAsyncOperationResponse)
+	err := c.cc.Invoke(ctx, "/yorc.plugin.OperationExecutor/ExecAsyncOperation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OperationExecutorServer is the server API for OperationExecutor service.
+type OperationExecutorServer interface {
+	GetSupportedArtifactTypes(context.Context, *Empty) (*TypesResponse, error)
+	ExecOperation(context.Context, *ExecOperationRequest) (*ErrorResponse, error)
+	ExecAsyncOperation(context.Context, *ExecOperationRequest) (*ExecAsyncOperationResponse, error)
+}
+
+// UnimplementedOperationExecutorServer can be embedded to have forward compatible implementations.
+type UnimplementedOperationExecutorServer struct {
+}
+
+func (*UnimplementedOperationExecutorServer) GetSupportedArtifactTypes(ctx context.Context, req *Empty) (*TypesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSupportedArtifactTypes not implemented")
+}
+func (*UnimplementedOperationExecutorServer) ExecOperation(ctx context.Context, req *ExecOperationRequest) (*ErrorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecOperation not implemented")
+}
+func (*UnimplementedOperationExecutorServer) ExecAsyncOperation(ctx context.Context, req *ExecOperationRequest) (*ExecAsyncOperationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecAsyncOperation not implemented")
+}
+
+func RegisterOperationExecutorServer(s *grpc.Server, srv OperationExecutorServer) {
+	s.RegisterService(&_OperationExecutor_serviceDesc, srv)
+}
+
+func _OperationExecutor_GetSupportedArtifactTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OperationExecutorServer).GetSupportedArtifactTypes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yorc.plugin.OperationExecutor/GetSupportedArtifactTypes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OperationExecutorServer).GetSupportedArtifactTypes(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OperationExecutor_ExecOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OperationExecutorServer).ExecOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yorc.plugin.OperationExecutor/ExecOperation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OperationExecutorServer).ExecOperation(ctx, req.(*ExecOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OperationExecutor_ExecAsyncOperation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecOperationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OperationExecutorServer).ExecAsyncOperation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yorc.plugin.OperationExecutor/ExecAsyncOperation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OperationExecutorServer).ExecAsyncOperation(ctx, req.(*ExecOperationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _OperationExecutor_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "yorc.plugin.OperationExecutor",
+	HandlerType: (*OperationExecutorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetSupportedArtifactTypes",
+			Handler:    _OperationExecutor_GetSupportedArtifactTypes_Handler,
+		},
+		{
+			MethodName: "ExecOperation",
+			Handler:    _OperationExecutor_ExecOperation_Handler,
+		},
+		{
+			MethodName: "ExecAsyncOperation",
+			Handler:    _OperationExecutor_ExecAsyncOperation_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin.proto",
+}
+
+// ActionOperatorClient is the client API for ActionOperator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ActionOperatorClient interface {
+	GetActionTypes(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TypesResponse, error)
+	ExecAction(ctx context.Context, in *ExecActionRequest, opts ...grpc.CallOption) (*ExecActionResponse, error)
+}
+
+type actionOperatorClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewActionOperatorClient(cc *grpc.ClientConn) ActionOperatorClient {
+	return &actionOperatorClient{cc}
+}
+
+func (c *actionOperatorClient) GetActionTypes(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TypesResponse, error) {
+	out := new(TypesResponse)
+	err := c.cc.Invoke(ctx, "/yorc.plugin.ActionOperator/GetActionTypes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *actionOperatorClient) ExecAction(ctx context.Context, in *ExecActionRequest, opts ...grpc.CallOption) (*ExecActionResponse, error) {
+	out := new(ExecActionResponse)
+	err := c.cc.Invoke(ctx, "/yorc.plugin.ActionOperator/ExecAction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ActionOperatorServer is the server API for ActionOperator service.
+type ActionOperatorServer interface {
+	GetActionTypes(context.Context, *Empty) (*TypesResponse, error)
+	ExecAction(context.Context, *ExecActionRequest) (*ExecActionResponse, error)
+}
+
+// UnimplementedActionOperatorServer can be embedded to have forward compatible implementations.
+type UnimplementedActionOperatorServer struct {
+}
+
+func (*UnimplementedActionOperatorServer) GetActionTypes(ctx context.Context, req *Empty) (*TypesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetActionTypes not implemented")
+}
+func (*UnimplementedActionOperatorServer) ExecAction(ctx context.Context, req *ExecActionRequest) (*ExecActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecAction not implemented")
+}
+
+func RegisterActionOperatorServer(s *grpc.Server, srv ActionOperatorServer) {
+	s.RegisterService(&_ActionOperator_serviceDesc, srv)
+}
+
+func _ActionOperator_GetActionTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActionOperatorServer).GetActionTypes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yorc.plugin.ActionOperator/GetActionTypes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActionOperatorServer).GetActionTypes(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActionOperator_ExecAction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActionOperatorServer).ExecAction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yorc.plugin.ActionOperator/ExecAction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActionOperatorServer).ExecAction(ctx, req.(*ExecActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ActionOperator_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "yorc.plugin.ActionOperator",
+	HandlerType: (*ActionOperatorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetActionTypes",
+			Handler:    _ActionOperator_GetActionTypes_Handler,
+		},
+		{
+			MethodName: "ExecAction",
+			Handler:    _ActionOperator_ExecAction_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin.proto",
+}
+
+// InfraUsageCollectorClient is the client API for InfraUsageCollector service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type InfraUsageCollectorClient interface {
+	GetSupportedInfras(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TypesResponse, error)
+	GetUsageInfo(ctx context.Context, in *GetUsageInfoRequest, opts ...grpc.CallOption) (*GetUsageInfoResponse, error)
+}
+
+type infraUsageCollectorClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewInfraUsageCollectorClient(cc *grpc.ClientConn) InfraUsageCollectorClient {
+	return &infraUsageCollectorClient{cc}
+}
+
+func (c *infraUsageCollectorClient) GetSupportedInfras(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TypesResponse, error) {
+	out := new(TypesResponse)
+	err := c.cc.Invoke(ctx, "/yorc.plugin.InfraUsageCollector/GetSupportedInfras", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infraUsageCollectorClient) GetUsageInfo(ctx context.Context, in *GetUsageInfoRequest, opts ...grpc.CallOption) (*GetUsageInfoResponse, error) {
+	out := new(GetUsageInfoResponse)
+	err := c.cc.Invoke(ctx, "/yorc.plugin.InfraUsageCollector/GetUsageInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InfraUsageCollectorServer is the server API for InfraUsageCollector service.
+type InfraUsageCollectorServer interface {
+	GetSupportedInfras(context.Context, *Empty) (*TypesResponse, error)
+	GetUsageInfo(context.Context, *GetUsageInfoRequest) (*GetUsageInfoResponse, error)
+}
+
+// UnimplementedInfraUsageCollectorServer can be embedded to have forward compatible implementations.
+type UnimplementedInfraUsageCollectorServer struct {
+}
+
+func (*UnimplementedInfraUsageCollectorServer) GetSupportedInfras(ctx context.Context, req *Empty) (*TypesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSupportedInfras not implemented")
+}
+func (*UnimplementedInfraUsageCollectorServer) GetUsageInfo(ctx context.Context, req *GetUsageInfoRequest) (*GetUsageInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsageInfo not implemented")
+}
+
+func RegisterInfraUsageCollectorServer(s *grpc.Server, srv InfraUsageCollectorServer) {
+	s.RegisterService(&_InfraUsageCollector_serviceDesc, srv)
+}
+
+func _InfraUsageCollector_GetSupportedInfras_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfraUsageCollectorServer).GetSupportedInfras(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yorc.plugin.InfraUsageCollector/GetSupportedInfras",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfraUsageCollectorServer).GetSupportedInfras(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InfraUsageCollector_GetUsageInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsageInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfraUsageCollectorServer).GetUsageInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yorc.plugin.InfraUsageCollector/GetUsageInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfraUsageCollectorServer).GetUsageInfo(ctx, req.(*GetUsageInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _InfraUsageCollector_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "yorc.plugin.InfraUsageCollector",
+	HandlerType: (*InfraUsageCollectorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetSupportedInfras",
+			Handler:    _InfraUsageCollector_GetSupportedInfras_Handler,
+		},
+		{
+			MethodName: "GetUsageInfo",
+			Handler:    _InfraUsageCollector_GetUsageInfo_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin.proto",
+}
+
+// StoreClient is the client API for Store service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type StoreClient interface {
+	GetStoreImplementations(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TypesResponse, error)
+	NewStore(ctx context.Context, in *NewStoreRequest, opts ...grpc.CallOption) (*ErrorResponse, error)
+	Set(ctx context.Context, in *StoreSetRequest, opts ...grpc.CallOption) (*ErrorResponse, error)
+	Get(ctx context.Context, in *StoreKeyRequest, opts ...grpc.CallOption) (*StoreGetResponse, error)
+	Exist(ctx context.Context, in *StoreKeyRequest, opts ...grpc.CallOption) (*StoreExistResponse, error)
+	Keys(ctx context.Context, in *StoreKeyRequest, opts ...grpc.CallOption) (*StoreKeysResponse, error)
+	Delete(ctx context.Context, in *StoreDeleteRequest, opts ...grpc.CallOption) (*ErrorResponse, error)
+	GetLastModifyIndex(ctx context.Context, in *StoreKeyRequest, opts ...grpc.CallOption) (*StoreIndexResponse, error)
+	List(ctx context.Context, in *StoreListRequest, opts ...grpc.CallOption) (*StoreListResponse, error)
+}
+
+type storeClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewStoreClient(cc *grpc.ClientConn) StoreClient {
+	return &storeClient{cc}
+}
+
+func (c *storeClient) GetStoreImplementations(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TypesResponse, error) {
+	out := new(TypesResponse)
+	err := c.cc.Invoke(ctx, "/yorc.plugin.Store/GetStoreImplementations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeClient) NewStore(ctx context.Context, in *NewStoreRequest, opts ...grpc.CallOption) (*ErrorResponse, error) {
+	out := new(ErrorResponse)
+	err := c.cc.Invoke(ctx, "/yorc.plugin.Store/NewStore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeClient) Set(ctx context.Context, in *StoreSetRequest, opts ...grpc.CallOption) (*ErrorResponse, error) {
+	out := new(ErrorResponse)
+	err := c.cc.Invoke(ctx, "/yorc.plugin.Store/Set", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeClient) Get(ctx context.Context, in *StoreKeyRequest, opts ...grpc.CallOption) (*StoreGetResponse, error) {
+	out := new(StoreGetResponse)
+	err := c.cc.Invoke(ctx, "/yorc.plugin.Store/Get", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeClient) Exist(ctx context.Context, in *StoreKeyRequest, opts ...grpc.CallOption) (*StoreExistResponse, error) {
+	out := new(StoreExistResponse)
+	err := c.cc.Invoke(ctx, "/yorc.plugin.Store/Exist", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeClient) Keys(ctx context.Context, in *StoreKeyRequest, opts ...grpc.CallOption) (*StoreKeysResponse, error) {
+	out := new(StoreKeysResponse)
+	err := c.cc.Invoke(ctx, "/yorc.plugin.Store/Keys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeClient) Delete(ctx context.Context, in *StoreDeleteRequest, opts ...grpc.CallOption) (*ErrorResponse, error) {
+	out := new(ErrorResponse)
+	err := c.cc.Invoke(ctx, "/yorc.plugin.Store/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeClient) GetLastModifyIndex(ctx context.Context, in *StoreKeyRequest, opts ...grpc.CallOption) (*StoreIndexResponse, error) {
+	out := new(StoreIndexResponse)
+	err := c.cc.Invoke(ctx, "/yorc.plugin.Store/GetLastModifyIndex", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeClient) List(ctx context.Context, in *StoreListRequest, opts ...grpc.CallOption) (*StoreListResponse, error) {
+	out := new(StoreListResponse)
+	err := c.cc.Invoke(ctx, "/yorc.plugin.Store/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StoreServer is the server API for Store service.
+type StoreServer interface {
+	GetStoreImplementations(context.Context, *Empty) (*TypesResponse, error)
+	NewStore(context.Context, *NewStoreRequest) (*ErrorResponse, error)
+	Set(context.Context, *StoreSetRequest) (*ErrorResponse, error)
+	Get(context.Context, *StoreKeyRequest) (*StoreGetResponse, error)
+	Exist(context.Context, *StoreKeyRequest) (*StoreExistResponse, error)
+	Keys(context.Context, *StoreKeyRequest) (*StoreKeysResponse, error)
+	Delete(context.Context, *StoreDeleteRequest) (*ErrorResponse, error)
+	GetLastModifyIndex(context.Context, *StoreKeyRequest) (*StoreIndexResponse, error)
+	List(context.Context, *StoreListRequest) (*StoreListResponse, error)
+}
+
+// UnimplementedStoreServer can be embedded to have forward compatible implementations.
+type UnimplementedStoreServer struct {
+}
+
+func (*UnimplementedStoreServer) GetStoreImplementations(ctx context.Context, req *Empty) (*TypesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStoreImplementations not implemented")
+}
+func (*UnimplementedStoreServer) NewStore(ctx context.Context, req *NewStoreRequest) (*ErrorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NewStore not implemented")
+}
+func (*UnimplementedStoreServer) Set(ctx context.Context, req *StoreSetRequest) (*ErrorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Set not implemented")
+}
+func (*UnimplementedStoreServer) Get(ctx context.Context, req *StoreKeyRequest) (*StoreGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (*UnimplementedStoreServer) Exist(ctx context.Context, req *StoreKeyRequest) (*StoreExistResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Exist not implemented")
+}
+func (*UnimplementedStoreServer) Keys(ctx context.Context, req *StoreKeyRequest) (*StoreKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Keys not implemented")
+}
+func (*UnimplementedStoreServer) Delete(ctx context.Context, req *StoreDeleteRequest) (*ErrorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (*UnimplementedStoreServer) GetLastModifyIndex(ctx context.Context, req *StoreKeyRequest) (*StoreIndexResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLastModifyIndex not implemented")
+}
+func (*UnimplementedStoreServer) List(ctx context.Context, req *StoreListRequest) (*StoreListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+
+func RegisterStoreServer(s *grpc.Server, srv StoreServer) {
+	s.RegisterService(&_Store_serviceDesc, srv)
+}
+
+func _Store_GetStoreImplementations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoreServer).GetStoreImplementations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yorc.plugin.Store/GetStoreImplementations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoreServer).GetStoreImplementations(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Store_NewStore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewStoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoreServer).NewStore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yorc.plugin.Store/NewStore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoreServer).NewStore(ctx, req.(*NewStoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Store_Set_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StoreSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoreServer).Set(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yorc.plugin.Store/Set",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoreServer).Set(ctx, req.(*StoreSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Store_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StoreKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoreServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yorc.plugin.Store/Get",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoreServer).Get(ctx, req.(*StoreKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Store_Exist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StoreKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoreServer).Exist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yorc.plugin.Store/Exist",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoreServer).Exist(ctx, req.(*StoreKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Store_Keys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StoreKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoreServer).Keys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yorc.plugin.Store/Keys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoreServer).Keys(ctx, req.(*StoreKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Store_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StoreDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoreServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yorc.plugin.Store/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoreServer).Delete(ctx, req.(*StoreDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Store_GetLastModifyIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StoreKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoreServer).GetLastModifyIndex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yorc.plugin.Store/GetLastModifyIndex",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoreServer).GetLastModifyIndex(ctx, req.(*StoreKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Store_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StoreListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoreServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yorc.plugin.Store/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoreServer).List(ctx, req.(*StoreListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Store_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "yorc.plugin.Store",
+	HandlerType: (*StoreServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStoreImplementations",
+			Handler:    _Store_GetStoreImplementations_Handler,
+		},
+		{
+			MethodName: "NewStore",
+			Handler:    _Store_NewStore_Handler,
+		},
+		{
+			MethodName: "Set",
+			Handler:    _Store_Set_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _Store_Get_Handler,
+		},
+		{
+			MethodName: "Exist",
+			Handler:    _Store_Exist_Handler,
+		},
+		{
+			MethodName: "Keys",
+			Handler:    _Store_Keys_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Store_Delete_Handler,
+		},
+		{
+			MethodName: "GetLastModifyIndex",
+			Handler:    _Store_GetLastModifyIndex_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _Store_List_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin.proto",
+}
+
+// ConfigTemplateResolverClient is the client API for ConfigTemplateResolver service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ConfigTemplateResolverClient interface {
+	ResolveValueWithTemplates(ctx context.Context, in *ResolveValueRequest, opts ...grpc.CallOption) (*ResolveValueResponse, error)
+}
+
+type configTemplateResolverClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewConfigTemplateResolverClient(cc *grpc.ClientConn) ConfigTemplateResolverClient {
+	return &configTemplateResolverClient{cc}
+}
+
+func (c *configTemplateResolverClient) ResolveValueWithTemplates(ctx context.Context, in *ResolveValueRequest, opts ...grpc.CallOption) (*ResolveValueResponse, error) {
+	out := new(ResolveValueResponse)
+	err := c.cc.Invoke(ctx, "/yorc.plugin.ConfigTemplateResolver/ResolveValueWithTemplates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ConfigTemplateResolverServer is the server API for ConfigTemplateResolver service.
+type ConfigTemplateResolverServer interface {
+	ResolveValueWithTemplates(context.Context, *ResolveValueRequest) (*ResolveValueResponse, error)
+}
+
+// UnimplementedConfigTemplateResolverServer can be embedded to have forward compatible implementations.
+type UnimplementedConfigTemplateResolverServer struct {
+}
+
+func (*UnimplementedConfigTemplateResolverServer) ResolveValueWithTemplates(ctx context.Context, req *ResolveValueRequest) (*ResolveValueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveValueWithTemplates not implemented")
+}
+
+func RegisterConfigTemplateResolverServer(s *grpc.Server, srv ConfigTemplateResolverServer) {
+	s.RegisterService(&_ConfigTemplateResolver_serviceDesc, srv)
+}
+
+func _ConfigTemplateResolver_ResolveValueWithTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveValueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigTemplateResolverServer).ResolveValueWithTemplates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/yorc.plugin.ConfigTemplateResolver/ResolveValueWithTemplates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigTemplateResolverServer).ResolveValueWithTemplates(ctx, req.(*ResolveValueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ConfigTemplateResolver_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "yorc.plugin.ConfigTemplateResolver",
+	HandlerType: (*ConfigTemplateResolverServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ResolveValueWithTemplates",
+			Handler:    _ConfigTemplateResolver_ResolveValueWithTemplates_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin.proto",
+}
+
+// LogForwarderClient is the client API for LogForwarder service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type LogForwarderClient interface {
+	ForwardLogs(ctx context.Context, opts ...grpc.CallOption) (LogForwarder_ForwardLogsClient, error)
+}
+
+type logForwarderClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewLogForwarderClient(cc *grpc.ClientConn) LogForwarderClient {
+	return &logForwarderClient{cc}
+}
+
+func (c *logForwarderClient) ForwardLogs(ctx context.Context, opts ...grpc.CallOption) (LogForwarder_ForwardLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LogForwarder_serviceDesc.Streams[0], "/yorc.plugin.LogForwarder/ForwardLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &logForwarderForwardLogsClient{stream}
+	return x, nil
+}
+
+type LogForwarder_ForwardLogsClient interface {
+	Send(*LogEntry) error
+	CloseAndRecv() (*Empty, error)
+	grpc.ClientStream
+}
+
+type logForwarderForwardLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *logForwarderForwardLogsClient) Send(m *LogEntry) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *logForwarderForwardLogsClient) CloseAndRecv() (*Empty, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(Empty)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// LogForwarderServer is the server API for LogForwarder service.
+type LogForwarderServer interface {
+	ForwardLogs(LogForwarder_ForwardLogsServer) error
+}
+
+// UnimplementedLogForwarderServer can be embedded to have forward compatible implementations.
+type UnimplementedLogForwarderServer struct {
+}
+
+func (*UnimplementedLogForwarderServer) ForwardLogs(srv LogForwarder_ForwardLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ForwardLogs not implemented")
+}
+
+func RegisterLogForwarderServer(s *grpc.Server, srv LogForwarderServer) {
+	s.RegisterService(&_LogForwarder_serviceDesc, srv)
+}
+
+func _LogForwarder_ForwardLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LogForwarderServer).ForwardLogs(&logForwarderForwardLogsServer{stream})
+}
+
+type LogForwarder_ForwardLogsServer interface {
+	SendAndClose(*Empty) error
+	Recv() (*LogEntry, error)
+	grpc.ServerStream
+}
+
+type logForwarderForwardLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *logForwarderForwardLogsServer) SendAndClose(m *Empty) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *logForwarderForwardLogsServer) Recv() (*LogEntry, error) {
+	m := new(LogEntry)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _LogForwarder_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "yorc.plugin.LogForwarder",
+	HandlerType: (*LogForwarderServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ForwardLogs",
+			Handler:       _LogForwarder_ForwardLogs_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "plugin.proto",
+}
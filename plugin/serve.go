@@ -23,6 +23,8 @@ import (
 	"github.com/ystia/yorc/v4/config"
 	"github.com/ystia/yorc/v4/events"
 	"github.com/ystia/yorc/v4/prov"
+	"github.com/ystia/yorc/v4/storage"
+	"github.com/ystia/yorc/v4/storage/store"
 	"github.com/ystia/yorc/v4/vault"
 )
 
@@ -39,6 +41,18 @@ const (
 	ActionPluginName = "action"
 	// InfraUsageCollectorPluginName is the name of InfraUsageCollector Plugins it could be used as a lookup key in Client.Dispense
 	InfraUsageCollectorPluginName = "infraUsageCollector"
+	// StorePluginName is the name of Store Plugins it could be used as a lookup key in Client.Dispense
+	StorePluginName = "store"
+)
+
+const (
+	// NetRPCProtocolVersion is the version of the plugins protocol using net/rpc
+	NetRPCProtocolVersion = 3
+	// GRPCProtocolVersion is the version of the plugins protocol using gRPC
+	//
+	// The protocol version is negotiated between Yorc and its plugins, the highest
+	// version supported by both is used.
+	GRPCProtocolVersion = 4
 )
 
 // HandshakeConfig are used to just do a basic handshake between
@@ -46,7 +60,7 @@ const (
 // This prevents users from executing bad plugins or executing a plugin
 // directory. It is a UX feature, not a security feature.
 var HandshakeConfig = plugin.HandshakeConfig{
-	ProtocolVersion:  3,
+	ProtocolVersion:  NetRPCProtocolVersion,
 	MagicCookieKey:   "YORC_PLUG_API",
 	MagicCookieValue: "a3292e718f7c96578aae47e92b7475394e72e6da3de3455554462ba15dde56d1b3187ad0e5f809f50767e0d10ca6944fdf4c6c412380d3aa083b9e8951f7101e",
 }
@@ -74,6 +88,9 @@ type ServeOpts struct {
 	ActionTypes                        []string
 	InfraUsageCollectorFunc            InfraUsageCollectorFunc
 	InfraUsageCollectorSupportedInfras []string
+	// StoreBuilders are the store implementations provided by this plugin indexed by implementation name.
+	// These implementations could be referenced in the Yorc stores configuration.
+	StoreBuilders map[string]store.Builder
 }
 
 // Serve serves a plugin. This function never returns and should be the final
@@ -90,10 +107,16 @@ func Serve(opts *ServeOpts) {
 	// plugin.Serve() is setting standard log output to os.Stderr.
 	// This will be done in config.go by the defaultConfigManager
 
+	if opts != nil && len(opts.StoreBuilders) > 0 {
+		// Stores provided by this plugin may also be used by the plugin itself
+		storage.SetStoreBuilderResolver(newStoresManager(opts.StoreBuilders).builder)
+	}
+
 	plugin.Serve(&plugin.ServeConfig{
-		HandshakeConfig: HandshakeConfig,
-		Plugins:         getPlugins(opts),
-		Logger:          hclogger,
+		HandshakeConfig:  HandshakeConfig,
+		VersionedPlugins: getVersionedPlugins(opts),
+		GRPCServer:       plugin.DefaultGRPCServer,
+		Logger:           hclogger,
 	})
 }
 
@@ -108,6 +131,7 @@ func getPlugins(opts *ServeOpts) map[string]plugin.Plugin {
 		DefinitionsPluginName:         &DefinitionsPlugin{Definitions: opts.Definitions},
 		ConfigManagerPluginName:       &ConfigManagerPlugin{&defaultConfigManager{}},
 		InfraUsageCollectorPluginName: &InfraUsageCollectorPlugin{F: opts.InfraUsageCollectorFunc, SupportedInfras: opts.InfraUsageCollectorSupportedInfras},
+		StorePluginName:               &StorePlugin{Builders: opts.StoreBuilders},
 	}
 }
 
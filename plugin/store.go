@@ -0,0 +1,441 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"net/rpc"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/storage/store"
+)
+
+// StoreBuilder is an extension of store.Builder that exposes the store implementations it provides
+type StoreBuilder interface {
+	store.Builder
+	GetStoreImplementations() ([]string, error)
+}
+
+// storesManager creates and keeps track of the stores served by a plugin.
+//
+// Stores are identified by their names as defined in the Yorc configuration.
+// Values are exchanged JSON-encoded between Yorc and the plugin.
+type storesManager struct {
+	builders map[string]store.Builder
+	lock     sync.RWMutex
+	stores   map[string]store.Store
+}
+
+func newStoresManager(builders map[string]store.Builder) *storesManager {
+	return &storesManager{builders: builders, stores: make(map[string]store.Store)}
+}
+
+func (m *storesManager) implementations() []string {
+	impls := make([]string, 0, len(m.builders))
+	for impl := range m.builders {
+		impls = append(impls, impl)
+	}
+	sort.Strings(impls)
+	return impls
+}
+
+func (m *storesManager) builder(implementation string) (store.Builder, error) {
+	for impl, b := range m.builders {
+		if strings.EqualFold(impl, implementation) {
+			return b, nil
+		}
+	}
+	return nil, errors.Errorf("store implementation %q is not provided by this plugin", implementation)
+}
+
+func (m *storesManager) newStore(cfg config.Configuration, storeConfig config.Store) error {
+	builder, err := m.builder(storeConfig.Implementation)
+	if err != nil {
+		return err
+	}
+	s, err := builder.NewStore(cfg, storeConfig)
+	if err != nil {
+		return err
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.stores[storeConfig.Name] = s
+	return nil
+}
+
+func (m *storesManager) get(name string) (store.Store, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	s, ok := m.stores[name]
+	if !ok {
+		return nil, errors.Errorf("unknown store %q", name)
+	}
+	return s, nil
+}
+
+func (m *storesManager) set(ctx context.Context, name string, keyValues []StoreKeyValue) error {
+	s, err := m.get(name)
+	if err != nil {
+		return err
+	}
+	if len(keyValues) == 1 {
+		return s.Set(ctx, keyValues[0].Key, json.RawMessage(keyValues[0].Value))
+	}
+	kvs := make([]store.KeyValueIn, len(keyValues))
+	for i, kv := range keyValues {
+		kvs[i] = store.KeyValueIn{Key: kv.Key, Value: json.RawMessage(kv.Value)}
+	}
+	return s.SetCollection(ctx, kvs)
+}
+
+func (m *storesManager) getValue(name, key string) (bool, []byte, error) {
+	s, err := m.get(name)
+	if err != nil {
+		return false, nil, err
+	}
+	var raw json.RawMessage
+	found, err := s.Get(key, &raw)
+	return found, raw, err
+}
+
+func (m *storesManager) list(ctx context.Context, name, key string, waitIndex uint64, timeout time.Duration) ([]byte, uint64, error) {
+	s, err := m.get(name)
+	if err != nil {
+		return nil, 0, err
+	}
+	kvs, lastIndex, err := s.List(ctx, key, waitIndex, timeout)
+	if err != nil {
+		return nil, lastIndex, err
+	}
+	b, err := json.Marshal(kvs)
+	return b, lastIndex, errors.Wrap(err, "failed to encode store values")
+}
+
+func toStoreKeyValues(keyValues []store.KeyValueIn) ([]StoreKeyValue, error) {
+	res := make([]StoreKeyValue, len(keyValues))
+	for i, kv := range keyValues {
+		b, err := json.Marshal(kv.Value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to encode value for key %q", kv.Key)
+		}
+		res[i] = StoreKeyValue{Key: kv.Key, Value: b}
+	}
+	return res, nil
+}
+
+func decodeStoreValue(found bool, b []byte, v interface{}) (bool, error) {
+	if !found || v == nil {
+		return found, nil
+	}
+	return found, errors.Wrap(json.Unmarshal(b, v), "failed to decode store value")
+}
+
+func decodeStoreKeyValues(b []byte) ([]store.KeyValueOut, error) {
+	var kvs []store.KeyValueOut
+	if len(b) == 0 {
+		return kvs, nil
+	}
+	return kvs, errors.Wrap(json.Unmarshal(b, &kvs), "failed to decode store values")
+}
+
+// StorePlugin is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+type StorePlugin struct {
+	Builders map[string]store.Builder
+}
+
+// Server is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+func (p *StorePlugin) Server(b *plugin.MuxBroker) (interface{}, error) {
+	return &StoreServer{Broker: b, manager: newStoresManager(p.Builders)}, nil
+}
+
+// Client is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+func (p *StorePlugin) Client(b *plugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return &StoreClient{Broker: b, Client: c}, nil
+}
+
+// StoreClient is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+type StoreClient struct {
+	Broker *plugin.MuxBroker
+	Client *rpc.Client
+}
+
+// GetStoreImplementations is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+func (c *StoreClient) GetStoreImplementations() ([]string, error) {
+	var resp StoreGetStoreImplementationsResponse
+	err := c.Client.Call("Plugin.GetStoreImplementations", new(interface{}), &resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get store implementations for store plugin")
+	}
+	return resp.Implementations, toError(resp.Error)
+}
+
+// NewStore is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+func (c *StoreClient) NewStore(cfg config.Configuration, storeConfig config.Store) (store.Store, error) {
+	var resp StoreErrorResponse
+	err := c.Client.Call("Plugin.NewStore", &StoreNewStoreArgs{Conf: cfg, StoreConfig: storeConfig}, &resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create store for store plugin")
+	}
+	if err = toError(resp.Error); err != nil {
+		return nil, err
+	}
+	return &storeRPCClient{client: c, name: storeConfig.Name}, nil
+}
+
+// storeRPCClient is a store.Store implementation calling a store served by a plugin
+type storeRPCClient struct {
+	client *StoreClient
+	name   string
+}
+
+func (s *storeRPCClient) call(method string, args interface{}, resp *StoreResponse) error {
+	err := s.client.Client.Call("Plugin."+method, args, resp)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to call %s on store %q served by plugin", method, s.name)
+	}
+	return toError(resp.Error)
+}
+
+func (s *storeRPCClient) Set(ctx context.Context, k string, v interface{}) error {
+	return s.SetCollection(ctx, []store.KeyValueIn{{Key: k, Value: v}})
+}
+
+func (s *storeRPCClient) SetCollection(ctx context.Context, keyValues []store.KeyValueIn) error {
+	kvs, err := toStoreKeyValues(keyValues)
+	if err != nil {
+		return err
+	}
+	var resp StoreResponse
+	return s.call("Set", &StoreSetArgs{Store: s.name, KeyValues: kvs}, &resp)
+}
+
+func (s *storeRPCClient) Get(k string, v interface{}) (bool, error) {
+	var resp StoreResponse
+	err := s.call("Get", &StoreKeyArgs{Store: s.name, Key: k}, &resp)
+	if err != nil {
+		return false, err
+	}
+	return decodeStoreValue(resp.Found, resp.Value, v)
+}
+
+func (s *storeRPCClient) Exist(k string) (bool, error) {
+	var resp StoreResponse
+	err := s.call("Exist", &StoreKeyArgs{Store: s.name, Key: k}, &resp)
+	return resp.Found, err
+}
+
+func (s *storeRPCClient) Keys(k string) ([]string, error) {
+	var resp StoreResponse
+	err := s.call("Keys", &StoreKeyArgs{Store: s.name, Key: k}, &resp)
+	return resp.Keys, err
+}
+
+func (s *storeRPCClient) Delete(ctx context.Context, k string, recursive bool) error {
+	var resp StoreResponse
+	return s.call("Delete", &StoreKeyArgs{Store: s.name, Key: k, Recursive: recursive}, &resp)
+}
+
+func (s *storeRPCClient) GetLastModifyIndex(k string) (uint64, error) {
+	var resp StoreResponse
+	err := s.call("GetLastModifyIndex", &StoreKeyArgs{Store: s.name, Key: k}, &resp)
+	return resp.Index, err
+}
+
+func (s *storeRPCClient) List(ctx context.Context, k string, waitIndex uint64, timeout time.Duration) ([]store.KeyValueOut, uint64, error) {
+	id := s.client.Broker.NextId()
+	closeChan := make(chan struct{}, 0)
+	defer close(closeChan)
+	go clientMonitorContextCancellation(ctx, closeChan, id, s.client.Broker)
+
+	var resp StoreResponse
+	err := s.call("List", &StoreKeyArgs{ChannelID: id, Store: s.name, Key: k, WaitIndex: waitIndex, Timeout: timeout}, &resp)
+	if err != nil {
+		return nil, resp.Index, err
+	}
+	kvs, err := decodeStoreKeyValues(resp.Value)
+	return kvs, resp.Index, err
+}
+
+// StoreServer is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+type StoreServer struct {
+	Broker  *plugin.MuxBroker
+	manager *storesManager
+}
+
+// StoreKeyValue is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+type StoreKeyValue struct {
+	Key   string
+	Value []byte
+}
+
+// StoreNewStoreArgs is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+type StoreNewStoreArgs struct {
+	Conf        config.Configuration
+	StoreConfig config.Store
+}
+
+// StoreSetArgs is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+type StoreSetArgs struct {
+	Store     string
+	KeyValues []StoreKeyValue
+}
+
+// StoreKeyArgs is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+type StoreKeyArgs struct {
+	ChannelID uint32
+	Store     string
+	Key       string
+	Recursive bool
+	WaitIndex uint64
+	Timeout   time.Duration
+}
+
+// StoreGetStoreImplementationsResponse is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+type StoreGetStoreImplementationsResponse struct {
+	Implementations []string
+	Error           *RPCError
+}
+
+// StoreErrorResponse is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+type StoreErrorResponse struct {
+	Error *RPCError
+}
+
+// StoreResponse is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+type StoreResponse struct {
+	Found bool
+	// Value is JSON encoded
+	Value []byte
+	Keys  []string
+	Index uint64
+	Error *RPCError
+}
+
+func toRPCError(err error) *RPCError {
+	if err == nil {
+		return nil
+	}
+	return NewRPCError(err)
+}
+
+// GetStoreImplementations is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+func (s *StoreServer) GetStoreImplementations(_ interface{}, reply *StoreGetStoreImplementationsResponse) error {
+	*reply = StoreGetStoreImplementationsResponse{Implementations: s.manager.implementations()}
+	return nil
+}
+
+// NewStore is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+func (s *StoreServer) NewStore(args *StoreNewStoreArgs, reply *StoreErrorResponse) error {
+	*reply = StoreErrorResponse{Error: toRPCError(s.manager.newStore(args.Conf, args.StoreConfig))}
+	return nil
+}
+
+// Set is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+func (s *StoreServer) Set(args *StoreSetArgs, reply *StoreResponse) error {
+	*reply = StoreResponse{Error: toRPCError(s.manager.set(context.Background(), args.Store, args.KeyValues))}
+	return nil
+}
+
+// Get is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+func (s *StoreServer) Get(args *StoreKeyArgs, reply *StoreResponse) error {
+	found, value, err := s.manager.getValue(args.Store, args.Key)
+	*reply = StoreResponse{Found: found, Value: value, Error: toRPCError(err)}
+	return nil
+}
+
+// Exist is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+func (s *StoreServer) Exist(args *StoreKeyArgs, reply *StoreResponse) error {
+	st, err := s.manager.get(args.Store)
+	var found bool
+	if err == nil {
+		found, err = st.Exist(args.Key)
+	}
+	*reply = StoreResponse{Found: found, Error: toRPCError(err)}
+	return nil
+}
+
+// Keys is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+func (s *StoreServer) Keys(args *StoreKeyArgs, reply *StoreResponse) error {
+	st, err := s.manager.get(args.Store)
+	var keys []string
+	if err == nil {
+		keys, err = st.Keys(args.Key)
+	}
+	*reply = StoreResponse{Keys: keys, Error: toRPCError(err)}
+	return nil
+}
+
+// Delete is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+func (s *StoreServer) Delete(args *StoreKeyArgs, reply *StoreResponse) error {
+	st, err := s.manager.get(args.Store)
+	if err == nil {
+		err = st.Delete(context.Background(), args.Key, args.Recursive)
+	}
+	*reply = StoreResponse{Error: toRPCError(err)}
+	return nil
+}
+
+// GetLastModifyIndex is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+func (s *StoreServer) GetLastModifyIndex(args *StoreKeyArgs, reply *StoreResponse) error {
+	st, err := s.manager.get(args.Store)
+	var index uint64
+	if err == nil {
+		index, err = st.GetLastModifyIndex(args.Key)
+	}
+	*reply = StoreResponse{Index: index, Error: toRPCError(err)}
+	return nil
+}
+
+// List is public for use by reflexion and should be considered as private to this package.
+// Please do not use it directly.
+func (s *StoreServer) List(args *StoreKeyArgs, reply *StoreResponse) error {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	go s.Broker.AcceptAndServe(args.ChannelID, &RPCContextCanceller{CancelFunc: cancelFunc})
+	value, index, err := s.manager.list(ctx, args.Store, args.Key, args.WaitIndex, args.Timeout)
+	*reply = StoreResponse{Value: value, Index: index, Error: toRPCError(err)}
+	return nil
+}
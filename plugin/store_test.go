@@ -0,0 +1,274 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/storage/store"
+)
+
+type mockStoreBuilder struct {
+	storeConfigs []config.Store
+	stores       map[string]*mockStore
+}
+
+func (b *mockStoreBuilder) NewStore(cfg config.Configuration, storeConfig config.Store) (store.Store, error) {
+	if storeConfig.Name == "failing" {
+		return nil, NewRPCErrorFromMessage("failed to create store %q", storeConfig.Name)
+	}
+	b.storeConfigs = append(b.storeConfigs, storeConfig)
+	s := &mockStore{values: make(map[string]json.RawMessage), indexes: make(map[string]uint64), changed: make(chan struct{})}
+	b.stores[storeConfig.Name] = s
+	return s, nil
+}
+
+// mockStore is a simple in-memory store
+type mockStore struct {
+	lock    sync.Mutex
+	values  map[string]json.RawMessage
+	indexes map[string]uint64
+	index   uint64
+	changed chan struct{}
+}
+
+func (s *mockStore) Set(ctx context.Context, k string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.index++
+	s.values[k] = b
+	s.indexes[k] = s.index
+	close(s.changed)
+	s.changed = make(chan struct{})
+	return nil
+}
+
+func (s *mockStore) SetCollection(ctx context.Context, keyValues []store.KeyValueIn) error {
+	for _, kv := range keyValues {
+		if err := s.Set(ctx, kv.Key, kv.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *mockStore) Get(k string, v interface{}) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	b, ok := s.values[k]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(b, v)
+}
+
+func (s *mockStore) Exist(k string) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, ok := s.values[k]
+	return ok, nil
+}
+
+func (s *mockStore) Keys(k string) ([]string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	keys := make([]string, 0)
+	for key := range s.values {
+		if path.Dir(key) == k {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *mockStore) Delete(ctx context.Context, k string, recursive bool) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for key := range s.values {
+		if key == k || recursive && strings.HasPrefix(key, k+"/") {
+			delete(s.values, key)
+			delete(s.indexes, key)
+		}
+	}
+	return nil
+}
+
+func (s *mockStore) GetLastModifyIndex(k string) (uint64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.indexes[k], nil
+}
+
+func (s *mockStore) List(ctx context.Context, k string, waitIndex uint64, timeout time.Duration) ([]store.KeyValueOut, uint64, error) {
+	s.lock.Lock()
+	if waitIndex > 0 && s.index <= waitIndex {
+		changed := s.changed
+		s.lock.Unlock()
+		select {
+		case <-changed:
+		case <-time.After(timeout):
+		case <-ctx.Done():
+			return nil, waitIndex, ctx.Err()
+		}
+		s.lock.Lock()
+	}
+	defer s.lock.Unlock()
+	kvs := make([]store.KeyValueOut, 0)
+	for key, b := range s.values {
+		if strings.HasPrefix(key, k+"/") {
+			value := make(map[string]interface{})
+			if err := json.Unmarshal(b, &value); err != nil {
+				return nil, 0, err
+			}
+			kvs = append(kvs, store.KeyValueOut{Key: key, LastModifyIndex: s.indexes[key], Value: value, RawValue: b})
+		}
+	}
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].Key < kvs[j].Key })
+	return kvs, s.index, nil
+}
+
+type storeTestValue struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func setupStoreTestEnv(t *testing.T, grpcTransport bool) (*mockStoreBuilder, StoreBuilder) {
+	builder := &mockStoreBuilder{stores: make(map[string]*mockStore)}
+	builders := map[string]store.Builder{"memory": builder}
+	var client interface {
+		io.Closer
+		Dispense(string) (interface{}, error)
+	}
+	if grpcTransport {
+		client, _ = plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{StorePluginName: &StoreGRPCPlugin{Builders: builders}})
+	} else {
+		client, _ = plugin.TestPluginRPCConn(t, map[string]plugin.Plugin{StorePluginName: &StorePlugin{Builders: builders}}, nil)
+	}
+	t.Cleanup(func() { client.Close() })
+	raw, err := client.Dispense(StorePluginName)
+	require.NoError(t, err)
+	return builder, raw.(StoreBuilder)
+}
+
+func TestStorePlugin(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		grpc bool
+	}{
+		{"NetRPC", false},
+		{"GRPC", true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			builder, storeBuilder := setupStoreTestEnv(t, tt.grpc)
+
+			impls, err := storeBuilder.GetStoreImplementations()
+			require.NoError(t, err)
+			require.Equal(t, []string{"memory"}, impls)
+
+			_, err = storeBuilder.NewStore(config.Configuration{}, config.Store{Name: "unknown", Implementation: "other"})
+			require.Error(t, err)
+			_, err = storeBuilder.NewStore(config.Configuration{}, config.Store{Name: "failing", Implementation: "memory"})
+			require.Error(t, err)
+
+			storeConfig := config.Store{Name: "myStore", Implementation: "Memory", Types: []string{"Deployment"}}
+			s, err := storeBuilder.NewStore(config.Configuration{}, storeConfig)
+			require.NoError(t, err)
+			require.Len(t, builder.storeConfigs, 1)
+			require.Equal(t, storeConfig.Name, builder.storeConfigs[0].Name)
+			require.Equal(t, storeConfig.Types, builder.storeConfigs[0].Types)
+
+			ctx := context.Background()
+			require.NoError(t, s.Set(ctx, "root/one", storeTestValue{Name: "one", Count: 1}))
+			require.NoError(t, s.SetCollection(ctx, []store.KeyValueIn{
+				{Key: "root/two", Value: storeTestValue{Name: "two", Count: 2}},
+				{Key: "root/sub/three", Value: &storeTestValue{Name: "three", Count: 3}},
+			}))
+
+			var value storeTestValue
+			found, err := s.Get("root/two", &value)
+			require.NoError(t, err)
+			require.True(t, found)
+			require.Equal(t, storeTestValue{Name: "two", Count: 2}, value)
+			found, err = s.Get("root/none", &value)
+			require.NoError(t, err)
+			require.False(t, found)
+
+			exist, err := s.Exist("root/one")
+			require.NoError(t, err)
+			require.True(t, exist)
+
+			keys, err := s.Keys("root")
+			require.NoError(t, err)
+			require.Equal(t, []string{"root/one", "root/two"}, keys)
+
+			index, err := s.GetLastModifyIndex("root/sub/three")
+			require.NoError(t, err)
+			require.Equal(t, uint64(3), index)
+
+			kvs, lastIndex, err := s.List(ctx, "root", 0, 0)
+			require.NoError(t, err)
+			require.Equal(t, uint64(3), lastIndex)
+			require.Len(t, kvs, 3)
+			require.Equal(t, "root/one", kvs[0].Key)
+			require.Equal(t, "one", kvs[0].Value["name"])
+			require.JSONEq(t, `{"name":"one","count":1}`, string(kvs[0].RawValue))
+
+			// Blocking query released by a new value
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				s.Set(ctx, "root/four", storeTestValue{Name: "four", Count: 4})
+			}()
+			kvs, lastIndex, err = s.List(ctx, "root", lastIndex, time.Minute)
+			require.NoError(t, err)
+			require.Equal(t, uint64(4), lastIndex)
+			require.Len(t, kvs, 4)
+
+			// Blocking query cancelled by the context
+			cancelCtx, cancel := context.WithCancel(ctx)
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				cancel()
+			}()
+			_, _, err = s.List(cancelCtx, "root", lastIndex, time.Minute)
+			require.Error(t, err)
+
+			require.NoError(t, s.Delete(ctx, "root", true))
+			exist, err = s.Exist("root/one")
+			require.NoError(t, err)
+			require.False(t, exist)
+		})
+	}
+}
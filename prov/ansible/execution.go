@@ -42,6 +42,7 @@ import (
 	"github.com/ystia/yorc/v4/helper/consulutil"
 	"github.com/ystia/yorc/v4/helper/executil"
 	"github.com/ystia/yorc/v4/helper/provutil"
+	"github.com/ystia/yorc/v4/helper/redactutil"
 	"github.com/ystia/yorc/v4/helper/sshutil"
 	"github.com/ystia/yorc/v4/helper/stringutil"
 	"github.com/ystia/yorc/v4/log"
@@ -908,6 +909,12 @@ func (e *executionCommon) executeWithCurrentInstance(ctx context.Context, retry
 				log.Debugf("%+v", err)
 				events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RegisterAsString(err.Error())
 			}
+		} else {
+			// Kept recipes should not disclose secrets
+			err := redactutil.RedactFiles(e.deploymentID, ansibleExecutionRootDir)
+			if err != nil {
+				log.Printf("Failed to redact secrets from ansible execution directory %q: %v", ansibleExecutionRootDir, err)
+			}
 		}
 	}()
 	ansibleHostVarsPath := filepath.Join(ansibleRecipePath, "host_vars")
@@ -0,0 +1,339 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/moby/moby/client"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov"
+	"github.com/ystia/yorc/v4/prov/operations"
+	"github.com/ystia/yorc/v4/tasks"
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+const dockerImagePrefix = "docker://"
+
+const stopTimeout = 10 * time.Second
+
+type execution struct {
+	cfg                     config.Configuration
+	deploymentID            string
+	taskID                  string
+	nodeName                string
+	operation               prov.Operation
+	localCli                *client.Client
+	isOrchestratorOperation bool
+	image                   string
+	registryAuth            string
+	envInputs               []*operations.EnvInput
+	instances               []string
+}
+
+func newExecution(ctx context.Context, cfg config.Configuration, taskID, deploymentID, nodeName string, operation prov.Operation, localCli *client.Client) (*execution, error) {
+	e := &execution{
+		cfg:                     cfg,
+		deploymentID:            deploymentID,
+		taskID:                  taskID,
+		nodeName:                nodeName,
+		operation:               operation,
+		localCli:                localCli,
+		isOrchestratorOperation: operations.IsOrchestratorHostOperation(operation),
+	}
+	return e, e.resolveExecution(ctx)
+}
+
+func (e *execution) resolveExecution(ctx context.Context) error {
+	log.Debugf("Preparing execution of operation %q on node %q for deployment %q", e.operation.Name, e.nodeName, e.deploymentID)
+	var err error
+	if err = e.resolveImage(ctx); err != nil {
+		return err
+	}
+	e.envInputs, _, err = operations.ResolveInputs(ctx, e.deploymentID, e.nodeName, e.taskID, e.operation)
+	if err != nil {
+		return err
+	}
+	e.instances, err = tasks.GetInstances(ctx, e.taskID, e.deploymentID, e.nodeName)
+	return err
+}
+
+// resolveImage computes the image reference from the operation implementation and its optional repository
+func (e *execution) resolveImage(ctx context.Context) error {
+	image, err := deployments.GetOperationImplementationFile(ctx, e.deploymentID, e.operation.ImplementedInNodeTemplate, e.operation.ImplementedInType, e.operation.Name)
+	if err != nil {
+		return err
+	}
+	e.image = strings.TrimPrefix(image, dockerImagePrefix)
+	if e.image == "" {
+		return errors.Errorf("missing container image for operation %q of node %q", e.operation.Name, e.nodeName)
+	}
+
+	repoName, err := deployments.GetOperationImplementationRepository(ctx, e.deploymentID, e.operation.ImplementedInNodeTemplate, e.operation.ImplementedInType, e.operation.Name)
+	if err != nil || repoName == "" {
+		return err
+	}
+	repoURL, err := deployments.GetRepositoryURLFromName(ctx, e.deploymentID, repoName)
+	if err != nil {
+		return err
+	}
+	var serverAddress string
+	// Just ignore default public Docker registry
+	if repoURL != "" && repoURL != deployments.DockerHubURL {
+		urlStruct, err := url.Parse(repoURL)
+		if err != nil {
+			return errors.Wrapf(err, "invalid URL for repository %q", repoName)
+		}
+		serverAddress = urlStruct.Host
+		e.image = path.Join(serverAddress, e.image)
+	}
+
+	token, user, err := deployments.GetRepositoryTokenUserFromName(ctx, e.deploymentID, repoName)
+	if err != nil || user == "" {
+		return err
+	}
+	authJSON, err := json.Marshal(types.AuthConfig{Username: user, Password: token, ServerAddress: serverAddress})
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode credentials of repository %q", repoName)
+	}
+	e.registryAuth = base64.URLEncoding.EncodeToString(authJSON)
+	return nil
+}
+
+// standardOperationName returns the short name of an operation of the Standard interface or an empty string
+// for other interfaces
+func (e *execution) standardOperationName() string {
+	name := strings.ToLower(e.operation.Name)
+	for _, prefix := range []string{tosca.StandardInterfaceName + ".", tosca.StandardInterfaceShortName + "."} {
+		if strings.HasPrefix(name, prefix) {
+			return strings.TrimPrefix(name, prefix)
+		}
+	}
+	return ""
+}
+
+func (e *execution) execute(ctx context.Context) error {
+	for _, instanceID := range e.instances {
+		if err := e.executeOnInstance(ctx, instanceID); err != nil {
+			return errors.Wrapf(err, "failed to execute operation %q on instance %q of node %q", e.operation.Name, instanceID, e.nodeName)
+		}
+	}
+	return nil
+}
+
+func (e *execution) executeOnInstance(ctx context.Context, instanceID string) error {
+	spec, err := buildContainerSpec(e.image, e.nodeName, instanceID, e.envInputs)
+	if err != nil {
+		return err
+	}
+	host, err := e.getContainerHost(ctx, instanceID, spec.socket)
+	if err != nil {
+		return err
+	}
+	defer host.close()
+
+	// Standard start operation runs a long-lived container, stop and delete operations act on it,
+	// any other operation runs a container until its completion
+	switch e.standardOperationName() {
+	case "start":
+		return e.startContainer(ctx, host, spec, instanceID)
+	case "stop":
+		return e.stopContainer(ctx, host, instanceID)
+	case "delete":
+		return e.removeContainer(ctx, host, instanceID)
+	default:
+		return e.runContainer(ctx, host, spec)
+	}
+}
+
+func (e *execution) createContainer(ctx context.Context, host *containerHost, spec *containerSpec) (string, error) {
+	cc, hc, err := spec.configs()
+	if err != nil {
+		return "", err
+	}
+
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, e.deploymentID).Registerf("Pulling container image: %s", spec.image)
+	pullResp, err := host.cli.ImagePull(ctx, spec.image, types.ImagePullOptions{RegistryAuth: e.registryAuth})
+	if pullResp != nil {
+		// The pull progress is a stream of JSON messages, one per line
+		scanner := bufio.NewScanner(pullResp)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, e.deploymentID).Registerf("Pulling container image %s: %s", spec.image, line)
+			}
+		}
+		pullResp.Close()
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to pull container image %q", spec.image)
+	}
+
+	createResp, err := host.cli.ContainerCreate(ctx, cc, hc, nil, "")
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to create container from image %q", spec.image)
+	}
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, e.deploymentID).Registerf("Container with id %q created on host %q", createResp.ID, host.address)
+	return createResp.ID, nil
+}
+
+// startContainer starts the container of an instance
+//
+// The container previously created for the instance is restarted if it still exists, otherwise a new container
+// is created so that stopping and starting an instance doesn't leak containers.
+func (e *execution) startContainer(ctx context.Context, host *containerHost, spec *containerSpec, instanceID string) error {
+	id, err := e.getContainerID(ctx, instanceID)
+	if err != nil {
+		return err
+	}
+	if id != "" {
+		_, err = host.cli.ContainerInspect(ctx, id)
+		if client.IsErrContainerNotFound(err) {
+			id = ""
+		} else if err != nil {
+			return errors.Wrapf(err, "Failed to inspect container %q", id)
+		}
+	}
+	created := id == ""
+	if created {
+		id, err = e.createContainer(ctx, host, spec)
+		if err != nil {
+			return err
+		}
+	}
+	err = host.cli.ContainerStart(ctx, id, types.ContainerStartOptions{})
+	if err != nil {
+		if created {
+			host.cli.ContainerRemove(ctx, id, types.ContainerRemoveOptions{Force: true})
+		}
+		return errors.Wrapf(err, "Failed to start container %q", id)
+	}
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, e.deploymentID).Registerf("Container with id %q started from image %q", id, spec.image)
+
+	if created {
+		err = deployments.SetInstanceAttribute(ctx, e.deploymentID, e.nodeName, instanceID, "container_id", id)
+		if err != nil {
+			return err
+		}
+	}
+	inspect, err := host.cli.ContainerInspect(ctx, id)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to inspect container %q", id)
+	}
+	endpoints := make(map[string]string)
+	if inspect.NetworkSettings != nil {
+		err = deployments.SetInstanceAttribute(ctx, e.deploymentID, e.nodeName, instanceID, "container_ip_address", inspect.NetworkSettings.IPAddress)
+		if err != nil {
+			return err
+		}
+		endpoints = containerEndpoints(inspect.NetworkSettings.Ports, host.address)
+	}
+	return deployments.SetInstanceAttributeComplex(ctx, e.deploymentID, e.nodeName, instanceID, "container_endpoints", endpoints)
+}
+
+func (e *execution) getContainerID(ctx context.Context, instanceID string) (string, error) {
+	id, err := deployments.GetInstanceAttributeValue(ctx, e.deploymentID, e.nodeName, instanceID, "container_id")
+	if err != nil || id == nil {
+		return "", err
+	}
+	return id.RawString(), nil
+}
+
+func (e *execution) stopContainer(ctx context.Context, host *containerHost, instanceID string) error {
+	id, err := e.getContainerID(ctx, instanceID)
+	if err != nil || id == "" {
+		return err
+	}
+	timeout := stopTimeout
+	err = host.cli.ContainerStop(ctx, id, &timeout)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to stop container %q", id)
+	}
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, e.deploymentID).Registerf("Container with id %q stopped", id)
+	return nil
+}
+
+func (e *execution) removeContainer(ctx context.Context, host *containerHost, instanceID string) error {
+	id, err := e.getContainerID(ctx, instanceID)
+	if err != nil || id == "" {
+		return err
+	}
+	err = host.cli.ContainerRemove(ctx, id, types.ContainerRemoveOptions{Force: true})
+	if err != nil && !client.IsErrContainerNotFound(err) {
+		return errors.Wrapf(err, "Failed to remove container %q", id)
+	}
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, e.deploymentID).Registerf("Container with id %q removed", id)
+	return deployments.SetInstanceAttribute(ctx, e.deploymentID, e.nodeName, instanceID, "container_id", "")
+}
+
+func (e *execution) runContainer(ctx context.Context, host *containerHost, spec *containerSpec) error {
+	id, err := e.createContainer(ctx, host, spec)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err := host.cli.ContainerRemove(context.Background(), id, types.ContainerRemoveOptions{Force: true})
+		if err != nil {
+			log.Printf("Failed to remove container %q: %v", id, err)
+		}
+	}()
+	err = host.cli.ContainerStart(ctx, id, types.ContainerStartOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to start container %q", id)
+	}
+	exitCode, err := host.cli.ContainerWait(ctx, id)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to wait for container %q", id)
+	}
+	e.logContainerOutput(ctx, host, id)
+	if exitCode != 0 {
+		return errors.Errorf("container %q from image %q exited with code %d", id, spec.image, exitCode)
+	}
+	return nil
+}
+
+func (e *execution) logContainerOutput(ctx context.Context, host *containerHost, id string) {
+	logs, err := host.cli.ContainerLogs(ctx, id, types.ContainerLogsOptions{ShowStdout: true, ShowStderr: true})
+	if err != nil {
+		log.Printf("Failed to retrieve logs of container %q: %v", id, err)
+		return
+	}
+	defer logs.Close()
+	var stdout, stderr bytes.Buffer
+	if _, err = stdcopy.StdCopy(&stdout, &stderr, logs); err != nil {
+		log.Printf("Failed to read logs of container %q: %v", id, err)
+	}
+	if stdout.Len() > 0 {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, e.deploymentID).RegisterAsString(fmt.Sprintf("[StdOut] %s", stdout.String()))
+	}
+	if stderr.Len() > 0 {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, e.deploymentID).RegisterAsString(fmt.Sprintf("[StdErr] %s", stderr.String()))
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+	"github.com/moby/moby/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/prov"
+	"github.com/ystia/yorc/v4/testutil"
+)
+
+func TestStandardOperationName(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		operation string
+		want      string
+	}{
+		{"tosca.interfaces.node.lifecycle.Standard.start", "start"},
+		{"standard.stop", "stop"},
+		{"Standard.Delete", "delete"},
+		{"tosca.interfaces.node.lifecycle.Runnable.run", ""},
+		{"custom.start", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.operation, func(t *testing.T) {
+			e := &execution{operation: prov.Operation{Name: tt.operation}}
+			require.Equal(t, tt.want, e.standardOperationName())
+		})
+	}
+}
+
+// dockerMock simulates a container engine, it records the requests it receives and the containers it hosts
+type dockerMock struct {
+	// containers are the existing containers indexed by ID with their running state
+	containers map[string]bool
+	created    int
+	requests   []string
+	exitCode   int64
+}
+
+func newDockerMock() *dockerMock {
+	return &dockerMock{containers: make(map[string]bool)}
+}
+
+func (m *dockerMock) client(t *testing.T) *client.Client {
+	hc := &http.Client{
+		Transport: &http.Transport{},
+	}
+	cli, err := client.NewClient("tcp://somewhere:42", "1.25", hc, nil)
+	require.NoError(t, err)
+	hc.Transport = testutil.NewMockClient(m.do).Transport
+	return cli
+}
+
+func (m *dockerMock) do(r *http.Request) (*http.Response, error) {
+	p := strings.TrimPrefix(r.URL.Path, "/v1.25")
+	m.requests = append(m.requests, r.Method+" "+p)
+	switch {
+	case p == "/images/create":
+		return response(http.StatusOK, "{\"status\":\"Pulling from library/nginx\"}\n{\"status\":\"Status: Downloaded newer image for nginx:1.19\"}\n"), nil
+	case p == "/containers/create":
+		m.created++
+		id := fmt.Sprintf("c%d", m.created)
+		m.containers[id] = false
+		return jsonResponse(container.ContainerCreateCreatedBody{ID: id})
+	}
+	elems := strings.Split(strings.TrimPrefix(p, "/containers/"), "/")
+	id := elems[0]
+	running, ok := m.containers[id]
+	if !ok {
+		return response(http.StatusNotFound, "No such container: "+id), nil
+	}
+	switch {
+	case r.Method == http.MethodDelete:
+		delete(m.containers, id)
+	case elems[1] == "start":
+		m.containers[id] = true
+	case elems[1] == "stop":
+		m.containers[id] = false
+	case elems[1] == "wait":
+		m.containers[id] = false
+		return jsonResponse(container.ContainerWaitOKBody{StatusCode: m.exitCode})
+	case elems[1] == "logs":
+		return response(http.StatusOK, ""), nil
+	case elems[1] == "json":
+		return jsonResponse(types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{ID: id, State: &types.ContainerState{Running: running}},
+			NetworkSettings: &types.NetworkSettings{
+				NetworkSettingsBase:    types.NetworkSettingsBase{Ports: nat.PortMap{"80/tcp": []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: "8080"}}}},
+				DefaultNetworkSettings: types.DefaultNetworkSettings{IPAddress: "172.17.0.2"},
+			},
+		})
+	}
+	return response(http.StatusNoContent, ""), nil
+}
+
+func response(status int, body string) *http.Response {
+	return &http.Response{StatusCode: status, Body: ioutil.NopCloser(bytes.NewReader([]byte(body)))}
+}
+
+func jsonResponse(obj interface{}) (*http.Response, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	return response(http.StatusOK, string(b)), nil
+}
+
+func requireInstanceAttribute(t *testing.T, deploymentID, attribute string, expected interface{}) {
+	t.Helper()
+	value, err := deployments.GetInstanceAttributeValue(context.Background(), deploymentID, "Web", "0", attribute)
+	require.NoError(t, err)
+	require.NotNil(t, value, "attribute %q not set", attribute)
+	require.Equal(t, expected, value.Value, "unexpected value for attribute %q", attribute)
+}
+
+func testContainerLifecycle(t *testing.T) {
+	deploymentID := testutil.BuildDeploymentID(t)
+	ctx := events.NewContext(context.Background(), events.LogOptionalFields{events.NodeID: "Web"})
+	err := deployments.StoreDeploymentDefinition(ctx, deploymentID, "testdata/topology.yml")
+	require.NoError(t, err)
+
+	mock := newDockerMock()
+	host := &containerHost{cli: mock.client(t), address: "10.0.0.2", close: func() {}}
+	spec, err := buildContainerSpec("nginx:1.19", "Web", "0", nil)
+	require.NoError(t, err)
+	e := &execution{deploymentID: deploymentID, nodeName: "Web"}
+
+	// First start creates the container and exposes its attributes
+	require.NoError(t, e.startContainer(ctx, host, spec, "0"))
+	require.Equal(t, map[string]bool{"c1": true}, mock.containers)
+	requireInstanceAttribute(t, deploymentID, "container_id", "c1")
+	requireInstanceAttribute(t, deploymentID, "container_ip_address", "172.17.0.2")
+	requireInstanceAttribute(t, deploymentID, "container_endpoints", map[string]interface{}{"80/tcp": "10.0.0.2:8080"})
+
+	// Image pull progress is logged line by line
+	logs, _, err := events.LogsEvents(ctx, deploymentID, 0, 5*time.Second)
+	require.NoError(t, err)
+	var pullLogs int
+	for _, l := range logs {
+		if strings.Contains(string(l), "Pulling container image nginx:1.19: {") {
+			pullLogs++
+		}
+	}
+	require.Equal(t, 2, pullLogs)
+
+	// Stop and start cycles reuse the container
+	require.NoError(t, e.stopContainer(ctx, host, "0"))
+	require.Equal(t, map[string]bool{"c1": false}, mock.containers)
+	require.NoError(t, e.startContainer(ctx, host, spec, "0"))
+	require.Equal(t, map[string]bool{"c1": true}, mock.containers)
+	require.Equal(t, 1, mock.created)
+
+	require.NoError(t, e.stopContainer(ctx, host, "0"))
+	require.NoError(t, e.removeContainer(ctx, host, "0"))
+	require.Len(t, mock.containers, 0)
+	requireInstanceAttribute(t, deploymentID, "container_id", "")
+
+	// Removing an already removed container succeeds
+	require.NoError(t, deployments.SetInstanceAttribute(ctx, deploymentID, "Web", "0", "container_id", "c1"))
+	require.NoError(t, e.removeContainer(ctx, host, "0"))
+
+	// A container removed outside of Yorc is created again
+	require.NoError(t, deployments.SetInstanceAttribute(ctx, deploymentID, "Web", "0", "container_id", "c1"))
+	require.NoError(t, e.startContainer(ctx, host, spec, "0"))
+	require.Equal(t, map[string]bool{"c2": true}, mock.containers)
+	requireInstanceAttribute(t, deploymentID, "container_id", "c2")
+}
+
+func testRunContainer(t *testing.T) {
+	deploymentID := testutil.BuildDeploymentID(t)
+	ctx := context.Background()
+	mock := newDockerMock()
+	host := &containerHost{cli: mock.client(t), address: "localhost", close: func() {}}
+	spec, err := buildContainerSpec("busybox", "Web", "0", nil)
+	require.NoError(t, err)
+	e := &execution{deploymentID: deploymentID, nodeName: "Web"}
+
+	require.NoError(t, e.runContainer(ctx, host, spec))
+	require.Len(t, mock.containers, 0, "container should be removed once completed")
+
+	// A non-zero exit code fails the operation
+	mock.exitCode = 1
+	err = e.runContainer(ctx, host, spec)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "exited with code 1")
+	require.Len(t, mock.containers, 0)
+	require.Contains(t, mock.requests, "DELETE /containers/c2")
+}
@@ -0,0 +1,52 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"context"
+	"time"
+
+	"github.com/moby/moby/client"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov"
+)
+
+type defaultExecutor struct {
+	localCli *client.Client
+}
+
+func newExecutor() *defaultExecutor {
+	cli, err := client.NewEnvClient()
+	if err != nil {
+		err = errors.Wrap(err, "failed to create docker execution client, container operations hosted on orchestrator are disabled")
+		log.Printf("%v", err)
+	}
+	return &defaultExecutor{localCli: cli}
+}
+
+func (e *defaultExecutor) ExecAsyncOperation(ctx context.Context, conf config.Configuration, taskID, deploymentID, nodeName string, operation prov.Operation, stepName string) (*prov.Action, time.Duration, error) {
+	return nil, 0, errors.Errorf("asynchronous operations are not supported by the container executor")
+}
+
+func (e *defaultExecutor) ExecOperation(ctx context.Context, conf config.Configuration, taskID, deploymentID, nodeName string, operation prov.Operation) error {
+	exec, err := newExecution(ctx, conf, taskID, deploymentID, nodeName, operation, e.localCli)
+	if err != nil {
+		return err
+	}
+	return exec.execute(ctx)
+}
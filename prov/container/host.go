@@ -0,0 +1,156 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/docker/docker/api"
+	"github.com/mitchellh/mapstructure"
+	"github.com/moby/moby/client"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/sshutil"
+	"github.com/ystia/yorc/v4/tosca/types"
+)
+
+// containerHost gives access to the container engine where containers of a node instance are run
+type containerHost struct {
+	cli *client.Client
+	// address used to reach published container ports
+	address string
+	close   func()
+}
+
+func (e *execution) getContainerHost(ctx context.Context, instanceID, socket string) (*containerHost, error) {
+	if !e.isOrchestratorOperation {
+		sshClient, err := e.resolveHostSSHClient(ctx, instanceID)
+		if err != nil {
+			return nil, err
+		}
+		if sshClient != nil {
+			return newRemotePodmanHost(sshClient, socket)
+		}
+	}
+	if e.localCli == nil {
+		return nil, errors.New("local docker client is not available, check the docker configuration of the Yorc server")
+	}
+	return &containerHost{cli: e.localCli, address: "localhost", close: func() {}}, nil
+}
+
+// newRemotePodmanHost returns a container host using the Docker compatible API of a Podman socket reached over SSH
+func newRemotePodmanHost(sshClient *sshutil.SSHClient, socket string) (*containerHost, error) {
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return sshClient.DialUnix(ctx, socket)
+		},
+	}
+	cli, err := client.NewClient("unix://"+socket, api.DefaultVersion, &http.Client{Transport: transport}, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create podman client for host %q", sshClient.Host)
+	}
+	return &containerHost{cli: cli, address: sshClient.Host, close: transport.CloseIdleConnections}, nil
+}
+
+// resolveHostSSHClient looks for the compute hosting the node in the hostedOn hierarchy and returns a SSH client
+// to connect to it. A nil client is returned if the node is not hosted on a compute with an endpoint.
+func (e *execution) resolveHostSSHClient(ctx context.Context, instanceID string) (*sshutil.SSHClient, error) {
+	host := e.nodeName
+	for host != "" {
+		capType, err := deployments.GetNodeCapabilityType(ctx, e.deploymentID, host, "endpoint")
+		if err != nil {
+			return nil, err
+		}
+		hasEndpoint, err := deployments.IsTypeDerivedFrom(ctx, e.deploymentID, capType, "tosca.capabilities.Endpoint")
+		if err != nil {
+			return nil, err
+		}
+		if hasEndpoint {
+			ipAddress, err := deployments.GetInstanceCapabilityAttributeValue(ctx, e.deploymentID, host, instanceID, "endpoint", "ip_address")
+			if err != nil {
+				return nil, err
+			}
+			if ipAddress != nil && ipAddress.RawString() != "" {
+				return e.newSSHClient(ctx, host, instanceID, ipAddress.RawString())
+			}
+		}
+		host, err = deployments.GetHostedOnNode(ctx, e.deploymentID, host)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (e *execution) newSSHClient(ctx context.Context, host, instanceID, ipAddress string) (*sshutil.SSHClient, error) {
+	credentials := new(types.Credential)
+	credentialValue, err := deployments.GetInstanceCapabilityAttributeValue(ctx, e.deploymentID, host, instanceID, "endpoint", "credentials")
+	if err != nil {
+		return nil, err
+	}
+	if credentialValue != nil && credentialValue.RawString() != "" {
+		err = mapstructure.Decode(credentialValue.Value, credentials)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode credentials for node %q", host)
+		}
+	}
+
+	sshConfig := &ssh.ClientConfig{
+		User:            config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("host.user", credentials.User).(string),
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         e.cfg.SSHConnectionTimeout,
+	}
+	keys, err := sshutil.GetKeysFromCredentialsDataType(credentials)
+	if err != nil {
+		return nil, err
+	}
+	for keyName, pk := range keys {
+		keyAuth, err := sshutil.ReadSSHPrivateKey(pk)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read key %q", keyName)
+		}
+		sshConfig.Auth = append(sshConfig.Auth, keyAuth)
+	}
+	if credentials.Token != "" {
+		password := config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("host.password", credentials.Token).(string)
+		sshConfig.Auth = append(sshConfig.Auth, ssh.Password(password))
+	}
+
+	port := 22
+	portValue, err := deployments.GetInstanceCapabilityAttributeValue(ctx, e.deploymentID, host, instanceID, "endpoint", "port")
+	if err != nil {
+		return nil, err
+	}
+	if portValue != nil && portValue.RawString() != "" {
+		port, err = strconv.Atoi(portValue.RawString())
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to convert port value:%q to int", portValue)
+		}
+	}
+
+	return &sshutil.SSHClient{
+		Config:       sshConfig,
+		Host:         config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("host.ip_address", ipAddress).(string),
+		Port:         port,
+		MaxRetries:   e.cfg.SSHConnectionMaxRetries,
+		RetryBackoff: e.cfg.SSHConnectionRetryBackoff,
+	}, nil
+}
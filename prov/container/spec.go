@@ -0,0 +1,148 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/prov/operations"
+)
+
+// Operation inputs having a specific meaning for the container executor.
+// Other operation inputs are passed to the container as environment variables.
+const (
+	// List of port mappings using the docker syntax ([[hostIP:]hostPort:]containerPort[/protocol])
+	portsInput = "container_ports"
+	// List of volumes using the docker syntax (hostPath:containerPath[:options])
+	volumesInput = "container_volumes"
+	// Command overriding the image default one
+	commandInput = "container_command"
+	// Path of the Podman socket on the remote host
+	socketInput = "container_socket"
+)
+
+const defaultPodmanSocket = "/run/podman/podman.sock"
+
+type containerSpec struct {
+	image   string
+	env     []string
+	ports   []string
+	volumes []string
+	command []string
+	socket  string
+}
+
+// buildContainerSpec computes the container specification of a given node instance from resolved operation inputs
+func buildContainerSpec(image, nodeName, instanceID string, envInputs []*operations.EnvInput) (*containerSpec, error) {
+	instanceName := operations.GetInstanceName(nodeName, instanceID)
+	// Keep the value of the current instance if any, otherwise the first one
+	values := make(map[string]string)
+	names := make([]string, 0)
+	for _, input := range envInputs {
+		_, ok := values[input.Name]
+		if !ok {
+			names = append(names, input.Name)
+		}
+		if !ok || input.InstanceName == instanceName {
+			values[input.Name] = input.Value
+		}
+	}
+
+	spec := &containerSpec{image: image, socket: defaultPodmanSocket}
+	var err error
+	for _, name := range names {
+		value := values[name]
+		switch name {
+		case portsInput:
+			spec.ports, err = parseListInput(name, value)
+		case volumesInput:
+			spec.volumes, err = parseListInput(name, value)
+		case commandInput:
+			spec.command, err = parseListInput(name, value)
+		case socketInput:
+			if value != "" {
+				spec.socket = value
+			}
+		default:
+			spec.env = append(spec.env, fmt.Sprintf("%s=%s", name, value))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	spec.env = append(spec.env, "NODE="+nodeName, "INSTANCE="+instanceName)
+	return spec, nil
+}
+
+// parseListInput accepts either a JSON list or a comma-separated list of values
+func parseListInput(name, value string) ([]string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	var res []string
+	if strings.HasPrefix(value, "[") {
+		err := json.Unmarshal([]byte(value), &res)
+		return res, errors.Wrapf(err, "invalid list value for input %q", name)
+	}
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			res = append(res, v)
+		}
+	}
+	return res, nil
+}
+
+func (s *containerSpec) configs() (*container.Config, *container.HostConfig, error) {
+	exposedPorts, portBindings, err := nat.ParsePortSpecs(s.ports)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid container port mapping")
+	}
+	cc := &container.Config{
+		Image:        s.image,
+		Env:          s.env,
+		ExposedPorts: exposedPorts,
+	}
+	if len(s.command) > 0 {
+		cc.Cmd = s.command
+	}
+	hc := &container.HostConfig{
+		Binds:        s.volumes,
+		PortBindings: portBindings,
+	}
+	return cc, hc, nil
+}
+
+// containerEndpoints returns published endpoints of a container indexed by container port
+func containerEndpoints(ports nat.PortMap, hostAddress string) map[string]string {
+	endpoints := make(map[string]string)
+	for port, bindings := range ports {
+		if len(bindings) == 0 {
+			continue
+		}
+		host := bindings[0].HostIP
+		if host == "" || host == "0.0.0.0" || host == "::" {
+			host = hostAddress
+		}
+		endpoints[string(port)] = fmt.Sprintf("%s:%s", host, bindings[0].HostPort)
+	}
+	return endpoints
+}
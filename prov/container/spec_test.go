@@ -0,0 +1,79 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"testing"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/prov/operations"
+)
+
+func TestBuildContainerSpec(t *testing.T) {
+	t.Parallel()
+	envInputs := []*operations.EnvInput{
+		{Name: "LOG_LEVEL", Value: "debug", InstanceName: "Web_0"},
+		{Name: "ID", Value: "0", InstanceName: "Web_0"},
+		{Name: "ID", Value: "1", InstanceName: "Web_1"},
+		{Name: portsInput, Value: `["8080:80/tcp", "127.0.0.1:8443:443"]`, InstanceName: "Web_0"},
+		{Name: volumesInput, Value: "/data:/var/lib/data:ro, /logs:/var/log", InstanceName: "Web_0"},
+		{Name: commandInput, Value: `["nginx","-g","daemon off;"]`, InstanceName: "Web_0"},
+	}
+
+	spec, err := buildContainerSpec("nginx:1.19", "Web", "1", envInputs)
+	require.NoError(t, err)
+	require.Equal(t, "nginx:1.19", spec.image)
+	require.Equal(t, []string{"LOG_LEVEL=debug", "ID=1", "NODE=Web", "INSTANCE=Web_1"}, spec.env)
+	require.Equal(t, []string{"8080:80/tcp", "127.0.0.1:8443:443"}, spec.ports)
+	require.Equal(t, []string{"/data:/var/lib/data:ro", "/logs:/var/log"}, spec.volumes)
+	require.Equal(t, []string{"nginx", "-g", "daemon off;"}, spec.command)
+	require.Equal(t, defaultPodmanSocket, spec.socket)
+
+	cc, hc, err := spec.configs()
+	require.NoError(t, err)
+	require.Contains(t, cc.ExposedPorts, nat.Port("80/tcp"))
+	require.Contains(t, cc.ExposedPorts, nat.Port("443/tcp"))
+	require.Equal(t, []nat.PortBinding{{HostIP: "", HostPort: "8080"}}, hc.PortBindings[nat.Port("80/tcp")])
+	require.Equal(t, []nat.PortBinding{{HostIP: "127.0.0.1", HostPort: "8443"}}, hc.PortBindings[nat.Port("443/tcp")])
+	require.Equal(t, spec.volumes, hc.Binds)
+	require.Equal(t, "nginx", cc.Cmd[0])
+}
+
+func TestBuildContainerSpecErrors(t *testing.T) {
+	t.Parallel()
+	_, err := buildContainerSpec("nginx", "Web", "0", []*operations.EnvInput{{Name: portsInput, Value: `["8080:80"`}})
+	require.Error(t, err)
+
+	spec, err := buildContainerSpec("nginx", "Web", "0", []*operations.EnvInput{{Name: portsInput, Value: "notaport"}, {Name: socketInput, Value: "/run/user/1000/podman/podman.sock"}})
+	require.NoError(t, err)
+	require.Equal(t, "/run/user/1000/podman/podman.sock", spec.socket)
+	_, _, err = spec.configs()
+	require.Error(t, err)
+}
+
+func TestContainerEndpoints(t *testing.T) {
+	t.Parallel()
+	ports := nat.PortMap{
+		"80/tcp":  []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: "32768"}},
+		"443/tcp": []nat.PortBinding{{HostIP: "127.0.0.1", HostPort: "8443"}},
+		"53/udp":  nil,
+	}
+	require.Equal(t, map[string]string{
+		"80/tcp":  "10.0.0.2:32768",
+		"443/tcp": "127.0.0.1:8443",
+	}, containerEndpoints(ports, "10.0.0.2"))
+}
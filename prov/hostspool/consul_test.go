@@ -110,6 +110,9 @@ func TestRunConsulHostsPoolPackageTests(t *testing.T) {
 	t.Run("testConsulManagerAllocateShareableComputeWithSameAllocationPrefix", func(t *testing.T) {
 		testConsulManagerAllocateShareableComputeWithSameAllocationPrefix(t, client, cfg)
 	})
+	t.Run("testConsulManagerRestore", func(t *testing.T) {
+		testConsulManagerRestore(t, client, cfg)
+	})
 	t.Run("testConsulManagerApplyWithAllocation", func(t *testing.T) {
 		testConsulManagerApplyWithAllocation(t, client, cfg)
 	})
@@ -63,6 +63,7 @@ type Manager interface {
 	GetHost(locationName, hostname string) (Host, error)
 	Allocate(locationName string, allocation *Allocation, filters ...labelsutil.Filter) (string, []labelsutil.Warning, error)
 	Release(locationName, hostname, deploymentID, nodeName, instance string) (*Allocation, error)
+	Restore(locationName, hostname string, allocation *Allocation) error
 	ListLocations() ([]string, error)
 	RemoveLocation(locationName string) error
 	CheckPlacementPolicy(placementPolicy string) error
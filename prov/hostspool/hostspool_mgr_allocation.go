@@ -112,6 +112,9 @@ func (cm *consulManager) allocateWait(locationName string, maxWaitTime time.Dura
 
 	// Apply the policy placement
 	hostname := cm.electHostFromCandidates(locationName, allocation, candidates)
+	if err := cm.checkAllocationQuotas(locationName, hostname, allocation); err != nil {
+		return "", warnings, err
+	}
 	select {
 	case <-lockCh:
 		return "", warnings, errors.New("admin lock lost on hosts pool during host allocation")
@@ -162,6 +165,54 @@ func binPacking(candidates []hostCandidate) string {
 	return hostname
 }
 
+func (cm *consulManager) Restore(locationName, hostname string, allocation *Allocation) error {
+	err := cm.restoreWait(locationName, hostname, maxWaitTimeSeconds*time.Second, allocation)
+	if err != nil {
+		return err
+	}
+	return cm.UpdateResourcesLabels(locationName, hostname, allocation.Resources, subtract, updateResourcesLabels, allocation.GenericResources, removeElements, updateGenericResourcesLabels)
+}
+
+func (cm *consulManager) restoreWait(locationName, hostname string, maxWaitTime time.Duration, allocation *Allocation) error {
+	if err := allocation.buildID(); err != nil {
+		return err
+	}
+
+	_, cleanupFn, err := cm.lockKey(locationName, "", "allocation", maxWaitTime)
+	if err != nil {
+		return err
+	}
+	defer cleanupFn()
+
+	hs, err := cm.GetHostStatus(locationName, hostname)
+	if err != nil {
+		return err
+	}
+	switch hs {
+	case HostStatusFree:
+	case HostStatusAllocated:
+		allocations, err := cm.getAllocations(locationName, hostname)
+		if err != nil {
+			return err
+		}
+		for _, alloc := range allocations {
+			if alloc.ID == allocation.ID {
+				return errors.WithStack(badRequestError{fmt.Sprintf("allocation %q already exists on host %q", allocation.ID, hostname)})
+			}
+			if !alloc.Shareable || !allocation.Shareable {
+				return errors.WithStack(badRequestError{fmt.Sprintf("host %q is already allocated and can't be shared", hostname)})
+			}
+		}
+	default:
+		return errors.WithStack(badRequestError{fmt.Sprintf("can't restore an allocation on host %q with status %q", hostname, hs.String())})
+	}
+
+	if err = cm.storeAllocation(locationName, hostname, allocation); err != nil {
+		return errors.Wrapf(err, "failed to restore allocation for hostname:%q", hostname)
+	}
+	return cm.setHostStatus(locationName, hostname, HostStatusAllocated)
+}
+
 func (cm *consulManager) Release(locationName, hostname, deploymentID, nodeName, instance string) (*Allocation, error) {
 	return cm.releaseWait(locationName, hostname, deploymentID, nodeName, instance, maxWaitTimeSeconds*time.Second)
 }
@@ -246,16 +297,17 @@ func getAddAllocationsOperation(locationName, hostname string, allocations []All
 }
 
 func (cm *consulManager) addAllocation(locationName, hostname string, allocation *Allocation) error {
-	var allocOps api.KVTxnOps
-	var err error
-
 	if allocation.GenericResources != nil {
-		if err = cm.allocateGenericResources(locationName, hostname, allocation); err != nil {
+		if err := cm.allocateGenericResources(locationName, hostname, allocation); err != nil {
 			return err
 		}
 	}
+	return cm.storeAllocation(locationName, hostname, allocation)
+}
 
-	if allocOps, err = getAddAllocationsOperation(locationName, hostname, []Allocation{*allocation}); err != nil {
+func (cm *consulManager) storeAllocation(locationName, hostname string, allocation *Allocation) error {
+	allocOps, err := getAddAllocationsOperation(locationName, hostname, []Allocation{*allocation})
+	if err != nil {
 		return errors.Wrapf(err, "failed to add allocation to host:%q, location: %q", hostname, locationName)
 	}
 
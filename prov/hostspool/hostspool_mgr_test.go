@@ -1203,6 +1203,50 @@ func testConsulManagerAllocateShareableCompute(t *testing.T, cc *api.Client, cfg
 	require.Equal(t, HostStatusFree, allocatedHost.Status)
 }
 
+func testConsulManagerRestore(t *testing.T, cc *api.Client, cfg config.Configuration) {
+	location := "myLocation1"
+	cleanupHostsPool(t, cc)
+	cm := &consulManager{cc, cfg, mockSSHClientFactory}
+
+	var hostpool = createHosts(2)
+	var checkpoint uint64
+	err := cm.Apply(location, hostpool, &checkpoint)
+	require.NoError(t, err, "Unexpected failure applying host pool configuration")
+
+	alloc1 := &Allocation{NodeName: "node_test1", Instance: "0", DeploymentID: "test1", Shareable: true}
+	err = cm.Restore(location, hostpool[0].Name, alloc1)
+	require.NoError(t, err, "Unexpected error restoring allocation")
+	host, err := cm.GetHost(location, hostpool[0].Name)
+	require.NoError(t, err)
+	require.Equal(t, HostStatusAllocated, host.Status)
+	require.Len(t, host.Allocations, 1)
+	require.Equal(t, "test1", host.Allocations[0].DeploymentID)
+
+	// Same allocation can't be restored twice
+	err = cm.Restore(location, hostpool[0].Name, &Allocation{NodeName: "node_test1", Instance: "0", DeploymentID: "test1", Shareable: true})
+	require.Error(t, err)
+
+	// Shareable allocations could be restored on the same host
+	err = cm.Restore(location, hostpool[0].Name, &Allocation{NodeName: "node_test2", Instance: "0", DeploymentID: "test2", Shareable: true})
+	require.NoError(t, err)
+
+	// But not an exclusive one
+	err = cm.Restore(location, hostpool[0].Name, &Allocation{NodeName: "node_test3", Instance: "0", DeploymentID: "test3"})
+	require.Error(t, err)
+
+	// Unknown host
+	err = cm.Restore(location, "unknown", &Allocation{NodeName: "node_test3", Instance: "0", DeploymentID: "test3"})
+	require.Error(t, err)
+
+	_, err = cm.Release(location, hostpool[0].Name, "test1", "node_test1", "0")
+	require.NoError(t, err)
+	_, err = cm.Release(location, hostpool[0].Name, "test2", "node_test2", "0")
+	require.NoError(t, err)
+	host, err = cm.GetHost(location, hostpool[0].Name)
+	require.NoError(t, err)
+	require.Equal(t, HostStatusFree, host.Status)
+}
+
 func testConsulManagerAllocateWithWeightBalancedPlacement(t *testing.T, cc *api.Client, cfg config.Configuration) {
 	location := "myLocation1"
 	cleanupHostsPool(t, cc)
@@ -0,0 +1,177 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostspool
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/prov"
+)
+
+// infraUsageGroupByParam is the infrastructure usage query parameter giving the comma-separated names of labels
+// used to group hosts
+const infraUsageGroupByParam = "group_by"
+
+type infraUsageCollector struct {
+}
+
+// GetUsageInfo returns the capacity of the hosts of a hosts pool location and their allocated resources
+func (c *infraUsageCollector) GetUsageInfo(ctx context.Context, cfg config.Configuration, taskID, infraName, locationName string,
+	params map[string]string) (map[string]interface{}, error) {
+	cc, err := cfg.GetConsulClient()
+	if err != nil {
+		return nil, err
+	}
+	var groupBy []string
+	if params[infraUsageGroupByParam] != "" {
+		groupBy = toSlice(params[infraUsageGroupByParam])
+	}
+	usage, err := collectInfraUsage(NewManager(cc, cfg), locationName, groupBy)
+	if err != nil {
+		return nil, err
+	}
+	return usage.ToMap()
+}
+
+func collectInfraUsage(hpManager Manager, locationName string, groupBy []string) (*prov.InfraUsage, error) {
+	hostnames, _, _, err := hpManager.List(locationName)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(hostnames)
+
+	var total prov.ResourceUsagesBuilder
+	groups := make(map[string]*prov.ResourceUsagesBuilder)
+	var groupsNames []string
+	for _, hostname := range hostnames {
+		host, err := hpManager.GetHost(locationName, hostname)
+		if err != nil {
+			return nil, err
+		}
+		usages, err := hostResourcesUsages(host)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compute resources usage of host %q", hostname)
+		}
+		for _, u := range usages {
+			total.Add(u)
+		}
+		for _, label := range groupBy {
+			value, ok := host.Labels[label]
+			if !ok {
+				continue
+			}
+			groupName := label + "=" + value
+			b, ok := groups[groupName]
+			if !ok {
+				b = new(prov.ResourceUsagesBuilder)
+				groups[groupName] = b
+				groupsNames = append(groupsNames, groupName)
+			}
+			for _, u := range usages {
+				b.Add(u)
+			}
+		}
+	}
+
+	usage := &prov.InfraUsage{
+		Infrastructure: infrastructureType,
+		Location:       locationName,
+		CollectDate:    time.Now(),
+		Resources:      total.Resources(),
+	}
+	sort.Strings(groupsNames)
+	for _, groupName := range groupsNames {
+		usage.Groups = append(usage.Groups, prov.InfraUsageGroup{Kind: "label", Name: groupName, Resources: groups[groupName].Resources()})
+	}
+	return usage, nil
+}
+
+// hostResourcesUsages returns the capacity of a host and its allocated resources
+//
+// Resources labels of a host are decreased on allocations so the capacity of a resource is the sum
+// of its label value and of its allocations.
+func hostResourcesUsages(host Host) ([]prov.ResourceUsage, error) {
+	var usedHosts float64
+	if len(host.Allocations) > 0 {
+		usedHosts = 1
+	}
+	usages := []prov.ResourceUsage{prov.NewResourceUsage(prov.ResourceHosts, "", 1, usedHosts)}
+
+	resources := []struct {
+		name  string
+		label string
+		unit  string
+		parse func(string) (float64, error)
+	}{
+		{prov.ResourceCPUs, "host.num_cpus", "", parseNumber},
+		{prov.ResourceMemory, "host.mem_size", prov.UnitBytes, parseBytes},
+		{prov.ResourceDisk, "host.disk_size", prov.UnitBytes, parseBytes},
+	}
+	for _, r := range resources {
+		available, ok := host.Labels[r.label]
+		if !ok {
+			continue
+		}
+		capacity, err := r.parse(available)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value for label %q", r.label)
+		}
+		var used float64
+		for _, alloc := range host.Allocations {
+			if v, ok := alloc.Resources[r.label]; ok {
+				allocated, err := r.parse(v)
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid value for label %q of allocation %q", r.label, alloc.ID)
+				}
+				used += allocated
+			}
+		}
+		usages = append(usages, prov.NewResourceUsage(r.name, r.unit, capacity+used, used))
+	}
+
+	for label, value := range host.Labels {
+		if !strings.HasPrefix(label, genericResourceLabelPrefix+".") {
+			continue
+		}
+		name := strings.TrimPrefix(label, genericResourceLabelPrefix+".")
+		var used float64
+		for _, alloc := range host.Allocations {
+			for _, gres := range alloc.GenericResources {
+				if gres.Name == name && !gres.NoConsumable {
+					used += float64(len(toSlice(gres.Value)))
+				}
+			}
+		}
+		usages = append(usages, prov.NewResourceUsage(name, "", float64(len(toSlice(value)))+used, used))
+	}
+	return usages, nil
+}
+
+func parseNumber(s string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSpace(s), 64)
+}
+
+func parseBytes(s string) (float64, error) {
+	b, err := humanize.ParseBytes(s)
+	return float64(b), err
+}
@@ -0,0 +1,119 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostspool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/helper/labelsutil"
+	"github.com/ystia/yorc/v4/prov"
+)
+
+type infraUsageManagerMock struct {
+	Manager
+	hosts map[string]Host
+}
+
+func (m *infraUsageManagerMock) List(locationName string, filters ...labelsutil.Filter) ([]string, []labelsutil.Warning, uint64, error) {
+	var names []string
+	for name := range m.hosts {
+		names = append(names, name)
+	}
+	return names, nil, 0, nil
+}
+
+func (m *infraUsageManagerMock) GetHost(locationName, hostname string) (Host, error) {
+	return m.hosts[hostname], nil
+}
+
+func findResourceUsage(t *testing.T, resources []prov.ResourceUsage, name string) prov.ResourceUsage {
+	t.Helper()
+	for _, r := range resources {
+		if r.Name == name {
+			return r
+		}
+	}
+	require.FailNowf(t, "missing resource", "resource %q not found in %+v", name, resources)
+	return prov.ResourceUsage{}
+}
+
+func TestCollectInfraUsage(t *testing.T) {
+	m := &infraUsageManagerMock{hosts: map[string]Host{
+		"host1": {
+			Name: "host1",
+			Labels: map[string]string{
+				"host.num_cpus":      "2",
+				"host.mem_size":      "2 GB",
+				"host.resource.gpu":  "gpu2",
+				"os.distribution":    "ubuntu",
+				"host.resource.fpga": "",
+			},
+			Allocations: []Allocation{
+				{ID: "a1", Resources: map[string]string{"host.num_cpus": "2", "host.mem_size": "1 GB"},
+					GenericResources: []*GenericResource{{Name: "gpu", Value: "gpu0,gpu1"}}},
+			},
+		},
+		"host2": {
+			Name: "host2",
+			Labels: map[string]string{
+				"host.num_cpus":   "8",
+				"host.mem_size":   "4 GB",
+				"os.distribution": "centos",
+			},
+		},
+		"host3": {
+			Name:   "host3",
+			Labels: map[string]string{"host.num_cpus": "4"},
+		},
+	}}
+
+	usage, err := collectInfraUsage(m, "myLocation", []string{"os.distribution"})
+	require.NoError(t, err)
+	require.Equal(t, "hostspool", usage.Infrastructure)
+	require.Equal(t, "myLocation", usage.Location)
+
+	tests := []struct {
+		resource     string
+		wantCapacity float64
+		wantUsed     float64
+	}{
+		{prov.ResourceHosts, 3, 1},
+		{prov.ResourceCPUs, 16, 2},
+		{prov.ResourceMemory, 7e9, 1e9},
+		{"gpu", 3, 2},
+		{"fpga", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.resource, func(t *testing.T) {
+			r := findResourceUsage(t, usage.Resources, tt.resource)
+			require.NotNil(t, r.Capacity)
+			require.Equal(t, tt.wantCapacity, *r.Capacity)
+			require.Equal(t, tt.wantUsed, r.Used)
+		})
+	}
+
+	require.Len(t, usage.Groups, 2)
+	require.Equal(t, "os.distribution=centos", usage.Groups[0].Name)
+	require.Equal(t, "os.distribution=ubuntu", usage.Groups[1].Name)
+	r := findResourceUsage(t, usage.Groups[1].Resources, prov.ResourceCPUs)
+	require.Equal(t, float64(4), *r.Capacity)
+	require.Equal(t, float64(2), r.Used)
+
+	m.hosts["host3"].Labels["host.num_cpus"] = "four"
+	_, err = collectInfraUsage(m, "myLocation", nil)
+	require.Error(t, err)
+}
@@ -19,4 +19,5 @@ import "github.com/ystia/yorc/v4/registry"
 func init() {
 	reg := registry.GetRegistry()
 	reg.RegisterDelegates([]string{`yorc\.nodes\.hostspool\..*`}, &defaultExecutor{}, registry.BuiltinOrigin)
+	reg.RegisterInfraUsageCollector(infrastructureType, &infraUsageCollector{}, registry.BuiltinOrigin)
 }
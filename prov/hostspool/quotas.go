@@ -0,0 +1,166 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostspool
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/quotas"
+)
+
+// QuotaUsage returns the hosts pools resources allocated to the deployments a quota applies to
+func QuotaUsage(ctx context.Context, hpManager Manager, q *quotas.Quota) (quotas.Amounts, error) {
+	used := quotas.Amounts{quotas.ResourceHostsPoolCPUs: 0, quotas.ResourceHostsPoolMemory: 0}
+	for name := range q.Limits.HostsPoolGenericResources {
+		used[quotas.ResourceHostsPoolGenericResourcePrefix+name] = 0
+	}
+	locations, err := hpManager.ListLocations()
+	if err != nil {
+		return nil, err
+	}
+	matchingDeployments := make(map[string]bool)
+	for _, locationName := range locations {
+		if !q.AppliesToLocation(locationName) {
+			continue
+		}
+		hostnames, _, _, err := hpManager.List(locationName)
+		if err != nil {
+			return nil, err
+		}
+		for _, hostname := range hostnames {
+			host, err := hpManager.GetHost(locationName, hostname)
+			if err != nil {
+				return nil, err
+			}
+			for i := range host.Allocations {
+				alloc := &host.Allocations[i]
+				match, ok := matchingDeployments[alloc.DeploymentID]
+				if !ok {
+					match, err = quotas.MatchesDeployment(ctx, q, alloc.DeploymentID)
+					if err != nil {
+						return nil, err
+					}
+					matchingDeployments[alloc.DeploymentID] = match
+				}
+				if !match {
+					continue
+				}
+				amounts, err := allocationAmounts(alloc, nil)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to compute resources of allocation %q on host %q", alloc.ID, hostname)
+				}
+				used.Add(amounts)
+			}
+		}
+	}
+	return used, nil
+}
+
+// checkAllocationQuotas checks that the quotas applying to the deployment of an allocation on a hosts pool location
+// allow to allocate its resources on the given host
+func (cm *consulManager) checkAllocationQuotas(locationName, hostname string, allocation *Allocation) error {
+	ctx := context.Background()
+	quotasList, err := quotas.GetQuotas(ctx)
+	if err != nil {
+		return err
+	}
+	var requested quotas.Amounts
+	for _, q := range quotasList {
+		if !q.LimitsHostsPoolResources() || !q.AppliesToLocation(locationName) {
+			continue
+		}
+		match, err := quotas.MatchesDeployment(ctx, q, allocation.DeploymentID)
+		if err != nil {
+			return err
+		}
+		if !match {
+			continue
+		}
+		if requested == nil {
+			hostLabels, err := cm.GetHostLabels(locationName, hostname)
+			if err != nil {
+				return err
+			}
+			requested, err = allocationAmounts(allocation, hostLabels)
+			if err != nil {
+				return err
+			}
+		}
+		used, err := QuotaUsage(ctx, cm, q)
+		if err != nil {
+			return err
+		}
+		if err = q.Check(used, requested); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// allocationAmounts returns the amounts of hosts pool resources of an allocation
+//
+// Host labels are used to know if generic resources of an allocation not yet stored are consumable,
+// they are not needed for stored allocations.
+func allocationAmounts(alloc *Allocation, hostLabels map[string]string) (quotas.Amounts, error) {
+	amounts := make(quotas.Amounts)
+	if v, ok := alloc.Resources["host.num_cpus"]; ok {
+		cpus, err := parseNumber(v)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid value for resource \"host.num_cpus\"")
+		}
+		amounts[quotas.ResourceHostsPoolCPUs] = cpus
+	}
+	if v, ok := alloc.Resources["host.mem_size"]; ok {
+		mem, err := parseBytes(v)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid value for resource \"host.mem_size\"")
+		}
+		amounts[quotas.ResourceHostsPoolMemory] = mem
+	}
+	for _, gres := range alloc.GenericResources {
+		noConsumable := gres.NoConsumable
+		if v, ok := hostLabels[gres.Label+"."+genericResourceNoConsumeProperty]; ok && v != "" {
+			var err error
+			noConsumable, err = strconv.ParseBool(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "expected boolean value for no_consumable label of generic resource with name:%q", gres.Name)
+			}
+		}
+		if noConsumable {
+			continue
+		}
+		amounts[quotas.ResourceHostsPoolGenericResourcePrefix+gres.Name] += float64(gres.count(alloc.Instance))
+	}
+	return amounts, nil
+}
+
+// count returns the number of generic resources allocated or to be allocated for a node instance
+func (gr *GenericResource) count(instance string) int {
+	if len(gr.ids) > 0 {
+		ids := gr.ids[0]
+		if i, err := strconv.Atoi(instance); err == nil && len(gr.ids) > i {
+			ids = gr.ids[i]
+		}
+		return len(ids)
+	}
+	if gr.nb > 0 {
+		return gr.nb
+	}
+	// Stored allocations only keep allocated values
+	return len(toSlice(gr.Value))
+}
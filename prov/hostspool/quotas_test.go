@@ -0,0 +1,64 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostspool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/quotas"
+)
+
+func TestAllocationAmounts(t *testing.T) {
+	tests := []struct {
+		name       string
+		alloc      Allocation
+		hostLabels map[string]string
+		want       quotas.Amounts
+		wantErr    bool
+	}{
+		{"NoResources", Allocation{}, nil, quotas.Amounts{}, false},
+		{"CPUsAndMemory", Allocation{Resources: map[string]string{"host.num_cpus": "4", "host.mem_size": "2 GB", "host.disk_size": "50 GB"}}, nil,
+			quotas.Amounts{quotas.ResourceHostsPoolCPUs: 4, quotas.ResourceHostsPoolMemory: 2e9}, false},
+		{"StoredGenericResources", Allocation{GenericResources: []*GenericResource{
+			{Name: "gpu", Label: "host.resource.gpu", Value: "gpu0,gpu1"},
+			{Name: "license", Label: "host.resource.license", Value: "l1", NoConsumable: true},
+		}}, nil, quotas.Amounts{quotas.ResourceHostsPoolGenericResourcePrefix + "gpu": 2}, false},
+		{"RequestedGenericResourcesByNumber", Allocation{Instance: "0", GenericResources: []*GenericResource{
+			{Name: "gpu", Label: "host.resource.gpu", nb: 3},
+		}}, nil, quotas.Amounts{quotas.ResourceHostsPoolGenericResourcePrefix + "gpu": 3}, false},
+		{"RequestedGenericResourcesByIDs", Allocation{Instance: "1", GenericResources: []*GenericResource{
+			{Name: "gpu", Label: "host.resource.gpu", ids: [][]string{{"gpu0"}, {"gpu1", "gpu2"}}},
+		}}, nil, quotas.Amounts{quotas.ResourceHostsPoolGenericResourcePrefix + "gpu": 2}, false},
+		{"RequestedNoConsumableGenericResources", Allocation{Instance: "0", GenericResources: []*GenericResource{
+			{Name: "gpu", Label: "host.resource.gpu", nb: 1},
+		}}, map[string]string{"host.resource.gpu.no_consume": "true"}, quotas.Amounts{}, false},
+		{"InvalidCPUs", Allocation{Resources: map[string]string{"host.num_cpus": "four"}}, nil, nil, true},
+		{"InvalidNoConsume", Allocation{GenericResources: []*GenericResource{{Name: "gpu", Label: "host.resource.gpu", nb: 1}}},
+			map[string]string{"host.resource.gpu.no_consume": "maybe"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := allocationAmounts(&tt.alloc, tt.hostLabels)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prov
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Well known resources names of infrastructure usages
+const (
+	ResourceCPUs    = "cpus"
+	ResourceMemory  = "memory"
+	ResourceDisk    = "disk"
+	ResourceHosts   = "hosts"
+	ResourcePods    = "pods"
+	ResourceCPUTime = "cpu_time"
+	ResourceJobs    = "jobs"
+)
+
+// Units of resources of infrastructure usages
+const (
+	UnitBytes      = "bytes"
+	UnitCores      = "cores"
+	UnitCPUSeconds = "cpu_seconds"
+	UnitRatio      = "ratio"
+)
+
+// InfraUsage is the normalized infrastructure usage returned by builtin infrastructure usage collectors
+//
+// Resources contains the usage of the whole location while Groups details the usage of subsets of the location
+// like hosts having a given label, Slurm accounts or Kubernetes namespaces.
+type InfraUsage struct {
+	Infrastructure string            `json:"infrastructure"`
+	Location       string            `json:"location"`
+	CollectDate    time.Time         `json:"collect_date"`
+	Resources      []ResourceUsage   `json:"resources"`
+	Groups         []InfraUsageGroup `json:"groups,omitempty"`
+}
+
+// InfraUsageGroup is the usage of a subset of a location
+//
+// Kind is the kind of grouping (label, account, namespace, ...) and Name identifies the group for this kind.
+// Properties may contain additional non numeric information on the group.
+type InfraUsageGroup struct {
+	Kind       string            `json:"kind"`
+	Name       string            `json:"name"`
+	Resources  []ResourceUsage   `json:"resources"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// ResourceUsage is the usage of a resource
+//
+// Capacity is nil when the resource is not limited or if its capacity is unknown.
+type ResourceUsage struct {
+	Name     string   `json:"name"`
+	Unit     string   `json:"unit,omitempty"`
+	Capacity *float64 `json:"capacity,omitempty"`
+	Used     float64  `json:"used"`
+}
+
+// NewResourceUsage returns the usage of a resource with a known capacity
+func NewResourceUsage(name, unit string, capacity, used float64) ResourceUsage {
+	return ResourceUsage{Name: name, Unit: unit, Capacity: &capacity, Used: used}
+}
+
+// ToMap converts an infrastructure usage into the generic format returned by InfraUsageCollector
+func (u *InfraUsage) ToMap() (map[string]interface{}, error) {
+	b, err := json.Marshal(u)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal infrastructure usage")
+	}
+	res := make(map[string]interface{})
+	err = json.Unmarshal(b, &res)
+	return res, errors.Wrap(err, "failed to convert infrastructure usage")
+}
+
+// ResourceUsagesBuilder aggregates usages of resources by name
+//
+// The zero value is ready to use.
+type ResourceUsagesBuilder struct {
+	usages map[string]*ResourceUsage
+}
+
+// Add adds the usage and capacity of a resource to the ones already added for this resource
+func (b *ResourceUsagesBuilder) Add(r ResourceUsage) {
+	if b.usages == nil {
+		b.usages = make(map[string]*ResourceUsage)
+	}
+	u, ok := b.usages[r.Name]
+	if !ok {
+		u = &ResourceUsage{Name: r.Name, Unit: r.Unit}
+		b.usages[r.Name] = u
+	}
+	u.Used += r.Used
+	if r.Capacity != nil {
+		capacity := *r.Capacity
+		if u.Capacity != nil {
+			capacity += *u.Capacity
+		}
+		u.Capacity = &capacity
+	}
+}
+
+// Resources returns the aggregated usages sorted by resource name
+func (b *ResourceUsagesBuilder) Resources() []ResourceUsage {
+	res := make([]ResourceUsage, 0, len(b.usages))
+	for _, u := range b.usages {
+		res = append(res, *u)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
@@ -209,7 +209,7 @@ func (e *execution) manageKubernetesResource(ctx context.Context, clientset kube
 				set attributes			OK
 		*/
 		if !namespaceProvided {
-			err = createNamespaceIfMissing(namespaceName, clientset)
+			err = createNamespaceIfMissing(namespaceName, getNamespaceLabels(ctx, e.deploymentID), clientset)
 			if err != nil {
 				return err
 			}
@@ -70,7 +70,7 @@ func (e *execution) submitJob(ctx context.Context, clientset kubernetes.Interfac
 	}
 
 	if !job.namespaceProvided {
-		err = createNamespaceIfMissing(job.namespace, clientset)
+		err = createNamespaceIfMissing(job.namespace, getNamespaceLabels(ctx, e.deploymentID), clientset)
 		if err != nil {
 			return err
 		}
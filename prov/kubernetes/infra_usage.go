@@ -0,0 +1,223 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/locations"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov"
+)
+
+// infraUsageNamespacesParam is the infrastructure usage query parameter giving the comma-separated list
+// of namespaces to report, all namespaces are reported by default
+const infraUsageNamespacesParam = "namespaces"
+
+type infraUsageCollector struct {
+}
+
+// GetUsageInfo returns the resources requested by pods of a Kubernetes cluster per namespace
+// compared to the namespaces resource quotas and to the nodes allocatable resources
+func (c *infraUsageCollector) GetUsageInfo(ctx context.Context, cfg config.Configuration, taskID, infraName, locationName string,
+	params map[string]string) (map[string]interface{}, error) {
+	locationMgr, err := locations.GetManager(cfg)
+	if err != nil {
+		return nil, err
+	}
+	locationProps, err := locationMgr.GetLocationProperties(locationName, infrastructureType)
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := getClientSet(locationProps)
+	if err != nil {
+		return nil, err
+	}
+	var namespaces []string
+	for _, ns := range strings.Split(params[infraUsageNamespacesParam], ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	usage, err := collectInfraUsage(clientset, locationName, namespaces)
+	if err != nil {
+		return nil, err
+	}
+	return usage.ToMap()
+}
+
+func collectInfraUsage(clientset kubernetes.Interface, locationName string, namespaces []string) (*prov.InfraUsage, error) {
+	if len(namespaces) == 0 {
+		nsList, err := clientset.CoreV1().Namespaces().List(metav1.ListOptions{})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list Kubernetes namespaces")
+		}
+		for _, ns := range nsList.Items {
+			namespaces = append(namespaces, ns.Name)
+		}
+	}
+	sort.Strings(namespaces)
+
+	usage := &prov.InfraUsage{
+		Infrastructure: infrastructureType,
+		Location:       locationName,
+		CollectDate:    time.Now(),
+	}
+	var total prov.ResourceUsagesBuilder
+	// Nodes may not be readable by the configured user, in this case the cluster capacity is unknown
+	nodes, err := clientset.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		log.Debugf("Kubernetes cluster capacity of location %q will not be reported as nodes can't be listed: %v", locationName, err)
+	} else {
+		for _, u := range nodesCapacity(nodes.Items) {
+			total.Add(u)
+		}
+	}
+
+	for _, ns := range namespaces {
+		quotas, err := clientset.CoreV1().ResourceQuotas(ns).List(metav1.ListOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list resource quotas of Kubernetes namespace %q", ns)
+		}
+		pods, err := clientset.CoreV1().Pods(ns).List(metav1.ListOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list pods of Kubernetes namespace %q", ns)
+		}
+		requests := podsRequests(pods.Items)
+		for _, u := range requests {
+			total.Add(prov.ResourceUsage{Name: u.Name, Unit: u.Unit, Used: u.Used})
+		}
+		usage.Groups = append(usage.Groups, namespaceUsage(ns, quotas.Items, requests))
+	}
+	usage.Resources = total.Resources()
+	return usage, nil
+}
+
+// k8sResourceUsage returns the normalized name, unit and value of a Kubernetes resource quantity
+func k8sResourceUsage(name corev1.ResourceName, q resource.Quantity) prov.ResourceUsage {
+	switch name {
+	case corev1.ResourceCPU, corev1.ResourceRequestsCPU:
+		return prov.ResourceUsage{Name: prov.ResourceCPUs, Unit: prov.UnitCores, Used: quantityValue(q)}
+	case corev1.ResourceMemory, corev1.ResourceRequestsMemory:
+		return prov.ResourceUsage{Name: prov.ResourceMemory, Unit: prov.UnitBytes, Used: quantityValue(q)}
+	case corev1.ResourcePods:
+		return prov.ResourceUsage{Name: prov.ResourcePods, Used: quantityValue(q)}
+	case corev1.ResourceLimitsCPU:
+		return prov.ResourceUsage{Name: string(name), Unit: prov.UnitCores, Used: quantityValue(q)}
+	case corev1.ResourceLimitsMemory, corev1.ResourceStorage, corev1.ResourceRequestsStorage, corev1.ResourceEphemeralStorage,
+		corev1.ResourceRequestsEphemeralStorage, corev1.ResourceLimitsEphemeralStorage:
+		return prov.ResourceUsage{Name: string(name), Unit: prov.UnitBytes, Used: quantityValue(q)}
+	}
+	return prov.ResourceUsage{Name: string(name), Used: quantityValue(q)}
+}
+
+// quantityValue returns the value of a Kubernetes resource quantity as a float
+//
+// Milli-units are used to keep fractional values like CPUs requests, unless it overflows for large quantities.
+func quantityValue(q resource.Quantity) float64 {
+	if v := q.Value(); v < math.MaxInt64/1000 && v > math.MinInt64/1000 {
+		return float64(q.MilliValue()) / 1000
+	}
+	return float64(q.Value())
+}
+
+// nodesCapacity returns the allocatable CPUs, memory and pods of nodes
+func nodesCapacity(nodes []corev1.Node) []prov.ResourceUsage {
+	var b prov.ResourceUsagesBuilder
+	for _, node := range nodes {
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourcePods} {
+			if q, ok := node.Status.Allocatable[name]; ok {
+				u := k8sResourceUsage(name, q)
+				b.Add(prov.NewResourceUsage(u.Name, u.Unit, u.Used, 0))
+			}
+		}
+	}
+	return b.Resources()
+}
+
+// podsRequests returns the CPUs and memory requested by running or pending pods and their number
+func podsRequests(pods []corev1.Pod) []prov.ResourceUsage {
+	var b prov.ResourceUsagesBuilder
+	b.Add(prov.ResourceUsage{Name: prov.ResourcePods})
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		b.Add(prov.ResourceUsage{Name: prov.ResourcePods, Used: 1})
+		for _, container := range pod.Spec.Containers {
+			for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+				if q, ok := container.Resources.Requests[name]; ok {
+					b.Add(k8sResourceUsage(name, q))
+				}
+			}
+		}
+	}
+	return b.Resources()
+}
+
+// namespaceUsage returns the usage of a namespace
+//
+// Resources limited by a quota are reported with the quota hard limit as capacity and the quota used value,
+// when several quotas limit a same resource the most restrictive is reported.
+// Other resources are reported as requested by pods of the namespace.
+func namespaceUsage(namespace string, quotas []corev1.ResourceQuota, requests []prov.ResourceUsage) prov.InfraUsageGroup {
+	usages := make(map[string]prov.ResourceUsage)
+	for _, r := range requests {
+		usages[r.Name] = r
+	}
+	quotasNames := make([]string, 0, len(quotas))
+	limited := make(map[string]bool)
+	for _, quota := range quotas {
+		quotasNames = append(quotasNames, quota.Name)
+		for name, hard := range quota.Status.Hard {
+			u := k8sResourceUsage(name, hard)
+			if limited[u.Name] && *usages[u.Name].Capacity <= u.Used {
+				continue
+			}
+			limited[u.Name] = true
+			var used float64
+			if q, ok := quota.Status.Used[name]; ok {
+				used = quantityValue(q)
+			}
+			usages[u.Name] = prov.NewResourceUsage(u.Name, u.Unit, u.Used, used)
+		}
+	}
+
+	group := prov.InfraUsageGroup{Kind: "namespace", Name: namespace}
+	names := make([]string, 0, len(usages))
+	for name := range usages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		group.Resources = append(group.Resources, usages[name])
+	}
+	if len(quotasNames) > 0 {
+		sort.Strings(quotasNames)
+		group.Properties = map[string]string{"quotas": strings.Join(quotasNames, ",")}
+	}
+	return group
+}
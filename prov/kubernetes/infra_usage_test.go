@@ -0,0 +1,109 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/ystia/yorc/v4/prov"
+)
+
+func newTestPod(namespace, name string, phase corev1.PodPhase, cpu, memory string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "c",
+			Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			}},
+		}}},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func newTestQuota(namespace, name string, hard, used corev1.ResourceList) *corev1.ResourceQuota {
+	return &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Status:     corev1.ResourceQuotaStatus{Hard: hard, Used: used},
+	}
+}
+
+func TestCollectInfraUsage(t *testing.T) {
+	objects := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns2"}},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			}},
+		},
+		newTestPod("ns1", "p1", corev1.PodRunning, "500m", "1Gi"),
+		newTestPod("ns1", "p2", corev1.PodPending, "1", "1Gi"),
+		newTestPod("ns1", "p3", corev1.PodSucceeded, "2", "1Gi"),
+		newTestPod("ns2", "p4", corev1.PodRunning, "250m", "512Mi"),
+		newTestQuota("ns2", "q1",
+			corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("2"), corev1.ResourcePods: resource.MustParse("10")},
+			corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("250m"), corev1.ResourcePods: resource.MustParse("1")}),
+		newTestQuota("ns2", "q2",
+			corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1")},
+			corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("250m")}),
+	}
+
+	usage, err := collectInfraUsage(fake.NewSimpleClientset(objects...), "myK8s", nil)
+	require.NoError(t, err)
+	require.Equal(t, "kubernetes", usage.Infrastructure)
+	require.Equal(t, []prov.ResourceUsage{
+		prov.NewResourceUsage(prov.ResourceCPUs, prov.UnitCores, 4, 1.75),
+		prov.NewResourceUsage(prov.ResourceMemory, prov.UnitBytes, 8*1024*1024*1024, 2.5*1024*1024*1024),
+		prov.NewResourceUsage(prov.ResourcePods, "", 110, 3),
+	}, usage.Resources)
+
+	require.Len(t, usage.Groups, 2)
+	require.Equal(t, prov.InfraUsageGroup{
+		Kind: "namespace",
+		Name: "ns1",
+		Resources: []prov.ResourceUsage{
+			{Name: prov.ResourceCPUs, Unit: prov.UnitCores, Used: 1.5},
+			{Name: prov.ResourceMemory, Unit: prov.UnitBytes, Used: 2 * 1024 * 1024 * 1024},
+			{Name: prov.ResourcePods, Used: 2},
+		},
+	}, usage.Groups[0])
+	require.Equal(t, prov.InfraUsageGroup{
+		Kind: "namespace",
+		Name: "ns2",
+		Resources: []prov.ResourceUsage{
+			prov.NewResourceUsage(prov.ResourceCPUs, prov.UnitCores, 1, 0.25),
+			{Name: prov.ResourceMemory, Unit: prov.UnitBytes, Used: 512 * 1024 * 1024},
+			prov.NewResourceUsage(prov.ResourcePods, "", 10, 1),
+		},
+		Properties: map[string]string{"quotas": "q1,q2"},
+	}, usage.Groups[1])
+
+	usage, err = collectInfraUsage(fake.NewSimpleClientset(objects...), "myK8s", []string{"ns2"})
+	require.NoError(t, err)
+	require.Len(t, usage.Groups, 1)
+	require.Equal(t, "ns2", usage.Groups[0].Name)
+}
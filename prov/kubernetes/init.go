@@ -31,4 +31,5 @@ func init() {
 		}, &defaultExecutor{}, registry.BuiltinOrigin)
 
 	reg.RegisterActionOperator([]string{"k8s-job-monitoring"}, &actionOperator{}, registry.BuiltinOrigin)
+	reg.RegisterInfraUsageCollector(infrastructureType, &infraUsageCollector{}, registry.BuiltinOrigin)
 }
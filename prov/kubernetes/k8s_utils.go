@@ -26,12 +26,14 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/ystia/yorc/v4/deployments"
 	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/log"
 )
 
 func isDeploymentFailed(deployment *v1.Deployment) (bool, string) {
@@ -158,12 +160,12 @@ func referenceFromOwnerReference(namespace string, ref metav1.OwnerReference) re
 }
 
 // CreateNamespaceIfMissing create a kubernetes namespace (only if missing)
-func createNamespaceIfMissing(namespaceName string, clientset kubernetes.Interface) error {
+func createNamespaceIfMissing(namespaceName string, labels map[string]string, clientset kubernetes.Interface) error {
 	_, err := clientset.CoreV1().Namespaces().Get(namespaceName, metav1.GetOptions{})
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			_, err := clientset.CoreV1().Namespaces().Create(&corev1.Namespace{
-				ObjectMeta: metav1.ObjectMeta{Name: namespaceName},
+				ObjectMeta: metav1.ObjectMeta{Name: namespaceName, Labels: labels},
 			})
 			if err != nil && !strings.Contains(err.Error(), "already exists") {
 				return errors.Wrap(err, "Failed to create namespace")
@@ -175,6 +177,30 @@ func createNamespaceIfMissing(namespaceName string, clientset kubernetes.Interfa
 	return nil
 }
 
+// getNamespaceLabels returns the labels of a deployment that are valid Kubernetes labels,
+// they are set on namespaces created for this deployment
+func getNamespaceLabels(ctx context.Context, deploymentID string) map[string]string {
+	depLabels, err := deployments.GetDeploymentLabels(ctx, deploymentID)
+	if err != nil {
+		log.Printf("[WARNING] Failed to get labels of deployment %q, they won't be set on its namespaces: %+v", deploymentID, err)
+		return nil
+	}
+	return filterValidLabels(depLabels)
+}
+
+// filterValidLabels returns the labels having a name and a value allowed by Kubernetes
+func filterValidLabels(labels map[string]string) map[string]string {
+	valid := make(map[string]string, len(labels))
+	for k, v := range labels {
+		if len(validation.IsQualifiedName(k)) > 0 || len(validation.IsValidLabelValue(v)) > 0 {
+			log.Debugf("Label %s=%s is not a valid Kubernetes label, it won't be set on namespaces", k, v)
+			continue
+		}
+		valid[k] = v
+	}
+	return valid
+}
+
 // deleteNamespace delete a Kubernetes namespaces known by its name
 func deleteNamespace(namespaceName string, clientset kubernetes.Interface) error {
 	err := clientset.CoreV1().Namespaces().Delete(namespaceName, &metav1.DeleteOptions{})
@@ -16,6 +16,7 @@ package kubernetes
 
 import (
 	"context"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -431,3 +432,39 @@ func Test_replaceServiceDepLookups(t *testing.T) {
 		})
 	}
 }
+
+func Test_filterValidLabels(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   map[string]string
+	}{
+		{"NoLabels", nil, map[string]string{}},
+		{"ValidLabels", map[string]string{"env": "prod", "app.kubernetes.io/name": "myapp", "empty": ""},
+			map[string]string{"env": "prod", "app.kubernetes.io/name": "myapp", "empty": ""}},
+		{"InvalidLabels", map[string]string{"env": "prod", "cost center": "1234", "team": "not a valid value"},
+			map[string]string{"env": "prod"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filterValidLabels(tt.labels); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterValidLabels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_createNamespaceIfMissingWithLabels(t *testing.T) {
+	k8s := newTestSimpleK8s()
+	labels := map[string]string{"env": "prod"}
+	if err := createNamespaceIfMissing("labeled-ns", labels, k8s.clientset); err != nil {
+		t.Fatalf("createNamespaceIfMissing() error = %v", err)
+	}
+	ns, err := k8s.clientset.CoreV1().Namespaces().Get("labeled-ns", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get namespace: %v", err)
+	}
+	if !reflect.DeepEqual(ns.Labels, labels) {
+		t.Errorf("namespace labels = %v, want %v", ns.Labels, labels)
+	}
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/ystia/yorc/v4/cluster"
 	"github.com/ystia/yorc/v4/deployments"
 	"github.com/ystia/yorc/v4/events"
 	"github.com/ystia/yorc/v4/helper/consulutil"
@@ -62,6 +63,7 @@ func (c *Check) Start() {
 	// instantiate channel to close the internal routine
 	c.chStop = make(chan struct{})
 	c.stop = false
+	cluster.AddWork(cluster.WorkCheck, c.ID)
 
 	// check if initially the node can be monitored according to its node state
 	if c.isNodeStateOKForMonitoring(ctx) {
@@ -107,6 +109,7 @@ func (c *Check) Stop() {
 	if !c.stop {
 		c.stop = true
 		close(c.chStop)
+		cluster.RemoveWork(cluster.WorkCheck, c.ID)
 	}
 }
 
@@ -215,3 +218,18 @@ func (c *Check) notify(additionalMessage string) {
 func buildID(deploymentID, nodeName, instance string) string {
 	return fmt.Sprintf("%s:%s:%s", deploymentID, nodeName, instance)
 }
+
+// GetInstanceCheckStatus returns the status of the monitoring check of a node instance
+//
+// The returned boolean is false if the node instance is not monitored.
+func GetInstanceCheckStatus(deploymentID, nodeName, instance string) (CheckStatus, bool, error) {
+	exist, value, err := consulutil.GetStringValue(path.Join(consulutil.MonitoringKVPrefix, "reports", buildID(deploymentID, nodeName, instance), "status"))
+	if err != nil {
+		return CheckStatusINITIAL, false, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if !exist || value == "" {
+		return CheckStatusINITIAL, false, nil
+	}
+	status, err := ParseCheckStatus(value)
+	return status, err == nil, errors.Wrapf(err, "invalid monitoring check status for instance %q of node %q", instance, nodeName)
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/hashicorp/consul/api"
 	"github.com/pkg/errors"
 
+	"github.com/ystia/yorc/v4/cluster"
 	"github.com/ystia/yorc/v4/config"
 	"github.com/ystia/yorc/v4/helper/consulutil"
 	"github.com/ystia/yorc/v4/log"
@@ -55,7 +56,7 @@ func Start(cfg config.Configuration, cc *api.Client) {
 	}
 
 	// Watch leader election for monitoring service
-	go consulutil.WatchLeaderElection(defaultMonManager.cc, defaultMonManager.serviceKey, defaultMonManager.chShutdown, defaultMonManager.startMonitoring, defaultMonManager.stopMonitoring)
+	go cluster.WatchLeaderElection(defaultMonManager.cc, cluster.RoleMonitoringLeader, defaultMonManager.serviceKey, defaultMonManager.chShutdown, defaultMonManager.startMonitoring, defaultMonManager.stopMonitoring)
 }
 
 // Stop allows to stop managing monitoring checks
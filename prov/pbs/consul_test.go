@@ -0,0 +1,75 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pbs
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/locations"
+	"github.com/ystia/yorc/v4/testutil"
+)
+
+var pbsTestLocationProps config.DynamicMap
+
+// The aim of this function is to run all package tests with consul server dependency with only one consul server start
+func TestRunConsulPBSPackageTests(t *testing.T) {
+	cfg := testutil.SetupTestConfig(t)
+	srv, _ := testutil.NewTestConsulInstance(t, &cfg)
+	defer func() {
+		srv.Stop()
+		os.RemoveAll(cfg.WorkingDirectory)
+	}()
+
+	// Create a PBS location
+	locationMgr, err := locations.GetManager(cfg)
+	require.NoError(t, err, "Error initializing locations")
+
+	pbsTestLocationProps = config.DynamicMap{
+		"user_name": "root",
+		"password":  "pwd",
+		"name":      "pbs",
+		"url":       "1.2.3.4",
+		"port":      "1234",
+	}
+	err = locationMgr.CreateLocation(
+		locations.LocationConfiguration{
+			Name:       "testPBSLocation",
+			Type:       infrastructureType,
+			Properties: pbsTestLocationProps,
+		})
+	require.NoError(t, err, "Failed to create a location")
+	defer func() {
+		locationMgr.RemoveLocation(t.Name())
+	}()
+
+	t.Run("groupPBS", func(t *testing.T) {
+		t.Run("simplePBSNodeAllocation", func(t *testing.T) {
+			testSimplePBSNodeAllocation(t, cfg)
+		})
+		t.Run("ExecutionCommonBuildJobInfo", func(t *testing.T) {
+			testExecutionCommonBuildJobInfo(t)
+		})
+		t.Run("ExecutionCommonPrepareAndSubmitJob", func(t *testing.T) {
+			testExecutionCommonPrepareAndSubmitJob(t)
+		})
+		t.Run("ActionOperatorAnalyzeJob", func(t *testing.T) {
+			testActionOperatorAnalyzeJob(t, cfg)
+		})
+	})
+}
@@ -0,0 +1,632 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pbs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/helper/sshutil"
+	"github.com/ystia/yorc/v4/locations"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov"
+	"github.com/ystia/yorc/v4/prov/operations"
+	"github.com/ystia/yorc/v4/tasks"
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+const home = "~"
+const batchScript = "b-%s.pbs"
+
+type execution interface {
+	resolveExecution(ctx context.Context) error
+	executeAsync(ctx context.Context) (*prov.Action, time.Duration, error)
+	execute(ctx context.Context) error
+}
+
+type executionCommon struct {
+	cfg            config.Configuration
+	locationProps  config.DynamicMap
+	deploymentID   string
+	taskID         string
+	client         sshutil.Client
+	NodeName       string
+	operation      prov.Operation
+	NodeType       string
+	OverlayPath    string
+	Artifacts      map[string]string
+	EnvInputs      []*operations.EnvInput
+	VarInputsNames []string
+	Primary        string
+	PrimaryFile    string
+	nodeInstances  []string
+	jobInfo        *jobInfo
+	stepName       string
+	isContainer    bool
+}
+
+func newExecution(ctx context.Context, cfg config.Configuration, taskID, deploymentID, nodeName, stepName string, operation prov.Operation) (execution, error) {
+	isContainer, err := deployments.IsTypeDerivedFrom(ctx, deploymentID, operation.ImplementationArtifact, artifactImageImplementation)
+	if err != nil {
+		return nil, err
+	}
+
+	var locationProps config.DynamicMap
+	locationMgr, err := locations.GetManager(cfg)
+	if err == nil {
+		locationProps, err = locationMgr.GetLocationPropertiesForNode(ctx, deploymentID, nodeName, infrastructureType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	execCommon := &executionCommon{
+		cfg:            cfg,
+		locationProps:  locationProps,
+		deploymentID:   deploymentID,
+		NodeName:       nodeName,
+		operation:      operation,
+		VarInputsNames: make([]string, 0),
+		EnvInputs:      make([]*operations.EnvInput, 0),
+		taskID:         taskID,
+		stepName:       stepName,
+		isContainer:    isContainer,
+	}
+	if err := execCommon.resolveOperation(ctx); err != nil {
+		return nil, err
+	}
+	// Get user credentials from credentials node property
+	// Its not a capability, so capabilityName set to empty string
+	creds, err := getUserCredentials(ctx, locationProps, deploymentID, nodeName, "")
+	if err != nil {
+		return nil, err
+	}
+	// Create sshClient using user credentials from credentials property if the are provided, or from the location otherwise
+	execCommon.client, err = getSSHClient(cfg, creds, locationProps)
+	if err != nil {
+		return nil, err
+	}
+
+	if isContainer {
+		execContainer := &executionContainer{executionCommon: execCommon}
+		return execContainer, execCommon.resolveExecution(ctx)
+	}
+
+	return execCommon, execCommon.resolveExecution(ctx)
+}
+
+func (e *executionCommon) executeAsync(ctx context.Context) (*prov.Action, time.Duration, error) {
+	// Only runnable operation is currently supported
+	log.Debugf("Execute the operation:%+v", e.operation)
+	switch strings.ToLower(e.operation.Name) {
+	case strings.ToLower(tosca.RunnableRunOperationName):
+		var err error
+		e.jobInfo, err = e.getJobInfoFromTaskContext()
+		if err != nil {
+			return nil, 0, err
+		}
+		return e.buildJobMonitoringAction(), e.jobInfo.MonitoringTimeInterval, nil
+	default:
+		return nil, 0, errors.Errorf("Unsupported operation %q", e.operation.Name)
+	}
+}
+
+func (e *executionCommon) execute(ctx context.Context) error {
+	// Only runnable operation is currently supported
+	log.Debugf("Execute the operation:%+v", e.operation)
+	switch strings.ToLower(e.operation.Name) {
+	case strings.ToLower(tosca.RunnableSubmitOperationName):
+		log.Debugf("Submit the job: %s", e.operation.Name)
+		if err := e.buildJobInfo(ctx); err != nil {
+			return errors.Wrap(err, "failed to build job information")
+		}
+		if e.jobInfo.ExecutionOptions.Command != "" && e.Primary != "" {
+			// If both primary artifact is provided (script) and command: return an error
+			return errors.Errorf("Either a script artifact or a command must be provided, but not both.")
+		}
+
+		// Add the primary artifact to the artifacts map if not already included
+		var is bool
+		if e.Primary != "" && e.PrimaryFile != "" {
+			for _, artPath := range e.Artifacts {
+				if strings.HasPrefix(e.Primary, artPath) {
+					is = true
+				}
+			}
+			if !is {
+				e.Artifacts[e.PrimaryFile] = e.Primary
+			}
+		}
+
+		if err := e.uploadArtifacts(ctx); err != nil {
+			return errors.Wrap(err, "failed to upload artifact")
+		}
+		if err := e.prepareAndSubmitJob(ctx); err != nil {
+			events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RegisterAsString(err.Error())
+			return errors.Wrapf(err, "failed to submit job with ID:%s", e.jobInfo.ID)
+		}
+		return e.storeJobInfo(ctx)
+	case strings.ToLower(tosca.RunnableCancelOperationName):
+		return e.cancelJob(ctx)
+	default:
+		return errors.Errorf("Unsupported operation %q", e.operation.Name)
+	}
+}
+
+func (e *executionCommon) storeJobInfo(ctx context.Context) error {
+	jobInfoJSON, err := json.Marshal(e.jobInfo)
+	if err != nil {
+		return errors.Wrap(err, "Failed to marshal PBS job information")
+	}
+	err = tasks.SetTaskData(e.taskID, e.NodeName+"-jobInfo", string(jobInfoJSON))
+	if err != nil {
+		return err
+	}
+	// Set the JobID attribute
+	err = deployments.SetAttributeForAllInstances(ctx, e.deploymentID, e.NodeName, "job_id", e.jobInfo.ID)
+	return errors.Wrap(err, "failed to store job id an manual cleanup may be necessary")
+}
+
+func (e *executionCommon) cancelJob(ctx context.Context) error {
+	var jobID string
+	if jobInfo, err := e.getJobInfoFromTaskContext(); err != nil {
+		if !tasks.IsTaskDataNotFoundError(err) {
+			return err
+		}
+		// Not cancelling within the same task try to get jobID from attribute
+		id, err := deployments.GetInstanceAttributeValue(ctx, e.deploymentID, e.NodeName, "0", "job_id")
+		if err != nil {
+			return err
+		} else if id != nil && id.RawString() != "" {
+			jobID = id.String()
+		}
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, e.deploymentID).Registerf(
+			"PBS job cancellation called from a dedicated \"cancel\" workflow. JobID retrieved from node %q attribute. This may cause issues if multiple workflows are running in parallel. Prefer using a workflow cancellation.", e.NodeName)
+	} else {
+		jobID = jobInfo.ID
+	}
+	return cancelJobID(jobID, e.client)
+}
+
+func (e *executionCommon) getJobInfoFromTaskContext() (*jobInfo, error) {
+	jobInfoJSON, err := tasks.GetTaskData(e.taskID, e.NodeName+"-jobInfo")
+	if err != nil {
+		return nil, err
+	}
+	jobInfo := new(jobInfo)
+	err = json.Unmarshal([]byte(jobInfoJSON), jobInfo)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to unmarshal stored PBS job information")
+	}
+	log.Debugf("Unmarshal Job info for task %s, Job ID %q.", e.taskID, jobInfo.ID)
+	return jobInfo, nil
+}
+
+func (e *executionCommon) buildJobMonitoringAction() *prov.Action {
+	// Fill all used data for job monitoring
+	data := make(map[string]string)
+	data["taskID"] = e.taskID
+	data["jobID"] = e.jobInfo.ID
+	data["stepName"] = e.stepName
+	data["nodeName"] = e.NodeName
+	data["workingDir"] = e.jobInfo.WorkingDir
+	data["artifacts"] = strings.Join(e.jobInfo.Artifacts, ",")
+
+	return &prov.Action{ActionType: jobMonitoringActionType, Data: data}
+}
+
+func (e *executionCommon) getIntJobOption(ctx context.Context, name string) (int, error) {
+	v, err := deployments.GetNodePropertyValue(ctx, e.deploymentID, e.NodeName, "pbs_options", name)
+	if err != nil || v == nil || v.RawString() == "" {
+		return 0, err
+	}
+	i, err := strconv.Atoi(v.RawString())
+	return i, errors.Wrapf(err, "invalid %q option for node %q", name, e.NodeName)
+}
+
+func (e *executionCommon) getStringJobOption(ctx context.Context, name string) (string, error) {
+	v, err := deployments.GetNodePropertyValue(ctx, e.deploymentID, e.NodeName, "pbs_options", name)
+	if err != nil || v == nil {
+		return "", err
+	}
+	return v.RawString(), nil
+}
+
+func (e *executionCommon) buildJobInfo(ctx context.Context) error {
+	e.jobInfo = &jobInfo{}
+	var err error
+	if e.jobInfo.Name, err = e.getStringJobOption(ctx, "name"); err != nil {
+		return err
+	}
+	if e.jobInfo.Name == "" {
+		e.jobInfo.Name = e.locationProps.GetString("default_job_name")
+		if e.jobInfo.Name == "" {
+			e.jobInfo.Name = e.deploymentID
+		}
+	}
+
+	if e.jobInfo.Chunks, err = e.getIntJobOption(ctx, "chunks"); err != nil {
+		return err
+	}
+	if e.jobInfo.Chunks == 0 {
+		e.jobInfo.Chunks = 1
+	}
+	if e.jobInfo.Cpus, err = e.getIntJobOption(ctx, "ncpus"); err != nil {
+		return err
+	}
+	if e.jobInfo.MPIProcs, err = e.getIntJobOption(ctx, "mpiprocs"); err != nil {
+		return err
+	}
+	if mem, err := e.getStringJobOption(ctx, "mem"); err != nil {
+		return err
+	} else if mem != "" {
+		if e.jobInfo.Mem, err = toPBSMemFormat(mem); err != nil {
+			return err
+		}
+	}
+	if e.jobInfo.Walltime, err = e.getStringJobOption(ctx, "walltime"); err != nil {
+		return err
+	}
+	if e.jobInfo.Queue, err = e.getStringJobOption(ctx, "queue"); err != nil {
+		return err
+	}
+	if e.jobInfo.Account, err = e.getStringJobOption(ctx, "account"); err != nil {
+		return err
+	}
+	if e.jobInfo.Account == "" && e.locationProps.GetBool("enforce_accounting") {
+		return errors.Errorf("Job account must be set as configuration enforces accounting")
+	}
+
+	if extra, err := e.getStringJobOption(ctx, "extra_options"); err != nil {
+		return err
+	} else if extra != "" {
+		if err = json.Unmarshal([]byte(extra), &e.jobInfo.Opts); err != nil {
+			return err
+		}
+	}
+
+	if monitoringTime, err := deployments.GetNodePropertyValue(ctx, e.deploymentID, e.NodeName, "monitoring_time_interval"); err != nil {
+		return err
+	} else if monitoringTime != nil && monitoringTime.RawString() != "" {
+		e.jobInfo.MonitoringTimeInterval, err = time.ParseDuration(monitoringTime.RawString())
+		if err != nil {
+			return err
+		}
+	}
+	if e.jobInfo.MonitoringTimeInterval == 0 {
+		e.jobInfo.MonitoringTimeInterval = e.locationProps.GetDuration("job_monitoring_time_interval")
+		if e.jobInfo.MonitoringTimeInterval <= 0 {
+			// Default value
+			e.jobInfo.MonitoringTimeInterval = 5 * time.Second
+		}
+	}
+
+	e.jobInfo.Inputs = make(map[string]string)
+	for _, input := range e.EnvInputs {
+		if !strings.Contains(input.Name, "credentials") {
+			e.jobInfo.Inputs[input.Name] = input.Value
+		}
+	}
+
+	// Retrieve job id from attribute if it was previously set (otherwise will be retrieved when running the job)
+	id, err := deployments.GetInstanceAttributeValue(ctx, e.deploymentID, e.NodeName, "0", "job_id")
+	if err != nil {
+		return err
+	} else if id != nil && id.RawString() != "" {
+		e.jobInfo.ID = id.String()
+	}
+
+	eo, err := deployments.GetNodePropertyValue(ctx, e.deploymentID, e.NodeName, "execution_options")
+	if err != nil {
+		return err
+	}
+	if eo != nil && eo.Value != nil {
+		err = mapstructure.Decode(eo.Value, &e.jobInfo.ExecutionOptions)
+		if err != nil {
+			return errors.Wrapf(err, `invalid execution options datatype for attribute "execution_options" for node %q`, e.NodeName)
+		}
+	}
+
+	if e.jobInfo.ExecutionOptions.Command == "" && e.Primary == "" {
+		return errors.Errorf("Either job command property must be filled or batch script must be provided")
+	}
+
+	// Working directory: default is user's home
+	if wd, err := deployments.GetNodePropertyValue(ctx, e.deploymentID, e.NodeName, "working_directory"); err != nil {
+		return err
+	} else if wd != nil && wd.RawString() != "" {
+		e.jobInfo.WorkingDir = wd.RawString()
+	} else {
+		e.jobInfo.WorkingDir = home
+	}
+
+	envFile, err := deployments.GetNodePropertyValue(ctx, e.deploymentID, e.NodeName, "environment_file")
+	if err != nil {
+		return err
+	}
+	if envFile != nil {
+		e.jobInfo.EnvFile = envFile.RawString()
+	}
+	return nil
+}
+
+func (e *executionCommon) buildJobOpts() string {
+	var opts string
+	opts += fmt.Sprintf(" -N '%s'", e.jobInfo.Name)
+	selectStmt := fmt.Sprintf("select=%d", e.jobInfo.Chunks)
+	if e.jobInfo.Cpus != 0 {
+		selectStmt += fmt.Sprintf(":ncpus=%d", e.jobInfo.Cpus)
+	}
+	if e.jobInfo.MPIProcs != 0 {
+		selectStmt += fmt.Sprintf(":mpiprocs=%d", e.jobInfo.MPIProcs)
+	}
+	if e.jobInfo.Mem != "" {
+		selectStmt += fmt.Sprintf(":mem=%s", e.jobInfo.Mem)
+	}
+	opts += fmt.Sprintf(" -l %s", selectStmt)
+	if e.jobInfo.Walltime != "" {
+		opts += fmt.Sprintf(" -l walltime=%s", e.jobInfo.Walltime)
+	}
+	if e.jobInfo.Queue != "" {
+		opts += fmt.Sprintf(" -q '%s'", e.jobInfo.Queue)
+	}
+	if e.jobInfo.Account != "" {
+		opts += fmt.Sprintf(" -A '%s'", e.jobInfo.Account)
+	}
+	if len(e.jobInfo.Opts) > 0 {
+		opts += fmt.Sprintf(" %s", strings.Join(e.jobInfo.Opts, " "))
+	}
+	// Environment variables exported before the submission are not propagated to the job by default
+	if e.buildEnvVars() != "" {
+		opts += " -V"
+	}
+	log.Debugf("opts=%q", opts)
+	return opts
+}
+
+func (e *executionCommon) prepareAndSubmitJob(ctx context.Context) error {
+	var cmd string
+	if e.jobInfo.ExecutionOptions.Command != "" {
+		inner := fmt.Sprintf("%s %s", e.jobInfo.ExecutionOptions.Command, quoteArgs(e.jobInfo.ExecutionOptions.Args))
+		var err error
+		cmd, err = e.wrapCommand(inner)
+		if err != nil {
+			return err
+		}
+	} else {
+		cmd = fmt.Sprintf("%s%s%sqsub%s %s", e.sourceEnvFile(), e.changeWorkingDirCmd(), e.buildEnvVars(), e.buildJobOpts(), path.Join(e.jobInfo.WorkingDir, e.PrimaryFile))
+	}
+	return e.submitJob(ctx, cmd)
+}
+
+func (e *executionCommon) wrapCommand(innerCmd string) (string, error) {
+	// Generate a random UUID to add it to the batch wrapper script name
+	// this will prevent collisions when running several jobs in parallel
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to generate UUID for generated PBS batch script name")
+	}
+	scriptName := fmt.Sprintf(batchScript, id.String())
+	pathScript := path.Join(e.jobInfo.WorkingDir, scriptName)
+	// Add the script to the artifact's list
+	e.jobInfo.Artifacts = append(e.jobInfo.Artifacts, scriptName)
+	// PBS runs jobs from the user's home directory, so we move to the submission directory first
+	cat := fmt.Sprintf(`cat <<'EOF' > %s
+#!/bin/bash
+%s
+cd "$PBS_O_WORKDIR"
+%s
+EOF
+`, pathScript, e.buildInlineQsubOptions(), innerCmd)
+	// Ensure generated script removal after its submission
+	return fmt.Sprintf("%s%s%s%sqsub%s %s; rm -f %s", e.sourceEnvFile(), e.changeWorkingDirCmd(), e.buildEnvVars(), cat, e.buildJobOpts(), pathScript, pathScript), nil
+}
+
+func (e *executionCommon) buildInlineQsubOptions() string {
+	var b strings.Builder
+	for _, opt := range e.jobInfo.ExecutionOptions.InScriptOptions {
+		if strings.HasPrefix(opt, "#") {
+			b.WriteString(opt)
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+func (e *executionCommon) changeWorkingDirCmd() string {
+	if e.jobInfo.WorkingDir != home {
+		return fmt.Sprintf("mkdir -p %s;cd %s;", e.jobInfo.WorkingDir, e.jobInfo.WorkingDir)
+	}
+	return "cd ~;"
+}
+
+func (e *executionCommon) sourceEnvFile() string {
+	var cmd string
+	if e.jobInfo.EnvFile != "" {
+		cmd = fmt.Sprintf("[ -f %s ] && { source %s ; } ;", e.jobInfo.EnvFile, e.jobInfo.EnvFile)
+	}
+	return cmd
+}
+
+func (e *executionCommon) buildEnvVars() string {
+	var exports string
+	for _, v := range e.jobInfo.ExecutionOptions.EnvVars {
+		if is, key, val := parseKeyValue(v); is {
+			exports += fmt.Sprintf("export %s='%s';", key, val)
+		}
+	}
+	for k, v := range e.jobInfo.Inputs {
+		if strings.TrimSpace(k) != "" && strings.TrimSpace(v) != "" {
+			exports += fmt.Sprintf("export %s='%s';", k, v)
+		}
+	}
+	return exports
+}
+
+func (e *executionCommon) submitJob(ctx context.Context, cmd string) error {
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, e.deploymentID).RegisterAsString(fmt.Sprintf("Run the command: %s", cmd))
+	out, err := e.client.RunCommand(cmd)
+	if err != nil {
+		log.Debugf("stderr:%q", out)
+		return errors.Wrap(err, out)
+	}
+	if e.jobInfo.ID, err = retrieveJobID(out); err != nil {
+		return err
+	}
+	log.Debugf("JobID:%q", e.jobInfo.ID)
+	return nil
+}
+
+func (e *executionCommon) uploadArtifacts(ctx context.Context) error {
+	log.Debugf("Upload artifacts to remote host")
+	// Add artifact to job artifact's list for monitoring actions
+	e.jobInfo.Artifacts = make([]string, 0)
+	for k := range e.Artifacts {
+		e.jobInfo.Artifacts = append(e.jobInfo.Artifacts, k)
+	}
+
+	var g errgroup.Group
+	for artName, artPath := range e.Artifacts {
+		log.Debugf("handle artifact path:%q, name:%q", artPath, artName)
+		func(artName, artPath string) {
+			g.Go(func() error {
+				sourcePath := path.Join(e.OverlayPath, artPath)
+				fileInfo, err := os.Stat(sourcePath)
+				if err != nil {
+					return err
+				}
+				if fileInfo.IsDir() {
+					return e.walkArtifactDirectory(ctx, sourcePath, path.Dir(sourcePath))
+				}
+				return e.uploadArtifact(ctx, sourcePath, artName)
+			})
+		}(artName, artPath)
+	}
+	return g.Wait()
+}
+
+func (e *executionCommon) walkArtifactDirectory(ctx context.Context, rootPath string, artifactBaseName string) error {
+	return filepath.Walk(rootPath, func(pathFile string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return e.uploadArtifact(ctx, pathFile, artifactBaseName)
+		}
+		return nil
+	})
+}
+
+func (e *executionCommon) uploadArtifact(ctx context.Context, pathFile, artifactBaseName string) error {
+	var relPath string
+	if strings.HasSuffix(pathFile, artifactBaseName) {
+		relPath = artifactBaseName
+	} else {
+		var err error
+		relPath, err = filepath.Rel(artifactBaseName, pathFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	source, err := ioutil.ReadFile(pathFile)
+	if err != nil {
+		return err
+	}
+
+	remotePath := path.Join(e.jobInfo.WorkingDir, relPath)
+	log.Debugf("uploadArtifact file from source path:%q to:%q", pathFile, remotePath)
+	return e.client.CopyFile(bytes.NewReader(source), remotePath, "0755")
+}
+
+func (e *executionCommon) resolveOperation(ctx context.Context) error {
+	var err error
+	e.NodeType, err = deployments.GetNodeType(ctx, e.deploymentID, e.NodeName)
+	if err != nil {
+		return err
+	}
+
+	// Only Submit operation need to retrieve primary/operation implementation file
+	if strings.ToLower(e.operation.Name) != strings.ToLower(tosca.RunnableSubmitOperationName) {
+		return nil
+	}
+
+	// Only operation file is required for container execution
+	if e.isContainer {
+		e.Primary, err = deployments.GetOperationImplementationFile(ctx, e.deploymentID, e.operation.ImplementedInNodeTemplate, e.NodeType, e.operation.Name)
+		if err != nil {
+			return err
+		}
+	} else {
+		operationImpl, err := deployments.GetOperationImplementation(ctx, e.deploymentID, e.operation.ImplementedInNodeTemplate, e.operation.ImplementedInType, e.operation.Name)
+		if err != nil {
+			return err
+		}
+		if operationImpl != nil {
+			e.Primary = operationImpl.Primary
+		}
+	}
+
+	e.Primary = strings.TrimSpace(e.Primary)
+	if e.operation.ImplementedInType == "yorc.nodes.pbs.Job" && e.Primary == "embedded" {
+		e.Primary = ""
+	}
+
+	// Get operation implementation file for upload purpose
+	if !e.isContainer && e.Primary != "" {
+		e.PrimaryFile, err = deployments.GetOperationImplementationFile(ctx, e.deploymentID, e.operation.ImplementedInNodeTemplate, e.NodeType, e.operation.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Debugf("primary implementation: %q", e.Primary)
+	e.nodeInstances, err = tasks.GetInstances(ctx, e.taskID, e.deploymentID, e.NodeName)
+	return err
+}
+
+func (e *executionCommon) resolveExecution(ctx context.Context) error {
+	log.Debugf("Preparing execution of operation %q on node %q for deployment %q", e.operation.Name, e.NodeName, e.deploymentID)
+	ovPath, err := operations.GetOverlayPath(e.cfg, e.taskID, e.deploymentID)
+	if err != nil {
+		return err
+	}
+	e.OverlayPath = ovPath
+
+	e.EnvInputs, e.VarInputsNames, err = operations.ResolveInputsWithInstances(ctx, e.deploymentID, e.NodeName, e.taskID, e.operation, nil, nil)
+	if err != nil {
+		return err
+	}
+	e.Artifacts, err = deployments.GetFileArtifactsForNode(ctx, e.deploymentID, e.NodeName)
+	log.Debugf("Resolved artifacts: %v", e.Artifacts)
+	return err
+}
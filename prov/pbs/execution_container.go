@@ -0,0 +1,160 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pbs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+type executionContainer struct {
+	*executionCommon
+	imageURI       string
+	runtime        string
+	commandOptions []string
+}
+
+func (e *executionContainer) execute(ctx context.Context) error {
+	// Only runnable operation is currently supported
+	log.Debugf("Execute the operation:%+v", e.operation)
+	switch strings.ToLower(e.operation.Name) {
+	case strings.ToLower(tosca.RunnableSubmitOperationName):
+		log.Printf("Submit the job: %s", e.operation.Name)
+		if e.Primary == "" {
+			return errors.New("Image artifact is mandatory and must be filled in the operation implementation")
+		}
+		if err := e.buildJobInfo(ctx); err != nil {
+			return errors.Wrap(err, "failed to build job information")
+		}
+		if err := e.resolveImageURI(ctx); err != nil {
+			return errors.Wrap(err, "failed to resolve container image URI")
+		}
+		if err := e.getContainerProps(ctx); err != nil {
+			return errors.Wrap(err, "failed to retrieve container command options")
+		}
+		if err := e.uploadArtifacts(ctx); err != nil {
+			return errors.Wrap(err, "failed to upload artifact")
+		}
+		if err := e.prepareAndSubmitContainerJob(ctx); err != nil {
+			events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, e.deploymentID).RegisterAsString(err.Error())
+			return errors.Wrapf(err, "failed to submit job with ID:%s", e.jobInfo.ID)
+		}
+		return e.storeJobInfo(ctx)
+	case strings.ToLower(tosca.RunnableCancelOperationName):
+		return e.cancelJob(ctx)
+	default:
+		return errors.Errorf("Unsupported operation %q", e.operation.Name)
+	}
+}
+
+func (e *executionContainer) buildContainerCommand() string {
+	cmdOpts := strings.Join(e.commandOptions, " ")
+	switch e.runtime {
+	case "podman":
+		// Podman needs a registry prefix and not a docker:// transport
+		image := strings.TrimPrefix(e.imageURI, "docker://")
+		if e.jobInfo.ExecutionOptions.Command != "" {
+			return fmt.Sprintf("podman run --rm %s %s %s %s", cmdOpts, image, e.jobInfo.ExecutionOptions.Command, quoteArgs(e.jobInfo.ExecutionOptions.Args))
+		}
+		return fmt.Sprintf("podman run --rm %s %s", cmdOpts, image)
+	default:
+		if e.jobInfo.ExecutionOptions.Command != "" {
+			return fmt.Sprintf("singularity exec %s %s %s %s", cmdOpts, e.imageURI, e.jobInfo.ExecutionOptions.Command, quoteArgs(e.jobInfo.ExecutionOptions.Args))
+		}
+		return fmt.Sprintf("singularity run %s %s", cmdOpts, e.imageURI)
+	}
+}
+
+func (e *executionContainer) prepareAndSubmitContainerJob(ctx context.Context) error {
+	cmd, err := e.wrapCommand(e.buildContainerCommand())
+	if err != nil {
+		return err
+	}
+	return e.submitJob(ctx, cmd)
+}
+
+func (e *executionContainer) resolveImageURI(ctx context.Context) error {
+	switch {
+	// Docker image
+	case strings.HasPrefix(e.Primary, "docker://"):
+		return e.buildImageURI(ctx, "docker://")
+	// Singularity image
+	case strings.HasPrefix(e.Primary, "shub://"):
+		return e.buildImageURI(ctx, "shub://")
+	// File image
+	case strings.HasSuffix(e.Primary, ".simg") || strings.HasSuffix(e.Primary, ".sif") || strings.HasSuffix(e.Primary, ".img"):
+		e.imageURI = e.Primary
+	default:
+		return errors.Errorf("Unable to resolve image URI from image with name:%q", e.Primary)
+	}
+	return nil
+}
+
+func (e *executionContainer) buildImageURI(ctx context.Context, prefix string) error {
+	repoName, err := deployments.GetOperationImplementationRepository(ctx, e.deploymentID, e.operation.ImplementedInNodeTemplate, e.NodeType, e.operation.Name)
+	if err != nil {
+		return err
+	}
+	e.imageURI = e.Primary
+	if repoName == "" {
+		return nil
+	}
+	repoURL, err := deployments.GetRepositoryURLFromName(ctx, e.deploymentID, repoName)
+	if err != nil {
+		return err
+	}
+	// Just ignore default public Docker and Singularity registries
+	if repoURL == "" || repoURL == deployments.DockerHubURL || repoURL == deployments.SingularityHubURL {
+		return nil
+	}
+	urlStruct, err := url.Parse(repoURL)
+	if err != nil {
+		return err
+	}
+	tabs := strings.Split(e.Primary, prefix)
+	e.imageURI = prefix + path.Join(urlStruct.Host, tabs[1])
+	log.Debugf("imageURI:%q", e.imageURI)
+	return nil
+}
+
+func (e *executionContainer) getContainerProps(ctx context.Context) error {
+	if o, err := deployments.GetNodePropertyValue(ctx, e.deploymentID, e.NodeName, "container_command_options"); err != nil {
+		return err
+	} else if o != nil && o.RawString() != "" {
+		if err = json.Unmarshal([]byte(o.RawString()), &e.commandOptions); err != nil {
+			return err
+		}
+	}
+	r, err := deployments.GetNodePropertyValue(ctx, e.deploymentID, e.NodeName, "container_runtime")
+	if err != nil {
+		return err
+	}
+	e.runtime = "singularity"
+	if r != nil && r.RawString() != "" {
+		e.runtime = r.RawString()
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pbs
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/sshutil"
+	"github.com/ystia/yorc/v4/prov/operations"
+	"github.com/ystia/yorc/v4/testutil"
+	"github.com/ystia/yorc/v4/tosca/types"
+)
+
+func Test_executionCommon_wrapCommand(t *testing.T) {
+	type fields struct {
+		jobInfo *jobInfo
+	}
+	type args struct {
+		innerCmd string
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		args        args
+		wantPattern *regexp.Regexp
+		wantErr     bool
+	}{
+		{"TestBasicGeneration", fields{
+			jobInfo: &jobInfo{Name: "MyJob", Chunks: 1, WorkingDir: "~"}},
+			args{"ping -c 3 1.1.1.1"}, regexp.MustCompile(`cd ~;cat <<'EOF' > ~/b-[-a-f0-9]+.pbs\n#!/bin/bash\n\ncd "\$PBS_O_WORKDIR"\nping -c 3 1.1.1.1\nEOF\nqsub -N 'MyJob' -l select=1 ~/b-[-a-f0-9]+.pbs; rm -f ~/b-[-a-f0-9]+.pbs`), false},
+		{"TestWithInlineOptsGeneration", fields{
+			jobInfo: &jobInfo{Name: "MyJob", Chunks: 1, WorkingDir: "~", ExecutionOptions: types.PBSExecutionOptions{InScriptOptions: []string{"#PBS -m abe", "not sharp prefixed so will not appear"}}}},
+			args{"ping -c 3 1.1.1.1"}, regexp.MustCompile(`cd ~;cat <<'EOF' > ~/b-[-a-f0-9]+.pbs\n#!/bin/bash\n#PBS -m abe\n\ncd "\$PBS_O_WORKDIR"\nping -c 3 1.1.1.1\nEOF\nqsub -N 'MyJob' -l select=1 ~/b-[-a-f0-9]+.pbs; rm -f ~/b-[-a-f0-9]+.pbs`), false},
+		{"TestWithEnvVars", fields{
+			jobInfo: &jobInfo{Name: "MyJob", Chunks: 1, WorkingDir: "work", ExecutionOptions: types.PBSExecutionOptions{EnvVars: []string{"FOO=bar"}}}},
+			args{"ping -c 3 1.1.1.1"}, regexp.MustCompile(`mkdir -p work;cd work;export FOO='bar';cat <<'EOF' > work/b-[-a-f0-9]+.pbs\n#!/bin/bash\n\ncd "\$PBS_O_WORKDIR"\nping -c 3 1.1.1.1\nEOF\nqsub -N 'MyJob' -l select=1 -V work/b-[-a-f0-9]+.pbs; rm -f work/b-[-a-f0-9]+.pbs`), false},
+		{"TestWithSourceEnvFile", fields{
+			jobInfo: &jobInfo{Name: "MyJob", Chunks: 1, WorkingDir: "~", EnvFile: "~/.bash_profile"}},
+			args{"ping -c 3 1.1.1.1"}, regexp.MustCompile(`\[ -f ~/.bash_profile \] && \{ source ~/.bash_profile ; \} ;cd ~;cat <<'EOF' > ~/b-[-a-f0-9]+.pbs\n#!/bin/bash\n\ncd "\$PBS_O_WORKDIR"\nping -c 3 1.1.1.1\nEOF\nqsub -N 'MyJob' -l select=1 ~/b-[-a-f0-9]+.pbs; rm -f ~/b-[-a-f0-9]+.pbs`), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &executionCommon{
+				jobInfo: tt.fields.jobInfo,
+			}
+			got, err := e.wrapCommand(tt.args.innerCmd)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("executionCommon.wrapCommand() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantPattern.MatchString(got) {
+				t.Errorf("executionCommon.wrapCommand() = %v, want %v", got, tt.wantPattern.String())
+			}
+		})
+	}
+}
+
+func Test_buildAllocationCmd(t *testing.T) {
+	tests := []struct {
+		name      string
+		nodeAlloc *nodeAllocation
+		want      string
+	}{
+		{"DefaultAllocation", &nodeAllocation{jobName: "xyz"}, "echo 'sleep infinity' | qsub -N 'xyz' -l select=1"},
+		{"FullAllocation", &nodeAllocation{jobName: "xyz", cpu: "4", memory: "1953125kb", resources: []string{"ngpus=1"}, walltime: "01:00:00", queue: "workq", account: "account_test"},
+			"echo 'sleep infinity' | qsub -N 'xyz' -l select=1:ncpus=4:mem=1953125kb:ngpus=1 -l walltime=01:00:00 -q 'workq' -A 'account_test'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, buildAllocationCmd(tt.nodeAlloc))
+		})
+	}
+}
+
+func testExecutionCommonBuildJobInfo(t *testing.T) {
+
+	deploymentID := testutil.BuildDeploymentID(t)
+	ctx := context.Background()
+	err := deployments.StoreDeploymentDefinition(ctx, deploymentID, "testdata/simple_job.yaml")
+	require.NoError(t, err)
+
+	type fields struct {
+		locationProps config.DynamicMap
+		NodeName      string
+		Primary       string
+	}
+
+	tests := []struct {
+		name            string
+		fields          fields
+		wantErr         bool
+		expectedJobInfo jobInfo
+	}{
+		{"CheckDefaultValues", fields{config.DynamicMap{}, "SimpleJob", "primary"}, false,
+			jobInfo{Name: deploymentID, Chunks: 1, MonitoringTimeInterval: 5 * time.Second, Inputs: make(map[string]string), WorkingDir: home}},
+		{"ChecklocationPropertiesValues", fields{config.DynamicMap{"default_job_name": "myjobname", "job_monitoring_time_interval": "1s"}, "SimpleJob", "primary"}, false,
+			jobInfo{Name: "myjobname", Chunks: 1, MonitoringTimeInterval: time.Second, Inputs: make(map[string]string), WorkingDir: home}},
+		{"CheckErrorIfNoCommandAndPrimary", fields{config.DynamicMap{}, "SimpleJob", ""}, true, jobInfo{}},
+		{"CheckErrorIfAccountingEnforced", fields{config.DynamicMap{"enforce_accounting": true}, "SimpleJob", "primary"}, true, jobInfo{}},
+		{"CheckJobOptions", fields{config.DynamicMap{}, "JobWithOptions", ""}, false,
+			jobInfo{Name: "MyJob", Chunks: 2, Cpus: 4, MPIProcs: 4, Mem: "1953125kb", Walltime: "02:00:00", Queue: "workq", Account: "myaccount",
+				Opts: []string{"-l place=scatter"}, MonitoringTimeInterval: 5 * time.Second, Inputs: make(map[string]string), WorkingDir: home,
+				ExecutionOptions: types.PBSExecutionOptions{
+					Command: "mpiexec",
+					Args:    []string{"./my_mpi_app"},
+				}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &executionCommon{
+				locationProps: tt.fields.locationProps,
+				deploymentID:  deploymentID,
+				NodeName:      tt.fields.NodeName,
+				EnvInputs:     make([]*operations.EnvInput, 0),
+				Primary:       tt.fields.Primary,
+			}
+			err := e.buildJobInfo(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("executionCommon.buildJobInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				assert.Equal(t, tt.expectedJobInfo, *e.jobInfo)
+			}
+		})
+	}
+}
+
+func testExecutionCommonPrepareAndSubmitJob(t *testing.T) {
+
+	deploymentID := testutil.BuildDeploymentID(t)
+	ctx := context.Background()
+	err := deployments.StoreDeploymentDefinition(ctx, deploymentID, "testdata/simple_job.yaml")
+	require.NoError(t, err)
+	type fields struct {
+		PrimaryFile string
+		jobInfo     *jobInfo
+	}
+	tests := []struct {
+		name                   string
+		fields                 fields
+		expectedCommandPattern *regexp.Regexp
+		wantErr                bool
+	}{
+		{"CheckProvidedBatchScript",
+			fields{"primary.pbs", &jobInfo{Name: "MyJob", Chunks: 2, Cpus: 4, WorkingDir: home}},
+			regexp.MustCompile("cd ~;qsub -N 'MyJob' -l select=2:ncpus=4 ~/primary.pbs"),
+			false},
+		{"CheckWrappedCommand",
+			fields{"", &jobInfo{Name: "MyJob", Chunks: 2, MPIProcs: 4, Walltime: "02:00:00", Queue: "workq", WorkingDir: home,
+				ExecutionOptions: types.PBSExecutionOptions{
+					Command: "mpiexec",
+					Args:    []string{"./my_mpi_app"},
+				}}},
+			regexp.MustCompile(`cd ~;cat <<'EOF' > ~/b-[-a-f0-9]+.pbs\n#!/bin/bash\n\ncd "\$PBS_O_WORKDIR"\nmpiexec './my_mpi_app' \nEOF\nqsub -N 'MyJob' -l select=2:mpiprocs=4 -l walltime=02:00:00 -q 'workq' ~/b-[-a-f0-9]+.pbs; rm -f ~/b-[-a-f0-9]+.pbs`),
+			false},
+		{"CheckExtraOptions",
+			fields{"primary.pbs", &jobInfo{Name: "MyJob", Chunks: 1, Account: "myaccount", Opts: []string{"-l place=scatter"}, WorkingDir: home}},
+			regexp.MustCompile("cd ~;qsub -N 'MyJob' -l select=1 -A 'myaccount' -l place=scatter ~/primary.pbs"),
+			false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &executionCommon{
+				cfg:           config.Configuration{},
+				locationProps: config.DynamicMap{},
+				deploymentID:  deploymentID,
+				NodeName:      "SimpleJob",
+				EnvInputs:     make([]*operations.EnvInput, 0),
+				PrimaryFile:   tt.fields.PrimaryFile,
+				jobInfo:       tt.fields.jobInfo,
+			}
+
+			e.client = &sshutil.MockSSHClient{
+				MockRunCommand: func(cmd string) (string, error) {
+					if tt.expectedCommandPattern != nil && !tt.expectedCommandPattern.MatchString(cmd) {
+						return "", errors.Errorf("unexpected command: %q", cmd)
+					}
+					return "42.pbsserver", nil
+				},
+			}
+
+			if err := e.prepareAndSubmitJob(ctx); (err != nil) != tt.wantErr {
+				t.Errorf("executionCommon.prepareAndSubmitJob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				require.Equal(t, "42.pbsserver", e.jobInfo.ID)
+			}
+		})
+	}
+}
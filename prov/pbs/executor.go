@@ -0,0 +1,308 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pbs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/helper/sshutil"
+	"github.com/ystia/yorc/v4/locations"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov"
+	"github.com/ystia/yorc/v4/tasks"
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+// allocationScript is the placeholder job submitted to hold a node allocation until it is deleted
+const allocationScript = "sleep infinity"
+
+type defaultExecutor struct {
+}
+
+func getJobExecution(ctx context.Context, conf config.Configuration, taskID, deploymentID, nodeName string, operation prov.Operation, stepName string) (execution, error) {
+	isJob, err := deployments.IsNodeDerivedFrom(ctx, deploymentID, nodeName, "yorc.nodes.pbs.Job")
+	if err != nil {
+		return nil, err
+	}
+	if !isJob {
+		return nil, errors.Errorf("operation %q supported only for nodes derived from %q", operation.Name, "yorc.nodes.pbs.Job")
+	}
+	return newExecution(ctx, conf, taskID, deploymentID, nodeName, stepName, operation)
+}
+
+func (e *defaultExecutor) ExecAsyncOperation(ctx context.Context, conf config.Configuration, taskID, deploymentID, nodeName string, operation prov.Operation, stepName string) (*prov.Action, time.Duration, error) {
+	log.Debugf("PBS defaultExecutor: Execute the operation async: %+v", operation)
+
+	exec, err := getJobExecution(ctx, conf, taskID, deploymentID, nodeName, operation, stepName)
+	if err != nil {
+		return nil, 0, err
+	}
+	return exec.executeAsync(ctx)
+}
+
+func (e *defaultExecutor) ExecOperation(ctx context.Context, conf config.Configuration, taskID, deploymentID, nodeName string, operation prov.Operation) error {
+	log.Debugf("PBS defaultExecutor: Execute the operation: %+v", operation)
+
+	exec, err := getJobExecution(ctx, conf, taskID, deploymentID, nodeName, operation, "")
+	if err != nil {
+		return err
+	}
+	return exec.execute(ctx)
+}
+
+func (e *defaultExecutor) ExecDelegate(ctx context.Context, cfg config.Configuration, taskID, deploymentID, nodeName, delegateOperation string) error {
+	instances, err := tasks.GetInstances(ctx, taskID, deploymentID, nodeName)
+	if err != nil {
+		return err
+	}
+
+	var locationProps config.DynamicMap
+	locationMgr, err := locations.GetManager(cfg)
+	if err == nil {
+		locationProps, err = locationMgr.GetLocationPropertiesForNode(ctx, deploymentID, nodeName, infrastructureType)
+	}
+	if err != nil {
+		return err
+	}
+
+	operation := strings.ToLower(delegateOperation)
+	switch {
+	case operation == "install":
+		err = e.installNode(ctx, cfg, locationProps, deploymentID, nodeName, instances, operation)
+	case operation == "uninstall":
+		err = e.uninstallNode(ctx, cfg, locationProps, deploymentID, nodeName, instances, operation)
+	default:
+		return errors.Errorf("Unsupported operation %q", delegateOperation)
+	}
+	return err
+}
+
+func (e *defaultExecutor) installNode(ctx context.Context, cfg config.Configuration, locationProps config.DynamicMap, deploymentID, nodeName string, instances []string, operation string) error {
+	for _, instance := range instances {
+		err := deployments.SetInstanceStateWithContextualLogs(events.AddLogOptionalFields(ctx, events.LogOptionalFields{events.InstanceID: instance}), deploymentID, nodeName, instance, tosca.NodeStateCreating)
+		if err != nil {
+			return err
+		}
+	}
+	infra, err := generateInfrastructure(ctx, locationProps, deploymentID, nodeName, operation)
+	if err != nil {
+		return err
+	}
+
+	return e.createInfrastructure(ctx, cfg, locationProps, deploymentID, nodeName, infra)
+}
+
+func (e *defaultExecutor) uninstallNode(ctx context.Context, cfg config.Configuration, locationProps config.DynamicMap, deploymentID, nodeName string, instances []string, operation string) error {
+	for _, instance := range instances {
+		err := deployments.SetInstanceStateWithContextualLogs(events.AddLogOptionalFields(ctx, events.LogOptionalFields{events.InstanceID: instance}), deploymentID, nodeName, instance, tosca.NodeStateDeleting)
+		if err != nil {
+			return err
+		}
+	}
+	infra, err := generateInfrastructure(ctx, locationProps, deploymentID, nodeName, operation)
+	if err != nil {
+		return err
+	}
+
+	return e.destroyInfrastructure(ctx, cfg, locationProps, deploymentID, nodeName, infra)
+}
+
+func (e *defaultExecutor) createInfrastructure(ctx context.Context, cfg config.Configuration, locationProps config.DynamicMap, deploymentID, nodeName string, infra *infrastructure) error {
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).RegisterAsString("Creating the PBS infrastructure")
+	var g errgroup.Group
+	for _, compute := range infra.nodes {
+		func(ctx context.Context, comp *nodeAllocation) {
+			g.Go(func() error {
+				// Return an sshClient configured using the user credentials provided in the yorc.nodes.pbs.Compute node definition,
+				// or if not provided, the user credentials specified in the location configuration
+				sshClient, err := getSSHClient(cfg, comp.credentials, locationProps)
+				if err != nil {
+					events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, deploymentID).RegisterAsString(err.Error())
+					return err
+				}
+				return e.createNodeAllocation(ctx, locationProps, sshClient, comp, deploymentID, nodeName)
+			})
+		}(events.AddLogOptionalFields(ctx, events.LogOptionalFields{events.InstanceID: compute.instanceName}), compute)
+	}
+
+	if err := g.Wait(); err != nil {
+		err = errors.Wrapf(err, "Failed to create PBS infrastructure for deploymentID:%q, node name:%s", deploymentID, nodeName)
+		log.Debugf("%+v", err)
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, deploymentID).RegisterAsString(err.Error())
+		return err
+	}
+
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).RegisterAsString("Successfully creating the PBS infrastructure")
+	return nil
+}
+
+func (e *defaultExecutor) destroyInfrastructure(ctx context.Context, cfg config.Configuration, locationProps config.DynamicMap, deploymentID, nodeName string, infra *infrastructure) error {
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).RegisterAsString("Destroying the PBS infrastructure")
+	var g errgroup.Group
+	for _, compute := range infra.nodes {
+		func(ctx context.Context, comp *nodeAllocation) {
+			g.Go(func() error {
+				sshClient, err := getSSHClient(cfg, comp.credentials, locationProps)
+				if err != nil {
+					events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, deploymentID).RegisterAsString(err.Error())
+					return err
+				}
+				return e.destroyNodeAllocation(ctx, sshClient, comp, deploymentID, nodeName)
+			})
+		}(events.AddLogOptionalFields(ctx, events.LogOptionalFields{events.InstanceID: compute.instanceName}), compute)
+	}
+
+	if err := g.Wait(); err != nil {
+		err = errors.Wrapf(err, "Failed to destroy PBS infrastructure for deploymentID:%q, node name:%s", deploymentID, nodeName)
+		log.Debugf("%+v", err)
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, deploymentID).RegisterAsString(err.Error())
+		return err
+	}
+
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).RegisterAsString("Successfully destroying the PBS infrastructure")
+	return nil
+}
+
+// buildAllocationCmd returns the qsub command submitting a placeholder job holding the node allocation
+func buildAllocationCmd(nodeAlloc *nodeAllocation) string {
+	selectStmt := "select=1"
+	if nodeAlloc.cpu != "" {
+		selectStmt += ":ncpus=" + nodeAlloc.cpu
+	}
+	if nodeAlloc.memory != "" {
+		selectStmt += ":mem=" + nodeAlloc.memory
+	}
+	for _, res := range nodeAlloc.resources {
+		selectStmt += ":" + res
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "echo '%s' | qsub -N '%s' -l %s", allocationScript, nodeAlloc.jobName, selectStmt)
+	if nodeAlloc.walltime != "" {
+		fmt.Fprintf(&b, " -l walltime=%s", nodeAlloc.walltime)
+	}
+	if nodeAlloc.queue != "" {
+		fmt.Fprintf(&b, " -q '%s'", nodeAlloc.queue)
+	}
+	if nodeAlloc.account != "" {
+		fmt.Fprintf(&b, " -A '%s'", nodeAlloc.account)
+	}
+	return b.String()
+}
+
+func (e *defaultExecutor) createNodeAllocation(ctx context.Context, locationProps config.DynamicMap, sshClient sshutil.Client, nodeAlloc *nodeAllocation, deploymentID, nodeName string) error {
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).RegisterAsString(fmt.Sprintf("Creating node allocation for: deploymentID:%q, node name:%q", deploymentID, nodeName))
+
+	cmd := buildAllocationCmd(nodeAlloc)
+	out, err := sshClient.RunCommand(cmd)
+	if err != nil {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, deploymentID).RegisterAsString(err.Error())
+		return errors.Wrapf(err, "Failed to allocate PBS resource: %s", out)
+	}
+	jobID, err := retrieveJobID(out)
+	if err != nil {
+		return err
+	}
+	err = deployments.SetInstanceAttribute(ctx, deploymentID, nodeName, nodeAlloc.instanceName, "job_id", jobID)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to set attribute (job_id) for node name:%q, instance name:%q", nodeName, nodeAlloc.instanceName)
+	}
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).Registerf("qsub command returned a job allocation with job ID:%q", jobID)
+
+	// Unlike Slurm salloc, qsub returns as soon as the job is queued, so we wait for the allocation to be running
+	info, err := waitForAllocation(ctx, sshClient, jobID, locationProps.GetDuration("job_monitoring_time_interval"))
+	if err != nil {
+		if ctx.Err() != nil {
+			log.Debugf("%s: Cancellation message has been sent: the pending job allocation (%s) has to be removed", deploymentID, jobID)
+			if err := cancelJobID(jobID, sshClient); err != nil {
+				log.Printf("[Warning] an error occurred during cancelling jobID:%q", jobID)
+			} else {
+				// Drain the related jobID compute attribute
+				deployments.SetInstanceAttribute(ctx, deploymentID, nodeName, nodeAlloc.instanceName, "job_id", "")
+			}
+		}
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, deploymentID).RegisterAsString(err.Error())
+		return errors.Wrap(err, "Failed to allocate PBS resource")
+	}
+
+	host := parseExecHost(info["exec_host"])
+	err = deployments.SetInstanceCapabilityAttribute(ctx, deploymentID, nodeName, nodeAlloc.instanceName, "endpoint", "ip_address", host)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to set capability attribute (ip_address) for node name:%s, instance name:%q", nodeName, nodeAlloc.instanceName)
+	}
+	for attr, value := range map[string]string{"ip_address": host, "node_name": host, "queue": info["queue"]} {
+		err = deployments.SetInstanceAttribute(ctx, deploymentID, nodeName, nodeAlloc.instanceName, attr, value)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to set attribute (%s) for node name:%q, instance name:%q", attr, nodeName, nodeAlloc.instanceName)
+		}
+	}
+
+	// Update the instance state
+	return deployments.SetInstanceStateWithContextualLogs(ctx, deploymentID, nodeName, nodeAlloc.instanceName, tosca.NodeStateStarted)
+}
+
+// waitForAllocation polls the given allocation job until it is running
+func waitForAllocation(ctx context.Context, sshClient sshutil.Client, jobID string, interval time.Duration) (map[string]string, error) {
+	if interval <= 0 {
+		// Default value
+		interval = 5 * time.Second
+	}
+	for {
+		info, err := getJobInfo(sshClient, jobID)
+		if err != nil {
+			return nil, err
+		}
+		state := jobState(info)
+		switch {
+		case state == "RUNNING" && info["exec_host"] != "":
+			return info, nil
+		case !isActiveJobState(state):
+			return nil, errors.Errorf("job allocation with ID %q ended with state %q (comment: %q)", jobID, state, info["comment"])
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (e *defaultExecutor) destroyNodeAllocation(ctx context.Context, sshClient sshutil.Client, nodeAlloc *nodeAllocation, deploymentID, nodeName string) error {
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).RegisterAsString(fmt.Sprintf("Destroying node allocation for: deploymentID:%q, node name:%q, instance name:%q", deploymentID, nodeName, nodeAlloc.instanceName))
+
+	jobID, err := deployments.GetInstanceAttributeValue(ctx, deploymentID, nodeName, nodeAlloc.instanceName, "job_id")
+	if err != nil {
+		return errors.Wrapf(err, "Failed to retrieve PBS job ID for node name:%q, instance name:%q", nodeName, nodeAlloc.instanceName)
+	}
+	if jobID == nil || jobID.RawString() == "" {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, deploymentID).Registerf("No job ID found for node name:%q, instance name:%q. We assume it has already been deleted", nodeName, nodeAlloc.instanceName)
+	} else {
+		if err := cancelJobID(jobID.RawString(), sshClient); err != nil {
+			return err
+		}
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).RegisterAsString(fmt.Sprintf("Cancelling Job ID:%q", jobID.RawString()))
+	}
+	// Update the instance state
+	return deployments.SetInstanceStateWithContextualLogs(ctx, deploymentID, nodeName, nodeAlloc.instanceName, tosca.NodeStateDeleted)
+}
@@ -0,0 +1,359 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pbs
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/sshutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/tosca/types"
+)
+
+// qsub returns the job identifier on stdout as "<sequence_number>.<server_name>"
+// job arrays identifiers have the form "<sequence_number>[].<server_name>"
+const reQsub = `^(\d+(?:\[\])?(?:\.[\w.-]+)?)$`
+
+const unknownJob = "Unknown Job Id"
+
+type noJobFound struct {
+	msg string
+}
+
+func (jid *noJobFound) Error() string {
+	return jid.msg
+}
+
+func isNoJobFoundError(err error) bool {
+	cause := errors.Cause(err)
+	_, ok := cause.(*noJobFound)
+	return ok
+}
+
+// getSSHClient returns a SSH client with PBS credentials from node or job configuration provided by the deployment,
+// or by the yorc PBS location configuration
+func getSSHClient(cfg config.Configuration, credentials *types.Credential, locationProps config.DynamicMap) (*sshutil.SSHClient, error) {
+	// Check mandatory PBS configuration
+	if err := checkLocationConfig(locationProps); err != nil {
+		log.Printf("Unable to provide SSH client due to:%+v", err)
+		return nil, err
+	}
+	if credentials.Token == "" && len(credentials.Keys) == 0 {
+		return nil, errors.New("PBS missing authentication details in deployment properties, password or private_key should be set")
+	}
+	keys, err := sshutil.GetKeysFromCredentialsDataType(credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	SSHConfig := &ssh.ClientConfig{
+		User:            credentials.User,
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         locationProps.GetDurationOrDefault("ssh_connection_timeout", cfg.SSHConnectionTimeout),
+	}
+
+	// Set an authentication method. At least one authentication method
+	// has to be set, private/public key or password.
+	for keyName, pk := range keys {
+		keyAuth, err := sshutil.ReadSSHPrivateKey(pk)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read key %q", keyName)
+		}
+		SSHConfig.Auth = append(SSHConfig.Auth, keyAuth)
+	}
+
+	if credentials.Token != "" {
+		SSHConfig.Auth = append(SSHConfig.Auth, ssh.Password(credentials.Token))
+	}
+
+	port, err := strconv.Atoi(locationProps.GetString("port"))
+	if err != nil {
+		wrapErr := errors.Wrap(err, "PBS configuration port is not a valid port")
+		log.Printf("Unable to provide SSH client due to:%+v", wrapErr)
+		return nil, wrapErr
+	}
+
+	return &sshutil.SSHClient{
+		Config:       SSHConfig,
+		Host:         locationProps.GetString("url"),
+		Port:         port,
+		MaxRetries:   locationProps.GetUint64OrDefault("ssh_connection_max_retries", cfg.SSHConnectionMaxRetries),
+		RetryBackoff: locationProps.GetDurationOrDefault("ssh_connection_retry_backoff", cfg.SSHConnectionRetryBackoff),
+	}, nil
+}
+
+// getUserCredentials returns user credentials from a node property, or a capability property.
+// the property name is provided by propertyName parameter, and its type is supposed to be tosca.datatypes.Credential
+func getUserCredentials(ctx context.Context, locationProps config.DynamicMap, deploymentID, nodeName, capabilityName string) (*types.Credential, error) {
+	var err error
+	var credentialsValue *deployments.TOSCAValue
+	if capabilityName != "" {
+		credentialsValue, err = deployments.GetCapabilityPropertyValue(ctx, deploymentID, nodeName, capabilityName, "credentials")
+	} else {
+		credentialsValue, err = deployments.GetNodePropertyValue(ctx, deploymentID, nodeName, "credentials")
+	}
+	if err != nil {
+		return nil, err
+	}
+	creds := new(types.Credential)
+	if credentialsValue != nil && credentialsValue.RawString() != "" {
+		err = mapstructure.Decode(credentialsValue.Value, creds)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode credentials for node %q", nodeName)
+		}
+	}
+
+	// Get user credentials provided by the deployment, if any
+	if creds.User != "" {
+		if creds.Token == "" && len(creds.Keys) == 0 {
+			return nil, errors.New("PBS missing authentication details in deployment properties, password or private_key should be set")
+		}
+		return creds, nil
+	}
+
+	// Get user credentials from the location properties
+	if err := checkLocationUserConfig(locationProps); err != nil {
+		log.Printf("Unable to provide SSH client due to:%+v", err)
+		return nil, err
+	}
+	creds.User = strings.TrimSpace(locationProps.GetString("user_name"))
+	creds.User = config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("pbs.user_name", creds.User).(string)
+	privateKey := strings.TrimSpace(locationProps.GetString("private_key"))
+	if privateKey != "" {
+		privateKey = config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("pbs.private_key", privateKey).(string)
+		if creds.Keys == nil {
+			creds.Keys = make(map[string]string)
+		}
+		creds.Keys["default"] = privateKey
+	}
+	creds.Token = strings.TrimSpace(locationProps.GetString("password"))
+	creds.Token = config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("pbs.password", creds.Token).(string)
+
+	return creds, nil
+}
+
+// checkLocationConfig checks PBS location mandatory configuration parameters :
+// - url (PBS client's node address)
+// - port (PBS client's node port)
+// returns error in case of inconsistent configuration, or nil if configuration ok
+func checkLocationConfig(locationProps config.DynamicMap) error {
+	if strings.TrimSpace(locationProps.GetString("url")) == "" {
+		return errors.New("PBS location url is not set")
+	}
+
+	if strings.TrimSpace(locationProps.GetString("port")) == "" {
+		return errors.New("PBS location port is not set")
+	}
+
+	return nil
+}
+
+// checkLocationUserConfig checks PBS location configuration parameters related to user credentials
+// necessary for connect using ssh to the PBS client's node
+// - user_name
+// - password or private_key
+// returns error in case of inconsistent configuration, or nil if configuration seems ok
+func checkLocationUserConfig(locationProps config.DynamicMap) error {
+	if strings.TrimSpace(locationProps.GetString("user_name")) == "" {
+		return errors.New("PBS location user_name is not set")
+	}
+
+	// Check an authentication method was specified
+	if strings.TrimSpace(locationProps.GetString("password")) == "" &&
+		strings.TrimSpace(locationProps.GetString("private_key")) == "" {
+		return errors.New("PBS location missing authentication details, password or private_key should be set")
+	}
+
+	return nil
+}
+
+// parseJobInfo parses the output of a "qstat -f" command
+//
+// Below is a classic example:
+// Job Id: 1234.pbsserver
+//
+//	    Job_Name = test
+//	    job_state = R
+//	    queue = workq
+//	    exec_host = node01/0*2
+//	    Variable_List = PBS_O_HOME=/home/john,PBS_O_LANG=en_US.UTF-8,
+//		PBS_O_LOGNAME=john
+//
+// Long values are split on several lines, continuation lines start with a tabulation.
+func parseJobInfo(r io.Reader) (map[string]string, error) {
+	data := make(map[string]string)
+	var lastKey string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.TrimSpace(line) == "":
+			continue
+		case strings.HasPrefix(line, "Job Id:"):
+			lastKey = "Job_Id"
+			data[lastKey] = strings.TrimSpace(strings.TrimPrefix(line, "Job Id:"))
+		case strings.HasPrefix(line, "\t") && lastKey != "":
+			data[lastKey] += strings.TrimSpace(line)
+		default:
+			kv := strings.SplitN(line, " = ", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			lastKey = strings.TrimSpace(kv[0])
+			data[lastKey] = strings.TrimSpace(kv[1])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "An error occurred scanning job info")
+	}
+	return data, nil
+}
+
+func getJobInfo(client sshutil.Client, jobID string) (map[string]string, error) {
+	// -x allows to retrieve information on finished jobs (within the server job history duration)
+	cmd := fmt.Sprintf("qstat -f -x %s", jobID)
+	output, err := client.RunCommand(cmd)
+	out := strings.Trim(output, "\" \t\n\x00")
+	if err != nil {
+		if strings.Contains(out, unknownJob) {
+			return nil, &noJobFound{msg: err.Error()}
+		}
+		return nil, errors.Wrap(err, out)
+	}
+	if out != "" {
+		return parseJobInfo(strings.NewReader(out))
+	}
+	return nil, &noJobFound{msg: fmt.Sprintf("no information found for job with id:%q", jobID)}
+}
+
+// jobState converts PBS job_state codes into a human readable job state
+//
+// Finished jobs are considered as COMPLETED if they exited with a 0 exit status and FAILED otherwise.
+func jobState(info map[string]string) string {
+	switch info["job_state"] {
+	case "Q":
+		return "QUEUED"
+	case "R":
+		return "RUNNING"
+	case "E":
+		return "EXITING"
+	case "H":
+		return "HELD"
+	case "W":
+		return "WAITING"
+	case "T":
+		return "TRANSITING"
+	case "B":
+		return "BEGUN"
+	case "S", "U":
+		return "SUSPENDED"
+	case "M":
+		return "MOVED"
+	case "X", "F":
+		if info["Exit_status"] == "0" {
+			return "COMPLETED"
+		}
+		return "FAILED"
+	}
+	return "UNKNOWN"
+}
+
+// isActiveJobState returns true if the given job state means that the job is still running
+// or that its state is about to be set definitively
+func isActiveJobState(state string) bool {
+	switch state {
+	case "QUEUED", "RUNNING", "EXITING", "HELD", "WAITING", "TRANSITING", "BEGUN":
+		return true
+	}
+	return false
+}
+
+// parseExecHost returns the first host of an exec_host value as "node01/0*2+node02/0*2"
+func parseExecHost(execHost string) string {
+	host := strings.SplitN(execHost, "+", 2)[0]
+	return strings.SplitN(host, "/", 2)[0]
+}
+
+// outputFilePath returns the path of an Output_Path or Error_Path value as "hostname:/path/to/file"
+func outputFilePath(p string) string {
+	if i := strings.Index(p, ":"); i >= 0 {
+		return p[i+1:]
+	}
+	return p
+}
+
+func cancelJobID(jobID string, client sshutil.Client) error {
+	qdelCmd := fmt.Sprintf("qdel %s", jobID)
+	qdelOutput, err := client.RunCommand(qdelCmd)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to cancel PBS job: %s:", qdelOutput)
+	}
+	return nil
+}
+
+func retrieveJobID(out string) (string, error) {
+	// expected: "4507.pbsserver"
+	// Some sites may print information lines before the job ID so only consider the last line
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	last := strings.TrimSpace(lines[len(lines)-1])
+	subMatch := regexp.MustCompile(reQsub).FindStringSubmatch(last)
+	if len(subMatch) != 2 {
+		return "", errors.Errorf("Unable to parse Job ID from stdout:%q", out)
+	}
+	return subMatch[1], nil
+}
+
+func parseKeyValue(str string) (bool, string, string) {
+	keyVal := strings.SplitN(str, "=", 2)
+	if len(keyVal) == 2 && strings.TrimSpace(keyVal[0]) != "" && strings.TrimSpace(keyVal[1]) != "" {
+		return true, keyVal[0], keyVal[1]
+	}
+	return false, "", ""
+}
+
+func quoteArgs(t []string) string {
+	var args string
+	for _, v := range t {
+		if !strings.HasPrefix(v, "'") && !strings.HasSuffix(v, "'") {
+			v = strings.Replace(v, "'", "\"", -1)
+			v = "'" + v + "'"
+		}
+		args += v + " "
+	}
+	return args
+}
+
+// Convert scalar-unit size to kilobytes as kb for PBS
+func toPBSMemFormat(memStr string) (string, error) {
+	mem, err := humanize.ParseBytes(memStr)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to convert to PBS memory format value:%q", memStr)
+	}
+
+	return strconv.FormatUint(mem/1024, 10) + "kb", nil
+}
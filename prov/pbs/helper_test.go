@@ -0,0 +1,141 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pbs
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/helper/sshutil"
+)
+
+func TestParseJobInfo(t *testing.T) {
+	t.Parallel()
+	data, err := os.Open("testdata/qstat_running.txt")
+	require.Nil(t, err, "unexpected error while opening test file")
+	info, err := parseJobInfo(data)
+	require.Nil(t, err, "unexpected error while parsing job info")
+	require.Equal(t, "6260.pbsserver", info["Job_Id"], "unexpected value for \"Job_Id\" key")
+	require.Equal(t, "test-job", info["Job_Name"], "unexpected value for \"Job_Name\" key")
+	require.Equal(t, "R", info["job_state"], "unexpected value for \"job_state\" key")
+	require.Equal(t, "node01/0*2+node02/0*2", info["exec_host"], "unexpected value for \"exec_host\" key")
+	require.Equal(t, "00:01:12", info["resources_used.walltime"], "unexpected value for \"resources_used.walltime\" key")
+	require.Equal(t, "PBS_O_HOME=/home/john,PBS_O_LANG=en_US.UTF-8,PBS_O_LOGNAME=john,PBS_O_WORKDIR=/home/john", info["Variable_List"], "unexpected value for multi-lines \"Variable_List\" key")
+	require.Equal(t, "node01", parseExecHost(info["exec_host"]))
+	require.Equal(t, "/home/john/test-job.o6260", outputFilePath(info["Output_Path"]))
+}
+
+func TestJobState(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		info   map[string]string
+		want   string
+		active bool
+	}{
+		{"Queued", map[string]string{"job_state": "Q"}, "QUEUED", true},
+		{"Running", map[string]string{"job_state": "R"}, "RUNNING", true},
+		{"Held", map[string]string{"job_state": "H"}, "HELD", true},
+		{"Completed", map[string]string{"job_state": "F", "Exit_status": "0"}, "COMPLETED", false},
+		{"Failed", map[string]string{"job_state": "F", "Exit_status": "1"}, "FAILED", false},
+		{"Suspended", map[string]string{"job_state": "S"}, "SUSPENDED", false},
+		{"Unknown", map[string]string{}, "UNKNOWN", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := jobState(tt.info)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.active, isActiveJobState(got))
+		})
+	}
+}
+
+func TestRetrieveJobID(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		out     string
+		want    string
+		wantErr bool
+	}{
+		{"SimpleID", "4507.pbsserver\n", "4507.pbsserver", false},
+		{"ShortID", "4507", "4507", false},
+		{"ArrayID", "4507[].pbs-server.example.com", "4507[].pbs-server.example.com", false},
+		{"WithInterferenceLogs", "Welcome on the cluster\n4507.pbsserver", "4507.pbsserver", false},
+		{"Error", "qsub: Unknown queue", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := retrieveJobID(tt.out)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("retrieveJobID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetJobInfo(t *testing.T) {
+	t.Parallel()
+	s := &sshutil.MockSSHClient{
+		MockRunCommand: func(cmd string) (string, error) {
+			require.Equal(t, "qstat -f -x 6260.pbsserver", cmd)
+			content, err := ioutil.ReadFile("testdata/qstat_finished.txt")
+			require.Nil(t, err, "Unexpected error reading qstat")
+			return string(content), nil
+		},
+	}
+	info, err := getJobInfo(s, "6260.pbsserver")
+	require.Nil(t, err, "Unexpected error retrieving job info")
+	require.Equal(t, "COMPLETED", jobState(info))
+}
+
+func TestGetJobInfoWithUnknownJob(t *testing.T) {
+	t.Parallel()
+	s := &sshutil.MockSSHClient{
+		MockRunCommand: func(cmd string) (string, error) {
+			return "qstat: Unknown Job Id 1234.pbsserver", errors.New("exit status 153")
+		},
+	}
+	info, err := getJobInfo(s, "1234.pbsserver")
+	require.Nil(t, info, "info should be nil")
+	require.Equal(t, true, isNoJobFoundError(err), "expected no job found error")
+}
+
+func TestGetJobInfoWithError(t *testing.T) {
+	t.Parallel()
+	s := &sshutil.MockSSHClient{
+		MockRunCommand: func(cmd string) (string, error) {
+			return "oups, it's bad", errors.New("this is an error !")
+		},
+	}
+	info, err := getJobInfo(s, "1234")
+	require.Nil(t, info, "info should be nil")
+	require.Equal(t, "oups, it's bad: this is an error !", err.Error(), "expected error")
+}
+
+func TestToPBSMemFormat(t *testing.T) {
+	t.Parallel()
+	got, err := toPBSMemFormat("2 GiB")
+	require.NoError(t, err)
+	require.Equal(t, "2097152kb", got)
+	_, err = toPBSMemFormat("not a size")
+	require.Error(t, err)
+}
@@ -0,0 +1,274 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pbs
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/helper/sshutil"
+	"github.com/ystia/yorc/v4/locations"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov"
+	"github.com/ystia/yorc/v4/prov/scheduling"
+)
+
+const bashLogger = `
+if [ -f %s ]; then
+    tail -n +%d %s
+fi
+
+`
+
+// jobAttributes are the qstat keys exposed as job instance attributes
+var jobAttributes = []string{"Job_Name", "job_state", "queue", "exec_host", "Exit_status", "resources_used.walltime", "resources_used.cput", "resources_used.mem", "comment"}
+
+type actionOperator struct {
+}
+
+type actionData struct {
+	stepName   string
+	jobID      string
+	taskID     string
+	workingDir string
+	artifacts  []string
+}
+
+func (o *actionOperator) ExecAction(ctx context.Context, cfg config.Configuration, taskID, deploymentID string, action *prov.Action) (bool, error) {
+	log.Debugf("Execute Action with ID:%q, taskID:%q, deploymentID:%q", action.ID, taskID, deploymentID)
+
+	if action.ActionType == jobMonitoringActionType {
+		deregister, err := o.monitorJob(ctx, cfg, deploymentID, action)
+		if err != nil {
+			// action scheduling needs to be unregistered
+			return true, err
+		}
+
+		return deregister, nil
+	}
+	return true, errors.Errorf("Unsupported actionType %q", action.ActionType)
+}
+
+func (o *actionOperator) updateJobAttributes(ctx context.Context, deploymentID, nodeName, instanceName string, jobInfo map[string]string) error {
+	for _, k := range jobAttributes {
+		v, ok := jobInfo[k]
+		if !ok {
+			continue
+		}
+		value, err := deployments.GetInstanceAttributeValue(ctx, deploymentID, nodeName, instanceName, k)
+		if err != nil {
+			return err
+		}
+		if value == nil || value.RawString() != v {
+			err = deployments.SetInstanceAttribute(ctx, deploymentID, nodeName, instanceName, k, v)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func getMonitoringJobActionData(action *prov.Action) (*actionData, error) {
+	var ok bool
+
+	actionData := &actionData{}
+	actionData.jobID, ok = action.Data["jobID"]
+	if !ok {
+		return nil, errors.Errorf("Missing mandatory information jobID for actionType:%q", action.ActionType)
+	}
+	actionData.stepName, ok = action.Data["stepName"]
+	if !ok {
+		return nil, errors.Errorf("Missing mandatory information stepName for actionType:%q", action.ActionType)
+	}
+	actionData.workingDir, ok = action.Data["workingDir"]
+	if !ok {
+		return nil, errors.Errorf("Missing mandatory information workingDir for actionType:%q", action.ActionType)
+	}
+	actionData.taskID, ok = action.Data["taskID"]
+	if !ok {
+		return nil, errors.Errorf("Missing mandatory information taskID for actionType:%q", action.ActionType)
+	}
+	// Check artifacts (optional)
+	artifactsStr, ok := action.Data["artifacts"]
+	if ok {
+		actionData.artifacts = strings.Split(artifactsStr, ",")
+	}
+
+	return actionData, nil
+}
+
+func (o *actionOperator) analyzeJob(ctx context.Context, cc *api.Client, sshClient sshutil.Client, deploymentID, nodeName string, action *prov.Action, keepArtifacts bool) (bool, error) {
+	var (
+		err        error
+		deregister bool
+	)
+
+	actionData, err := getMonitoringJobActionData(action)
+	if err != nil {
+		return true, err
+	}
+
+	info, err := getJobInfo(sshClient, actionData.jobID)
+
+	instanceName := "0"
+
+	if err != nil {
+		if isNoJobFoundError(err) {
+			// the job is not found in PBS history (should have been purged) : pass its status to "UNKNOWN"
+			deployments.SetInstanceStateStringWithContextualLogs(ctx, deploymentID, nodeName, instanceName, "UNKNOWN")
+		}
+		return true, errors.Wrapf(err, "failed to get job info with jobID:%q", actionData.jobID)
+	}
+	err = o.updateJobAttributes(ctx, deploymentID, nodeName, instanceName, info)
+	if err != nil {
+		return true, errors.Wrapf(err, "failed to update job attributes with jobID: %q", actionData.jobID)
+	}
+
+	state := jobState(info)
+	mess := fmt.Sprintf("Job Name:%s, ID:%s, State:%s, Execution Time:%s", info["Job_Name"], actionData.jobID, state, info["resources_used.walltime"])
+	if info["comment"] != "" {
+		mess += fmt.Sprintf(", Comment:%s", info["comment"])
+	}
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).RegisterAsString(mess)
+
+	if state != "QUEUED" {
+		stdOut := outputFilePath(info["Output_Path"])
+		stdErr := outputFilePath(info["Error_Path"])
+		if info["Join_Path"] == "oe" || stdOut == stdErr {
+			o.logFile(ctx, cc, action, deploymentID, stdOut, "StdOut/StdErr", sshClient)
+		} else {
+			if stdOut != "" {
+				o.logFile(ctx, cc, action, deploymentID, stdOut, "StdOut", sshClient)
+			}
+			if stdErr != "" {
+				o.logFile(ctx, cc, action, deploymentID, stdErr, "StdErr", sshClient)
+			}
+		}
+	}
+
+	previousJobState, err := deployments.GetInstanceStateString(ctx, deploymentID, nodeName, instanceName)
+	if err != nil {
+		return true, errors.Wrapf(err, "failed to get instance state for job %q", actionData.jobID)
+	}
+	if previousJobState != state {
+		deployments.SetInstanceStateStringWithContextualLogs(ctx, deploymentID, nodeName, instanceName, state)
+	}
+
+	// See if monitoring must be continued and set job state if terminated
+	switch {
+	case state == "COMPLETED":
+		// job has been done successfully : unregister monitoring
+		deregister = true
+	case isActiveJobState(state):
+		// job's still running or its state is about to be set definitively: monitoring is keeping on (deregister stays false)
+	default:
+		// Other cases as FAILED, SUSPENDED, etc : error is return with job state and job info is logged
+		deregister = true
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, deploymentID).RegisterAsString(fmt.Sprintf("job info:%+v", info))
+		err = errors.Errorf("job with ID:%q finished unsuccessfully with state:%q (exit status: %q)", actionData.jobID, state, info["Exit_status"])
+	}
+
+	// cleanup except if error occurred or explicitly specified in config
+	if deregister && err == nil && !keepArtifacts {
+		o.removeArtifacts(actionData, sshClient)
+	}
+	return deregister, err
+}
+
+func (o *actionOperator) monitorJob(ctx context.Context, cfg config.Configuration, deploymentID string, action *prov.Action) (bool, error) {
+	nodeName := action.Data["nodeName"]
+
+	var locationProps config.DynamicMap
+	locationMgr, err := locations.GetManager(cfg)
+	if err == nil {
+		locationProps, err = locationMgr.GetLocationPropertiesForNode(ctx, deploymentID, nodeName, infrastructureType)
+	}
+	if err != nil {
+		return true, err
+	}
+
+	credentials, err := getUserCredentials(ctx, locationProps, deploymentID, nodeName, "")
+	if err != nil {
+		return true, err
+	}
+	// Get a sshClient to connect to PBS client node, and execute PBS commands such as qstat
+	sshClient, err := getSSHClient(cfg, credentials, locationProps)
+	if err != nil {
+		return true, err
+	}
+
+	cc, err := cfg.GetConsulClient()
+	if err != nil {
+		return true, err
+	}
+
+	return o.analyzeJob(ctx, cc, sshClient, deploymentID, nodeName, action, locationProps.GetBool("keep_job_remote_artifacts"))
+}
+
+func (o *actionOperator) removeArtifacts(actionData *actionData, sshClient sshutil.Client) {
+	for _, art := range actionData.artifacts {
+		if art != "" {
+			p := path.Join(actionData.workingDir, art)
+			log.Debugf("Remove artifact %q", p)
+			_, err := sshClient.RunCommand(fmt.Sprintf("rm -rf %s", p))
+			if err != nil {
+				log.Printf("an error:%+v occurred during removing artifact %q", err, p)
+			}
+		}
+	}
+}
+
+func (o *actionOperator) logFile(ctx context.Context, cc *api.Client, action *prov.Action, deploymentID, filePath, fileType string, sshClient sshutil.Client) {
+	fileTypeKey := fmt.Sprintf("lastIndex%s", strings.Replace(fileType, "/", "", -1))
+	lastInd, err := o.getLogLastIndex(action, fileTypeKey)
+	if err != nil {
+		log.Debugf("fail to get log last index for log file (%s)due to error:%+v:", filePath, err)
+		return
+	}
+
+	cmd := fmt.Sprintf(bashLogger, filePath, lastInd+1, filePath)
+	output, err := sshClient.RunCommand(cmd)
+	if err != nil {
+		log.Debugf("fail to log file (%s)due to error:%+v:", filePath, err)
+		return
+	}
+	if strings.TrimSpace(output) != "" {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).RegisterAsString(fmt.Sprintf("%s %s:\n%s", fileType, filePath, output))
+	}
+
+	newInd := strconv.Itoa(lastInd + strings.Count(output, "\n"))
+	err = scheduling.UpdateActionData(cc, action.ID, fileTypeKey, newInd)
+	if err != nil {
+		log.Debugf("fail to update action data due to error:%+v:", err)
+	}
+}
+
+func (o *actionOperator) getLogLastIndex(action *prov.Action, fileTypeKey string) (int, error) {
+	lastIndex, ok := action.Data[fileTypeKey]
+	if !ok {
+		return 0, nil
+	}
+	return strconv.Atoi(lastIndex)
+}
@@ -0,0 +1,148 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pbs
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/sshutil"
+	"github.com/ystia/yorc/v4/prov"
+	"github.com/ystia/yorc/v4/testutil"
+)
+
+func testActionOperatorAnalyzeJob(t *testing.T, cfg config.Configuration) {
+
+	deploymentID := testutil.BuildDeploymentID(t)
+	ctx := context.Background()
+	err := deployments.StoreDeploymentDefinition(ctx, deploymentID, "testdata/jobMonitoringTest.yaml")
+	require.NoError(t, err)
+
+	cc, err := cfg.GetConsulClient()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		jobInfoFile string
+		want        bool
+		wantErr     bool
+		wantState   string
+	}{
+		{"MonitorQueuedJob", "qstat_queued.txt", false, false, "QUEUED"},
+		{"MonitorRunningJob", "qstat_running.txt", false, false, "RUNNING"},
+		{"MonitorCompletedJob", "qstat_finished.txt", true, false, "COMPLETED"},
+		{"MonitorFailedJob", "qstat_failed.txt", true, true, "FAILED"},
+		{"JobNotFound", "", true, true, "UNKNOWN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &actionOperator{}
+			action := &prov.Action{ActionType: jobMonitoringActionType, Data: map[string]string{
+				"nodeName":   "Job",
+				"jobID":      "6260.pbsserver",
+				"stepName":   "run",
+				"taskID":     "t1",
+				"workingDir": filepath.Join(cfg.WorkingDirectory, t.Name()),
+			}}
+
+			sshClient := &sshutil.MockSSHClient{
+				MockRunCommand: func(input string) (string, error) {
+					if tt.jobInfoFile == "" {
+						return "qstat: Unknown Job Id 6260.pbsserver", errors.New("exit status 153")
+					}
+					if input != "qstat -f -x 6260.pbsserver" {
+						// logs retrieval and artifacts cleanup
+						return "", nil
+					}
+					content, err := ioutil.ReadFile(filepath.Join("testdata", tt.jobInfoFile))
+					require.NoError(t, err)
+					return string(content), nil
+				},
+			}
+
+			got, err := o.analyzeJob(ctx, cc, sshClient, deploymentID, "Job", action, false)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("actionOperator.analyzeJob() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("actionOperator.analyzeJob() = %v, want %v", got, tt.want)
+			}
+			state, err := deployments.GetInstanceStateString(ctx, deploymentID, "Job", "0")
+			require.NoError(t, err)
+			require.Equal(t, tt.wantState, state)
+		})
+	}
+}
+
+func Test_getMonitoringJobActionData(t *testing.T) {
+	type args struct {
+		action *prov.Action
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *actionData
+		wantErr bool
+	}{
+		{"MissingJobID", args{&prov.Action{Data: map[string]string{
+			"stepName":   "s1",
+			"workingDir": "~",
+			"taskID":     "t1",
+		}}}, nil, true},
+		{"MissingStepName", args{&prov.Action{Data: map[string]string{
+			"jobID":      "1",
+			"workingDir": "~",
+			"taskID":     "t1",
+		}}}, nil, true},
+		{"MissingWorkingDir", args{&prov.Action{Data: map[string]string{
+			"jobID":    "1",
+			"stepName": "s1",
+			"taskID":   "t1",
+		}}}, nil, true},
+		{"MissingTaskID", args{&prov.Action{Data: map[string]string{
+			"jobID":      "1",
+			"stepName":   "s1",
+			"workingDir": "~",
+		}}}, nil, true},
+		{"WithArtifacts", args{&prov.Action{Data: map[string]string{
+			"jobID":      "1.pbsserver",
+			"stepName":   "s1",
+			"workingDir": "~",
+			"taskID":     "t1",
+			"artifacts":  "b1,a2",
+		}}}, &actionData{jobID: "1.pbsserver", stepName: "s1", workingDir: "~", taskID: "t1", artifacts: []string{"b1", "a2"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getMonitoringJobActionData(tt.args.action)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getMonitoringJobActionData() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getMonitoringJobActionData() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pbs
+
+import (
+	"time"
+
+	"github.com/ystia/yorc/v4/tosca/types"
+)
+
+type infrastructure struct {
+	nodes []*nodeAllocation
+}
+
+type nodeAllocation struct {
+	cpu          string
+	memory       string
+	resources    []string
+	queue        string
+	jobName      string
+	walltime     string
+	credentials  *types.Credential
+	instanceName string
+	account      string
+}
+
+type jobInfo struct {
+	ID                     string                    `json:"id,omitempty"`
+	Name                   string                    `json:"name,omitempty"`
+	Chunks                 int                       `json:"chunks,omitempty"`
+	Cpus                   int                       `json:"cpus,omitempty"`
+	MPIProcs               int                       `json:"mpi_procs,omitempty"`
+	Mem                    string                    `json:"mem,omitempty"`
+	Walltime               string                    `json:"walltime,omitempty"`
+	Queue                  string                    `json:"queue,omitempty"`
+	Opts                   []string                  `json:"opts,omitempty"`
+	ExecutionOptions       types.PBSExecutionOptions `json:"execution_options,omitempty"`
+	Inputs                 map[string]string         `json:"inputs,omitempty"`
+	MonitoringTimeInterval time.Duration             `json:"monitoring_time_interval,omitempty"`
+	Account                string                    `json:"account,omitempty"`
+	WorkingDir             string                    `json:"working_directory,omitempty"`
+	Artifacts              []string                  `json:"artifacts,omitempty"`
+	EnvFile                string                    `json:"env_file,omitempty"`
+}
@@ -71,5 +71,14 @@ func TestRunConsulSchedulingPackageTests(t *testing.T) {
 		t.Run("testUnregisterAction", func(t *testing.T) {
 			testUnregisterAction(t, client)
 		})
+		t.Run("testSchedulesStorage", func(t *testing.T) {
+			testSchedulesStorage(t, client)
+		})
+		t.Run("testScheduleRunnerOverlapPolicies", func(t *testing.T) {
+			testScheduleRunnerOverlapPolicies(t, client)
+		})
+		t.Run("testOneShotSchedule", func(t *testing.T) {
+			testOneShotSchedule(t, client)
+		})
 	})
 }
@@ -0,0 +1,339 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/helper/metricsutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/scheduling"
+	"github.com/ystia/yorc/v4/tasks"
+	"github.com/ystia/yorc/v4/tasks/collector"
+)
+
+// pendingCheckInterval is the delay between two checks of the end of the previous task of a schedule having a pending run
+var pendingCheckInterval = 10 * time.Second
+
+// scheduleRunner registers the tasks of a schedule when it triggers
+type scheduleRunner struct {
+	scheduling.Schedule
+	collector *collector.Collector
+
+	latestTaskID string
+	// triggered is true once a one-shot schedule has been triggered, it is also stored in Consul
+	// before the task of the schedule is registered
+	triggered bool
+	// pending is true when a run is waiting for the previous task to end
+	pending bool
+
+	stopScheduling     bool
+	stopSchedulingLock sync.Mutex
+	chStop             chan struct{}
+}
+
+func newScheduleRunner(s *scheduling.Schedule, c *collector.Collector) *scheduleRunner {
+	return &scheduleRunner{Schedule: *s, collector: c}
+}
+
+func (sr *scheduleRunner) start() {
+	sr.stopSchedulingLock.Lock()
+	defer sr.stopSchedulingLock.Unlock()
+
+	sr.chStop = make(chan struct{})
+	sr.stopScheduling = false
+	go sr.schedule()
+}
+
+func (sr *scheduleRunner) stop() {
+	sr.stopSchedulingLock.Lock()
+	defer sr.stopSchedulingLock.Unlock()
+
+	if !sr.stopScheduling {
+		sr.stopScheduling = true
+		close(sr.chStop)
+	}
+}
+
+func (sr *scheduleRunner) metricsLabels() []metrics.Label {
+	return []metrics.Label{
+		metrics.Label{Name: "Deployment", Value: sr.DeploymentID},
+		metrics.Label{Name: "ScheduleID", Value: sr.ID},
+	}
+}
+
+func (sr *scheduleRunner) logf(ctx context.Context, level events.LogLevel, format string, args ...interface{}) {
+	events.WithContextOptionalFields(ctx).NewLogEntry(level, sr.DeploymentID).Registerf("Schedule %q: "+format, append([]interface{}{sr.ID}, args...)...)
+}
+
+func (sr *scheduleRunner) schedule() {
+	log.Debugf("Starting schedule %q of deployment %q", sr.ID, sr.DeploymentID)
+	ctx := context.Background()
+	var err error
+	sr.latestTaskID, err = scheduling.GetScheduleLatestTaskID(ctx, sr.DeploymentID, sr.ID)
+	if err != nil {
+		log.Printf("[WARNING] Failed to get latest task of schedule %q: %+v", sr.ID, err)
+	}
+	if sr.IsOneShot() {
+		sr.triggered, err = scheduling.IsScheduleTriggered(ctx, sr.DeploymentID, sr.ID)
+		if err != nil {
+			sr.logf(ctx, events.LogLevelERROR, "will not run as its state can't be checked: %v", err)
+			return
+		}
+	}
+	for {
+		var chTrigger, chPending <-chan time.Time
+		var timer *time.Timer
+		if !sr.triggered {
+			next, err := sr.NextRun(time.Now())
+			if err != nil {
+				sr.logf(ctx, events.LogLevelERROR, "will not run anymore: %v", err)
+				return
+			}
+			if !next.IsZero() {
+				timer = time.NewTimer(time.Until(next))
+				chTrigger = timer.C
+			}
+		}
+		if sr.pending {
+			chPending = time.After(pendingCheckInterval)
+		}
+		if chTrigger == nil && chPending == nil {
+			if sr.IsOneShot() {
+				err = scheduling.DeleteSchedule(ctx, sr.DeploymentID, sr.ID)
+				if err != nil {
+					log.Printf("[WARNING] Failed to delete one-shot schedule %q: %+v", sr.ID, err)
+				}
+			}
+			log.Debugf("Schedule %q of deployment %q will not run anymore", sr.ID, sr.DeploymentID)
+			return
+		}
+
+		select {
+		case <-sr.chStop:
+			log.Debugf("Stop schedule %q of deployment %q", sr.ID, sr.DeploymentID)
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case <-chTrigger:
+			sr.trigger(ctx)
+		case <-chPending:
+			if timer != nil {
+				timer.Stop()
+			}
+			sr.runPending(ctx)
+		}
+	}
+}
+
+// trigger applies the overlap policy of the schedule if its previous task is still in progress or registers a new task
+func (sr *scheduleRunner) trigger(ctx context.Context) {
+	sr.triggered = sr.IsOneShot()
+	labels := sr.metricsLabels()
+	metrics.IncrCounterWithLabels(metricsutil.CleanupMetricKey([]string{"scheduling", "schedules", "ticks"}), 1, labels)
+	running, err := sr.isLatestTaskRunning()
+	if err != nil {
+		sr.logf(ctx, events.LogLevelERROR, "failed to check the status of the previous task %q: %v", sr.latestTaskID, err)
+		return
+	}
+	if running {
+		switch sr.OverlapPolicy {
+		case scheduling.OverlapPolicyQueue:
+			sr.logf(ctx, events.LogLevelINFO, "previous task %q is still in progress, this run is queued until it ends", sr.latestTaskID)
+			sr.pending = true
+		case scheduling.OverlapPolicyReplace:
+			sr.logf(ctx, events.LogLevelINFO, "previous task %q is still in progress, it is canceled and replaced by this run", sr.latestTaskID)
+			err = tasks.CancelTask(sr.latestTaskID)
+			if err != nil {
+				sr.logf(ctx, events.LogLevelERROR, "failed to cancel previous task %q: %v", sr.latestTaskID, err)
+				return
+			}
+			sr.pending = true
+		default:
+			sr.logf(ctx, events.LogLevelINFO, "previous task %q is still in progress, this run is skipped", sr.latestTaskID)
+			metrics.IncrCounterWithLabels(metricsutil.CleanupMetricKey([]string{"scheduling", "schedules", "misses"}), 1, labels)
+			if err = sr.storeTriggered(ctx); err != nil {
+				log.Printf("[WARNING] %+v", err)
+			}
+		}
+		return
+	}
+	sr.pending = true
+	sr.runPending(ctx)
+}
+
+// runPending registers the task of a pending run once the previous task is over
+func (sr *scheduleRunner) runPending(ctx context.Context) {
+	running, err := sr.isLatestTaskRunning()
+	if err != nil {
+		sr.logf(ctx, events.LogLevelERROR, "failed to check the status of the previous task %q: %v", sr.latestTaskID, err)
+		return
+	}
+	if running {
+		return
+	}
+	err = sr.storeTriggered(ctx)
+	if err != nil {
+		// Retry later rather than risking to run a one-shot schedule twice
+		sr.logf(ctx, events.LogLevelERROR, "%v", err)
+		return
+	}
+	taskID, err := sr.registerTask(ctx)
+	if err != nil {
+		if ok, _ := tasks.IsAnotherLivingTaskAlreadyExistsError(err); ok && sr.OverlapPolicy != scheduling.OverlapPolicySkip {
+			// Wait for the other task to end
+			log.Debugf("Schedule %q of deployment %q is waiting for another task to end: %v", sr.ID, sr.DeploymentID, err)
+			return
+		}
+		sr.pending = false
+		sr.logf(ctx, events.LogLevelERROR, "failed to register a new %s task: %v", sr.TaskType.String(), err)
+		metrics.IncrCounterWithLabels(metricsutil.CleanupMetricKey([]string{"scheduling", "schedules", "failures"}), 1, sr.metricsLabels())
+		return
+	}
+	sr.pending = false
+	sr.latestTaskID = taskID
+	err = scheduling.SetScheduleLatestTaskID(ctx, sr.DeploymentID, sr.ID, taskID)
+	if err != nil {
+		log.Printf("[WARNING] Failed to store latest task of schedule %q: %+v", sr.ID, err)
+	}
+	sr.logf(ctx, events.LogLevelINFO, "registered %s task %q", sr.TaskType.String(), taskID)
+}
+
+// storeTriggered flags a one-shot schedule as triggered in Consul
+func (sr *scheduleRunner) storeTriggered(ctx context.Context) error {
+	if !sr.IsOneShot() {
+		return nil
+	}
+	return errors.Wrap(scheduling.SetScheduleTriggered(ctx, sr.DeploymentID, sr.ID), "failed to flag one-shot schedule as triggered")
+}
+
+func (sr *scheduleRunner) isLatestTaskRunning() (bool, error) {
+	if sr.latestTaskID == "" {
+		return false, nil
+	}
+	status, err := tasks.GetTaskStatus(sr.latestTaskID)
+	if tasks.IsTaskNotFoundError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return status == tasks.TaskStatusINITIAL || status == tasks.TaskStatusRUNNING || status == tasks.TaskStatusPENDINGAPPROVAL, nil
+}
+
+func (sr *scheduleRunner) registerTask(ctx context.Context) (string, error) {
+	hasBlocking, err := deployments.HasBlockingOperationOnDeploymentFlag(ctx, sr.DeploymentID)
+	if err != nil {
+		return "", err
+	}
+	if hasBlocking {
+		return "", errors.Errorf("deployment %q is currently processing a blocking operation", sr.DeploymentID)
+	}
+	data, err := sr.taskData(ctx)
+	if err != nil {
+		return "", err
+	}
+	return sr.collector.RegisterTaskWithData(sr.DeploymentID, sr.TaskType, data)
+}
+
+// taskData computes the data of the task to register
+//
+// Some data depends on the deployment state at the time the task is registered: node instances of custom commands
+// without instances selection and instances to remove on a scale in.
+func (sr *scheduleRunner) taskData(ctx context.Context) (map[string]string, error) {
+	data := make(map[string]string, len(sr.Data))
+	for k, v := range sr.Data {
+		data[k] = v
+	}
+	switch sr.TaskType {
+	case tasks.TaskTypeCustomWorkflow:
+		err := deployments.CheckWorkflowPreconditions(ctx, sr.DeploymentID, data["workflowName"])
+		return data, err
+	case tasks.TaskTypeCustomCommand:
+		for k, v := range data {
+			if !strings.HasPrefix(k, "nodes/") || v != "" {
+				continue
+			}
+			instances, err := deployments.GetNodeInstancesIds(ctx, sr.DeploymentID, path.Base(k))
+			if err != nil {
+				return nil, err
+			}
+			data[k] = strings.Join(instances, ",")
+		}
+		return data, nil
+	case tasks.TaskTypeScaleOut:
+		return sr.scaleOutData(ctx, data)
+	case tasks.TaskTypeScaleIn:
+		return sr.scaleInData(ctx, data)
+	case tasks.TaskTypeUnDeploy:
+		status, err := deployments.GetDeploymentStatus(ctx, sr.DeploymentID)
+		if err != nil {
+			return nil, err
+		}
+		if status == deployments.UNDEPLOYED {
+			return nil, errors.New("deployment already undeployed")
+		}
+		return data, nil
+	}
+	return data, nil
+}
+
+func (sr *scheduleRunner) scaleOutData(ctx context.Context, data map[string]string) (map[string]string, error) {
+	nodeName := data["nodeName"]
+	delta, err := strconv.Atoi(data["instancesDelta"])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid instances delta %q", data["instancesDelta"])
+	}
+	instancesDelta, err := deployments.GetScaleOutInstancesDelta(ctx, sr.DeploymentID, nodeName, uint32(delta))
+	if err != nil {
+		return nil, err
+	}
+	data["instancesDelta"] = strconv.Itoa(int(instancesDelta))
+	data["workflowName"] = "install"
+	return data, nil
+}
+
+func (sr *scheduleRunner) scaleInData(ctx context.Context, data map[string]string) (map[string]string, error) {
+	nodeName := data["nodeName"]
+	delta, err := strconv.Atoi(data["instancesDelta"])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid instances delta %q", data["instancesDelta"])
+	}
+	instancesDelta, err := deployments.GetScaleInInstancesDelta(ctx, sr.DeploymentID, nodeName, uint32(delta))
+	if err != nil {
+		return nil, err
+	}
+	instancesByNodes, err := deployments.SelectNodeStackInstances(ctx, sr.DeploymentID, nodeName, int(instancesDelta))
+	if err != nil {
+		return nil, err
+	}
+	scaleData := make(map[string]string)
+	for scalableNode, nodeInstances := range instancesByNodes {
+		scaleData[path.Join("nodes", scalableNode)] = nodeInstances
+	}
+	scaleData["workflowName"] = "uninstall"
+	return scaleData, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"path"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/prov/scheduling"
+	"github.com/ystia/yorc/v4/tasks"
+)
+
+func testSchedulesStorage(t *testing.T, client *api.Client) {
+	t.Parallel()
+	ctx := context.Background()
+	deploymentID := "dep-" + t.Name()
+
+	_, err := scheduling.CreateSchedule(ctx, &scheduling.Schedule{DeploymentID: deploymentID, TaskType: tasks.TaskTypeCustomWorkflow})
+	require.Error(t, err, "a schedule without cron expression nor date should not be created")
+
+	id1, err := scheduling.CreateSchedule(ctx, &scheduling.Schedule{DeploymentID: deploymentID, Cron: "0 2 * * *", TaskType: tasks.TaskTypeCustomWorkflow, Data: map[string]string{"workflowName": "backup"}})
+	require.NoError(t, err)
+	at := time.Now().Add(24 * time.Hour)
+	id2, err := scheduling.CreateSchedule(ctx, &scheduling.Schedule{DeploymentID: deploymentID, At: &at, TaskType: tasks.TaskTypeUnDeploy, OverlapPolicy: scheduling.OverlapPolicyQueue})
+	require.NoError(t, err)
+
+	s, err := scheduling.GetSchedule(ctx, deploymentID, id1)
+	require.NoError(t, err)
+	require.NotNil(t, s)
+	require.Equal(t, "0 2 * * *", s.Cron)
+	require.Equal(t, "backup", s.Data["workflowName"])
+
+	schedules, err := scheduling.ListSchedules(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Len(t, schedules, 2)
+	require.Equal(t, id1, schedules[0].ID)
+	require.Equal(t, id2, schedules[1].ID)
+	require.Equal(t, scheduling.OverlapPolicyQueue, schedules[1].OverlapPolicy)
+
+	err = scheduling.SetScheduleLatestTaskID(ctx, deploymentID, id1, "task1")
+	require.NoError(t, err)
+	taskID, err := scheduling.GetScheduleLatestTaskID(ctx, deploymentID, id1)
+	require.NoError(t, err)
+	require.Equal(t, "task1", taskID)
+
+	triggered, err := scheduling.IsScheduleTriggered(ctx, deploymentID, id2)
+	require.NoError(t, err)
+	require.False(t, triggered)
+	err = scheduling.SetScheduleTriggered(ctx, deploymentID, id2)
+	require.NoError(t, err)
+	triggered, err = scheduling.IsScheduleTriggered(ctx, deploymentID, id2)
+	require.NoError(t, err)
+	require.True(t, triggered)
+
+	err = scheduling.DeleteSchedule(ctx, deploymentID, id1)
+	require.NoError(t, err)
+	s, err = scheduling.GetSchedule(ctx, deploymentID, id1)
+	require.NoError(t, err)
+	require.Nil(t, s)
+
+	err = scheduling.DeleteDeploymentSchedules(ctx, deploymentID)
+	require.NoError(t, err)
+	schedules, err = scheduling.ListSchedules(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Len(t, schedules, 0)
+}
+
+func testScheduleRunnerOverlapPolicies(t *testing.T, client *api.Client) {
+	t.Parallel()
+	ctx := context.Background()
+	deploymentID := "dep-" + t.Name()
+
+	tests := []struct {
+		name          string
+		policy        scheduling.OverlapPolicy
+		wantPending   bool
+		wantCancelled bool
+	}{
+		{"Skip", scheduling.OverlapPolicySkip, false, false},
+		{"Queue", scheduling.OverlapPolicyQueue, true, false},
+		{"Replace", scheduling.OverlapPolicyReplace, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runningTaskID := "task-" + t.Name()
+			_, err := client.KV().Put(&api.KVPair{Key: path.Join(consulutil.TasksPrefix, runningTaskID, "status"), Value: []byte(strconv.Itoa(int(tasks.TaskStatusRUNNING)))}, nil)
+			require.NoError(t, err)
+
+			sr := newScheduleRunner(&scheduling.Schedule{ID: tt.name, DeploymentID: deploymentID, Cron: "* * * * *", OverlapPolicy: tt.policy, TaskType: tasks.TaskTypeCustomWorkflow}, defaultScheduler.collector)
+			sr.latestTaskID = runningTaskID
+			sr.trigger(ctx)
+			require.Equal(t, tt.wantPending, sr.pending)
+			require.Equal(t, runningTaskID, sr.latestTaskID, "no task should be registered while the previous one is running")
+			cancelled, err := tasks.TaskHasCancellationFlag(runningTaskID)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantCancelled, cancelled)
+		})
+	}
+}
+
+func testOneShotSchedule(t *testing.T, client *api.Client) {
+	t.Parallel()
+	ctx := context.Background()
+	deploymentID := "dep-" + t.Name()
+
+	at := time.Now().Add(-time.Second)
+	id, err := scheduling.CreateSchedule(ctx, &scheduling.Schedule{
+		DeploymentID: deploymentID,
+		At:           &at,
+		TaskType:     tasks.TaskTypeCustomCommand,
+		Data:         map[string]string{"nodes/Compute": "0", "commandName": "restart", "interfaceName": "custom"},
+	})
+	require.NoError(t, err)
+
+	// One-shot schedules are removed once their task is registered
+	require.Eventually(t, func() bool {
+		s, err := scheduling.GetSchedule(ctx, deploymentID, id)
+		return err == nil && s == nil
+	}, 10*time.Second, 100*time.Millisecond)
+
+	tasksIDs, err := deployments.GetDeploymentTaskList(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Len(t, tasksIDs, 1, "expecting a task registered for deployment %q", deploymentID)
+	taskType, err := tasks.GetTaskType(tasksIDs[0])
+	require.NoError(t, err)
+	require.Equal(t, tasks.TaskTypeCustomCommand, taskType)
+	commandName, err := tasks.GetTaskData(tasksIDs[0], "commandName")
+	require.NoError(t, err)
+	require.Equal(t, "restart", commandName)
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/armon/go-metrics"
 	"github.com/pkg/errors"
 
+	"github.com/ystia/yorc/v4/cluster"
 	"github.com/ystia/yorc/v4/events"
 	"github.com/ystia/yorc/v4/helper/consulutil"
 	"github.com/ystia/yorc/v4/helper/metricsutil"
@@ -51,6 +52,7 @@ func (sca *scheduledAction) start() {
 
 	sca.chStop = make(chan struct{})
 	sca.stopScheduling = false
+	cluster.AddWork(cluster.WorkScheduledAction, sca.ID)
 	go sca.schedule()
 }
 
@@ -61,6 +63,7 @@ func (sca *scheduledAction) stop() {
 	if !sca.stopScheduling {
 		sca.stopScheduling = true
 		close(sca.chStop)
+		cluster.RemoveWork(cluster.WorkScheduledAction, sca.ID)
 	}
 }
 
@@ -110,7 +113,7 @@ func (sca *scheduledAction) proceed() error {
 		if err != nil {
 			return err
 		}
-		if status == tasks.TaskStatusINITIAL || status == tasks.TaskStatusRUNNING {
+		if status == tasks.TaskStatusINITIAL || status == tasks.TaskStatusRUNNING || status == tasks.TaskStatusPENDINGAPPROVAL {
 			ctx := context.Background()
 			if sca.AsyncOperation.TaskID != "" {
 				ctx = events.AddLogOptionalFields(ctx, events.LogOptionalFields{
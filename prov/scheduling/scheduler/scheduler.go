@@ -24,9 +24,11 @@ import (
 	"github.com/hashicorp/consul/api"
 	"github.com/pkg/errors"
 
+	"github.com/ystia/yorc/v4/cluster"
 	"github.com/ystia/yorc/v4/config"
 	"github.com/ystia/yorc/v4/helper/consulutil"
 	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/scheduling"
 	"github.com/ystia/yorc/v4/tasks/collector"
 )
 
@@ -42,6 +44,8 @@ type scheduler struct {
 	isActiveLock     sync.Mutex
 	cfg              config.Configuration
 	actions          map[string]*scheduledAction
+	schedules        map[string]*scheduleRunner
+	schedulesLock    sync.Mutex
 }
 
 // unregisterAction allows to unregister a scheduled action
@@ -59,14 +63,14 @@ func (sc *scheduler) unregisterAction(id string) error {
 func Start(cfg config.Configuration, cc *api.Client) {
 	defaultScheduler = &scheduler{
 		cc:         cc,
-		collector:  collector.NewCollector(cc),
+		collector:  collector.NewCollectorWithApprovals(cc, cfg.Tasks.Approval),
 		chShutdown: make(chan struct{}),
 		isActive:   false,
 		serviceKey: path.Join(consulutil.YorcServicePrefix, "/scheduling/leader"),
 		cfg:        cfg,
 	}
 	// Watch leader election for scheduler
-	go consulutil.WatchLeaderElection(defaultScheduler.cc, defaultScheduler.serviceKey, defaultScheduler.chShutdown, defaultScheduler.startScheduling, defaultScheduler.stopScheduling)
+	go cluster.WatchLeaderElection(defaultScheduler.cc, cluster.RoleSchedulingLeader, defaultScheduler.serviceKey, defaultScheduler.chShutdown, defaultScheduler.startScheduling, defaultScheduler.stopScheduling)
 }
 
 // Stop allows to stop polling and schedule actions
@@ -94,6 +98,8 @@ func (sc *scheduler) startScheduling() {
 	sc.isActiveLock.Unlock()
 	sc.chStopScheduling = make(chan struct{})
 	sc.actions = make(map[string]*scheduledAction)
+	sc.schedules = make(map[string]*scheduleRunner)
+	go sc.watchSchedules(sc.chStopScheduling)
 	var waitIndex uint64
 	go func() {
 		for {
@@ -172,6 +178,62 @@ func (sc *scheduler) stopScheduling() {
 		for _, action := range defaultScheduler.actions {
 			action.stop()
 		}
+		defaultScheduler.stopSchedules()
+	}
+}
+
+// watchSchedules polls for schedules definitions and starts or stops them accordingly
+func (sc *scheduler) watchSchedules(chStopScheduling chan struct{}) {
+	var waitIndex uint64
+	for {
+		select {
+		case <-chStopScheduling:
+			return
+		case <-sc.chShutdown:
+			return
+		default:
+		}
+
+		schedules, rMeta, err := scheduling.ListAllSchedules(sc.cc.KV(), &api.QueryOptions{WaitIndex: waitIndex})
+		if err != nil {
+			handleError(err)
+			continue
+		}
+		if waitIndex == rMeta.LastIndex {
+			// long pool ended due to a timeout
+			continue
+		}
+		waitIndex = rMeta.LastIndex
+		sc.updateSchedules(schedules)
+	}
+}
+
+// updateSchedules starts new schedules and stops removed ones
+func (sc *scheduler) updateSchedules(schedules []*scheduling.Schedule) {
+	sc.schedulesLock.Lock()
+	defer sc.schedulesLock.Unlock()
+	existing := make(map[string]struct{}, len(schedules))
+	for _, s := range schedules {
+		existing[s.ID] = struct{}{}
+		if _, ok := sc.schedules[s.ID]; !ok {
+			sr := newScheduleRunner(s, sc.collector)
+			sc.schedules[s.ID] = sr
+			sr.start()
+		}
+	}
+	for id, sr := range sc.schedules {
+		if _, ok := existing[id]; !ok {
+			sr.stop()
+			delete(sc.schedules, id)
+		}
+	}
+}
+
+func (sc *scheduler) stopSchedules() {
+	sc.schedulesLock.Lock()
+	defer sc.schedulesLock.Unlock()
+	for _, sr := range sc.schedules {
+		sr.stop()
 	}
 }
 
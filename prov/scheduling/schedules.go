@@ -0,0 +1,224 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduling
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/helper/cronutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/tasks"
+)
+
+// SchedulesTaskTypes are the types of tasks that could be scheduled
+var SchedulesTaskTypes = []tasks.TaskType{
+	tasks.TaskTypeCustomWorkflow,
+	tasks.TaskTypeCustomCommand,
+	tasks.TaskTypeScaleOut,
+	tasks.TaskTypeScaleIn,
+	tasks.TaskTypeUnDeploy,
+}
+
+// SchedulesPrefix is the prefix in Consul KV store of schedules definitions
+var SchedulesPrefix = path.Join(consulutil.SchedulingKVPrefix, "schedules")
+
+func schedulePath(deploymentID, id string) string {
+	return path.Join(SchedulesPrefix, deploymentID, id)
+}
+
+// IsOneShot returns true if the schedule runs only once
+func (s *Schedule) IsOneShot() bool {
+	return s.At != nil
+}
+
+func (s *Schedule) location() (*time.Location, error) {
+	if s.TimeZone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(s.TimeZone)
+	return loc, errors.Wrapf(err, "invalid time zone %q", s.TimeZone)
+}
+
+// Validate checks that a schedule definition is valid
+func (s *Schedule) Validate() error {
+	if s.DeploymentID == "" {
+		return errors.New("deployment ID is mandatory for a schedule")
+	}
+	if (s.Cron == "") == (s.At == nil) {
+		return errors.New("exactly one of a cron expression or an execution date should be defined for a schedule")
+	}
+	if s.Cron != "" {
+		if _, err := cronutil.Parse(s.Cron); err != nil {
+			return err
+		}
+	}
+	if _, err := s.location(); err != nil {
+		return err
+	}
+	if _, ok := _OverlapPolicyMap[s.OverlapPolicy]; !ok {
+		return errors.Errorf("invalid overlap policy %q", s.OverlapPolicy.String())
+	}
+	for _, tt := range SchedulesTaskTypes {
+		if s.TaskType == tt {
+			return nil
+		}
+	}
+	return errors.Errorf("tasks of type %q could not be scheduled", s.TaskType.String())
+}
+
+// NextRun returns the next execution date of a schedule after the given date
+//
+// One-shot schedules always return their execution date even if it is before the given date.
+// A zero time is returned if the schedule will never run.
+func (s *Schedule) NextRun(after time.Time) (time.Time, error) {
+	if s.IsOneShot() {
+		return *s.At, nil
+	}
+	loc, err := s.location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	expr, err := cronutil.Parse(s.Cron)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "invalid cron expression for schedule %q", s.ID)
+	}
+	return expr.Next(after.In(loc)), nil
+}
+
+// CreateSchedule validates and stores a new schedule, the schedule ID is returned
+func CreateSchedule(ctx context.Context, s *Schedule) (string, error) {
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+	s.ID = uuid.NewV4().String()
+	s.CreationDate = time.Now()
+	log.Debugf("Creating schedule %q for deployment %q", s.ID, s.DeploymentID)
+	err := consulutil.StoreConsulKeyWithJSONValue(path.Join(schedulePath(s.DeploymentID, s.ID), "definition"), s)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to store schedule %q of deployment %q", s.ID, s.DeploymentID)
+	}
+	return s.ID, nil
+}
+
+// UnmarshalSchedule reads a schedule from its stored definition
+func UnmarshalSchedule(definition []byte) (*Schedule, error) {
+	s := new(Schedule)
+	err := json.Unmarshal(definition, s)
+	return s, errors.Wrap(err, "failed to read schedule definition")
+}
+
+// IsScheduleDefinitionKey returns true if the given Consul key stores a schedule definition
+func IsScheduleDefinitionKey(key string) bool {
+	return path.Base(key) == "definition" && path.Dir(path.Dir(path.Dir(key))) == SchedulesPrefix
+}
+
+// GetSchedule returns a schedule of a deployment or nil if it doesn't exist
+func GetSchedule(ctx context.Context, deploymentID, id string) (*Schedule, error) {
+	exist, value, err := consulutil.GetValue(path.Join(schedulePath(deploymentID, id), "definition"))
+	if err != nil || !exist {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	return UnmarshalSchedule(value)
+}
+
+// ListSchedules returns the schedules of a deployment sorted by creation date
+func ListSchedules(ctx context.Context, deploymentID string) ([]*Schedule, error) {
+	kvps, err := consulutil.List(path.Join(SchedulesPrefix, deploymentID) + "/")
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	schedules := make([]*Schedule, 0)
+	for k, v := range kvps {
+		if !IsScheduleDefinitionKey(k) {
+			continue
+		}
+		s, err := UnmarshalSchedule(v)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, s)
+	}
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].CreationDate.Before(schedules[j].CreationDate)
+	})
+	return schedules, nil
+}
+
+// ListAllSchedules returns the schedules of all deployments, query options allows to perform a blocking query
+func ListAllSchedules(kv *api.KV, q *api.QueryOptions) ([]*Schedule, *api.QueryMeta, error) {
+	kvps, qm, err := kv.List(SchedulesPrefix+"/", q)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	schedules := make([]*Schedule, 0)
+	for _, kvp := range kvps {
+		if !IsScheduleDefinitionKey(kvp.Key) {
+			continue
+		}
+		s, err := UnmarshalSchedule(kvp.Value)
+		if err != nil {
+			log.Printf("[WARNING] ignoring schedule stored at %q: %v", kvp.Key, err)
+			continue
+		}
+		schedules = append(schedules, s)
+	}
+	return schedules, qm, nil
+}
+
+// DeleteSchedule removes a schedule of a deployment
+func DeleteSchedule(ctx context.Context, deploymentID, id string) error {
+	log.Debugf("Deleting schedule %q of deployment %q", id, deploymentID)
+	return errors.Wrap(consulutil.Delete(schedulePath(deploymentID, id)+"/", true), consulutil.ConsulGenericErrMsg)
+}
+
+// DeleteDeploymentSchedules removes all the schedules of a deployment
+func DeleteDeploymentSchedules(ctx context.Context, deploymentID string) error {
+	return errors.Wrap(consulutil.Delete(path.Join(SchedulesPrefix, deploymentID)+"/", true), consulutil.ConsulGenericErrMsg)
+}
+
+// GetScheduleLatestTaskID returns the ID of the latest task registered by a schedule or an empty string if none
+func GetScheduleLatestTaskID(ctx context.Context, deploymentID, id string) (string, error) {
+	_, taskID, err := consulutil.GetStringValue(path.Join(schedulePath(deploymentID, id), "latestTaskID"))
+	return taskID, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+}
+
+// SetScheduleLatestTaskID stores the ID of the latest task registered by a schedule
+func SetScheduleLatestTaskID(ctx context.Context, deploymentID, id, taskID string) error {
+	return errors.Wrap(consulutil.StoreConsulKeyAsString(path.Join(schedulePath(deploymentID, id), "latestTaskID"), taskID), consulutil.ConsulGenericErrMsg)
+}
+
+// IsScheduleTriggered returns true if a one-shot schedule was already triggered
+func IsScheduleTriggered(ctx context.Context, deploymentID, id string) (bool, error) {
+	exist, value, err := consulutil.GetStringValue(path.Join(schedulePath(deploymentID, id), "triggered"))
+	if err != nil {
+		return false, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	return exist && value == "true", nil
+}
+
+// SetScheduleTriggered flags a one-shot schedule as triggered so that it doesn't run again
+// if another Yorc server takes over its execution
+func SetScheduleTriggered(ctx context.Context, deploymentID, id string) error {
+	return errors.Wrap(consulutil.StoreConsulKeyAsString(path.Join(schedulePath(deploymentID, id), "triggered"), "true"), consulutil.ConsulGenericErrMsg)
+}
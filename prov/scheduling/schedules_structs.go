@@ -0,0 +1,54 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduling
+
+import (
+	"time"
+
+	"github.com/ystia/yorc/v4/tasks"
+)
+
+//go:generate go-enum -f=schedules_structs.go --lower
+
+// OverlapPolicy is an enumerated type defining what to do when a schedule triggers while the task
+// of its previous run is still in progress
+/*
+ENUM(
+Skip
+Queue
+Replace
+)
+*/
+type OverlapPolicy int
+
+// A Schedule defines when a task should be registered for a deployment
+//
+// Recurring schedules are defined by a Cron expression while one-shot schedules are defined
+// by an At date. One-shot schedules are removed once their task is registered.
+type Schedule struct {
+	ID           string `json:"id"`
+	DeploymentID string `json:"deployment_id"`
+	// Cron is a cron expression for recurring schedules
+	Cron string `json:"cron,omitempty"`
+	// At is the execution date of a one-shot schedule
+	At *time.Time `json:"at,omitempty"`
+	// TimeZone is the location used to evaluate the cron expression, defaults to the Yorc server local time
+	TimeZone      string         `json:"time_zone,omitempty"`
+	OverlapPolicy OverlapPolicy  `json:"overlap_policy"`
+	TaskType      tasks.TaskType `json:"task_type"`
+	// Data are the data of registered tasks
+	Data         map[string]string `json:"data,omitempty"`
+	CreationDate time.Time         `json:"creation_date"`
+}
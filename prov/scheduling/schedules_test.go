@@ -0,0 +1,85 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduling
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/tasks"
+)
+
+func TestScheduleValidate(t *testing.T) {
+	t.Parallel()
+	at := time.Date(2019, time.May, 3, 18, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		schedule Schedule
+		wantErr  bool
+	}{
+		{"Cron", Schedule{DeploymentID: "d", Cron: "0 2 * * *", TaskType: tasks.TaskTypeCustomWorkflow}, false},
+		{"OneShot", Schedule{DeploymentID: "d", At: &at, TaskType: tasks.TaskTypeUnDeploy}, false},
+		{"TimeZone", Schedule{DeploymentID: "d", Cron: "@daily", TimeZone: "Europe/Paris", TaskType: tasks.TaskTypeScaleOut, OverlapPolicy: OverlapPolicyReplace}, false},
+		{"MissingDeployment", Schedule{Cron: "0 2 * * *", TaskType: tasks.TaskTypeCustomWorkflow}, true},
+		{"MissingWhen", Schedule{DeploymentID: "d", TaskType: tasks.TaskTypeCustomWorkflow}, true},
+		{"CronAndAt", Schedule{DeploymentID: "d", Cron: "0 2 * * *", At: &at, TaskType: tasks.TaskTypeCustomWorkflow}, true},
+		{"BadCron", Schedule{DeploymentID: "d", Cron: "0 25 * * *", TaskType: tasks.TaskTypeCustomWorkflow}, true},
+		{"BadTimeZone", Schedule{DeploymentID: "d", Cron: "0 2 * * *", TimeZone: "Mars/Olympus", TaskType: tasks.TaskTypeCustomWorkflow}, true},
+		{"BadOverlapPolicy", Schedule{DeploymentID: "d", Cron: "0 2 * * *", OverlapPolicy: OverlapPolicy(42), TaskType: tasks.TaskTypeCustomWorkflow}, true},
+		{"BadTaskType", Schedule{DeploymentID: "d", Cron: "0 2 * * *", TaskType: tasks.TaskTypeDeploy}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schedule.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestScheduleNextRun(t *testing.T) {
+	t.Parallel()
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	at := time.Date(2019, time.May, 3, 18, 0, 0, 0, time.UTC)
+	now := time.Date(2019, time.May, 4, 10, 0, 0, 0, time.UTC)
+
+	s := &Schedule{At: &at}
+	next, err := s.NextRun(now)
+	require.NoError(t, err)
+	require.True(t, at.Equal(next), "one-shot schedules should always return their date")
+
+	s = &Schedule{Cron: "0 2 * * *", TimeZone: "Europe/Paris"}
+	next, err = s.NextRun(now)
+	require.NoError(t, err)
+	require.True(t, time.Date(2019, time.May, 5, 2, 0, 0, 0, paris).Equal(next), "unexpected next run %v", next)
+
+	s = &Schedule{Cron: "0 2 * * *", TimeZone: "Mars/Olympus"}
+	_, err = s.NextRun(now)
+	require.Error(t, err)
+}
+
+func TestIsScheduleDefinitionKey(t *testing.T) {
+	t.Parallel()
+	require.True(t, IsScheduleDefinitionKey(path.Join(SchedulesPrefix, "dep", "sched", "definition")))
+	require.False(t, IsScheduleDefinitionKey(path.Join(SchedulesPrefix, "dep", "sched", "latestTaskID")))
+	require.False(t, IsScheduleDefinitionKey(path.Join(SchedulesPrefix, "dep", "definition")))
+}
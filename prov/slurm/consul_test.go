@@ -80,5 +80,8 @@ func TestRunConsulSlurmPackageTests(t *testing.T) {
 		t.Run("ActionOperatorAnalyzeJob", func(t *testing.T) {
 			testActionOperatorAnalyzeJob(t, srv, cfg)
 		})
+		t.Run("ActionOperatorLogHetJobFiles", func(t *testing.T) {
+			testActionOperatorLogHetJobFiles(t, srv, cfg)
+		})
 	})
 }
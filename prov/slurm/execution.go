@@ -350,6 +350,14 @@ func (e *executionCommon) buildJobInfo(ctx context.Context) error {
 		}
 	}
 
+	if err = e.buildJobArrayInfo(ctx); err != nil {
+		return err
+	}
+
+	if err = e.buildHetJobInfo(ctx); err != nil {
+		return err
+	}
+
 	// Retrieve job id from attribute if it was previously set (otherwise will be retrieved when running the job)
 	// TODO(loicalbertin) right now I can't see any notion of multi-instances for Slurm jobs but this sounds bad to me
 	id, err := deployments.GetInstanceAttributeValue(ctx, e.deploymentID, e.NodeName, "0", "job_id")
@@ -410,6 +418,58 @@ func (e *executionCommon) buildJobInfo(ctx context.Context) error {
 	return nil
 }
 
+func (e *executionCommon) buildJobArrayInfo(ctx context.Context) error {
+	array, err := deployments.GetNodePropertyValue(ctx, e.deploymentID, e.NodeName, "slurm_options", "array")
+	if err != nil {
+		return err
+	}
+	if array == nil || array.RawString() == "" {
+		return nil
+	}
+	e.jobInfo.Array = array.RawString()
+
+	if throttle, err := deployments.GetNodePropertyValue(ctx, e.deploymentID, e.NodeName, "slurm_options", "array_throttle"); err != nil {
+		return err
+	} else if throttle != nil && throttle.RawString() != "" {
+		if e.jobInfo.ArrayThrottle, err = strconv.Atoi(throttle.RawString()); err != nil {
+			return errors.Wrapf(err, "invalid array_throttle value for node %q", e.NodeName)
+		}
+	}
+	return nil
+}
+
+func (e *executionCommon) buildHetJobInfo(ctx context.Context) error {
+	comps, err := deployments.GetNodePropertyValue(ctx, e.deploymentID, e.NodeName, "slurm_options", "het_job_components")
+	if err != nil {
+		return err
+	}
+	if comps == nil || comps.Value == nil {
+		return nil
+	}
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		WeaklyTypedInput: true,
+		Result:           &e.jobInfo.HetJobComponents,
+	})
+	if err != nil {
+		return err
+	}
+	if err = decoder.Decode(comps.Value); err != nil {
+		return errors.Wrapf(err, `invalid heterogeneous job components for node %q`, e.NodeName)
+	}
+	for i := range e.jobInfo.HetJobComponents {
+		comp := &e.jobInfo.HetJobComponents[i]
+		if comp.Mem != "" {
+			if comp.Mem, err = toSlurmMemFormat(comp.Mem); err != nil {
+				return err
+			}
+		}
+	}
+	if len(e.jobInfo.HetJobComponents) > 0 && e.jobInfo.Array != "" {
+		return errors.Errorf("node %q: job arrays and heterogeneous jobs can't be combined", e.NodeName)
+	}
+	return nil
+}
+
 func (e *executionCommon) buildJobOpts() string {
 	var opts string
 	opts += fmt.Sprintf(" --job-name='%s'", e.jobInfo.Name)
@@ -435,6 +495,34 @@ func (e *executionCommon) buildJobOpts() string {
 	if e.jobInfo.Account != "" {
 		opts += fmt.Sprintf(" --account='%s'", e.jobInfo.Account)
 	}
+	if e.jobInfo.Array != "" {
+		if e.jobInfo.ArrayThrottle > 0 {
+			opts += fmt.Sprintf(" --array='%s%%%d'", e.jobInfo.Array, e.jobInfo.ArrayThrottle)
+		} else {
+			opts += fmt.Sprintf(" --array='%s'", e.jobInfo.Array)
+		}
+	}
+	// Each heterogeneous job component is separated by a colon on the sbatch command line
+	for _, comp := range e.jobInfo.HetJobComponents {
+		opts += " :"
+		if comp.Tasks > 1 {
+			opts += fmt.Sprintf(" --ntasks=%d", comp.Tasks)
+		}
+		nodes := comp.Nodes
+		if nodes == 0 {
+			nodes = 1
+		}
+		opts += fmt.Sprintf(" --nodes=%d", nodes)
+		if comp.Mem != "" {
+			opts += fmt.Sprintf(" --mem='%s'", comp.Mem)
+		}
+		if comp.Cpus != 0 {
+			opts += fmt.Sprintf(" --cpus-per-task=%d", comp.Cpus)
+		}
+		if len(comp.Opts) > 0 {
+			opts += fmt.Sprintf(" %s", strings.Join(comp.Opts, " "))
+		}
+	}
 	log.Debugf("opts=%q", opts)
 	return opts
 }
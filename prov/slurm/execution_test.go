@@ -85,6 +85,10 @@ func testExecutionCommonBuildJobInfo(t *testing.T) {
 	err = deployments.StoreDeploymentDefinition(ctx, deploymentIDOpts, "testdata/job_with_options.yaml")
 	require.NoError(t, err)
 
+	deploymentIDArrays := deploymentID + "-arrays"
+	err = deployments.StoreDeploymentDefinition(ctx, deploymentIDArrays, "testdata/job_array_and_het_job.yaml")
+	require.NoError(t, err)
+
 	type fields struct {
 		locationProps config.DynamicMap
 		deploymentID  string
@@ -113,6 +117,15 @@ func testExecutionCommonBuildJobInfo(t *testing.T) {
 					Command:         "sh",
 					EnvVars:         []string{"STORAGE_PATH=/mnt/data-bu/models/C56A8BCD-380E-4E6C-B265-D50208641102-4203addf-aca9-3040-271d-d2bbe0719f79"},
 				}}},
+		{"CheckJobArray", fields{config.DynamicMap{}, deploymentIDArrays, "ArrayJob", make([]*operations.EnvInput, 0), "primary", false}, false,
+			jobInfo{Name: "ArrayJob", Tasks: 1, Nodes: 1, MonitoringTimeInterval: 5 * time.Second, Inputs: make(map[string]string), WorkingDir: home,
+				Array: "0-15:2", ArrayThrottle: 4}},
+		{"CheckHeterogeneousJob", fields{config.DynamicMap{}, deploymentIDArrays, "HetJob", make([]*operations.EnvInput, 0), "primary", false}, false,
+			jobInfo{Name: "HetJob", Tasks: 1, Nodes: 1, MonitoringTimeInterval: 5 * time.Second, Inputs: make(map[string]string), WorkingDir: home,
+				HetJobComponents: []hetJobComponent{
+					{Tasks: 4, Nodes: 2, Mem: "976K"},
+					{Tasks: 1, Nodes: 1, Cpus: 8, Opts: []string{"--partition=gpu"}},
+				}}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -178,6 +191,19 @@ func testExecutionCommonPrepareAndSubmitJob(t *testing.T) {
 					}}},
 			regexp.MustCompile(`cat <<'EOF' > ~/b-[-a-f0-9]+.batch\n#!/bin/bash\n\nsrun --mpi=pmi2 test.mpi \nEOF\nsbatch -D ~ --job-name='MyJob' --ntasks=2 --nodes=4 ~/b-[-a-f0-9]+.batch; rm -f ~/b-[-a-f0-9]+.batch`),
 			false},
+		{"CheckJobArray",
+			fields{config.Configuration{}, config.DynamicMap{}, deploymentID, "ClassificationJobUnit_Singularity", make([]*operations.EnvInput, 0), "primary.batch",
+				&jobInfo{Name: "MyJob", Nodes: 1, WorkingDir: home, Array: "0-15:2", ArrayThrottle: 4}},
+			regexp.MustCompile("sbatch -D ~ --job-name='MyJob' --nodes=1 --array='0-15:2%4' ~/primary.batch"),
+			false},
+		{"CheckHeterogeneousJob",
+			fields{config.Configuration{}, config.DynamicMap{}, deploymentID, "ClassificationJobUnit_Singularity", make([]*operations.EnvInput, 0), "primary.batch",
+				&jobInfo{Name: "MyJob", Nodes: 1, WorkingDir: home, HetJobComponents: []hetJobComponent{
+					{Tasks: 4, Nodes: 2, Mem: "1024K"},
+					{Cpus: 8, Opts: []string{"--partition=gpu"}},
+				}}},
+			regexp.MustCompile("sbatch -D ~ --job-name='MyJob' --nodes=1 : --ntasks=4 --nodes=2 --mem='1024K' : --nodes=1 --cpus-per-task=8 --partition=gpu ~/primary.batch"),
+			false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -122,28 +122,43 @@ func getUserCredentials(ctx context.Context, locationProps config.DynamicMap, de
 		}
 	} else {
 		// Get user credentials from the location properties
-		if err := checkLocationUserConfig(locationProps); err != nil {
-			log.Printf("Unable to provide SSH client due to:%+v", err)
+		locationCreds, err := getLocationCredentials(locationProps)
+		if err != nil {
 			return nil, err
 		}
-		creds.User = strings.Trim(locationProps.GetString("user_name"), "")
-		creds.User = config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("slurm.user_name", creds.User).(string)
-		privateKey := strings.Trim(locationProps.GetString("private_key"), "")
-		if privateKey != "" {
-			privateKey = config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("slurm.private_key", privateKey).(string)
+		creds.User = locationCreds.User
+		creds.Token = locationCreds.Token
+		for name, key := range locationCreds.Keys {
 			if creds.Keys == nil {
 				creds.Keys = make(map[string]string)
 			}
-			creds.Keys["default"] = privateKey
+			creds.Keys[name] = key
 		}
-		creds.Token = strings.Trim(locationProps.GetString("password"), "")
-		creds.Token = config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("slurm.password", creds.Token).(string)
 	}
 
 	return creds, nil
 
 }
 
+// getLocationCredentials returns user credentials defined in the location properties
+func getLocationCredentials(locationProps config.DynamicMap) (*types.Credential, error) {
+	if err := checkLocationUserConfig(locationProps); err != nil {
+		log.Printf("Unable to provide SSH client due to:%+v", err)
+		return nil, err
+	}
+	creds := new(types.Credential)
+	creds.User = strings.Trim(locationProps.GetString("user_name"), "")
+	creds.User = config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("slurm.user_name", creds.User).(string)
+	privateKey := strings.Trim(locationProps.GetString("private_key"), "")
+	if privateKey != "" {
+		privateKey = config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("slurm.private_key", privateKey).(string)
+		creds.Keys = map[string]string{"default": privateKey}
+	}
+	creds.Token = strings.Trim(locationProps.GetString("password"), "")
+	creds.Token = config.DefaultConfigTemplateResolver.ResolveValueWithTemplates("slurm.password", creds.Token).(string)
+	return creds, nil
+}
+
 // checkLocationConfig checks slurm location mandatory configuration parameters :
 // - url (slurm client's node address)
 // - port (slurm client's node port)
@@ -287,20 +302,38 @@ func parseSallocResponse(r io.Reader, chRes chan allocationResponse, chErr chan
 	return
 }
 
-func parseJobInfo(r io.Reader) (map[string]string, error) {
-	data := make(map[string]string, 0)
+// parseJobInfos parses a scontrol show job output that may contain several job records separated by empty lines.
+// This is the case for job arrays (one record per array task) and heterogeneous jobs (one record per component).
+func parseJobInfos(r io.Reader) ([]map[string]string, error) {
+	infos := make([]map[string]string, 0)
+	var data map[string]string
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
-		props := strings.Split(line, " ")
-		for _, prop := range props {
-			if strings.Contains(prop, "=") {
-				t := strings.Split(prop, "=")
-				data[strings.TrimSpace(t[0])] = strings.TrimSpace(t[1])
-			}
+		if strings.TrimSpace(line) == "" {
+			data = nil
+			continue
+		}
+		if data == nil {
+			data = make(map[string]string)
+			infos = append(infos, data)
+		}
+		parseJobInfoLine(line, data)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "An error occurred scanning job info")
+	}
+	return infos, nil
+}
+
+func parseJobInfoLine(line string, data map[string]string) {
+	props := strings.Split(line, " ")
+	for _, prop := range props {
+		if strings.Contains(prop, "=") {
+			t := strings.Split(prop, "=")
+			data[strings.TrimSpace(t[0])] = strings.TrimSpace(t[1])
 		}
 	}
-	return data, nil
 }
 
 func parseJobID(str string, regexp *regexp.Regexp) (string, error) {
@@ -341,7 +374,10 @@ func parseKeyValue(str string) (bool, string, string) {
 	return false, "", ""
 }
 
-func getJobInfo(client sshutil.Client, jobID string) (map[string]string, error) {
+// getJobInfo returns the job information records returned by scontrol for the given job ID.
+// A simple job leads to a single record, while job arrays and heterogeneous jobs lead to a record per array task or
+// per component.
+func getJobInfo(client sshutil.Client, jobID string) ([]map[string]string, error) {
 	cmd := fmt.Sprintf("scontrol show job %s", jobID)
 	output, err := client.RunCommand(cmd)
 	out := strings.Trim(output, "\" \t\n\x00")
@@ -352,11 +388,62 @@ func getJobInfo(client sshutil.Client, jobID string) (map[string]string, error)
 		return nil, errors.Wrap(err, out)
 	}
 	if out != "" {
-		return parseJobInfo(strings.NewReader(out))
+		infos, err := parseJobInfos(strings.NewReader(out))
+		if err != nil {
+			return nil, err
+		}
+		if len(infos) > 0 {
+			return infos, nil
+		}
 	}
 	return nil, &noJobFound{msg: fmt.Sprintf("no information found for job with id:%q", jobID)}
 }
 
+// isActiveJobState returns true if the given job state means that the job is still running
+// or that its state is about to be set definitively
+func isActiveJobState(state string) bool {
+	switch state {
+	case "RUNNING", "PENDING", "COMPLETING", "CONFIGURING", "SIGNALING", "RESIZING":
+		return true
+	}
+	return false
+}
+
+// aggregateJobState computes a global job state from the states of job records.
+//
+// A job is PENDING if all its records are pending, it is considered as RUNNING while at least one of its records
+// is active, COMPLETED if all its records are completed. Otherwise the state of the first unsuccessful record is
+// returned.
+func aggregateJobState(infos []map[string]string) string {
+	if len(infos) == 1 {
+		return infos[0]["JobState"]
+	}
+	var pending, active, completed int
+	var failedState string
+	for _, info := range infos {
+		state := info["JobState"]
+		switch {
+		case state == "PENDING":
+			pending++
+		case isActiveJobState(state):
+			active++
+		case state == "COMPLETED":
+			completed++
+		case failedState == "":
+			failedState = state
+		}
+	}
+	switch {
+	case pending == len(infos):
+		return "PENDING"
+	case active > 0 || pending > 0:
+		return "RUNNING"
+	case completed == len(infos):
+		return "COMPLETED"
+	}
+	return failedState
+}
+
 func quoteArgs(t []string) string {
 	var args string
 	for _, v := range t {
@@ -336,8 +336,10 @@ func TestParseJob(t *testing.T) {
 	t.Parallel()
 	data, err := os.Open("testdata/scontrol.txt")
 	require.Nil(t, err, "unexpected error while opening test file")
-	info, err := parseJobInfo(data)
+	infos, err := parseJobInfos(data)
 	require.Nil(t, err, "unexpected error while parsing job info")
+	require.Len(t, infos, 1, "unexpected number of job records")
+	info := infos[0]
 	require.Equal(t, "hpda19", info["NodeList"], "unexpected value for \"NodeList\" key")
 	require.Equal(t, "/home_nfs/john/file.out", info["StdOut"], "unexpected value for \"StdOut\" key")
 	require.Equal(t, "None", info["Reason"], "unexpected value for \"Reason\" key")
@@ -372,7 +374,7 @@ func TestGetJobInfo(t *testing.T) {
 
 	data, err := os.Open("testdata/scontrol.txt")
 	require.Nil(t, err, "unexpected error while opening test file")
-	expected, err := parseJobInfo(data)
+	expected, err := parseJobInfos(data)
 	require.Nil(t, err, "Unexpected error parsing job info")
 	info, err := getJobInfo(s, "1234")
 	require.Nil(t, err, "Unexpected error retrieving job info")
@@ -381,6 +383,49 @@ func TestGetJobInfo(t *testing.T) {
 	require.Equal(t, expected, info, "unexpected job info")
 }
 
+func TestParseJobArray(t *testing.T) {
+	t.Parallel()
+	data, err := os.Open("testdata/scontrol_show_job_array.txt")
+	require.Nil(t, err, "unexpected error while opening test file")
+	infos, err := parseJobInfos(data)
+	require.Nil(t, err, "unexpected error while parsing job info")
+	require.Len(t, infos, 3, "unexpected number of job records")
+	require.Equal(t, "2-3%1", infos[0]["ArrayTaskId"], "unexpected value for \"ArrayTaskId\" key")
+	require.Equal(t, "PENDING", infos[0]["JobState"], "unexpected value for \"JobState\" key")
+	require.Equal(t, "1", infos[1]["ArrayTaskId"], "unexpected value for \"ArrayTaskId\" key")
+	require.Equal(t, "RUNNING", infos[1]["JobState"], "unexpected value for \"JobState\" key")
+	require.Equal(t, "/home_nfs/john/slurm-6260_1.out", infos[1]["StdOut"], "unexpected value for \"StdOut\" key")
+	require.Equal(t, "0", infos[2]["ArrayTaskId"], "unexpected value for \"ArrayTaskId\" key")
+	require.Equal(t, "COMPLETED", infos[2]["JobState"], "unexpected value for \"JobState\" key")
+	require.Equal(t, "RUNNING", aggregateJobState(infos), "unexpected aggregated job state")
+}
+
+func TestAggregateJobState(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		states []string
+		want   string
+	}{
+		{"SingleJob", []string{"TIMEOUT"}, "TIMEOUT"},
+		{"AllPending", []string{"PENDING", "PENDING"}, "PENDING"},
+		{"SomeRunning", []string{"PENDING", "RUNNING", "COMPLETED"}, "RUNNING"},
+		{"PendingAndCompleted", []string{"PENDING", "COMPLETED"}, "RUNNING"},
+		{"AllCompleted", []string{"COMPLETED", "COMPLETED"}, "COMPLETED"},
+		{"SomeFailed", []string{"COMPLETED", "FAILED", "CANCELLED"}, "FAILED"},
+		{"RunningWithFailures", []string{"RUNNING", "FAILED"}, "RUNNING"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			infos := make([]map[string]string, 0)
+			for _, s := range tt.states {
+				infos = append(infos, map[string]string{"JobState": s})
+			}
+			require.Equal(t, tt.want, aggregateJobState(infos))
+		})
+	}
+}
+
 func TestGetJobInfoWithEmptyResponse(t *testing.T) {
 	t.Parallel()
 	s := &sshutil.MockSSHClient{
@@ -0,0 +1,196 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slurm
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/sshutil"
+	"github.com/ystia/yorc/v4/locations"
+	"github.com/ystia/yorc/v4/prov"
+)
+
+// Infrastructure usage query parameters
+const (
+	// infraUsageAccountsParam is the comma-separated list of accounts to report, all accounts are reported by default
+	infraUsageAccountsParam = "accounts"
+	// infraUsageStartParam is the start of the accounting period as accepted by sacct, default is midnight of the current day
+	infraUsageStartParam = "start"
+	// infraUsageEndParam is the end of the accounting period as accepted by sacct, default is now
+	infraUsageEndParam = "end"
+)
+
+type infraUsageCollector struct {
+}
+
+// GetUsageInfo returns the CPUs usage of a Slurm cluster, the fair-share usage of its accounts
+// and their accounted jobs over a period
+func (c *infraUsageCollector) GetUsageInfo(ctx context.Context, cfg config.Configuration, taskID, infraName, locationName string,
+	params map[string]string) (map[string]interface{}, error) {
+	locationMgr, err := locations.GetManager(cfg)
+	if err != nil {
+		return nil, err
+	}
+	locationProps, err := locationMgr.GetLocationProperties(locationName, infrastructureType)
+	if err != nil {
+		return nil, err
+	}
+	creds, err := getLocationCredentials(locationProps)
+	if err != nil {
+		return nil, err
+	}
+	client, err := getSSHClient(cfg, creds, locationProps)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := collectInfraUsage(client, locationName, params)
+	if err != nil {
+		return nil, err
+	}
+	return usage.ToMap()
+}
+
+type accountUsage struct {
+	resources  prov.ResourceUsagesBuilder
+	properties map[string]string
+}
+
+func collectInfraUsage(client sshutil.Client, locationName string, params map[string]string) (*prov.InfraUsage, error) {
+	usage := &prov.InfraUsage{
+		Infrastructure: infrastructureType,
+		Location:       locationName,
+		CollectDate:    time.Now(),
+	}
+	var total prov.ResourceUsagesBuilder
+
+	// CPUs as allocated/idle/other/total
+	out, err := client.RunCommand(`sinfo -h -o "%C"`)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve Slurm CPUs usage")
+	}
+	cpus := strings.Split(strings.TrimSpace(out), "/")
+	if len(cpus) != 4 {
+		return nil, errors.Errorf("unexpected Slurm CPUs usage: %q", out)
+	}
+	allocated, err := strconv.ParseFloat(cpus[0], 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unexpected Slurm CPUs usage: %q", out)
+	}
+	capacity, err := strconv.ParseFloat(cpus[3], 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unexpected Slurm CPUs usage: %q", out)
+	}
+	total.Add(prov.NewResourceUsage(prov.ResourceCPUs, prov.UnitCores, capacity, allocated))
+
+	var accountsOpt string
+	if params[infraUsageAccountsParam] != "" {
+		accountsOpt = " -A " + quoteArgs([]string{params[infraUsageAccountsParam]})
+	}
+	accounts := make(map[string]*accountUsage)
+	getAccount := func(name string) *accountUsage {
+		a, ok := accounts[name]
+		if !ok {
+			a = &accountUsage{properties: make(map[string]string)}
+			accounts[name] = a
+		}
+		return a
+	}
+
+	// Fair-share usage of accounts
+	out, err = client.RunCommand("sshare -a -n -P -o Account,User,RawShares,NormShares,RawUsage,EffectvUsage,FairShare" + accountsOpt)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve Slurm accounts shares")
+	}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "|")
+		if len(fields) != 7 || strings.TrimSpace(fields[1]) != "" {
+			// Ignore users associations
+			continue
+		}
+		a := getAccount(strings.TrimSpace(fields[0]))
+		a.properties["raw_shares"] = fields[2]
+		a.properties["fairshare"] = fields[6]
+		normShares, errShares := strconv.ParseFloat(fields[3], 64)
+		effectiveUsage, errUsage := strconv.ParseFloat(fields[5], 64)
+		if errShares == nil && errUsage == nil {
+			a.resources.Add(prov.NewResourceUsage("shares", prov.UnitRatio, normShares, effectiveUsage))
+		}
+		if rawUsage, err := strconv.ParseFloat(fields[4], 64); err == nil {
+			a.resources.Add(prov.ResourceUsage{Name: "raw_usage", Unit: prov.UnitCPUSeconds, Used: rawUsage})
+		}
+	}
+
+	// Accounted jobs over the period
+	cmd := "sacct -a -X -n -P -o Account,AllocCPUS,ElapsedRaw" + accountsOpt
+	if params[infraUsageStartParam] != "" {
+		cmd += " -S " + quoteArgs([]string{params[infraUsageStartParam]})
+	}
+	if params[infraUsageEndParam] != "" {
+		cmd += " -E " + quoteArgs([]string{params[infraUsageEndParam]})
+	}
+	out, err = client.RunCommand(cmd)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve Slurm jobs accounting")
+	}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "|")
+		if len(fields) != 3 {
+			continue
+		}
+		allocCPUs, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unexpected Slurm job accounting %q", line)
+		}
+		elapsed, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unexpected Slurm job accounting %q", line)
+		}
+		jobUsages := []prov.ResourceUsage{
+			{Name: prov.ResourceCPUTime, Unit: prov.UnitCPUSeconds, Used: allocCPUs * elapsed},
+			{Name: prov.ResourceJobs, Used: 1},
+		}
+		a := getAccount(strings.TrimSpace(fields[0]))
+		for _, u := range jobUsages {
+			a.resources.Add(u)
+			total.Add(u)
+		}
+	}
+	// Report the period even if there is no job on it
+	total.Add(prov.ResourceUsage{Name: prov.ResourceCPUTime, Unit: prov.UnitCPUSeconds})
+	total.Add(prov.ResourceUsage{Name: prov.ResourceJobs})
+
+	usage.Resources = total.Resources()
+	names := make([]string, 0, len(accounts))
+	for name := range accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		usage.Groups = append(usage.Groups, prov.InfraUsageGroup{
+			Kind:       "account",
+			Name:       name,
+			Resources:  accounts[name].resources.Resources(),
+			Properties: accounts[name].properties,
+		})
+	}
+	return usage, nil
+}
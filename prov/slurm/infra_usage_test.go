@@ -0,0 +1,112 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slurm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/helper/sshutil"
+	"github.com/ystia/yorc/v4/prov"
+)
+
+func TestCollectInfraUsage(t *testing.T) {
+	t.Parallel()
+	var commands []string
+	s := &sshutil.MockSSHClient{
+		MockRunCommand: func(cmd string) (string, error) {
+			commands = append(commands, cmd)
+			switch {
+			case strings.HasPrefix(cmd, "sinfo"):
+				return "90/78/8/176\n", nil
+			case strings.HasPrefix(cmd, "sshare"):
+				return `root|||1.000000|1000|1.000000|
+ physics||10|0.500000|600|0.600000|0.250000
+  physics|jdoe|1|0.250000|600|0.600000|0.250000
+ biology||10|0.500000|400|0.400000|0.750000
+`, nil
+			case strings.HasPrefix(cmd, "sacct"):
+				return `physics|4|100
+physics|2|50
+biology|1|10
+`, nil
+			}
+			return "", errors.Errorf("unexpected command %q", cmd)
+		},
+	}
+
+	usage, err := collectInfraUsage(s, "mySlurm", map[string]string{"start": "2020-01-01", "accounts": "physics,biology"})
+	require.NoError(t, err)
+	require.Len(t, commands, 3)
+	require.Contains(t, commands[2], "-A 'physics,biology'")
+	require.Contains(t, commands[2], "-S '2020-01-01'")
+
+	require.Equal(t, "slurm", usage.Infrastructure)
+	require.Equal(t, []prov.ResourceUsage{
+		{Name: prov.ResourceCPUTime, Unit: prov.UnitCPUSeconds, Used: 510},
+		prov.NewResourceUsage(prov.ResourceCPUs, prov.UnitCores, 176, 90),
+		{Name: prov.ResourceJobs, Used: 3},
+	}, usage.Resources)
+
+	require.Len(t, usage.Groups, 3)
+	require.Equal(t, "biology", usage.Groups[0].Name)
+	require.Equal(t, "physics", usage.Groups[1].Name)
+	require.Equal(t, "root", usage.Groups[2].Name)
+	require.Equal(t, "0.250000", usage.Groups[1].Properties["fairshare"])
+	require.Equal(t, []prov.ResourceUsage{
+		{Name: prov.ResourceCPUTime, Unit: prov.UnitCPUSeconds, Used: 500},
+		{Name: prov.ResourceJobs, Used: 2},
+		{Name: "raw_usage", Unit: prov.UnitCPUSeconds, Used: 600},
+		prov.NewResourceUsage("shares", prov.UnitRatio, 0.5, 0.6),
+	}, usage.Groups[1].Resources)
+}
+
+func TestCollectInfraUsageErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		sinfo  string
+		sacct  string
+		cmdErr string
+	}{
+		{"SinfoFailure", "", "", "sinfo"},
+		{"BadCPUs", "90/78", "", ""},
+		{"BadJobCPUs", "1/1/0/2", "physics|x|100", ""},
+		{"SacctFailure", "1/1/0/2", "", "sacct"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &sshutil.MockSSHClient{
+				MockRunCommand: func(cmd string) (string, error) {
+					if tt.cmdErr != "" && strings.HasPrefix(cmd, tt.cmdErr) {
+						return "", errors.New("command failed")
+					}
+					switch {
+					case strings.HasPrefix(cmd, "sinfo"):
+						return tt.sinfo, nil
+					case strings.HasPrefix(cmd, "sacct"):
+						return tt.sacct, nil
+					}
+					return "", nil
+				},
+			}
+			_, err := collectInfraUsage(s, "mySlurm", nil)
+			require.Error(t, err)
+		})
+	}
+}
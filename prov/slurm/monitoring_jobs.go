@@ -17,6 +17,7 @@ package slurm
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"path"
 	"strconv"
 	"strings"
@@ -84,6 +85,27 @@ func (o *actionOperator) updateJobAttributes(ctx context.Context, deploymentID,
 	return nil
 }
 
+// updateJobSubStatesAttributes stores the states of job array tasks or heterogeneous job components
+// into the array_tasks_states or het_job_components_states instance attributes.
+// It returns the states map or nil if the job is a simple job.
+func (o *actionOperator) updateJobSubStatesAttributes(ctx context.Context, deploymentID, nodeName, instanceName string, infos []map[string]string) (map[string]string, error) {
+	var attrName string
+	states := make(map[string]string)
+	for _, info := range infos {
+		if taskID, ok := info["ArrayTaskId"]; ok {
+			attrName = "array_tasks_states"
+			states[taskID] = info["JobState"]
+		} else if offset, ok := info["HetJobOffset"]; ok {
+			attrName = "het_job_components_states"
+			states[offset] = info["JobState"]
+		}
+	}
+	if attrName == "" {
+		return nil, nil
+	}
+	return states, deployments.SetInstanceAttributeComplex(ctx, deploymentID, nodeName, instanceName, attrName, states)
+}
+
 func getMonitoringJobActionData(action *prov.Action) (*actionData, error) {
 	var ok bool
 
@@ -129,7 +151,7 @@ func (o *actionOperator) analyzeJob(ctx context.Context, cc *api.Client, sshClie
 		return true, err
 	}
 
-	info, err := getJobInfo(sshClient, actionData.jobID)
+	infos, err := getJobInfo(sshClient, actionData.jobID)
 
 	// TODO(loicalbertin): This should be improved instance name should not be hard-coded (https://github.com/ystia/yorc/issues/670)
 	instanceName := "0"
@@ -141,59 +163,71 @@ func (o *actionOperator) analyzeJob(ctx context.Context, cc *api.Client, sshClie
 		}
 		return true, errors.Wrapf(err, "failed to get job info with jobID:%q", actionData.jobID)
 	}
+
+	// Job arrays and heterogeneous jobs have a record per array task or component.
+	// The first record is used for job attributes while the job state is aggregated over all records.
+	info := infos[0]
+	jobState := aggregateJobState(infos)
+	info["JobState"] = jobState
 	err = o.updateJobAttributes(ctx, deploymentID, nodeName, instanceName, info)
 	if err != nil {
 		return true, errors.Wrapf(err, "failed to update job attributes with jobID: %q", actionData.jobID)
 	}
-
-	var mess string
-	if info["Reason"] != "None" {
-		mess = fmt.Sprintf("Job Name:%s, ID:%s, State:%s, Reason:%s, Execution Time:%s", info["JobName"], info["JobId"], info["JobState"], info["Reason"], info["RunTime"])
-	} else {
-		mess = fmt.Sprintf("Job Name:%s, ID:%s, State:%s, Execution Time:%s", info["JobName"], info["JobId"], info["JobState"], info["RunTime"])
+	subStates, err := o.updateJobSubStatesAttributes(ctx, deploymentID, nodeName, instanceName, infos)
+	if err != nil {
+		return true, errors.Wrapf(err, "failed to update job attributes with jobID: %q", actionData.jobID)
 	}
-	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).RegisterAsString(mess)
 
-	stdOut, existStdOut := info["StdOut"]
-	stdErr, existStdErr := info["StdErr"]
-	if existStdOut && existStdErr && stdOut == stdErr {
-		o.logFile(ctx, cc, action, deploymentID, stdOut, "StdOut/StdErr", sshClient)
-	} else {
-		if existStdOut {
-			o.logFile(ctx, cc, action, deploymentID, stdOut, "StdOut", sshClient)
+	// Heterogeneous job components may share output files, each file is streamed once
+	loggedFiles := make(map[string]bool)
+	for _, recordInfo := range infos {
+		var mess string
+		if recordInfo["Reason"] != "None" {
+			mess = fmt.Sprintf("Job Name:%s, ID:%s, State:%s, Reason:%s, Execution Time:%s", recordInfo["JobName"], recordInfo["JobId"], recordInfo["JobState"], recordInfo["Reason"], recordInfo["RunTime"])
+		} else {
+			mess = fmt.Sprintf("Job Name:%s, ID:%s, State:%s, Execution Time:%s", recordInfo["JobName"], recordInfo["JobId"], recordInfo["JobState"], recordInfo["RunTime"])
 		}
-		if existStdErr {
-			o.logFile(ctx, cc, action, deploymentID, stdErr, "StdErr", sshClient)
+		if taskID, ok := recordInfo["ArrayTaskId"]; ok {
+			mess += fmt.Sprintf(", Array Task ID:%s", taskID)
+		} else if offset, ok := recordInfo["HetJobOffset"]; ok {
+			mess += fmt.Sprintf(", Heterogeneous Job Offset:%s", offset)
 		}
-	}
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).RegisterAsString(mess)
 
-	// See default output if nothing is specified here
-	if !existStdOut && !existStdErr {
-		o.logFile(ctx, cc, action, deploymentID, fmt.Sprintf("slurm-%s.out", actionData.jobID), "StdOut/Stderr", sshClient)
+		// Pending records have no logs yet
+		if recordInfo["JobState"] != "PENDING" {
+			o.logJobFiles(ctx, cc, action, deploymentID, actionData.jobID, recordInfo, loggedFiles, sshClient)
+		}
 	}
 
 	previousJobState, err := deployments.GetInstanceStateString(ctx, deploymentID, nodeName, instanceName)
 	if err != nil {
 		return true, errors.Wrapf(err, "failed to get instance state for job %q", actionData.jobID)
 	}
-	if previousJobState != info["JobState"] {
-		deployments.SetInstanceStateStringWithContextualLogs(ctx, deploymentID, nodeName, instanceName, info["JobState"])
+	if previousJobState != jobState {
+		deployments.SetInstanceStateStringWithContextualLogs(ctx, deploymentID, nodeName, instanceName, jobState)
 	}
 
 	// See if monitoring must be continued and set job state if terminated
-	switch info["JobState"] {
-	case "COMPLETED":
+	switch {
+	case jobState == "COMPLETED":
 		// job has been done successfully : unregister monitoring
 		deregister = true
-	case "RUNNING", "PENDING", "COMPLETING", "CONFIGURING", "SIGNALING", "RESIZING":
+	case isActiveJobState(jobState):
 		// job's still running or its state is about to be set definitively: monitoring is keeping on (deregister stays false)
 	default:
 		// Other cases as FAILED, CANCELLED, STOPPED, SUSPENDED, TIMEOUT, etc : error is return with job state and job info is logged
 		deregister = true
 		// Log event containing all the slurm information
-		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, deploymentID).RegisterAsString(fmt.Sprintf("job info:%+v", info))
+		for _, recordInfo := range infos {
+			events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, deploymentID).RegisterAsString(fmt.Sprintf("job info:%+v", recordInfo))
+		}
 		// Error to be returned
-		err = errors.Errorf("job with ID:%q finished unsuccessfully with state:%q", actionData.jobID, info["JobState"])
+		if len(subStates) > 0 {
+			err = errors.Errorf("job with ID:%q finished unsuccessfully with state:%q, sub-jobs states: %v", actionData.jobID, jobState, subStates)
+		} else {
+			err = errors.Errorf("job with ID:%q finished unsuccessfully with state:%q", actionData.jobID, jobState)
+		}
 	}
 
 	// cleanup except if error occurred or explicitly specified in config
@@ -255,10 +289,52 @@ func (o *actionOperator) removeArtifacts(actionData *actionData, sshClient sshut
 	}
 }
 
+// logJobFiles streams the output files of a job record which are not already in loggedFiles.
+// Job arrays tasks output files are identified by their array task ID.
+func (o *actionOperator) logJobFiles(ctx context.Context, cc *api.Client, action *prov.Action, deploymentID, jobID string, info map[string]string, loggedFiles map[string]bool, sshClient sshutil.Client) {
+	var suffix string
+	defaultOutput := fmt.Sprintf("slurm-%s.out", jobID)
+	if taskID, ok := info["ArrayTaskId"]; ok {
+		suffix = fmt.Sprintf("[%s]", taskID)
+		defaultOutput = fmt.Sprintf("slurm-%s_%s.out", info["ArrayJobId"], taskID)
+	} else if offset, ok := info["HetJobOffset"]; ok && offset != "0" {
+		suffix = fmt.Sprintf("[%s]", offset)
+	}
+	logFile := func(filePath, fileType string) {
+		if loggedFiles[filePath] {
+			return
+		}
+		loggedFiles[filePath] = true
+		o.logFile(ctx, cc, action, deploymentID, filePath, fileType+suffix, sshClient)
+	}
+
+	stdOut, existStdOut := info["StdOut"]
+	stdErr, existStdErr := info["StdErr"]
+	if existStdOut && existStdErr && stdOut == stdErr {
+		logFile(stdOut, "StdOut/StdErr")
+	} else {
+		if existStdOut {
+			logFile(stdOut, "StdOut")
+		}
+		if existStdErr {
+			logFile(stdErr, "StdErr")
+		}
+	}
+
+	// See default output if nothing is specified here
+	if !existStdOut && !existStdErr {
+		logFile(defaultOutput, "StdOut/Stderr")
+	}
+}
+
+// logFile streams the new lines of a file, the index of the last streamed line is stored
+// in the action data under a key derived from the file path
 func (o *actionOperator) logFile(ctx context.Context, cc *api.Client, action *prov.Action, deploymentID, filePath, fileType string, sshClient sshutil.Client) {
-	fileTypeKey := fmt.Sprintf("lastIndex%s", strings.Replace(fileType, "/", "", -1))
+	fileKey := "lastIndex" + url.PathEscape(filePath)
+	// Actions started by previous versions stored the index under a key derived from the file type
+	legacyKey := "lastIndex" + strings.Replace(fileType, "/", "", -1)
 	// Get the log last index
-	lastInd, err := o.getLogLastIndex(action, fileTypeKey)
+	lastInd, err := o.getLogLastIndex(action, fileKey, legacyKey)
 	if err != nil {
 		log.Debugf("fail to get log last index for log file (%s)due to error:%+v:", filePath, err)
 		return
@@ -277,15 +353,22 @@ func (o *actionOperator) logFile(ctx context.Context, cc *api.Client, action *pr
 
 	// Update the last index
 	newInd := strconv.Itoa(lastInd + strings.Count(output, "\n"))
-	err = scheduling.UpdateActionData(cc, action.ID, fileTypeKey, newInd)
+	err = scheduling.UpdateActionData(cc, action.ID, fileKey, newInd)
 	if err != nil {
 		log.Debugf("fail to update action data due to error:%+v:", err)
 		return
 	}
 }
 
-func (o *actionOperator) getLogLastIndex(action *prov.Action, fileTypeKey string) (int, error) {
-	lastIndex, ok := action.Data[fileTypeKey]
+// getLogLastIndex returns the index stored under the first of the given keys found in the action data
+func (o *actionOperator) getLogLastIndex(action *prov.Action, keys ...string) (int, error) {
+	var lastIndex string
+	var ok bool
+	for _, key := range keys {
+		if lastIndex, ok = action.Data[key]; ok {
+			break
+		}
+	}
 	if !ok {
 		return 0, nil
 	}
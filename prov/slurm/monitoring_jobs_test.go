@@ -19,6 +19,7 @@ import (
 	"io/ioutil"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	ctu "github.com/hashicorp/consul/testutil"
@@ -79,6 +80,13 @@ func testActionOperatorAnalyzeJob(t *testing.T, srv *ctu.TestServer, cfg config.
 			"taskID":     "t1",
 			"workingDir": filepath.Join(cfg.WorkingDirectory, t.Name()),
 		}}, keepArtifacts: false}, "scontrol_show_job_failed.txt", true, true},
+		{"MonitorRunningJobArray", args{deploymentID: deploymentID, nodeName: "Job", action: &prov.Action{ActionType: "job-monitoring", Data: map[string]string{
+			"nodeName":   "Job",
+			"jobID":      "6260",
+			"stepName":   "run",
+			"taskID":     "t1",
+			"workingDir": filepath.Join(cfg.WorkingDirectory, t.Name()),
+		}}, keepArtifacts: false}, "scontrol_show_job_array.txt", false, false},
 		{"JobNotFound", args{deploymentID: deploymentID, nodeName: "Job", action: &prov.Action{ActionType: "job-monitoring", Data: map[string]string{
 			"nodeName":   "Job",
 			"jobID":      "6260",
@@ -115,6 +123,41 @@ func testActionOperatorAnalyzeJob(t *testing.T, srv *ctu.TestServer, cfg config.
 	}
 }
 
+func testActionOperatorLogHetJobFiles(t *testing.T, srv *ctu.TestServer, cfg config.Configuration) {
+	deploymentID := testutil.BuildDeploymentID(t)
+	ctx := context.Background()
+	err := deployments.StoreDeploymentDefinition(ctx, deploymentID, "testdata/jobMonitoringTest.yaml")
+	assert.NilError(t, err)
+
+	cc, err := cfg.GetConsulClient()
+	assert.NilError(t, err)
+
+	action := &prov.Action{ID: "hetJobLogs", ActionType: "job-monitoring", Data: map[string]string{
+		"nodeName":   "Job",
+		"jobID":      "6270",
+		"stepName":   "run",
+		"taskID":     "t1",
+		"workingDir": filepath.Join(cfg.WorkingDirectory, t.Name()),
+	}}
+	var logCommands int
+	sshClient := &sshutil.MockSSHClient{
+		MockRunCommand: func(input string) (string, error) {
+			if strings.Contains(input, "tail -n") {
+				logCommands++
+				return "", nil
+			}
+			testdataFileContent, err := ioutil.ReadFile(filepath.Join("testdata", "scontrol_show_het_job.txt"))
+			assert.NilError(t, err)
+			return string(testdataFileContent), nil
+		},
+	}
+
+	_, err = (&actionOperator{}).analyzeJob(ctx, cc, sshClient, deploymentID, "Job", action, false)
+	assert.NilError(t, err)
+	// Both components share the same output file which should be streamed once
+	assert.Equal(t, 1, logCommands)
+}
+
 func Test_getMonitoringJobActionData(t *testing.T) {
 	type args struct {
 		action *prov.Action
@@ -172,3 +215,29 @@ func Test_getMonitoringJobActionData(t *testing.T) {
 		})
 	}
 }
+
+func Test_getLogLastIndex(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		want    int
+		wantErr bool
+	}{
+		{"NoIndex", map[string]string{}, 0, false},
+		{"Index", map[string]string{"lastIndexslurm-1.out": "5", "lastIndexStdOutStdErr": "3"}, 5, false},
+		{"LegacyIndex", map[string]string{"lastIndexStdOutStdErr": "3"}, 3, false},
+		{"InvalidIndex", map[string]string{"lastIndexslurm-1.out": "a"}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&actionOperator{}).getLogLastIndex(&prov.Action{Data: tt.data}, "lastIndexslurm-1.out", "lastIndexStdOutStdErr")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getLogLastIndex() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("getLogLastIndex() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -54,4 +54,16 @@ type jobInfo struct {
 	WorkingDir             string                      `json:"working_directory,omitempty"`
 	Artifacts              []string                    `json:"artifacts,omitempty"`
 	EnvFile                string                      `json:"env_file,omitempty"`
+	Array                  string                      `json:"array,omitempty"`
+	ArrayThrottle          int                         `json:"array_throttle,omitempty"`
+	HetJobComponents       []hetJobComponent           `json:"het_job_components,omitempty"`
+}
+
+// hetJobComponent is a yorc.datatypes.slurm.HetJobComponent
+type hetJobComponent struct {
+	Tasks int      `mapstructure:"tasks" json:"tasks,omitempty"`
+	Cpus  int      `mapstructure:"cpus_per_task" json:"cpus,omitempty"`
+	Nodes int      `mapstructure:"nodes" json:"nodes,omitempty"`
+	Mem   string   `mapstructure:"mem_per_node" json:"mem,omitempty"`
+	Opts  []string `mapstructure:"extra_options" json:"opts,omitempty"`
 }
@@ -30,6 +30,7 @@ import (
 	"github.com/ystia/yorc/v4/events"
 	"github.com/ystia/yorc/v4/helper/consulutil"
 	"github.com/ystia/yorc/v4/helper/executil"
+	"github.com/ystia/yorc/v4/helper/redactutil"
 	"github.com/ystia/yorc/v4/helper/sshutil"
 	"github.com/ystia/yorc/v4/log"
 	"github.com/ystia/yorc/v4/prov"
@@ -65,6 +66,12 @@ func (e *defaultExecutor) ExecDelegate(ctx context.Context, cfg config.Configura
 				log.Debugf("%+v", err)
 				events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, deploymentID).RegisterAsString(err.Error())
 			}
+		} else {
+			// Kept files should not disclose secrets
+			err := redactutil.RedactFiles(deploymentID, infrastructurePath)
+			if err != nil {
+				log.Printf("Failed to redact secrets from Terraform infrastructure directory %q: %v", infrastructurePath, err)
+			}
 		}
 	}()
 	op := strings.ToLower(delegateOperation)
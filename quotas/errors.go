@@ -0,0 +1,58 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quotas
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+)
+
+type quotaExceededError struct {
+	quota     string
+	resource  string
+	limit     float64
+	used      float64
+	requested float64
+}
+
+func (e quotaExceededError) Error() string {
+	return fmt.Sprintf("quota %q exceeded for %s: %s requested while %s of %s are already used", e.quota, e.resource,
+		FormatAmount(e.resource, e.requested), FormatAmount(e.resource, e.used), FormatAmount(e.resource, e.limit))
+}
+
+// IsQuotaExceededError checks if an error is a "quota exceeded" error
+func IsQuotaExceededError(err error) bool {
+	_, ok := errors.Cause(err).(quotaExceededError)
+	return ok
+}
+
+// Unit returns the unit of the amounts of a resource or an empty string for a number of items
+func Unit(resource string) string {
+	if resource == ResourceHostsPoolMemory {
+		return UnitBytes
+	}
+	return ""
+}
+
+// FormatAmount returns a human readable representation of an amount of resource
+func FormatAmount(resource string, amount float64) string {
+	if Unit(resource) == UnitBytes {
+		return humanize.Bytes(uint64(amount))
+	}
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
@@ -0,0 +1,257 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package quotas is responsible for handling quotas limiting the number of deployments
+// and the resources used by the deployments selected by a location and labels
+package quotas
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/helper/labelsutil"
+	"github.com/ystia/yorc/v4/log"
+)
+
+// Names of the resources limited by quotas
+const (
+	// ResourceDeployments is the number of deployments that are not undeployed
+	ResourceDeployments = "deployments"
+	// ResourceComputeInstances is the number of instances of nodes derived from tosca.nodes.Compute
+	ResourceComputeInstances = "compute_instances"
+	// ResourceConcurrentTasks is the number of tasks in INITIAL or RUNNING status
+	ResourceConcurrentTasks = "concurrent_tasks"
+	// ResourceHostsPoolCPUs is the number of CPUs allocated on hosts pools
+	ResourceHostsPoolCPUs = "hosts_pool_cpus"
+	// ResourceHostsPoolMemory is the memory in bytes allocated on hosts pools
+	ResourceHostsPoolMemory = "hosts_pool_memory"
+	// ResourceHostsPoolGenericResourcePrefix is the prefix of the name of a generic resource allocated on hosts pools
+	ResourceHostsPoolGenericResourcePrefix = "hosts_pool_generic_resource."
+)
+
+// UnitBytes is the unit of resources amounts expressed in bytes
+const UnitBytes = "bytes"
+
+var quotaNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+// Quota limits the resources used by the deployments it applies to
+type Quota struct {
+	Name string `json:"name"`
+	// Location restricts the quota to the resources of this location, the quota applies to all locations if empty
+	Location string `json:"location,omitempty"`
+	// Selector is a list of labels filters that deployments labels should all match, the quota applies to all deployments if empty
+	Selector []string `json:"selector,omitempty"`
+	Limits   Limits   `json:"limits"`
+}
+
+// Limits are the maximum amounts of resources allowed by a quota, a nil or empty limit means unlimited
+type Limits struct {
+	Deployments      *int `json:"deployments,omitempty"`
+	ComputeInstances *int `json:"compute_instances,omitempty"`
+	ConcurrentTasks  *int `json:"concurrent_tasks,omitempty"`
+	HostsPoolCPUs    *int `json:"hosts_pool_cpus,omitempty"`
+	// HostsPoolMemory is a human readable size like "64 GB"
+	HostsPoolMemory string `json:"hosts_pool_memory,omitempty"`
+	// HostsPoolGenericResources are the maximum numbers of generic resources indexed by generic resource name
+	HostsPoolGenericResources map[string]int `json:"hosts_pool_generic_resources,omitempty"`
+}
+
+// Amounts are amounts of resources indexed by resource name
+type Amounts map[string]float64
+
+// Add adds other amounts to these amounts
+func (a Amounts) Add(other Amounts) {
+	for name, value := range other {
+		a[name] += value
+	}
+}
+
+func quotaPath(name string) string {
+	return path.Join(consulutil.QuotasKVPrefix, name)
+}
+
+// Validate checks that a quota definition is valid
+func (q *Quota) Validate() error {
+	if !quotaNameRegexp.MatchString(q.Name) {
+		return errors.Errorf("invalid quota name %q, it should match %q", q.Name, quotaNameRegexp.String())
+	}
+	if _, err := q.filters(); err != nil {
+		return err
+	}
+	if _, err := q.Limits.amounts(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (q *Quota) filters() ([]labelsutil.Filter, error) {
+	filters := make([]labelsutil.Filter, len(q.Selector))
+	for i, s := range q.Selector {
+		var err error
+		filters[i], err = labelsutil.CreateFilter(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid selector %q for quota %q", s, q.Name)
+		}
+	}
+	return filters, nil
+}
+
+// MatchesLabels returns true if the given deployment labels match all the filters of the quota selector
+func (q *Quota) MatchesLabels(labels map[string]string) (bool, error) {
+	filters, err := q.filters()
+	if err != nil {
+		return false, err
+	}
+	ok, _ := labelsutil.MatchesAll(labels, filters...)
+	return ok, nil
+}
+
+// LimitedAmounts returns the limits of the quota as resources amounts
+func (q *Quota) LimitedAmounts() (Amounts, error) {
+	return q.Limits.amounts()
+}
+
+func (l Limits) amounts() (Amounts, error) {
+	amounts := make(Amounts)
+	for name, limit := range map[string]*int{
+		ResourceDeployments:      l.Deployments,
+		ResourceComputeInstances: l.ComputeInstances,
+		ResourceConcurrentTasks:  l.ConcurrentTasks,
+		ResourceHostsPoolCPUs:    l.HostsPoolCPUs,
+	} {
+		if limit == nil {
+			continue
+		}
+		if *limit < 0 {
+			return nil, errors.Errorf("invalid negative limit %d for %s", *limit, name)
+		}
+		amounts[name] = float64(*limit)
+	}
+	if l.HostsPoolMemory != "" {
+		mem, err := humanize.ParseBytes(l.HostsPoolMemory)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid limit %q for %s", l.HostsPoolMemory, ResourceHostsPoolMemory)
+		}
+		amounts[ResourceHostsPoolMemory] = float64(mem)
+	}
+	for name, limit := range l.HostsPoolGenericResources {
+		if name == "" {
+			return nil, errors.New("empty generic resources names are not allowed")
+		}
+		if limit < 0 {
+			return nil, errors.Errorf("invalid negative limit %d for generic resource %q", limit, name)
+		}
+		amounts[ResourceHostsPoolGenericResourcePrefix+name] = float64(limit)
+	}
+	return amounts, nil
+}
+
+// LimitsHostsPoolResources returns true if the quota defines a limit on hosts pools resources
+func (q *Quota) LimitsHostsPoolResources() bool {
+	return q.Limits.HostsPoolCPUs != nil || q.Limits.HostsPoolMemory != "" || len(q.Limits.HostsPoolGenericResources) > 0
+}
+
+// AppliesToLocation returns true if the quota applies to resources of the given location
+func (q *Quota) AppliesToLocation(location string) bool {
+	return q.Location == "" || q.Location == location
+}
+
+// Check returns a quota exceeded error if one of the requested amounts of resources added to the used ones exceeds a
+// limit of the quota
+//
+// Only resources actually requested are checked so that a quota lowered under the current usage doesn't prevent to
+// use other resources.
+func (q *Quota) Check(used, requested Amounts) error {
+	limits, err := q.LimitedAmounts()
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(requested))
+	for name := range requested {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		limit, ok := limits[name]
+		if !ok || requested[name] <= 0 {
+			continue
+		}
+		if used[name]+requested[name] > limit {
+			return errors.WithStack(quotaExceededError{quota: q.Name, resource: name, limit: limit, used: used[name], requested: requested[name]})
+		}
+	}
+	return nil
+}
+
+// StoreQuota validates and stores a quota definition, an existing quota with the same name is replaced
+func StoreQuota(ctx context.Context, q *Quota) error {
+	if err := q.Validate(); err != nil {
+		return err
+	}
+	log.Debugf("Storing quota %q", q.Name)
+	err := consulutil.StoreConsulKeyWithJSONValue(quotaPath(q.Name), q)
+	return errors.Wrapf(err, "failed to store quota %q", q.Name)
+}
+
+// GetQuota returns a quota definition or nil if it doesn't exist
+func GetQuota(ctx context.Context, name string) (*Quota, error) {
+	exist, value, err := consulutil.GetValue(quotaPath(name))
+	if err != nil || !exist {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	return unmarshalQuota(value)
+}
+
+// GetQuotas returns all quotas definitions sorted by name
+func GetQuotas(ctx context.Context) ([]*Quota, error) {
+	kvps, err := consulutil.List(consulutil.QuotasKVPrefix + "/")
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	quotas := make([]*Quota, 0, len(kvps))
+	for k, v := range kvps {
+		if strings.Contains(strings.TrimPrefix(k, consulutil.QuotasKVPrefix+"/"), "/") {
+			continue
+		}
+		q, err := unmarshalQuota(v)
+		if err != nil {
+			return nil, err
+		}
+		quotas = append(quotas, q)
+	}
+	sort.Slice(quotas, func(i, j int) bool {
+		return quotas[i].Name < quotas[j].Name
+	})
+	return quotas, nil
+}
+
+// DeleteQuota removes a quota definition
+func DeleteQuota(ctx context.Context, name string) error {
+	log.Debugf("Deleting quota %q", name)
+	return errors.Wrap(consulutil.Delete(quotaPath(name), false), consulutil.ConsulGenericErrMsg)
+}
+
+func unmarshalQuota(definition []byte) (*Quota, error) {
+	q := new(Quota)
+	err := json.Unmarshal(definition, q)
+	return q, errors.Wrap(err, "failed to read quota definition")
+}
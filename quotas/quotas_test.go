@@ -0,0 +1,278 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quotas
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/tasks"
+	"github.com/ystia/yorc/v4/testutil"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestQuotaValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		quota   Quota
+		wantErr bool
+	}{
+		{"Empty", Quota{Name: "q"}, false},
+		{"Full", Quota{Name: "team-a_1.0", Location: "hp", Selector: []string{`team="a"`}, Limits: Limits{
+			Deployments: intPtr(2), ComputeInstances: intPtr(10), ConcurrentTasks: intPtr(3), HostsPoolCPUs: intPtr(16),
+			HostsPoolMemory: "64 GB", HostsPoolGenericResources: map[string]int{"gpu": 2}}}, false},
+		{"NoName", Quota{}, true},
+		{"InvalidName", Quota{Name: "team/a"}, true},
+		{"InvalidSelector", Quota{Name: "q", Selector: []string{`team in (`}}, true},
+		{"InvalidMemory", Quota{Name: "q", Limits: Limits{HostsPoolMemory: "lots"}}, true},
+		{"NegativeLimit", Quota{Name: "q", Limits: Limits{Deployments: intPtr(-1)}}, true},
+		{"NegativeGenericResourceLimit", Quota{Name: "q", Limits: Limits{HostsPoolGenericResources: map[string]int{"gpu": -1}}}, true},
+		{"EmptyGenericResourceName", Quota{Name: "q", Limits: Limits{HostsPoolGenericResources: map[string]int{"": 1}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.quota.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestQuotaMatchesLabels(t *testing.T) {
+	q := Quota{Name: "q", Selector: []string{`team="hpc"`, `env != "prod"`}}
+	ok, err := q.MatchesLabels(map[string]string{"team": "hpc", "env": "dev"})
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = q.MatchesLabels(map[string]string{"team": "hpc", "env": "prod"})
+	require.NoError(t, err)
+	require.False(t, ok)
+	ok, err = q.MatchesLabels(nil)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	q = Quota{Name: "all"}
+	ok, err = q.MatchesLabels(nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestQuotaCheck(t *testing.T) {
+	q := Quota{Name: "q", Limits: Limits{ComputeInstances: intPtr(4), HostsPoolMemory: "8 GB", HostsPoolGenericResources: map[string]int{"gpu": 1}}}
+	tests := []struct {
+		name      string
+		used      Amounts
+		requested Amounts
+		wantErr   string
+	}{
+		{"UnderLimit", Amounts{ResourceComputeInstances: 1}, Amounts{ResourceComputeInstances: 3}, ""},
+		{"OverLimit", Amounts{ResourceComputeInstances: 2}, Amounts{ResourceComputeInstances: 3},
+			`quota "q" exceeded for compute_instances: 3 requested while 2 of 4 are already used`},
+		{"Unlimited", Amounts{ResourceDeployments: 100}, Amounts{ResourceDeployments: 1}, ""},
+		{"NotRequested", Amounts{ResourceComputeInstances: 10}, Amounts{ResourceComputeInstances: 0, ResourceConcurrentTasks: 1}, ""},
+		{"MemoryOverLimit", Amounts{ResourceHostsPoolMemory: 6e9}, Amounts{ResourceHostsPoolMemory: 4e9},
+			`quota "q" exceeded for hosts_pool_memory: 4.0 GB requested while 6.0 GB of 8.0 GB are already used`},
+		{"GenericResourceOverLimit", Amounts{}, Amounts{ResourceHostsPoolGenericResourcePrefix + "gpu": 2},
+			`quota "q" exceeded for hosts_pool_generic_resource.gpu: 2 requested while 0 of 1 are already used`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := q.Check(tt.used, tt.requested)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.wantErr)
+			require.True(t, IsQuotaExceededError(err))
+		})
+	}
+}
+
+func testQuotasStorage(t *testing.T) {
+	ctx := context.Background()
+
+	q, err := GetQuota(ctx, "teamB")
+	require.NoError(t, err)
+	require.Nil(t, q)
+
+	err = StoreQuota(ctx, &Quota{Name: "teamB", Selector: []string{`team="b"`}, Limits: Limits{Deployments: intPtr(2)}})
+	require.NoError(t, err)
+	err = StoreQuota(ctx, &Quota{Name: "teamA", Location: "hp", Limits: Limits{HostsPoolMemory: "16 GB"}})
+	require.NoError(t, err)
+	err = StoreQuota(ctx, &Quota{Name: "invalid/name"})
+	require.Error(t, err)
+
+	q, err = GetQuota(ctx, "teamB")
+	require.NoError(t, err)
+	require.NotNil(t, q)
+	require.Equal(t, []string{`team="b"`}, q.Selector)
+	require.Equal(t, 2, *q.Limits.Deployments)
+
+	quotas, err := GetQuotas(ctx)
+	require.NoError(t, err)
+	require.Len(t, quotas, 2)
+	require.Equal(t, "teamA", quotas[0].Name)
+	require.Equal(t, "hp", quotas[0].Location)
+	require.Equal(t, "teamB", quotas[1].Name)
+
+	for _, name := range []string{"teamA", "teamB"} {
+		require.NoError(t, DeleteQuota(ctx, name))
+	}
+	quotas, err = GetQuotas(ctx)
+	require.NoError(t, err)
+	require.Len(t, quotas, 0)
+}
+
+// registerTestTask registers a task through the quotas checks, the task is stored with the given status
+func registerTestTask(ctx context.Context, cc *api.Client, deploymentID string, taskType tasks.TaskType, data map[string]string, status tasks.TaskStatus) (string, error) {
+	return RegisterTask(ctx, cc, deploymentID, taskType, data, func() (string, error) {
+		taskID := fmt.Sprint(uuid.NewV4())
+		err := consulutil.StoreConsulKeyAsString(path.Join(consulutil.TasksPrefix, taskID, "targetId"), deploymentID)
+		if err != nil {
+			return "", err
+		}
+		err = consulutil.StoreConsulKeyAsString(path.Join(consulutil.TasksPrefix, taskID, "type"), strconv.Itoa(int(taskType)))
+		if err != nil {
+			return "", err
+		}
+		return taskID, setTestTaskStatus(taskID, status)
+	})
+}
+
+func setTestTaskStatus(taskID string, status tasks.TaskStatus) error {
+	return consulutil.StoreConsulKeyAsString(path.Join(consulutil.TasksPrefix, taskID, "status"), strconv.Itoa(int(status)))
+}
+
+func testQuotasEnforcement(t *testing.T, cc *api.Client) {
+	ctx := context.Background()
+	deploymentID := testutil.BuildDeploymentID(t)
+	err := deployments.StoreDeploymentDefinition(ctx, deploymentID, "testdata/topology.yml")
+	require.NoError(t, err)
+	err = deployments.UpdateDeploymentLabels(ctx, deploymentID, map[string]string{"team": "hpc"}, nil)
+	require.NoError(t, err)
+
+	q := &Quota{Name: "hpc", Selector: []string{`team="hpc"`}, Limits: Limits{Deployments: intPtr(1), ComputeInstances: intPtr(3), ConcurrentTasks: intPtr(2)}}
+	require.NoError(t, StoreQuota(ctx, q))
+	defer DeleteQuota(ctx, q.Name)
+
+	// The deployment is accounted once its deployment task is registered
+	used, err := ComputeUsage(ctx, q)
+	require.NoError(t, err)
+	require.Equal(t, Amounts{ResourceDeployments: 0, ResourceComputeInstances: 0, ResourceConcurrentTasks: 0}, used)
+	deployTaskID, err := registerTestTask(ctx, cc, deploymentID, tasks.TaskTypeDeploy, map[string]string{"workflowName": "install"}, tasks.TaskStatusRUNNING)
+	require.NoError(t, err)
+	used, err = ComputeUsage(ctx, q)
+	require.NoError(t, err)
+	require.Equal(t, Amounts{ResourceDeployments: 1, ResourceComputeInstances: 3, ResourceConcurrentTasks: 1}, used)
+
+	scaleOut := map[string]string{"workflowName": "install", "nodeName": "Compute1", "instancesDelta": "1"}
+	_, err = registerTestTask(ctx, cc, deploymentID, tasks.TaskTypeScaleOut, scaleOut, tasks.TaskStatusINITIAL)
+	require.Error(t, err)
+	require.True(t, IsQuotaExceededError(err), "unexpected error %+v", err)
+
+	// Every task of the deployment is accounted by the concurrent tasks quota
+	workflow := map[string]string{"workflowName": "run"}
+	workflowTaskID, err := registerTestTask(ctx, cc, deploymentID, tasks.TaskTypeCustomWorkflow, workflow, tasks.TaskStatusINITIAL)
+	require.NoError(t, err)
+	_, err = registerTestTask(ctx, cc, deploymentID, tasks.TaskTypeCustomWorkflow, workflow, tasks.TaskStatusINITIAL)
+	require.Error(t, err)
+	require.True(t, IsQuotaExceededError(err), "unexpected error %+v", err)
+	require.NoError(t, setTestTaskStatus(deployTaskID, tasks.TaskStatusDONE))
+	require.NoError(t, setTestTaskStatus(workflowTaskID, tasks.TaskStatusDONE))
+
+	otherID := deploymentID + "-other"
+	err = deployments.StoreDeploymentDefinition(ctx, otherID, "testdata/topology.yml")
+	require.NoError(t, err)
+	_, err = registerTestTask(ctx, cc, otherID, tasks.TaskTypeDeploy, map[string]string{"workflowName": "install"}, tasks.TaskStatusDONE)
+	require.NoError(t, err, "deployment without labels should not be affected by the quota")
+	require.NoError(t, deployments.DeleteDeployment(ctx, otherID))
+	exist, _, err := consulutil.GetValue(usagePath(otherID))
+	require.NoError(t, err)
+	require.True(t, exist)
+
+	hpcID := deploymentID + "-hpc"
+	err = deployments.StoreDeploymentDefinition(ctx, hpcID, "testdata/topology.yml")
+	require.NoError(t, err)
+	err = deployments.UpdateDeploymentLabels(ctx, hpcID, map[string]string{"team": "hpc"}, nil)
+	require.NoError(t, err)
+	_, err = RegisterTask(ctx, cc, hpcID, tasks.TaskTypeDeploy, nil, func() (string, error) {
+		require.Fail(t, "task registered while exceeding quota")
+		return "", nil
+	})
+	require.Error(t, err)
+	require.True(t, IsQuotaExceededError(err), "unexpected error %+v", err)
+	require.NoError(t, deployments.DeleteDeployment(ctx, hpcID))
+
+	// Records of purged deployments are removed on the next registration
+	exist, _, err = consulutil.GetValue(usagePath(otherID))
+	require.NoError(t, err)
+	require.False(t, exist)
+
+	// Only compute nodes placed on the quota location are accounted
+	q.Location = "hostsPool1"
+	require.NoError(t, StoreQuota(ctx, q))
+	used, err = ComputeUsage(ctx, q)
+	require.NoError(t, err)
+	require.Equal(t, Amounts{ResourceDeployments: 1, ResourceComputeInstances: 2, ResourceConcurrentTasks: 0}, used)
+	_, err = registerTestTask(ctx, cc, deploymentID, tasks.TaskTypeScaleOut, scaleOut, tasks.TaskStatusDONE)
+	require.NoError(t, err)
+	scaleOut["nodeName"] = "Compute2"
+	scaleOut["instancesDelta"] = "4"
+	_, err = registerTestTask(ctx, cc, deploymentID, tasks.TaskTypeScaleOut, scaleOut, tasks.TaskStatusDONE)
+	require.NoError(t, err)
+	scaleOut["nodeName"] = "Compute1"
+	scaleOut["instancesDelta"] = "1"
+	_, err = registerTestTask(ctx, cc, deploymentID, tasks.TaskTypeScaleOut, scaleOut, tasks.TaskStatusDONE)
+	require.True(t, IsQuotaExceededError(err), "unexpected error %+v", err)
+	scaleIn := map[string]string{"workflowName": "uninstall", "nodeName": "Compute1", "nodes/Compute1": "0,1"}
+	_, err = registerTestTask(ctx, cc, deploymentID, tasks.TaskTypeScaleIn, scaleIn, tasks.TaskStatusDONE)
+	require.NoError(t, err)
+	used, err = ComputeUsage(ctx, q)
+	require.NoError(t, err)
+	require.Equal(t, float64(1), used[ResourceComputeInstances])
+
+	q.Location = "otherLocation"
+	require.NoError(t, StoreQuota(ctx, q))
+	used, err = ComputeUsage(ctx, q)
+	require.NoError(t, err)
+	require.Equal(t, float64(0), used[ResourceDeployments])
+
+	// The deployment is not accounted anymore once undeployed
+	q.Location = ""
+	require.NoError(t, StoreQuota(ctx, q))
+	undeployTaskID, err := registerTestTask(ctx, cc, deploymentID, tasks.TaskTypeUnDeploy, map[string]string{"workflowName": "uninstall"}, tasks.TaskStatusRUNNING)
+	require.NoError(t, err)
+	used, err = ComputeUsage(ctx, q)
+	require.NoError(t, err)
+	require.Equal(t, Amounts{ResourceDeployments: 1, ResourceComputeInstances: 6, ResourceConcurrentTasks: 1}, used)
+	require.NoError(t, setTestTaskStatus(undeployTaskID, tasks.TaskStatusDONE))
+	used, err = ComputeUsage(ctx, q)
+	require.NoError(t, err)
+	require.Equal(t, Amounts{ResourceDeployments: 0, ResourceComputeInstances: 0, ResourceConcurrentTasks: 0}, used)
+}
@@ -0,0 +1,481 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quotas
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/tasks"
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+// usageLockKey is the key of the Consul lock serializing quotas checks and the registration of the tasks they account
+var usageLockKey = path.Join(consulutil.YorcServicePrefix, "quotas", "lock")
+
+// deploymentUsage is the record of the resources of a deployment accounted by quotas
+//
+// It is updated when a task of the deployment is registered so that quotas usage is computed without scanning
+// the topologies and tasks of all deployments.
+type deploymentUsage struct {
+	// Locations are the locations of the deployment nodes, nodes without location have an empty location
+	Locations []string `json:"locations"`
+	// ComputeInstances are the numbers of compute instances of the deployment indexed by location
+	ComputeInstances map[string]float64 `json:"compute_instances"`
+	// Tasks are the IDs of the tasks of the deployment which are not ended
+	Tasks []string `json:"tasks,omitempty"`
+	// Undeployed is true if the deployment was undeployed when its record was built
+	Undeployed bool `json:"undeployed,omitempty"`
+	// UndeployTaskID is the ID of the latest undeployment or purge task, the deployment is undeployed once it is done
+	UndeployTaskID string `json:"undeploy_task_id,omitempty"`
+}
+
+// usageState holds the usage records of the deployments and caches the data needed to account them
+type usageState struct {
+	records map[string]*deploymentUsage
+	// stale are the IDs of deployments which records remain while they were purged
+	stale    []string
+	labels   map[string]map[string]string
+	statuses map[string]tasks.TaskStatus
+}
+
+func usagePath(deploymentID string) string {
+	return path.Join(consulutil.QuotasUsageKVPrefix, deploymentID)
+}
+
+func newUsageState() *usageState {
+	return &usageState{
+		records:  make(map[string]*deploymentUsage),
+		labels:   make(map[string]map[string]string),
+		statuses: make(map[string]tasks.TaskStatus),
+	}
+}
+
+// loadUsage reads the usage records of all deployments
+//
+// Records of deployments submitted before quotas usage was recorded are built from their topology, they are stored
+// only if persist is true. Deployments in INITIAL status are not accounted until their deployment task is registered.
+func loadUsage(ctx context.Context, persist bool) (*usageState, error) {
+	deploymentsIDs, err := deployments.GetDeploymentsIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	exist := make(map[string]bool, len(deploymentsIDs))
+	for _, id := range deploymentsIDs {
+		exist[id] = true
+	}
+	kvps, err := consulutil.List(consulutil.QuotasUsageKVPrefix + "/")
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	s := newUsageState()
+	for k, v := range kvps {
+		deploymentID := path.Base(k)
+		if !exist[deploymentID] {
+			s.stale = append(s.stale, deploymentID)
+			continue
+		}
+		u := new(deploymentUsage)
+		err = json.Unmarshal(v, u)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read quotas usage of deployment %q", deploymentID)
+		}
+		s.records[deploymentID] = u
+	}
+	for _, deploymentID := range deploymentsIDs {
+		if _, ok := s.records[deploymentID]; ok {
+			continue
+		}
+		status, err := deployments.GetDeploymentStatus(ctx, deploymentID)
+		if deployments.IsDeploymentNotFoundError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if status == deployments.INITIAL {
+			continue
+		}
+		u, err := s.buildDeploymentUsage(ctx, deploymentID)
+		if err != nil {
+			return nil, err
+		}
+		u.Undeployed = status == deployments.UNDEPLOYED
+		if persist {
+			err = storeDeploymentUsage(deploymentID, u)
+			if err != nil {
+				return nil, err
+			}
+		}
+		s.records[deploymentID] = u
+	}
+	return s, nil
+}
+
+func storeDeploymentUsage(deploymentID string, u *deploymentUsage) error {
+	err := consulutil.StoreConsulKeyWithJSONValue(usagePath(deploymentID), u)
+	return errors.Wrapf(err, "failed to store quotas usage of deployment %q", deploymentID)
+}
+
+// deleteStale removes the records of purged deployments
+func (s *usageState) deleteStale() {
+	for _, deploymentID := range s.stale {
+		err := consulutil.Delete(usagePath(deploymentID), false)
+		if err != nil {
+			log.Printf("[WARNING] Failed to remove quotas usage of purged deployment %q: %v", deploymentID, err)
+		}
+	}
+}
+
+// buildDeploymentUsage builds the usage record of a deployment from its topology and tasks
+func (s *usageState) buildDeploymentUsage(ctx context.Context, deploymentID string) (*deploymentUsage, error) {
+	nodes, err := deployments.GetNodes(ctx, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	u := &deploymentUsage{Locations: make([]string, 0), ComputeInstances: make(map[string]float64)}
+	locations := make(map[string]bool)
+	for _, node := range nodes {
+		location, compute, err := computeNodeLocation(ctx, deploymentID, node)
+		if err != nil {
+			return nil, err
+		}
+		if !locations[location] {
+			locations[location] = true
+			u.Locations = append(u.Locations, location)
+		}
+		if !compute {
+			continue
+		}
+		instances, err := deployments.GetNodeInstancesIds(ctx, deploymentID, node)
+		if err != nil {
+			return nil, err
+		}
+		u.ComputeInstances[location] += float64(len(instances))
+	}
+	tasksIDs, err := deployments.GetDeploymentTaskList(ctx, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, taskID := range tasksIDs {
+		ended, err := s.isTaskEnded(taskID)
+		if err != nil {
+			return nil, err
+		}
+		if !ended {
+			u.Tasks = append(u.Tasks, taskID)
+		}
+	}
+	return u, nil
+}
+
+// taskStatus returns the status of a task, the returned boolean is false if the task doesn't exist anymore
+func (s *usageState) taskStatus(taskID string) (tasks.TaskStatus, bool, error) {
+	if status, ok := s.statuses[taskID]; ok {
+		return status, true, nil
+	}
+	status, err := tasks.GetTaskStatus(taskID)
+	if tasks.IsTaskNotFoundError(err) {
+		return status, false, nil
+	}
+	if err != nil {
+		return status, false, err
+	}
+	s.statuses[taskID] = status
+	return status, true, nil
+}
+
+// isTaskEnded returns true if a task is done, canceled or doesn't exist anymore, failed tasks could be resumed
+func (s *usageState) isTaskEnded(taskID string) (bool, error) {
+	status, found, err := s.taskStatus(taskID)
+	if err != nil {
+		return false, err
+	}
+	return !found || status == tasks.TaskStatusDONE || status == tasks.TaskStatusCANCELED, nil
+}
+
+// amounts returns the resources of a deployment accounted by a quota
+//
+// The returned boolean is false if the quota doesn't apply to the deployment: its labels don't match the quota
+// selector, it is undeployed or none of its nodes is placed on the quota location.
+func (s *usageState) amounts(ctx context.Context, q *Quota, deploymentID string, u *deploymentUsage) (Amounts, bool, error) {
+	if u == nil || u.Undeployed {
+		return nil, false, nil
+	}
+	if u.UndeployTaskID != "" {
+		status, found, err := s.taskStatus(u.UndeployTaskID)
+		if err != nil || !found || status == tasks.TaskStatusDONE {
+			return nil, false, err
+		}
+	}
+	if len(q.Selector) > 0 {
+		labels, ok := s.labels[deploymentID]
+		if !ok {
+			var err error
+			labels, err = deployments.GetDeploymentLabels(ctx, deploymentID)
+			if err != nil {
+				return nil, false, err
+			}
+			s.labels[deploymentID] = labels
+		}
+		match, err := q.MatchesLabels(labels)
+		if err != nil || !match {
+			return nil, false, err
+		}
+	}
+	onLocation := q.Location == ""
+	for _, location := range u.Locations {
+		onLocation = onLocation || q.AppliesToLocation(location)
+	}
+	if !onLocation {
+		return nil, false, nil
+	}
+	amounts := Amounts{ResourceDeployments: 1, ResourceComputeInstances: 0, ResourceConcurrentTasks: 0}
+	for location, instances := range u.ComputeInstances {
+		if q.AppliesToLocation(location) {
+			amounts[ResourceComputeInstances] += instances
+		}
+	}
+	for _, taskID := range u.Tasks {
+		status, found, err := s.taskStatus(taskID)
+		if err != nil {
+			return nil, false, err
+		}
+		if found && (status == tasks.TaskStatusINITIAL || status == tasks.TaskStatusRUNNING) {
+			amounts[ResourceConcurrentTasks]++
+		}
+	}
+	return amounts, true, nil
+}
+
+// usage returns the resources of all deployments accounted by a quota
+func (s *usageState) usage(ctx context.Context, q *Quota) (Amounts, error) {
+	used := Amounts{
+		ResourceDeployments:      0,
+		ResourceComputeInstances: 0,
+		ResourceConcurrentTasks:  0,
+	}
+	for deploymentID, u := range s.records {
+		amounts, ok, err := s.amounts(ctx, q, deploymentID, u)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			used.Add(amounts)
+		}
+	}
+	return used, nil
+}
+
+// MatchesDeployment returns true if the labels of a deployment match the quota selector
+func MatchesDeployment(ctx context.Context, q *Quota, deploymentID string) (bool, error) {
+	labels, err := deployments.GetDeploymentLabels(ctx, deploymentID)
+	if err != nil {
+		return false, err
+	}
+	return q.MatchesLabels(labels)
+}
+
+// ComputeUsage returns the number of deployments, compute instances and concurrent tasks of the deployments the quota
+// applies to
+//
+// Hosts pools resources are accounted by the hosts pool manager.
+func ComputeUsage(ctx context.Context, q *Quota) (Amounts, error) {
+	s, err := loadUsage(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.usage(ctx, q)
+}
+
+// RegisterTask checks that the quotas applying to a deployment allow to register a new task of the given type,
+// the task is then registered by calling register and the resources of the deployment are recorded
+//
+// Deployment tasks account the deployment and its compute instances, scale out tasks account the added instances.
+// Checks and registrations are serialized by a Consul lock so that concurrent registrations can't exceed a quota.
+// An error checked by IsQuotaExceededError is returned if the task would exceed a quota.
+func RegisterTask(ctx context.Context, cc *api.Client, deploymentID string, taskType tasks.TaskType, data map[string]string, register func() (string, error)) (string, error) {
+	lock, err := consulutil.AcquireLock(cc, usageLockKey, 0)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to acquire quotas lock")
+	}
+	defer func() {
+		if err := lock.Unlock(); err != nil {
+			log.Printf("[WARNING] Failed to release quotas lock: %v", err)
+		}
+	}()
+
+	s, err := loadUsage(ctx, true)
+	if err != nil {
+		return "", err
+	}
+	s.deleteStale()
+	current := s.records[deploymentID]
+	if current == nil && taskType != tasks.TaskTypeDeploy {
+		// The deployment was never deployed
+		return register()
+	}
+	next, err := s.nextDeploymentUsage(ctx, deploymentID, current, taskType, data)
+	if err != nil {
+		return "", err
+	}
+	err = s.check(ctx, deploymentID, current, next)
+	if err != nil {
+		return "", err
+	}
+
+	taskID, err := register()
+	if err != nil {
+		return "", err
+	}
+	next.Tasks = append(next.Tasks, taskID)
+	switch taskType {
+	case tasks.TaskTypeUnDeploy, tasks.TaskTypePurge, tasks.TaskTypeForcePurge:
+		next.UndeployTaskID = taskID
+	}
+	err = storeDeploymentUsage(deploymentID, next)
+	if err != nil {
+		log.Printf("[WARNING] Task %q is registered but quotas may not account it: %v", taskID, err)
+	}
+	return taskID, nil
+}
+
+// check checks that the quotas allow a deployment to use the resources of its next usage record and a new task
+func (s *usageState) check(ctx context.Context, deploymentID string, current, next *deploymentUsage) error {
+	quotas, err := GetQuotas(ctx)
+	if err != nil {
+		return err
+	}
+	for _, q := range quotas {
+		before, _, err := s.amounts(ctx, q, deploymentID, current)
+		if err != nil {
+			return err
+		}
+		after, ok, err := s.amounts(ctx, q, deploymentID, next)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		after[ResourceConcurrentTasks]++
+		used, err := s.usage(ctx, q)
+		if err != nil {
+			return err
+		}
+		// Only resources added by the task are checked
+		requested := make(Amounts, len(after))
+		for name, value := range after {
+			requested[name] = value - before[name]
+		}
+		if err = q.Check(used, requested); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nextDeploymentUsage returns the usage record of a deployment once a task of the given type is registered
+//
+// Ended tasks are removed from the record. The record of a deployment task is rebuilt from the deployment
+// topology, compute instances added or removed by scaling tasks are accounted as soon as they are registered.
+func (s *usageState) nextDeploymentUsage(ctx context.Context, deploymentID string, current *deploymentUsage, taskType tasks.TaskType, data map[string]string) (*deploymentUsage, error) {
+	if taskType == tasks.TaskTypeDeploy {
+		return s.buildDeploymentUsage(ctx, deploymentID)
+	}
+	next := &deploymentUsage{
+		Locations:        current.Locations,
+		ComputeInstances: make(map[string]float64, len(current.ComputeInstances)),
+		Undeployed:       current.Undeployed,
+		UndeployTaskID:   current.UndeployTaskID,
+	}
+	for location, instances := range current.ComputeInstances {
+		next.ComputeInstances[location] = instances
+	}
+	for _, taskID := range current.Tasks {
+		ended, err := s.isTaskEnded(taskID)
+		if err != nil {
+			return nil, err
+		}
+		if !ended {
+			next.Tasks = append(next.Tasks, taskID)
+		}
+	}
+
+	switch taskType {
+	case tasks.TaskTypeScaleOut:
+		nodeName := data["nodeName"]
+		delta, err := strconv.Atoi(data["instancesDelta"])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid instances delta %q", data["instancesDelta"])
+		}
+		stack, err := deployments.GetNodesHostedOn(ctx, deploymentID, nodeName)
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range append(stack, nodeName) {
+			location, compute, err := computeNodeLocation(ctx, deploymentID, node)
+			if err != nil {
+				return nil, err
+			}
+			if compute {
+				next.ComputeInstances[location] += float64(delta)
+			}
+		}
+	case tasks.TaskTypeScaleIn:
+		for k, v := range data {
+			if !strings.HasPrefix(k, "nodes/") || v == "" {
+				continue
+			}
+			location, compute, err := computeNodeLocation(ctx, deploymentID, path.Base(k))
+			if err != nil {
+				return nil, err
+			}
+			if !compute {
+				continue
+			}
+			next.ComputeInstances[location] -= float64(len(strings.Split(v, ",")))
+			if next.ComputeInstances[location] < 0 {
+				next.ComputeInstances[location] = 0
+			}
+		}
+	}
+	return next, nil
+}
+
+// computeNodeLocation returns the location of a node and true if it is a compute node
+func computeNodeLocation(ctx context.Context, deploymentID, nodeName string) (string, bool, error) {
+	location, err := nodeLocation(ctx, deploymentID, nodeName)
+	if err != nil {
+		return "", false, err
+	}
+	compute, err := deployments.IsNodeDerivedFrom(ctx, deploymentID, nodeName, "tosca.nodes.Compute")
+	return location, compute, err
+}
+
+// nodeLocation returns the location set in the node template metadata or an empty string if none
+func nodeLocation(ctx context.Context, deploymentID, nodeName string) (string, error) {
+	_, location, err := deployments.GetNodeMetadata(ctx, deploymentID, nodeName, tosca.MetadataLocationNameKey)
+	return location, errors.Wrapf(err, "failed to get location of node %q", nodeName)
+}
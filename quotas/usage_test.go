@@ -0,0 +1,71 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quotas
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/tasks"
+)
+
+func TestUsageStateUsage(t *testing.T) {
+	s := newUsageState()
+	s.labels = map[string]map[string]string{
+		"d1": {"team": "hpc"},
+		"d2": {"team": "hpc"},
+		"d3": {"team": "hpc"},
+		"d4": {"team": "hpc"},
+		"d5": {"team": "web"},
+	}
+	s.statuses = map[string]tasks.TaskStatus{
+		"t1": tasks.TaskStatusRUNNING,
+		"t2": tasks.TaskStatusINITIAL,
+		"t3": tasks.TaskStatusFAILED,
+		"t4": tasks.TaskStatusDONE,
+		"t5": tasks.TaskStatusRUNNING,
+	}
+	s.records = map[string]*deploymentUsage{
+		"d1": {Locations: []string{"hp", ""}, ComputeInstances: map[string]float64{"hp": 2, "": 3}, Tasks: []string{"t1", "t2", "t3"}},
+		"d2": {Locations: []string{""}, ComputeInstances: map[string]float64{"": 1}, Undeployed: true},
+		"d3": {Locations: []string{"hp"}, ComputeInstances: map[string]float64{"hp": 4}, UndeployTaskID: "t4"},
+		"d4": {Locations: []string{"hp"}, ComputeInstances: map[string]float64{"hp": 1}, Tasks: []string{"t5"}, UndeployTaskID: "t5"},
+		"d5": {Locations: []string{"hp"}, ComputeInstances: map[string]float64{"hp": 8}},
+	}
+
+	tests := []struct {
+		name  string
+		quota Quota
+		want  Amounts
+	}{
+		{"AllDeployments", Quota{Name: "all"},
+			Amounts{ResourceDeployments: 3, ResourceComputeInstances: 14, ResourceConcurrentTasks: 3}},
+		{"Selector", Quota{Name: "hpc", Selector: []string{`team="hpc"`}},
+			Amounts{ResourceDeployments: 2, ResourceComputeInstances: 6, ResourceConcurrentTasks: 3}},
+		{"Location", Quota{Name: "hp", Location: "hp", Selector: []string{`team="hpc"`}},
+			Amounts{ResourceDeployments: 2, ResourceComputeInstances: 3, ResourceConcurrentTasks: 3}},
+		{"OtherLocation", Quota{Name: "other", Location: "other"},
+			Amounts{ResourceDeployments: 0, ResourceComputeInstances: 0, ResourceConcurrentTasks: 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.usage(context.Background(), &tt.quota)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -16,11 +16,16 @@ package registry
 
 import (
 	"regexp"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/ystia/yorc/v4/prov"
+	"github.com/ystia/yorc/v4/storage"
+	"github.com/ystia/yorc/v4/storage/store"
 	"github.com/ystia/yorc/v4/vault"
 )
 
@@ -79,12 +84,49 @@ type Registry interface {
 	GetActionOperator(actionType string) (prov.ActionOperator, error)
 	// ListActionOperators returns a map of actionTypes matches to prov.ActionOperator origin
 	ListActionOperators() []ActionTypeMatch
+
+	// RegisterToscaDefinition register a TOSCA definition content under the given name (ex: yorc-types.yml).
+	// Origin is the origin of the definition (builtin for builtin definitions or the plugin name in case of a plugin)
+	RegisterToscaDefinition(name string, data []byte, origin string)
+	// GetToscaDefinition returns the content of a TOSCA definition from its name
+	//
+	// If the given name can't match any registered definition an error is returned
+	GetToscaDefinition(name string) ([]byte, error)
+	// ListToscaDefinitions returns the list of registered TOSCA definitions
+	ListToscaDefinitions() []Definition
+
+	// RegisterStoreBuilder registers a store.Builder for the given store implementation name.
+	// This implementation name could then be used in the storage configuration.
+	// Origin is the origin of the builder (the plugin name in case of a plugin)
+	RegisterStoreBuilder(implementation string, builder store.Builder, origin string)
+	// GetStoreBuilder returns the first store.Builder that matches the given implementation name (case insensitive)
+	//
+	// If the given implementation can't match any store.Builder an error is returned
+	GetStoreBuilder(implementation string) (store.Builder, error)
+	// ListStoreBuilders returns the list of registered store implementations
+	ListStoreBuilders() []StoreBuilder
+
+	// UnregisterOrigin removes all delegate executors, operation executors, action operators,
+	// infrastructure usage collectors, vault client builders, store builders and TOSCA definitions
+	// registered with the given origin.
+	//
+	// This is typically used when a plugin stops or is reloaded.
+	UnregisterOrigin(origin string)
+
+	// SetPluginStatus registers or updates the status of a plugin
+	SetPluginStatus(status PluginStatus)
+	// RemovePluginStatus removes the status of a plugin
+	RemovePluginStatus(name string)
+	// ListPlugins returns the status of known plugins sorted by name
+	ListPlugins() []PluginStatus
 }
 
 var defaultReg Registry
 
 func init() {
 	defaultReg = &defaultRegistry{delegateMatches: make([]DelegateMatch, 0), vaultClientBuilders: make([]VaultClientBuilder, 0)}
+	// Allows storage to use stores implementations provided by plugins
+	storage.SetStoreBuilderResolver(defaultReg.GetStoreBuilder)
 }
 
 // GetRegistry returns the singleton instance of the Registry
@@ -127,18 +169,63 @@ type InfraUsageCollector struct {
 	InfraUsageCollector prov.InfraUsageCollector `json:"-"`
 }
 
+// StoreBuilder represents a store builder with the store implementation it provides and its Origin
+type StoreBuilder struct {
+	Implementation string        `json:"implementation"`
+	Origin         string        `json:"origin"`
+	Builder        store.Builder `json:"-"`
+}
+
+// PluginState is the state of a plugin
+type PluginState string
+
+const (
+	// PluginStateRunning is the state of a plugin successfully loaded and responding to health checks
+	PluginStateRunning PluginState = "running"
+	// PluginStateRestarting is the state of a plugin that crashed or failed to load and that will be restarted
+	PluginStateRestarting PluginState = "restarting"
+	// PluginStateStopped is the state of a plugin that was stopped
+	PluginStateStopped PluginState = "stopped"
+	// PluginStateRejected is the state of an executable of the plugins directory that failed to handshake with Yorc,
+	// typically because it is not a Yorc plugin. It is loaded again once updated.
+	PluginStateRejected PluginState = "rejected"
+)
+
+// PluginStatus represents the status of a plugin managed by Yorc
+type PluginStatus struct {
+	Name            string      `json:"name"`
+	Path            string      `json:"path"`
+	State           PluginState `json:"state"`
+	ProtocolVersion int         `json:"protocol_version,omitempty"`
+	Restarts        int         `json:"restarts"`
+	StartedAt       time.Time   `json:"started_at"`
+	LastError       string      `json:"last_error,omitempty"`
+}
+
+// Definition represents a TOSCA definition with its Name, Origin and Data content
+type Definition struct {
+	Name   string `json:"name"`
+	Origin string `json:"origin"`
+	Data   []byte `json:"-"`
+}
+
 type defaultRegistry struct {
 	delegateMatches          []DelegateMatch
 	operationMatches         []OperationExecMatch
 	actionTypeMatches        []ActionTypeMatch
 	vaultClientBuilders      []VaultClientBuilder
 	infraUsageCollectors     []InfraUsageCollector
+	definitions              []Definition
 	delegatesLock            sync.RWMutex
 	operationsLock           sync.RWMutex
 	definitionsLock          sync.RWMutex
 	vaultsLock               sync.RWMutex
 	infraUsageCollectorsLock sync.RWMutex
 	actionOperatorsLock      sync.RWMutex
+	storeBuilders            []StoreBuilder
+	storeBuildersLock        sync.RWMutex
+	plugins                  map[string]PluginStatus
+	pluginsLock              sync.RWMutex
 }
 
 func (r *defaultRegistry) RegisterDelegates(matches []string, executor prov.DelegateExecutor, origin string) {
@@ -235,9 +322,10 @@ func (r *defaultRegistry) ListVaultClientBuilders() []VaultClientBuilder {
 }
 
 func (r *defaultRegistry) RegisterInfraUsageCollector(name string, infraUsageCollector prov.InfraUsageCollector, origin string) {
-	r.infraUsageCollectorsLock.RLock()
-	defer r.infraUsageCollectorsLock.RUnlock()
-	r.infraUsageCollectors = append([]InfraUsageCollector{{Name: name, Origin: origin, InfraUsageCollector: infraUsageCollector}})
+	r.infraUsageCollectorsLock.Lock()
+	defer r.infraUsageCollectorsLock.Unlock()
+	// Insert as first
+	r.infraUsageCollectors = append([]InfraUsageCollector{{Name: name, Origin: origin, InfraUsageCollector: infraUsageCollector}}, r.infraUsageCollectors...)
 }
 
 func (r *defaultRegistry) GetInfraUsageCollector(name string) (prov.InfraUsageCollector, error) {
@@ -290,3 +378,159 @@ func (r *defaultRegistry) ListActionOperators() []ActionTypeMatch {
 	copy(result, r.actionTypeMatches)
 	return result
 }
+
+func (r *defaultRegistry) RegisterToscaDefinition(name string, data []byte, origin string) {
+	r.definitionsLock.Lock()
+	defer r.definitionsLock.Unlock()
+	// Insert as first so a definition registered later overrides a previous one with the same name
+	r.definitions = append([]Definition{{Name: name, Origin: origin, Data: data}}, r.definitions...)
+}
+
+func (r *defaultRegistry) GetToscaDefinition(name string) ([]byte, error) {
+	r.definitionsLock.RLock()
+	defer r.definitionsLock.RUnlock()
+	for _, d := range r.definitions {
+		if d.Name == name {
+			return d.Data, nil
+		}
+	}
+	return nil, errors.Errorf("Unknown TOSCA definition: %q", name)
+}
+
+func (r *defaultRegistry) ListToscaDefinitions() []Definition {
+	r.definitionsLock.RLock()
+	defer r.definitionsLock.RUnlock()
+	result := make([]Definition, 0, len(r.definitions))
+	seen := make(map[string]bool, len(r.definitions))
+	for _, d := range r.definitions {
+		if !seen[d.Name] {
+			seen[d.Name] = true
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+func (r *defaultRegistry) UnregisterOrigin(origin string) {
+	r.delegatesLock.Lock()
+	delegates := r.delegateMatches[:0]
+	for _, m := range r.delegateMatches {
+		if m.Origin != origin {
+			delegates = append(delegates, m)
+		}
+	}
+	r.delegateMatches = delegates
+	r.delegatesLock.Unlock()
+
+	r.operationsLock.Lock()
+	operations := r.operationMatches[:0]
+	for _, m := range r.operationMatches {
+		if m.Origin != origin {
+			operations = append(operations, m)
+		}
+	}
+	r.operationMatches = operations
+	r.operationsLock.Unlock()
+
+	r.actionOperatorsLock.Lock()
+	actions := r.actionTypeMatches[:0]
+	for _, m := range r.actionTypeMatches {
+		if m.Origin != origin {
+			actions = append(actions, m)
+		}
+	}
+	r.actionTypeMatches = actions
+	r.actionOperatorsLock.Unlock()
+
+	r.infraUsageCollectorsLock.Lock()
+	collectors := r.infraUsageCollectors[:0]
+	for _, c := range r.infraUsageCollectors {
+		if c.Origin != origin {
+			collectors = append(collectors, c)
+		}
+	}
+	r.infraUsageCollectors = collectors
+	r.infraUsageCollectorsLock.Unlock()
+
+	r.vaultsLock.Lock()
+	vaults := r.vaultClientBuilders[:0]
+	for _, v := range r.vaultClientBuilders {
+		if v.Origin != origin {
+			vaults = append(vaults, v)
+		}
+	}
+	r.vaultClientBuilders = vaults
+	r.vaultsLock.Unlock()
+
+	r.definitionsLock.Lock()
+	definitions := r.definitions[:0]
+	for _, d := range r.definitions {
+		if d.Origin != origin {
+			definitions = append(definitions, d)
+		}
+	}
+	r.definitions = definitions
+	r.definitionsLock.Unlock()
+
+	r.storeBuildersLock.Lock()
+	storeBuilders := r.storeBuilders[:0]
+	for _, b := range r.storeBuilders {
+		if b.Origin != origin {
+			storeBuilders = append(storeBuilders, b)
+		}
+	}
+	r.storeBuilders = storeBuilders
+	r.storeBuildersLock.Unlock()
+}
+
+func (r *defaultRegistry) RegisterStoreBuilder(implementation string, builder store.Builder, origin string) {
+	r.storeBuildersLock.Lock()
+	defer r.storeBuildersLock.Unlock()
+	// Insert as first
+	r.storeBuilders = append([]StoreBuilder{{Implementation: implementation, Origin: origin, Builder: builder}}, r.storeBuilders...)
+}
+
+func (r *defaultRegistry) GetStoreBuilder(implementation string) (store.Builder, error) {
+	r.storeBuildersLock.RLock()
+	defer r.storeBuildersLock.RUnlock()
+	for _, b := range r.storeBuilders {
+		if strings.EqualFold(b.Implementation, implementation) {
+			return b.Builder, nil
+		}
+	}
+	return nil, errors.Errorf("Unknown store implementation: %q", implementation)
+}
+
+func (r *defaultRegistry) ListStoreBuilders() []StoreBuilder {
+	r.storeBuildersLock.RLock()
+	defer r.storeBuildersLock.RUnlock()
+	result := make([]StoreBuilder, len(r.storeBuilders))
+	copy(result, r.storeBuilders)
+	return result
+}
+
+func (r *defaultRegistry) SetPluginStatus(status PluginStatus) {
+	r.pluginsLock.Lock()
+	defer r.pluginsLock.Unlock()
+	if r.plugins == nil {
+		r.plugins = make(map[string]PluginStatus)
+	}
+	r.plugins[status.Name] = status
+}
+
+func (r *defaultRegistry) RemovePluginStatus(name string) {
+	r.pluginsLock.Lock()
+	defer r.pluginsLock.Unlock()
+	delete(r.plugins, name)
+}
+
+func (r *defaultRegistry) ListPlugins() []PluginStatus {
+	r.pluginsLock.RLock()
+	defer r.pluginsLock.RUnlock()
+	result := make([]PluginStatus, 0, len(r.plugins))
+	for _, p := range r.plugins {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
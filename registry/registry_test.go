@@ -0,0 +1,104 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/storage/store"
+)
+
+func TestUnregisterOrigin(t *testing.T) {
+	r := &defaultRegistry{}
+	r.RegisterDelegates([]string{"yorc.nodes.A"}, nil, BuiltinOrigin)
+	r.RegisterDelegates([]string{"my.nodes.B", "my.nodes.C"}, nil, "my-plugin")
+	r.RegisterOperationExecutor([]string{"my.artifacts.D"}, nil, "my-plugin")
+	r.RegisterActionOperator([]string{"my-action"}, nil, "my-plugin")
+	r.RegisterInfraUsageCollector("slurm", nil, BuiltinOrigin)
+	r.RegisterInfraUsageCollector("my-infra", nil, "my-plugin")
+	r.RegisterToscaDefinition("my-types.yaml", []byte("content"), "my-plugin")
+	r.RegisterStoreBuilder("my-store", nil, "my-plugin")
+
+	require.Len(t, r.ListDelegateExecutors(), 3)
+	require.Len(t, r.ListInfraUsageCollectors(), 2)
+
+	r.UnregisterOrigin("my-plugin")
+
+	delegates := r.ListDelegateExecutors()
+	require.Len(t, delegates, 1)
+	require.Equal(t, "yorc.nodes.A", delegates[0].Match)
+	require.Len(t, r.ListOperationExecutors(), 0)
+	require.Len(t, r.ListActionOperators(), 0)
+	require.Len(t, r.ListToscaDefinitions(), 0)
+	require.Len(t, r.ListStoreBuilders(), 0)
+	collectors := r.ListInfraUsageCollectors()
+	require.Len(t, collectors, 1)
+	require.Equal(t, "slurm", collectors[0].Name)
+	_, err := r.GetInfraUsageCollector("my-infra")
+	require.Error(t, err)
+}
+
+func TestPluginsStatus(t *testing.T) {
+	r := &defaultRegistry{}
+	require.Len(t, r.ListPlugins(), 0)
+
+	r.SetPluginStatus(PluginStatus{Name: "pluginB", State: PluginStateRunning})
+	r.SetPluginStatus(PluginStatus{Name: "pluginA", State: PluginStateRunning})
+	r.SetPluginStatus(PluginStatus{Name: "pluginB", State: PluginStateRestarting, Restarts: 1})
+
+	plugins := r.ListPlugins()
+	require.Len(t, plugins, 2)
+	require.Equal(t, "pluginA", plugins[0].Name)
+	require.Equal(t, "pluginB", plugins[1].Name)
+	require.Equal(t, PluginStateRestarting, plugins[1].State)
+	require.Equal(t, 1, plugins[1].Restarts)
+
+	r.RemovePluginStatus("pluginA")
+	plugins = r.ListPlugins()
+	require.Len(t, plugins, 1)
+	require.Equal(t, "pluginB", plugins[0].Name)
+}
+
+type testStoreBuilder struct {
+	name string
+}
+
+func (b *testStoreBuilder) NewStore(cfg config.Configuration, storeConfig config.Store) (store.Store, error) {
+	return nil, nil
+}
+
+func TestStoreBuilders(t *testing.T) {
+	r := &defaultRegistry{}
+	_, err := r.GetStoreBuilder("redis")
+	require.Error(t, err)
+
+	b1 := &testStoreBuilder{name: "b1"}
+	b2 := &testStoreBuilder{name: "b2"}
+	r.RegisterStoreBuilder("redis", b1, "plugin1")
+	r.RegisterStoreBuilder("Redis", b2, "plugin2")
+
+	builder, err := r.GetStoreBuilder("REDIS")
+	require.NoError(t, err)
+	require.Equal(t, b2, builder, "last registered builder should take precedence")
+	require.Len(t, r.ListStoreBuilders(), 2)
+
+	r.UnregisterOrigin("plugin2")
+	builder, err = r.GetStoreBuilder("redis")
+	require.NoError(t, err)
+	require.Equal(t, b1, builder)
+}
@@ -44,6 +44,11 @@ func StoreBuiltinTOSCAResources() error {
 	return nil
 }
 
+// GetBuiltinTOSCADefinitions returns TOSCA definitions stored into Yorc executable indexed by their names
+func GetBuiltinTOSCADefinitions() (map[string][]byte, error) {
+	return getToscaResources()
+}
+
 // GetTOSCADefinition allows to retrieve a TOSCA definition stored into Yorc executable
 func GetTOSCADefinition(name string) ([]byte, error) {
 	// try to get resources from Yorc executable
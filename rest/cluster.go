@@ -0,0 +1,72 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/ystia/yorc/v4/cluster"
+	"github.com/ystia/yorc/v4/log"
+)
+
+func (s *Server) listClusterMembersHandler(w http.ResponseWriter, r *http.Request) {
+	members, err := cluster.Members(s.consulClient)
+	if err != nil {
+		log.Panicf("%v", err)
+	}
+	encodeJSONResponse(w, r, ClusterMembersCollection{Members: members})
+}
+
+func (s *Server) getClusterMemberHandler(w http.ResponseWriter, r *http.Request) {
+	params := r.Context().Value(paramsLookupKey).(httprouter.Params)
+	member, err := cluster.GetMember(s.consulClient, params.ByName("serverId"))
+	if err != nil {
+		log.Panicf("%v", err)
+	}
+	if member == nil {
+		writeError(w, r, errNotFound)
+		return
+	}
+	encodeJSONResponse(w, r, member)
+}
+
+func (s *Server) drainClusterMemberHandler(w http.ResponseWriter, r *http.Request) {
+	params := r.Context().Value(paramsLookupKey).(httprouter.Params)
+	serverID := params.ByName("serverId")
+	member, err := cluster.GetMember(s.consulClient, serverID)
+	if err != nil {
+		log.Panicf("%v", err)
+	}
+	if member == nil {
+		writeError(w, r, errNotFound)
+		return
+	}
+	err = cluster.Drain(s.consulClient, serverID)
+	if err != nil {
+		log.Panicf("%v", err)
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) resumeClusterMemberHandler(w http.ResponseWriter, r *http.Request) {
+	params := r.Context().Value(paramsLookupKey).(httprouter.Params)
+	err := cluster.Resume(s.consulClient, params.ByName("serverId"))
+	if err != nil {
+		log.Panicf("%v", err)
+	}
+	w.WriteHeader(http.StatusOK)
+}
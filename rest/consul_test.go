@@ -94,5 +94,11 @@ func TestRunConsulRestPackageTests(t *testing.T) {
 		t.Run("testDeploymentTaskHandlers", func(t *testing.T) {
 			testDeploymentTaskHandlers(t, client, cfg, srv)
 		})
+		t.Run("testScheduleHandlers", func(t *testing.T) {
+			testScheduleHandlers(t, client, cfg, srv)
+		})
+		t.Run("testRolloutHandlers", func(t *testing.T) {
+			testRolloutHandlers(t, client, cfg, srv)
+		})
 	})
 }
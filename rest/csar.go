@@ -0,0 +1,50 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/ystia/yorc/v4/csar"
+)
+
+// maxCSARSize is the maximum size in bytes of a CSAR sent for validation as it is loaded in memory
+var maxCSARSize int64 = 100 << 20
+
+func (s *Server) validateCSARHandler(w http.ResponseWriter, r *http.Request) {
+	if r.ContentLength > maxCSARSize {
+		writeError(w, r, newRequestEntityTooLargeError(maxCSARSize))
+		return
+	}
+	content, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxCSARSize))
+	if err != nil {
+		writeError(w, r, newBadRequestError(err))
+		return
+	}
+	archive, err := csar.LoadZip(content)
+	if err != nil {
+		writeError(w, r, newBadRequestError(err))
+		return
+	}
+
+	// Validate against builtin and plugins definitions
+	definitions := make(map[string][]byte)
+	for _, def := range reg.ListToscaDefinitions() {
+		definitions[def.Name] = def.Data
+	}
+	report := csar.Validate(archive, definitions)
+	encodeJSONResponse(w, r, report)
+}
@@ -0,0 +1,101 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/csar"
+	"github.com/ystia/yorc/v4/helper/ziputil"
+	"github.com/ystia/yorc/v4/registry"
+)
+
+func TestValidateCSARHandler(t *testing.T) {
+	files, err := filepath.Glob("../data/tosca/*.yml")
+	require.NoError(t, err)
+	for _, f := range files {
+		content, err := ioutil.ReadFile(f)
+		require.NoError(t, err)
+		reg.RegisterToscaDefinition(filepath.Base(f), content, registry.BuiltinOrigin)
+	}
+
+	validCSAR, err := ziputil.ZipPath("../csar/testdata/valid")
+	require.NoError(t, err)
+	invalidCSAR, err := ziputil.ZipPath("../csar/testdata/invalid.yml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		body       []byte
+		wantStatus int
+		wantValid  bool
+	}{
+		{"ValidCSAR", validCSAR, http.StatusOK, true},
+		{"InvalidCSAR", invalidCSAR, http.StatusOK, false},
+		{"NotAZip", []byte("not a zip"), http.StatusBadRequest, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/csar/validate", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", mimeTypeApplicationZip)
+			req.Header.Set("Accept", mimeTypeApplicationJSON)
+			rr := httptest.NewRecorder()
+			ts := &Server{}
+			ts.validateCSARHandler(rr, req)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			report := new(csar.Report)
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), report))
+			require.Equal(t, tt.wantValid, report.Valid, "errors: %v", report.Errors)
+		})
+	}
+}
+
+func TestValidateCSARHandlerTooLarge(t *testing.T) {
+	defer func(size int64) { maxCSARSize = size }(maxCSARSize)
+	maxCSARSize = 8
+
+	tests := []struct {
+		name          string
+		contentLength int64
+		wantStatus    int
+	}{
+		{"ContentLengthTooLarge", 9, http.StatusRequestEntityTooLarge},
+		{"UnknownContentLength", -1, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/csar/validate", bytes.NewReader([]byte("not a zip but too large")))
+			req.ContentLength = tt.contentLength
+			req.Header.Set("Content-Type", mimeTypeApplicationZip)
+			req.Header.Set("Accept", mimeTypeApplicationJSON)
+			rr := httptest.NewRecorder()
+			ts := &Server{}
+			ts.validateCSARHandler(rr, req)
+			require.Equal(t, tt.wantStatus, rr.Code, rr.Body.String())
+			require.Contains(t, rr.Body.String(), "too large")
+		})
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"regexp"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/deployments/archive"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/tasks"
+)
+
+func (s *Server) exportDeploymentHandler(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	id := params.ByName("id")
+
+	dExits, err := deployments.DoesDeploymentExists(ctx, id)
+	if err != nil {
+		log.Panicf("%v", err)
+	}
+	if !dExits {
+		writeError(w, r, errNotFound)
+		return
+	}
+	if !checkBlockingOperationOnDeployment(ctx, id, w, r) {
+		return
+	}
+	hasLivingTask, livingTaskID, livingTaskStatus, err := tasks.TargetHasLivingTasks(id, nil)
+	if err != nil {
+		log.Panicf("%v", err)
+	}
+	if hasLivingTask {
+		writeError(w, r, newBadRequestError(tasks.NewAnotherLivingTaskAlreadyExistsError(livingTaskID, id, livingTaskStatus)))
+		return
+	}
+
+	opts := archive.ExportOptions{
+		WorkingDirectory: s.config.WorkingDirectory,
+		HostsPoolManager: s.hostsPoolMgr,
+	}
+	opts.IncludeEvents, err = getBoolQueryParam(r, "events")
+	if err != nil {
+		writeError(w, r, newBadRequestMessage("events query parameter must be a boolean value"))
+		return
+	}
+	opts.IncludeLogs, err = getBoolQueryParam(r, "logs")
+	if err != nil {
+		writeError(w, r, newBadRequestMessage("logs query parameter must be a boolean value"))
+		return
+	}
+
+	// The archive is built into a temporary file to be able to report errors before starting to send it
+	f, err := ioutil.TempFile("", "yorc-export-")
+	if err != nil {
+		log.Panic(err)
+	}
+	defer func() {
+		f.Close()
+		os.Remove(f.Name())
+	}()
+	err = archive.Export(ctx, f, id, opts)
+	if err != nil {
+		log.Panicf("%+v", err)
+	}
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		log.Panic(err)
+	}
+	_, err = f.Seek(0, io.SeekStart)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	w.Header().Set("Content-Type", mimeTypeApplicationZip)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", id+".zip"))
+	w.Header().Set("Content-Length", fmt.Sprint(size))
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, f)
+}
+
+func (s *Server) importDeploymentHandler(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	id := params.ByName("id")
+
+	matched, err := regexp.MatchString(YorcDeploymentIDPattern, id)
+	if err != nil {
+		log.Panicf("%v", errors.Wrapf(err, "Failed to parse given deployment id %q", id))
+	}
+	if !matched {
+		writeError(w, r, newBadRequestError(errors.Errorf("Deployment id should respect the following format: %q", YorcDeploymentIDPattern)))
+		return
+	}
+
+	err = deployments.CleanupPurgedDeployments(context.Background(), s.consulClient, s.config.PurgedDeploymentsEvictionTimeout, id)
+	if err != nil {
+		log.Panicf("%v", err)
+	}
+	if !checkBlockingOperationOnDeployment(ctx, id, w, r) {
+		return
+	}
+	if err := deployments.AddBlockingOperationOnDeploymentFlag(ctx, id); err != nil {
+		log.Panic(err)
+	}
+	defer deployments.RemoveBlockingOperationOnDeploymentFlag(context.Background(), id)
+
+	f, err := ioutil.TempFile("", "yorc-import-")
+	if err != nil {
+		log.Panic(err)
+	}
+	defer func() {
+		f.Close()
+		os.Remove(f.Name())
+	}()
+	size, err := io.Copy(f, r.Body)
+	if err != nil {
+		log.Panic(err)
+	}
+	zr, err := zip.NewReader(f, size)
+	if err != nil {
+		writeError(w, r, newBadRequestError(errors.Wrap(err, "invalid deployment archive")))
+		return
+	}
+
+	_, err = archive.Import(ctx, zr, id, archive.ImportOptions{
+		WorkingDirectory: s.config.WorkingDirectory,
+		HostsPoolManager: s.hostsPoolMgr,
+	})
+	if err != nil {
+		if archive.IsAlreadyExistsError(err) {
+			writeError(w, r, newConflictRequest(err.Error()))
+			return
+		}
+		if archive.IsBadArchiveError(err) {
+			writeError(w, r, newBadRequestError(err))
+			return
+		}
+		log.Panicf("%+v", err)
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/deployments/%s", id))
+	w.WriteHeader(http.StatusCreated)
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/ystia/yorc/v4/deployments"
 	"github.com/ystia/yorc/v4/log"
 	"github.com/ystia/yorc/v4/prov/operations"
+	"github.com/ystia/yorc/v4/quotas"
 	"github.com/ystia/yorc/v4/tasks"
 )
 
@@ -62,39 +63,69 @@ func (s *Server) newCustomCommandHandler(w http.ResponseWriter, r *http.Request)
 	if err = json.Unmarshal(body, &ccRequest); err != nil {
 		log.Panic(err)
 	}
+	data, err := s.customCommandTaskData(ctx, id, &ccRequest, true)
+	if err != nil {
+		if restError, ok := err.(*Error); ok {
+			writeError(w, r, restError)
+			return
+		}
+		log.Panic(err)
+	}
+
+	taskID, err := s.tasksCollector.RegisterTaskWithData(id, tasks.TaskTypeCustomCommand, data)
+	if err != nil {
+		if ok, _ := tasks.IsAnotherLivingTaskAlreadyExistsError(err); ok {
+			writeError(w, r, newBadRequestError(err))
+			return
+		}
+		if quotas.IsQuotaExceededError(err) {
+			writeError(w, r, newForbiddenRequest(err.Error()))
+			return
+		}
+		log.Panic(err)
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/deployments/%s/tasks/%s", id, taskID))
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// customCommandTaskData checks a custom command request and returns the corresponding task data
+//
+// If no instances are selected, the command applies on all the node instances. In this case instances are
+// resolved only if resolveInstances is true, otherwise they are left empty to be resolved when the task is registered.
+func (s *Server) customCommandTaskData(ctx context.Context, id string, ccRequest *CustomCommandRequest, resolveInstances bool) (map[string]string, error) {
 	// Check that provided node exists
 	nodeName := ccRequest.NodeName
 	nodeExists, err := deployments.DoesNodeExist(ctx, id, nodeName)
 	if err != nil {
-		log.Panicf("%v", err)
+		return nil, err
 	}
 	if !nodeExists {
-		writeError(w, r, newBadRequestParameter("node", errors.Errorf("Node %q must exist", nodeName)))
-		return
+		return nil, newBadRequestParameter("node", errors.Errorf("Node %q must exist", nodeName))
 	}
 
 	// Get node instances on which the command is to be applied
 	var instances []string
 	if ccRequest.Instances == nil {
-		// Apply command on all the instances
-		instances, err = deployments.GetNodeInstancesIds(ctx, id, nodeName)
-		if err != nil {
-			log.Panic(err)
+		if resolveInstances {
+			// Apply command on all the instances
+			instances, err = deployments.GetNodeInstancesIds(ctx, id, nodeName)
+			if err != nil {
+				return nil, err
+			}
 		}
 	} else {
 		checked, inexistent := s.checkInstances(ctx, id, nodeName, ccRequest.Instances)
-		if checked {
-			instances = ccRequest.Instances
-		} else {
-			writeError(w, r, newBadRequestParameter("instance", errors.Errorf("Instance %q must exist", inexistent)))
-			return
+		if !checked {
+			return nil, newBadRequestParameter("instance", errors.Errorf("Instance %q must exist", inexistent))
 		}
+		instances = ccRequest.Instances
 	}
 
 	ccRequest.InterfaceName = strings.ToLower(ccRequest.InterfaceName)
 	inputsName, err := s.getInputNameFromCustom(ctx, id, nodeName, ccRequest.InterfaceName, ccRequest.CustomCommandName)
 	if err != nil {
-		log.Panic(err)
+		return nil, err
 	}
 
 	data := make(map[string]string)
@@ -103,23 +134,9 @@ func (s *Server) newCustomCommandHandler(w http.ResponseWriter, r *http.Request)
 	data["interfaceName"] = ccRequest.InterfaceName
 
 	for _, name := range inputsName {
-		if err != nil {
-			log.Panic(err)
-		}
 		data[path.Join("inputs", name)] = ccRequest.Inputs[name].String()
 	}
-
-	taskID, err := s.tasksCollector.RegisterTaskWithData(id, tasks.TaskTypeCustomCommand, data)
-	if err != nil {
-		if ok, _ := tasks.IsAnotherLivingTaskAlreadyExistsError(err); ok {
-			writeError(w, r, newBadRequestError(err))
-			return
-		}
-		log.Panic(err)
-	}
-
-	w.Header().Set("Location", fmt.Sprintf("/deployments/%s/tasks/%s", id, taskID))
-	w.WriteHeader(http.StatusAccepted)
+	return data, nil
 }
 
 func (s *Server) getInputNameFromCustom(ctx context.Context, deploymentID, nodeName, interfaceName, customCName string) ([]string, error) {
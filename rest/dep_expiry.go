@@ -0,0 +1,111 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/deployments"
+)
+
+// parseExpiryQueryValues parses the ttl, expiry_date and purge_on_expiry query parameters of a deployment submission
+//
+// It returns nil if none of them is given.
+func parseExpiryQueryValues(values url.Values) (*DeploymentExpiryRequest, error) {
+	if values.Get("ttl") == "" && values.Get("expiry_date") == "" && values.Get("purge_on_expiry") == "" {
+		return nil, nil
+	}
+	req := &DeploymentExpiryRequest{TTL: values.Get("ttl")}
+	if v := values.Get("expiry_date"); v != "" {
+		date, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid expiry_date %q, expecting a RFC3339 date", v)
+		}
+		req.Date = &date
+	}
+	if v := values.Get("purge_on_expiry"); v != "" {
+		purge, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid purge_on_expiry %q, expecting a boolean value", v)
+		}
+		req.Purge = &purge
+	}
+	return req, nil
+}
+
+// expiryDate returns the expiry date requested at a given time
+//
+// A zero maxTTL means that the expiry date is not limited.
+func expiryDate(req *DeploymentExpiryRequest, maxTTL time.Duration, now time.Time) (time.Time, error) {
+	var date time.Time
+	switch {
+	case req.TTL != "" && req.Date != nil:
+		return date, errors.New("only one of a ttl or an expiry date could be provided")
+	case req.TTL != "":
+		ttl, err := time.ParseDuration(req.TTL)
+		if err != nil {
+			return date, errors.Wrapf(err, "invalid ttl %q", req.TTL)
+		}
+		if ttl <= 0 {
+			return date, errors.Errorf("invalid ttl %q, expecting a positive duration", req.TTL)
+		}
+		date = now.Add(ttl)
+	case req.Date != nil:
+		date = *req.Date
+		if !date.After(now) {
+			return date, errors.Errorf("expiry date %s is not in the future", date.Format(time.RFC3339))
+		}
+	default:
+		return date, errors.New("a ttl or an expiry date is required")
+	}
+	if maxTTL > 0 && date.Sub(now) > maxTTL {
+		return date, errors.Errorf("expiry date %s exceeds the maximum time-to-live of %s", date.Format(time.RFC3339), maxTTL)
+	}
+	return date, nil
+}
+
+// checkExpiryRequest checks an expiry request and returns the requested expiry date, the date is zero if the expiry is removed
+func (s *Server) checkExpiryRequest(req *DeploymentExpiryRequest, now time.Time) (time.Time, error) {
+	if req.Remove {
+		if req.TTL != "" || req.Date != nil || req.Purge != nil {
+			return time.Time{}, errors.New("an expiry could not be removed and updated at the same time")
+		}
+		return time.Time{}, nil
+	}
+	return expiryDate(req, s.config.DeploymentsExpiry.MaxTTL, now)
+}
+
+// storeDeploymentExpiry stores a checked expiry request of a deployment
+func storeDeploymentExpiry(ctx context.Context, deploymentID string, req *DeploymentExpiryRequest, date time.Time) error {
+	if req.Remove {
+		return deployments.RemoveDeploymentExpiry(ctx, deploymentID)
+	}
+	var purge bool
+	if req.Purge != nil {
+		purge = *req.Purge
+	} else {
+		current, err := deployments.GetDeploymentExpiry(ctx, deploymentID)
+		if err != nil {
+			return err
+		}
+		purge = current != nil && current.Purge
+	}
+	return deployments.SetDeploymentExpiry(ctx, deploymentID, date, purge)
+}
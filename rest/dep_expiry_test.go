@@ -0,0 +1,91 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+)
+
+func TestParseExpiryQueryValues(t *testing.T) {
+	date := time.Date(2019, time.November, 4, 10, 0, 0, 0, time.UTC)
+	purge := true
+	tests := []struct {
+		name    string
+		query   string
+		want    *DeploymentExpiryRequest
+		wantErr bool
+	}{
+		{"NoExpiry", "label=env=dev", nil, false},
+		{"TTL", "ttl=2h", &DeploymentExpiryRequest{TTL: "2h"}, false},
+		{"DateAndPurge", "expiry_date=2019-11-04T10:00:00Z&purge_on_expiry=true", &DeploymentExpiryRequest{Date: &date, Purge: &purge}, false},
+		{"InvalidDate", "expiry_date=tomorrow", nil, true},
+		{"InvalidPurge", "ttl=2h&purge_on_expiry=maybe", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+			got, err := parseExpiryQueryValues(values)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCheckExpiryRequest(t *testing.T) {
+	now := time.Date(2019, time.November, 4, 10, 0, 0, 0, time.UTC)
+	nextWeek := now.Add(7 * 24 * time.Hour)
+	past := now.Add(-time.Minute)
+	purge := true
+	s := &Server{config: config.Configuration{DeploymentsExpiry: config.DeploymentsExpiry{MaxTTL: 30 * 24 * time.Hour}}}
+	tests := []struct {
+		name    string
+		req     DeploymentExpiryRequest
+		want    time.Time
+		wantErr bool
+	}{
+		{"TTL", DeploymentExpiryRequest{TTL: "90m"}, now.Add(90 * time.Minute), false},
+		{"Date", DeploymentExpiryRequest{Date: &nextWeek, Purge: &purge}, nextWeek, false},
+		{"Remove", DeploymentExpiryRequest{Remove: true}, time.Time{}, false},
+		{"RemoveAndUpdate", DeploymentExpiryRequest{Remove: true, TTL: "1h"}, time.Time{}, true},
+		{"TTLAndDate", DeploymentExpiryRequest{TTL: "1h", Date: &nextWeek}, time.Time{}, true},
+		{"Missing", DeploymentExpiryRequest{Purge: &purge}, time.Time{}, true},
+		{"InvalidTTL", DeploymentExpiryRequest{TTL: "1 day"}, time.Time{}, true},
+		{"NegativeTTL", DeploymentExpiryRequest{TTL: "-1h"}, time.Time{}, true},
+		{"PastDate", DeploymentExpiryRequest{Date: &past}, time.Time{}, true},
+		{"AboveMaxTTL", DeploymentExpiryRequest{TTL: "720h1s"}, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.checkExpiryRequest(&tt.req, now)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/labelsutil"
+)
+
+// parseMetadataQueryValues parses the name=value pairs given as occurrences of a query parameter
+func parseMetadataQueryValues(values url.Values, param string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	for _, v := range values[param] {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, errors.Errorf("invalid %s %q, expecting name=value", param, v)
+		}
+		metadata[strings.TrimSpace(kv[0])] = kv[1]
+	}
+	return metadata, nil
+}
+
+// mapEntriesChanges splits map entries operations into added entries and removed entries names
+func mapEntriesChanges(entries []MapEntry) (map[string]string, []string, error) {
+	added := make(map[string]string)
+	removed := make([]string, 0)
+	for _, entry := range entries {
+		if entry.Name == "" {
+			return nil, nil, errors.New("empty names are not allowed")
+		}
+		switch entry.Op {
+		case MapEntryOperationAdd:
+			added[entry.Name] = entry.Value
+		case MapEntryOperationRemove:
+			removed = append(removed, entry.Name)
+		default:
+			return nil, nil, errors.Errorf("unsupported operation %q for %q", entry.Op.String(), entry.Name)
+		}
+	}
+	return added, removed, nil
+}
+
+// parseLabelsFilters parses labels filters given as occurrences of the filter query parameter
+func parseLabelsFilters(values url.Values) ([]labelsutil.Filter, error) {
+	filtersString := values["filter"]
+	filters := make([]labelsutil.Filter, len(filtersString))
+	for i := range filtersString {
+		var err error
+		filters[i], err = labelsutil.CreateFilter(filtersString[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return filters, nil
+}
+
+// storeDeploymentMetadata stores labels and annotations given as query parameters at deployment creation
+func storeDeploymentMetadata(r *http.Request, deploymentID string, labels, annotations map[string]string) {
+	if err := deployments.UpdateDeploymentLabels(r.Context(), deploymentID, labels, nil); err != nil {
+		log.Panic(err)
+	}
+	if err := deployments.UpdateDeploymentAnnotations(r.Context(), deploymentID, annotations, nil); err != nil {
+		log.Panic(err)
+	}
+}
+
+func (s *Server) updateDeploymentMetadataHandler(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	id := params.ByName("id")
+
+	dExits, err := deployments.DoesDeploymentExists(ctx, id)
+	if err != nil {
+		log.Panic(err)
+	}
+	if !dExits {
+		writeError(w, r, errNotFound)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Panic(err)
+	}
+	var req DeploymentMetadataRequest
+	if err = json.Unmarshal(body, &req); err != nil {
+		writeError(w, r, newBadRequestError(err))
+		return
+	}
+	labels, removedLabels, err := mapEntriesChanges(req.Labels)
+	if err != nil {
+		writeError(w, r, newBadRequestError(errors.Wrap(err, "invalid labels")))
+		return
+	}
+	annotations, removedAnnotations, err := mapEntriesChanges(req.Annotations)
+	if err != nil {
+		writeError(w, r, newBadRequestError(errors.Wrap(err, "invalid annotations")))
+		return
+	}
+	var expiresAt time.Time
+	if req.Expiry != nil {
+		expiresAt, err = s.checkExpiryRequest(req.Expiry, time.Now())
+		if err != nil {
+			writeError(w, r, newBadRequestError(errors.Wrap(err, "invalid expiry")))
+			return
+		}
+	}
+
+	err = deployments.UpdateDeploymentLabels(ctx, id, labels, removedLabels)
+	if err == nil {
+		err = deployments.UpdateDeploymentAnnotations(ctx, id, annotations, removedAnnotations)
+	}
+	if err == nil && req.Expiry != nil {
+		err = storeDeploymentExpiry(ctx, id, req.Expiry, expiresAt)
+	}
+	if err != nil {
+		log.Panic(err)
+	}
+	w.WriteHeader(http.StatusOK)
+}
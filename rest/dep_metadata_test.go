@@ -0,0 +1,76 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMetadataQueryValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    map[string]string
+		wantErr bool
+	}{
+		{"NoLabels", "", map[string]string{}, false},
+		{"Labels", "label=env=prod&label=team=hpc&label=formula=a=b&label=empty=", map[string]string{"env": "prod", "team": "hpc", "formula": "a=b", "empty": ""}, false},
+		{"MissingValue", "label=env", nil, true},
+		{"MissingName", "label==prod", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+			got, err := parseMetadataQueryValues(values, "label")
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMapEntriesChanges(t *testing.T) {
+	added, removed, err := mapEntriesChanges([]MapEntry{
+		{Name: "env", Value: "prod"},
+		{Op: MapEntryOperationRemove, Name: "team"},
+		{Op: MapEntryOperationAdd, Name: "cost_center", Value: "1234"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"env": "prod", "cost_center": "1234"}, added)
+	require.Equal(t, []string{"team"}, removed)
+
+	_, _, err = mapEntriesChanges([]MapEntry{{Value: "noname"}})
+	require.Error(t, err)
+}
+
+func TestParseLabelsFilters(t *testing.T) {
+	values := url.Values{"filter": []string{`env="prod"`, "team in (hpc, ai)"}}
+	filters, err := parseLabelsFilters(values)
+	require.NoError(t, err)
+	require.Len(t, filters, 2)
+	ok, err := filters[1].Matches(map[string]string{"team": "ai"})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, err = parseLabelsFilters(url.Values{"filter": []string{"env=="}})
+	require.Error(t, err)
+}
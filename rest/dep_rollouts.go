@@ -0,0 +1,278 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/collections"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/tasks/rollout"
+)
+
+func (s *Server) newRolloutHandler(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	id := params.ByName("id")
+
+	dExits, err := deployments.DoesDeploymentExists(ctx, id)
+	if err != nil {
+		log.Panic(err)
+	}
+	if !dExits {
+		writeError(w, r, errNotFound)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Panic(err)
+	}
+	var req RolloutRequest
+	if err = json.Unmarshal(body, &req); err != nil {
+		writeError(w, r, newBadRequestError(err))
+		return
+	}
+
+	ro, err := s.rolloutFromRequest(ctx, id, &req)
+	if err != nil {
+		if restError, ok := err.(*Error); ok {
+			writeError(w, r, restError)
+			return
+		}
+		log.Panic(err)
+	}
+	if err = ro.Validate(); err != nil {
+		writeError(w, r, newBadRequestError(err))
+		return
+	}
+
+	active, err := rollout.GetActiveRollout(ctx, id)
+	if err != nil {
+		log.Panic(err)
+	}
+	if active != nil {
+		writeError(w, r, newConflictRequest(fmt.Sprintf("rollout %q is already in progress for this deployment", active.ID)))
+		return
+	}
+
+	instances, err := deployments.GetNodeInstancesIds(ctx, id, ro.NodeName)
+	if err != nil {
+		log.Panic(err)
+	}
+	if len(instances) == 0 {
+		writeError(w, r, newBadRequestParameter("node", errors.Errorf("Node %q has no instance", ro.NodeName)))
+		return
+	}
+	rolloutID, err := rollout.CreateRollout(ctx, ro, instances)
+	if err != nil {
+		log.Panic(err)
+	}
+	w.Header().Set("Location", fmt.Sprintf("/deployments/%s/rollouts/%s", id, rolloutID))
+	w.WriteHeader(http.StatusCreated)
+}
+
+// rolloutFromRequest checks a rollout request and converts it into a rollout definition
+func (s *Server) rolloutFromRequest(ctx context.Context, id string, req *RolloutRequest) (*rollout.Rollout, error) {
+	ro := &rollout.Rollout{
+		DeploymentID:         id,
+		NodeName:             req.NodeName,
+		WorkflowName:         req.Workflow,
+		RollbackWorkflowName: req.RollbackWorkflow,
+		Canary:               req.Canary,
+		BatchSize:            req.BatchSize,
+		BatchPercentage:      req.BatchPercentage,
+	}
+	var err error
+	if req.Pause != "" {
+		ro.Pause, err = time.ParseDuration(req.Pause)
+		if err != nil {
+			return nil, newBadRequestParameter("pause", errors.Wrapf(err, "invalid pause %q", req.Pause))
+		}
+	}
+	if req.HealthGateDelay != "" {
+		ro.HealthGateDelay, err = time.ParseDuration(req.HealthGateDelay)
+		if err != nil {
+			return nil, newBadRequestParameter("health_gate_delay", errors.Wrapf(err, "invalid health gate delay %q", req.HealthGateDelay))
+		}
+	}
+
+	exists, err := deployments.DoesNodeExist(ctx, id, req.NodeName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, newBadRequestParameter("node", errors.Errorf("Node %q must exist", req.NodeName))
+	}
+	scalable, err := deployments.HasScalableCapability(ctx, id, req.NodeName)
+	if err != nil {
+		return nil, err
+	}
+	if !scalable {
+		return nil, newBadRequestParameter("node", errors.Errorf("Node %q must be scalable", req.NodeName))
+	}
+
+	workflows, err := deployments.GetWorkflows(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	wfRequest := &WorkflowRequest{Inputs: req.Inputs}
+	ro.Inputs = make(map[string]string)
+	for _, wfName := range []string{req.Workflow, req.RollbackWorkflow} {
+		if wfName == "" {
+			continue
+		}
+		if !collections.ContainsString(workflows, wfName) {
+			return nil, newBadRequestParameter("workflow", errors.Errorf("Workflow %q must exist", wfName))
+		}
+		// Both workflows are executed with the rollout inputs
+		data, err := s.workflowTaskData(ctx, id, wfName, false, wfRequest)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range data {
+			if strings.HasPrefix(k, "inputs/") {
+				ro.Inputs[strings.TrimPrefix(k, "inputs/")] = v
+			}
+		}
+	}
+	err = deployments.CheckWorkflowPreconditions(ctx, id, req.Workflow)
+	if err != nil {
+		if deployments.IsWorkflowPreconditionsNotMetError(err) {
+			return nil, newBadRequestError(err)
+		}
+		return nil, err
+	}
+	return ro, nil
+}
+
+func (s *Server) listRolloutsHandler(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	id := params.ByName("id")
+
+	dExits, err := deployments.DoesDeploymentExists(ctx, id)
+	if err != nil {
+		log.Panic(err)
+	}
+	if !dExits {
+		writeError(w, r, errNotFound)
+		return
+	}
+
+	rollouts, err := rollout.ListRollouts(ctx, id)
+	if err != nil {
+		log.Panic(err)
+	}
+	if len(rollouts) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	col := RolloutsCollection{Rollouts: make([]Rollout, len(rollouts))}
+	for i, ro := range rollouts {
+		col.Rollouts[i] = newRolloutRepresentation(ro)
+	}
+	encodeJSONResponse(w, r, col)
+}
+
+func (s *Server) getRolloutHandler(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	id := params.ByName("id")
+	rolloutID := params.ByName("rolloutId")
+
+	ro, err := rollout.GetRollout(ctx, id, rolloutID)
+	if err != nil {
+		log.Panic(err)
+	}
+	if ro == nil {
+		writeError(w, r, errNotFound)
+		return
+	}
+	encodeJSONResponse(w, r, newRolloutRepresentation(ro))
+}
+
+func (s *Server) cancelRolloutHandler(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	id := params.ByName("id")
+	rolloutID := params.ByName("rolloutId")
+
+	ro, err := rollout.GetRollout(ctx, id, rolloutID)
+	if err != nil {
+		log.Panic(err)
+	}
+	if ro == nil {
+		writeError(w, r, errNotFound)
+		return
+	}
+	if !ro.IsActive() {
+		writeError(w, r, newBadRequestMessage(fmt.Sprintf("Cannot cancel a rollout with status %q", ro.Status.String())))
+		return
+	}
+	if err = rollout.CancelRollout(ctx, id, rolloutID); err != nil {
+		log.Panic(err)
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func newRolloutRepresentation(ro *rollout.Rollout) Rollout {
+	res := Rollout{
+		ID:               ro.ID,
+		NodeName:         ro.NodeName,
+		Workflow:         ro.WorkflowName,
+		RollbackWorkflow: ro.RollbackWorkflowName,
+		Inputs:           ro.Inputs,
+		Canary:           ro.Canary,
+		BatchSize:        ro.BatchSize,
+		BatchPercentage:  ro.BatchPercentage,
+		Status:           ro.Status.String(),
+		CurrentBatch:     ro.CurrentBatch,
+		Batches:          make([]RolloutBatch, len(ro.Batches)),
+		RollbackTaskID:   ro.RollbackTaskID,
+		Error:            ro.Error,
+		CreationDate:     ro.CreationDate,
+		EndDate:          ro.EndDate,
+		Links: []AtomLink{
+			newAtomLink(LinkRelSelf, path.Join("/deployments", ro.DeploymentID, "rollouts", ro.ID)),
+			newAtomLink(LinkRelDeployment, path.Join("/deployments", ro.DeploymentID)),
+		},
+	}
+	if ro.Pause > 0 {
+		res.Pause = ro.Pause.String()
+	}
+	if ro.HealthGateDelay > 0 {
+		res.HealthGateDelay = ro.HealthGateDelay.String()
+	}
+	for i, b := range ro.Batches {
+		res.Batches[i] = RolloutBatch{Instances: b.Instances, Status: b.Status.String(), TaskID: b.TaskID}
+	}
+	return res
+}
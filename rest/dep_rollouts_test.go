@@ -0,0 +1,126 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+)
+
+func TestRolloutFromRequestDurationErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		request RolloutRequest
+	}{
+		{"BadPause", RolloutRequest{Workflow: "upgrade", NodeName: "Compute", BatchSize: 1, Pause: "1 minute"}},
+		{"BadHealthGateDelay", RolloutRequest{Workflow: "upgrade", NodeName: "Compute", BatchSize: 1, HealthGateDelay: "soon"}},
+	}
+	s := &Server{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.rolloutFromRequest(context.Background(), "dep", &tt.request)
+			require.Error(t, err)
+			restError, ok := err.(*Error)
+			require.True(t, ok, "expecting a REST error, got %v", err)
+			require.Equal(t, http.StatusBadRequest, restError.Status)
+		})
+	}
+}
+
+func testRolloutHandlers(t *testing.T, client *api.Client, cfg config.Configuration, srv *testutil.TestServer) {
+	deploymentID := "depRollouts"
+	prepareTest(t, deploymentID, client, srv)
+	defer func() {
+		cleanTest(deploymentID, "")
+		client.KV().DeleteTree(consulutil.RolloutsKVPrefix, nil)
+	}()
+
+	req := httptest.NewRequest("GET", "/deployments/depRollouts/rollouts", nil)
+	req.Header.Add("Accept", mimeTypeApplicationJSON)
+	resp := newTestHTTPRouter(client, cfg, req)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	tests := []struct {
+		name       string
+		url        string
+		body       string
+		statusCode int
+	}{
+		{"UnknownDeployment", "/deployments/unknown/rollouts", `{"workflow": "testWorkflow", "node": "Compute", "batch_size": 1}`, http.StatusNotFound},
+		{"BadJSON", "/deployments/depRollouts/rollouts", `{"workflow": `, http.StatusBadRequest},
+		{"UnknownNode", "/deployments/depRollouts/rollouts", `{"workflow": "testWorkflow", "node": "unknown", "batch_size": 1}`, http.StatusBadRequest},
+		{"UnknownWorkflow", "/deployments/depRollouts/rollouts", `{"workflow": "unknown", "node": "Compute", "batch_size": 1}`, http.StatusBadRequest},
+		{"UnknownRollbackWorkflow", "/deployments/depRollouts/rollouts", `{"workflow": "testWorkflow", "node": "Compute", "batch_size": 1, "rollback_workflow": "unknown"}`, http.StatusBadRequest},
+		{"MissingBatch", "/deployments/depRollouts/rollouts", `{"workflow": "testWorkflow", "node": "Compute"}`, http.StatusBadRequest},
+		{"Rollout", "/deployments/depRollouts/rollouts", `{"workflow": "testWorkflow", "node": "Compute", "batch_percentage": 50, "pause": "1m", "health_gate_delay": "30s", "rollback_workflow": "stop"}`, http.StatusCreated},
+		{"AlreadyInProgress", "/deployments/depRollouts/rollouts", `{"workflow": "testWorkflow", "node": "Compute", "batch_size": 1}`, http.StatusConflict},
+	}
+	var location string
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.url, strings.NewReader(tt.body))
+			req.Header.Add("Content-Type", mimeTypeApplicationJSON)
+			resp := newTestHTTPRouter(client, cfg, req)
+			require.Equal(t, tt.statusCode, resp.StatusCode)
+			if tt.statusCode == http.StatusCreated {
+				location = resp.Header.Get("Location")
+			}
+		})
+	}
+	require.NotEmpty(t, location)
+
+	req = httptest.NewRequest("GET", location, nil)
+	req.Header.Add("Accept", mimeTypeApplicationJSON)
+	resp = newTestHTTPRouter(client, cfg, req)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var ro Rollout
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&ro))
+	require.Equal(t, path.Base(location), ro.ID)
+	require.Equal(t, "RUNNING", ro.Status)
+	require.Equal(t, time.Minute.String(), ro.Pause)
+	require.Equal(t, "stop", ro.RollbackWorkflow)
+	require.NotEmpty(t, ro.Batches)
+	require.Equal(t, "INITIAL", ro.Batches[0].Status)
+
+	req = httptest.NewRequest("GET", "/deployments/depRollouts/rollouts", nil)
+	req.Header.Add("Accept", mimeTypeApplicationJSON)
+	resp = newTestHTTPRouter(client, cfg, req)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var collection RolloutsCollection
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&collection))
+	require.Len(t, collection.Rollouts, 1)
+
+	req = httptest.NewRequest("DELETE", location, nil)
+	resp = newTestHTTPRouter(client, cfg, req)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	req = httptest.NewRequest("DELETE", "/deployments/depRollouts/rollouts/unknown", nil)
+	resp = newTestHTTPRouter(client, cfg, req)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
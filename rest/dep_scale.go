@@ -26,6 +26,7 @@ import (
 
 	"github.com/ystia/yorc/v4/deployments"
 	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/quotas"
 	"github.com/ystia/yorc/v4/tasks"
 )
 
@@ -99,6 +100,10 @@ func (s *Server) scaleHandler(w http.ResponseWriter, r *http.Request) {
 			writeError(w, r, restError)
 			return
 		}
+		if quotas.IsQuotaExceededError(err) {
+			writeError(w, r, newForbiddenRequest(err.Error()))
+			return
+		}
 		log.Panic(err)
 	}
 	w.Header().Set("Location", fmt.Sprintf("/deployments/%s/tasks/%s", id, taskID))
@@ -106,21 +111,12 @@ func (s *Server) scaleHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) scaleOut(ctx context.Context, id, nodeName string, instancesDelta uint32) (string, error) {
-	maxInstances, err := deployments.GetMaxNbInstancesForNode(ctx, id, nodeName)
-	if err != nil {
-		return "", err
-	}
-	currentNbInstance, err := deployments.GetNbInstancesForNode(ctx, id, nodeName)
+	instancesDelta, err := deployments.GetScaleOutInstancesDelta(ctx, id, nodeName, instancesDelta)
 	if err != nil {
-		return "", err
-	}
-
-	if currentNbInstance+instancesDelta > maxInstances {
-		log.Debug("The delta is too high, the max instances number is chosen")
-		instancesDelta = maxInstances - currentNbInstance
-		if instancesDelta == 0 {
-			return "", newBadRequestMessage("Maximum number of instances reached")
+		if deployments.IsInstancesLimitReachedError(err) {
+			return "", newBadRequestMessage(err.Error())
 		}
+		return "", err
 	}
 
 	// Add related workflow, nodeName and instances delta
@@ -132,21 +128,12 @@ func (s *Server) scaleOut(ctx context.Context, id, nodeName string, instancesDel
 }
 
 func (s *Server) scaleIn(ctx context.Context, id, nodeName string, instancesDelta uint32) (string, error) {
-	minInstances, err := deployments.GetMinNbInstancesForNode(ctx, id, nodeName)
-	if err != nil {
-		return "", err
-	}
-	currentNbInstance, err := deployments.GetNbInstancesForNode(ctx, id, nodeName)
+	instancesDelta, err := deployments.GetScaleInInstancesDelta(ctx, id, nodeName, instancesDelta)
 	if err != nil {
-		return "", err
-	}
-
-	if currentNbInstance-instancesDelta < minInstances {
-		log.Debug("The delta is too low, the min instances number is chosen")
-		instancesDelta = currentNbInstance - minInstances
-		if instancesDelta == 0 {
-			return "", newBadRequestMessage("Minimum number of instances reached")
+		if deployments.IsInstancesLimitReachedError(err) {
+			return "", newBadRequestMessage(err.Error())
 		}
+		return "", err
 	}
 
 	instancesByNodes, err := deployments.SelectNodeStackInstances(ctx, id, nodeName, int(instancesDelta))
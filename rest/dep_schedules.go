@@ -0,0 +1,275 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/collections"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/scheduling"
+	"github.com/ystia/yorc/v4/tasks"
+)
+
+func (s *Server) newScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	id := params.ByName("id")
+
+	dExits, err := deployments.DoesDeploymentExists(ctx, id)
+	if err != nil {
+		log.Panic(err)
+	}
+	if !dExits {
+		writeError(w, r, errNotFound)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Panic(err)
+	}
+	var req ScheduleRequest
+	if err = json.Unmarshal(body, &req); err != nil {
+		writeError(w, r, newBadRequestError(err))
+		return
+	}
+
+	sched, err := s.scheduleFromRequest(ctx, id, &req)
+	if err != nil {
+		if restError, ok := err.(*Error); ok {
+			writeError(w, r, restError)
+			return
+		}
+		log.Panic(err)
+	}
+	if err = sched.Validate(); err != nil {
+		writeError(w, r, newBadRequestError(err))
+		return
+	}
+	scheduleID, err := scheduling.CreateSchedule(ctx, sched)
+	if err != nil {
+		log.Panic(err)
+	}
+	w.Header().Set("Location", fmt.Sprintf("/deployments/%s/schedules/%s", id, scheduleID))
+	w.WriteHeader(http.StatusCreated)
+}
+
+// scheduleFromRequest checks a schedule request and converts it into a schedule definition
+func (s *Server) scheduleFromRequest(ctx context.Context, id string, req *ScheduleRequest) (*scheduling.Schedule, error) {
+	sched := &scheduling.Schedule{
+		DeploymentID: id,
+		Cron:         req.Cron,
+		At:           req.At,
+		TimeZone:     req.TimeZone,
+	}
+	if req.OverlapPolicy != "" {
+		var err error
+		sched.OverlapPolicy, err = scheduling.ParseOverlapPolicy(req.OverlapPolicy)
+		if err != nil {
+			return nil, newBadRequestParameter("overlap_policy", err)
+		}
+	}
+
+	nbOperations := 0
+	for _, defined := range []bool{req.Workflow != nil, req.CustomCommand != nil, req.Scale != nil, req.Undeploy != nil} {
+		if defined {
+			nbOperations++
+		}
+	}
+	if nbOperations != 1 {
+		return nil, newBadRequestMessage("Exactly one of workflow, custom_command, scale or undeploy operations should be defined")
+	}
+
+	var err error
+	switch {
+	case req.Workflow != nil:
+		var workflows []string
+		workflows, err = deployments.GetWorkflows(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !collections.ContainsString(workflows, req.Workflow.Name) {
+			return nil, newBadRequestParameter("workflow", errors.Errorf("Workflow %q must exist", req.Workflow.Name))
+		}
+		sched.TaskType = tasks.TaskTypeCustomWorkflow
+		sched.Data, err = s.workflowTaskData(ctx, id, req.Workflow.Name, req.Workflow.ContinueOnError, &req.Workflow.WorkflowRequest)
+		if err != nil {
+			return nil, err
+		}
+	case req.CustomCommand != nil:
+		sched.TaskType = tasks.TaskTypeCustomCommand
+		sched.Data, err = s.customCommandTaskData(ctx, id, req.CustomCommand, false)
+		if err != nil {
+			return nil, err
+		}
+	case req.Scale != nil:
+		err = checkScheduledScale(ctx, id, req.Scale)
+		if err != nil {
+			return nil, err
+		}
+		delta := req.Scale.Delta
+		sched.TaskType = tasks.TaskTypeScaleOut
+		if delta < 0 {
+			sched.TaskType = tasks.TaskTypeScaleIn
+			delta = -delta
+		}
+		sched.Data = map[string]string{
+			"nodeName":       req.Scale.NodeName,
+			"instancesDelta": strconv.Itoa(delta),
+		}
+	case req.Undeploy != nil:
+		sched.TaskType = tasks.TaskTypeUnDeploy
+		sched.Data = map[string]string{
+			"workflowName":    "uninstall",
+			"continueOnError": strconv.FormatBool(!req.Undeploy.StopOnError),
+		}
+	}
+	return sched, nil
+}
+
+func checkScheduledScale(ctx context.Context, id string, scale *ScheduledScale) error {
+	if scale.Delta == 0 {
+		return newBadRequestParameter("delta", errors.New("You need to provide a non zero value as 'delta' parameter"))
+	}
+	exists, err := deployments.DoesNodeExist(ctx, id, scale.NodeName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return newBadRequestParameter("node", errors.Errorf("Node %q must exist", scale.NodeName))
+	}
+	ok, err := deployments.HasScalableCapability(ctx, id, scale.NodeName)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return newBadRequestParameter("node", errors.Errorf("Node %q must be scalable", scale.NodeName))
+	}
+	return nil
+}
+
+func (s *Server) listSchedulesHandler(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	id := params.ByName("id")
+
+	dExits, err := deployments.DoesDeploymentExists(ctx, id)
+	if err != nil {
+		log.Panic(err)
+	}
+	if !dExits {
+		writeError(w, r, errNotFound)
+		return
+	}
+
+	schedules, err := scheduling.ListSchedules(ctx, id)
+	if err != nil {
+		log.Panic(err)
+	}
+	if len(schedules) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	col := SchedulesCollection{Schedules: make([]Schedule, len(schedules))}
+	for i, sched := range schedules {
+		col.Schedules[i] = newScheduleRepresentation(ctx, sched)
+	}
+	encodeJSONResponse(w, r, col)
+}
+
+func (s *Server) getScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	id := params.ByName("id")
+	scheduleID := params.ByName("scheduleId")
+
+	sched, err := scheduling.GetSchedule(ctx, id, scheduleID)
+	if err != nil {
+		log.Panic(err)
+	}
+	if sched == nil {
+		writeError(w, r, errNotFound)
+		return
+	}
+	encodeJSONResponse(w, r, newScheduleRepresentation(ctx, sched))
+}
+
+func (s *Server) deleteScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	id := params.ByName("id")
+	scheduleID := params.ByName("scheduleId")
+
+	sched, err := scheduling.GetSchedule(ctx, id, scheduleID)
+	if err != nil {
+		log.Panic(err)
+	}
+	if sched == nil {
+		writeError(w, r, errNotFound)
+		return
+	}
+	if err = scheduling.DeleteSchedule(ctx, id, scheduleID); err != nil {
+		log.Panic(err)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func newScheduleRepresentation(ctx context.Context, sched *scheduling.Schedule) Schedule {
+	schedulePath := path.Join("/deployments", sched.DeploymentID, "schedules", sched.ID)
+	res := Schedule{
+		ID:            sched.ID,
+		Cron:          sched.Cron,
+		At:            sched.At,
+		TimeZone:      sched.TimeZone,
+		OverlapPolicy: sched.OverlapPolicy.String(),
+		TaskType:      sched.TaskType.String(),
+		Data:          sched.Data,
+		CreationDate:  sched.CreationDate,
+		Links: []AtomLink{
+			newAtomLink(LinkRelSelf, schedulePath),
+			newAtomLink(LinkRelDeployment, path.Join("/deployments", sched.DeploymentID)),
+		},
+	}
+	next, err := sched.NextRun(time.Now())
+	if err != nil {
+		log.Printf("[WARNING] failed to compute next run of schedule %q: %v", sched.ID, err)
+	} else if !next.IsZero() {
+		res.NextRun = &next
+	}
+	res.LatestTaskID, err = scheduling.GetScheduleLatestTaskID(ctx, sched.DeploymentID, sched.ID)
+	if err != nil {
+		log.Panic(err)
+	}
+	if res.LatestTaskID != "" {
+		res.Links = append(res.Links, newAtomLink(LinkRelTask, path.Join("/deployments", sched.DeploymentID, "tasks", res.LatestTaskID)))
+	}
+	return res
+}
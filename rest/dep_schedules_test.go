@@ -0,0 +1,136 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/prov/scheduling"
+)
+
+func TestScheduleFromRequestErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		request ScheduleRequest
+	}{
+		{"NoOperation", ScheduleRequest{Cron: "0 2 * * *"}},
+		{"SeveralOperations", ScheduleRequest{Cron: "0 2 * * *", Workflow: &ScheduledWorkflow{Name: "backup"}, Undeploy: &ScheduledUndeploy{}}},
+		{"BadOverlapPolicy", ScheduleRequest{Cron: "0 2 * * *", OverlapPolicy: "cancel", Undeploy: &ScheduledUndeploy{}}},
+	}
+	s := &Server{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.scheduleFromRequest(context.Background(), "dep", &tt.request)
+			require.Error(t, err)
+			restError, ok := err.(*Error)
+			require.True(t, ok, "expecting a REST error, got %v", err)
+			require.Equal(t, http.StatusBadRequest, restError.Status)
+		})
+	}
+}
+
+func TestScheduleFromRequestUndeploy(t *testing.T) {
+	t.Parallel()
+	s := &Server{}
+	sched, err := s.scheduleFromRequest(context.Background(), "dep", &ScheduleRequest{Cron: "0 18 * * fri", OverlapPolicy: "queue", Undeploy: &ScheduledUndeploy{StopOnError: true}})
+	require.NoError(t, err)
+	require.NoError(t, sched.Validate())
+	require.Equal(t, "UnDeploy", sched.TaskType.String())
+	require.Equal(t, "Queue", sched.OverlapPolicy.String())
+	require.Equal(t, map[string]string{"workflowName": "uninstall", "continueOnError": "false"}, sched.Data)
+}
+
+func testScheduleHandlers(t *testing.T, client *api.Client, cfg config.Configuration, srv *testutil.TestServer) {
+	srv.PopulateKV(t, map[string][]byte{
+		consulutil.DeploymentKVPrefix + "/depSchedules/status": []byte("DEPLOYED"),
+	})
+	defer func() {
+		client.KV().DeleteTree(consulutil.DeploymentKVPrefix+"/depSchedules", nil)
+		client.KV().DeleteTree(scheduling.SchedulesPrefix, nil)
+	}()
+
+	req := httptest.NewRequest("GET", "/deployments/depSchedules/schedules", nil)
+	req.Header.Add("Accept", mimeTypeApplicationJSON)
+	resp := newTestHTTPRouter(client, cfg, req)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	tests := []struct {
+		name       string
+		url        string
+		body       string
+		statusCode int
+	}{
+		{"UnknownDeployment", "/deployments/unknown/schedules", `{"cron": "0 18 * * fri", "undeploy": {}}`, http.StatusNotFound},
+		{"BadJSON", "/deployments/depSchedules/schedules", `{"cron": `, http.StatusBadRequest},
+		{"BadCron", "/deployments/depSchedules/schedules", `{"cron": "0 18 * *", "undeploy": {}}`, http.StatusBadRequest},
+		{"Undeploy", "/deployments/depSchedules/schedules", `{"cron": "0 18 * * fri", "time_zone": "Europe/Paris", "overlap_policy": "skip", "undeploy": {}}`, http.StatusCreated},
+	}
+	var location string
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.url, strings.NewReader(tt.body))
+			req.Header.Add("Content-Type", mimeTypeApplicationJSON)
+			resp := newTestHTTPRouter(client, cfg, req)
+			require.Equal(t, tt.statusCode, resp.StatusCode)
+			if tt.statusCode == http.StatusCreated {
+				location = resp.Header.Get("Location")
+			}
+		})
+	}
+	require.NotEmpty(t, location)
+
+	req = httptest.NewRequest("GET", location, nil)
+	req.Header.Add("Accept", mimeTypeApplicationJSON)
+	resp = newTestHTTPRouter(client, cfg, req)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var sched Schedule
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&sched))
+	require.Equal(t, path.Base(location), sched.ID)
+	require.Equal(t, "UnDeploy", sched.TaskType)
+	require.Equal(t, "Skip", sched.OverlapPolicy)
+	require.NotNil(t, sched.NextRun)
+	require.Equal(t, time.Friday, sched.NextRun.Weekday())
+
+	req = httptest.NewRequest("GET", "/deployments/depSchedules/schedules", nil)
+	req.Header.Add("Accept", mimeTypeApplicationJSON)
+	resp = newTestHTTPRouter(client, cfg, req)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var collection SchedulesCollection
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&collection))
+	require.Len(t, collection.Schedules, 1)
+
+	req = httptest.NewRequest("DELETE", location, nil)
+	resp = newTestHTTPRouter(client, cfg, req)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	req = httptest.NewRequest("GET", location, nil)
+	req.Header.Add("Accept", mimeTypeApplicationJSON)
+	resp = newTestHTTPRouter(client, cfg, req)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
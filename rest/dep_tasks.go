@@ -15,15 +15,21 @@
 package rest
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/pkg/errors"
 
+	"github.com/ystia/yorc/v4/deployments"
 	"github.com/ystia/yorc/v4/tasks"
+	"github.com/ystia/yorc/v4/tasks/workflow/graph"
+	"github.com/ystia/yorc/v4/tosca"
 )
 
 func (s *Server) tasksPreChecks(w http.ResponseWriter, r *http.Request, id, taskID string) bool {
@@ -60,6 +66,16 @@ func (s *Server) cancelTaskHandler(w http.ResponseWriter, r *http.Request) {
 
 	if taskStatus, err := tasks.GetTaskStatus(taskID); err != nil {
 		log.Panic(err)
+	} else if taskStatus == tasks.TaskStatusPENDINGAPPROVAL {
+		// Tasks pending approval are not processed by workers, cancel them right now
+		canceled, err := tasks.CancelPendingTask(ctx, taskID, "canceled before being approved")
+		if err != nil {
+			log.Panic(err)
+		}
+		if canceled {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
 	} else if taskStatus != tasks.TaskStatusRUNNING && taskStatus != tasks.TaskStatusINITIAL {
 		writeError(w, r, newBadRequestError(errors.Errorf("Cannot cancel a task with status %q", taskStatus.String())))
 		return
@@ -113,9 +129,68 @@ func (s *Server) getTaskHandler(w http.ResponseWriter, r *http.Request) {
 		log.Panic(err)
 	}
 	task.ErrorMessage = taskErrorMessage
+
+	task.Approval, err = tasks.GetTaskApproval(taskID)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	task.Signals, err = tasks.GetTaskStepSignals(taskID)
+	if err != nil {
+		log.Panic(err)
+	}
 	encodeJSONResponse(w, r, task)
 }
 
+func (s *Server) approveTaskHandler(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	id := params.ByName("id")
+	taskID := params.ByName("taskId")
+	if !s.tasksPreChecks(w, r, id, taskID) {
+		return
+	}
+
+	approver, err := getApprover(r)
+	if err != nil {
+		writeError(w, r, newBadRequestError(err))
+		return
+	}
+	if approver == "" {
+		writeError(w, r, newBadRequestMessage("an approver is required"))
+		return
+	}
+
+	approval, err := s.tasksCollector.ApproveTask(ctx, taskID, approver)
+	if err != nil {
+		if tasks.IsTaskApprovalError(err) {
+			writeError(w, r, newConflictRequest(errors.Cause(err).Error()))
+			return
+		}
+		log.Panic(err)
+	}
+	encodeJSONResponse(w, r, approval)
+}
+
+// getApprover returns the common name of the TLS client certificate of an approval request if any
+// or the approver given in the request body
+func getApprover(r *http.Request) (string, error) {
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 && r.TLS.PeerCertificates[0].Subject.CommonName != "" {
+		return r.TLS.PeerCertificates[0].Subject.CommonName, nil
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return "", err
+	}
+	if len(body) == 0 {
+		return "", nil
+	}
+	var request TaskApprovalRequest
+	err = json.Unmarshal(body, &request)
+	return request.Approver, errors.Wrap(err, "invalid approval request")
+}
+
 func (s *Server) getTaskStepsHandler(w http.ResponseWriter, r *http.Request) {
 	var params httprouter.Params
 	ctx := r.Context()
@@ -134,6 +209,50 @@ func (s *Server) getTaskStepsHandler(w http.ResponseWriter, r *http.Request) {
 	encodeJSONResponse(w, r, steps)
 }
 
+func (s *Server) getTaskGraphHandler(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	deploymentID := params.ByName("id")
+	taskID := params.ByName("taskId")
+
+	if !s.tasksPreChecks(w, r, deploymentID, taskID) {
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "", "json", "dot", "mermaid":
+	default:
+		writeError(w, r, newBadRequestParameter("format", errors.Errorf("unsupported graph format %q, expecting one of json, dot or mermaid", format)))
+		return
+	}
+	horizontal, err := getBoolQueryParam(r, "horizontal")
+	if err != nil {
+		writeError(w, r, newBadRequestParameter("horizontal", err))
+		return
+	}
+
+	g, err := graph.Build(ctx, deploymentID, taskID)
+	if err != nil {
+		if tasks.IsTaskDataNotFoundError(err) {
+			writeError(w, r, newBadRequestMessage("task is not a workflow task"))
+			return
+		}
+		log.Panic(err)
+	}
+	switch format {
+	case "dot":
+		w.Header().Set("Content-Type", mimeTypeTextVndGraphviz)
+		w.Write([]byte(g.DOT(horizontal)))
+	case "mermaid":
+		w.Header().Set("Content-Type", mimeTypeTextPlain)
+		w.Write([]byte(g.Mermaid(horizontal)))
+	default:
+		encodeJSONResponse(w, r, g)
+	}
+}
+
 func (s *Server) updateTaskStepStatusHandler(w http.ResponseWriter, r *http.Request) {
 	var params httprouter.Params
 	ctx := r.Context()
@@ -188,6 +307,103 @@ func (s *Server) updateTaskStepStatusHandler(w http.ResponseWriter, r *http.Requ
 	w.WriteHeader(http.StatusOK)
 }
 
+func (s *Server) signalTaskStepHandler(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	deploymentID := params.ByName("id")
+	taskID := params.ByName("taskId")
+	stepID := params.ByName("stepId")
+	if !s.tasksPreChecks(w, r, deploymentID, taskID) {
+		return
+	}
+
+	stExists, _, err := tasks.TaskStepExists(taskID, stepID)
+	if err != nil {
+		log.Panic(err)
+	}
+	if !stExists {
+		writeError(w, r, errNotFound)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Panic(err)
+	}
+	var request TaskStepSignalRequest
+	if len(body) > 0 {
+		err = json.Unmarshal(body, &request)
+		if err != nil {
+			writeError(w, r, newBadRequestError(errors.Wrap(err, "invalid signal request")))
+			return
+		}
+	}
+
+	inputs, err := signalInputs(ctx, deploymentID, taskID, stepID, &request)
+	if err != nil {
+		if restError, ok := err.(*Error); ok {
+			writeError(w, r, restError)
+			return
+		}
+		log.Panic(err)
+	}
+
+	err = tasks.SignalTaskStep(taskID, stepID, inputs, time.Now())
+	if err != nil {
+		if tasks.IsTaskSignalError(err) {
+			writeError(w, r, newConflictRequest(errors.Cause(err).Error()))
+			return
+		}
+		log.Panic(err)
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// signalInputs checks a signal request against the inputs expected by the wait_for_signal activity of a step
+// and returns the inputs to store, including default values of inputs missing in the request
+func signalInputs(ctx context.Context, deploymentID, taskID, stepName string, request *TaskStepSignalRequest) (map[string]string, error) {
+	workflowName, err := tasks.GetTaskData(taskID, "workflowName")
+	if err != nil {
+		if tasks.IsTaskDataNotFoundError(err) {
+			return nil, newBadRequestMessage("task is not a workflow task")
+		}
+		return nil, err
+	}
+	wf, err := deployments.GetWorkflow(ctx, deploymentID, workflowName)
+	if err != nil {
+		return nil, err
+	}
+	if wf == nil {
+		return nil, errors.Errorf("Can't check signal inputs of workflow %q in deployment %q, workflow definition not found", workflowName, deploymentID)
+	}
+	var signal *tosca.SignalActivity
+	if step, ok := wf.Steps[stepName]; ok {
+		for _, activity := range step.Activities {
+			if activity.WaitForSignal != nil {
+				signal = activity.WaitForSignal
+			}
+		}
+	}
+	if signal == nil {
+		return nil, newBadRequestMessage(fmt.Sprintf("step %q of workflow %q doesn't wait for a signal", stepName, workflowName))
+	}
+
+	inputs, err := deployments.ValidateSignalInputs(ctx, deploymentID, signal, request.Inputs)
+	if err != nil {
+		if deployments.IsInvalidParameterValueError(err) {
+			return nil, newBadRequestParameter("inputs", err)
+		}
+		return nil, err
+	}
+	for inputName, def := range signal.Inputs {
+		if _, found := inputs[inputName]; !found && def.Default != nil {
+			inputs[inputName] = fmt.Sprintf("%v", def.Default)
+		}
+	}
+	return inputs, nil
+}
+
 func (s *Server) resumeTaskHandler(w http.ResponseWriter, r *http.Request) {
 	var params httprouter.Params
 	ctx := r.Context()
@@ -15,11 +15,17 @@
 package rest
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/testutil"
@@ -27,6 +33,7 @@ import (
 
 	"github.com/ystia/yorc/v4/config"
 	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/tasks"
 )
 
 func testDeploymentTaskHandlers(t *testing.T, client *api.Client, cfg config.Configuration, srv *testutil.TestServer) {
@@ -39,6 +46,48 @@ func testDeploymentTaskHandlers(t *testing.T, client *api.Client, cfg config.Con
 	t.Run("testGetTaskHandlerWithTaskNotFound", func(t *testing.T) {
 		testGetTaskHandlerWithTaskNotFound(t, client, cfg, srv)
 	})
+	t.Run("testListTasksHandlers", func(t *testing.T) {
+		testListTasksHandlers(t, client, cfg, srv)
+	})
+	t.Run("testApproveTaskHandler", func(t *testing.T) {
+		testApproveTaskHandler(t, client, cfg, srv)
+	})
+	t.Run("testSignalTaskStepHandler", func(t *testing.T) {
+		testSignalTaskStepHandler(t, client, cfg, srv)
+	})
+	t.Run("testGetTaskGraphHandler", func(t *testing.T) {
+		testGetTaskGraphHandler(t, client, cfg, srv)
+	})
+}
+
+func TestGetApprover(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		clientCN string
+		want     string
+		wantErr  bool
+	}{
+		{"EmptyBody", "", "", "", false},
+		{"Body", `{"approver": "alice"}`, "", "alice", false},
+		{"ClientCertificate", `{"approver": "alice"}`, "bob", "bob", false},
+		{"InvalidBody", `{"approver": `, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/deployments/myDepID/tasks/task123/approve", strings.NewReader(tt.body))
+			if tt.clientCN != "" {
+				req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: tt.clientCN}}}}
+			}
+			got, err := getApprover(req)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
 }
 
 func testGetTaskHandlerWithTaskOutput(t *testing.T, client *api.Client, cfg config.Configuration, srv *testutil.TestServer) {
@@ -106,6 +155,58 @@ func testGetTaskHandlerWithTaskError(t *testing.T, client *api.Client, cfg confi
 	client.KV().DeleteTree(consulutil.TasksPrefix, nil)
 }
 
+func testApproveTaskHandler(t *testing.T, client *api.Client, cfg config.Configuration, srv *testutil.TestServer) {
+	expirationDate, err := time.Now().Add(time.Hour).MarshalBinary()
+	require.NoError(t, err)
+	srv.PopulateKV(t, map[string][]byte{
+		consulutil.TasksPrefix + "/taskApprove/type":                    []byte("5"),
+		consulutil.TasksPrefix + "/taskApprove/targetId":                []byte("myDepID"),
+		consulutil.TasksPrefix + "/taskApprove/status":                  []byte("5"),
+		consulutil.TasksPrefix + "/taskApprove/approval/policy":         []byte("prod"),
+		consulutil.TasksPrefix + "/taskApprove/approval/required":       []byte("1"),
+		consulutil.TasksPrefix + "/taskApprove/approval/expirationDate": expirationDate,
+	})
+
+	req := httptest.NewRequest("GET", "/deployments/myDepID/tasks/taskApprove", nil)
+	req.Header.Add("Accept", mimeTypeApplicationJSON)
+	resp := newTestHTTPRouter(client, cfg, req)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	task := new(Task)
+	err = json.NewDecoder(resp.Body).Decode(task)
+	require.NoError(t, err)
+	require.Equal(t, "PENDING_APPROVAL", task.Status)
+	require.NotNil(t, task.Approval)
+	require.Equal(t, "prod", task.Approval.Policy)
+	require.Equal(t, 1, task.Approval.Required)
+
+	req = httptest.NewRequest("POST", "/deployments/myDepID/tasks/taskApprove/approve", nil)
+	req.Header.Add("Accept", mimeTypeApplicationJSON)
+	resp = newTestHTTPRouter(client, cfg, req)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode, "an approver is required")
+
+	req = httptest.NewRequest("POST", "/deployments/myDepID/tasks/taskApprove/approve", strings.NewReader(`{"approver": "alice"}`))
+	req.Header.Add("Accept", mimeTypeApplicationJSON)
+	resp = newTestHTTPRouter(client, cfg, req)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	approval := new(tasks.TaskApproval)
+	err = json.NewDecoder(resp.Body).Decode(approval)
+	require.NoError(t, err)
+	require.Len(t, approval.Approvers, 1)
+	require.Equal(t, "alice", approval.Approvers[0].Name)
+
+	status, err := tasks.GetTaskStatus("taskApprove")
+	require.NoError(t, err)
+	require.Equal(t, tasks.TaskStatusINITIAL, status)
+
+	req = httptest.NewRequest("POST", "/deployments/myDepID/tasks/taskApprove/approve", strings.NewReader(`{"approver": "bob"}`))
+	req.Header.Add("Accept", mimeTypeApplicationJSON)
+	resp = newTestHTTPRouter(client, cfg, req)
+	require.Equal(t, http.StatusConflict, resp.StatusCode, "task is not pending approval anymore")
+
+	client.KV().DeleteTree(consulutil.TasksPrefix, nil)
+	client.KV().DeleteTree(consulutil.ExecutionsTaskPrefix, nil)
+}
+
 func testGetTaskHandlerWithTaskNotFound(t *testing.T, client *api.Client, cfg config.Configuration, srv *testutil.TestServer) {
 	req := httptest.NewRequest("GET", "/deployments/myDepID/tasks/taskNotFound", nil)
 	req.Header.Add("Accept", mimeTypeApplicationJSON)
@@ -114,3 +215,200 @@ func testGetTaskHandlerWithTaskNotFound(t *testing.T, client *api.Client, cfg co
 	require.NotNil(t, resp, "unexpected nil response")
 	require.Equal(t, http.StatusNotFound, resp.StatusCode, "unexpected status code %d instead of %d", resp.StatusCode, http.StatusNotFound)
 }
+
+func testListTasksHandlers(t *testing.T, client *api.Client, cfg config.Configuration, srv *testutil.TestServer) {
+	creation := time.Now().Add(-time.Hour)
+	date := func(d time.Duration) []byte {
+		b, err := creation.Add(d).MarshalBinary()
+		require.NoError(t, err)
+		return b
+	}
+	srv.PopulateKV(t, map[string][]byte{
+		consulutil.DeploymentKVPrefix + "/depTasks/status":          []byte("DEPLOYED"),
+		consulutil.DeploymentKVPrefix + "/depTasks/tasks/tInstall":  []byte(""),
+		consulutil.DeploymentKVPrefix + "/depTasks/tasks/tWorkflow": []byte(""),
+		consulutil.TasksPrefix + "/tInstall/type":                   []byte("0"),
+		consulutil.TasksPrefix + "/tInstall/targetId":               []byte("depTasks"),
+		consulutil.TasksPrefix + "/tInstall/status":                 []byte("2"),
+		consulutil.TasksPrefix + "/tInstall/creationDate":           date(0),
+		consulutil.TasksPrefix + "/tInstall/endDate":                date(90 * time.Second),
+		consulutil.TasksPrefix + "/tInstall/data/workflowName":      []byte("install"),
+		consulutil.WorkflowsPrefix + "/tInstall/step1":              []byte("done"),
+		consulutil.WorkflowsPrefix + "/tInstall/step2":              []byte("done"),
+		consulutil.TasksPrefix + "/tWorkflow/type":                  []byte("6"),
+		consulutil.TasksPrefix + "/tWorkflow/targetId":              []byte("depTasks"),
+		consulutil.TasksPrefix + "/tWorkflow/status":                []byte("3"),
+		consulutil.TasksPrefix + "/tWorkflow/creationDate":          date(time.Minute),
+		consulutil.TasksPrefix + "/tWorkflow/endDate":               date(2 * time.Minute),
+		consulutil.TasksPrefix + "/tWorkflow/data/workflowName":     []byte("backup"),
+		consulutil.WorkflowsPrefix + "/tWorkflow/step1":             []byte("done"),
+		consulutil.WorkflowsPrefix + "/tWorkflow/step2":             []byte("error"),
+		consulutil.TasksPrefix + "/tQuery/type":                     []byte("7"),
+		consulutil.TasksPrefix + "/tQuery/targetId":                 []byte("infra_usage:slurm"),
+		consulutil.TasksPrefix + "/tQuery/status":                   []byte("1"),
+		consulutil.TasksPrefix + "/tQuery/creationDate":             date(2 * time.Minute),
+	})
+	defer func() {
+		client.KV().DeleteTree(consulutil.TasksPrefix, nil)
+		client.KV().DeleteTree(consulutil.WorkflowsPrefix, nil)
+		client.KV().DeleteTree(consulutil.DeploymentKVPrefix+"/depTasks", nil)
+	}()
+
+	tests := []struct {
+		name       string
+		url        string
+		statusCode int
+		wantIDs    []string
+		wantNext   bool
+	}{
+		{"AllTasks", "/tasks", http.StatusOK, []string{"tQuery", "tWorkflow", "tInstall"}, false},
+		{"AllTasksPaginated", "/tasks?limit=2&sort=creation_date", http.StatusOK, []string{"tInstall", "tWorkflow"}, true},
+		{"DeploymentTasks", "/deployments/depTasks/tasks", http.StatusOK, []string{"tWorkflow", "tInstall"}, false},
+		{"SortByDuration", "/deployments/depTasks/tasks?sort=-duration", http.StatusOK, []string{"tInstall", "tWorkflow"}, false},
+		{"FilterByStatus", "/tasks?status=FAILED,RUNNING", http.StatusOK, []string{"tQuery", "tWorkflow"}, false},
+		{"FilterByTypeAndWorkflow", "/tasks?type=Deploy,CustomWorkflow&workflow=backup", http.StatusOK, []string{"tWorkflow"}, false},
+		{"NoMatch", "/tasks?type=Purge", http.StatusNoContent, nil, false},
+		{"BadFilter", "/tasks?status=unknown", http.StatusBadRequest, nil, false},
+		{"UnknownDeployment", "/deployments/unknown/tasks", http.StatusNotFound, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			req.Header.Add("Accept", mimeTypeApplicationJSON)
+			resp := newTestHTTPRouter(client, cfg, req)
+			require.Equal(t, tt.statusCode, resp.StatusCode)
+			if tt.statusCode != http.StatusOK {
+				return
+			}
+			var collection TaskHistoryCollection
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&collection))
+			ids := make([]string, 0)
+			for _, task := range collection.Tasks {
+				ids = append(ids, task.ID)
+			}
+			require.Equal(t, tt.wantIDs, ids)
+			require.Equal(t, tt.wantNext, collection.NextCursor != "")
+		})
+	}
+
+	req := httptest.NewRequest("GET", "/deployments/depTasks/tasks?workflow=backup", nil)
+	req.Header.Add("Accept", mimeTypeApplicationJSON)
+	resp := newTestHTTPRouter(client, cfg, req)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var collection TaskHistoryCollection
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&collection))
+	require.Len(t, collection.Tasks, 1)
+	task := collection.Tasks[0]
+	require.Equal(t, "CustomWorkflow", task.Type)
+	require.Equal(t, "FAILED", task.Status)
+	require.Equal(t, "1m0s", task.Duration)
+	require.NotNil(t, task.EndDate)
+	require.Equal(t, &TaskStepsCount{Total: 2, ByStatus: map[string]int{"done": 1, "error": 1}}, task.Steps)
+	require.Equal(t, "/deployments/depTasks/tasks/tWorkflow", task.Links[0].Href)
+}
+
+func testSignalTaskStepHandler(t *testing.T, client *api.Client, cfg config.Configuration, srv *testutil.TestServer) {
+	deploymentID := "testSignalTaskStep"
+	prepareTest(t, deploymentID, client, srv)
+	defer cleanTest(deploymentID, "taskSignal")
+	srv.PopulateKV(t, map[string][]byte{
+		consulutil.TasksPrefix + "/taskSignal/type":              []byte("6"),
+		consulutil.TasksPrefix + "/taskSignal/targetId":          []byte(deploymentID),
+		consulutil.TasksPrefix + "/taskSignal/status":            []byte("1"),
+		consulutil.TasksPrefix + "/taskSignal/data/workflowName": []byte("testSignalWorkflow"),
+		consulutil.WorkflowsPrefix + "/taskSignal/pause":         []byte("WAITING"),
+		consulutil.WorkflowsPrefix + "/taskSignal/Compute_start": []byte("INITIAL"),
+	})
+	require.NoError(t, tasks.RegisterStepSignalWait("taskSignal", "pause", "go_live", time.Now()))
+
+	signalURL := fmt.Sprintf("/deployments/%s/tasks/taskSignal/steps/%%s/signal", deploymentID)
+	tests := []struct {
+		name       string
+		step       string
+		body       string
+		wantStatus int
+	}{
+		{"UnknownStep", "unknown", "", http.StatusNotFound},
+		{"StepNotWaitingForSignal", "Compute_start", "", http.StatusBadRequest},
+		{"InvalidPayload", "pause", "{", http.StatusBadRequest},
+		{"MissingRequiredInput", "pause", `{"inputs": {"comment": "ok"}}`, http.StatusBadRequest},
+		{"Signal", "pause", `{"inputs": {"version": "1.2"}}`, http.StatusAccepted},
+		{"AlreadySignaled", "pause", `{"inputs": {"version": "1.3"}}`, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", fmt.Sprintf(signalURL, tt.step), strings.NewReader(tt.body))
+			resp := newTestHTTPRouter(client, cfg, req)
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+
+	signal, err := tasks.GetTaskStepSignal("taskSignal", "pause")
+	require.NoError(t, err)
+	require.Equal(t, tasks.TaskStepSignalStatusReceived, signal.Status)
+	require.Equal(t, map[string]string{"version": "1.2", "comment": "none"}, signal.Inputs)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("/deployments/%s/tasks/taskSignal", deploymentID), nil)
+	req.Header.Add("Accept", mimeTypeApplicationJSON)
+	resp := newTestHTTPRouter(client, cfg, req)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	task := new(Task)
+	err = json.NewDecoder(resp.Body).Decode(task)
+	require.NoError(t, err)
+	require.Len(t, task.Signals, 1)
+	require.Equal(t, "go_live", task.Signals[0].Signal)
+	client.KV().DeleteTree(consulutil.ExecutionsTaskPrefix, nil)
+}
+
+func testGetTaskGraphHandler(t *testing.T, client *api.Client, cfg config.Configuration, srv *testutil.TestServer) {
+	deploymentID := "testTaskGraph"
+	prepareTest(t, deploymentID, client, srv)
+	defer cleanTest(deploymentID, "taskGraph")
+	defer cleanTest("", "taskGraphNoWf")
+	srv.PopulateKV(t, map[string][]byte{
+		consulutil.TasksPrefix + "/taskGraph/type":                             []byte("6"),
+		consulutil.TasksPrefix + "/taskGraph/targetId":                         []byte(deploymentID),
+		consulutil.TasksPrefix + "/taskGraph/status":                           []byte("3"),
+		consulutil.TasksPrefix + "/taskGraph/data/workflowName":                []byte("testSignalWorkflow"),
+		consulutil.TasksPrefix + "/taskGraph/steps/Compute_start/instances":    []byte("0"),
+		consulutil.TasksPrefix + "/taskGraph/steps/Compute_start/errorMessage": []byte("start failed"),
+		consulutil.WorkflowsPrefix + "/taskGraph/pause":                        []byte("DONE"),
+		consulutil.WorkflowsPrefix + "/taskGraph/Compute_start":                []byte("ERROR"),
+		consulutil.TasksPrefix + "/taskGraphNoWf/type":                         []byte("0"),
+		consulutil.TasksPrefix + "/taskGraphNoWf/targetId":                     []byte(deploymentID),
+		consulutil.TasksPrefix + "/taskGraphNoWf/status":                       []byte("2"),
+	})
+
+	graphURL := fmt.Sprintf("/deployments/%s/tasks/%%s/graph%%s", deploymentID)
+	tests := []struct {
+		name            string
+		taskID          string
+		query           string
+		wantStatus      int
+		wantContentType string
+		wantContains    []string
+	}{
+		{"JSON", "taskGraph", "", http.StatusOK, mimeTypeApplicationJSON, []string{`"workflow_name":"testSignalWorkflow"`, `"error_message":"start failed"`}},
+		{"DOT", "taskGraph", "?format=dot&horizontal=true", http.StatusOK, mimeTypeTextVndGraphviz, []string{"digraph", "rankdir=LR", "start failed"}},
+		{"Mermaid", "taskGraph", "?format=mermaid", http.StatusOK, mimeTypeTextPlain, []string{"flowchart TD", "error: start failed"}},
+		{"UnsupportedFormat", "taskGraph", "?format=png", http.StatusBadRequest, "", nil},
+		{"InvalidHorizontal", "taskGraph", "?horizontal=maybe", http.StatusBadRequest, "", nil},
+		{"NotAWorkflowTask", "taskGraphNoWf", "", http.StatusBadRequest, "", nil},
+		{"TaskNotFound", "unknownTask", "", http.StatusNotFound, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", fmt.Sprintf(graphURL, tt.taskID, tt.query), nil)
+			resp := newTestHTTPRouter(client, cfg, req)
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantContentType != "" {
+				require.Equal(t, tt.wantContentType, resp.Header.Get("Content-Type"))
+			}
+			body, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err)
+			for _, s := range tt.wantContains {
+				require.Contains(t, string(body), s)
+			}
+		})
+	}
+}
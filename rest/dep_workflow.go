@@ -15,6 +15,7 @@
 package rest
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -28,6 +29,7 @@ import (
 	"github.com/ystia/yorc/v4/deployments"
 	"github.com/ystia/yorc/v4/helper/collections"
 	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/quotas"
 	"github.com/ystia/yorc/v4/tasks"
 )
 
@@ -70,71 +72,37 @@ func (s *Server) newWorkflowHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	data := make(map[string]string)
-	data["workflowName"] = workflowName
-	if _, ok := r.URL.Query()["continueOnError"]; ok {
-		data["continueOnError"] = strconv.FormatBool(true)
-	} else {
-		data["continueOnError"] = strconv.FormatBool(false)
-	}
+	_, continueOnError := r.URL.Query()["continueOnError"]
 	// Get instances selection if provided in the request body
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		log.Panic(err)
 	}
 
+	var wfRequest *WorkflowRequest
 	if len(body) > 0 {
-		var wfRequest WorkflowRequest
-		err = json.Unmarshal(body, &wfRequest)
+		wfRequest = new(WorkflowRequest)
+		err = json.Unmarshal(body, wfRequest)
 		if err != nil {
 			log.Panic(err)
 		}
-		for _, nodeInstances := range wfRequest.NodesInstances {
-			nodeName := nodeInstances.NodeName
-			// Check that provided node exists
-			nodeExists, err := deployments.DoesNodeExist(ctx, deploymentID, nodeName)
-			if err != nil {
-				log.Panicf("%v", err)
-			}
-			if !nodeExists {
-				writeError(w, r, newBadRequestParameter("node", errors.Errorf("Node %q must exist", nodeName)))
-				return
-			}
-			// Check that provided instances exist
-			checked, inexistent := s.checkInstances(ctx, deploymentID, nodeName, nodeInstances.Instances)
-			if !checked {
-				writeError(w, r, newBadRequestParameter("instance", errors.Errorf("Instance %q must exist", inexistent)))
-				return
-			}
-			instances := strings.Join(nodeInstances.Instances, ",")
-			data["nodes/"+nodeName] = instances
-		}
-
-		// Adding workflow inputs in task data
-		for inputName, inputValue := range wfRequest.Inputs {
-			data[path.Join("inputs", inputName)] = fmt.Sprintf("%v", inputValue)
+	}
+	data, err := s.workflowTaskData(ctx, deploymentID, workflowName, continueOnError, wfRequest)
+	if err != nil {
+		if restError, ok := err.(*Error); ok {
+			writeError(w, r, restError)
+			return
 		}
+		log.Panic(err)
+	}
 
-		// Check all workflow required input parameters have a value
-		wf, err := deployments.GetWorkflow(ctx, deploymentID, workflowName)
-		if err != nil {
-			log.Panic(err)
-		}
-		if wf == nil {
-			log.Panic(errors.Errorf("Can't check inputs of workflow %q in deployment %q, workflow definition not found", workflowName, deploymentID))
-		}
-		for inputName, def := range wf.Inputs {
-			// A property is considered as required by default, unless def.Required
-			// is set to false
-			if (def.Required == nil || *def.Required) && def.Default == nil {
-				_, found := wfRequest.Inputs[inputName]
-				if !found {
-					writeError(w, r, newBadRequestParameter("inputs", errors.Errorf("Missing value for required workflow input parameter %s", inputName)))
-					return
-				}
-			}
+	err = deployments.CheckWorkflowPreconditions(ctx, deploymentID, workflowName)
+	if err != nil {
+		if deployments.IsWorkflowPreconditionsNotMetError(err) {
+			writeError(w, r, newBadRequestError(err))
+			return
 		}
-
+		log.Panic(err)
 	}
 
 	taskID, err := s.tasksCollector.RegisterTaskWithData(deploymentID, tasks.TaskTypeCustomWorkflow, data)
@@ -143,6 +111,10 @@ func (s *Server) newWorkflowHandler(w http.ResponseWriter, r *http.Request) {
 			writeError(w, r, newBadRequestError(err))
 			return
 		}
+		if quotas.IsQuotaExceededError(err) {
+			writeError(w, r, newForbiddenRequest(err.Error()))
+			return
+		}
 		log.Panic(err)
 	}
 
@@ -151,6 +123,49 @@ func (s *Server) newWorkflowHandler(w http.ResponseWriter, r *http.Request) {
 
 }
 
+// workflowTaskData checks a workflow execution request and returns the corresponding task data
+//
+// The instances selection and inputs request is optional.
+func (s *Server) workflowTaskData(ctx context.Context, deploymentID, workflowName string, continueOnError bool, wfRequest *WorkflowRequest) (map[string]string, error) {
+	data := make(map[string]string)
+	data["workflowName"] = workflowName
+	data["continueOnError"] = strconv.FormatBool(continueOnError)
+	if wfRequest == nil {
+		return data, nil
+	}
+	for _, nodeInstances := range wfRequest.NodesInstances {
+		nodeName := nodeInstances.NodeName
+		// Check that provided node exists
+		nodeExists, err := deployments.DoesNodeExist(ctx, deploymentID, nodeName)
+		if err != nil {
+			return nil, err
+		}
+		if !nodeExists {
+			return nil, newBadRequestParameter("node", errors.Errorf("Node %q must exist", nodeName))
+		}
+		// Check that provided instances exist
+		checked, inexistent := s.checkInstances(ctx, deploymentID, nodeName, nodeInstances.Instances)
+		if !checked {
+			return nil, newBadRequestParameter("instance", errors.Errorf("Instance %q must exist", inexistent))
+		}
+		instances := strings.Join(nodeInstances.Instances, ",")
+		data["nodes/"+nodeName] = instances
+	}
+
+	// Check workflow inputs against their definitions before adding them in task data
+	inputs, err := deployments.ValidateWorkflowInputs(ctx, deploymentID, workflowName, wfRequest.Inputs)
+	if err != nil {
+		if deployments.IsInvalidParameterValueError(err) {
+			return nil, newBadRequestParameter("inputs", err)
+		}
+		return nil, err
+	}
+	for inputName, inputValue := range inputs {
+		data[path.Join("inputs", inputName)] = inputValue
+	}
+	return data, nil
+}
+
 func (s *Server) listWorkflowsHandler(w http.ResponseWriter, r *http.Request) {
 	var params httprouter.Params
 	ctx := r.Context()
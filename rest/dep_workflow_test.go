@@ -62,6 +62,15 @@ func testExecuteWorkflow(t *testing.T, client *api.Client, cfg config.Configurat
 			http.StatusBadRequest,
 			0,
 			"Missing value for required workflow input"},
+		{"execWithInvalidInput",
+			WorkflowRequest{
+				Inputs: map[string]interface{}{
+					"param1": "value1",
+					"param2": -1}},
+			false,
+			http.StatusBadRequest,
+			0,
+			"Invalid value for workflow input parameter param2"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -26,13 +26,17 @@ import (
 	"path/filepath"
 	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 
 	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/helper/labelsutil"
 	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/quotas"
 	"github.com/ystia/yorc/v4/tasks"
 )
 
@@ -164,6 +168,25 @@ func (s *Server) newDeploymentHandler(w http.ResponseWriter, r *http.Request) {
 	} else {
 		uid = fmt.Sprint(uuid.NewV4())
 	}
+	labels, err := parseMetadataQueryValues(r.URL.Query(), "label")
+	if err != nil {
+		writeError(w, r, newBadRequestError(err))
+		return
+	}
+	annotations, err := parseMetadataQueryValues(r.URL.Query(), "annotation")
+	if err != nil {
+		writeError(w, r, newBadRequestError(err))
+		return
+	}
+	expiryRequest, err := parseExpiryQueryValues(r.URL.Query())
+	var expiresAt time.Time
+	if err == nil && expiryRequest != nil {
+		expiresAt, err = s.checkExpiryRequest(expiryRequest, time.Now())
+	}
+	if err != nil {
+		writeError(w, r, newBadRequestError(err))
+		return
+	}
 	log.Printf("Analyzing deployment %s\n", uid)
 
 	yamlFile, archiveErr := unzipArchiveGetTopology(s.config.WorkingDirectory, uid, r)
@@ -177,7 +200,7 @@ func (s *Server) newDeploymentHandler(w http.ResponseWriter, r *http.Request) {
 	// I was expecting to use the one from http.Request
 	// To be checked if there is a good reason for this.
 	ctx := context.Background()
-	err := deployments.CleanupPurgedDeployments(ctx, s.consulClient, s.config.PurgedDeploymentsEvictionTimeout, uid)
+	err = deployments.CleanupPurgedDeployments(ctx, s.consulClient, s.config.PurgedDeploymentsEvictionTimeout, uid)
 	if err != nil {
 		log.Panicf("%v", err)
 	}
@@ -196,6 +219,12 @@ func (s *Server) newDeploymentHandler(w http.ResponseWriter, r *http.Request) {
 		log.Debugf("ERROR: %+v", err)
 		log.Panic(err)
 	}
+	storeDeploymentMetadata(r, uid, labels, annotations)
+	if expiryRequest != nil {
+		if err = storeDeploymentExpiry(ctx, uid, expiryRequest, expiresAt); err != nil {
+			log.Panic(err)
+		}
+	}
 	data := map[string]string{
 		"workflowName": "install",
 	}
@@ -205,6 +234,11 @@ func (s *Server) newDeploymentHandler(w http.ResponseWriter, r *http.Request) {
 			writeError(w, r, newBadRequestError(err))
 			return
 		}
+		if quotas.IsQuotaExceededError(err) {
+			s.discardNewDeployment(ctx, uid)
+			writeError(w, r, newForbiddenRequest(err.Error()))
+			return
+		}
 		log.Panic(err)
 	}
 
@@ -212,6 +246,22 @@ func (s *Server) newDeploymentHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 }
 
+// discardNewDeployment removes a stored deployment whose install task was not registered
+func (s *Server) discardNewDeployment(ctx context.Context, deploymentID string) {
+	if err := events.PurgeDeploymentEvents(ctx, deploymentID); err != nil {
+		log.Printf("Failed to cleanup events of discarded deployment %q: %v", deploymentID, err)
+	}
+	if err := events.PurgeDeploymentLogs(ctx, deploymentID); err != nil {
+		log.Printf("Failed to cleanup logs of discarded deployment %q: %v", deploymentID, err)
+	}
+	if err := deployments.DeleteDeployment(ctx, deploymentID); err != nil {
+		log.Printf("Failed to cleanup discarded deployment %q: %v", deploymentID, err)
+	}
+	if err := os.RemoveAll(filepath.Join(s.config.WorkingDirectory, "deployments", deploymentID)); err != nil {
+		log.Printf("Failed to cleanup files of discarded deployment %q: %v", deploymentID, err)
+	}
+}
+
 func (s *Server) updateDeploymentHandler(w http.ResponseWriter, r *http.Request) {
 	var params httprouter.Params
 	ctx := r.Context()
@@ -303,6 +353,10 @@ func (s *Server) deleteDeploymentHandler(w http.ResponseWriter, r *http.Request)
 			writeError(w, r, newBadRequestError(err))
 			return
 		}
+		if quotas.IsQuotaExceededError(err) {
+			writeError(w, r, newForbiddenRequest(err.Error()))
+			return
+		}
 
 		// Inconsistent deployment: force purge enters in action
 		if ok := deployments.IsInconsistentDeploymentError(err); ok {
@@ -340,6 +394,21 @@ func (s *Server) getDeploymentHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	deployment := Deployment{ID: id, Status: status.String()}
+	deployment.Labels, err = deployments.GetDeploymentLabels(ctx, id)
+	if err != nil {
+		log.Panic(err)
+	}
+	deployment.Annotations, err = deployments.GetDeploymentAnnotations(ctx, id)
+	if err != nil {
+		log.Panic(err)
+	}
+	expiry, err := deployments.GetDeploymentExpiry(ctx, id)
+	if err != nil {
+		log.Panic(err)
+	}
+	if expiry != nil {
+		deployment.Expiry = &DeploymentExpiry{Date: expiry.Date, Purge: expiry.Purge}
+	}
 	links := []AtomLink{newAtomLink(LinkRelSelf, r.URL.Path)}
 	nodes, err := deployments.GetNodes(ctx, id)
 	if err != nil {
@@ -365,6 +434,28 @@ func (s *Server) getDeploymentHandler(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) listDeploymentsHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	values := r.URL.Query()
+	statuses := make(map[deployments.DeploymentStatus]bool)
+	for _, v := range splitQueryValues(values, "status") {
+		status, err := deployments.ParseDeploymentStatus(v)
+		if err != nil {
+			writeError(w, r, newBadRequestError(err))
+			return
+		}
+		statuses[status] = true
+	}
+	filters, err := parseLabelsFilters(values)
+	if err != nil {
+		writeError(w, r, newBadRequestError(err))
+		return
+	}
+	// Deployments are not paginated unless a limit is given
+	pr, err := parsePageRequest(values, []string{"id"}, "id", 0)
+	if err != nil {
+		writeError(w, r, newBadRequestError(err))
+		return
+	}
+
 	deploymentsIDs, err := deployments.GetDeploymentsIDs(ctx)
 	if err != nil {
 		log.Panic(err)
@@ -375,6 +466,7 @@ func (s *Server) listDeploymentsHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	deps := make([]Deployment, 0)
+	keys := make([]pageKey, 0)
 	for _, deploymentID := range deploymentsIDs {
 		status, err := deployments.GetDeploymentStatus(ctx, deploymentID)
 		if err != nil {
@@ -385,15 +477,35 @@ func (s *Server) listDeploymentsHandler(w http.ResponseWriter, r *http.Request)
 			}
 			log.Panic(err)
 		}
+		if len(statuses) > 0 && !statuses[status] {
+			continue
+		}
+		labels, err := deployments.GetDeploymentLabels(ctx, deploymentID)
+		if err != nil {
+			log.Panic(err)
+		}
+		if ok, warn := labelsutil.MatchesAll(labels, filters...); !ok {
+			if warn != nil {
+				log.Printf("[WARNING] deployment %q labels do not match filters: %v", deploymentID, warn)
+			}
+			continue
+		}
 		deps = append(deps, Deployment{
 			ID:     deploymentID,
 			Status: status.String(),
+			Labels: labels,
 			Links:  []AtomLink{newAtomLink(LinkRelDeployment, "/deployments/"+deploymentID)},
 		})
+		keys = append(keys, pageKey{ID: deploymentID})
 	}
-	if len(deps) == 0 {
+	indexes, nextCursor := paginate(keys, pr)
+	if len(indexes) == 0 {
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
-	encodeJSONResponse(w, r, DeploymentsCollection{Deployments: deps})
+	collection := DeploymentsCollection{Deployments: make([]Deployment, 0, len(indexes)), NextCursor: nextCursor}
+	for _, i := range indexes {
+		collection.Deployments = append(collection.Deployments, deps[i])
+	}
+	encodeJSONResponse(w, r, collection)
 }
@@ -289,7 +289,7 @@ func testListDeploymentHandler(t *testing.T, client *api.Client, cfg config.Conf
 		want *result
 	}{
 		{"getDeployment", &result{statusCode: http.StatusOK, errors: nil,
-			deployments: &DeploymentsCollection{[]Deployment{{ID: "getDeployment", Status: "DEPLOYED",
+			deployments: &DeploymentsCollection{Deployments: []Deployment{{ID: "getDeployment", Status: "DEPLOYED",
 				Links: []AtomLink{{Href: "/deployments/getDeployment", Rel: "deployment", LinkType: mimeTypeApplicationJSON}}}}}}},
 		{"noDeployment", &result{statusCode: http.StatusNoContent, errors: nil,
 			deployments: nil}},
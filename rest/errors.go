@@ -62,6 +62,10 @@ func newUnsupportedMediaTypeError(contentType string) *Error {
 	return &Error{"unsupported_media_type", 415, "Unsupported Media Type", fmt.Sprintf("Content-Type header must be set to: '%s'.", contentType)}
 }
 
+func newRequestEntityTooLargeError(maxSize int64) *Error {
+	return &Error{"request_entity_too_large", http.StatusRequestEntityTooLarge, "Request Entity Too Large", fmt.Sprintf("Request body too large, maximum size is %d bytes.", maxSize)}
+}
+
 func newBadRequestParameter(param string, err error) *Error {
 	return &Error{"bad_request", http.StatusBadRequest, "Bad Request", fmt.Sprintf("Invalid %q parameter %v", param, err)}
 }
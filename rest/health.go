@@ -17,6 +17,6 @@ package rest
 import "net/http"
 
 func (s *Server) getHealthHandler(w http.ResponseWriter, r *http.Request) {
-	health := Health{"passing"}
+	health := Health{Value: "passing", Plugins: reg.ListPlugins()}
 	encodeJSONResponse(w, r, health)
 }
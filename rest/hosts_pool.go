@@ -24,7 +24,6 @@ import (
 	"github.com/julienschmidt/httprouter"
 	"github.com/pkg/errors"
 
-	"github.com/ystia/yorc/v4/helper/labelsutil"
 	"github.com/ystia/yorc/v4/log"
 	"github.com/ystia/yorc/v4/prov/hostspool"
 )
@@ -223,17 +222,10 @@ func (s *Server) listHostsInPool(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	params = ctx.Value(paramsLookupKey).(httprouter.Params)
 	location := params.ByName("location")
-	filtersString := r.URL.Query()["filter"]
-
-	filters := make([]labelsutil.Filter, len(filtersString))
-	for i := range filtersString {
-		var err error
-
-		filters[i], err = labelsutil.CreateFilter(filtersString[i])
-		if err != nil {
-			writeError(w, r, newBadRequestError(err))
-			return
-		}
+	filters, err := parseLabelsFilters(r.URL.Query())
+	if err != nil {
+		writeError(w, r, newBadRequestError(err))
+		return
 	}
 
 	hostsNames, warnings, checkpoint, err := s.hostsPoolMgr.List(location, filters...)
@@ -43,6 +43,8 @@ const LOCATIONURI = LOCATIONS + "/:locationName"
 const (
 	mimeTypeApplicationZip  = "application/zip"
 	mimeTypeApplicationJSON = "application/json"
+	mimeTypeTextVndGraphviz = "text/vnd.graphviz"
+	mimeTypeTextPlain       = "text/plain"
 )
 
 type router struct {
@@ -132,7 +134,7 @@ func NewServer(configuration config.Configuration, client *api.Client, shutdownC
 		router:         newRouter(),
 		listener:       listener,
 		consulClient:   client,
-		tasksCollector: collector.NewCollector(client),
+		tasksCollector: collector.NewCollectorWithApprovals(client, configuration.Tasks.Approval),
 		config:         configuration,
 		hostsPoolMgr:   hostspool.NewManager(client, configuration),
 		locationMgr:    locations.NewManager(client, configuration),
@@ -159,12 +161,19 @@ func (s *Server) registerHandlers() {
 	commonHandlers := alice.New(telemetryHandler, loggingHandler, recoverHandler)
 	s.router.Get("/server/info", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.getInfoHandler))
 	s.router.Get("/server/health", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.getHealthHandler))
+	s.router.Get("/server/members", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.listClusterMembersHandler))
+	s.router.Get("/server/members/:serverId", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.getClusterMemberHandler))
+	s.router.Put("/server/members/:serverId/drain", commonHandlers.ThenFunc(s.drainClusterMemberHandler))
+	s.router.Delete("/server/members/:serverId/drain", commonHandlers.ThenFunc(s.resumeClusterMemberHandler))
 	s.router.Post("/deployments", commonHandlers.Append(contentTypeHandler(mimeTypeApplicationZip)).ThenFunc(s.newDeploymentHandler))
 	s.router.Put("/deployments/:id", commonHandlers.Append(contentTypeHandler(mimeTypeApplicationZip)).ThenFunc(s.newDeploymentHandler))
 	s.router.Patch("/deployments/:id", commonHandlers.Append(contentTypeHandler(mimeTypeApplicationZip)).ThenFunc(s.updateDeploymentHandler))
 	s.router.Delete("/deployments/:id", commonHandlers.ThenFunc(s.deleteDeploymentHandler))
 	s.router.Get("/deployments/:id", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.getDeploymentHandler))
 	s.router.Get("/deployments", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.listDeploymentsHandler))
+	s.router.Patch("/deployments/:id/metadata", commonHandlers.Append(contentTypeHandler(mimeTypeApplicationJSON)).ThenFunc(s.updateDeploymentMetadataHandler))
+	s.router.Get("/deployments/:id/export", commonHandlers.Append(acceptHandler(mimeTypeApplicationZip)).ThenFunc(s.exportDeploymentHandler))
+	s.router.Put("/deployments/:id/import", commonHandlers.Append(contentTypeHandler(mimeTypeApplicationZip)).ThenFunc(s.importDeploymentHandler))
 	s.router.Get("/deployments/:id/events", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.pollEvents))
 	s.router.Get("/events", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.pollEvents))
 	s.router.Head("/deployments/:id/events", commonHandlers.ThenFunc(s.headEventsIndex))
@@ -177,24 +186,41 @@ func (s *Server) registerHandlers() {
 	s.router.Get("/deployments/:id/nodes/:nodeName/instances/:instanceId", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.getNodeInstanceHandler))
 	s.router.Get("/deployments/:id/outputs", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.listOutputsHandler))
 	s.router.Get("/deployments/:id/outputs/:opt", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.getOutputHandler))
+	s.router.Get("/deployments/:id/tasks", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.listDeploymentTasksHandler))
 	s.router.Get("/deployments/:id/tasks/:taskId", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.getTaskHandler))
 	s.router.Get("/deployments/:id/tasks/:taskId/steps", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.getTaskStepsHandler))
+	s.router.Get("/deployments/:id/tasks/:taskId/graph", commonHandlers.ThenFunc(s.getTaskGraphHandler))
 	s.router.Delete("/deployments/:id/tasks/:taskId", commonHandlers.ThenFunc(s.cancelTaskHandler))
 	s.router.Put("/deployments/:id/tasks/:taskId", commonHandlers.ThenFunc(s.resumeTaskHandler))
+	s.router.Post("/deployments/:id/tasks/:taskId/approve", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.approveTaskHandler))
 	s.router.Put("/deployments/:id/tasks/:taskId/steps/:stepId", commonHandlers.Append(contentTypeHandler(mimeTypeApplicationJSON)).ThenFunc(s.updateTaskStepStatusHandler))
+	s.router.Post("/deployments/:id/tasks/:taskId/steps/:stepId/signal", commonHandlers.ThenFunc(s.signalTaskStepHandler))
+	s.router.Get("/tasks", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.listTasksHandler))
 	s.router.Post("/deployments/:id/scale/:nodeName", commonHandlers.ThenFunc(s.scaleHandler))
 	s.router.Get("/deployments/:id/nodes/:nodeName/instances/:instanceId/attributes", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.getNodeInstanceAttributesListHandler))
 	s.router.Get("/deployments/:id/nodes/:nodeName/instances/:instanceId/attributes/:attributeName", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.getNodeInstanceAttributeHandler))
 	s.router.Post("/deployments/:id/custom", commonHandlers.Append(contentTypeHandler(mimeTypeApplicationJSON)).ThenFunc(s.newCustomCommandHandler))
 	s.router.Post("/deployments/:id/workflows/:workflowName", commonHandlers.ThenFunc(s.newWorkflowHandler))
+	s.router.Post("/deployments/:id/schedules", commonHandlers.Append(contentTypeHandler(mimeTypeApplicationJSON)).ThenFunc(s.newScheduleHandler))
+	s.router.Get("/deployments/:id/schedules", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.listSchedulesHandler))
+	s.router.Get("/deployments/:id/schedules/:scheduleId", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.getScheduleHandler))
+	s.router.Delete("/deployments/:id/schedules/:scheduleId", commonHandlers.ThenFunc(s.deleteScheduleHandler))
+	s.router.Post("/deployments/:id/rollouts", commonHandlers.Append(contentTypeHandler(mimeTypeApplicationJSON)).ThenFunc(s.newRolloutHandler))
+	s.router.Get("/deployments/:id/rollouts", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.listRolloutsHandler))
+	s.router.Get("/deployments/:id/rollouts/:rolloutId", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.getRolloutHandler))
+	s.router.Delete("/deployments/:id/rollouts/:rolloutId", commonHandlers.ThenFunc(s.cancelRolloutHandler))
 	s.router.Get("/deployments/:id/workflows/:workflowName", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.getWorkflowHandler))
 	s.router.Get("/deployments/:id/workflows", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.listWorkflowsHandler))
 
+	s.router.Post("/csar/validate", commonHandlers.Append(contentTypeHandler(mimeTypeApplicationZip), acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.validateCSARHandler))
+
 	s.router.Get("/registry/delegates", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.listRegistryDelegatesHandler))
 	s.router.Get("/registry/implementations", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.listRegistryImplementationsHandler))
 	s.router.Get("/registry/definitions", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.listRegistryDefinitionsHandler))
 	s.router.Get("/registry/vaults", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.listVaultsBuilderHandler))
 	s.router.Get("/registry/infra_usage_collectors", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.listInfraHandler))
+	s.router.Get("/registry/plugins", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.listPluginsHandler))
+	s.router.Get("/registry/stores", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.listStoresHandler))
 
 	s.router.Post("/infra_usage/:infraName/:locationName", commonHandlers.Append(contentTypeHandler(mimeTypeApplicationJSON)).ThenFunc(s.postInfraUsageHandler))
 	s.router.Get("/infra_usage/:infraName/:locationName/tasks/:taskId", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.getTaskQueryHandler))
@@ -216,6 +242,12 @@ func (s *Server) registerHandlers() {
 	s.router.Patch(LOCATIONURI, commonHandlers.Append(contentTypeHandler("application/json")).ThenFunc(s.updateLocationHandler))
 	s.router.Delete(LOCATIONURI, commonHandlers.ThenFunc(s.deleteLocationHandler))
 
+	s.router.Get("/quotas", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.listQuotasHandler))
+	s.router.Get("/quotas/:quotaName", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.getQuotaHandler))
+	s.router.Put("/quotas/:quotaName", commonHandlers.Append(contentTypeHandler(mimeTypeApplicationJSON)).ThenFunc(s.putQuotaHandler))
+	s.router.Delete("/quotas/:quotaName", commonHandlers.ThenFunc(s.deleteQuotaHandler))
+	s.router.Get("/quotas/:quotaName/usage", commonHandlers.Append(acceptHandler(mimeTypeApplicationJSON)).ThenFunc(s.getQuotaUsageHandler))
+
 	if s.config.Telemetry.PrometheusEndpoint {
 		s.router.Get("/metrics", commonHandlers.Then(promhttp.Handler()))
 	}
@@ -0,0 +1,158 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// maxPageSize is the maximum number of items that could be returned in a single page
+const maxPageSize = 1000
+
+// pageKey identifies the position of an item in a sorted listing
+type pageKey struct {
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+func (k pageKey) less(o pageKey) bool {
+	if k.Value != o.Value {
+		return k.Value < o.Value
+	}
+	return k.ID < o.ID
+}
+
+// pageCursor is the opaque cursor given to clients to retrieve the next page of a listing
+type pageCursor struct {
+	Sort string `json:"s"`
+	pageKey
+}
+
+// pageRequest describes the page requested by a client
+type pageRequest struct {
+	// Sort is the field used to sort items
+	Sort string
+	// Descending is true if items are sorted in descending order
+	Descending bool
+	// Limit is the maximum number of items of the page, 0 means unlimited
+	Limit int
+	// After is the position after which the page starts, nil for the first page
+	After *pageKey
+}
+
+func (pr pageRequest) sortParam() string {
+	if pr.Descending {
+		return "-" + pr.Sort
+	}
+	return pr.Sort
+}
+
+// parsePageRequest parses the sort, limit and cursor query parameters
+//
+// The sort parameter is a field name among sortFields optionally prefixed by '-' for a descending order.
+func parsePageRequest(values url.Values, sortFields []string, defaultSort string, defaultLimit int) (pageRequest, error) {
+	pr := pageRequest{Limit: defaultLimit}
+	sortParam := values.Get("sort")
+	if sortParam == "" {
+		sortParam = defaultSort
+	}
+	pr.Sort = strings.TrimPrefix(sortParam, "-")
+	pr.Descending = pr.Sort != sortParam
+	if !contains(sortFields, pr.Sort) {
+		return pr, errors.Errorf("invalid sort field %q, expecting one of %s", pr.Sort, strings.Join(sortFields, ", "))
+	}
+
+	if l := values.Get("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil || limit <= 0 {
+			return pr, errors.Errorf("invalid limit %q, expecting a positive integer", l)
+		}
+		pr.Limit = limit
+	}
+	if pr.Limit > maxPageSize {
+		pr.Limit = maxPageSize
+	}
+
+	if c := values.Get("cursor"); c != "" {
+		b, err := base64.RawURLEncoding.DecodeString(c)
+		cursor := new(pageCursor)
+		if err == nil {
+			err = json.Unmarshal(b, cursor)
+		}
+		if err != nil {
+			return pr, errors.Errorf("invalid cursor %q", c)
+		}
+		if cursor.Sort != pr.sortParam() {
+			return pr, errors.Errorf("cursor %q was not issued for a listing sorted by %q", c, pr.sortParam())
+		}
+		pr.After = &cursor.pageKey
+	}
+	return pr, nil
+}
+
+// paginate sorts the given keys according to the page request and returns the indexes of the keys
+// belonging to the requested page and the cursor of the next page if any
+func paginate(keys []pageKey, pr pageRequest) ([]int, string) {
+	less := func(a, b pageKey) bool {
+		if pr.Descending {
+			return b.less(a)
+		}
+		return a.less(b)
+	}
+	indexes := make([]int, 0, len(keys))
+	for i, k := range keys {
+		if pr.After == nil || less(*pr.After, k) {
+			indexes = append(indexes, i)
+		}
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		return less(keys[indexes[i]], keys[indexes[j]])
+	})
+	if pr.Limit == 0 || len(indexes) <= pr.Limit {
+		return indexes, ""
+	}
+	indexes = indexes[:pr.Limit]
+	b, _ := json.Marshal(pageCursor{Sort: pr.sortParam(), pageKey: keys[indexes[len(indexes)-1]]})
+	return indexes, base64.RawURLEncoding.EncodeToString(b)
+}
+
+// splitQueryValues returns the comma-separated values of all occurrences of a query parameter
+func splitQueryValues(values url.Values, param string) []string {
+	res := make([]string, 0)
+	for _, v := range values[param] {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				res = append(res, s)
+			}
+		}
+	}
+	return res
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
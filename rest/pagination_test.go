@@ -0,0 +1,106 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePageRequest(t *testing.T) {
+	sortFields := []string{"creation_date", "type"}
+	first, next := paginate([]pageKey{{"1", "a"}, {"2", "b"}}, pageRequest{Sort: "type", Descending: true, Limit: 1})
+	require.Equal(t, []int{1}, first)
+	tests := []struct {
+		name    string
+		query   string
+		want    pageRequest
+		wantErr bool
+	}{
+		{"Defaults", "", pageRequest{Sort: "creation_date", Descending: true, Limit: 10}, false},
+		{"SortAsc", "sort=type&limit=5", pageRequest{Sort: "type", Limit: 5}, false},
+		{"MaxLimit", "limit=100000", pageRequest{Sort: "creation_date", Descending: true, Limit: maxPageSize}, false},
+		{"Cursor", "sort=-type&cursor=" + next, pageRequest{Sort: "type", Descending: true, Limit: 10, After: &pageKey{"2", "b"}}, false},
+		{"CursorOtherSort", "sort=type&cursor=" + next, pageRequest{}, true},
+		{"InvalidCursor", "cursor=%2B%2B", pageRequest{}, true},
+		{"InvalidSort", "sort=status", pageRequest{}, true},
+		{"InvalidLimit", "limit=-1", pageRequest{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+			got, err := parsePageRequest(values, sortFields, "-creation_date", 10)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	keys := []pageKey{{"2", "c"}, {"1", "b"}, {"3", "a"}, {"1", "a"}}
+	tests := []struct {
+		name     string
+		pr       pageRequest
+		want     []int
+		wantNext bool
+	}{
+		{"Unlimited", pageRequest{}, []int{3, 1, 0, 2}, false},
+		{"Descending", pageRequest{Descending: true}, []int{2, 0, 1, 3}, false},
+		{"FirstPage", pageRequest{Limit: 3}, []int{3, 1, 0}, true},
+		{"LastPage", pageRequest{Limit: 3, After: &pageKey{"2", "c"}}, []int{2}, false},
+		{"DescendingAfter", pageRequest{Descending: true, Limit: 2, After: &pageKey{"2", "c"}}, []int{1, 3}, false},
+		{"ExactPage", pageRequest{Limit: 4}, []int{3, 1, 0, 2}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, next := paginate(keys, tt.pr)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.wantNext, next != "")
+		})
+	}
+}
+
+func TestPaginateFollowingCursors(t *testing.T) {
+	keys := []pageKey{{ID: "d"}, {ID: "a"}, {ID: "c"}, {ID: "b"}, {ID: "e"}}
+	values := url.Values{"limit": []string{"2"}}
+	ids := make([]string, 0)
+	for i := 0; i < len(keys); i++ {
+		pr, err := parsePageRequest(values, []string{"id"}, "id", 0)
+		require.NoError(t, err)
+		indexes, next := paginate(keys, pr)
+		for _, j := range indexes {
+			ids = append(ids, keys[j].ID)
+		}
+		if next == "" {
+			break
+		}
+		values.Set("cursor", next)
+	}
+	require.Equal(t, []string{"a", "b", "c", "d", "e"}, ids)
+}
+
+func TestSplitQueryValues(t *testing.T) {
+	values, err := url.ParseQuery("status=DONE,FAILED&status=%20RUNNING%20&status=")
+	require.NoError(t, err)
+	require.Equal(t, []string{"DONE", "FAILED", "RUNNING"}, splitQueryValues(values, "status"))
+	require.Empty(t, splitQueryValues(values, "type"))
+}
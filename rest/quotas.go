@@ -0,0 +1,155 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sort"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/hostspool"
+	"github.com/ystia/yorc/v4/quotas"
+)
+
+func (s *Server) listQuotasHandler(w http.ResponseWriter, r *http.Request) {
+	quotasList, err := quotas.GetQuotas(r.Context())
+	if err != nil {
+		log.Panic(err)
+	}
+	if len(quotasList) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	encodeJSONResponse(w, r, QuotasCollection{Quotas: quotasList})
+}
+
+func (s *Server) getQuotaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	params := ctx.Value(paramsLookupKey).(httprouter.Params)
+	q, err := quotas.GetQuota(ctx, params.ByName("quotaName"))
+	if err != nil {
+		log.Panic(err)
+	}
+	if q == nil {
+		writeError(w, r, errNotFound)
+		return
+	}
+	encodeJSONResponse(w, r, q)
+}
+
+func (s *Server) putQuotaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	params := ctx.Value(paramsLookupKey).(httprouter.Params)
+	name := params.ByName("quotaName")
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Panic(err)
+	}
+	q := new(quotas.Quota)
+	if err = json.Unmarshal(body, q); err != nil {
+		writeError(w, r, newBadRequestError(err))
+		return
+	}
+	if q.Name != "" && q.Name != name {
+		writeError(w, r, newBadRequestError(errors.Errorf("quota name %q doesn't match the name %q given in the URL", q.Name, name)))
+		return
+	}
+	q.Name = name
+	if err = q.Validate(); err != nil {
+		writeError(w, r, newBadRequestError(err))
+		return
+	}
+
+	existing, err := quotas.GetQuota(ctx, name)
+	if err != nil {
+		log.Panic(err)
+	}
+	if err = quotas.StoreQuota(ctx, q); err != nil {
+		log.Panic(err)
+	}
+	if existing != nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	w.Header().Set("Location", "/quotas/"+name)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) deleteQuotaHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	params := ctx.Value(paramsLookupKey).(httprouter.Params)
+	name := params.ByName("quotaName")
+	q, err := quotas.GetQuota(ctx, name)
+	if err != nil {
+		log.Panic(err)
+	}
+	if q == nil {
+		writeError(w, r, errNotFound)
+		return
+	}
+	if err = quotas.DeleteQuota(ctx, name); err != nil {
+		log.Panic(err)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) getQuotaUsageHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	params := ctx.Value(paramsLookupKey).(httprouter.Params)
+	q, err := quotas.GetQuota(ctx, params.ByName("quotaName"))
+	if err != nil {
+		log.Panic(err)
+	}
+	if q == nil {
+		writeError(w, r, errNotFound)
+		return
+	}
+	used, err := quotas.ComputeUsage(ctx, q)
+	if err != nil {
+		log.Panic(err)
+	}
+	hostsPoolUsed, err := hostspool.QuotaUsage(ctx, s.hostsPoolMgr, q)
+	if err != nil {
+		log.Panic(err)
+	}
+	used.Add(hostsPoolUsed)
+	limits, err := q.LimitedAmounts()
+	if err != nil {
+		log.Panic(err)
+	}
+	encodeJSONResponse(w, r, newQuotaUsage(q.Name, limits, used))
+}
+
+// newQuotaUsage builds the usage of the resources of a quota sorted by resource name
+func newQuotaUsage(name string, limits, used quotas.Amounts) QuotaUsage {
+	usage := QuotaUsage{Quota: name, Resources: make([]QuotaResourceUsage, 0, len(used))}
+	for resource, value := range used {
+		ru := QuotaResourceUsage{Name: resource, Unit: quotas.Unit(resource), Used: value}
+		if limit, ok := limits[resource]; ok {
+			ru.Limit = &limit
+		}
+		usage.Resources = append(usage.Resources, ru)
+	}
+	sort.Slice(usage.Resources, func(i, j int) bool {
+		return usage.Resources[i].Name < usage.Resources[j].Name
+	})
+	return usage
+}
@@ -0,0 +1,37 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/quotas"
+)
+
+func TestNewQuotaUsage(t *testing.T) {
+	limits := quotas.Amounts{quotas.ResourceDeployments: 2, quotas.ResourceHostsPoolMemory: 8e9}
+	used := quotas.Amounts{quotas.ResourceDeployments: 1, quotas.ResourceHostsPoolMemory: 2e9, quotas.ResourceConcurrentTasks: 3}
+
+	usage := newQuotaUsage("q", limits, used)
+	deploymentsLimit := float64(2)
+	memoryLimit := float64(8e9)
+	require.Equal(t, QuotaUsage{Quota: "q", Resources: []QuotaResourceUsage{
+		{Name: quotas.ResourceConcurrentTasks, Used: 3},
+		{Name: quotas.ResourceDeployments, Limit: &deploymentsLimit, Used: 1},
+		{Name: quotas.ResourceHostsPoolMemory, Unit: quotas.UnitBytes, Limit: &memoryLimit, Used: 2e9},
+	}}, usage)
+}
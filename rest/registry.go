@@ -57,3 +57,13 @@ func (s *Server) listInfraHandler(w http.ResponseWriter, r *http.Request) {
 	infraCollection := RegistryInfraUsageCollectorsCollection{InfraUsageCollectors: infras}
 	encodeJSONResponse(w, r, infraCollection)
 }
+
+func (s *Server) listPluginsHandler(w http.ResponseWriter, r *http.Request) {
+	pluginsCollection := RegistryPluginsCollection{Plugins: reg.ListPlugins()}
+	encodeJSONResponse(w, r, pluginsCollection)
+}
+
+func (s *Server) listStoresHandler(w http.ResponseWriter, r *http.Request) {
+	storesCollection := RegistryStoresCollection{Stores: reg.ListStoreBuilders()}
+	encodeJSONResponse(w, r, storesCollection)
+}
@@ -19,11 +19,15 @@ package rest
 import (
 	"bytes"
 	"encoding/json"
+	"time"
 
+	"github.com/ystia/yorc/v4/cluster"
 	"github.com/ystia/yorc/v4/config"
 	"github.com/ystia/yorc/v4/deployments/store"
 	"github.com/ystia/yorc/v4/prov/hostspool"
+	"github.com/ystia/yorc/v4/quotas"
 	"github.com/ystia/yorc/v4/registry"
+	"github.com/ystia/yorc/v4/tasks"
 	"github.com/ystia/yorc/v4/tosca"
 )
 
@@ -48,6 +52,8 @@ const (
 	LinkRelHost string = "host"
 	// LinkRelLocation defines the AtomLink Rel attribute for relationships of the "location"
 	LinkRelLocation string = "location"
+	// LinkRelSchedule defines the AtomLink Rel attribute for relationships of the "schedule"
+	LinkRelSchedule string = "schedule"
 )
 
 const (
@@ -78,7 +84,8 @@ func newAtomLink(rel, href string) AtomLink {
 
 // Health of a Yorc instance
 type Health struct {
-	Value string `json:"value"`
+	Value   string                  `json:"value"`
+	Plugins []registry.PluginStatus `json:"plugins,omitempty"`
 }
 
 // LocationRequest represents a request for creating or updating a location
@@ -103,9 +110,143 @@ type LocationsCollection struct {
 //
 // Deployment's links may be of type LinkRelSelf, LinkRelNode, LinkRelTask, LinkRelOutput.
 type Deployment struct {
-	ID     string     `json:"id"`
-	Status string     `json:"status"`
-	Links  []AtomLink `json:"links"`
+	ID          string            `json:"id"`
+	Status      string            `json:"status"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Expiry      *DeploymentExpiry `json:"expiry,omitempty"`
+	Links       []AtomLink        `json:"links"`
+}
+
+// DeploymentExpiry is the representation of the expiry of a deployment having a time-to-live
+type DeploymentExpiry struct {
+	Date  time.Time `json:"date"`
+	Purge bool      `json:"purge"`
+}
+
+// DeploymentMetadataRequest represents a request for updating labels and annotations of a deployment
+type DeploymentMetadataRequest struct {
+	Labels      []MapEntry               `json:"labels,omitempty"`
+	Annotations []MapEntry               `json:"annotations,omitempty"`
+	Expiry      *DeploymentExpiryRequest `json:"expiry,omitempty"`
+}
+
+// DeploymentExpiryRequest sets, extends or removes the expiry date of a deployment
+//
+// Exactly one of TTL or Date should be defined unless Remove is true. TTL is a duration counted from the request time.
+// The purge flag of a deployment is kept unchanged if Purge is not defined.
+type DeploymentExpiryRequest struct {
+	TTL    string     `json:"ttl,omitempty"`
+	Date   *time.Time `json:"date,omitempty"`
+	Purge  *bool      `json:"purge,omitempty"`
+	Remove bool       `json:"remove,omitempty"`
+}
+
+// ScheduleRequest is a request to create a schedule for a deployment
+//
+// Exactly one of Cron or At should be defined as well as exactly one of the Workflow, CustomCommand,
+// Scale or Undeploy operations.
+type ScheduleRequest struct {
+	Cron          string                `json:"cron,omitempty"`
+	At            *time.Time            `json:"at,omitempty"`
+	TimeZone      string                `json:"time_zone,omitempty"`
+	OverlapPolicy string                `json:"overlap_policy,omitempty"`
+	Workflow      *ScheduledWorkflow    `json:"workflow,omitempty"`
+	CustomCommand *CustomCommandRequest `json:"custom_command,omitempty"`
+	Scale         *ScheduledScale       `json:"scale,omitempty"`
+	Undeploy      *ScheduledUndeploy    `json:"undeploy,omitempty"`
+}
+
+// ScheduledWorkflow is a scheduled custom workflow execution
+type ScheduledWorkflow struct {
+	Name            string `json:"name"`
+	ContinueOnError bool   `json:"continue_on_error,omitempty"`
+	WorkflowRequest
+}
+
+// ScheduledScale is a scheduled scaling of a node, a positive delta scales out the node while a negative one scales it in
+type ScheduledScale struct {
+	NodeName string `json:"node"`
+	Delta    int    `json:"delta"`
+}
+
+// ScheduledUndeploy is a scheduled undeployment
+type ScheduledUndeploy struct {
+	StopOnError bool `json:"stop_on_error,omitempty"`
+}
+
+// Schedule is the representation of a deployment schedule
+//
+// Schedule's links are of type LinkRelSelf, LinkRelDeployment and LinkRelTask for the latest registered task.
+type Schedule struct {
+	ID            string            `json:"id"`
+	Cron          string            `json:"cron,omitempty"`
+	At            *time.Time        `json:"at,omitempty"`
+	TimeZone      string            `json:"time_zone,omitempty"`
+	OverlapPolicy string            `json:"overlap_policy"`
+	TaskType      string            `json:"task_type"`
+	Data          map[string]string `json:"data,omitempty"`
+	CreationDate  time.Time         `json:"creation_date"`
+	NextRun       *time.Time        `json:"next_run,omitempty"`
+	LatestTaskID  string            `json:"latest_task_id,omitempty"`
+	Links         []AtomLink        `json:"links"`
+}
+
+// SchedulesCollection is a collection of schedules
+type SchedulesCollection struct {
+	Schedules []Schedule `json:"schedules"`
+}
+
+// RolloutRequest is a request to progressively execute a workflow on the instances of a scalable node
+//
+// Exactly one of BatchSize or BatchPercentage should be defined. Pause and HealthGateDelay are durations
+// such as "30s" or "5m".
+type RolloutRequest struct {
+	Workflow         string                 `json:"workflow"`
+	NodeName         string                 `json:"node"`
+	Inputs           map[string]interface{} `json:"inputs,omitempty"`
+	Canary           int                    `json:"canary,omitempty"`
+	BatchSize        int                    `json:"batch_size,omitempty"`
+	BatchPercentage  int                    `json:"batch_percentage,omitempty"`
+	Pause            string                 `json:"pause,omitempty"`
+	HealthGateDelay  string                 `json:"health_gate_delay,omitempty"`
+	RollbackWorkflow string                 `json:"rollback_workflow,omitempty"`
+}
+
+// Rollout is the representation of a rollout of a workflow on a scalable node
+//
+// Rollout's links are of type LinkRelSelf and LinkRelDeployment.
+type Rollout struct {
+	ID               string            `json:"id"`
+	NodeName         string            `json:"node"`
+	Workflow         string            `json:"workflow"`
+	RollbackWorkflow string            `json:"rollback_workflow,omitempty"`
+	Inputs           map[string]string `json:"inputs,omitempty"`
+	Canary           int               `json:"canary,omitempty"`
+	BatchSize        int               `json:"batch_size,omitempty"`
+	BatchPercentage  int               `json:"batch_percentage,omitempty"`
+	Pause            string            `json:"pause,omitempty"`
+	HealthGateDelay  string            `json:"health_gate_delay,omitempty"`
+	Status           string            `json:"status"`
+	CurrentBatch     int               `json:"current_batch"`
+	Batches          []RolloutBatch    `json:"batches"`
+	RollbackTaskID   string            `json:"rollback_task_id,omitempty"`
+	Error            string            `json:"error,omitempty"`
+	CreationDate     time.Time         `json:"creation_date"`
+	EndDate          *time.Time        `json:"end_date,omitempty"`
+	Links            []AtomLink        `json:"links"`
+}
+
+// RolloutBatch is the representation of a batch of instances of a rollout
+type RolloutBatch struct {
+	Instances []string `json:"instances"`
+	Status    string   `json:"status"`
+	TaskID    string   `json:"task_id,omitempty"`
+}
+
+// RolloutsCollection is a collection of rollouts
+type RolloutsCollection struct {
+	Rollouts []Rollout `json:"rollouts"`
 }
 
 // Output is the representation of a deployment output
@@ -119,6 +260,8 @@ type Output struct {
 // Links are all of type LinkRelDeployment.
 type DeploymentsCollection struct {
 	Deployments []Deployment `json:"deployments"`
+	// NextCursor allows to retrieve the next page of deployments when the collection is paginated
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // EventsCollection is a collection of instances status change events
@@ -164,6 +307,53 @@ type Task struct {
 	ErrorMessage string            `json:"error_message,omitempty"`
 	ResultSet    json.RawMessage   `json:"result_set,omitempty"`
 	Outputs      map[string]string `json:"outputs,omitempty"`
+	// Approval is set for tasks matching an approval policy
+	Approval *tasks.TaskApproval `json:"approval,omitempty"`
+	// Signals are the signals expected or received by workflow steps of the task
+	Signals []tasks.TaskStepSignal `json:"signals,omitempty"`
+}
+
+// TaskApprovalRequest is the optional body of a task approval request
+//
+// The approver is ignored if the request is authenticated by a TLS client certificate, the common name of
+// the certificate is used instead.
+type TaskApprovalRequest struct {
+	Approver string `json:"approver,omitempty"`
+}
+
+// TaskStepSignalRequest is the optional body of a request sending a signal to a workflow step
+//
+// Inputs are made available to the next steps of the workflow as workflow inputs.
+type TaskStepSignalRequest struct {
+	Inputs map[string]interface{} `json:"inputs,omitempty"`
+}
+
+// TaskSummary is the representation of a task in tasks listings
+type TaskSummary struct {
+	ID           string     `json:"id"`
+	TargetID     string     `json:"target_id"`
+	Type         string     `json:"type"`
+	Status       string     `json:"status"`
+	WorkflowName string     `json:"workflow_name,omitempty"`
+	CreationDate time.Time  `json:"creation_date"`
+	EndDate      *time.Time `json:"end_date,omitempty"`
+	// Duration is the time elapsed since the task creation until it ended or until now if it is still running
+	Duration string          `json:"duration,omitempty"`
+	Steps    *TaskStepsCount `json:"steps,omitempty"`
+	Links    []AtomLink      `json:"links,omitempty"`
+}
+
+// TaskStepsCount gives the number of steps of a workflow task by step status
+type TaskStepsCount struct {
+	Total    int            `json:"total"`
+	ByStatus map[string]int `json:"by_status"`
+}
+
+// TaskHistoryCollection is a page of a tasks listing
+type TaskHistoryCollection struct {
+	Tasks []TaskSummary `json:"tasks"`
+	// NextCursor allows to retrieve the next page of tasks if any
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // TasksCollection is the collection of task's links
@@ -332,8 +522,42 @@ type RegistryInfraUsageCollectorsCollection struct {
 	InfraUsageCollectors []registry.InfraUsageCollector `json:"infrastructure_usage_collectors"`
 }
 
+// RegistryPluginsCollection is the collection of plugins managed by Yorc
+type RegistryPluginsCollection struct {
+	Plugins []registry.PluginStatus `json:"plugins"`
+}
+
+// RegistryStoresCollection is the collection of store implementations registered in the Yorc registry
+type RegistryStoresCollection struct {
+	Stores []registry.StoreBuilder `json:"stores"`
+}
+
+// ClusterMembersCollection is a collection of the Yorc servers members of the cluster
+type ClusterMembersCollection struct {
+	Members []cluster.Member `json:"members"`
+}
+
 // Info are the infos about the current YORC server
 type Info struct {
 	YorcVersion string `json:"yorc_version"`
 	GitCommit   string `json:"git_commit"`
 }
+
+// QuotasCollection is the collection of quotas definitions
+type QuotasCollection struct {
+	Quotas []*quotas.Quota `json:"quotas"`
+}
+
+// QuotaUsage is the current usage of the resources of the deployments a quota applies to
+type QuotaUsage struct {
+	Quota     string               `json:"quota"`
+	Resources []QuotaResourceUsage `json:"resources"`
+}
+
+// QuotaResourceUsage is the used amount of a resource and its limit if the quota defines one
+type QuotaResourceUsage struct {
+	Name  string   `json:"name"`
+	Unit  string   `json:"unit,omitempty"`
+	Limit *float64 `json:"limit,omitempty"`
+	Used  float64  `json:"used"`
+}
@@ -0,0 +1,353 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/tasks"
+)
+
+// defaultTasksPageSize is the number of tasks returned by tasks listings when no limit is given
+const defaultTasksPageSize = 100
+
+// taskHistorySortFields are the fields tasks listings could be sorted by
+var taskHistorySortFields = []string{"creation_date", "duration", "type", "status"}
+
+// taskHistoryFilter selects tasks of a tasks listing
+type taskHistoryFilter struct {
+	types     map[tasks.TaskType]bool
+	statuses  map[tasks.TaskStatus]bool
+	workflows map[string]bool
+	from      time.Time
+	to        time.Time
+}
+
+// taskRecord is a task summary with the typed values used to filter and sort tasks
+type taskRecord struct {
+	summary  TaskSummary
+	taskType tasks.TaskType
+	status   tasks.TaskStatus
+	duration time.Duration
+}
+
+// parseTaskHistoryFilter parses the type, status, workflow, from and to query parameters
+func parseTaskHistoryFilter(values url.Values) (taskHistoryFilter, error) {
+	f := taskHistoryFilter{
+		types:     make(map[tasks.TaskType]bool),
+		statuses:  make(map[tasks.TaskStatus]bool),
+		workflows: make(map[string]bool),
+	}
+	for _, v := range splitQueryValues(values, "type") {
+		tt, err := tasks.ParseTaskType(v)
+		if err != nil {
+			return f, err
+		}
+		f.types[tt] = true
+	}
+	for _, v := range splitQueryValues(values, "status") {
+		st, err := tasks.ParseTaskStatus(v)
+		if err != nil {
+			return f, err
+		}
+		f.statuses[st] = true
+	}
+	for _, v := range splitQueryValues(values, "workflow") {
+		f.workflows[v] = true
+	}
+	var err error
+	if v := values.Get("from"); v != "" {
+		f.from, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			return f, errors.Errorf("invalid from date %q, expecting a RFC3339 date", v)
+		}
+	}
+	if v := values.Get("to"); v != "" {
+		f.to, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			return f, errors.Errorf("invalid to date %q, expecting a RFC3339 date", v)
+		}
+	}
+	return f, nil
+}
+
+func (f taskHistoryFilter) match(rec taskRecord) bool {
+	if len(f.types) > 0 && !f.types[rec.taskType] {
+		return false
+	}
+	if len(f.statuses) > 0 && !f.statuses[rec.status] {
+		return false
+	}
+	if len(f.workflows) > 0 && !f.workflows[rec.summary.WorkflowName] {
+		return false
+	}
+	if !f.from.IsZero() && rec.summary.CreationDate.Before(f.from) {
+		return false
+	}
+	if !f.to.IsZero() && !rec.summary.CreationDate.Before(f.to) {
+		return false
+	}
+	return true
+}
+
+// sortKey returns the pagination key of a task record for the given sort field
+func (rec taskRecord) sortKey(field string) pageKey {
+	k := pageKey{ID: rec.summary.ID}
+	switch field {
+	case "creation_date":
+		k.Value = fmt.Sprintf("%020d", rec.summary.CreationDate.UnixNano())
+	case "duration":
+		// The duration of tasks not in a final status grows with time, their position in a
+		// listing sorted by duration is not stable between pages
+		if rec.summary.Duration != "" {
+			k.Value = fmt.Sprintf("%020d", rec.duration)
+		}
+	case "type":
+		k.Value = rec.summary.Type
+	case "status":
+		k.Value = rec.summary.Status
+	}
+	return k
+}
+
+// setDuration computes the duration of a task from its creation date and its end date if the task
+// is in a final status or now otherwise
+func (rec *taskRecord) setDuration(end, now time.Time) {
+	if !tasks.IsFinalStatus(rec.status) {
+		end = now
+	} else if !end.IsZero() {
+		rec.summary.EndDate = &end
+	}
+	if !end.IsZero() {
+		rec.duration = end.Sub(rec.summary.CreationDate)
+		rec.summary.Duration = rec.duration.Round(time.Millisecond).String()
+	}
+}
+
+// listTasksKeys retrieves the keys of all tasks with a single request, they are returned by task ID
+// and by key relative to the task prefix
+func listTasksKeys() (map[string]map[string][]byte, error) {
+	kvs, err := consulutil.List(consulutil.TasksPrefix + "/")
+	if err != nil {
+		return nil, err
+	}
+	tasksKeys := make(map[string]map[string][]byte)
+	for k, v := range kvs {
+		parts := strings.SplitN(strings.TrimPrefix(k, consulutil.TasksPrefix+"/"), "/", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if tasksKeys[parts[0]] == nil {
+			tasksKeys[parts[0]] = make(map[string][]byte)
+		}
+		tasksKeys[parts[0]][parts[1]] = v
+	}
+	return tasksKeys, nil
+}
+
+// newTaskCandidate builds the record of a task from its keys with only the fields used to filter
+// and sort tasks
+func newTaskCandidate(taskID string, keys map[string][]byte, now time.Time) (taskRecord, error) {
+	rec := taskRecord{summary: TaskSummary{ID: taskID}}
+	// Values are checked by parsing their names back, only values defined by the enums have a name
+	status, err := strconv.Atoi(string(keys["status"]))
+	if err == nil {
+		rec.status, err = tasks.ParseTaskStatus(tasks.TaskStatus(status).String())
+	}
+	if err != nil {
+		return rec, errors.Errorf("invalid status %q for task with id %q", keys["status"], taskID)
+	}
+	taskType, err := strconv.Atoi(string(keys["type"]))
+	if err == nil {
+		rec.taskType, err = tasks.ParseTaskType(tasks.TaskType(taskType).String())
+	}
+	if err != nil {
+		return rec, errors.Errorf("invalid type %q for task with id %q", keys["type"], taskID)
+	}
+	rec.summary.Status = rec.status.String()
+	rec.summary.Type = rec.taskType.String()
+	if err = rec.summary.CreationDate.UnmarshalBinary(keys["creationDate"]); err != nil {
+		return rec, errors.Wrapf(err, "invalid creationDate for task with id %q", taskID)
+	}
+	rec.summary.WorkflowName = string(keys["data/workflowName"])
+	var end time.Time
+	if v := keys["endDate"]; len(v) > 0 {
+		if err = end.UnmarshalBinary(v); err != nil {
+			return rec, errors.Wrapf(err, "invalid endDate for task with id %q", taskID)
+		}
+	}
+	rec.setDuration(end, now)
+	return rec, nil
+}
+
+// newTaskRecord builds the full record of a task, its duration is computed from its creation date and
+// its end date if the task is in a final status or now otherwise
+func newTaskRecord(taskID string, now time.Time) (taskRecord, error) {
+	rec := taskRecord{summary: TaskSummary{ID: taskID}}
+	var err error
+	rec.status, err = tasks.GetTaskStatus(taskID)
+	if err != nil {
+		return rec, err
+	}
+	rec.taskType, err = tasks.GetTaskType(taskID)
+	if err != nil {
+		return rec, err
+	}
+	rec.summary.TargetID, err = tasks.GetTaskTarget(taskID)
+	if err != nil {
+		return rec, err
+	}
+	rec.summary.CreationDate, err = tasks.GetTaskCreationDate(taskID)
+	if err != nil {
+		return rec, err
+	}
+	// workflow name is not filled for non-workflow tasks
+	rec.summary.WorkflowName, err = tasks.GetTaskData(taskID, "workflowName")
+	if err != nil && !tasks.IsTaskDataNotFoundError(err) {
+		return rec, err
+	}
+	rec.summary.Type = rec.taskType.String()
+	rec.summary.Status = rec.status.String()
+
+	var end time.Time
+	if tasks.IsFinalStatus(rec.status) {
+		end, err = tasks.GetTaskEndDate(taskID)
+		if err != nil {
+			return rec, err
+		}
+	}
+	rec.setDuration(end, now)
+	rec.summary.Steps, err = countTaskSteps(taskID)
+	return rec, err
+}
+
+// countTaskSteps counts the steps of a workflow task by status, nil is returned for tasks without steps
+func countTaskSteps(taskID string) (*TaskStepsCount, error) {
+	steps, err := tasks.GetTaskRelatedSteps(taskID)
+	if err != nil || len(steps) == 0 {
+		return nil, err
+	}
+	count := &TaskStepsCount{Total: len(steps), ByStatus: make(map[string]int)}
+	for _, step := range steps {
+		count.ByStatus[step.Status]++
+	}
+	return count, nil
+}
+
+func (s *Server) listTasksHandler(w http.ResponseWriter, r *http.Request) {
+	s.writeTasksHistory(w, r, nil)
+}
+
+func (s *Server) listDeploymentTasksHandler(w http.ResponseWriter, r *http.Request) {
+	var params httprouter.Params
+	ctx := r.Context()
+	params = ctx.Value(paramsLookupKey).(httprouter.Params)
+	id := params.ByName("id")
+
+	if _, err := deployments.GetDeploymentStatus(ctx, id); err != nil {
+		if deployments.IsDeploymentNotFoundError(err) {
+			writeError(w, r, errNotFound)
+			return
+		}
+		log.Panic(err)
+	}
+	taskIDs, err := deployments.GetDeploymentTaskList(ctx, id)
+	if err != nil {
+		log.Panic(err)
+	}
+	s.writeTasksHistory(w, r, taskIDs)
+}
+
+// writeTasksHistory writes the requested page of the given tasks, or of all tasks if taskIDs is nil,
+// matching the request filters.
+//
+// Tasks are filtered and sorted using the keys retrieved by a single request on the tasks prefix,
+// full records are built only for tasks of the returned page.
+func (s *Server) writeTasksHistory(w http.ResponseWriter, r *http.Request, taskIDs []string) {
+	values := r.URL.Query()
+	filter, err := parseTaskHistoryFilter(values)
+	if err != nil {
+		writeError(w, r, newBadRequestError(err))
+		return
+	}
+	pr, err := parsePageRequest(values, taskHistorySortFields, "-creation_date", defaultTasksPageSize)
+	if err != nil {
+		writeError(w, r, newBadRequestError(err))
+		return
+	}
+
+	tasksKeys, err := listTasksKeys()
+	if err != nil {
+		log.Panic(err)
+	}
+	if taskIDs == nil {
+		taskIDs = make([]string, 0, len(tasksKeys))
+		for taskID := range tasksKeys {
+			taskIDs = append(taskIDs, taskID)
+		}
+	}
+
+	now := time.Now()
+	candidates := make([]string, 0, len(taskIDs))
+	keys := make([]pageKey, 0, len(taskIDs))
+	for _, taskID := range taskIDs {
+		taskKeys, ok := tasksKeys[taskID]
+		if !ok {
+			// Tasks may be deleted while listed
+			continue
+		}
+		rec, err := newTaskCandidate(taskID, taskKeys, now)
+		if err != nil {
+			log.Printf("[WARNING] the task with id:%q won't be listed due to error:%+v", taskID, err)
+			continue
+		}
+		if filter.match(rec) {
+			candidates = append(candidates, taskID)
+			keys = append(keys, rec.sortKey(pr.Sort))
+		}
+	}
+	indexes, nextCursor := paginate(keys, pr)
+	if len(indexes) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	collection := TaskHistoryCollection{Tasks: make([]TaskSummary, 0, len(indexes)), NextCursor: nextCursor}
+	for _, i := range indexes {
+		rec, err := newTaskRecord(candidates[i], now)
+		if err != nil {
+			// Tasks may be deleted while listed
+			log.Printf("[WARNING] the task with id:%q won't be listed due to error:%+v", candidates[i], err)
+			continue
+		}
+		if tasks.IsDeploymentRelatedTask(rec.taskType) {
+			rec.summary.Links = []AtomLink{newAtomLink(LinkRelTask, path.Join("/deployments", rec.summary.TargetID, "tasks", rec.summary.ID))}
+		}
+		collection.Tasks = append(collection.Tasks, rec.summary)
+	}
+	encodeJSONResponse(w, r, collection)
+}
@@ -0,0 +1,132 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rest
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/tasks"
+)
+
+func TestTaskHistoryFilter(t *testing.T) {
+	creation := time.Date(2019, 6, 12, 10, 0, 0, 0, time.UTC)
+	rec := taskRecord{
+		summary:  TaskSummary{ID: "t1", WorkflowName: "install", CreationDate: creation},
+		taskType: tasks.TaskTypeDeploy,
+		status:   tasks.TaskStatusDONE,
+	}
+	tests := []struct {
+		name      string
+		query     string
+		wantMatch bool
+		wantErr   bool
+	}{
+		{"NoFilter", "", true, false},
+		{"Types", "type=UnDeploy,Deploy", true, false},
+		{"OtherType", "type=UnDeploy", false, false},
+		{"Statuses", "status=FAILED&status=DONE", true, false},
+		{"OtherStatus", "status=RUNNING", false, false},
+		{"Workflow", "workflow=install", true, false},
+		{"OtherWorkflow", "workflow=uninstall", false, false},
+		{"DateRange", "from=2019-06-12T10:00:00Z&to=2019-06-12T11:00:00Z", true, false},
+		{"BeforeRange", "from=2019-06-12T10:00:01Z", false, false},
+		{"AfterRange", "to=2019-06-12T10:00:00Z", false, false},
+		{"InvalidType", "type=deploy", false, true},
+		{"InvalidStatus", "status=done", false, true},
+		{"InvalidFrom", "from=yesterday", false, true},
+		{"InvalidTo", "to=2019-06-12", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+			f, err := parseTaskHistoryFilter(values)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantMatch, f.match(rec))
+		})
+	}
+}
+
+func TestTaskRecordSortKey(t *testing.T) {
+	older := taskRecord{summary: TaskSummary{ID: "b", CreationDate: time.Unix(100, 0), Duration: "2s", Type: "Deploy"}, duration: 2 * time.Second}
+	newer := taskRecord{summary: TaskSummary{ID: "a", CreationDate: time.Unix(1000, 0), Duration: "10s", Type: "UnDeploy"}, duration: 10 * time.Second}
+	unknown := taskRecord{summary: TaskSummary{ID: "c", CreationDate: time.Unix(10, 0)}}
+
+	for _, field := range []string{"creation_date", "duration", "type"} {
+		require.True(t, older.sortKey(field).less(newer.sortKey(field)), "unexpected order for %s", field)
+	}
+	require.True(t, unknown.sortKey("duration").less(older.sortKey("duration")))
+	require.Equal(t, "c", unknown.sortKey("status").ID)
+}
+
+func TestNewTaskCandidate(t *testing.T) {
+	creation := time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC)
+	end := creation.Add(90 * time.Second)
+	now := creation.Add(time.Hour)
+	creationValue, err := creation.MarshalBinary()
+	require.NoError(t, err)
+	endValue, err := end.MarshalBinary()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		keys         map[string][]byte
+		wantType     tasks.TaskType
+		wantStatus   tasks.TaskStatus
+		wantWorkflow string
+		wantDuration time.Duration
+		wantEndDate  bool
+		wantErr      bool
+	}{
+		{"DoneWorkflow", map[string][]byte{"type": []byte("6"), "status": []byte("2"), "creationDate": creationValue, "endDate": endValue, "data/workflowName": []byte("install")},
+			tasks.TaskTypeCustomWorkflow, tasks.TaskStatusDONE, "install", 90 * time.Second, true, false},
+		{"RunningUsesNow", map[string][]byte{"type": []byte("0"), "status": []byte("1"), "creationDate": creationValue, "endDate": endValue},
+			tasks.TaskTypeDeploy, tasks.TaskStatusRUNNING, "", time.Hour, false, false},
+		{"DoneWithoutEndDate", map[string][]byte{"type": []byte("0"), "status": []byte("3"), "creationDate": creationValue},
+			tasks.TaskTypeDeploy, tasks.TaskStatusFAILED, "", 0, false, false},
+		{"PendingApprovalRemoveNodes", map[string][]byte{"type": []byte("11"), "status": []byte("5"), "creationDate": creationValue},
+			tasks.TaskTypeRemoveNodes, tasks.TaskStatusPENDINGAPPROVAL, "", time.Hour, false, false},
+		{"InvalidType", map[string][]byte{"type": []byte("-1"), "status": []byte("0"), "creationDate": creationValue}, 0, 0, "", 0, false, true},
+		{"InvalidStatus", map[string][]byte{"type": []byte("0"), "status": []byte("42"), "creationDate": creationValue}, 0, 0, "", 0, false, true},
+		{"MissingType", map[string][]byte{"status": []byte("0"), "creationDate": creationValue}, 0, 0, "", 0, false, true},
+		{"MissingCreationDate", map[string][]byte{"type": []byte("0"), "status": []byte("0")}, 0, 0, "", 0, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := newTaskCandidate("t1", tt.keys, now)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantType, rec.taskType)
+			require.Equal(t, tt.wantStatus, rec.status)
+			require.Equal(t, tt.wantType.String(), rec.summary.Type)
+			require.Equal(t, tt.wantStatus.String(), rec.summary.Status)
+			require.Equal(t, tt.wantWorkflow, rec.summary.WorkflowName)
+			require.Equal(t, tt.wantDuration, rec.duration)
+			require.Equal(t, tt.wantEndDate, rec.summary.EndDate != nil)
+			require.True(t, creation.Equal(rec.summary.CreationDate))
+		})
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package backup allows to save a point-in-time consistent snapshot of all the data
+// owned by a Yorc cluster and to restore it into a Yorc cluster.
+//
+// A backup is a zip archive containing:
+//   - a manifest describing the backup and the version of the data schema
+//   - the Consul KV keys owned by Yorc (tasks, deployments runtime data, hosts pools,
+//     locations, monitoring checks, scheduled actions, ...)
+//   - the content of the deployments, logs and events stores
+//   - the CSARs and overlays of the deployments stored in the Yorc working directory
+package backup
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/storage/types"
+)
+
+// FormatVersion is the version of the backup format produced by this package
+const FormatVersion = 1
+
+const (
+	manifestFile = "manifest.json"
+	consulFile   = "consul.json"
+	storesDir    = "stores/"
+	filesDir     = "files/"
+	csarFile     = "deployment.zip"
+	overlayDir   = "overlay"
+)
+
+// Manifest describes the content of a backup
+type Manifest struct {
+	FormatVersion int       `json:"format_version"`
+	SchemaVersion string    `json:"schema_version"`
+	YorcVersion   string    `json:"yorc_version,omitempty"`
+	BackupDate    time.Time `json:"backup_date"`
+	ConsulIndex   uint64    `json:"consul_index"`
+	Deployments   int       `json:"deployments"`
+}
+
+// kvPair is a Consul KV key
+type kvPair struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// entry is a store key with its raw value
+type entry struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+type badBackupError struct {
+	msg string
+}
+
+func (e badBackupError) Error() string {
+	return e.msg
+}
+
+// IsBadBackupError checks if an error is due to an invalid backup
+func IsBadBackupError(err error) bool {
+	_, ok := errors.Cause(err).(badBackupError)
+	return ok
+}
+
+// ReadManifest reads and checks the manifest of a backup
+func ReadManifest(r *zip.Reader) (*Manifest, error) {
+	m := new(Manifest)
+	found, err := readJSONFile(r, manifestFile, m)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errors.WithStack(badBackupError{"missing manifest in backup"})
+	}
+	if m.FormatVersion != FormatVersion {
+		return nil, errors.WithStack(badBackupError{fmt.Sprintf("unsupported backup format version %d, expecting %d", m.FormatVersion, FormatVersion)})
+	}
+	if m.SchemaVersion == "" {
+		return nil, errors.WithStack(badBackupError{"missing data schema version in backup manifest"})
+	}
+	return m, nil
+}
+
+func readJSONFile(r *zip.Reader, name string, v interface{}) (bool, error) {
+	for _, f := range r.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to read %q from backup", name)
+		}
+		defer rc.Close()
+		err = json.NewDecoder(rc).Decode(v)
+		if err != nil {
+			return false, errors.WithStack(badBackupError{fmt.Sprintf("failed to decode %q from backup: %v", name, err)})
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func writeJSONFile(w *zip.Writer, name string, v interface{}) error {
+	f, err := w.Create(name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to add %q to backup", name)
+	}
+	return errors.Wrapf(json.NewEncoder(f).Encode(v), "failed to add %q to backup", name)
+}
+
+func storeFile(storeType types.StoreType) string {
+	return storesDir + strings.ToLower(storeType.String()) + ".json"
+}
+
+// isExcludedKey checks if a Consul key should not be part of a backup.
+//
+// Excluded keys are locks and semaphores, data related to the running Yorc servers,
+// the stores configuration that belongs to each cluster configuration and the schema
+// version that is stored into the manifest.
+func isExcludedKey(kvp *api.KVPair) bool {
+	if kvp.Session != "" || kvp.Flags == api.LockFlagValue || kvp.Flags == api.SemaphoreFlagValue {
+		return true
+	}
+	return kvp.Key == consulutil.YorcSchemaVersionPath || strings.HasPrefix(kvp.Key, consulutil.YorcSchemaVersionPath+".") ||
+		isUnderPrefix(kvp.Key, consulutil.StoresPrefix) || isUnderPrefix(kvp.Key, consulutil.YorcServicePrefix)
+}
+
+func isUnderPrefix(key, prefix string) bool {
+	return key == prefix || strings.HasPrefix(key, prefix+"/")
+}
+
+// deploymentsIDs returns the IDs of deployments having keys in the given list
+func deploymentsIDs(kvps api.KVPairs) []string {
+	ids := make([]string, 0)
+	seen := make(map[string]struct{})
+	for _, kvp := range kvps {
+		if !strings.HasPrefix(kvp.Key, consulutil.DeploymentKVPrefix+"/") {
+			continue
+		}
+		id := strings.SplitN(strings.TrimPrefix(kvp.Key, consulutil.DeploymentKVPrefix+"/"), "/", 2)[0]
+		if _, ok := seen[id]; ok || id == "" {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func deploymentPrefix(deploymentID string) string {
+	return path.Join(consulutil.DeploymentKVPrefix, deploymentID)
+}
@@ -0,0 +1,205 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/tasks"
+	"github.com/ystia/yorc/v4/testutil"
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+func buildBackup(t *testing.T, files map[string]string) *zip.Reader {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	return r
+}
+
+func TestReadManifest(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		wantErr   bool
+		badBackup bool
+	}{
+		{"Valid", map[string]string{manifestFile: `{"format_version": 1, "schema_version": "1.3.1"}`}, false, false},
+		{"Missing", map[string]string{consulFile: `[]`}, true, true},
+		{"Invalid", map[string]string{manifestFile: `{"format_version": `}, true, true},
+		{"UnsupportedFormat", map[string]string{manifestFile: `{"format_version": 2, "schema_version": "1.3.1"}`}, true, true},
+		{"MissingSchemaVersion", map[string]string{manifestFile: `{"format_version": 1}`}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := ReadManifest(buildBackup(t, tt.files))
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Equal(t, tt.badBackup, IsBadBackupError(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "1.3.1", m.SchemaVersion)
+		})
+	}
+}
+
+func TestIsExcludedKey(t *testing.T) {
+	tests := []struct {
+		name string
+		kvp  *api.KVPair
+		want bool
+	}{
+		{"DeploymentKey", &api.KVPair{Key: consulutil.DeploymentKVPrefix + "/dep/status"}, false},
+		{"TaskKey", &api.KVPair{Key: consulutil.TasksPrefix + "/task/status"}, false},
+		{"HeldLock", &api.KVPair{Key: consulutil.DeploymentKVPrefix + "/dep/.lock", Session: "session"}, true},
+		{"ReleasedLock", &api.KVPair{Key: consulutil.DeploymentKVPrefix + "/dep/.lock", Flags: api.LockFlagValue}, true},
+		{"Semaphore", &api.KVPair{Key: consulutil.TasksPrefix + "/.sem", Flags: api.SemaphoreFlagValue}, true},
+		{"SchemaVersion", &api.KVPair{Key: consulutil.YorcSchemaVersionPath}, true},
+		{"StoresConfig", &api.KVPair{Key: consulutil.StoresPrefix + "/defaultConsulStore"}, true},
+		{"Services", &api.KVPair{Key: consulutil.YorcServicePrefix + "/monitoring/leader"}, true},
+		{"NotAServiceKey", &api.KVPair{Key: consulutil.YorcServicePrefix + "s/key"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, isExcludedKey(tt.kvp))
+		})
+	}
+}
+
+func TestDeploymentsIDs(t *testing.T) {
+	kvps := api.KVPairs{
+		{Key: consulutil.DeploymentKVPrefix + "/dep1/status"},
+		{Key: consulutil.DeploymentKVPrefix + "/dep1/topology/instances/Compute/0/state"},
+		{Key: consulutil.DeploymentKVPrefix + "/dep2/status"},
+		{Key: consulutil.TasksPrefix + "/task/targetId"},
+	}
+	require.Equal(t, []string{"dep1", "dep2"}, deploymentsIDs(kvps))
+}
+
+func TestRestoreFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yorc-restore-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	r := buildBackup(t, map[string]string{
+		manifestFile: `{}`,
+		filesDir + "deployments/dep/deployment.zip": "csar",
+		filesDir + "deployments/dep/overlay/a.yml":  "a",
+	})
+	err = restoreFiles(r, dir)
+	require.NoError(t, err)
+	b, err := ioutil.ReadFile(filepath.Join(dir, "deployments", "dep", "overlay", "a.yml"))
+	require.NoError(t, err)
+	require.Equal(t, "a", string(b))
+	_, err = os.Stat(filepath.Join(dir, manifestFile))
+	require.True(t, os.IsNotExist(err))
+
+	r = buildBackup(t, map[string]string{filesDir + "../evil": "evil"})
+	err = restoreFiles(r, dir)
+	require.Error(t, err)
+	require.True(t, IsBadBackupError(err))
+}
+
+func testSaveRestore(t *testing.T, kv *api.KV, workingDir string) {
+	ctx := context.Background()
+	deploymentID := testutil.BuildDeploymentID(t)
+	taskID := deploymentID + "-task"
+
+	err := consulutil.StoreConsulKeyAsString(consulutil.YorcSchemaVersionPath, consulutil.YorcSchemaVersion)
+	require.NoError(t, err)
+	err = deployments.StoreDeploymentDefinition(ctx, deploymentID, "testdata/topology.yaml")
+	require.NoError(t, err)
+	err = deployments.SetInstanceStateWithContextualLogs(ctx, deploymentID, "Compute", "0", tosca.NodeStateStarted)
+	require.NoError(t, err)
+	err = deployments.SetDeploymentStatus(ctx, deploymentID, deployments.DEPLOYED)
+	require.NoError(t, err)
+	err = consulutil.StoreConsulKeyAsString(path.Join(consulutil.TasksPrefix, taskID, "targetId"), deploymentID)
+	require.NoError(t, err)
+	events.SimpleLogEntry(ctx, events.LogLevelINFO, deploymentID).RegisterAsString("some log")
+	overlayFile := filepath.Join(workingDir, "deployments", deploymentID, "overlay", "topology.yml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(overlayFile), 0775))
+	require.NoError(t, ioutil.WriteFile(overlayFile, []byte("overlay"), 0664))
+
+	buf := new(bytes.Buffer)
+	m, err := Save(ctx, kv, buf, SaveOptions{WorkingDirectory: workingDir})
+	require.NoError(t, err)
+	require.Equal(t, consulutil.YorcSchemaVersion, m.SchemaVersion)
+	require.True(t, m.Deployments > 0)
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	// Data changed after the backup
+	err = deployments.SetDeploymentStatus(ctx, deploymentID, deployments.UNDEPLOYED)
+	require.NoError(t, err)
+	err = tasks.DeleteTask(taskID)
+	require.NoError(t, err)
+	require.NoError(t, os.RemoveAll(filepath.Join(workingDir, "deployments", deploymentID)))
+
+	_, err = Restore(ctx, kv, r, RestoreOptions{WorkingDirectory: workingDir})
+	require.Error(t, err, "restoring over existing data should require force")
+
+	var upgradedFrom string
+	_, err = Restore(ctx, kv, r, RestoreOptions{
+		WorkingDirectory: workingDir,
+		Force:            true,
+		Upgrade: func(schemaVersion string) error {
+			upgradedFrom = schemaVersion
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, consulutil.YorcSchemaVersion, upgradedFrom)
+
+	status, err := deployments.GetDeploymentStatus(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Equal(t, deployments.DEPLOYED, status)
+	nodes, err := deployments.GetNodes(ctx, deploymentID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Compute"}, nodes)
+	state, err := deployments.GetInstanceState(ctx, deploymentID, "Compute", "0")
+	require.NoError(t, err)
+	require.Equal(t, tosca.NodeStateStarted, state)
+	target, err := tasks.GetTaskTarget(taskID)
+	require.NoError(t, err)
+	require.Equal(t, deploymentID, target)
+	logs, _, err := events.LogsEvents(ctx, deploymentID, 0, 0)
+	require.NoError(t, err)
+	require.NotEmpty(t, logs)
+	b, err := ioutil.ReadFile(overlayFile)
+	require.NoError(t, err)
+	require.Equal(t, "overlay", string(b))
+}
@@ -0,0 +1,228 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/storage"
+	"github.com/ystia/yorc/v4/storage/store"
+	"github.com/ystia/yorc/v4/storage/types"
+)
+
+// RestoreOptions allows to configure a restore
+type RestoreOptions struct {
+	// WorkingDirectory is the Yorc working directory where deployments files are restored
+	WorkingDirectory string
+	// Force allows to restore a backup on a cluster already having Yorc data.
+	// These data are removed before restoring the backup.
+	Force bool
+	// Upgrade is called once data are restored to upgrade them from the backup schema version
+	// to the schema version of this Yorc version
+	Upgrade func(schemaVersion string) error
+}
+
+type content struct {
+	consul []kvPair
+	stores map[types.StoreType][]entry
+}
+
+// dataPrefixes are the Consul prefixes checked to know if a cluster already has Yorc data
+var dataPrefixes = []string{
+	consulutil.DeploymentKVPrefix,
+	consulutil.TasksPrefix,
+	consulutil.HostsPoolPrefix,
+	consulutil.LocationsPrefix,
+}
+
+// Restore restores a backup created by Save.
+//
+// Yorc servers should not be running on the cluster while restoring a backup.
+func Restore(ctx context.Context, kv *api.KV, r *zip.Reader, opts RestoreOptions) (*Manifest, error) {
+	m, err := ReadManifest(r)
+	if err != nil {
+		return nil, err
+	}
+	backupVersion, err := semver.Make(m.SchemaVersion)
+	if err != nil {
+		return nil, errors.WithStack(badBackupError{fmt.Sprintf("invalid data schema version %q in backup manifest", m.SchemaVersion)})
+	}
+	if backupVersion.GT(semver.MustParse(consulutil.YorcSchemaVersion)) {
+		return nil, errors.Errorf("this version of Yorc is too old compared to the backup data schema (%s), an upgrade is needed", m.SchemaVersion)
+	}
+	c, err := readContent(r)
+	if err != nil {
+		return nil, err
+	}
+
+	exist, err := hasData(kv)
+	if err != nil {
+		return nil, err
+	}
+	if exist {
+		if !opts.Force {
+			return nil, errors.New("Yorc data already exist on this cluster, use force to replace them by the backup")
+		}
+		err = clearData(ctx, kv)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, errGroup, consulStore := consulutil.WithContext(ctx)
+	for _, kvp := range c.consul {
+		consulStore.StoreConsulKey(kvp.Key, kvp.Value)
+	}
+	err = errGroup.Wait()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to restore Consul data")
+	}
+	for storeType, entries := range c.stores {
+		kvs := make([]store.KeyValueIn, len(entries))
+		for i, e := range entries {
+			kvs[i] = store.KeyValueIn{Key: e.Key, Value: e.Value}
+		}
+		err = storage.GetStore(storeType).SetCollection(ctx, kvs)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to restore %s store data", storeType)
+		}
+	}
+	if opts.WorkingDirectory != "" {
+		err = restoreFiles(r, opts.WorkingDirectory)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = consulutil.StoreConsulKeyAsString(consulutil.YorcSchemaVersionPath, m.SchemaVersion)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Upgrade != nil {
+		err = opts.Upgrade(m.SchemaVersion)
+	}
+	return m, err
+}
+
+func readContent(r *zip.Reader) (*content, error) {
+	c := &content{stores: make(map[types.StoreType][]entry)}
+	_, err := readJSONFile(r, consulFile, &c.consul)
+	if err != nil {
+		return nil, err
+	}
+	for _, storeTypeName := range types.StoreTypeNames() {
+		storeType, _ := types.ParseStoreType(storeTypeName)
+		var entries []entry
+		found, err := readJSONFile(r, storeFile(storeType), &entries)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			c.stores[storeType] = entries
+		}
+	}
+	return c, nil
+}
+
+func hasData(kv *api.KV) (bool, error) {
+	for _, prefix := range dataPrefixes {
+		keys, _, err := kv.Keys(prefix+"/", "/", nil)
+		if err != nil {
+			return false, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+		}
+		if len(keys) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// clearData removes Yorc data from Consul and from the stores
+func clearData(ctx context.Context, kv *api.KV) error {
+	kvps, _, err := kv.List(consulutil.YorcKVPrefix+"/", nil)
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	for _, kvp := range kvps {
+		if isExcludedKey(kvp) {
+			continue
+		}
+		_, err = kv.Delete(kvp.Key, nil)
+		if err != nil {
+			return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+		}
+	}
+	storesPrefixes := map[types.StoreType][]string{
+		types.StoreTypeDeployment: {consulutil.CommonsTypesKVPrefix, consulutil.DeploymentKVPrefix},
+		types.StoreTypeLog:        {consulutil.LogsPrefix},
+		types.StoreTypeEvent:      {consulutil.EventsPrefix},
+	}
+	for storeType, prefixes := range storesPrefixes {
+		for _, prefix := range prefixes {
+			err = storage.GetStore(storeType).Delete(ctx, prefix, true)
+			if err != nil {
+				return errors.Wrapf(err, "failed to clear %s store data", storeType)
+			}
+		}
+	}
+	return nil
+}
+
+func restoreFiles(r *zip.Reader, workingDir string) error {
+	for _, f := range r.File {
+		if !strings.HasPrefix(f.Name, filesDir) || f.FileInfo().IsDir() {
+			continue
+		}
+		filePath := filepath.Join(workingDir, filepath.FromSlash(strings.TrimPrefix(f.Name, filesDir)))
+		if !strings.HasPrefix(filePath, filepath.Clean(workingDir)+string(os.PathSeparator)) {
+			return errors.WithStack(badBackupError{fmt.Sprintf("invalid file path %q in backup", f.Name)})
+		}
+		err := extractFile(f, filePath)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractFile(f *zip.File, filePath string) error {
+	err := os.MkdirAll(filepath.Dir(filePath), 0775)
+	if err != nil {
+		return errors.Wrapf(err, "failed to extract %q from backup", f.Name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return errors.Wrapf(err, "failed to extract %q from backup", f.Name)
+	}
+	defer rc.Close()
+	target, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode()|0600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to extract %q from backup", f.Name)
+	}
+	defer target.Close()
+	_, err = io.Copy(target, rc)
+	return errors.Wrapf(err, "failed to extract %q from backup", f.Name)
+}
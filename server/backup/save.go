@@ -0,0 +1,266 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backup
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/helper/ziputil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/server/info"
+	"github.com/ystia/yorc/v4/storage"
+	"github.com/ystia/yorc/v4/storage/types"
+)
+
+// DefaultMaxAttempts is the default number of attempts to take a consistent snapshot
+const DefaultMaxAttempts = 5
+
+// SaveOptions allows to configure a backup
+type SaveOptions struct {
+	// WorkingDirectory is the Yorc working directory where deployments files are stored
+	WorkingDirectory string
+	// MaxAttempts is the number of attempts to take a consistent snapshot of Yorc data
+	// while the cluster keeps changing them. Defaults to DefaultMaxAttempts.
+	MaxAttempts int
+}
+
+type snapshot struct {
+	index       uint64
+	deployments []string
+	consul      []kvPair
+	stores      map[types.StoreType][]entry
+}
+
+// Save writes a backup of all the Yorc data into w.
+//
+// Consul keys are read at once so they are consistent, stores are then read and the snapshot
+// is taken again if Yorc data were modified in Consul meanwhile.
+func Save(ctx context.Context, kv *api.KV, w io.Writer, opts SaveOptions) (*Manifest, error) {
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	var snap *snapshot
+	var schemaVersion string
+	for attempt := 1; snap == nil; attempt++ {
+		if attempt > maxAttempts {
+			return nil, errors.Errorf("failed to take a consistent snapshot of Yorc data after %d attempts as they keep changing, retry when the cluster is less busy", maxAttempts)
+		}
+		var err error
+		snap, schemaVersion, err = takeSnapshot(ctx, kv)
+		if err != nil {
+			return nil, err
+		}
+		if snap == nil {
+			log.Debugf("Yorc data changed while taking a backup snapshot (attempt %d/%d)", attempt, maxAttempts)
+		}
+	}
+
+	m := &Manifest{
+		FormatVersion: FormatVersion,
+		SchemaVersion: schemaVersion,
+		YorcVersion:   info.YorcVersion,
+		BackupDate:    time.Now(),
+		ConsulIndex:   snap.index,
+		Deployments:   len(snap.deployments),
+	}
+
+	zw := zip.NewWriter(w)
+	err := writeJSONFile(zw, manifestFile, m)
+	if err != nil {
+		return nil, err
+	}
+	err = writeJSONFile(zw, consulFile, snap.consul)
+	if err != nil {
+		return nil, err
+	}
+	for storeType, entries := range snap.stores {
+		err = writeJSONFile(zw, storeFile(storeType), entries)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opts.WorkingDirectory != "" {
+		for _, deploymentID := range snap.deployments {
+			err = saveDeploymentFiles(zw, opts.WorkingDirectory, deploymentID)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return m, errors.Wrap(zw.Close(), "failed to write backup")
+}
+
+// takeSnapshot returns a nil snapshot if Yorc data changed in Consul while reading the stores
+func takeSnapshot(ctx context.Context, kv *api.KV) (*snapshot, string, error) {
+	kvps, qm, err := kv.List(consulutil.YorcKVPrefix+"/", &api.QueryOptions{RequireConsistent: true})
+	if err != nil {
+		return nil, "", errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	var schemaVersion string
+	for _, kvp := range kvps {
+		if kvp.Key == consulutil.YorcSchemaVersionPath {
+			schemaVersion = string(kvp.Value)
+		}
+	}
+	if schemaVersion == "" {
+		return nil, "", errors.Errorf("no Yorc data schema version found in Consul under %q", consulutil.YorcSchemaVersionPath)
+	}
+
+	snap := &snapshot{
+		index:       qm.LastIndex,
+		deployments: deploymentsIDs(kvps),
+		stores:      make(map[types.StoreType][]entry),
+	}
+	snap.stores[types.StoreTypeDeployment], err = saveDeploymentStore(snap.deployments)
+	if err != nil {
+		return nil, "", err
+	}
+	snap.stores[types.StoreTypeLog], err = saveStoreEntries(ctx, types.StoreTypeLog, consulutil.LogsPrefix)
+	if err != nil {
+		return nil, "", err
+	}
+	snap.stores[types.StoreTypeEvent], err = saveStoreEntries(ctx, types.StoreTypeEvent, consulutil.EventsPrefix)
+	if err != nil {
+		return nil, "", err
+	}
+
+	_, qm, err = kv.Keys(consulutil.YorcKVPrefix+"/", "/", &api.QueryOptions{RequireConsistent: true})
+	if err != nil {
+		return nil, "", errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if qm.LastIndex != snap.index {
+		return nil, schemaVersion, nil
+	}
+
+	// Keys held by stores using Consul are saved with their store
+	storeKeys := make(map[string]struct{})
+	for _, entries := range snap.stores {
+		for _, e := range entries {
+			storeKeys[e.Key] = struct{}{}
+		}
+	}
+	snap.consul = make([]kvPair, 0, len(kvps))
+	for _, kvp := range kvps {
+		if _, ok := storeKeys[kvp.Key]; ok || isExcludedKey(kvp) {
+			continue
+		}
+		snap.consul = append(snap.consul, kvPair{Key: kvp.Key, Value: kvp.Value})
+	}
+	return snap, schemaVersion, nil
+}
+
+func saveDeploymentStore(deploymentsIDs []string) ([]entry, error) {
+	s := storage.GetStore(types.StoreTypeDeployment)
+	result := make([]entry, 0)
+
+	var walk func(key string, skipped []string) error
+	walk = func(key string, skipped []string) error {
+		for _, k := range skipped {
+			if key == k {
+				return nil
+			}
+		}
+		var value json.RawMessage
+		exist, err := s.Get(key, &value)
+		if err != nil {
+			return errors.Wrapf(err, "failed to backup store key %q", key)
+		}
+		if exist {
+			result = append(result, entry{Key: key, Value: value})
+		}
+		keys, err := s.Keys(key)
+		if err != nil {
+			return errors.Wrapf(err, "failed to backup store key %q", key)
+		}
+		for _, k := range keys {
+			err = walk(k, skipped)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	err := walk(consulutil.CommonsTypesKVPrefix, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, deploymentID := range deploymentsIDs {
+		prefix := deploymentPrefix(deploymentID)
+		skipped := make([]string, len(deployments.RuntimeTopologyKeys))
+		for i, k := range deployments.RuntimeTopologyKeys {
+			skipped[i] = path.Join(prefix, k)
+		}
+		for _, root := range deployments.DefinitionStoreRoots {
+			err = walk(path.Join(prefix, root), skipped)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
+func saveStoreEntries(ctx context.Context, storeType types.StoreType, prefix string) ([]entry, error) {
+	kvs, _, err := storage.GetStore(storeType).List(ctx, prefix, 0, 0)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to backup %s store", storeType)
+	}
+	result := make([]entry, 0, len(kvs))
+	for _, kv := range kvs {
+		result = append(result, entry{Key: kv.Key, Value: kv.RawValue})
+	}
+	return result, nil
+}
+
+func saveDeploymentFiles(zw *zip.Writer, workingDir, deploymentID string) error {
+	deploymentDir := filepath.Join(workingDir, "deployments", deploymentID)
+	name := path.Join(filesDir, "deployments", deploymentID)
+	err := ziputil.AddFile(zw, filepath.Join(deploymentDir, csarFile), path.Join(name, csarFile))
+	if err != nil && !os.IsNotExist(errors.Cause(err)) {
+		return err
+	}
+	overlayPath := filepath.Join(deploymentDir, overlayDir)
+	err = filepath.Walk(overlayPath, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(deploymentDir, p)
+		if err != nil {
+			return err
+		}
+		return ziputil.AddFile(zw, p, path.Join(name, filepath.ToSlash(rel)))
+	})
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return errors.Wrapf(err, "failed to backup files of deployment %q", deploymentID)
+}
@@ -0,0 +1,134 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/server/backup"
+	"github.com/ystia/yorc/v4/storage"
+)
+
+// Backup saves a consistent snapshot of all the Yorc data of the cluster into the given file
+func Backup(cfg config.Configuration, filePath string) error {
+	client, pm, err := initOfflineEnv(cfg)
+	if err != nil {
+		return err
+	}
+	defer pm.cleanup()
+
+	f, err := os.Create(filePath)
+	if err != nil {
+		return errors.Wrap(err, "failed to create backup file")
+	}
+	defer f.Close()
+	m, err := backup.Save(context.Background(), client.KV(), f, backup.SaveOptions{WorkingDirectory: cfg.WorkingDirectory})
+	if err != nil {
+		os.Remove(filePath)
+		return err
+	}
+	log.Printf("Backup of %d deployments with data schema version %q saved into %q", m.Deployments, m.SchemaVersion, filePath)
+	return nil
+}
+
+// Restore restores a backup created by Backup.
+//
+// Data of an older backup are upgraded to the data schema version of this Yorc version.
+// Yorc servers of the cluster should be stopped before restoring a backup.
+func Restore(cfg config.Configuration, filePath string, force bool) error {
+	r, err := zip.OpenReader(filePath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open backup file %q", filePath)
+	}
+	defer r.Close()
+
+	client, pm, err := initOfflineEnv(cfg)
+	if err != nil {
+		return err
+	}
+	defer pm.cleanup()
+
+	servers, _, err := client.Health().Service(consulutil.YorcService, "", true, nil)
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if len(servers) > 0 {
+		return errors.Errorf("%d Yorc servers are running on this cluster, they should be stopped before restoring a backup", len(servers))
+	}
+
+	lock, leaderCh, err := synchronizeDBUpdate(client)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		lock.Unlock()
+		lock.Destroy()
+	}()
+
+	opts := backup.RestoreOptions{
+		WorkingDirectory: cfg.WorkingDirectory,
+		Force:            force,
+		Upgrade: func(schemaVersion string) error {
+			err := registerBuiltinTOSCATypes()
+			if err != nil {
+				return err
+			}
+			return upgradeFromVersion(cfg, client, leaderCh, schemaVersion)
+		},
+	}
+	m, err := backup.Restore(context.Background(), client.KV(), &r.Reader, opts)
+	if err != nil {
+		return err
+	}
+	log.Printf("Backup of %d deployments taken on %s restored from data schema version %q", m.Deployments, m.BackupDate, m.SchemaVersion)
+	return nil
+}
+
+// initOfflineEnv connects to Consul and loads the stores and the plugins that may provide them
+// for commands working on Yorc data without running a server
+func initOfflineEnv(cfg config.Configuration) (*api.Client, *pluginManager, error) {
+	client, err := cfg.GetConsulClient()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Can't connect to Consul")
+	}
+	maxConsulPubRoutines := cfg.Consul.PubMaxRoutines
+	if maxConsulPubRoutines <= 0 {
+		maxConsulPubRoutines = config.DefaultConsulPubMaxRoutines
+	}
+	consulutil.InitConsulPublisher(maxConsulPubRoutines, client.KV())
+
+	// Plugins are loaded before stores as they may provide stores implementations
+	pm := newPluginManager()
+	err = pm.loadPlugins(cfg)
+	if err != nil {
+		pm.cleanup()
+		return nil, nil, err
+	}
+	err = storage.LoadStores(cfg)
+	if err != nil {
+		pm.cleanup()
+		return nil, nil, err
+	}
+	pm.storesLoaded()
+	return client, pm, nil
+}
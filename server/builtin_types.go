@@ -17,9 +17,23 @@
 package server
 
 import (
+	"github.com/ystia/yorc/v4/registry"
 	"github.com/ystia/yorc/v4/resources"
 )
 
 func registerBuiltinTOSCATypes() error {
-	return resources.StoreBuiltinTOSCAResources()
+	err := resources.StoreBuiltinTOSCAResources()
+	if err != nil {
+		return err
+	}
+	// Also register them into the registry to make them available to the CSAR validation
+	definitions, err := resources.GetBuiltinTOSCADefinitions()
+	if err != nil {
+		return err
+	}
+	reg := registry.GetRegistry()
+	for defName, defContent := range definitions {
+		reg.RegisterToscaDefinition(defName, defContent, registry.BuiltinOrigin)
+	}
+	return nil
 }
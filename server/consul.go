@@ -15,6 +15,7 @@
 package server
 
 import (
+	"context"
 	"io"
 	"os"
 	"strconv"
@@ -30,25 +31,12 @@ import (
 	"github.com/ystia/yorc/v4/server/upgradeschema"
 )
 
-var upgradeToMap = map[string]func(config.Configuration, *api.KV, <-chan struct{}) error{
-	"1.0.0": upgradeschema.UpgradeFromPre31,
-	"1.1.0": upgradeschema.UpgradeTo110,
-	"1.1.1": upgradeschema.UpgradeTo111,
-	"1.1.2": upgradeschema.UpgradeTo112,
-	"1.2.0": upgradeschema.UpgradeTo120,
-	"1.3.0": upgradeschema.UpgradeTo130,
-	"1.3.1": upgradeschema.UpgradeTo131,
-}
+// schemaMigrations are the data schema migrations to apply, defaults to the registered ones
+var schemaMigrations []upgradeschema.Migration
 
-var orderedUpgradesVersions []semver.Version
 var disableConsulSnapshotsOnUpgrades bool
 
 func init() {
-	for v := range upgradeToMap {
-		orderedUpgradesVersions = append(orderedUpgradesVersions, semver.MustParse(v))
-	}
-	semver.Sort(orderedUpgradesVersions)
-
 	var err error
 	disableConsulSnapEnv := os.Getenv("YORC_DISABLE_CONSUL_SNAPSHOTS_ON_UPGRADE")
 	if disableConsulSnapEnv != "" {
@@ -164,22 +152,20 @@ func performUpgrade(cfg config.Configuration, client *api.Client, leaderCh <-cha
 		}
 		defer snapReader.Close()
 	}
-	for _, vUp := range orderedUpgradesVersions {
-		if vUp.GT(vCurrent) {
-			err := upgradeToMap[vUp.String()](cfg, client.KV(), leaderCh)
-			if err != nil {
-				if !disableConsulSnapshotsOnUpgrades {
-					// Restore Consul snapshot
-					restoreErr := snap.Restore(nil, snapReader)
-					if restoreErr != nil {
-						log.Printf("failed to restore consul db schema to %q due to error:%+v", vCurrent, restoreErr)
-					} else {
-						log.Printf("As any error occurred, schema has been successfully restored to version %q", vCurrent)
-					}
-				}
-				return errors.Wrapf(err, "failed to upgrade consul db schema to %q.", vUp)
+	report, err := upgradeschema.Migrate(context.Background(), cfg, client.KV(), leaderCh, vCurrent.String(), upgradeschema.Options{Migrations: schemaMigrations})
+	if err != nil {
+		if report != nil && report.RolledBack {
+			log.Printf("As an error occurred, data schema has been successfully reverted to version %q", vCurrent)
+		} else if !disableConsulSnapshotsOnUpgrades {
+			// Restore Consul snapshot
+			restoreErr := snap.Restore(nil, snapReader)
+			if restoreErr != nil {
+				log.Printf("failed to restore consul db schema to %q due to error:%+v", vCurrent, restoreErr)
+			} else {
+				log.Printf("As any error occurred, schema has been successfully restored to version %q", vCurrent)
 			}
 		}
+		return err
 	}
 	return nil
 }
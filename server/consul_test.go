@@ -15,6 +15,7 @@
 package server
 
 import (
+	"context"
 	"github.com/ystia/yorc/v4/config"
 	"os"
 	"testing"
@@ -26,6 +27,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/server/upgradeschema"
 	"github.com/ystia/yorc/v4/testutil"
 )
 
@@ -110,11 +112,18 @@ func testSetupVersion(t *testing.T, client *api.Client, cfg config.Configuration
 	// Check error and auto-rollback
 	currentConsulVersion := semver.MustParse(consulutil.YorcSchemaVersion)
 	currentConsulVersion.Minor--
-	upgradeToMap[consulutil.YorcSchemaVersion] = func(cfg config.Configuration, kv *api.KV, lc <-chan struct{}) error {
-		// Delete something
-		kv.Delete(initialDataKP.Key, nil)
-		return errors.New("This is an expected error")
-	}
+	migrations := upgradeschema.Migrations()
+	schemaMigrations = append(migrations[:len(migrations)-1], upgradeschema.Migration{
+		Version: consulutil.YorcSchemaVersion,
+		Legacy: func(cfg config.Configuration, kv *api.KV, lc <-chan struct{}) error {
+			// Delete something
+			kv.Delete(initialDataKP.Key, nil)
+			return errors.New("This is an expected error")
+		},
+	})
+	defer func() {
+		schemaMigrations = nil
+	}()
 	setSchemaVersion(t, kv, currentConsulVersion.String())
 	err = setupConsulDBSchema(cfg, client)
 	// An error is expected
@@ -137,6 +146,27 @@ func testSetupVersion(t *testing.T, client *api.Client, cfg config.Configuration
 	assert.NoError(t, err)
 	assert.Nil(t, kvp)
 
+	// Check that changes of a migration using a session are reverted even without snapshots
+	_, err = kv.Put(initialDataKP, nil)
+	require.NoError(t, err)
+	schemaMigrations[len(schemaMigrations)-1] = upgradeschema.Migration{
+		Version: consulutil.YorcSchemaVersion,
+		Up: func(ctx context.Context, s *upgradeschema.Session) error {
+			err := s.Consul().Delete(ctx, initialDataKP.Key)
+			require.NoError(t, err)
+			return errors.New("This is an expected error")
+		},
+	}
+	setSchemaVersion(t, kv, "1.3.0")
+	err = setupConsulDBSchema(cfg, client)
+	assert.Error(t, err)
+	checkSchemaVersion(t, kv, "1.3.0")
+	kvp, _, err = kv.Get(initialDataKP.Key, nil)
+	assert.NoError(t, err)
+	require.NotNil(t, kvp)
+	assert.Equal(t, initialDataKP.Value, kvp.Value)
+	disableConsulSnapshotsOnUpgrades = false
+
 	// Back to current version for next tests
 	setSchemaVersion(t, kv, "1.3.0")
 }
@@ -28,20 +28,31 @@ import (
 	_ "github.com/ystia/yorc/v4/prov/kubernetes"
 	// Registering slurm delegate executor in the registry
 	_ "github.com/ystia/yorc/v4/prov/slurm"
+	// Registering PBS delegate executor in the registry
+	_ "github.com/ystia/yorc/v4/prov/pbs"
+	// Registering container operation executor in the registry
+	_ "github.com/ystia/yorc/v4/prov/container"
 	// Registering hosts pool delegate executor in the registry
 	_ "github.com/ystia/yorc/v4/prov/hostspool"
 	// Registering builtin Tosca definition files
 	_ "github.com/ystia/yorc/v4/tosca"
 	// Registering builtin HashiCorp Vault Client Builder
 	_ "github.com/ystia/yorc/v4/vault/hashivault"
+	// Registering builtin encrypted file, environment and SOPS Vault Client Builders
+	_ "github.com/ystia/yorc/v4/vault/envvault"
+	_ "github.com/ystia/yorc/v4/vault/filevault"
+	_ "github.com/ystia/yorc/v4/vault/sopsvault"
 	// Registering builtin activity hooks
 	_ "github.com/ystia/yorc/v4/prov/validation"
 )
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/ystia/yorc/v4/deployments/store"
 
@@ -54,22 +65,109 @@ import (
 	"github.com/ystia/yorc/v4/registry"
 )
 
+const (
+	// minPluginRestartBackoff is the initial delay before restarting a crashed plugin
+	minPluginRestartBackoff = 5 * time.Second
+	// maxPluginRestartBackoff is the maximum delay before restarting a crashed plugin,
+	// a plugin running for a longer time is considered as stable and its backoff is reset
+	maxPluginRestartBackoff = 5 * time.Minute
+	// pluginPingTimeout is the maximum delay for a plugin to respond to a health check
+	pluginPingTimeout = 30 * time.Second
+)
+
+// managedPlugin is a plugin supervised by the pluginManager
+type managedPlugin struct {
+	id          string
+	path        string
+	modTime     time.Time
+	size        int64
+	client      *gplugin.Client
+	rpcClient   gplugin.ClientProtocol
+	status      registry.PluginStatus
+	backoff     time.Duration
+	nextRestart time.Time
+	// definitions are the TOSCA definitions provided by the plugin
+	definitions map[string][]byte
+	// starting is true while the plugin process is started without holding the manager lock
+	starting bool
+	// handshaked is true once the current plugin binary completed a handshake with Yorc
+	handshaked bool
+}
+
+// pluginInstance is a plugin process started by the pluginManager
+type pluginInstance struct {
+	client      *gplugin.Client
+	rpcClient   gplugin.ClientProtocol
+	definitions map[string][]byte
+}
+
+func (inst *pluginInstance) kill() {
+	if inst != nil && inst.client != nil {
+		inst.client.Kill()
+	}
+}
+
+// handshakeError is returned when an executable fails to handshake with Yorc, typically because it is not a Yorc plugin
+type handshakeError struct {
+	cause error
+}
+
+func (e handshakeError) Error() string {
+	return fmt.Sprintf("plugin handshake failed: %v", e.cause)
+}
+
+func isHandshakeError(err error) bool {
+	_, ok := errors.Cause(err).(handshakeError)
+	return ok
+}
+
+// pluginManager loads plugins and supervises them.
+//
+// It periodically checks plugins health, restarts crashed ones with an exponential backoff
+// and loads new or updated plugins binaries from the plugins directory.
+//
+// Plugins are loaded before stores as they may provide stores implementations, TOSCA definitions
+// of plugins are stored in the deployments store once stores are loaded (see storesLoaded).
+//
+// The lock protects plugins entries, plugins processes are started and health checked without
+// holding it so that a hung plugin doesn't block other plugins, the registry or the shutdown.
 type pluginManager struct {
-	pluginClients []*gplugin.Client
+	lock        sync.Mutex
+	cfg         config.Configuration
+	pluginsPath string
+	plugins     map[string]*managedPlugin
+	reg         registry.Registry
+	stopCh      chan struct{}
+	stopOnce    sync.Once
+	storesReady bool
 }
 
 func newPluginManager() *pluginManager {
 	pm := &pluginManager{
-		pluginClients: make([]*gplugin.Client, 0),
+		plugins: make(map[string]*managedPlugin),
+		reg:     registry.GetRegistry(),
+		stopCh:  make(chan struct{}),
 	}
 	return pm
 }
 
 func (pm *pluginManager) cleanup() {
-	for _, client := range pm.pluginClients {
-		client.Kill()
+	pm.stopOnce.Do(func() { close(pm.stopCh) })
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+	for _, mp := range pm.plugins {
+		pm.stopPlugin(mp)
+	}
+	pm.plugins = make(map[string]*managedPlugin)
+}
+
+func (pm *pluginManager) stopped() bool {
+	select {
+	case <-pm.stopCh:
+		return true
+	default:
+		return false
 	}
-	pm.pluginClients = nil
 }
 
 func (pm *pluginManager) loadPlugins(cfg config.Configuration) error {
@@ -81,152 +179,421 @@ func (pm *pluginManager) loadPlugins(cfg config.Configuration) error {
 	if err != nil {
 		return errors.Wrap(err, "Failed to explore plugins directory")
 	}
-	pluginsFiles, err := filepath.Glob(filepath.Join(pluginPath, "*"))
+	pm.cfg = cfg
+	pm.pluginsPath = pluginPath
+
+	err = pm.scanPlugins()
+	if err != nil {
+		return err
+	}
+
+	interval := cfg.PluginsHealthCheckInterval
+	if interval <= 0 {
+		interval = config.DefaultPluginsHealthCheckInterval
+	}
+	go pm.supervise(interval)
+	return nil
+}
+
+// supervise periodically checks plugins health and plugins directory changes until cleanup is called
+func (pm *pluginManager) supervise(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-pm.stopCh:
+			return
+		case <-ticker.C:
+			err := pm.scanPlugins()
+			if err != nil {
+				log.Printf("[Warning] %v", err)
+			}
+			pm.checkPlugins()
+		}
+	}
+}
+
+// scanPlugins loads new plugins, reloads updated ones and stops removed ones
+func (pm *pluginManager) scanPlugins() error {
+	pluginsFiles, err := filepath.Glob(filepath.Join(pm.pluginsPath, "*"))
 	if err != nil {
 		return errors.Wrap(err, "Failed to explore plugins directory")
 	}
-	plugins := make([]string, 0)
+	found := make(map[string]os.FileInfo)
 	for _, pFile := range pluginsFiles {
 		fInfo, err := os.Stat(pFile)
 		if err != nil {
 			return errors.Wrap(err, "Failed to explore plugins directory")
 		}
 		if !fInfo.IsDir() && fInfo.Mode().Perm()&0111 != 0 {
-			plugins = append(plugins, pFile)
+			found[pFile] = fInfo
 		}
 	}
-	reg := registry.GetRegistry()
-	ctx := context.Background()
-	for _, pFile := range plugins {
-		log.Debugf("Loading plugin %q...", pFile)
-		// OK the idea here is to _try_ to load the plugin if we can't we give up with this plugin and try the others
-		// There is no reason to stop the server loading if we can't load a plugin.
-		pluginID := filepath.Base(pFile)
-		client := plugin.NewClient(pFile)
-		// Connect via RPC
-		rpcClient, err := client.Client()
-		if err != nil {
-			log.Printf("[Warning] Failed to load %q as a plugin: %v. Skipping it and continue loading plugins.", pFile, err)
-			log.Debugf("Error details: %+v", err)
+
+	pm.lock.Lock()
+	if pm.stopped() {
+		// Cleanup already done
+		pm.lock.Unlock()
+		return nil
+	}
+
+	var toStart []*managedPlugin
+	for pluginID, mp := range pm.plugins {
+		fInfo, ok := found[mp.path]
+		if !ok {
+			log.Printf("Plugin %q was removed from plugins directory, stopping it.", pluginID)
+			pm.stopPlugin(mp)
+			pm.reg.RemovePluginStatus(pluginID)
+			delete(pm.plugins, pluginID)
 			continue
 		}
-
-		// Request the configManager plugin
-		raw, err := rpcClient.Dispense(plugin.ConfigManagerPluginName)
-		if err != nil {
-			log.Printf("[Warning] Failed to load %q as a plugin: %v. Skipping it and continue loading plugins.", pFile, err)
-			log.Debugf("Error details: %+v", err)
-			client.Kill()
+		if mp.starting {
+			// Updates are checked again at next scan
 			continue
 		}
-		cfgManager := raw.(plugin.ConfigManager)
-		err = cfgManager.SetupConfig(cfg)
-		if err != nil {
-			log.Printf("[Warning] Failed to load %q as a plugin: %v. Skipping it and continue loading plugins.", pFile, err)
-			log.Debugf("Error details: %+v", err)
-			client.Kill()
+		if !fInfo.ModTime().Equal(mp.modTime) || fInfo.Size() != mp.size {
+			log.Printf("Plugin %q was updated, reloading it.", pluginID)
+			pm.stopPlugin(mp)
+			mp.modTime = fInfo.ModTime()
+			mp.size = fInfo.Size()
+			mp.backoff = 0
+			mp.handshaked = false
+			mp.status.Restarts = 0
+			mp.starting = true
+			toStart = append(toStart, mp)
+		}
+	}
+
+	for pFile, fInfo := range found {
+		pluginID := filepath.Base(pFile)
+		if _, ok := pm.plugins[pluginID]; ok {
 			continue
 		}
+		mp := &managedPlugin{
+			id:       pluginID,
+			path:     pFile,
+			modTime:  fInfo.ModTime(),
+			size:     fInfo.Size(),
+			status:   registry.PluginStatus{Name: pluginID, Path: pFile},
+			starting: true,
+		}
+		pm.plugins[pluginID] = mp
+		toStart = append(toStart, mp)
+	}
+	pm.lock.Unlock()
 
-		// Request the delegate plugin
-		raw, err = rpcClient.Dispense(plugin.DelegatePluginName)
-		if err == nil {
-			delegateExecutor := raw.(plugin.DelegateExecutor)
-			supportedTypes, err := delegateExecutor.GetSupportedTypes()
-			if err != nil {
-				log.Printf("[Warning] Failed to retrieve delegate executor supported type for plugin %q.", pluginID)
-				log.Debugf("%+v", err)
-			}
-			if len(supportedTypes) > 0 {
-				log.Debugf("Registering supported node types %v into registry for plugin %q", supportedTypes, pluginID)
-				reg.RegisterDelegates(supportedTypes, delegateExecutor, pluginID)
+	for _, mp := range toStart {
+		pm.startPlugin(mp)
+	}
+	return nil
+}
+
+// checkPlugins checks running plugins health and restarts crashed plugins when their backoff delay expired
+func (pm *pluginManager) checkPlugins() {
+	type runningPlugin struct {
+		mp        *managedPlugin
+		client    *gplugin.Client
+		rpcClient gplugin.ClientProtocol
+	}
+	var running []runningPlugin
+	var toStart []*managedPlugin
+	pm.lock.Lock()
+	for pluginID, mp := range pm.plugins {
+		if mp.starting {
+			continue
+		}
+		switch mp.status.State {
+		case registry.PluginStateRunning:
+			running = append(running, runningPlugin{mp: mp, client: mp.client, rpcClient: mp.rpcClient})
+		case registry.PluginStateRestarting:
+			if time.Now().After(mp.nextRestart) {
+				log.Printf("Restarting plugin %q.", pluginID)
+				mp.status.Restarts++
+				mp.starting = true
+				toStart = append(toStart, mp)
 			}
-		} else {
-			log.Printf("[Warning] Can't retrieve delegate executor from plugin %q: %v. This is likely due to a outdated plugin.", pluginID, err)
-			log.Debugf("%+v", err)
 		}
+	}
+	pm.lock.Unlock()
 
-		// Request the operation plugin
-		raw, err = rpcClient.Dispense(plugin.OperationPluginName)
-		if err == nil {
-			operationExecutor := raw.(plugin.OperationExecutor)
-			supportedArtTypes, err := operationExecutor.GetSupportedArtifactTypes()
-			if err != nil {
-				log.Printf("[Warning] Failed to retrieve operation executor supported implementation artifacts for plugin %q.", pluginID)
-				log.Debugf("%+v", err)
-			}
-			if len(supportedArtTypes) > 0 {
-				log.Debugf("Registering supported implementation artifact types %v into registry for plugin %q", supportedArtTypes, pluginID)
-				reg.RegisterOperationExecutor(supportedArtTypes, operationExecutor, pluginID)
-			}
+	for _, rp := range running {
+		var err error
+		if rp.client.Exited() {
+			err = errors.New("plugin process exited")
 		} else {
-			log.Printf("[Warning] Can't retrieve operation executor from plugin %q: %v. This is likely due to a outdated plugin.", pluginID, err)
-			log.Debugf("%+v", err)
+			err = pingPlugin(rp.rpcClient)
 		}
-
-		// Request the action plugin
-		raw, err = rpcClient.Dispense(plugin.ActionPluginName)
-		if err == nil {
-			actionOperator := raw.(plugin.ActionOperator)
-			actionTypes, err := actionOperator.GetActionTypes()
+		pm.lock.Lock()
+		// Skip plugins stopped, reloaded or removed while checking their health
+		if pm.plugins[rp.mp.id] == rp.mp && rp.mp.client == rp.client {
 			if err != nil {
-				log.Printf("[Warning] Failed to retrieve action types for plugin %q.", pluginID)
-				log.Debugf("%+v", err)
-			}
-			if len(actionTypes) > 0 {
-				log.Debugf("Registering action types %v into registry for plugin %q", actionTypes, pluginID)
-				reg.RegisterActionOperator(actionTypes, actionOperator, pluginID)
+				log.Printf("[Warning] Plugin %q is not healthy: %v. It will be restarted.", rp.mp.id, err)
+				pm.stopPlugin(rp.mp)
+				pm.scheduleRestart(rp.mp, err)
+			} else if time.Since(rp.mp.status.StartedAt) > maxPluginRestartBackoff {
+				rp.mp.backoff = 0
 			}
-		} else {
-			log.Printf("[Warning] Can't retrieve action operator from plugin %q: %v. This is likely due to a outdated plugin.", pluginID, err)
-			log.Debugf("%+v", err)
 		}
+		pm.lock.Unlock()
+	}
 
-		// Request the definitions plugin
-		raw, err = rpcClient.Dispense(plugin.DefinitionsPluginName)
-		if err == nil {
-			definitionPlugin := raw.(plugin.Definitions)
-			definitions, err := definitionPlugin.GetDefinitions()
-			if err != nil {
-				log.Printf("[Warning] Failed to retrieve TOSCA definitions for plugin %q.", pluginID)
-				log.Debugf("%+v", err)
-			}
-			if len(definitions) > 0 {
-				for defName, defContent := range definitions {
-					log.Debugf("Registering TOSCA definition %q into registry for plugin %q", defName, pluginID)
-					store.CommonDefinition(ctx, defName, pluginID, defContent)
-				}
-			}
-		} else {
-			log.Printf("[Warning] Can't retrieve TOSCA definitions from plugin %q: %v. This is likely due to a outdated plugin.", pluginID, err)
+	for _, mp := range toStart {
+		pm.startPlugin(mp)
+	}
+}
+
+// pingPlugin checks that a plugin responds in a timely manner
+func pingPlugin(rpcClient gplugin.ClientProtocol) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- rpcClient.Ping()
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-time.After(pluginPingTimeout):
+		return errors.Errorf("plugin did not respond to health check within %v", pluginPingTimeout)
+	}
+}
+
+func (pm *pluginManager) scheduleRestart(mp *managedPlugin, err error) {
+	if mp.backoff == 0 {
+		mp.backoff = minPluginRestartBackoff
+	} else {
+		mp.backoff *= 2
+		if mp.backoff > maxPluginRestartBackoff {
+			mp.backoff = maxPluginRestartBackoff
+		}
+	}
+	mp.nextRestart = time.Now().Add(mp.backoff)
+	mp.status.State = registry.PluginStateRestarting
+	mp.status.LastError = err.Error()
+	pm.reg.SetPluginStatus(mp.status)
+}
+
+// stopPlugin kills the plugin process and removes its registrations
+func (pm *pluginManager) stopPlugin(mp *managedPlugin) {
+	if mp.client != nil {
+		mp.client.Kill()
+		mp.client = nil
+		mp.rpcClient = nil
+	}
+	pm.reg.UnregisterOrigin(mp.id)
+	mp.status.State = registry.PluginStateStopped
+	pm.reg.SetPluginStatus(mp.status)
+}
+
+// startPlugin starts a plugin and registers its components.
+//
+// The idea here is to _try_ to load the plugin if we can't we schedule a restart of this plugin later.
+// There is no reason to stop the server loading if we can't load a plugin.
+// Executables failing the handshake are rejected until they are updated.
+//
+// The plugin process is started without holding the manager lock, callers should mark the plugin as starting.
+func (pm *pluginManager) startPlugin(mp *managedPlugin) {
+	log.Debugf("Loading plugin %q...", mp.path)
+	inst, err := pm.loadPlugin(mp.id, mp.path)
+
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+	mp.starting = false
+	if pm.stopped() || pm.plugins[mp.id] != mp {
+		// Plugin removed or manager cleaned up meanwhile
+		inst.kill()
+		if _, ok := pm.plugins[mp.id]; !ok {
+			pm.reg.UnregisterOrigin(mp.id)
+		}
+		return
+	}
+	if err != nil {
+		inst.kill()
+		pm.reg.UnregisterOrigin(mp.id)
+		if isHandshakeError(err) && !mp.handshaked {
+			log.Printf("[Warning] %q is not a valid Yorc plugin: %v. It will be ignored until it is updated.", mp.path, err)
+			mp.status.State = registry.PluginStateRejected
+			mp.status.LastError = err.Error()
+			pm.reg.SetPluginStatus(mp.status)
+			return
+		}
+		log.Printf("[Warning] Failed to load %q as a plugin: %v. It will be retried later.", mp.path, err)
+		log.Debugf("Error details: %+v", err)
+		pm.scheduleRestart(mp, err)
+		return
+	}
+	mp.handshaked = true
+	mp.client = inst.client
+	mp.rpcClient = inst.rpcClient
+	mp.definitions = inst.definitions
+	mp.status.State = registry.PluginStateRunning
+	mp.status.StartedAt = time.Now()
+	mp.status.ProtocolVersion = mp.client.NegotiatedVersion()
+	mp.status.LastError = ""
+	pm.reg.SetPluginStatus(mp.status)
+	if pm.storesReady {
+		pm.storeDefinitions(mp)
+	}
+	log.Printf("Plugin %q successfully loaded", mp.id)
+}
+
+// storesLoaded should be called once stores are loaded, it stores TOSCA definitions of running plugins
+// and allows to store definitions of plugins loaded afterward.
+func (pm *pluginManager) storesLoaded() {
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+	pm.storesReady = true
+	for _, mp := range pm.plugins {
+		if mp.status.State == registry.PluginStateRunning {
+			pm.storeDefinitions(mp)
+		}
+	}
+}
+
+// storeDefinitions stores TOSCA definitions of a plugin in the deployments store
+func (pm *pluginManager) storeDefinitions(mp *managedPlugin) {
+	ctx := context.Background()
+	for defName, defContent := range mp.definitions {
+		err := store.CommonDefinition(ctx, defName, mp.id, defContent)
+		if err != nil {
+			log.Printf("[Warning] Failed to store TOSCA definition %q of plugin %q: %v", defName, mp.id, err)
+		}
+	}
+}
+
+// loadPlugin starts a plugin process and registers its components into the registry
+//
+// The returned instance should be killed on error.
+func (pm *pluginManager) loadPlugin(pluginID, pluginPath string) (*pluginInstance, error) {
+	reg := pm.reg
+	inst := &pluginInstance{client: plugin.NewClient(pluginPath)}
+	// Connect via RPC
+	rpcClient, err := inst.client.Client()
+	if err != nil {
+		return inst, handshakeError{cause: err}
+	}
+	inst.rpcClient = rpcClient
+	log.Debugf("Plugin %q uses protocol version %d", pluginID, inst.client.NegotiatedVersion())
+
+	// Request the configManager plugin
+	raw, err := rpcClient.Dispense(plugin.ConfigManagerPluginName)
+	if err != nil {
+		return inst, err
+	}
+	cfgManager := raw.(plugin.ConfigManager)
+	err = cfgManager.SetupConfig(pm.cfg)
+	if err != nil {
+		return inst, err
+	}
+
+	// Request the store plugin
+	// Done first as other plugins features may rely on stores
+	raw, err = rpcClient.Dispense(plugin.StorePluginName)
+	if err == nil {
+		storeBuilder := raw.(plugin.StoreBuilder)
+		implementations, err := storeBuilder.GetStoreImplementations()
+		if err != nil {
+			log.Printf("[Warning] Failed to retrieve store implementations for plugin %q.", pluginID)
 			log.Debugf("%+v", err)
 		}
+		for _, implementation := range implementations {
+			log.Debugf("Registering store implementation %q into registry for plugin %q", implementation, pluginID)
+			reg.RegisterStoreBuilder(implementation, storeBuilder, pluginID)
+		}
+	} else {
+		log.Printf("[Warning] Can't retrieve store implementations from plugin %q: %v. This is likely due to a outdated plugin.", pluginID, err)
+		log.Debugf("%+v", err)
+	}
 
-		// Request the infra usage collector plugin
-		raw, err = rpcClient.Dispense(plugin.InfraUsageCollectorPluginName)
-		if err == nil {
-			infraUsageCollectorPlugin := raw.(plugin.InfraUsageCollector)
-			infras, err := infraUsageCollectorPlugin.GetSupportedInfras()
-			if err != nil {
-				log.Printf("[Warning] Failed to retrieve supported infrastructure for plugin %q.", pluginID)
-				log.Debugf("%+v", err)
-			}
-			if len(infras) > 0 {
-				for _, infra := range infras {
-					log.Debugf("Registering infrastructure usage collector %q into registry for plugin %q", infra, pluginID)
-					reg.RegisterInfraUsageCollector(infra, infraUsageCollectorPlugin, pluginID)
-				}
-			}
-		} else {
-			log.Printf("[Warning] Can't get collector supported infra from plugin %q: %v. This is likely due to a outdated plugin.", pluginID, err)
+	// Request the delegate plugin
+	raw, err = rpcClient.Dispense(plugin.DelegatePluginName)
+	if err == nil {
+		delegateExecutor := raw.(plugin.DelegateExecutor)
+		supportedTypes, err := delegateExecutor.GetSupportedTypes()
+		if err != nil {
+			log.Printf("[Warning] Failed to retrieve delegate executor supported type for plugin %q.", pluginID)
 			log.Debugf("%+v", err)
 		}
+		if len(supportedTypes) > 0 {
+			log.Debugf("Registering supported node types %v into registry for plugin %q", supportedTypes, pluginID)
+			reg.RegisterDelegates(supportedTypes, delegateExecutor, pluginID)
+		}
+	} else {
+		log.Printf("[Warning] Can't retrieve delegate executor from plugin %q: %v. This is likely due to a outdated plugin.", pluginID, err)
+		log.Debugf("%+v", err)
+	}
 
-		pm.pluginClients = append(pm.pluginClients, client)
+	// Request the operation plugin
+	raw, err = rpcClient.Dispense(plugin.OperationPluginName)
+	if err == nil {
+		operationExecutor := raw.(plugin.OperationExecutor)
+		supportedArtTypes, err := operationExecutor.GetSupportedArtifactTypes()
+		if err != nil {
+			log.Printf("[Warning] Failed to retrieve operation executor supported implementation artifacts for plugin %q.", pluginID)
+			log.Debugf("%+v", err)
+		}
+		if len(supportedArtTypes) > 0 {
+			log.Debugf("Registering supported implementation artifact types %v into registry for plugin %q", supportedArtTypes, pluginID)
+			reg.RegisterOperationExecutor(supportedArtTypes, operationExecutor, pluginID)
+		}
+	} else {
+		log.Printf("[Warning] Can't retrieve operation executor from plugin %q: %v. This is likely due to a outdated plugin.", pluginID, err)
+		log.Debugf("%+v", err)
+	}
 
-		log.Printf("Plugin %q successfully loaded", pluginID)
+	// Request the action plugin
+	raw, err = rpcClient.Dispense(plugin.ActionPluginName)
+	if err == nil {
+		actionOperator := raw.(plugin.ActionOperator)
+		actionTypes, err := actionOperator.GetActionTypes()
+		if err != nil {
+			log.Printf("[Warning] Failed to retrieve action types for plugin %q.", pluginID)
+			log.Debugf("%+v", err)
+		}
+		if len(actionTypes) > 0 {
+			log.Debugf("Registering action types %v into registry for plugin %q", actionTypes, pluginID)
+			reg.RegisterActionOperator(actionTypes, actionOperator, pluginID)
+		}
+	} else {
+		log.Printf("[Warning] Can't retrieve action operator from plugin %q: %v. This is likely due to a outdated plugin.", pluginID, err)
+		log.Debugf("%+v", err)
+	}
 
+	// Request the definitions plugin
+	raw, err = rpcClient.Dispense(plugin.DefinitionsPluginName)
+	if err == nil {
+		definitionPlugin := raw.(plugin.Definitions)
+		definitions, err := definitionPlugin.GetDefinitions()
+		if err != nil {
+			log.Printf("[Warning] Failed to retrieve TOSCA definitions for plugin %q.", pluginID)
+			log.Debugf("%+v", err)
+		}
+		for defName, defContent := range definitions {
+			log.Debugf("Registering TOSCA definition %q into registry for plugin %q", defName, pluginID)
+			reg.RegisterToscaDefinition(defName, defContent, pluginID)
+		}
+		inst.definitions = definitions
+	} else {
+		log.Printf("[Warning] Can't retrieve TOSCA definitions from plugin %q: %v. This is likely due to a outdated plugin.", pluginID, err)
+		log.Debugf("%+v", err)
 	}
 
-	return nil
+	// Request the infra usage collector plugin
+	raw, err = rpcClient.Dispense(plugin.InfraUsageCollectorPluginName)
+	if err == nil {
+		infraUsageCollectorPlugin := raw.(plugin.InfraUsageCollector)
+		infras, err := infraUsageCollectorPlugin.GetSupportedInfras()
+		if err != nil {
+			log.Printf("[Warning] Failed to retrieve supported infrastructure for plugin %q.", pluginID)
+			log.Debugf("%+v", err)
+		}
+		if len(infras) > 0 {
+			for _, infra := range infras {
+				log.Debugf("Registering infrastructure usage collector %q into registry for plugin %q", infra, pluginID)
+				reg.RegisterInfraUsageCollector(infra, infraUsageCollectorPlugin, pluginID)
+			}
+		}
+	} else {
+		log.Printf("[Warning] Can't get collector supported infra from plugin %q: %v. This is likely due to a outdated plugin.", pluginID, err)
+		log.Debugf("%+v", err)
+	}
+	return inst, nil
 }
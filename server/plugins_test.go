@@ -0,0 +1,74 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/registry"
+)
+
+func pluginState(pm *pluginManager, pluginID string) registry.PluginState {
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+	mp, ok := pm.plugins[pluginID]
+	if !ok {
+		return ""
+	}
+	return mp.status.State
+}
+
+func TestPluginManagerRejectsNonPluginExecutables(t *testing.T) {
+	pluginsDir, err := ioutil.TempDir("", "yorc-plugins-")
+	require.NoError(t, err)
+	defer os.RemoveAll(pluginsDir)
+	notAPlugin := filepath.Join(pluginsDir, "notAPlugin")
+	require.NoError(t, ioutil.WriteFile(notAPlugin, []byte("#!/bin/sh\necho 'not a plugin'\n"), 0755))
+
+	pm := newPluginManager()
+	defer pm.cleanup()
+	err = pm.loadPlugins(config.Configuration{PluginsDirectory: pluginsDir, PluginsHealthCheckInterval: time.Hour})
+	require.NoError(t, err)
+	require.Equal(t, registry.PluginStateRejected, pluginState(pm, "notAPlugin"))
+
+	// Rejected executables are not restarted
+	pm.checkPlugins()
+	require.Equal(t, registry.PluginStateRejected, pluginState(pm, "notAPlugin"))
+	pm.lock.Lock()
+	require.Equal(t, 0, pm.plugins["notAPlugin"].status.Restarts)
+	require.True(t, pm.plugins["notAPlugin"].nextRestart.IsZero())
+	pm.lock.Unlock()
+
+	// Updated executables are loaded again
+	modTime := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(notAPlugin, modTime, modTime))
+	require.NoError(t, pm.scanPlugins())
+	pm.lock.Lock()
+	require.True(t, pm.plugins["notAPlugin"].modTime.Equal(modTime))
+	pm.lock.Unlock()
+	require.Equal(t, registry.PluginStateRejected, pluginState(pm, "notAPlugin"))
+
+	// Removed executables are forgotten
+	require.NoError(t, os.Remove(notAPlugin))
+	require.NoError(t, pm.scanPlugins())
+	require.Equal(t, registry.PluginState(""), pluginState(pm, "notAPlugin"))
+}
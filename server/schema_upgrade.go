@@ -0,0 +1,59 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/server/upgradeschema"
+)
+
+// UpgradeSchema upgrades the data schema of the Yorc cluster to the version of this Yorc version.
+//
+// If dryRun is true, data are not upgraded but changes that would be done by the upgrade are
+// written into w.
+func UpgradeSchema(cfg config.Configuration, dryRun bool, w io.Writer) error {
+	client, pm, err := initOfflineEnv(cfg)
+	if err != nil {
+		return err
+	}
+	defer pm.cleanup()
+
+	if !dryRun {
+		err = registerBuiltinTOSCATypes()
+		if err != nil {
+			return err
+		}
+		return setupConsulDBSchema(cfg, client)
+	}
+
+	kvp, _, err := client.KV().Get(consulutil.YorcSchemaVersionPath, nil)
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp == nil {
+		return errors.New("no data schema version found, data of a pre-3.1 Yorc version can't be upgraded in dry-run mode")
+	}
+	report, err := upgradeschema.Migrate(context.Background(), cfg, client.KV(), nil, string(kvp.Value), upgradeschema.Options{DryRun: true, Migrations: schemaMigrations})
+	if report != nil {
+		report.Print(w)
+	}
+	return err
+}
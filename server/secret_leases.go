@@ -0,0 +1,110 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+
+	"github.com/ystia/yorc/v4/cluster"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+)
+
+// secretLeasesRenewalInterval is the period used to check if deployments secrets leases should be renewed
+const secretLeasesRenewalInterval = time.Minute
+
+// secretLeasesRenewer periodically renews leases of secrets used by deployments.
+//
+// Only the leader Yorc server of the cluster renews leases.
+type secretLeasesRenewer struct {
+	cc         *api.Client
+	serviceKey string
+	chShutdown chan struct{}
+	lock       sync.Mutex
+	chStop     chan struct{}
+}
+
+func startSecretLeasesRenewal(cc *api.Client) *secretLeasesRenewer {
+	r := &secretLeasesRenewer{
+		cc:         cc,
+		serviceKey: path.Join(consulutil.YorcServicePrefix, "/secrets/leases/leader"),
+		chShutdown: make(chan struct{}),
+	}
+	go cluster.WatchLeaderElection(r.cc, cluster.RoleSecretLeasesLeader, r.serviceKey, r.chShutdown, r.startRenewal, r.stopRenewal)
+	return r
+}
+
+func (r *secretLeasesRenewer) stop() {
+	r.stopRenewal()
+	close(r.chShutdown)
+}
+
+func (r *secretLeasesRenewer) startRenewal() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.chStop != nil {
+		return
+	}
+	chStop := make(chan struct{})
+	r.chStop = chStop
+	go func() {
+		ticker := time.NewTicker(secretLeasesRenewalInterval)
+		defer ticker.Stop()
+		for {
+			r.renewLeases()
+			select {
+			case <-chStop:
+				return
+			case <-r.chShutdown:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (r *secretLeasesRenewer) stopRenewal() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.chStop != nil {
+		close(r.chStop)
+		r.chStop = nil
+	}
+}
+
+func (r *secretLeasesRenewer) renewLeases() {
+	ctx := context.Background()
+	deploymentsIDs, err := deployments.GetDeploymentsIDs(ctx)
+	if err != nil {
+		log.Printf("[WARN] Failed to list deployments to renew secrets leases: %v", err)
+		return
+	}
+	for _, deploymentID := range deploymentsIDs {
+		status, err := deployments.GetDeploymentStatus(ctx, deploymentID)
+		if err != nil || status == deployments.UNDEPLOYED {
+			continue
+		}
+		err = deployments.RenewSecretLeases(ctx, deploymentID)
+		if err != nil {
+			log.Printf("[WARN] Failed to renew secrets leases of deployment %q: %v", deploymentID, err)
+		}
+	}
+}
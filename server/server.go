@@ -25,16 +25,22 @@ import (
 	"github.com/hashicorp/consul/api"
 	"github.com/pkg/errors"
 
+	"github.com/ystia/yorc/v4/cluster"
 	"github.com/ystia/yorc/v4/config"
 	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/deployments/expiry"
 	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/helper/redactutil"
 	"github.com/ystia/yorc/v4/locations"
 	"github.com/ystia/yorc/v4/log"
 	"github.com/ystia/yorc/v4/prov/monitoring"
 	"github.com/ystia/yorc/v4/prov/scheduling/scheduler"
 	"github.com/ystia/yorc/v4/rest"
 	"github.com/ystia/yorc/v4/storage"
+	"github.com/ystia/yorc/v4/tasks/approval"
+	rollouts "github.com/ystia/yorc/v4/tasks/rollout/controller"
 	"github.com/ystia/yorc/v4/tasks/workflow"
+	"github.com/ystia/yorc/v4/vault"
 )
 
 func initVaultClient(configuration config.Configuration) error {
@@ -44,7 +50,14 @@ func initVaultClient(configuration config.Configuration) error {
 	}
 	if vaultClient != nil {
 		fm := template.FuncMap{
-			"secret": vaultClient.GetSecret,
+			"secret": func(id string, options ...string) (vault.Secret, error) {
+				secret, err := vaultClient.GetSecret(id, options...)
+				if err == nil {
+					// Secrets used in configuration should never show up in logs
+					redactutil.RegisterSecret("", secret.String())
+				}
+				return secret, err
+			},
 		}
 		config.DefaultConfigTemplateResolver.SetTemplatesFunctions(fm)
 
@@ -118,19 +131,21 @@ func RunServer(configuration config.Configuration, shutdownCh chan struct{}) err
 		return err
 	}
 
-	client, err := initConsulClient(configuration)
+	// Plugins are loaded before stores as they may provide stores implementations
+	pm := newPluginManager()
+	defer pm.cleanup()
+	err = pm.loadPlugins(configuration)
 	if err != nil {
 		return err
 	}
 
-	err = initLocationManager(configuration)
+	client, err := initConsulClient(configuration)
 	if err != nil {
 		return err
 	}
+	pm.storesLoaded()
 
-	pm := newPluginManager()
-	defer pm.cleanup()
-	err = pm.loadPlugins(configuration)
+	err = initLocationManager(configuration)
 	if err != nil {
 		return err
 	}
@@ -146,6 +161,11 @@ func RunServer(configuration config.Configuration, shutdownCh chan struct{}) err
 		return err
 	}
 
+	// Join the cluster of Yorc servers
+	if err = cluster.Start(configuration, client, shutdownCh); err != nil {
+		return err
+	}
+
 	var wg sync.WaitGroup
 	// Dispatcher needs
 	go workflow.NewDispatcher(configuration, shutdownCh, client, &wg).Run()
@@ -158,6 +178,23 @@ func RunServer(configuration config.Configuration, shutdownCh chan struct{}) err
 	scheduler.Start(configuration, client)
 	defer scheduler.Stop()
 
+	// Start deployments expiry checks
+	expiry.Start(configuration, client)
+	defer expiry.Stop()
+
+	// Start tasks approval expiration checks
+	approval.Start(client)
+	defer approval.Stop()
+
+	// Start rollouts of workflows on scalable nodes
+	rollouts.Start(configuration, client)
+	defer rollouts.Stop()
+
+	if deployments.DefaultVaultClient != nil {
+		leasesRenewer := startSecretLeasesRenewal(client)
+		defer leasesRenewer.stop()
+	}
+
 	signalCh := make(chan os.Signal, 4)
 	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
 	for {
@@ -174,6 +211,10 @@ func RunServer(configuration config.Configuration, shutdownCh chan struct{}) err
 		// Check if this is a SIGHUP
 		if sig == syscall.SIGHUP {
 			// TODO reload
+			// Look for new, updated or removed plugins right now
+			if err := pm.scanPlugins(); err != nil {
+				log.Printf("[Warning] %v", err)
+			}
 		} else {
 			if !shutdownChClosed {
 				close(shutdownCh)
@@ -0,0 +1,171 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgradeschema
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/storage"
+	"github.com/ystia/yorc/v4/storage/store"
+	"github.com/ystia/yorc/v4/storage/types"
+)
+
+// Backend is a storage backend changed by migrations
+type Backend interface {
+	// Get returns the value of a key or nil if it doesn't exist
+	Get(key string) (*Value, error)
+	// Keys returns the direct sub-keys of a key
+	Keys(key string) ([]string, error)
+	// Set sets the value of a key
+	Set(ctx context.Context, key string, value Value) error
+	// Delete deletes a key, sub-keys are not deleted
+	Delete(ctx context.Context, key string) error
+}
+
+// treeBackend is implemented by backends able to list and delete a tree of keys in a single request
+type treeBackend interface {
+	// Tree returns the given key and its sub-keys having a value, values are returned only if withValues is true
+	Tree(key string, withValues bool) (map[string]*Value, error)
+	// DeleteTree deletes a key and all its sub-keys
+	DeleteTree(ctx context.Context, key string) error
+}
+
+type consulBackend struct {
+	kv *api.KV
+}
+
+// NewConsulBackend returns a backend using the given Consul KV store
+func NewConsulBackend(kv *api.KV) Backend {
+	return &consulBackend{kv: kv}
+}
+
+func (b *consulBackend) Get(key string) (*Value, error) {
+	kvp, _, err := b.kv.Get(key, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp == nil {
+		return nil, nil
+	}
+	return &Value{Data: kvp.Value, Flags: kvp.Flags}, nil
+}
+
+func (b *consulBackend) Keys(key string) ([]string, error) {
+	keys, _, err := b.kv.Keys(strings.TrimSuffix(key, "/")+"/", "/", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	// A key may be both a value and a folder
+	result := make([]string, 0, len(keys))
+	seen := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		k = strings.TrimSuffix(k, "/")
+		if _, ok := seen[k]; !ok {
+			seen[k] = struct{}{}
+			result = append(result, k)
+		}
+	}
+	return result, nil
+}
+
+func (b *consulBackend) Set(ctx context.Context, key string, value Value) error {
+	_, err := b.kv.Put(&api.KVPair{Key: key, Value: value.Data, Flags: value.Flags}, nil)
+	return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+}
+
+func (b *consulBackend) Delete(ctx context.Context, key string) error {
+	_, err := b.kv.Delete(key, nil)
+	return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+}
+
+func (b *consulBackend) Tree(key string, withValues bool) (map[string]*Value, error) {
+	result := make(map[string]*Value)
+	root, err := b.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if root != nil {
+		result[key] = root
+	}
+	if !withValues {
+		keys, _, err := b.kv.Keys(key+"/", "", nil)
+		if err != nil {
+			return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+		}
+		for _, k := range keys {
+			result[k] = nil
+		}
+		return result, nil
+	}
+	kvps, _, err := b.kv.List(key+"/", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	for _, kvp := range kvps {
+		result[kvp.Key] = &Value{Data: kvp.Value, Flags: kvp.Flags}
+	}
+	return result, nil
+}
+
+func (b *consulBackend) DeleteTree(ctx context.Context, key string) error {
+	_, err := b.kv.DeleteTree(key+"/", nil)
+	if err != nil {
+		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	return b.Delete(ctx, key)
+}
+
+type storeBackend struct {
+	getStore func() store.Store
+}
+
+// NewStoreBackend returns a backend using the given store.
+//
+// Values are the raw values stored by the store, flags are ignored.
+func NewStoreBackend(s store.Store) Backend {
+	return &storeBackend{getStore: func() store.Store { return s }}
+}
+
+// newStoreTypeBackend returns a backend using the store configured for the given type
+func newStoreTypeBackend(storeType types.StoreType) Backend {
+	return &storeBackend{getStore: func() store.Store { return storage.GetStore(storeType) }}
+}
+
+func (b *storeBackend) Get(key string) (*Value, error) {
+	var raw json.RawMessage
+	exist, err := b.getStore().Get(key, &raw)
+	if err != nil || !exist {
+		return nil, err
+	}
+	return &Value{Data: raw}, nil
+}
+
+func (b *storeBackend) Keys(key string) ([]string, error) {
+	return b.getStore().Keys(strings.TrimSuffix(key, "/"))
+}
+
+func (b *storeBackend) Set(ctx context.Context, key string, value Value) error {
+	return b.getStore().Set(ctx, key, json.RawMessage(value.Data))
+}
+
+func (b *storeBackend) Delete(ctx context.Context, key string) error {
+	return b.getStore().Delete(ctx, key, false)
+}
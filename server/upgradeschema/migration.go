@@ -0,0 +1,282 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgradeschema
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/blang/semver"
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/storage/types"
+)
+
+// ConsulBackend is the name of the backend giving access to the raw Consul KV store
+const ConsulBackend = "consul"
+
+// Migration is a versioned change of the Yorc data schema
+type Migration struct {
+	// Version is the data schema version reached once the migration is applied
+	Version string
+	// Description describes what the migration does
+	Description string
+	// Up applies the migration. All changes should be done through the session
+	// to be reported by dry runs and to be reverted if the upgrade fails.
+	Up func(ctx context.Context, s *Session) error
+	// Down optionally reverts the migration.
+	// If not set, the changes recorded by the session while applying the migration are reverted.
+	Down func(ctx context.Context, s *Session) error
+	// Legacy is used by migrations working directly on Consul KV instead of Up.
+	// Such migrations are not reported by dry runs and can't be reverted.
+	Legacy func(cfg config.Configuration, kv *api.KV, leaderch <-chan struct{}) error
+}
+
+// Options allows to configure migrations
+type Options struct {
+	// DryRun allows to report changes that would be done without applying them
+	DryRun bool
+	// Migrations to apply, defaults to the registered ones
+	Migrations []Migration
+}
+
+// MigrationReport reports what was done by a migration
+type MigrationReport struct {
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	Legacy      bool     `json:"legacy,omitempty"`
+	Changes     []Change `json:"changes,omitempty"`
+	RolledBack  bool     `json:"rolled_back,omitempty"`
+}
+
+// Report reports what was done by Migrate
+type Report struct {
+	From       string             `json:"from"`
+	To         string             `json:"to"`
+	DryRun     bool               `json:"dry_run,omitempty"`
+	Migrations []*MigrationReport `json:"migrations"`
+	// RolledBack is true if migrations failed and all the applied ones were reverted
+	RolledBack bool `json:"rolled_back,omitempty"`
+}
+
+var registry = struct {
+	sync.Mutex
+	migrations map[string]Migration
+}{migrations: make(map[string]Migration)}
+
+// Register registers a migration.
+//
+// It panics if a migration is already registered for the same version or if the version is invalid.
+func Register(m Migration) {
+	semver.MustParse(m.Version)
+	if (m.Up == nil) == (m.Legacy == nil) {
+		log.Panicf("migration to version %q should define either Up or Legacy", m.Version)
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.migrations[m.Version]; ok {
+		log.Panicf("a migration to version %q is already registered", m.Version)
+	}
+	registry.migrations[m.Version] = m
+}
+
+// Migrations returns the registered migrations ordered by version
+func Migrations() []Migration {
+	registry.Lock()
+	defer registry.Unlock()
+	result := make([]Migration, 0, len(registry.migrations))
+	for _, m := range registry.migrations {
+		result = append(result, m)
+	}
+	sortMigrations(result)
+	return result
+}
+
+func sortMigrations(migrations []Migration) {
+	sort.Slice(migrations, func(i, j int) bool {
+		return semver.MustParse(migrations[i].Version).LT(semver.MustParse(migrations[j].Version))
+	})
+}
+
+// Migrate applies the migrations to versions greater than fromVersion.
+//
+// The schema version stored in Consul is updated after each applied migration.
+// If a migration fails, it and the previously applied ones are reverted up to the
+// last applied legacy migration. Report.RolledBack tells if data are back to fromVersion.
+func Migrate(ctx context.Context, cfg config.Configuration, kv *api.KV, leaderch <-chan struct{}, fromVersion string, opts Options) (*Report, error) {
+	setVersion := func(version string) error {
+		return consulutil.StoreConsulKeyAsString(consulutil.YorcSchemaVersionPath, version)
+	}
+	legacy := func(m Migration) error {
+		return m.Legacy(cfg, kv, leaderch)
+	}
+	return migrate(ctx, cfg, newResolver(kv), setVersion, legacy, fromVersion, opts)
+}
+
+func migrate(ctx context.Context, cfg config.Configuration, resolver func(string) (Backend, error), setVersion func(string) error,
+	legacy func(Migration) error, fromVersion string, opts Options) (*Report, error) {
+	vFrom, err := semver.Make(fromVersion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid data schema version %q", fromVersion)
+	}
+	migrations := opts.Migrations
+	if migrations == nil {
+		migrations = Migrations()
+	} else {
+		migrations = append([]Migration(nil), migrations...)
+		sortMigrations(migrations)
+	}
+
+	report := &Report{From: fromVersion, To: fromVersion, DryRun: opts.DryRun, Migrations: make([]*MigrationReport, 0)}
+	// In dry-run mode the same session is used for all migrations, so they see changes of previous ones
+	dryRunSession := newSession(cfg, resolver, true)
+	type applied struct {
+		m       Migration
+		session *Session
+		report  *MigrationReport
+	}
+	appliedMigrations := make([]applied, 0)
+	// baseVersion is the version data could be reverted to
+	baseVersion := fromVersion
+	for _, m := range migrations {
+		if !semver.MustParse(m.Version).GT(vFrom) {
+			continue
+		}
+		mr := &MigrationReport{Version: m.Version, Description: m.Description, Legacy: m.Legacy != nil}
+		report.Migrations = append(report.Migrations, mr)
+		if opts.DryRun {
+			if m.Legacy == nil {
+				dryRunSession.changes = nil
+				err = m.Up(ctx, dryRunSession)
+				mr.Changes = dryRunSession.changes
+				if err != nil {
+					return report, errors.Wrapf(err, "dry run of migration to data schema version %q failed", m.Version)
+				}
+			}
+			report.To = m.Version
+			continue
+		}
+
+		log.Printf("Upgrading to database version %s: %s", m.Version, m.Description)
+		session := newSession(cfg, resolver, false)
+		session.revertible = m.Down == nil
+		if m.Legacy != nil {
+			err = legacy(m)
+		} else {
+			err = m.Up(ctx, session)
+			mr.Changes = session.changes
+		}
+		if err == nil {
+			err = setVersion(m.Version)
+		}
+		if err != nil {
+			err = errors.Wrapf(err, "failed to upgrade data schema to version %q", m.Version)
+			if m.Legacy != nil {
+				// A legacy migration can't be reverted
+				return report, err
+			}
+			appliedMigrations = append(appliedMigrations, applied{m, session, mr})
+			for i := len(appliedMigrations) - 1; i >= 0; i-- {
+				a := appliedMigrations[i]
+				rbErr := a.session.revert(ctx, a.m)
+				if rbErr != nil {
+					log.Printf("[ERROR] failed to revert migration to data schema version %q: %+v", a.m.Version, rbErr)
+					// Data are now in the state of this migration
+					baseVersion = a.m.Version
+					break
+				}
+				a.report.RolledBack = true
+				log.Printf("Migration to data schema version %q reverted", a.m.Version)
+			}
+			if vErr := setVersion(baseVersion); vErr != nil {
+				log.Printf("[ERROR] failed to restore data schema version %q: %+v", baseVersion, vErr)
+			}
+			report.To = baseVersion
+			report.RolledBack = baseVersion == fromVersion
+			return report, err
+		}
+		report.To = m.Version
+		if m.Legacy != nil {
+			// A legacy migration can't be reverted so previous ones can't be reverted either
+			baseVersion = m.Version
+			appliedMigrations = appliedMigrations[:0]
+			continue
+		}
+		appliedMigrations = append(appliedMigrations, applied{m, session, mr})
+	}
+	return report, nil
+}
+
+// Print writes a human readable version of the report
+func (r *Report) Print(w io.Writer) {
+	if len(r.Migrations) == 0 {
+		fmt.Fprintf(w, "Data schema version %s is up to date\n", r.From)
+		return
+	}
+	verb := "Upgraded"
+	if r.DryRun {
+		verb = "Upgrade"
+	}
+	fmt.Fprintf(w, "%s data schema from version %s to %s\n", verb, r.From, r.To)
+	for _, mr := range r.Migrations {
+		fmt.Fprintf(w, "Migration to version %s: %s\n", mr.Version, mr.Description)
+		if mr.Legacy {
+			fmt.Fprintln(w, "    changes of this migration can't be reported")
+			continue
+		}
+		if len(mr.Changes) == 0 {
+			fmt.Fprintln(w, "    no changes")
+		}
+		for _, c := range mr.Changes {
+			fmt.Fprintf(w, "    %-6s %s %s\n", c.Operation(), c.Backend, c.Key)
+		}
+		if mr.RolledBack {
+			fmt.Fprintln(w, "    reverted")
+		}
+	}
+}
+
+// apply applies a single migration without updating the schema version
+func apply(cfg config.Configuration, kv *api.KV, m Migration) error {
+	return m.Up(context.Background(), newSession(cfg, newResolver(kv), false))
+}
+
+// newResolver returns a function resolving backends by name: Consul or stores types
+func newResolver(kv *api.KV) func(string) (Backend, error) {
+	return func(name string) (Backend, error) {
+		if name == ConsulBackend {
+			return NewConsulBackend(kv), nil
+		}
+		return storeBackendFromName(name)
+	}
+}
+
+func storeBackendFromName(name string) (Backend, error) {
+	for _, storeTypeName := range types.StoreTypeNames() {
+		if strings.EqualFold(name, storeTypeName) {
+			storeType, _ := types.ParseStoreType(storeTypeName)
+			return newStoreTypeBackend(storeType), nil
+		}
+	}
+	return nil, errors.Errorf("unknown migration backend %q", name)
+}
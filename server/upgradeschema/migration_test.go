@@ -0,0 +1,322 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgradeschema
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+)
+
+type memBackend struct {
+	lock sync.Mutex
+	kv   map[string]Value
+	// treeRequests counts the requests listing or deleting a tree of keys
+	treeRequests int
+}
+
+func newMemBackend(kv map[string]string) *memBackend {
+	b := &memBackend{kv: make(map[string]Value)}
+	for k, v := range kv {
+		b.kv[k] = Value{Data: []byte(v)}
+	}
+	return b
+}
+
+func (b *memBackend) Get(key string) (*Value, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	v, ok := b.kv[key]
+	if !ok {
+		return nil, nil
+	}
+	return &v, nil
+}
+
+func (b *memBackend) Keys(key string) ([]string, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	prefix := key + "/"
+	set := make(map[string]struct{})
+	for k := range b.kv {
+		if strings.HasPrefix(k, prefix) {
+			set[prefix+strings.SplitN(strings.TrimPrefix(k, prefix), "/", 2)[0]] = struct{}{}
+		}
+	}
+	result := make([]string, 0, len(set))
+	for k := range set {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func (b *memBackend) Set(ctx context.Context, key string, value Value) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.kv[key] = value
+	return nil
+}
+
+func (b *memBackend) Delete(ctx context.Context, key string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	delete(b.kv, key)
+	return nil
+}
+
+func (b *memBackend) Tree(key string, withValues bool) (map[string]*Value, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.treeRequests++
+	result := make(map[string]*Value)
+	for k, v := range b.kv {
+		if k == key || strings.HasPrefix(k, key+"/") {
+			result[k] = nil
+			if withValues {
+				value := v
+				result[k] = &value
+			}
+		}
+	}
+	return result, nil
+}
+
+func (b *memBackend) DeleteTree(ctx context.Context, key string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.treeRequests++
+	for k := range b.kv {
+		if k == key || strings.HasPrefix(k, key+"/") {
+			delete(b.kv, k)
+		}
+	}
+	return nil
+}
+
+func (b *memBackend) values() map[string]string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	result := make(map[string]string, len(b.kv))
+	for k, v := range b.kv {
+		result[k] = string(v.Data)
+	}
+	return result
+}
+
+type migrateEnv struct {
+	backend  *memBackend
+	version  string
+	legacies []string
+}
+
+func (e *migrateEnv) migrate(from string, opts Options) (*Report, error) {
+	resolver := func(name string) (Backend, error) {
+		if name != ConsulBackend {
+			return nil, errors.Errorf("unknown backend %q", name)
+		}
+		return e.backend, nil
+	}
+	setVersion := func(v string) error {
+		e.version = v
+		return nil
+	}
+	legacy := func(m Migration) error {
+		e.legacies = append(e.legacies, m.Version)
+		return m.Legacy(config.Configuration{}, nil, nil)
+	}
+	return migrate(context.Background(), config.Configuration{}, resolver, setVersion, legacy, from, opts)
+}
+
+func setKey(key, value string) func(ctx context.Context, s *Session) error {
+	return func(ctx context.Context, s *Session) error {
+		return s.Consul().Set(ctx, key, Value{Data: []byte(value)})
+	}
+}
+
+func failing(ctx context.Context, s *Session) error {
+	err := s.Consul().Delete(ctx, "a/b")
+	if err != nil {
+		return err
+	}
+	return errors.New("expected failure")
+}
+
+func TestMigrate(t *testing.T) {
+	initial := map[string]string{"a/b": "b", "a/c": "c"}
+	legacyOK := func(cfg config.Configuration, kv *api.KV, leaderch <-chan struct{}) error { return nil }
+	tests := []struct {
+		name           string
+		from           string
+		migrations     []Migration
+		wantErr        bool
+		wantVersion    string
+		wantValues     map[string]string
+		wantRolledBack bool
+		wantLegacies   []string
+	}{
+		{"Applied", "1.0.0", []Migration{
+			{Version: "1.0.0", Up: setKey("a/old", "old")},
+			{Version: "1.2.0", Up: func(ctx context.Context, s *Session) error { return s.Consul().DeleteTree(ctx, "a") }},
+			{Version: "1.1.0", Up: setKey("a/b", "b2")},
+			{Version: "1.3.0", Up: setKey("d", "d")},
+		}, false, "1.3.0", map[string]string{"d": "d"}, false, nil},
+		{"RolledBack", "1.0.0", []Migration{
+			{Version: "1.1.0", Up: setKey("a/c", "c2")},
+			{Version: "1.2.0", Up: setKey("a/d", "d")},
+			{Version: "1.3.0", Up: failing},
+		}, true, "1.0.0", initial, true, nil},
+		{"RolledBackUpToLegacy", "1.0.0", []Migration{
+			{Version: "1.1.0", Up: setKey("a/c", "c2")},
+			{Version: "1.2.0", Legacy: legacyOK},
+			{Version: "1.3.0", Up: failing},
+		}, true, "1.2.0", map[string]string{"a/b": "b", "a/c": "c2"}, false, []string{"1.2.0"}},
+		{"FailedLegacy", "1.0.0", []Migration{
+			{Version: "1.1.0", Up: setKey("a/c", "c2")},
+			{Version: "1.2.0", Legacy: func(cfg config.Configuration, kv *api.KV, leaderch <-chan struct{}) error {
+				return errors.New("expected failure")
+			}},
+		}, true, "1.1.0", map[string]string{"a/b": "b", "a/c": "c2"}, false, []string{"1.2.0"}},
+		{"Down", "1.0.0", []Migration{
+			{Version: "1.1.0", Up: setKey("a/c", "c2"), Down: setKey("a/c", "down")},
+			{Version: "1.2.0", Up: failing},
+		}, true, "1.0.0", map[string]string{"a/b": "b", "a/c": "down"}, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := &migrateEnv{backend: newMemBackend(initial), version: tt.from}
+			report, err := env.migrate(tt.from, Options{Migrations: tt.migrations})
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantVersion, env.version)
+			require.Equal(t, tt.wantVersion, report.To)
+			require.Equal(t, tt.wantValues, env.backend.values())
+			require.Equal(t, tt.wantRolledBack, report.RolledBack)
+			require.Equal(t, tt.wantLegacies, env.legacies)
+		})
+	}
+}
+
+func TestMigrateDryRun(t *testing.T) {
+	initial := map[string]string{"a/b": "b", "a/c": "c"}
+	env := &migrateEnv{backend: newMemBackend(initial), version: "1.0.0"}
+	migrations := []Migration{
+		{Version: "1.1.0", Up: func(ctx context.Context, s *Session) error {
+			err := s.Consul().Set(ctx, "a/d/e", Value{Data: []byte("e")})
+			if err != nil {
+				return err
+			}
+			return s.Consul().Delete(ctx, "a/b")
+		}},
+		{Version: "1.2.0", Legacy: func(cfg config.Configuration, kv *api.KV, leaderch <-chan struct{}) error {
+			return errors.New("legacy migrations should not be called on dry runs")
+		}},
+		{Version: "1.3.0", Up: func(ctx context.Context, s *Session) error {
+			// Changes of previous migrations are visible
+			keys, err := s.Consul().Keys("a")
+			if err != nil {
+				return err
+			}
+			return s.Consul().Set(ctx, "keys", Value{Data: []byte(strings.Join(keys, ","))})
+		}},
+	}
+	report, err := env.migrate("1.0.0", Options{DryRun: true, Migrations: migrations})
+	require.NoError(t, err)
+	require.Equal(t, "1.3.0", report.To)
+	require.Equal(t, "1.0.0", env.version)
+	require.Equal(t, initial, env.backend.values())
+	require.Len(t, report.Migrations, 3)
+	require.Len(t, report.Migrations[0].Changes, 2)
+	require.Equal(t, "create", report.Migrations[0].Changes[0].Operation())
+	require.Equal(t, "delete", report.Migrations[0].Changes[1].Operation())
+	require.True(t, report.Migrations[1].Legacy)
+	require.Len(t, report.Migrations[2].Changes, 1)
+	require.Equal(t, "a/c,a/d", string(report.Migrations[2].Changes[0].Value.Data))
+
+	buf := new(bytes.Buffer)
+	report.Print(buf)
+	require.Contains(t, buf.String(), "create consul a/d/e")
+	require.Contains(t, buf.String(), "can't be reported")
+}
+
+func TestTargetSetUnchanged(t *testing.T) {
+	b := newMemBackend(map[string]string{"a": "a"})
+	s := newSession(config.Configuration{}, func(string) (Backend, error) { return b, nil }, false)
+	require.NoError(t, s.Consul().Set(context.Background(), "a", Value{Data: []byte("a")}))
+	require.NoError(t, s.Consul().Delete(context.Background(), "missing"))
+	require.Empty(t, s.changes)
+}
+
+func TestTargetDeleteTree(t *testing.T) {
+	initial := map[string]string{"a": "a", "a/b": "b", "a/b/c": "c", "ab": "ab"}
+	tests := []struct {
+		name       string
+		revertible bool
+	}{
+		{"Revertible", true},
+		{"NotRevertible", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newMemBackend(initial)
+			s := newSession(config.Configuration{}, func(string) (Backend, error) { return b, nil }, false)
+			s.revertible = tt.revertible
+			require.NoError(t, s.Consul().DeleteTree(context.Background(), "a/"))
+			require.Equal(t, map[string]string{"ab": "ab"}, b.values())
+			require.Equal(t, 2, b.treeRequests)
+			require.Len(t, s.changes, 3)
+			for _, c := range s.changes {
+				require.Equal(t, "delete", c.Operation())
+				if tt.revertible {
+					require.Equal(t, initial[c.Key], string(c.Previous.Data))
+				} else {
+					require.Nil(t, c.Previous)
+				}
+			}
+
+			err := s.revert(context.Background(), Migration{})
+			if !tt.revertible {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, initial, b.values())
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	require.Panics(t, func() { Register(Migration{Version: "1.3.1", Up: setKey("a", "a")}) }, "duplicate version")
+	require.Panics(t, func() { Register(Migration{Version: "invalid", Up: setKey("a", "a")}) }, "invalid version")
+	require.Panics(t, func() { Register(Migration{Version: "99.0.0"}) }, "missing Up or Legacy")
+
+	migrations := Migrations()
+	require.NotEmpty(t, migrations)
+	require.Equal(t, "1.0.0", migrations[0].Version)
+	require.Equal(t, consulutil.YorcSchemaVersion, migrations[len(migrations)-1].Version)
+}
@@ -0,0 +1,344 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upgradeschema
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/storage/types"
+)
+
+// Value is a value stored in a backend. Flags are only supported by the Consul backend.
+type Value struct {
+	Data  []byte `json:"data"`
+	Flags uint64 `json:"flags,omitempty"`
+}
+
+func (v *Value) equal(o *Value) bool {
+	return v.Flags == o.Flags && bytes.Equal(v.Data, o.Data)
+}
+
+// Change is a change of a key done by a migration
+type Change struct {
+	Backend string `json:"backend"`
+	Key     string `json:"key"`
+	// Previous is the value before the change, nil if the key was created.
+	// It is not recorded for keys deleted by Target.DeleteTree if the session changes are not reverted.
+	Previous *Value `json:"previous,omitempty"`
+	// Value is the value after the change, nil if the key was deleted
+	Value *Value `json:"value,omitempty"`
+}
+
+// Operation returns the kind of change: create, update or delete
+func (c Change) Operation() string {
+	switch {
+	case c.Value == nil:
+		return "delete"
+	case c.Previous == nil:
+		return "create"
+	default:
+		return "update"
+	}
+}
+
+// Session gives access to storage backends to a migration and records the changes it does.
+//
+// In dry-run mode changes are not applied to backends but kept in the session,
+// so they are visible to the migration. A session is safe for concurrent use.
+type Session struct {
+	cfg      config.Configuration
+	resolver func(string) (Backend, error)
+	dryRun   bool
+	// revertible is true if the recorded changes are used to revert the migration
+	revertible bool
+
+	lock     sync.Mutex
+	backends map[string]Backend
+	changes  []Change
+	// overlay holds the keys changed during a dry run by backend, a nil value is a deleted key
+	overlay map[string]map[string]*Value
+}
+
+func newSession(cfg config.Configuration, resolver func(string) (Backend, error), dryRun bool) *Session {
+	return &Session{
+		cfg:      cfg,
+		resolver: resolver,
+		dryRun:   dryRun,
+		backends: make(map[string]Backend),
+		overlay:  make(map[string]map[string]*Value),
+	}
+}
+
+// Config returns the Yorc configuration
+func (s *Session) Config() config.Configuration {
+	return s.cfg
+}
+
+// DryRun returns true if changes are not applied
+func (s *Session) DryRun() bool {
+	return s.dryRun
+}
+
+// Consul returns the target allowing to change the raw Consul KV store
+func (s *Session) Consul() *Target {
+	return s.Target(ConsulBackend)
+}
+
+// Store returns the target allowing to change the store of the given type
+func (s *Session) Store(storeType types.StoreType) *Target {
+	return s.Target(storeType.String())
+}
+
+// Target returns the target allowing to change the backend with the given name
+func (s *Session) Target(backend string) *Target {
+	return &Target{s: s, name: backend}
+}
+
+func (s *Session) backend(name string) (Backend, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if b, ok := s.backends[name]; ok {
+		return b, nil
+	}
+	b, err := s.resolver(name)
+	if err != nil {
+		return nil, err
+	}
+	s.backends[name] = b
+	return b, nil
+}
+
+func (s *Session) record(c Change) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.changes = append(s.changes, c)
+	if s.dryRun {
+		if s.overlay[c.Backend] == nil {
+			s.overlay[c.Backend] = make(map[string]*Value)
+		}
+		s.overlay[c.Backend][c.Key] = c.Value
+	}
+}
+
+// revert reverts a migration applied using this session
+func (s *Session) revert(ctx context.Context, m Migration) error {
+	if m.Down != nil {
+		return m.Down(ctx, s)
+	}
+	for i := len(s.changes) - 1; i >= 0; i-- {
+		c := s.changes[i]
+		b, err := s.backend(c.Backend)
+		if err != nil {
+			return err
+		}
+		if c.Previous == nil && c.Value == nil {
+			return errors.Errorf("previous value of deleted key %q of backend %q was not recorded", c.Key, c.Backend)
+		}
+		if c.Previous == nil {
+			err = b.Delete(ctx, c.Key)
+		} else {
+			err = b.Set(ctx, c.Key, *c.Previous)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to revert change of key %q of backend %q", c.Key, c.Backend)
+		}
+	}
+	return nil
+}
+
+// Target allows to read and change keys of a backend within a session
+type Target struct {
+	s    *Session
+	name string
+}
+
+// Get returns the value of a key or nil if it doesn't exist
+func (t *Target) Get(key string) (*Value, error) {
+	if t.s.dryRun {
+		t.s.lock.Lock()
+		v, ok := t.s.overlay[t.name][key]
+		t.s.lock.Unlock()
+		if ok {
+			return v, nil
+		}
+	}
+	b, err := t.s.backend(t.name)
+	if err != nil {
+		return nil, err
+	}
+	return b.Get(key)
+}
+
+// Keys returns the direct sub-keys of a key
+func (t *Target) Keys(key string) ([]string, error) {
+	b, err := t.s.backend(t.name)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := b.Keys(key)
+	if err != nil || !t.s.dryRun {
+		return keys, err
+	}
+
+	t.s.lock.Lock()
+	defer t.s.lock.Unlock()
+	overlay := t.s.overlay[t.name]
+	set := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		if v, ok := overlay[k]; !ok || v != nil {
+			set[k] = struct{}{}
+		}
+	}
+	prefix := strings.TrimSuffix(key, "/") + "/"
+	for k, v := range overlay {
+		if v == nil || !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		set[prefix+strings.SplitN(strings.TrimPrefix(k, prefix), "/", 2)[0]] = struct{}{}
+	}
+	result := make([]string, 0, len(set))
+	for k := range set {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// Walk calls fn for the given key and all its sub-keys having a value
+func (t *Target) Walk(key string, fn func(key string, value *Value) error) error {
+	v, err := t.Get(key)
+	if err != nil {
+		return err
+	}
+	if v != nil {
+		err = fn(key, v)
+		if err != nil {
+			return err
+		}
+	}
+	keys, err := t.Keys(key)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		err = t.Walk(k, fn)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Set sets the value of a key
+func (t *Target) Set(ctx context.Context, key string, value Value) error {
+	previous, err := t.Get(key)
+	if err != nil {
+		return err
+	}
+	if previous != nil && previous.equal(&value) {
+		return nil
+	}
+	if !t.s.dryRun {
+		b, err := t.s.backend(t.name)
+		if err != nil {
+			return err
+		}
+		err = b.Set(ctx, key, value)
+		if err != nil {
+			return err
+		}
+	}
+	t.s.record(Change{Backend: t.name, Key: key, Previous: previous, Value: &value})
+	return nil
+}
+
+// Delete deletes a key. Sub-keys are not deleted.
+func (t *Target) Delete(ctx context.Context, key string) error {
+	previous, err := t.Get(key)
+	if err != nil || previous == nil {
+		return err
+	}
+	if !t.s.dryRun {
+		b, err := t.s.backend(t.name)
+		if err != nil {
+			return err
+		}
+		err = b.Delete(ctx, key)
+		if err != nil {
+			return err
+		}
+	}
+	t.s.record(Change{Backend: t.name, Key: key, Previous: previous})
+	return nil
+}
+
+// DeleteTree deletes a key and all its sub-keys
+//
+// Backends supporting it delete the tree in a single request, the previous values of the deleted keys are then
+// read and recorded only if the changes of the session may be reverted.
+func (t *Target) DeleteTree(ctx context.Context, key string) error {
+	key = strings.TrimSuffix(key, "/")
+	b, err := t.s.backend(t.name)
+	if err != nil {
+		return err
+	}
+	tb, ok := b.(treeBackend)
+	if !ok || t.s.dryRun {
+		return t.deleteKeys(ctx, key)
+	}
+	values, err := tb.Tree(key, t.s.revertible)
+	if err != nil || len(values) == 0 {
+		return err
+	}
+	err = tb.DeleteTree(ctx, key)
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		t.s.record(Change{Backend: t.name, Key: k, Previous: values[k]})
+	}
+	return nil
+}
+
+// deleteKeys deletes a key and all its sub-keys one by one
+func (t *Target) deleteKeys(ctx context.Context, key string) error {
+	keys := make([]string, 0)
+	err := t.Walk(key, func(k string, v *Value) error {
+		keys = append(keys, k)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		err = t.Delete(ctx, k)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -16,28 +16,37 @@ package upgradeschema
 
 import (
 	"context"
-	"github.com/ystia/yorc/v4/config"
-	"golang.org/x/sync/errgroup"
 	"path"
 	"strings"
 
 	"github.com/hashicorp/consul/api"
-	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
 
+	"github.com/ystia/yorc/v4/config"
 	"github.com/ystia/yorc/v4/helper/consulutil"
-	"github.com/ystia/yorc/v4/log"
 )
 
+func init() {
+	Register(Migration{
+		Version:     "1.1.0",
+		Description: "Remove descriptions and names of TOSCA elements from deployments topologies",
+		Up:          up110,
+	})
+}
+
 // UpgradeTo110 allows to upgrade Consul schema from 1.0.0 to 1.1.0
 func UpgradeTo110(cfg config.Configuration, kv *api.KV, leaderch <-chan struct{}) error {
-	log.Print("Upgrading to database version 1.1.0...")
-	keys, _, err := kv.Keys(consulutil.DeploymentKVPrefix+"/", "/", nil)
+	return apply(cfg, kv, Migration{Up: up110})
+}
+
+func up110(ctx context.Context, s *Session) error {
+	kv := s.Consul()
+	keys, err := kv.Keys(consulutil.DeploymentKVPrefix)
 	if err != nil {
-		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+		return err
 	}
 
-	ctx := context.Background()
-	sem := make(chan struct{}, cfg.UpgradeConcurrencyLimit)
+	sem := make(chan struct{}, s.Config().UpgradeConcurrencyLimit)
 	errGroup, ctx := errgroup.WithContext(ctx)
 	for _, deploymentPrefix := range keys {
 		topologyPrefix := path.Join(deploymentPrefix, "topology")
@@ -46,61 +55,58 @@ func UpgradeTo110(cfg config.Configuration, kv *api.KV, leaderch <-chan struct{}
 			defer func() {
 				<-sem
 			}()
-			if err = deleteElementsFromConsulPrefix(kv, topologyPrefix, "description", "tosca_version"); err != nil {
+			if err := deleteElementsFromPrefix(ctx, kv, topologyPrefix, "description", "tosca_version"); err != nil {
 				return err
 			}
-			if err = deleteElementsFromAllSubPathsOfPrefix(kv, path.Join(topologyPrefix, "imports"), "description", "tosca_version"); err != nil {
+			if err := deleteElementsFromAllSubPathsOfPrefix(ctx, kv, path.Join(topologyPrefix, "imports"), "description", "tosca_version"); err != nil {
 				return err
 			}
-			if err = deleteElementsFromAllSubPathsOfPrefix(kv, path.Join(topologyPrefix, "repositories"), "description"); err != nil {
+			if err := deleteElementsFromAllSubPathsOfPrefix(ctx, kv, path.Join(topologyPrefix, "repositories"), "description"); err != nil {
 				return err
 			}
-			if err = deleteElementsFromAllSubPathsOfPrefix(kv, path.Join(topologyPrefix, "outputs"), "description", "name"); err != nil {
+			if err := deleteElementsFromAllSubPathsOfPrefix(ctx, kv, path.Join(topologyPrefix, "outputs"), "description", "name"); err != nil {
 				return err
 			}
-			if err = deleteElementsFromAllSubPathsOfPrefix(kv, path.Join(topologyPrefix, "inputs"), "description", "name"); err != nil {
+			if err := deleteElementsFromAllSubPathsOfPrefix(ctx, kv, path.Join(topologyPrefix, "inputs"), "description", "name"); err != nil {
 				return err
 			}
-			if err = up110CleanupTypes(kv, topologyPrefix); err != nil {
+			if err := up110CleanupTypes(ctx, kv, topologyPrefix); err != nil {
 				return err
 			}
-			if err = up110CleanupNodes(kv, topologyPrefix); err != nil {
-				return err
-			}
-			return nil
+			return up110CleanupNodes(ctx, kv, topologyPrefix)
 		})
 	}
 
 	return errGroup.Wait()
 }
 
-func up110CleanupTypes(kv *api.KV, topoPrefix string) error {
-	types, _, err := kv.Keys(path.Join(topoPrefix, "types")+"/", "/", nil)
+func up110CleanupTypes(ctx context.Context, kv *Target, topoPrefix string) error {
+	types, err := kv.Keys(path.Join(topoPrefix, "types"))
 	if err != nil {
-		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+		return err
 	}
 	for _, typePrefix := range types {
 
-		if err = deleteElementsFromConsulPrefix(kv, typePrefix, "description", "name", "version"); err != nil {
+		if err = deleteElementsFromPrefix(ctx, kv, typePrefix, "description", "name", "version"); err != nil {
 			return err
 		}
 
-		if err = deleteElementsFromAllSubPathsOfPrefix(kv, path.Join(typePrefix, "artifacts"), "description", "name"); err != nil {
+		if err = deleteElementsFromAllSubPathsOfPrefix(ctx, kv, path.Join(typePrefix, "artifacts"), "description", "name"); err != nil {
 			return err
 		}
 
-		if err = deleteElementsFromAllSubPathsOfPrefix(kv, path.Join(typePrefix, "attributes"), "description", "name"); err != nil {
+		if err = deleteElementsFromAllSubPathsOfPrefix(ctx, kv, path.Join(typePrefix, "attributes"), "description", "name"); err != nil {
 			return err
 		}
-		if err = deleteElementsFromAllSubPathsOfPrefix(kv, path.Join(typePrefix, "properties"), "description", "name"); err != nil {
+		if err = deleteElementsFromAllSubPathsOfPrefix(ctx, kv, path.Join(typePrefix, "properties"), "description", "name"); err != nil {
 			return err
 		}
 
-		if err = deleteElementsFromAllSubPathsOfPrefix(kv, path.Join(typePrefix, "capabilities"), "description", "name"); err != nil {
+		if err = deleteElementsFromAllSubPathsOfPrefix(ctx, kv, path.Join(typePrefix, "capabilities"), "description", "name"); err != nil {
 			return err
 		}
 
-		if err = up110CleanupInterfaces(kv, typePrefix); err != nil {
+		if err = up110CleanupInterfaces(ctx, kv, typePrefix); err != nil {
 			return err
 		}
 
@@ -108,17 +114,17 @@ func up110CleanupTypes(kv *api.KV, topoPrefix string) error {
 	return nil
 }
 
-func up110CleanupNodes(kv *api.KV, topoPrefix string) error {
-	nodes, _, err := kv.Keys(path.Join(topoPrefix, "nodes")+"/", "/", nil)
+func up110CleanupNodes(ctx context.Context, kv *Target, topoPrefix string) error {
+	nodes, err := kv.Keys(path.Join(topoPrefix, "nodes"))
 	if err != nil {
-		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+		return err
 	}
 	for _, nodePrefix := range nodes {
 
-		if err = deleteElementsFromConsulPrefix(kv, nodePrefix, "description", "name"); err != nil {
+		if err = deleteElementsFromPrefix(ctx, kv, nodePrefix, "description", "name"); err != nil {
 			return err
 		}
-		if err = deleteElementsFromAllSubPathsOfPrefix(kv, path.Join(nodePrefix, "artifacts"), "description", "name"); err != nil {
+		if err = deleteElementsFromAllSubPathsOfPrefix(ctx, kv, path.Join(nodePrefix, "artifacts"), "description", "name"); err != nil {
 			return err
 		}
 
@@ -126,21 +132,21 @@ func up110CleanupNodes(kv *api.KV, topoPrefix string) error {
 	return nil
 }
 
-func up110CleanupInterfaces(kv *api.KV, elementPrefix string) error {
-	interfaces, _, err := kv.Keys(path.Join(elementPrefix, "interfaces")+"/", "/", nil)
+func up110CleanupInterfaces(ctx context.Context, kv *Target, elementPrefix string) error {
+	interfaces, err := kv.Keys(path.Join(elementPrefix, "interfaces"))
 	if err != nil {
-		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+		return err
 	}
 	for _, interfacePrefix := range interfaces {
 
 		// Global inputs
-		if err = deleteElementsFromAllSubPathsOfPrefix(kv, path.Join(interfacePrefix, "inputs"), "description", "name"); err != nil {
+		if err = deleteElementsFromAllSubPathsOfPrefix(ctx, kv, path.Join(interfacePrefix, "inputs"), "description", "name"); err != nil {
 			return err
 		}
 
-		operations, _, err := kv.Keys(interfacePrefix+"/", "/", nil)
+		operations, err := kv.Keys(interfacePrefix)
 		if err != nil {
-			return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+			return err
 		}
 
 		for _, opPrefix := range operations {
@@ -149,11 +155,11 @@ func up110CleanupInterfaces(kv *api.KV, elementPrefix string) error {
 			}
 
 			// operation inputs
-			if err = deleteElementsFromAllSubPathsOfPrefix(kv, path.Join(opPrefix, "inputs"), "description", "name"); err != nil {
+			if err = deleteElementsFromAllSubPathsOfPrefix(ctx, kv, path.Join(opPrefix, "inputs"), "description", "name"); err != nil {
 				return err
 			}
 
-			if err = deleteElementsFromConsulPrefix(kv, opPrefix, "description", "name", "implementation/description"); err != nil {
+			if err = deleteElementsFromPrefix(ctx, kv, opPrefix, "description", "name", "implementation/description"); err != nil {
 				return err
 			}
 
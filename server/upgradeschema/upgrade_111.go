@@ -16,27 +16,34 @@ package upgradeschema
 
 import (
 	"context"
-	"github.com/ystia/yorc/v4/config"
-	"golang.org/x/sync/errgroup"
 	"path"
 	"strings"
 
 	"github.com/hashicorp/consul/api"
-	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
 
+	"github.com/ystia/yorc/v4/config"
 	"github.com/ystia/yorc/v4/deployments/store"
 	"github.com/ystia/yorc/v4/helper/collections"
 	"github.com/ystia/yorc/v4/helper/consulutil"
 	"github.com/ystia/yorc/v4/log"
 )
 
-func getCommonsTypesList(kv *api.KV) ([]string, error) {
+func init() {
+	Register(Migration{
+		Version:     "1.1.1",
+		Description: "Remove commons types and imports from deployments topologies",
+		Up:          up111UpgradeCommonsTypes,
+	})
+}
+
+func getCommonsTypesList(kv *Target) ([]string, error) {
 	paths := store.GetCommonsTypesKeyPaths()
 	res := make([]string, 0)
 	for _, p := range paths {
-		keys, _, err := kv.Keys(path.Join(p, "types")+"/", "/", nil)
+		keys, err := kv.Keys(path.Join(p, "types"))
 		if err != nil {
-			return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+			return nil, err
 		}
 		for _, k := range keys {
 			res = append(res, path.Base(k))
@@ -46,55 +53,56 @@ func getCommonsTypesList(kv *api.KV) ([]string, error) {
 
 }
 
-func up111RemoveCommonsImports(kv *api.KV, deploymentPrefix string) error {
-	iKeys, _, err := kv.Keys(path.Join(deploymentPrefix, "topology/imports")+"/", "/", nil)
+func up111RemoveCommonsImports(ctx context.Context, kv *Target, deploymentPrefix string) error {
+	iKeys, err := kv.Keys(path.Join(deploymentPrefix, "topology/imports"))
 	if err != nil {
-		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+		return err
 	}
 	for _, importPrefix := range iKeys {
 		importName := path.Base(importPrefix)
 		if strings.HasPrefix(importName, "<") && strings.HasSuffix(importName, ">") {
-			_, err = kv.DeleteTree(importPrefix+"/", nil)
+			err = kv.DeleteTree(ctx, importPrefix)
 			if err != nil {
-				return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+				return err
 			}
 		}
 	}
 	return nil
 }
 
-func up111RemoveCommonsTypes(kv *api.KV, commons []string, deploymentPrefix string) error {
-	tKeys, _, err := kv.Keys(path.Join(deploymentPrefix, "topology/types")+"/", "/", nil)
+func up111RemoveCommonsTypes(ctx context.Context, kv *Target, commons []string, deploymentPrefix string) error {
+	tKeys, err := kv.Keys(path.Join(deploymentPrefix, "topology/types"))
 	if err != nil {
-		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+		return err
 	}
 	for _, tPrefix := range tKeys {
 		if collections.ContainsString(commons, path.Base(tPrefix)) {
 			log.Debugf("\tRemoving type %q from deployment %q", path.Base(tPrefix), path.Base(deploymentPrefix))
-			_, err := kv.DeleteTree(tPrefix+"/", nil)
-			if err != nil {
-				return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
-			}
+			err = kv.DeleteTree(ctx, tPrefix)
 		} else {
-			consulutil.StoreConsulKeyWithFlags(path.Join(tPrefix, ".existFlag"), nil, 0)
+			err = kv.Set(ctx, path.Join(tPrefix, ".existFlag"), Value{})
+		}
+		if err != nil {
+			return err
 		}
 	}
 	return nil
 }
-func up111UpgradeCommonsTypes(cfg config.Configuration, kv *api.KV) error {
+
+func up111UpgradeCommonsTypes(ctx context.Context, s *Session) error {
 	log.Print("\tRemoving commons types...")
+	kv := s.Consul()
 	commons, err := getCommonsTypesList(kv)
 	if err != nil {
 		return err
 	}
-	depKeys, _, err := kv.Keys(consulutil.DeploymentKVPrefix+"/", "/", nil)
+	depKeys, err := kv.Keys(consulutil.DeploymentKVPrefix)
 	if err != nil {
-		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+		return err
 	}
 
-	ctx := context.Background()
 	errGroup, ctx := errgroup.WithContext(ctx)
-	sem := make(chan struct{}, cfg.UpgradeConcurrencyLimit)
+	sem := make(chan struct{}, s.Config().UpgradeConcurrencyLimit)
 	for _, deploymentPrefix := range depKeys {
 		sem <- struct{}{}
 		depItem := deploymentPrefix
@@ -102,15 +110,11 @@ func up111UpgradeCommonsTypes(cfg config.Configuration, kv *api.KV) error {
 			defer func() {
 				<-sem
 			}()
-			err = up111RemoveCommonsTypes(kv, commons, depItem)
-			if err != nil {
-				return err
-			}
-			err = up111RemoveCommonsImports(kv, depItem)
+			err := up111RemoveCommonsTypes(ctx, kv, commons, depItem)
 			if err != nil {
 				return err
 			}
-			return nil
+			return up111RemoveCommonsImports(ctx, kv, depItem)
 		})
 	}
 	return errGroup.Wait()
@@ -118,6 +122,5 @@ func up111UpgradeCommonsTypes(cfg config.Configuration, kv *api.KV) error {
 
 // UpgradeTo111 allows to upgrade Consul schema from 1.1.0 to 1.1.1
 func UpgradeTo111(cfg config.Configuration, kv *api.KV, leaderch <-chan struct{}) error {
-	log.Print("Upgrading to database version 1.1.1...")
-	return up111UpgradeCommonsTypes(cfg, kv)
+	return apply(cfg, kv, Migration{Up: up111UpgradeCommonsTypes})
 }
@@ -15,72 +15,73 @@
 package upgradeschema
 
 import (
-	"github.com/ystia/yorc/v4/config"
+	"context"
 	"path"
 	"strings"
 
 	"github.com/hashicorp/consul/api"
-	"github.com/pkg/errors"
 
+	"github.com/ystia/yorc/v4/config"
 	"github.com/ystia/yorc/v4/helper/consulutil"
 	"github.com/ystia/yorc/v4/log"
 )
 
+func init() {
+	Register(Migration{
+		Version:     "1.1.2",
+		Description: "Move hosts of the legacy hosts pool into a location",
+		Up:          up112UpgradeHostsPoolStorage,
+	})
+}
+
 // UpgradeTo112 allows to upgrade Consul schema from 1.1.1 to 1.1.2
 func UpgradeTo112(cfg config.Configuration, kv *api.KV, leaderch <-chan struct{}) error {
-	log.Print("Upgrading to database version 1.1.12..")
-	return up112UpgradeHostsPoolStorage(kv)
+	return apply(cfg, kv, Migration{Up: up112UpgradeHostsPoolStorage})
 }
 
-func up112UpgradeHostsPoolStorage(kv *api.KV) error {
+func up112UpgradeHostsPoolStorage(ctx context.Context, s *Session) error {
 	log.Print("\tUpgrade hosts pool storage...")
+	kv := s.Consul()
 	defaultLocationName := "hostsPool111"
 	// Check the schema is the previous one by retrieving the host status
-	keys, _, err := kv.Keys(consulutil.HostsPoolPrefix+"/", "/", nil)
+	keys, err := kv.Keys(consulutil.HostsPoolPrefix)
 	if err != nil {
-		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+		return err
 	}
 	for _, k := range keys {
 		log.Debugf("check key=%q", k)
-		kvp, _, err := kv.Get(path.Join(k, "status"), nil)
+		status, err := kv.Get(path.Join(k, "status"))
 		if err != nil {
-			return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+			return err
 		}
-		if kvp == nil || len(kvp.Value) == 0 {
+		if status == nil || len(status.Data) == 0 {
 			log.Debugf("No host status retrieved for this key. We assume its a hosts pool location with up-to-date schema.")
 			continue
 		}
 
 		log.Debugf("Found a host from legacy pool: it will be associated to location name:%q. This name can be changed with the CLI/REST API locations.", defaultLocationName)
 
-		err = moveKeyToNewSchema(kv, k, defaultLocationName)
+		err = moveKeyToNewSchema(ctx, kv, k, defaultLocationName)
 		if err != nil {
-			return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+			return err
 		}
 	}
 	return nil
 }
 
-func moveKeyToNewSchema(kv *api.KV, key, defaultLocationName string) error {
-	kvps, _, err := kv.List(key+"/", nil)
-	if err != nil {
-		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
-	}
-	for _, kvp := range kvps {
-		if kvp != nil && len(kvp.Value) > 0 {
-			newKey := path.Join(consulutil.HostsPoolPrefix, defaultLocationName, strings.TrimPrefix(kvp.Key, consulutil.HostsPoolPrefix))
-			log.Debugf("Create new key: %q", newKey)
-			err = consulutil.StoreConsulKey(newKey, kvp.Value)
-			if err != nil {
-				return errors.Wrapf(err, "failed to store hosts pool key with location:%q", defaultLocationName)
-			}
+func moveKeyToNewSchema(ctx context.Context, kv *Target, key, defaultLocationName string) error {
+	err := kv.Walk(key, func(k string, v *Value) error {
+		if len(v.Data) == 0 {
+			return nil
 		}
+		newKey := path.Join(consulutil.HostsPoolPrefix, defaultLocationName, strings.TrimPrefix(k, consulutil.HostsPoolPrefix))
+		log.Debugf("Create new key: %q", newKey)
+		return kv.Set(ctx, newKey, Value{Data: v.Data})
+	})
+	if err != nil {
+		return err
 	}
 
 	log.Debugf("Delete old key tree: %q", key)
-	_, err = kv.DeleteTree(key, nil)
-	if err != nil {
-		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
-	}
-	return nil
+	return kv.DeleteTree(ctx, key)
 }
@@ -33,6 +33,14 @@ import (
 	"path/filepath"
 )
 
+func init() {
+	Register(Migration{
+		Version:     "1.2.0",
+		Description: "Store again deployments topologies and commons types using the refactored deployments storage",
+		Legacy:      UpgradeTo120,
+	})
+}
+
 // UpgradeTo120 allows to upgrade Consul schema from 1.1.1 to 1.2.0
 func UpgradeTo120(cfg config.Configuration, kv *api.KV, leaderch <-chan struct{}) error {
 	log.Print("Upgrading to database version 1.2.0")
@@ -21,6 +21,14 @@ import (
 	"github.com/ystia/yorc/v4/log"
 )
 
+func init() {
+	Register(Migration{
+		Version:     "1.3.0",
+		Description: "Store again deployments topologies and commons types into the deployments store",
+		Legacy:      UpgradeTo130,
+	})
+}
+
 // UpgradeTo130 allows to upgrade Consul schema from 1.2.0 to 1.3.0
 func UpgradeTo130(cfg config.Configuration, kv *api.KV, leaderch <-chan struct{}) error {
 	log.Print("Upgrading to database version 1.3.0")
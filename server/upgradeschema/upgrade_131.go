@@ -19,40 +19,50 @@ import (
 	"path"
 
 	"github.com/hashicorp/consul/api"
-	"github.com/pkg/errors"
 
 	"github.com/ystia/yorc/v4/config"
 	"github.com/ystia/yorc/v4/helper/consulutil"
-	"github.com/ystia/yorc/v4/log"
 )
 
+func init() {
+	Register(Migration{
+		Version:     "1.3.1",
+		Description: "Reference tasks under their deployment",
+		Up:          up131,
+	})
+}
+
 // UpgradeTo131 allows to upgrade Consul schema from 1.3.0 to 1.3.1
 func UpgradeTo131(cfg config.Configuration, kv *api.KV, leaderch <-chan struct{}) error {
-	log.Print("Upgrading to database version 1.3.1")
+	return apply(cfg, kv, Migration{Up: up131})
+}
 
-	tasks, _, err := kv.Keys(consulutil.TasksPrefix+"/", "/", nil)
+func up131(ctx context.Context, s *Session) error {
+	kv := s.Consul()
+	tasks, err := kv.Keys(consulutil.TasksPrefix)
 	if err != nil {
-		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+		return err
 	}
-	ctx, cancelFn := context.WithCancel(context.Background())
-	defer cancelFn()
-	_, errGrp, store := consulutil.WithContext(ctx)
 	for _, taskPath := range tasks {
 		taskID := path.Base(taskPath)
-		kvp, _, err := kv.Get(path.Join(taskPath, "targetId"), nil)
+		target, err := kv.Get(path.Join(taskPath, "targetId"))
 		if err != nil {
-			return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+			return err
+		}
+		if target == nil || len(target.Data) == 0 {
+			continue
 		}
-		if kvp != nil && len(kvp.Value) != 0 {
-			deploymentID := string(kvp.Value)
-			kvp, _, err = kv.Get(path.Join(consulutil.DeploymentKVPrefix, deploymentID, "status"), nil)
+		deploymentID := string(target.Data)
+		status, err := kv.Get(path.Join(consulutil.DeploymentKVPrefix, deploymentID, "status"))
+		if err != nil {
+			return err
+		}
+		if status != nil && len(status.Data) != 0 {
+			err = kv.Set(ctx, path.Join(consulutil.DeploymentKVPrefix, deploymentID, "tasks", taskID), Value{Flags: 1})
 			if err != nil {
-				return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
-			}
-			if kvp != nil && len(kvp.Value) != 0 {
-				store.StoreConsulKeyWithFlags(path.Join(consulutil.DeploymentKVPrefix, deploymentID, "tasks", taskID), nil, 1)
+				return err
 			}
 		}
 	}
-	return errors.Wrap(errGrp.Wait(), "failed to store task key under deployment")
+	return nil
 }
@@ -32,6 +32,14 @@ import (
 	"github.com/ystia/yorc/v4/log"
 )
 
+func init() {
+	Register(Migration{
+		Version:     "1.0.0",
+		Description: "Convert events to the JSON format",
+		Legacy:      UpgradeFromPre31,
+	})
+}
+
 // UpgradeFromPre31 allows to upgrade Consul schema from schema version before 1.0.0 (pre 3.1 yorc version)
 func UpgradeFromPre31(cfg config.Configuration, kv *api.KV, leaderch <-chan struct{}) error {
 	log.Print("Preparing upgrade database schema to 1.0.0 schema version")
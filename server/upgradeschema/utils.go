@@ -15,41 +15,29 @@
 package upgradeschema
 
 import (
+	"context"
 	"path"
-
-	"github.com/hashicorp/consul/api"
-	"github.com/pkg/errors"
-
-	"github.com/ystia/yorc/v4/helper/consulutil"
 )
 
-func deleteElementFromConsulPrefix(kv *api.KV, prefix, element string) error {
-	_, err := kv.Delete(path.Join(prefix, element), nil)
-	return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
-}
-
-func deleteElementsFromConsulPrefix(kv *api.KV, prefix string, elements ...string) error {
-	if len(elements) == 0 {
-		return nil
-	}
+func deleteElementsFromPrefix(ctx context.Context, t *Target, prefix string, elements ...string) error {
 	for _, element := range elements {
-		if err := deleteElementFromConsulPrefix(kv, prefix, element); err != nil {
+		if err := t.Delete(ctx, path.Join(prefix, element)); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func deleteElementsFromAllSubPathsOfPrefix(kv *api.KV, prefix string, elements ...string) error {
+func deleteElementsFromAllSubPathsOfPrefix(ctx context.Context, t *Target, prefix string, elements ...string) error {
 	if len(elements) == 0 {
 		return nil
 	}
-	sub, _, err := kv.Keys(prefix+"/", "/", nil)
+	sub, err := t.Keys(prefix)
 	if err != nil {
-		return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+		return err
 	}
 	for _, subPath := range sub {
-		if err = deleteElementsFromConsulPrefix(kv, subPath, elements...); err != nil {
+		if err = deleteElementsFromPrefix(ctx, t, subPath, elements...); err != nil {
 			return err
 		}
 	}
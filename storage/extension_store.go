@@ -0,0 +1,145 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/storage/store"
+)
+
+var storeBuilderResolver func(implementation string) (store.Builder, error)
+
+// SetStoreBuilderResolver sets the function used to resolve store implementations that are not builtin in Yorc.
+//
+// This allows stores implementations to be provided by plugins. It is typically set by the registry.
+func SetStoreBuilderResolver(resolver func(implementation string) (store.Builder, error)) {
+	storeBuilderResolver = resolver
+}
+
+// extensionStore is a store whose implementation is provided by an extension like a plugin.
+//
+// Plugins are loaded before stores, so the store implementation is checked against the
+// registered store builders when stores are loaded. The actual store is then created at
+// first use from the builder resolved for its implementation, and it is created again
+// if this builder changes, typically when a plugin restarts.
+type extensionStore struct {
+	cfg         config.Configuration
+	storeConfig config.Store
+	lock        sync.Mutex
+	builder     store.Builder
+	store       store.Store
+}
+
+func newExtensionStore(cfg config.Configuration, storeConfig config.Store) *extensionStore {
+	return &extensionStore{cfg: cfg, storeConfig: storeConfig}
+}
+
+func sameBuilder(b1, b2 store.Builder) bool {
+	t := reflect.TypeOf(b1)
+	return t != nil && t == reflect.TypeOf(b2) && t.Comparable() && b1 == b2
+}
+
+func (s *extensionStore) get() (store.Store, error) {
+	if storeBuilderResolver == nil {
+		return nil, errors.Errorf("unknown store implementation %q for store %q", s.storeConfig.Implementation, s.storeConfig.Name)
+	}
+	builder, err := storeBuilderResolver(s.storeConfig.Implementation)
+	if err != nil {
+		return nil, errors.Wrapf(err, "store implementation %q for store %q is not available", s.storeConfig.Implementation, s.storeConfig.Name)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.store == nil || !sameBuilder(builder, s.builder) {
+		st, err := builder.NewStore(s.cfg, s.storeConfig)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create store %q with implementation %q", s.storeConfig.Name, s.storeConfig.Implementation)
+		}
+		s.store = st
+		s.builder = builder
+	}
+	return s.store, nil
+}
+
+func (s *extensionStore) Set(ctx context.Context, k string, v interface{}) error {
+	st, err := s.get()
+	if err != nil {
+		return err
+	}
+	return st.Set(ctx, k, v)
+}
+
+func (s *extensionStore) SetCollection(ctx context.Context, keyValues []store.KeyValueIn) error {
+	st, err := s.get()
+	if err != nil {
+		return err
+	}
+	return st.SetCollection(ctx, keyValues)
+}
+
+func (s *extensionStore) Get(k string, v interface{}) (bool, error) {
+	st, err := s.get()
+	if err != nil {
+		return false, err
+	}
+	return st.Get(k, v)
+}
+
+func (s *extensionStore) Exist(k string) (bool, error) {
+	st, err := s.get()
+	if err != nil {
+		return false, err
+	}
+	return st.Exist(k)
+}
+
+func (s *extensionStore) Keys(k string) ([]string, error) {
+	st, err := s.get()
+	if err != nil {
+		return nil, err
+	}
+	return st.Keys(k)
+}
+
+func (s *extensionStore) Delete(ctx context.Context, k string, recursive bool) error {
+	st, err := s.get()
+	if err != nil {
+		return err
+	}
+	return st.Delete(ctx, k, recursive)
+}
+
+func (s *extensionStore) GetLastModifyIndex(k string) (uint64, error) {
+	st, err := s.get()
+	if err != nil {
+		return 0, err
+	}
+	return st.GetLastModifyIndex(k)
+}
+
+func (s *extensionStore) List(ctx context.Context, k string, waitIndex uint64, timeout time.Duration) ([]store.KeyValueOut, uint64, error) {
+	st, err := s.get()
+	if err != nil {
+		return nil, 0, err
+	}
+	return st.List(ctx, k, waitIndex, timeout)
+}
@@ -0,0 +1,104 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/storage/store"
+)
+
+type testExtensionStore struct {
+	store.Store
+	storeConfig config.Store
+}
+
+func (s *testExtensionStore) Exist(k string) (bool, error) {
+	return k == s.storeConfig.Name, nil
+}
+
+type testStoreBuilder struct {
+	created int
+}
+
+func (b *testStoreBuilder) NewStore(cfg config.Configuration, storeConfig config.Store) (store.Store, error) {
+	b.created++
+	return &testExtensionStore{storeConfig: storeConfig}, nil
+}
+
+func TestExtensionStore(t *testing.T) {
+	defer SetStoreBuilderResolver(storeBuilderResolver)
+
+	s := newExtensionStore(config.Configuration{}, config.Store{Name: "myStore", Implementation: "myImpl"})
+
+	SetStoreBuilderResolver(nil)
+	_, err := s.Exist("myStore")
+	require.Error(t, err)
+
+	SetStoreBuilderResolver(func(implementation string) (store.Builder, error) {
+		return nil, errors.Errorf("Unknown store implementation: %q", implementation)
+	})
+	_, err = s.Exist("myStore")
+	require.Error(t, err)
+
+	b1 := &testStoreBuilder{}
+	builder := b1
+	SetStoreBuilderResolver(func(implementation string) (store.Builder, error) {
+		require.Equal(t, "myImpl", implementation)
+		return builder, nil
+	})
+	exist, err := s.Exist("myStore")
+	require.NoError(t, err)
+	require.True(t, exist)
+	_, err = s.Exist("other")
+	require.NoError(t, err)
+	require.Equal(t, 1, b1.created, "store should be created once")
+
+	// Builder changed, typically when a plugin restarts
+	b2 := &testStoreBuilder{}
+	builder = b2
+	exist, err = s.Exist("myStore")
+	require.NoError(t, err)
+	require.True(t, exist)
+	require.Equal(t, 1, b1.created)
+	require.Equal(t, 1, b2.created)
+}
+
+func TestCreateExtensionStoreImpl(t *testing.T) {
+	defer SetStoreBuilderResolver(storeBuilderResolver)
+	storeConfig := config.Store{Name: "myStore", Implementation: "myImpl"}
+
+	SetStoreBuilderResolver(nil)
+	s, err := createStoreImpl(config.Configuration{}, storeConfig)
+	require.NoError(t, err)
+	require.Nil(t, s, "unknown implementation should be ignored when no extension is supported")
+
+	SetStoreBuilderResolver(func(implementation string) (store.Builder, error) {
+		if implementation != "myImpl" {
+			return nil, errors.Errorf("Unknown store implementation: %q", implementation)
+		}
+		return &testStoreBuilder{}, nil
+	})
+	s, err = createStoreImpl(config.Configuration{}, storeConfig)
+	require.NoError(t, err)
+	require.IsType(t, &extensionStore{}, s)
+
+	_, err = createStoreImpl(config.Configuration{}, config.Store{Name: "myStore", Implementation: "myImlp"})
+	require.Error(t, err, "implementation not provided by any plugin should be rejected")
+}
@@ -17,6 +17,8 @@ package store
 import (
 	"context"
 	"time"
+
+	"github.com/ystia/yorc/v4/config"
 )
 
 // Store is an abstraction for different key-value store implementations.
@@ -69,3 +71,10 @@ type Store interface {
 	// The lastIndex is returned to perform new blocking query.
 	List(ctx context.Context, k string, waitIndex uint64, timeout time.Duration) ([]KeyValueOut, uint64, error)
 }
+
+// Builder allows to create Store implementations which are not builtin in Yorc like stores provided by plugins.
+type Builder interface {
+	// NewStore creates a store for the given store configuration.
+	// The store implementation name is provided by storeConfig.Implementation.
+	NewStore(cfg config.Configuration, storeConfig config.Store) (Store, error)
+}
@@ -165,6 +165,10 @@ func LoadStores(cfg config.Configuration) error {
 
 					// Handle Consul data migration for log/event stores
 					if configStore.MigrateDataFromConsul && init && configStore.Implementation != consulStoreImpl {
+						if _, ok := storeImpl.(*extensionStore); ok {
+							log.Printf("[WARNING] Data migration from Consul is not supported for store with name:%q as its implementation %q is not builtin.", configStore.Name, configStore.Implementation)
+							continue
+						}
 						err = migrateData(configStore.Name, st, stores[st])
 						if err != nil {
 							return
@@ -370,7 +374,15 @@ func createStoreImpl(cfg config.Configuration, configStore config.Store) (store.
 			return nil, err
 		}
 	default:
-		log.Printf("[WARNING] unknown store implementation:%q. This will be ignored.", impl)
+		if storeBuilderResolver == nil {
+			log.Printf("[WARNING] unknown store implementation:%q. This will be ignored.", impl)
+			break
+		}
+		// Implementation may be provided by a plugin
+		if _, err = storeBuilderResolver(configStore.Implementation); err != nil {
+			return nil, errors.Wrapf(err, "failed to load store %q", configStore.Name)
+		}
+		storeImpl = newExtensionStore(cfg, configStore)
 	}
 	return storeImpl, nil
 }
@@ -421,3 +433,15 @@ func GetStore(tType types.StoreType) store.Store {
 	}
 	return store
 }
+
+// OverrideStore replaces the store implementation related to a defined store type.
+//
+// This is typically used by plugins to forward data to the Yorc server instead of
+// writing them directly into the store backend. It should be called once stores
+// are loaded.
+func OverrideStore(tType types.StoreType, s store.Store) {
+	if stores == nil {
+		stores = make(map[types.StoreType]store.Store)
+	}
+	stores[tType] = s
+}
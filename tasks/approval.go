@@ -0,0 +1,228 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+)
+
+// TaskApproval describes the approvals of a task in status PENDING_APPROVAL
+type TaskApproval struct {
+	// Policy is the name of the approval policy that applies to the task
+	Policy string `json:"policy"`
+	// Required is the number of distinct approvers required before the task is executed
+	Required       int            `json:"required"`
+	ExpirationDate time.Time      `json:"expiration_date"`
+	Approvers      []TaskApprover `json:"approvers,omitempty"`
+}
+
+// TaskApprover is an approval given to a task
+type TaskApprover struct {
+	Name string    `json:"name"`
+	Date time.Time `json:"date"`
+}
+
+type taskApprovalError struct {
+	taskID  string
+	message string
+}
+
+func (e taskApprovalError) Error() string {
+	return fmt.Sprintf("Can't approve task %q: %s", e.taskID, e.message)
+}
+
+// IsTaskApprovalError checks if an error is due to an approval that is not allowed
+// like an approval of a task not pending approval or a second approval by the same approver
+func IsTaskApprovalError(err error) bool {
+	_, ok := errors.Cause(err).(taskApprovalError)
+	return ok
+}
+
+func approvalPath(taskID string) string {
+	return path.Join(consulutil.TasksPrefix, taskID, "approval")
+}
+
+// ApprovalOperations returns the operations storing the approvals requirements of a task
+func ApprovalOperations(taskID, policy string, required int, expirationDate time.Time) (api.KVTxnOps, error) {
+	date, err := expirationDate.MarshalBinary()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to generate task approval expiration date")
+	}
+	p := approvalPath(taskID)
+	return api.KVTxnOps{
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(p, "policy"),
+			Value: []byte(policy),
+		},
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(p, "required"),
+			Value: []byte(strconv.Itoa(required)),
+		},
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(p, "expirationDate"),
+			Value: date,
+		},
+	}, nil
+}
+
+// GetTaskApproval returns the approvals of a task or nil if the task doesn't require approvals
+func GetTaskApproval(taskID string) (*TaskApproval, error) {
+	p := approvalPath(taskID)
+	kvs, err := consulutil.List(p + "/")
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if len(kvs) == 0 {
+		return nil, nil
+	}
+	approval := &TaskApproval{Policy: string(kvs[path.Join(p, "policy")])}
+	approval.Required, err = strconv.Atoi(string(kvs[path.Join(p, "required")]))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid number of required approvals for task %q", taskID)
+	}
+	err = approval.ExpirationDate.UnmarshalBinary(kvs[path.Join(p, "expirationDate")])
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid approval expiration date for task %q", taskID)
+	}
+	approversPrefix := path.Join(p, "approvers") + "/"
+	for k, v := range kvs {
+		if path.Dir(k)+"/" != approversPrefix {
+			continue
+		}
+		approver := TaskApprover{Name: path.Base(k)}
+		err = approver.Date.UnmarshalBinary(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid approval date of approver %q for task %q", approver.Name, taskID)
+		}
+		approval.Approvers = append(approval.Approvers, approver)
+	}
+	sort.Slice(approval.Approvers, func(i, j int) bool {
+		return approval.Approvers[i].Date.Before(approval.Approvers[j].Date)
+	})
+	return approval, nil
+}
+
+// AddTaskApprover records the approval of a task in status PENDING_APPROVAL by a given approver
+//
+// The approval is refused if the task is not pending approval, if its approval expired or if
+// the approver already approved it. The updated approvals of the task are returned.
+func AddTaskApprover(taskID, approver string, now time.Time) (*TaskApproval, error) {
+	if approver == "" || path.Base(approver) != approver {
+		return nil, errors.WithStack(taskApprovalError{taskID: taskID, message: fmt.Sprintf("invalid approver name %q", approver)})
+	}
+	status, err := GetTaskStatus(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if status != TaskStatusPENDINGAPPROVAL {
+		return nil, errors.WithStack(taskApprovalError{taskID: taskID, message: fmt.Sprintf("task status is %q", status.String())})
+	}
+	approval, err := GetTaskApproval(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if approval == nil {
+		return nil, errors.Errorf("Missing approval requirements for task %q", taskID)
+	}
+	if !now.Before(approval.ExpirationDate) {
+		return nil, errors.WithStack(taskApprovalError{taskID: taskID, message: "approval expired"})
+	}
+	date, err := now.MarshalBinary()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to generate task approval date")
+	}
+	// A ModifyIndex of 0 means that the approver should not already exist
+	kvp := &api.KVPair{Key: path.Join(approvalPath(taskID), "approvers", approver), Value: date}
+	set, _, err := consulutil.GetKV().CAS(kvp, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if !set {
+		return nil, errors.WithStack(taskApprovalError{taskID: taskID, message: fmt.Sprintf("task already approved by %q", approver)})
+	}
+	approval.Approvers = append(approval.Approvers, TaskApprover{Name: approver, Date: now})
+	return approval, nil
+}
+
+// UpdatePendingTaskStatus atomically changes the status of a task in status PENDING_APPROVAL
+//
+// It returns false if the task is not pending approval anymore, typically because it was
+// approved, canceled or expired concurrently.
+func UpdatePendingTaskStatus(taskID string, status TaskStatus) (bool, error) {
+	statusPath := path.Join(consulutil.TasksPrefix, taskID, "status")
+	kvp, _, err := consulutil.GetKV().Get(statusPath, nil)
+	if err != nil {
+		return false, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp == nil || string(kvp.Value) != strconv.Itoa(int(TaskStatusPENDINGAPPROVAL)) {
+		return false, nil
+	}
+	kvp.Value = []byte(strconv.Itoa(int(status)))
+	set, _, err := consulutil.GetKV().CAS(kvp, nil)
+	return set, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+}
+
+// CancelPendingTask cancels a task in status PENDING_APPROVAL with the given reason
+//
+// It returns false if the task is not pending approval anymore.
+func CancelPendingTask(ctx context.Context, taskID, reason string) (bool, error) {
+	canceled, err := UpdatePendingTaskStatus(taskID, TaskStatusCANCELED)
+	if err != nil || !canceled {
+		return false, err
+	}
+	err = SetTaskErrorMessage(taskID, reason)
+	if err != nil {
+		return true, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	err = SetTaskEndDate(taskID, time.Now())
+	if err != nil {
+		return true, err
+	}
+	targetID, err := GetTaskTarget(taskID)
+	if err != nil {
+		return true, err
+	}
+	taskType, err := GetTaskType(taskID)
+	if err != nil {
+		return true, err
+	}
+	var workflowName string
+	if IsWorkflowTask(taskType) {
+		workflowName, err = GetTaskData(taskID, "workflowName")
+		if err != nil {
+			return true, err
+		}
+	}
+	log.Debugf("Task %q pending approval canceled: %s", taskID, reason)
+	ctx = events.AddLogOptionalFields(ctx, events.LogOptionalFields{events.WorkFlowID: workflowName, events.ExecutionID: taskID})
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, targetID).Registerf("Task %q canceled: %s", taskID, reason)
+	EmitTaskEventWithContextualLogs(ctx, targetID, taskID, taskType, workflowName, TaskStatusCANCELED.String())
+	return true, nil
+}
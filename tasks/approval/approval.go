@@ -0,0 +1,123 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package approval cancels the tasks waiting for approvals that were not approved before their approval expiration date.
+//
+// Only the leader Yorc server of the cluster checks the approvals expiration dates.
+package approval
+
+import (
+	"context"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+
+	"github.com/ystia/yorc/v4/cluster"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/tasks"
+)
+
+// checkInterval is the interval between two checks of the approvals expiration dates
+const checkInterval = time.Minute
+
+var defaultJanitor *janitor
+
+// janitor periodically cancels tasks whose approval expired
+type janitor struct {
+	cc         *api.Client
+	serviceKey string
+	chShutdown chan struct{}
+	lock       sync.Mutex
+	chStop     chan struct{}
+}
+
+// Start starts to watch the leader election of the tasks approval janitor,
+// approvals expiration dates are checked while this server is the leader
+func Start(cc *api.Client) {
+	defaultJanitor = &janitor{
+		cc:         cc,
+		serviceKey: path.Join(consulutil.YorcServicePrefix, "/tasks/approval/leader"),
+		chShutdown: make(chan struct{}),
+	}
+	go cluster.WatchLeaderElection(cc, cluster.RoleTasksApprovalLeader, defaultJanitor.serviceKey, defaultJanitor.chShutdown, defaultJanitor.startChecks, defaultJanitor.stopChecks)
+}
+
+// Stop stops checking approvals expiration dates
+func Stop() {
+	defaultJanitor.stopChecks()
+	close(defaultJanitor.chShutdown)
+}
+
+func (j *janitor) startChecks() {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if j.chStop != nil {
+		return
+	}
+	chStop := make(chan struct{})
+	j.chStop = chStop
+	go func() {
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+		for {
+			checkPendingTasks(context.Background(), time.Now())
+			select {
+			case <-chStop:
+				return
+			case <-j.chShutdown:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (j *janitor) stopChecks() {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if j.chStop != nil {
+		close(j.chStop)
+		j.chStop = nil
+	}
+}
+
+func checkPendingTasks(ctx context.Context, now time.Time) {
+	taskIDs, err := tasks.GetTasksIDs()
+	if err != nil {
+		log.Printf("[WARN] Failed to list tasks to check their approval: %v", err)
+		return
+	}
+	for _, taskID := range taskIDs {
+		err = checkPendingTask(ctx, taskID, now)
+		if err != nil && !tasks.IsTaskNotFoundError(err) {
+			log.Printf("[WARN] Failed to check approval of task %q: %v", taskID, err)
+		}
+	}
+}
+
+func checkPendingTask(ctx context.Context, taskID string, now time.Time) error {
+	status, err := tasks.GetTaskStatus(taskID)
+	if err != nil || status != tasks.TaskStatusPENDINGAPPROVAL {
+		return err
+	}
+	approval, err := tasks.GetTaskApproval(taskID)
+	if err != nil || approval == nil || now.Before(approval.ExpirationDate) {
+		return err
+	}
+	_, err = tasks.CancelPendingTask(ctx, taskID, "approval expired")
+	return err
+}
@@ -0,0 +1,115 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tasks
+
+import (
+	"context"
+	"path"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/helper/consulutil"
+)
+
+func storePendingTask(t *testing.T, taskID, targetID string, required int, expirationDate time.Time) {
+	t.Helper()
+	ops, err := ApprovalOperations(taskID, "prod", required, expirationDate)
+	require.NoError(t, err)
+	taskPath := path.Join(consulutil.TasksPrefix, taskID)
+	for k, v := range map[string]string{
+		"targetId":          targetID,
+		"status":            strconv.Itoa(int(TaskStatusPENDINGAPPROVAL)),
+		"type":              strconv.Itoa(int(TaskTypeUnDeploy)),
+		"data/workflowName": "uninstall",
+	} {
+		require.NoError(t, consulutil.StoreConsulKeyAsString(path.Join(taskPath, k), v))
+	}
+	require.NoError(t, StoreOperations(taskID, ops))
+}
+
+func testTaskApproval(t *testing.T) {
+	now := time.Now()
+	storePendingTask(t, "tApproval", "idApproval", 2, now.Add(time.Hour))
+
+	approval, err := GetTaskApproval("tApproval")
+	require.NoError(t, err)
+	require.NotNil(t, approval)
+	require.Equal(t, "prod", approval.Policy)
+	require.Equal(t, 2, approval.Required)
+	require.True(t, approval.ExpirationDate.Equal(now.Add(time.Hour)))
+	require.Len(t, approval.Approvers, 0)
+
+	approval, err = AddTaskApprover("tApproval", "alice", now)
+	require.NoError(t, err)
+	require.Len(t, approval.Approvers, 1)
+
+	_, err = AddTaskApprover("tApproval", "alice", now.Add(time.Second))
+	require.Error(t, err)
+	require.True(t, IsTaskApprovalError(err), "unexpected error %+v", err)
+
+	_, err = AddTaskApprover("tApproval", "bob", now.Add(2*time.Hour))
+	require.True(t, IsTaskApprovalError(err), "expected an expired approval error, got %+v", err)
+
+	_, err = AddTaskApprover("tApproval", "bob/eve", now)
+	require.True(t, IsTaskApprovalError(err), "expected an invalid approver error, got %+v", err)
+
+	approval, err = AddTaskApprover("tApproval", "bob", now.Add(time.Second))
+	require.NoError(t, err)
+	require.Len(t, approval.Approvers, 2)
+
+	approval, err = GetTaskApproval("tApproval")
+	require.NoError(t, err)
+	require.Equal(t, "alice", approval.Approvers[0].Name)
+	require.Equal(t, "bob", approval.Approvers[1].Name)
+
+	updated, err := UpdatePendingTaskStatus("tApproval", TaskStatusINITIAL)
+	require.NoError(t, err)
+	require.True(t, updated)
+	updated, err = UpdatePendingTaskStatus("tApproval", TaskStatusINITIAL)
+	require.NoError(t, err)
+	require.False(t, updated)
+
+	_, err = AddTaskApprover("tApproval", "carol", now)
+	require.True(t, IsTaskApprovalError(err), "expected a not pending task error, got %+v", err)
+
+	approval, err = GetTaskApproval("t1")
+	require.NoError(t, err)
+	require.Nil(t, approval)
+}
+
+func testCancelPendingTask(t *testing.T) {
+	storePendingTask(t, "tPendingCancel", "idApproval", 1, time.Now().Add(time.Hour))
+
+	canceled, err := CancelPendingTask(context.Background(), "tPendingCancel", "approval expired")
+	require.NoError(t, err)
+	require.True(t, canceled)
+
+	status, err := GetTaskStatus("tPendingCancel")
+	require.NoError(t, err)
+	require.Equal(t, TaskStatusCANCELED, status)
+	msg, err := GetTaskErrorMessage("tPendingCancel")
+	require.NoError(t, err)
+	require.Equal(t, "approval expired", msg)
+	endDate, err := GetTaskEndDate("tPendingCancel")
+	require.NoError(t, err)
+	require.False(t, endDate.IsZero())
+
+	canceled, err = CancelPendingTask(context.Background(), "tPendingCancel", "approval expired")
+	require.NoError(t, err)
+	require.False(t, canceled)
+}
@@ -0,0 +1,160 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/helper/collections"
+	"github.com/ystia/yorc/v4/helper/labelsutil"
+	"github.com/ystia/yorc/v4/tasks"
+)
+
+// approvalPolicy returns the first approval policy matching a task of the given type on the given
+// deployment or nil if the task doesn't require approvals
+func (c *Collector) approvalPolicy(ctx context.Context, targetID string, taskType tasks.TaskType) (*config.TaskApprovalPolicy, error) {
+	if len(c.approval.Policies) == 0 || !tasks.IsDeploymentRelatedTask(taskType) {
+		return nil, nil
+	}
+	var labels map[string]string
+	for i := range c.approval.Policies {
+		policy := &c.approval.Policies[i]
+		if !collections.ContainsString(policy.TaskTypes, taskType.String()) {
+			continue
+		}
+		if labels == nil {
+			var err error
+			labels, err = deployments.GetDeploymentLabels(ctx, targetID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		ok, err := policyMatchesLabels(policy, labels)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return policy, nil
+		}
+	}
+	return nil, nil
+}
+
+func policyMatchesLabels(policy *config.TaskApprovalPolicy, labels map[string]string) (bool, error) {
+	filters := make([]labelsutil.Filter, len(policy.Selector))
+	for i, s := range policy.Selector {
+		var err error
+		filters[i], err = labelsutil.CreateFilter(s)
+		if err != nil {
+			return false, errors.Wrapf(err, "invalid selector %q for tasks approval policy %q", s, policy.Name)
+		}
+	}
+	ok, _ := labelsutil.MatchesAll(labels, filters...)
+	return ok, nil
+}
+
+func (c *Collector) approvalTimeout(policy *config.TaskApprovalPolicy) time.Duration {
+	if policy.Timeout > 0 {
+		return policy.Timeout
+	}
+	if c.approval.Timeout > 0 {
+		return c.approval.Timeout
+	}
+	return config.DefaultTasksApprovalTimeout
+}
+
+func requiredApprovals(policy *config.TaskApprovalPolicy) int {
+	if policy.Approvals <= 0 {
+		return 1
+	}
+	return policy.Approvals
+}
+
+// registerPendingTask stores a task waiting for approvals, its executions are registered once it is approved
+func (c *Collector) registerPendingTask(ctx context.Context, operations api.KVTxnOps, policy *config.TaskApprovalPolicy, taskType tasks.TaskType, taskID, targetID string) error {
+	required := requiredApprovals(policy)
+	expirationDate := time.Now().Add(c.approvalTimeout(policy))
+	approvalOps, err := tasks.ApprovalOperations(taskID, policy.Name, required, expirationDate)
+	if err != nil {
+		return err
+	}
+	err = tasks.StoreOperations(taskID, append(operations, approvalOps...))
+	if err != nil {
+		return errors.Wrapf(err, "Failed to register task with targetID:%q, taskType:%q due to error %s",
+			targetID, taskType.String(), err.Error())
+	}
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, targetID).Registerf(
+		"Task %q of type %q requires %d approval(s) according to policy %q before %s",
+		taskID, taskType.String(), required, policy.Name, expirationDate.Format(time.RFC3339))
+	return nil
+}
+
+// ApproveTask records the approval of a task in status PENDING_APPROVAL by the given approver
+//
+// Once the task received the required number of approvals its status is set to INITIAL and its
+// executions are registered so that it is dispatched to workers.
+func (c *Collector) ApproveTask(ctx context.Context, taskID, approver string) (*tasks.TaskApproval, error) {
+	approval, err := tasks.AddTaskApprover(taskID, approver, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	targetID, err := tasks.GetTaskTarget(taskID)
+	if err != nil {
+		return nil, err
+	}
+	taskType, err := tasks.GetTaskType(taskID)
+	if err != nil {
+		return nil, err
+	}
+	var workflowName string
+	if tasks.IsWorkflowTask(taskType) {
+		workflowName, err = tasks.GetTaskData(taskID, "workflowName")
+		if err != nil {
+			return nil, err
+		}
+	}
+	ctx = events.AddLogOptionalFields(ctx, events.LogOptionalFields{
+		events.WorkFlowID:  workflowName,
+		events.ExecutionID: taskID,
+	})
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, targetID).Registerf(
+		"Task %q approved by %q (%d/%d approvals)", taskID, approver, len(approval.Approvers), approval.Required)
+	if len(approval.Approvers) < approval.Required {
+		return approval, nil
+	}
+
+	// Only one of concurrent approvals reaching the required number of approvals dispatches the task
+	dispatched, err := tasks.UpdatePendingTaskStatus(taskID, tasks.TaskStatusINITIAL)
+	if err != nil || !dispatched {
+		return approval, err
+	}
+	err = c.prepareForRegistration(ctx, nil, taskType, taskID, targetID, workflowName, true)
+	if err != nil {
+		return approval, err
+	}
+	err = setUndeploymentInProgress(ctx, targetID, taskType)
+	if err != nil {
+		return approval, err
+	}
+	tasks.EmitTaskEventWithContextualLogs(ctx, targetID, taskID, taskType, workflowName, tasks.TaskStatusINITIAL.String())
+	return approval, nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/tasks"
+)
+
+func TestApprovalTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		approval config.TasksApproval
+		policy   config.TaskApprovalPolicy
+		want     time.Duration
+	}{
+		{"Default", config.TasksApproval{}, config.TaskApprovalPolicy{}, config.DefaultTasksApprovalTimeout},
+		{"Global", config.TasksApproval{Timeout: time.Hour}, config.TaskApprovalPolicy{}, time.Hour},
+		{"Policy", config.TasksApproval{Timeout: time.Hour}, config.TaskApprovalPolicy{Timeout: time.Minute}, time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Collector{approval: tt.approval}
+			require.Equal(t, tt.want, c.approvalTimeout(&tt.policy))
+		})
+	}
+}
+
+func TestPolicyMatchesLabels(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector []string
+		labels   map[string]string
+		want     bool
+		wantErr  bool
+	}{
+		{"NoSelector", nil, map[string]string{"env": "dev"}, true, false},
+		{"Matches", []string{`env = "prod"`}, map[string]string{"env": "prod"}, true, false},
+		{"DoesNotMatch", []string{`env = "prod"`, `team = "hpc"`}, map[string]string{"env": "prod"}, false, false},
+		{"InvalidSelector", []string{`env = = "prod"`}, map[string]string{"env": "prod"}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := policyMatchesLabels(&config.TaskApprovalPolicy{Name: "p", Selector: tt.selector}, tt.labels)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func countTaskExecutions(t *testing.T, taskID string) int {
+	t.Helper()
+	kvs, err := consulutil.List(consulutil.ExecutionsTaskPrefix + "/")
+	require.NoError(t, err)
+	count := 0
+	for k, v := range kvs {
+		if path.Base(k) == "taskID" && string(v) == taskID {
+			count++
+		}
+	}
+	return count
+}
+
+func testRegisterTaskWithApproval(t *testing.T, client *api.Client) {
+	ctx := context.Background()
+	deploymentID := strings.Replace(t.Name(), "/", "_", -1)
+	err := deployments.StoreDeploymentDefinition(ctx, deploymentID, "testdata/bigTopology.yaml")
+	require.NoError(t, err)
+	err = deployments.UpdateDeploymentLabels(ctx, deploymentID, map[string]string{"env": "prod"}, nil)
+	require.NoError(t, err)
+
+	testCollector := NewCollectorWithApprovals(client, config.TasksApproval{
+		Policies: []config.TaskApprovalPolicy{
+			{Name: "dev", TaskTypes: []string{"Deploy"}, Selector: []string{`env = "dev"`}},
+			{Name: "prod", TaskTypes: []string{"Deploy"}, Selector: []string{`env = "prod"`}, Approvals: 2},
+		},
+	})
+	taskID, err := testCollector.RegisterTaskWithData(deploymentID, tasks.TaskTypeDeploy, map[string]string{"workflowName": "install"})
+	require.NoError(t, err)
+
+	status, err := tasks.GetTaskStatus(taskID)
+	require.NoError(t, err)
+	require.Equal(t, tasks.TaskStatusPENDINGAPPROVAL, status)
+	require.Equal(t, 0, countTaskExecutions(t, taskID))
+
+	_, err = testCollector.RegisterTaskWithData(deploymentID, tasks.TaskTypeDeploy, map[string]string{"workflowName": "install"})
+	ok, livingTaskID := tasks.IsAnotherLivingTaskAlreadyExistsError(err)
+	require.True(t, ok, "expected a living task error, got %+v", err)
+	require.Equal(t, taskID, livingTaskID)
+
+	approval, err := testCollector.ApproveTask(ctx, taskID, "alice")
+	require.NoError(t, err)
+	require.Equal(t, "prod", approval.Policy)
+	require.Len(t, approval.Approvers, 1)
+	status, err = tasks.GetTaskStatus(taskID)
+	require.NoError(t, err)
+	require.Equal(t, tasks.TaskStatusPENDINGAPPROVAL, status)
+
+	_, err = testCollector.ApproveTask(ctx, taskID, "alice")
+	require.True(t, tasks.IsTaskApprovalError(err), "expected an approval error, got %+v", err)
+
+	approval, err = testCollector.ApproveTask(ctx, taskID, "bob")
+	require.NoError(t, err)
+	require.Len(t, approval.Approvers, 2)
+	status, err = tasks.GetTaskStatus(taskID)
+	require.NoError(t, err)
+	require.Equal(t, tasks.TaskStatusINITIAL, status)
+	require.NotEqual(t, 0, countTaskExecutions(t, taskID))
+
+	// Tasks not matching policies are not pending approval
+	otherID := deploymentID + "_other"
+	err = deployments.StoreDeploymentDefinition(ctx, otherID, "testdata/bigTopology.yaml")
+	require.NoError(t, err)
+	otherTaskID, err := testCollector.RegisterTaskWithData(otherID, tasks.TaskTypeDeploy, map[string]string{"workflowName": "install"})
+	require.NoError(t, err)
+	status, err = tasks.GetTaskStatus(otherTaskID)
+	require.NoError(t, err)
+	require.Equal(t, tasks.TaskStatusINITIAL, status)
+}
@@ -25,10 +25,12 @@ import (
 	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 
+	"github.com/ystia/yorc/v4/config"
 	"github.com/ystia/yorc/v4/deployments"
 	"github.com/ystia/yorc/v4/events"
 	"github.com/ystia/yorc/v4/helper/consulutil"
 	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/quotas"
 	"github.com/ystia/yorc/v4/tasks"
 	"github.com/ystia/yorc/v4/tasks/workflow/builder"
 )
@@ -42,8 +44,12 @@ import (
 // - register in Consul the workflow if task is workflow (/workflows/)
 // - register in Consul the executions for initial steps if task is workflow (/executions/)
 // - register in Consul the execution for task if non workflow task
+//
+// Tasks matching an approval policy are registered in status PENDING_APPROVAL
+// and their executions are registered only once they are approved
 type Collector struct {
 	consulClient *api.Client
+	approval     config.TasksApproval
 }
 
 // NewCollector creates a Collector
@@ -51,6 +57,12 @@ func NewCollector(consulClient *api.Client) *Collector {
 	return &Collector{consulClient: consulClient}
 }
 
+// NewCollectorWithApprovals creates a Collector registering tasks matching the given approval
+// policies in status PENDING_APPROVAL
+func NewCollectorWithApprovals(consulClient *api.Client, approval config.TasksApproval) *Collector {
+	return &Collector{consulClient: consulClient, approval: approval}
+}
+
 // RegisterTaskWithData register a new Task of a given type with some data
 //
 // The task id is returned.
@@ -149,6 +161,24 @@ func (c *Collector) registerTask(targetID string, taskType tasks.TaskType, data
 		if hasLivingTask {
 			return "", tasks.NewAnotherLivingTaskAlreadyExistsError(livingTaskID, targetID, livingTaskStatus)
 		}
+
+		// Quotas are checked and the resources used by the deployment recorded atomically with the registration
+		return quotas.RegisterTask(context.Background(), c.consulClient, targetID, taskType, data, func() (string, error) {
+			return c.storeTask(targetID, taskType, data)
+		})
+	}
+	return c.storeTask(targetID, taskType, data)
+}
+
+// storeTask stores a new task and its executions or registers it as pending approval
+func (c *Collector) storeTask(targetID string, taskType tasks.TaskType, data map[string]string) (string, error) {
+	policy, err := c.approvalPolicy(context.Background(), targetID, taskType)
+	if err != nil {
+		return "", err
+	}
+	status := tasks.TaskStatusINITIAL
+	if policy != nil {
+		status = tasks.TaskStatusPENDINGAPPROVAL
 	}
 
 	taskID := fmt.Sprint(uuid.NewV4())
@@ -167,7 +197,7 @@ func (c *Collector) registerTask(targetID string, taskType tasks.TaskType, data
 		&api.KVTxnOp{
 			Verb:  api.KVSet,
 			Key:   path.Join(taskPath, "status"),
-			Value: []byte(strconv.Itoa(int(tasks.TaskStatusINITIAL))),
+			Value: []byte(strconv.Itoa(int(status))),
 		},
 		&api.KVTxnOp{
 			Verb:  api.KVSet,
@@ -213,15 +243,33 @@ func (c *Collector) registerTask(targetID string, taskType tasks.TaskType, data
 		events.ExecutionID: taskID,
 	})
 
+	if policy != nil {
+		err = c.registerPendingTask(ctx, taskOps, policy, taskType, taskID, targetID)
+		if err != nil {
+			return "", err
+		}
+		tasks.EmitTaskEventWithContextualLogs(ctx, targetID, taskID, taskType, workflowName, status.String())
+		return taskID, nil
+	}
+
 	err = c.prepareForRegistration(ctx, taskOps, taskType, taskID, targetID, workflowName, true)
 	if err != nil {
 		return "", err
 	}
 
+	err = setUndeploymentInProgress(ctx, targetID, taskType)
+	if err != nil {
+		return "", err
+	}
+	tasks.EmitTaskEventWithContextualLogs(ctx, targetID, taskID, taskType, workflowName, tasks.TaskStatusINITIAL.String())
+	return taskID, nil
+}
+
+func setUndeploymentInProgress(ctx context.Context, targetID string, taskType tasks.TaskType) error {
 	if taskType == tasks.TaskTypeUnDeploy || taskType == tasks.TaskTypePurge {
 		status, err := deployments.GetDeploymentStatus(ctx, targetID)
 		if err != nil {
-			return "", err
+			return err
 		}
 		if status != deployments.UNDEPLOYED {
 			// Set the deployment status to undeployment in progress right now as the task was registered
@@ -230,8 +278,7 @@ func (c *Collector) registerTask(targetID string, taskType tasks.TaskType, data
 			deployments.SetDeploymentStatus(ctx, targetID, deployments.UNDEPLOYMENT_IN_PROGRESS)
 		}
 	}
-	tasks.EmitTaskEventWithContextualLogs(ctx, targetID, taskID, taskType, workflowName, tasks.TaskStatusINITIAL.String())
-	return taskID, nil
+	return nil
 }
 
 func (c *Collector) prepareForRegistration(ctx context.Context, operations api.KVTxnOps, taskType tasks.TaskType, taskID, targetID, workflowName string, registerWorkflow bool) error {
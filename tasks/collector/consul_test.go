@@ -43,5 +43,8 @@ func TestRunConsulCollectorPackageTests(t *testing.T) {
 		t.Run("testRegisterTaskWithBigWorkflow", func(t *testing.T) {
 			testRegisterTaskWithBigWorkflow(t, client)
 		})
+		t.Run("testRegisterTaskWithApproval", func(t *testing.T) {
+			testRegisterTaskWithApproval(t, client)
+		})
 	})
 }
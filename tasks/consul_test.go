@@ -58,6 +58,21 @@ func TestRunConsulTasksPackageTests(t *testing.T) {
 		t.Run("TestTargetHasLivingTasks", func(t *testing.T) {
 			testTargetHasLivingTasks(t)
 		})
+		t.Run("TestTaskApproval", func(t *testing.T) {
+			testTaskApproval(t)
+		})
+		t.Run("TestCancelPendingTask", func(t *testing.T) {
+			testCancelPendingTask(t)
+		})
+		t.Run("TestTaskStepSignals", func(t *testing.T) {
+			testTaskStepSignals(t)
+		})
+		t.Run("TestCancelTaskWithWaitingSteps", func(t *testing.T) {
+			testCancelTaskWithWaitingSteps(t)
+		})
+		t.Run("TestTaskStepsExecutions", func(t *testing.T) {
+			testTaskStepsExecutions(t)
+		})
 		t.Run("TestGetTaskInput", func(t *testing.T) {
 			testGetTaskInput(t)
 		})
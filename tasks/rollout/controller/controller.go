@@ -0,0 +1,439 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package controller drives the rollouts of workflows on scalable nodes.
+//
+// Rollouts progress batch by batch: the workflow is executed on the instances of a batch, then a health
+// gate checks the monitoring status and the state of the updated instances before the next batch starts.
+// A rollout is halted when a batch fails or a health gate doesn't pass, its rollback workflow is then
+// executed on the instances already updated.
+//
+// Only the leader Yorc server of the cluster drives rollouts. Their progress is stored after each change
+// so that another server could take over if the leadership changes. Tasks started by a rollout are tagged
+// with the stage of the rollout they belong to, a stage task already registered is reused instead of being
+// registered again, so a workflow is never executed twice for the same batch.
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+
+	"github.com/ystia/yorc/v4/cluster"
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/prov/monitoring"
+	"github.com/ystia/yorc/v4/tasks"
+	"github.com/ystia/yorc/v4/tasks/collector"
+	"github.com/ystia/yorc/v4/tasks/rollout"
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+// checkInterval is the interval between two checks of the rollouts progress
+const checkInterval = 10 * time.Second
+
+// rolloutStageData is the task data holding the rollout stage executed by a task
+const rolloutStageData = "rolloutStage"
+
+var defaultController *controller
+
+// operations are the operations on tasks and deployments used to drive rollouts
+type operations interface {
+	registerWorkflowTask(deploymentID string, data map[string]string) (string, error)
+	// findStageTask returns the ID of the task already registered for a rollout stage or an empty string
+	findStageTask(ctx context.Context, deploymentID, stage string) (string, error)
+	taskStatus(taskID string) (tasks.TaskStatus, error)
+	cancelTask(taskID string) error
+	isRolloutCanceled(ctx context.Context, r *rollout.Rollout) (bool, error)
+	// instanceHealth returns false and the reason why a node instance is unhealthy
+	instanceHealth(ctx context.Context, deploymentID, nodeName, instance string) (bool, string, error)
+	logf(ctx context.Context, r *rollout.Rollout, level events.LogLevel, format string, args ...interface{})
+	storeRollout(ctx context.Context, r *rollout.Rollout) error
+}
+
+// controller periodically makes the active rollouts progress
+type controller struct {
+	cc         *api.Client
+	ops        operations
+	serviceKey string
+	chShutdown chan struct{}
+	lock       sync.Mutex
+	chStop     chan struct{}
+}
+
+// Start starts to watch the leader election of the rollouts controller,
+// rollouts progress while this server is the leader
+func Start(cfg config.Configuration, cc *api.Client) {
+	defaultController = &controller{
+		cc:         cc,
+		ops:        &yorcOperations{collector: collector.NewCollectorWithApprovals(cc, cfg.Tasks.Approval)},
+		serviceKey: path.Join(consulutil.YorcServicePrefix, "/tasks/rollouts/leader"),
+		chShutdown: make(chan struct{}),
+	}
+	go cluster.WatchLeaderElection(cc, cluster.RoleRolloutsLeader, defaultController.serviceKey, defaultController.chShutdown, defaultController.startChecks, defaultController.stopChecks)
+}
+
+// Stop stops driving rollouts
+func Stop() {
+	defaultController.stopChecks()
+	close(defaultController.chShutdown)
+}
+
+func (c *controller) startChecks() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.chStop != nil {
+		return
+	}
+	chStop := make(chan struct{})
+	c.chStop = chStop
+	go func() {
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+		for {
+			c.checkRollouts(context.Background())
+			select {
+			case <-chStop:
+				return
+			case <-c.chShutdown:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (c *controller) stopChecks() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.chStop != nil {
+		close(c.chStop)
+		c.chStop = nil
+	}
+}
+
+func (c *controller) checkRollouts(ctx context.Context) {
+	rollouts, err := rollout.ListActiveRollouts(ctx)
+	if err != nil {
+		log.Printf("[WARN] Failed to list active rollouts: %v", err)
+		return
+	}
+	for _, r := range rollouts {
+		changed, err := c.progress(ctx, r, time.Now())
+		if err != nil {
+			log.Printf("[WARN] Failed to check progress of rollout %q of deployment %q: %v", r.ID, r.DeploymentID, err)
+		}
+		if !changed {
+			continue
+		}
+		err = c.ops.storeRollout(ctx, r)
+		if err != nil {
+			c.storeFailed(ctx, r, time.Now(), err)
+		}
+	}
+}
+
+// storeFailed fails a rollout which progress can't be stored, its running task is canceled as
+// the rollout could not be resumed from its stored state
+func (c *controller) storeFailed(ctx context.Context, r *rollout.Rollout, now time.Time, err error) {
+	c.cancelTask(ctx, r)
+	r.Error = fmt.Sprintf("failed to store progress: %v", err)
+	c.end(r, rollout.StatusFAILED, now)
+	c.ops.logf(ctx, r, events.LogLevelERROR, "%s", r.Error)
+	err = c.ops.storeRollout(ctx, r)
+	if err != nil {
+		log.Printf("[WARN] %v", err)
+	}
+}
+
+// progress makes a rollout progress at the given date and returns true if the rollout changed
+func (c *controller) progress(ctx context.Context, r *rollout.Rollout, now time.Time) (bool, error) {
+	canceled, err := c.ops.isRolloutCanceled(ctx, r)
+	if err != nil {
+		return false, err
+	}
+	if canceled {
+		c.cancel(ctx, r, now)
+		return true, nil
+	}
+	switch r.Status {
+	case rollout.StatusRUNNING:
+		return c.progressBatch(ctx, r, now)
+	case rollout.StatusROLLINGBACK:
+		return c.progressRollback(ctx, r, now)
+	}
+	return false, nil
+}
+
+func (c *controller) cancel(ctx context.Context, r *rollout.Rollout, now time.Time) {
+	c.cancelTask(ctx, r)
+	c.end(r, rollout.StatusCANCELED, now)
+	c.ops.logf(ctx, r, events.LogLevelINFO, "canceled")
+}
+
+// cancelTask cancels the task of the current stage of a rollout if it is not ended
+func (c *controller) cancelTask(ctx context.Context, r *rollout.Rollout) {
+	taskID := r.RollbackTaskID
+	if r.Status == rollout.StatusRUNNING {
+		taskID = r.Batches[r.CurrentBatch].TaskID
+	}
+	if taskID == "" {
+		return
+	}
+	status, err := c.ops.taskStatus(taskID)
+	if err == nil && !isTaskEnded(status) {
+		err = c.ops.cancelTask(taskID)
+	}
+	if err != nil {
+		c.ops.logf(ctx, r, events.LogLevelWARN, "failed to cancel task %q: %v", taskID, err)
+	}
+}
+
+func (c *controller) end(r *rollout.Rollout, status rollout.Status, now time.Time) {
+	r.Status = status
+	r.EndDate = &now
+}
+
+// progressBatch makes the current batch of a running rollout progress
+func (c *controller) progressBatch(ctx context.Context, r *rollout.Rollout, now time.Time) (bool, error) {
+	b := &r.Batches[r.CurrentBatch]
+	switch b.Status {
+	case rollout.BatchStatusINITIAL:
+		if b.NotBefore != nil && now.Before(*b.NotBefore) {
+			return false, nil
+		}
+		taskID, err := c.startStageTask(ctx, r, batchStage(r, r.CurrentBatch), r.WorkflowName, b.Instances)
+		if err != nil {
+			if ok, _ := tasks.IsAnotherLivingTaskAlreadyExistsError(err); ok {
+				// Wait for the other task to end
+				return false, nil
+			}
+			c.halt(ctx, r, now, fmt.Sprintf("failed to start batch %d: %v", r.CurrentBatch+1, err))
+			return true, nil
+		}
+		b.TaskID = taskID
+		b.Status = rollout.BatchStatusRUNNING
+		b.NotBefore = nil
+		c.ops.logf(ctx, r, events.LogLevelINFO, "batch %d/%d started on instances %s with task %q", r.CurrentBatch+1, len(r.Batches), strings.Join(b.Instances, ","), taskID)
+		return true, nil
+	case rollout.BatchStatusRUNNING:
+		status, err := c.ops.taskStatus(b.TaskID)
+		if err != nil {
+			return false, err
+		}
+		switch status {
+		case tasks.TaskStatusDONE:
+			gate := now.Add(r.HealthGateDelay)
+			b.Status = rollout.BatchStatusVERIFYING
+			b.NotBefore = &gate
+			return true, nil
+		case tasks.TaskStatusFAILED, tasks.TaskStatusCANCELED:
+			b.Status = rollout.BatchStatusFAILED
+			c.halt(ctx, r, now, fmt.Sprintf("task %q of batch %d ended with status %s", b.TaskID, r.CurrentBatch+1, status.String()))
+			return true, nil
+		}
+		return false, nil
+	case rollout.BatchStatusVERIFYING:
+		if b.NotBefore != nil && now.Before(*b.NotBefore) {
+			return false, nil
+		}
+		for _, instance := range r.UpdatedInstances() {
+			healthy, reason, err := c.ops.instanceHealth(ctx, r.DeploymentID, r.NodeName, instance)
+			if err != nil {
+				return false, err
+			}
+			if !healthy {
+				b.Status = rollout.BatchStatusFAILED
+				c.halt(ctx, r, now, fmt.Sprintf("health gate of batch %d failed, instance %q of node %q %s", r.CurrentBatch+1, instance, r.NodeName, reason))
+				return true, nil
+			}
+		}
+		b.Status = rollout.BatchStatusDONE
+		b.NotBefore = nil
+		c.ops.logf(ctx, r, events.LogLevelINFO, "batch %d/%d succeeded", r.CurrentBatch+1, len(r.Batches))
+		if r.CurrentBatch == len(r.Batches)-1 {
+			c.end(r, rollout.StatusDONE, now)
+			c.ops.logf(ctx, r, events.LogLevelINFO, "workflow %q rolled out on all instances of node %q", r.WorkflowName, r.NodeName)
+			return true, nil
+		}
+		r.CurrentBatch++
+		next := now.Add(r.Pause)
+		r.Batches[r.CurrentBatch].NotBefore = &next
+		return true, nil
+	}
+	return false, nil
+}
+
+// halt stops a rollout on failure, the rollout is rolled back if it has a rollback workflow
+func (c *controller) halt(ctx context.Context, r *rollout.Rollout, now time.Time, reason string) {
+	r.Error = reason
+	if r.RollbackWorkflowName == "" {
+		c.end(r, rollout.StatusFAILED, now)
+		c.ops.logf(ctx, r, events.LogLevelERROR, "halted: %s", reason)
+		return
+	}
+	r.Status = rollout.StatusROLLINGBACK
+	c.ops.logf(ctx, r, events.LogLevelERROR, "halted: %s, rolling back updated instances", reason)
+}
+
+// progressRollback makes a rolling back rollout progress
+func (c *controller) progressRollback(ctx context.Context, r *rollout.Rollout, now time.Time) (bool, error) {
+	if r.RollbackTaskID == "" {
+		instances := r.UpdatedInstances()
+		taskID, err := c.startStageTask(ctx, r, rollbackStage(r), r.RollbackWorkflowName, instances)
+		if err != nil {
+			if ok, _ := tasks.IsAnotherLivingTaskAlreadyExistsError(err); ok {
+				return false, nil
+			}
+			c.rollbackFailed(ctx, r, now, fmt.Sprintf("failed to start rollback: %v", err))
+			return true, nil
+		}
+		r.RollbackTaskID = taskID
+		c.ops.logf(ctx, r, events.LogLevelINFO, "rollback started on instances %s with task %q", strings.Join(instances, ","), taskID)
+		return true, nil
+	}
+	status, err := c.ops.taskStatus(r.RollbackTaskID)
+	if err != nil {
+		return false, err
+	}
+	switch status {
+	case tasks.TaskStatusDONE:
+		c.end(r, rollout.StatusROLLEDBACK, now)
+		c.ops.logf(ctx, r, events.LogLevelINFO, "rolled back")
+		return true, nil
+	case tasks.TaskStatusFAILED, tasks.TaskStatusCANCELED:
+		c.rollbackFailed(ctx, r, now, fmt.Sprintf("rollback task %q ended with status %s", r.RollbackTaskID, status.String()))
+		return true, nil
+	}
+	return false, nil
+}
+
+func (c *controller) rollbackFailed(ctx context.Context, r *rollout.Rollout, now time.Time, reason string) {
+	r.Error = fmt.Sprintf("%s, %s", r.Error, reason)
+	c.end(r, rollout.StatusFAILED, now)
+	c.ops.logf(ctx, r, events.LogLevelERROR, "%s", reason)
+}
+
+// startStageTask returns the task executing a workflow for a stage of a rollout, the task is registered
+// only if no task was already registered for this stage
+func (c *controller) startStageTask(ctx context.Context, r *rollout.Rollout, stage, workflowName string, instances []string) (string, error) {
+	taskID, err := c.ops.findStageTask(ctx, r.DeploymentID, stage)
+	if err != nil || taskID != "" {
+		return taskID, err
+	}
+	return c.ops.registerWorkflowTask(r.DeploymentID, taskData(r, stage, workflowName, instances))
+}
+
+// batchStage returns the stage of a rollout batch
+func batchStage(r *rollout.Rollout, batch int) string {
+	return fmt.Sprintf("%s/batch/%d", r.ID, batch)
+}
+
+// rollbackStage returns the rollback stage of a rollout
+func rollbackStage(r *rollout.Rollout) string {
+	return r.ID + "/rollback"
+}
+
+// taskData returns the data of a task executing a workflow for a rollout stage on the given instances of the rollout node
+func taskData(r *rollout.Rollout, stage, workflowName string, instances []string) map[string]string {
+	data := map[string]string{
+		"workflowName":        workflowName,
+		"continueOnError":     "false",
+		"nodes/" + r.NodeName: strings.Join(instances, ","),
+		rolloutStageData:      stage,
+	}
+	for name, value := range r.Inputs {
+		data[path.Join("inputs", name)] = value
+	}
+	return data
+}
+
+func isTaskEnded(status tasks.TaskStatus) bool {
+	return status == tasks.TaskStatusDONE || status == tasks.TaskStatusFAILED || status == tasks.TaskStatusCANCELED
+}
+
+// yorcOperations implements operations using the Yorc tasks and deployments
+type yorcOperations struct {
+	collector *collector.Collector
+}
+
+func (o *yorcOperations) registerWorkflowTask(deploymentID string, data map[string]string) (string, error) {
+	return o.collector.RegisterTaskWithData(deploymentID, tasks.TaskTypeCustomWorkflow, data)
+}
+
+func (o *yorcOperations) findStageTask(ctx context.Context, deploymentID, stage string) (string, error) {
+	taskIDs, err := deployments.GetDeploymentTaskList(ctx, deploymentID)
+	if err != nil {
+		return "", err
+	}
+	for _, taskID := range taskIDs {
+		taskStage, err := tasks.GetTaskData(taskID, rolloutStageData)
+		if tasks.IsTaskDataNotFoundError(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if taskStage == stage {
+			return taskID, nil
+		}
+	}
+	return "", nil
+}
+
+func (o *yorcOperations) taskStatus(taskID string) (tasks.TaskStatus, error) {
+	return tasks.GetTaskStatus(taskID)
+}
+
+func (o *yorcOperations) cancelTask(taskID string) error {
+	return tasks.CancelTask(taskID)
+}
+
+func (o *yorcOperations) isRolloutCanceled(ctx context.Context, r *rollout.Rollout) (bool, error) {
+	return rollout.IsRolloutCanceled(ctx, r.DeploymentID, r.ID)
+}
+
+func (o *yorcOperations) instanceHealth(ctx context.Context, deploymentID, nodeName, instance string) (bool, string, error) {
+	status, monitored, err := monitoring.GetInstanceCheckStatus(deploymentID, nodeName, instance)
+	if err != nil {
+		return false, "", err
+	}
+	if monitored && (status == monitoring.CheckStatusCRITICAL || status == monitoring.CheckStatusWARNING) {
+		return false, fmt.Sprintf("has a %s monitoring check status", status.String()), nil
+	}
+	state, err := deployments.GetInstanceState(ctx, deploymentID, nodeName, instance)
+	if err != nil {
+		return false, "", err
+	}
+	if state == tosca.NodeStateError {
+		return false, "is in error state", nil
+	}
+	return true, "", nil
+}
+
+func (o *yorcOperations) logf(ctx context.Context, r *rollout.Rollout, level events.LogLevel, format string, args ...interface{}) {
+	events.WithContextOptionalFields(ctx).NewLogEntry(level, r.DeploymentID).Registerf("Rollout %q: "+format, append([]interface{}{r.ID}, args...)...)
+}
+
+func (o *yorcOperations) storeRollout(ctx context.Context, r *rollout.Rollout) error {
+	return rollout.StoreRollout(ctx, r)
+}
@@ -0,0 +1,266 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/events"
+	"github.com/ystia/yorc/v4/tasks"
+	"github.com/ystia/yorc/v4/tasks/rollout"
+)
+
+type fakeOperations struct {
+	registered    []map[string]string
+	registerErr   error
+	stageTasks    map[string]string
+	stored        []rollout.Status
+	storeErr      error
+	statuses      map[string]tasks.TaskStatus
+	canceledTasks []string
+	canceled      bool
+	unhealthy     map[string]bool
+	logs          []string
+}
+
+func (o *fakeOperations) registerWorkflowTask(deploymentID string, data map[string]string) (string, error) {
+	if o.registerErr != nil {
+		return "", o.registerErr
+	}
+	o.registered = append(o.registered, data)
+	taskID := fmt.Sprintf("task%d", len(o.registered))
+	o.statuses[taskID] = tasks.TaskStatusINITIAL
+	return taskID, nil
+}
+
+func (o *fakeOperations) findStageTask(ctx context.Context, deploymentID, stage string) (string, error) {
+	return o.stageTasks[stage], nil
+}
+
+func (o *fakeOperations) storeRollout(ctx context.Context, r *rollout.Rollout) error {
+	o.stored = append(o.stored, r.Status)
+	return o.storeErr
+}
+
+func (o *fakeOperations) taskStatus(taskID string) (tasks.TaskStatus, error) {
+	return o.statuses[taskID], nil
+}
+
+func (o *fakeOperations) cancelTask(taskID string) error {
+	o.canceledTasks = append(o.canceledTasks, taskID)
+	return nil
+}
+
+func (o *fakeOperations) isRolloutCanceled(ctx context.Context, r *rollout.Rollout) (bool, error) {
+	return o.canceled, nil
+}
+
+func (o *fakeOperations) instanceHealth(ctx context.Context, deploymentID, nodeName, instance string) (bool, string, error) {
+	if o.unhealthy[instance] {
+		return false, "is in error state", nil
+	}
+	return true, "", nil
+}
+
+func (o *fakeOperations) logf(ctx context.Context, r *rollout.Rollout, level events.LogLevel, format string, args ...interface{}) {
+	o.logs = append(o.logs, fmt.Sprintf(format, args...))
+}
+
+func newTestRollout(rollbackWorkflow string) *rollout.Rollout {
+	return &rollout.Rollout{
+		ID:                   "r1",
+		DeploymentID:         "dep",
+		NodeName:             "Compute",
+		WorkflowName:         "upgrade",
+		RollbackWorkflowName: rollbackWorkflow,
+		Inputs:               map[string]string{"version": "2"},
+		Pause:                time.Minute,
+		HealthGateDelay:      30 * time.Second,
+		Status:               rollout.StatusRUNNING,
+		Batches: []rollout.Batch{
+			{Instances: []string{"0"}},
+			{Instances: []string{"1", "2"}},
+		},
+	}
+}
+
+func progress(t *testing.T, c *controller, r *rollout.Rollout, now time.Time, wantChanged bool) {
+	t.Helper()
+	changed, err := c.progress(context.Background(), r, now)
+	require.NoError(t, err)
+	require.Equal(t, wantChanged, changed)
+}
+
+func TestRolloutProgressSuccess(t *testing.T) {
+	t.Parallel()
+	ops := &fakeOperations{statuses: make(map[string]tasks.TaskStatus)}
+	c := &controller{ops: ops}
+	r := newTestRollout("")
+	now := time.Date(2019, time.June, 3, 10, 0, 0, 0, time.UTC)
+
+	// Canary batch
+	progress(t, c, r, now, true)
+	require.Equal(t, rollout.BatchStatusRUNNING, r.Batches[0].Status)
+	require.Equal(t, map[string]string{"workflowName": "upgrade", "continueOnError": "false", "nodes/Compute": "0", "inputs/version": "2", "rolloutStage": "r1/batch/0"}, ops.registered[0])
+	progress(t, c, r, now, false)
+	ops.statuses["task1"] = tasks.TaskStatusDONE
+	progress(t, c, r, now, true)
+	require.Equal(t, rollout.BatchStatusVERIFYING, r.Batches[0].Status)
+	// Health gate delay
+	progress(t, c, r, now.Add(10*time.Second), false)
+	now = now.Add(30 * time.Second)
+	progress(t, c, r, now, true)
+	require.Equal(t, rollout.BatchStatusDONE, r.Batches[0].Status)
+	require.Equal(t, 1, r.CurrentBatch)
+
+	// Pause between batches
+	progress(t, c, r, now.Add(30*time.Second), false)
+	now = now.Add(time.Minute)
+	progress(t, c, r, now, true)
+	require.Equal(t, "1,2", ops.registered[1]["nodes/Compute"])
+	ops.statuses["task2"] = tasks.TaskStatusDONE
+	progress(t, c, r, now, true)
+	now = now.Add(30 * time.Second)
+	progress(t, c, r, now, true)
+	require.Equal(t, rollout.StatusDONE, r.Status)
+	require.NotNil(t, r.EndDate)
+	require.False(t, r.IsActive())
+}
+
+func TestRolloutProgressHealthGateRollback(t *testing.T) {
+	t.Parallel()
+	ops := &fakeOperations{statuses: make(map[string]tasks.TaskStatus), unhealthy: map[string]bool{"0": true}}
+	c := &controller{ops: ops}
+	r := newTestRollout("revert")
+	now := time.Date(2019, time.June, 3, 10, 0, 0, 0, time.UTC)
+
+	progress(t, c, r, now, true)
+	ops.statuses["task1"] = tasks.TaskStatusDONE
+	progress(t, c, r, now, true)
+	now = now.Add(time.Minute)
+	progress(t, c, r, now, true)
+	require.Equal(t, rollout.BatchStatusFAILED, r.Batches[0].Status)
+	require.Equal(t, rollout.StatusROLLINGBACK, r.Status)
+	require.Contains(t, r.Error, "health gate of batch 1 failed")
+
+	progress(t, c, r, now, true)
+	require.Equal(t, "task2", r.RollbackTaskID)
+	require.Equal(t, "revert", ops.registered[1]["workflowName"])
+	require.Equal(t, "0", ops.registered[1]["nodes/Compute"])
+	require.Equal(t, "r1/rollback", ops.registered[1]["rolloutStage"])
+	progress(t, c, r, now, false)
+	ops.statuses["task2"] = tasks.TaskStatusDONE
+	progress(t, c, r, now, true)
+	require.Equal(t, rollout.StatusROLLEDBACK, r.Status)
+	require.Equal(t, rollout.BatchStatusINITIAL, r.Batches[1].Status)
+}
+
+func TestRolloutProgressFailures(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2019, time.June, 3, 10, 0, 0, 0, time.UTC)
+
+	// A failed batch task halts a rollout without rollback workflow
+	ops := &fakeOperations{statuses: make(map[string]tasks.TaskStatus)}
+	c := &controller{ops: ops}
+	r := newTestRollout("")
+	progress(t, c, r, now, true)
+	ops.statuses["task1"] = tasks.TaskStatusFAILED
+	progress(t, c, r, now, true)
+	require.Equal(t, rollout.StatusFAILED, r.Status)
+	require.Contains(t, r.Error, "ended with status FAILED")
+
+	// A failed rollback task fails the rollout
+	ops = &fakeOperations{statuses: make(map[string]tasks.TaskStatus)}
+	c = &controller{ops: ops}
+	r = newTestRollout("revert")
+	progress(t, c, r, now, true)
+	ops.statuses["task1"] = tasks.TaskStatusCANCELED
+	progress(t, c, r, now, true)
+	progress(t, c, r, now, true)
+	ops.statuses["task2"] = tasks.TaskStatusFAILED
+	progress(t, c, r, now, true)
+	require.Equal(t, rollout.StatusFAILED, r.Status)
+	require.Contains(t, r.Error, "rollback task \"task2\" ended with status FAILED")
+
+	// Another living task delays the batch
+	ops = &fakeOperations{statuses: make(map[string]tasks.TaskStatus), registerErr: tasks.NewAnotherLivingTaskAlreadyExistsError("t", "dep", "RUNNING")}
+	c = &controller{ops: ops}
+	r = newTestRollout("")
+	progress(t, c, r, now, false)
+	require.Equal(t, rollout.StatusRUNNING, r.Status)
+
+	// Other registration errors halt the rollout
+	ops.registerErr = errors.New("boom")
+	progress(t, c, r, now, true)
+	require.Equal(t, rollout.StatusFAILED, r.Status)
+}
+
+func TestRolloutProgressCanceled(t *testing.T) {
+	t.Parallel()
+	ops := &fakeOperations{statuses: make(map[string]tasks.TaskStatus)}
+	c := &controller{ops: ops}
+	r := newTestRollout("revert")
+	now := time.Date(2019, time.June, 3, 10, 0, 0, 0, time.UTC)
+
+	progress(t, c, r, now, true)
+	ops.canceled = true
+	progress(t, c, r, now, true)
+	require.Equal(t, rollout.StatusCANCELED, r.Status)
+	require.Equal(t, []string{"task1"}, ops.canceledTasks)
+	require.Len(t, ops.registered, 1)
+}
+
+func TestRolloutProgressReusesStageTask(t *testing.T) {
+	t.Parallel()
+	ops := &fakeOperations{
+		statuses:   map[string]tasks.TaskStatus{"batchTask": tasks.TaskStatusRUNNING, "rollbackTask": tasks.TaskStatusRUNNING},
+		stageTasks: map[string]string{"r1/batch/0": "batchTask", "r1/rollback": "rollbackTask"},
+	}
+	c := &controller{ops: ops}
+	r := newTestRollout("revert")
+	now := time.Date(2019, time.June, 3, 10, 0, 0, 0, time.UTC)
+
+	// A task registered before a leadership change is reused
+	progress(t, c, r, now, true)
+	require.Equal(t, "batchTask", r.Batches[0].TaskID)
+	require.Equal(t, rollout.BatchStatusRUNNING, r.Batches[0].Status)
+	ops.statuses["batchTask"] = tasks.TaskStatusFAILED
+	progress(t, c, r, now, true)
+	require.Equal(t, rollout.StatusROLLINGBACK, r.Status)
+	progress(t, c, r, now, true)
+	require.Equal(t, "rollbackTask", r.RollbackTaskID)
+	require.Len(t, ops.registered, 0)
+}
+
+func TestRolloutStoreFailed(t *testing.T) {
+	t.Parallel()
+	ops := &fakeOperations{statuses: make(map[string]tasks.TaskStatus), storeErr: errors.New("boom")}
+	c := &controller{ops: ops}
+	r := newTestRollout("")
+	now := time.Date(2019, time.June, 3, 10, 0, 0, 0, time.UTC)
+
+	progress(t, c, r, now, true)
+	c.storeFailed(context.Background(), r, now, errors.New("boom"))
+	require.Equal(t, rollout.StatusFAILED, r.Status)
+	require.Contains(t, r.Error, "failed to store progress: boom")
+	require.Equal(t, []string{"task1"}, ops.canceledTasks)
+	require.Equal(t, []rollout.Status{rollout.StatusFAILED}, ops.stored)
+}
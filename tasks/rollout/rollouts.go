@@ -0,0 +1,253 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rollout defines rollouts that progressively execute a workflow on the instances of a scalable node.
+//
+// Rollouts are executed by the rollouts controller running on the leader Yorc server of the cluster.
+package rollout
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+)
+
+func rolloutPath(deploymentID, id string) string {
+	return path.Join(consulutil.RolloutsKVPrefix, deploymentID, id)
+}
+
+// IsActive returns true if the rollout is running or rolling back
+func (r *Rollout) IsActive() bool {
+	return r.Status == StatusRUNNING || r.Status == StatusROLLINGBACK
+}
+
+// UpdatedInstances returns the instances of the batches on which the workflow was executed or is being executed
+func (r *Rollout) UpdatedInstances() []string {
+	instances := make([]string, 0)
+	for _, b := range r.Batches {
+		if b.Status == BatchStatusINITIAL {
+			continue
+		}
+		instances = append(instances, b.Instances...)
+	}
+	return instances
+}
+
+// Validate checks that a rollout definition is valid
+func (r *Rollout) Validate() error {
+	if r.DeploymentID == "" {
+		return errors.New("deployment ID is mandatory for a rollout")
+	}
+	if r.NodeName == "" {
+		return errors.New("node name is mandatory for a rollout")
+	}
+	if r.WorkflowName == "" {
+		return errors.New("workflow name is mandatory for a rollout")
+	}
+	if (r.BatchSize > 0) == (r.BatchPercentage > 0) {
+		return errors.New("exactly one of a batch size or a batch percentage should be defined for a rollout")
+	}
+	if r.BatchSize < 0 {
+		return errors.Errorf("invalid batch size %d", r.BatchSize)
+	}
+	if r.BatchPercentage < 0 || r.BatchPercentage > 100 {
+		return errors.Errorf("invalid batch percentage %d, it should be between 1 and 100", r.BatchPercentage)
+	}
+	if r.Canary < 0 {
+		return errors.Errorf("invalid canary size %d", r.Canary)
+	}
+	if r.Pause < 0 {
+		return errors.Errorf("invalid pause between batches %v", r.Pause)
+	}
+	if r.HealthGateDelay < 0 {
+		return errors.Errorf("invalid health gate delay %v", r.HealthGateDelay)
+	}
+	return nil
+}
+
+// sortInstances sorts instances names numerically when possible
+func sortInstances(instances []string) {
+	sort.SliceStable(instances, func(i, j int) bool {
+		ni, erri := strconv.Atoi(instances[i])
+		nj, errj := strconv.Atoi(instances[j])
+		if erri == nil && errj == nil {
+			return ni < nj
+		}
+		return instances[i] < instances[j]
+	})
+}
+
+// computeBatches splits instances into an optional canary batch followed by batches of the given size or percentage
+func computeBatches(instances []string, canary, batchSize, batchPercentage int) [][]string {
+	if batchPercentage > 0 {
+		// Round up so that a batch contains at least one instance
+		batchSize = (len(instances)*batchPercentage + 99) / 100
+	}
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	batches := make([][]string, 0)
+	remaining := instances
+	if canary > 0 && len(remaining) > 0 {
+		if canary > len(remaining) {
+			canary = len(remaining)
+		}
+		batches = append(batches, remaining[:canary])
+		remaining = remaining[canary:]
+	}
+	for len(remaining) > 0 {
+		size := batchSize
+		if size > len(remaining) {
+			size = len(remaining)
+		}
+		batches = append(batches, remaining[:size])
+		remaining = remaining[size:]
+	}
+	return batches
+}
+
+// CreateRollout validates and stores a new rollout of the workflow on the given node instances, the rollout ID is returned
+func CreateRollout(ctx context.Context, r *Rollout, instances []string) (string, error) {
+	if err := r.Validate(); err != nil {
+		return "", err
+	}
+	if len(instances) == 0 {
+		return "", errors.Errorf("node %q has no instance to roll out", r.NodeName)
+	}
+	sorted := make([]string, len(instances))
+	copy(sorted, instances)
+	sortInstances(sorted)
+	r.Batches = make([]Batch, 0)
+	for _, b := range computeBatches(sorted, r.Canary, r.BatchSize, r.BatchPercentage) {
+		r.Batches = append(r.Batches, Batch{Instances: b, Status: BatchStatusINITIAL})
+	}
+	r.ID = uuid.NewV4().String()
+	r.Status = StatusRUNNING
+	r.CurrentBatch = 0
+	r.CreationDate = time.Now()
+	log.Debugf("Creating rollout %q of workflow %q on node %q of deployment %q", r.ID, r.WorkflowName, r.NodeName, r.DeploymentID)
+	return r.ID, StoreRollout(ctx, r)
+}
+
+// StoreRollout stores the definition and progress of a rollout
+func StoreRollout(ctx context.Context, r *Rollout) error {
+	err := consulutil.StoreConsulKeyWithJSONValue(path.Join(rolloutPath(r.DeploymentID, r.ID), "definition"), r)
+	return errors.Wrapf(err, "failed to store rollout %q of deployment %q", r.ID, r.DeploymentID)
+}
+
+// UnmarshalRollout reads a rollout from its stored definition
+func UnmarshalRollout(definition []byte) (*Rollout, error) {
+	r := new(Rollout)
+	err := json.Unmarshal(definition, r)
+	return r, errors.Wrap(err, "failed to read rollout definition")
+}
+
+func isRolloutDefinitionKey(key string) bool {
+	return path.Base(key) == "definition" && path.Dir(path.Dir(path.Dir(key))) == consulutil.RolloutsKVPrefix
+}
+
+// GetRollout returns a rollout of a deployment or nil if it doesn't exist
+func GetRollout(ctx context.Context, deploymentID, id string) (*Rollout, error) {
+	exist, value, err := consulutil.GetValue(path.Join(rolloutPath(deploymentID, id), "definition"))
+	if err != nil || !exist {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	return UnmarshalRollout(value)
+}
+
+func listRollouts(prefix string) ([]*Rollout, error) {
+	kvps, err := consulutil.List(prefix + "/")
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	rollouts := make([]*Rollout, 0)
+	for k, v := range kvps {
+		if !isRolloutDefinitionKey(k) {
+			continue
+		}
+		r, err := UnmarshalRollout(v)
+		if err != nil {
+			log.Printf("[WARNING] ignoring rollout stored at %q: %v", k, err)
+			continue
+		}
+		rollouts = append(rollouts, r)
+	}
+	sort.Slice(rollouts, func(i, j int) bool {
+		return rollouts[i].CreationDate.Before(rollouts[j].CreationDate)
+	})
+	return rollouts, nil
+}
+
+// ListRollouts returns the rollouts of a deployment sorted by creation date
+func ListRollouts(ctx context.Context, deploymentID string) ([]*Rollout, error) {
+	return listRollouts(path.Join(consulutil.RolloutsKVPrefix, deploymentID))
+}
+
+// ListActiveRollouts returns the running or rolling back rollouts of all deployments
+func ListActiveRollouts(ctx context.Context) ([]*Rollout, error) {
+	rollouts, err := listRollouts(consulutil.RolloutsKVPrefix)
+	if err != nil {
+		return nil, err
+	}
+	active := rollouts[:0]
+	for _, r := range rollouts {
+		if r.IsActive() {
+			active = append(active, r)
+		}
+	}
+	return active, nil
+}
+
+// GetActiveRollout returns the running or rolling back rollout of a deployment or nil if there is none
+func GetActiveRollout(ctx context.Context, deploymentID string) (*Rollout, error) {
+	rollouts, err := ListRollouts(ctx, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rollouts {
+		if r.IsActive() {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+
+// CancelRollout flags a rollout as canceled, the rollouts controller stops it and cancels its running task
+func CancelRollout(ctx context.Context, deploymentID, id string) error {
+	err := consulutil.StoreConsulKeyAsString(path.Join(rolloutPath(deploymentID, id), ".canceledFlag"), "true")
+	return errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+}
+
+// IsRolloutCanceled returns true if a rollout was flagged as canceled
+func IsRolloutCanceled(ctx context.Context, deploymentID, id string) (bool, error) {
+	exist, value, err := consulutil.GetStringValue(path.Join(rolloutPath(deploymentID, id), ".canceledFlag"))
+	if err != nil {
+		return false, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	return exist && value == "true", nil
+}
+
+// DeleteDeploymentRollouts removes all the rollouts of a deployment
+func DeleteDeploymentRollouts(ctx context.Context, deploymentID string) error {
+	return errors.Wrap(consulutil.Delete(path.Join(consulutil.RolloutsKVPrefix, deploymentID)+"/", true), consulutil.ConsulGenericErrMsg)
+}
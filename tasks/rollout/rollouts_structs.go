@@ -0,0 +1,90 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rollout
+
+import (
+	"time"
+)
+
+//go:generate go-enum -f=rollouts_structs.go
+
+// Status is an enumerated type for rollouts statuses
+/*
+ENUM(
+RUNNING
+DONE
+FAILED
+ROLLING_BACK
+ROLLED_BACK
+CANCELED
+)
+*/
+type Status int
+
+// BatchStatus is an enumerated type for the statuses of rollouts batches
+/*
+ENUM(
+INITIAL
+RUNNING
+VERIFYING
+DONE
+FAILED
+)
+*/
+type BatchStatus int
+
+// A Rollout progressively executes a workflow on the instances of a scalable node
+//
+// Instances are split into batches, the first one being an optional canary batch. The workflow
+// is executed on one batch at a time, health gates check the monitoring status of updated
+// instances between batches. On failure the rollout is halted and the optional rollback workflow
+// is executed on the instances already updated.
+type Rollout struct {
+	ID           string `json:"id"`
+	DeploymentID string `json:"deployment_id"`
+	NodeName     string `json:"node_name"`
+	WorkflowName string `json:"workflow_name"`
+	// RollbackWorkflowName is the workflow executed on updated instances when the rollout fails
+	RollbackWorkflowName string `json:"rollback_workflow_name,omitempty"`
+	// Inputs are the inputs of the workflows
+	Inputs map[string]string `json:"inputs,omitempty"`
+	// Canary is the number of instances of the first batch
+	Canary int `json:"canary,omitempty"`
+	// BatchSize is the number of instances of a batch, exclusive with BatchPercentage
+	BatchSize int `json:"batch_size,omitempty"`
+	// BatchPercentage is the percentage of the node instances in a batch, exclusive with BatchSize
+	BatchPercentage int `json:"batch_percentage,omitempty"`
+	// Pause is the delay between the success of a batch and the start of the next one
+	Pause time.Duration `json:"pause,omitempty"`
+	// HealthGateDelay is the delay between the end of the workflow of a batch and the check of its instances health
+	HealthGateDelay time.Duration `json:"health_gate_delay,omitempty"`
+
+	Status         Status     `json:"status"`
+	Batches        []Batch    `json:"batches"`
+	CurrentBatch   int        `json:"current_batch"`
+	RollbackTaskID string     `json:"rollback_task_id,omitempty"`
+	Error          string     `json:"error,omitempty"`
+	CreationDate   time.Time  `json:"creation_date"`
+	EndDate        *time.Time `json:"end_date,omitempty"`
+}
+
+// A Batch is a set of instances updated together during a rollout
+type Batch struct {
+	Instances []string    `json:"instances"`
+	Status    BatchStatus `json:"status"`
+	TaskID    string      `json:"task_id,omitempty"`
+	// NotBefore is the date before which the batch should not be started or verified
+	NotBefore *time.Time `json:"not_before,omitempty"`
+}
@@ -0,0 +1,120 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by go-enum
+// DO NOT EDIT!
+
+package rollout
+
+import (
+	"fmt"
+)
+
+const (
+	// StatusRUNNING is a Status of type RUNNING
+	StatusRUNNING Status = iota
+	// StatusDONE is a Status of type DONE
+	StatusDONE
+	// StatusFAILED is a Status of type FAILED
+	StatusFAILED
+	// StatusROLLINGBACK is a Status of type ROLLING_BACK
+	StatusROLLINGBACK
+	// StatusROLLEDBACK is a Status of type ROLLED_BACK
+	StatusROLLEDBACK
+	// StatusCANCELED is a Status of type CANCELED
+	StatusCANCELED
+)
+
+const _StatusName = "RUNNINGDONEFAILEDROLLING_BACKROLLED_BACKCANCELED"
+
+var _StatusMap = map[Status]string{
+	0: _StatusName[0:7],
+	1: _StatusName[7:11],
+	2: _StatusName[11:17],
+	3: _StatusName[17:29],
+	4: _StatusName[29:40],
+	5: _StatusName[40:48],
+}
+
+// String implements the Stringer interface.
+func (x Status) String() string {
+	if str, ok := _StatusMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("Status(%d)", x)
+}
+
+var _StatusValue = map[string]Status{
+	_StatusName[0:7]:   0,
+	_StatusName[7:11]:  1,
+	_StatusName[11:17]: 2,
+	_StatusName[17:29]: 3,
+	_StatusName[29:40]: 4,
+	_StatusName[40:48]: 5,
+}
+
+// ParseStatus attempts to convert a string to a Status
+func ParseStatus(name string) (Status, error) {
+	if x, ok := _StatusValue[name]; ok {
+		return x, nil
+	}
+	return Status(0), fmt.Errorf("%s is not a valid Status", name)
+}
+
+const (
+	// BatchStatusINITIAL is a BatchStatus of type INITIAL
+	BatchStatusINITIAL BatchStatus = iota
+	// BatchStatusRUNNING is a BatchStatus of type RUNNING
+	BatchStatusRUNNING
+	// BatchStatusVERIFYING is a BatchStatus of type VERIFYING
+	BatchStatusVERIFYING
+	// BatchStatusDONE is a BatchStatus of type DONE
+	BatchStatusDONE
+	// BatchStatusFAILED is a BatchStatus of type FAILED
+	BatchStatusFAILED
+)
+
+const _BatchStatusName = "INITIALRUNNINGVERIFYINGDONEFAILED"
+
+var _BatchStatusMap = map[BatchStatus]string{
+	0: _BatchStatusName[0:7],
+	1: _BatchStatusName[7:14],
+	2: _BatchStatusName[14:23],
+	3: _BatchStatusName[23:27],
+	4: _BatchStatusName[27:33],
+}
+
+// String implements the Stringer interface.
+func (x BatchStatus) String() string {
+	if str, ok := _BatchStatusMap[x]; ok {
+		return str
+	}
+	return fmt.Sprintf("BatchStatus(%d)", x)
+}
+
+var _BatchStatusValue = map[string]BatchStatus{
+	_BatchStatusName[0:7]:   0,
+	_BatchStatusName[7:14]:  1,
+	_BatchStatusName[14:23]: 2,
+	_BatchStatusName[23:27]: 3,
+	_BatchStatusName[27:33]: 4,
+}
+
+// ParseBatchStatus attempts to convert a string to a BatchStatus
+func ParseBatchStatus(name string) (BatchStatus, error) {
+	if x, ok := _BatchStatusValue[name]; ok {
+		return x, nil
+	}
+	return BatchStatus(0), fmt.Errorf("%s is not a valid BatchStatus", name)
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rollout
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/helper/consulutil"
+)
+
+func TestRolloutValidate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		rollout Rollout
+		wantErr bool
+	}{
+		{"BatchSize", Rollout{DeploymentID: "d", NodeName: "n", WorkflowName: "upgrade", BatchSize: 2}, false},
+		{"BatchPercentage", Rollout{DeploymentID: "d", NodeName: "n", WorkflowName: "upgrade", BatchPercentage: 25, Canary: 1, Pause: time.Minute, HealthGateDelay: 30 * time.Second}, false},
+		{"MissingDeployment", Rollout{NodeName: "n", WorkflowName: "upgrade", BatchSize: 2}, true},
+		{"MissingNode", Rollout{DeploymentID: "d", WorkflowName: "upgrade", BatchSize: 2}, true},
+		{"MissingWorkflow", Rollout{DeploymentID: "d", NodeName: "n", BatchSize: 2}, true},
+		{"MissingBatch", Rollout{DeploymentID: "d", NodeName: "n", WorkflowName: "upgrade"}, true},
+		{"SizeAndPercentage", Rollout{DeploymentID: "d", NodeName: "n", WorkflowName: "upgrade", BatchSize: 2, BatchPercentage: 10}, true},
+		{"BadPercentage", Rollout{DeploymentID: "d", NodeName: "n", WorkflowName: "upgrade", BatchPercentage: 120}, true},
+		{"NegativeCanary", Rollout{DeploymentID: "d", NodeName: "n", WorkflowName: "upgrade", BatchSize: 2, Canary: -1}, true},
+		{"NegativePause", Rollout{DeploymentID: "d", NodeName: "n", WorkflowName: "upgrade", BatchSize: 2, Pause: -time.Second}, true},
+		{"NegativeHealthGate", Rollout{DeploymentID: "d", NodeName: "n", WorkflowName: "upgrade", BatchSize: 2, HealthGateDelay: -time.Second}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rollout.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestComputeBatches(t *testing.T) {
+	t.Parallel()
+	instances := []string{"0", "1", "2", "3", "4", "5", "6"}
+	tests := []struct {
+		name            string
+		canary          int
+		batchSize       int
+		batchPercentage int
+		want            [][]string
+	}{
+		{"BatchSize", 0, 3, 0, [][]string{{"0", "1", "2"}, {"3", "4", "5"}, {"6"}}},
+		{"BatchPercentage", 0, 0, 50, [][]string{{"0", "1", "2", "3"}, {"4", "5", "6"}}},
+		{"SmallPercentage", 0, 0, 1, [][]string{{"0"}, {"1"}, {"2"}, {"3"}, {"4"}, {"5"}, {"6"}}},
+		{"Canary", 1, 3, 0, [][]string{{"0"}, {"1", "2", "3"}, {"4", "5", "6"}}},
+		{"CanaryAll", 10, 3, 0, [][]string{{"0", "1", "2", "3", "4", "5", "6"}}},
+		{"AllAtOnce", 0, 0, 100, [][]string{{"0", "1", "2", "3", "4", "5", "6"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, computeBatches(instances, tt.canary, tt.batchSize, tt.batchPercentage))
+		})
+	}
+}
+
+func TestSortInstances(t *testing.T) {
+	t.Parallel()
+	instances := []string{"10", "2", "1", "0", "11"}
+	sortInstances(instances)
+	require.Equal(t, []string{"0", "1", "2", "10", "11"}, instances)
+}
+
+func TestRolloutUpdatedInstances(t *testing.T) {
+	t.Parallel()
+	r := &Rollout{Status: StatusFAILED, Batches: []Batch{
+		{Instances: []string{"0"}, Status: BatchStatusDONE},
+		{Instances: []string{"1", "2"}, Status: BatchStatusFAILED},
+		{Instances: []string{"3", "4"}, Status: BatchStatusINITIAL},
+	}}
+	require.Equal(t, []string{"0", "1", "2"}, r.UpdatedInstances())
+	require.False(t, r.IsActive())
+	r.Status = StatusROLLINGBACK
+	require.True(t, r.IsActive())
+}
+
+func TestIsRolloutDefinitionKey(t *testing.T) {
+	t.Parallel()
+	require.True(t, isRolloutDefinitionKey(path.Join(consulutil.RolloutsKVPrefix, "dep", "id", "definition")))
+	require.False(t, isRolloutDefinitionKey(path.Join(consulutil.RolloutsKVPrefix, "dep", "id", ".canceledFlag")))
+	require.False(t, isRolloutDefinitionKey(path.Join(consulutil.RolloutsKVPrefix, "dep", "definition")))
+}
@@ -0,0 +1,336 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tasks
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/log"
+)
+
+const (
+	// TaskStepSignalStatusWaiting is the status of a signal expected by a workflow step
+	TaskStepSignalStatusWaiting = "waiting"
+	// TaskStepSignalStatusReceived is the status of a signal delivered to a workflow step
+	TaskStepSignalStatusReceived = "received"
+	// TaskStepSignalStatusCanceled is the status of a signal that will never be delivered as its task was canceled
+	TaskStepSignalStatusCanceled = "canceled"
+)
+
+// TaskStepSignal describes an external signal expected by a workflow step of a task
+type TaskStepSignal struct {
+	Step         string            `json:"step"`
+	Signal       string            `json:"signal"`
+	Status       string            `json:"status"`
+	WaitingDate  time.Time         `json:"waiting_date"`
+	ReceivedDate *time.Time        `json:"received_date,omitempty"`
+	Inputs       map[string]string `json:"inputs,omitempty"`
+}
+
+type taskSignalError struct {
+	taskID   string
+	stepName string
+	message  string
+}
+
+func (e taskSignalError) Error() string {
+	return fmt.Sprintf("Can't signal step %q of task %q: %s", e.stepName, e.taskID, e.message)
+}
+
+// IsTaskSignalError checks if an error is due to a signal sent to a step which is not waiting for it
+func IsTaskSignalError(err error) bool {
+	_, ok := errors.Cause(err).(taskSignalError)
+	return ok
+}
+
+func signalsPath(taskID string) string {
+	return path.Join(consulutil.TasksPrefix, taskID, "signals")
+}
+
+// RegisterStepSignalWait records that a workflow step waits for an external signal
+//
+// A running execution is registered for the task until the signal is received or the task is canceled
+// in order to prevent the task to be considered as done while one of its steps is waiting.
+func RegisterStepSignalWait(taskID, stepName, signal string, now time.Time) error {
+	date, err := now.MarshalBinary()
+	if err != nil {
+		return errors.Wrap(err, "Failed to generate signal waiting date")
+	}
+	execID := fmt.Sprint(uuid.NewV4())
+	p := path.Join(signalsPath(taskID), stepName)
+	ops := api.KVTxnOps{
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(p, "signal"),
+			Value: []byte(signal),
+		},
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(p, "status"),
+			Value: []byte(TaskStepSignalStatusWaiting),
+		},
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(p, "waitingDate"),
+			Value: date,
+		},
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(p, "execution"),
+			Value: []byte(execID),
+		},
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(consulutil.TasksPrefix, taskID, ".runningExecutions", execID),
+			Value: []byte("waiting signal"),
+		},
+	}
+	log.Debugf("Storing runningExecutions with id %q for task %q step %q waiting for signal %q", execID, taskID, stepName, signal)
+	return StoreOperations(taskID, ops)
+}
+
+// GetTaskStepSignal returns the signal expected by a workflow step or nil if this step never waited for a signal
+func GetTaskStepSignal(taskID, stepName string) (*TaskStepSignal, error) {
+	p := path.Join(signalsPath(taskID), stepName)
+	kvs, err := consulutil.List(p + "/")
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if len(kvs) == 0 {
+		return nil, nil
+	}
+	signal := &TaskStepSignal{
+		Step:   stepName,
+		Signal: string(kvs[path.Join(p, "signal")]),
+		Status: string(kvs[path.Join(p, "status")]),
+	}
+	err = signal.WaitingDate.UnmarshalBinary(kvs[path.Join(p, "waitingDate")])
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid signal waiting date for step %q of task %q", stepName, taskID)
+	}
+	if v, ok := kvs[path.Join(p, "receivedDate")]; ok {
+		receivedDate := new(time.Time)
+		err = receivedDate.UnmarshalBinary(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid signal reception date for step %q of task %q", stepName, taskID)
+		}
+		signal.ReceivedDate = receivedDate
+	}
+	inputsPrefix := path.Join(p, "inputs") + "/"
+	for k, v := range kvs {
+		if path.Dir(k)+"/" != inputsPrefix {
+			continue
+		}
+		if signal.Inputs == nil {
+			signal.Inputs = make(map[string]string)
+		}
+		signal.Inputs[path.Base(k)] = string(v)
+	}
+	return signal, nil
+}
+
+// GetTaskStepSignals returns the signals expected or received by the workflow steps of a task
+//
+// Signals are sorted by waiting date.
+func GetTaskStepSignals(taskID string) ([]TaskStepSignal, error) {
+	steps, err := consulutil.GetKeys(signalsPath(taskID))
+	if err != nil {
+		return nil, err
+	}
+	signals := make([]TaskStepSignal, 0, len(steps))
+	for _, s := range steps {
+		signal, err := GetTaskStepSignal(taskID, path.Base(s))
+		if err != nil {
+			return nil, err
+		}
+		if signal != nil {
+			signals = append(signals, *signal)
+		}
+	}
+	sort.Slice(signals, func(i, j int) bool {
+		return signals[i].WaitingDate.Before(signals[j].WaitingDate)
+	})
+	return signals, nil
+}
+
+// GetTaskSignalsInputs returns the inputs provided by all the signals received by the workflow steps of a task
+//
+// If several signals provide the same input, the most recently received value is returned.
+func GetTaskSignalsInputs(taskID string) (map[string]string, error) {
+	signals, err := GetTaskStepSignals(taskID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(signals, func(i, j int) bool {
+		if signals[i].ReceivedDate == nil || signals[j].ReceivedDate == nil {
+			return signals[j].ReceivedDate != nil
+		}
+		return signals[i].ReceivedDate.Before(*signals[j].ReceivedDate)
+	})
+	inputs := make(map[string]string)
+	for _, signal := range signals {
+		for k, v := range signal.Inputs {
+			inputs[k] = v
+		}
+	}
+	return inputs, nil
+}
+
+// SignalTaskStep delivers a signal to a workflow step waiting for it
+//
+// Given inputs are stored as task inputs to be available for next steps of the workflow.
+// The step is then registered to be executed again in order to complete and continue the workflow.
+func SignalTaskStep(taskID, stepName string, inputs map[string]string, now time.Time) error {
+	execID, err := updateWaitingSignalStatus(taskID, stepName, TaskStepSignalStatusReceived)
+	if err != nil {
+		return err
+	}
+	date, err := now.MarshalBinary()
+	if err != nil {
+		return errors.Wrap(err, "Failed to generate signal reception date")
+	}
+	p := path.Join(signalsPath(taskID), stepName)
+	ops := api.KVTxnOps{
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(p, "receivedDate"),
+			Value: date,
+		},
+	}
+	for name, value := range inputs {
+		ops = append(ops, &api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(p, "inputs", name),
+			Value: []byte(value),
+		}, &api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(consulutil.TasksPrefix, taskID, "data", "inputs", name),
+			Value: []byte(value),
+		})
+	}
+	err = StoreOperations(taskID, ops)
+	if err != nil {
+		return err
+	}
+	return resumeWaitingStep(taskID, stepName, execID)
+}
+
+// CancelTaskStepSignal stops waiting for a signal on a workflow step of a canceled task
+//
+// The step is registered to be executed again in order to end on error.
+// It returns false if the step is not waiting for a signal.
+func CancelTaskStepSignal(taskID, stepName string) (bool, error) {
+	execID, err := updateWaitingSignalStatus(taskID, stepName, TaskStepSignalStatusCanceled)
+	if err != nil {
+		if IsTaskSignalError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, resumeWaitingStep(taskID, stepName, execID)
+}
+
+func cancelTaskStepsSignals(taskID string) error {
+	signals, err := GetTaskStepSignals(taskID)
+	if err != nil {
+		return err
+	}
+	for _, signal := range signals {
+		if signal.Status != TaskStepSignalStatusWaiting {
+			continue
+		}
+		_, err = CancelTaskStepSignal(taskID, signal.Step)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateWaitingSignalStatus atomically changes the status of a waiting signal
+// and returns the id of the running execution registered while waiting
+func updateWaitingSignalStatus(taskID, stepName, status string) (string, error) {
+	p := path.Join(signalsPath(taskID), stepName)
+	kvp, _, err := consulutil.GetKV().Get(path.Join(p, "status"), nil)
+	if err != nil {
+		return "", errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if kvp == nil {
+		return "", errors.WithStack(taskSignalError{taskID: taskID, stepName: stepName, message: "step is not waiting for a signal"})
+	}
+	if string(kvp.Value) != TaskStepSignalStatusWaiting {
+		return "", errors.WithStack(taskSignalError{taskID: taskID, stepName: stepName, message: fmt.Sprintf("signal already %s", string(kvp.Value))})
+	}
+	_, execID, err := consulutil.GetStringValue(path.Join(p, "execution"))
+	if err != nil {
+		return "", err
+	}
+	kvp.Value = []byte(status)
+	set, _, err := consulutil.GetKV().CAS(kvp, nil)
+	if err != nil {
+		return "", errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if !set {
+		return "", errors.WithStack(taskSignalError{taskID: taskID, stepName: stepName, message: "signal concurrently updated"})
+	}
+	return execID, nil
+}
+
+// resumeWaitingStep registers a new execution of a waiting step
+//
+// The running execution registered while waiting is replaced in the same transaction
+// so the task never appears without running executions.
+func resumeWaitingStep(taskID, stepName, waitingExecID string) error {
+	execID := fmt.Sprint(uuid.NewV4())
+	log.Debugf("Register task execution with ID:%q, taskID:%q and step:%q waiting for signal", execID, taskID, stepName)
+	stepExecPath := path.Join(consulutil.ExecutionsTaskPrefix, execID)
+	ops := api.KVTxnOps{
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(consulutil.WorkflowsPrefix, taskID, stepName),
+			Value: []byte(TaskStepStatusINITIAL.String()),
+		},
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(stepExecPath, "taskID"),
+			Value: []byte(taskID),
+		},
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(stepExecPath, "step"),
+			Value: []byte(stepName),
+		},
+		&api.KVTxnOp{
+			Verb:  api.KVSet,
+			Key:   path.Join(consulutil.TasksPrefix, taskID, ".runningExecutions", execID),
+			Value: []byte(""),
+		},
+	}
+	if waitingExecID != "" {
+		ops = append(ops, &api.KVTxnOp{
+			Verb: api.KVDelete,
+			Key:  path.Join(consulutil.TasksPrefix, taskID, ".runningExecutions", waitingExecID),
+		})
+	}
+	return StoreOperations(taskID, ops)
+}
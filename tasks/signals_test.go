@@ -0,0 +1,114 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tasks
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/helper/consulutil"
+)
+
+func getRunningExecutions(t *testing.T, taskID string) []string {
+	t.Helper()
+	keys, err := consulutil.GetKeys(path.Join(consulutil.TasksPrefix, taskID, ".runningExecutions"))
+	require.NoError(t, err)
+	execs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		execs = append(execs, path.Base(k))
+	}
+	return execs
+}
+
+func testTaskStepSignals(t *testing.T) {
+	taskID := "tSignal"
+	now := time.Now()
+
+	signal, err := GetTaskStepSignal(taskID, "pause")
+	require.NoError(t, err)
+	require.Nil(t, signal)
+
+	err = SignalTaskStep(taskID, "pause", nil, now)
+	require.True(t, IsTaskSignalError(err), "expected a signal error, got %+v", err)
+
+	require.NoError(t, RegisterStepSignalWait(taskID, "pause", "go_live", now))
+	signal, err = GetTaskStepSignal(taskID, "pause")
+	require.NoError(t, err)
+	require.NotNil(t, signal)
+	require.Equal(t, "pause", signal.Step)
+	require.Equal(t, "go_live", signal.Signal)
+	require.Equal(t, TaskStepSignalStatusWaiting, signal.Status)
+	require.True(t, signal.WaitingDate.Equal(now))
+	require.Nil(t, signal.ReceivedDate)
+	waitingExecs := getRunningExecutions(t, taskID)
+	require.Len(t, waitingExecs, 1)
+
+	err = SignalTaskStep(taskID, "pause", map[string]string{"version": "1.2"}, now.Add(time.Minute))
+	require.NoError(t, err)
+
+	signal, err = GetTaskStepSignal(taskID, "pause")
+	require.NoError(t, err)
+	require.Equal(t, TaskStepSignalStatusReceived, signal.Status)
+	require.NotNil(t, signal.ReceivedDate)
+	require.True(t, signal.ReceivedDate.Equal(now.Add(time.Minute)))
+	require.Equal(t, map[string]string{"version": "1.2"}, signal.Inputs)
+
+	input, err := GetTaskInput(taskID, "version")
+	require.NoError(t, err)
+	require.Equal(t, "1.2", input)
+
+	inputs, err := GetTaskSignalsInputs(taskID)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"version": "1.2"}, inputs)
+
+	// The waiting execution is replaced by an execution of the step
+	execs := getRunningExecutions(t, taskID)
+	require.Len(t, execs, 1)
+	require.NotEqual(t, waitingExecs[0], execs[0])
+	_, step, err := consulutil.GetStringValue(path.Join(consulutil.ExecutionsTaskPrefix, execs[0], "step"))
+	require.NoError(t, err)
+	require.Equal(t, "pause", step)
+	status, err := GetTaskStepStatus(taskID, "pause")
+	require.NoError(t, err)
+	require.Equal(t, TaskStepStatusINITIAL, status)
+
+	err = SignalTaskStep(taskID, "pause", nil, now)
+	require.True(t, IsTaskSignalError(err), "expected a signal error, got %+v", err)
+}
+
+func testCancelTaskWithWaitingSteps(t *testing.T) {
+	taskID := "tSignalCancel"
+	now := time.Now()
+	require.NoError(t, RegisterStepSignalWait(taskID, "pause1", "first", now))
+	require.NoError(t, RegisterStepSignalWait(taskID, "pause2", "second", now.Add(time.Second)))
+	require.NoError(t, SignalTaskStep(taskID, "pause1", nil, now.Add(time.Minute)))
+
+	require.NoError(t, CancelTask(taskID))
+
+	signals, err := GetTaskStepSignals(taskID)
+	require.NoError(t, err)
+	require.Len(t, signals, 2)
+	require.Equal(t, "pause1", signals[0].Step)
+	require.Equal(t, TaskStepSignalStatusReceived, signals[0].Status)
+	require.Equal(t, "pause2", signals[1].Step)
+	require.Equal(t, TaskStepSignalStatusCanceled, signals[1].Status)
+
+	canceled, err := CancelTaskStepSignal(taskID, "pause2")
+	require.NoError(t, err)
+	require.False(t, canceled)
+}
@@ -0,0 +1,145 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tasks
+
+import (
+	"path"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/helper/consulutil"
+)
+
+// TaskStepExecution describes the execution of a workflow step of a task
+type TaskStepExecution struct {
+	Step         string     `json:"step"`
+	StartDate    *time.Time `json:"start_date,omitempty"`
+	EndDate      *time.Time `json:"end_date,omitempty"`
+	Instances    []string   `json:"instances,omitempty"`
+	ErrorMessage string     `json:"error_message,omitempty"`
+}
+
+func stepsExecutionsPath(taskID string) string {
+	return path.Join(consulutil.TasksPrefix, taskID, "steps")
+}
+
+// stepStatusDatesOperations returns the operations recording the dates of a step status change
+//
+// The start date is recorded when a step starts running, previous end date and error message are then
+// removed as the step may be run again when a task is resumed. The end date is recorded when a step
+// reaches a final status.
+func stepStatusDatesOperations(taskID, stepName string, status TaskStepStatus, now time.Time) (api.KVTxnOps, error) {
+	p := path.Join(stepsExecutionsPath(taskID), stepName)
+	date, err := now.MarshalBinary()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to generate step status change date")
+	}
+	switch status {
+	case TaskStepStatusRUNNING:
+		return api.KVTxnOps{
+			&api.KVTxnOp{
+				Verb:  api.KVSet,
+				Key:   path.Join(p, "startDate"),
+				Value: date,
+			},
+			&api.KVTxnOp{
+				Verb: api.KVDelete,
+				Key:  path.Join(p, "endDate"),
+			},
+			&api.KVTxnOp{
+				Verb: api.KVDelete,
+				Key:  path.Join(p, "errorMessage"),
+			},
+		}, nil
+	case TaskStepStatusDONE, TaskStepStatusERROR, TaskStepStatusCANCELED:
+		return api.KVTxnOps{
+			&api.KVTxnOp{
+				Verb:  api.KVSet,
+				Key:   path.Join(p, "endDate"),
+				Value: date,
+			},
+		}, nil
+	}
+	return nil, nil
+}
+
+// SetTaskStepInstances records the node instances a workflow step runs on
+func SetTaskStepInstances(taskID, stepName string, instances []string) error {
+	return consulutil.StoreConsulKeyAsString(path.Join(stepsExecutionsPath(taskID), stepName, "instances"), strings.Join(instances, ","))
+}
+
+// SetTaskStepErrorMessage records the error that made a workflow step fail
+func SetTaskStepErrorMessage(taskID, stepName, errorMessage string) error {
+	return consulutil.StoreConsulKeyAsString(path.Join(stepsExecutionsPath(taskID), stepName, "errorMessage"), errorMessage)
+}
+
+// GetTaskStepExecution returns the execution details of a workflow step or nil if this step never ran
+func GetTaskStepExecution(taskID, stepName string) (*TaskStepExecution, error) {
+	p := path.Join(stepsExecutionsPath(taskID), stepName)
+	kvs, err := consulutil.List(p + "/")
+	if err != nil {
+		return nil, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if len(kvs) == 0 {
+		return nil, nil
+	}
+	exec := &TaskStepExecution{
+		Step:         stepName,
+		ErrorMessage: string(kvs[path.Join(p, "errorMessage")]),
+	}
+	if v := string(kvs[path.Join(p, "instances")]); v != "" {
+		exec.Instances = strings.Split(v, ",")
+	}
+	exec.StartDate, err = unmarshalOptionalDate(kvs[path.Join(p, "startDate")])
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid start date for step %q of task %q", stepName, taskID)
+	}
+	exec.EndDate, err = unmarshalOptionalDate(kvs[path.Join(p, "endDate")])
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid end date for step %q of task %q", stepName, taskID)
+	}
+	return exec, nil
+}
+
+// GetTaskStepsExecutions returns the execution details of the workflow steps of a task indexed by steps names
+func GetTaskStepsExecutions(taskID string) (map[string]TaskStepExecution, error) {
+	steps, err := consulutil.GetKeys(stepsExecutionsPath(taskID))
+	if err != nil {
+		return nil, err
+	}
+	executions := make(map[string]TaskStepExecution, len(steps))
+	for _, s := range steps {
+		exec, err := GetTaskStepExecution(taskID, path.Base(s))
+		if err != nil {
+			return nil, err
+		}
+		if exec != nil {
+			executions[exec.Step] = *exec
+		}
+	}
+	return executions, nil
+}
+
+func unmarshalOptionalDate(value []byte) (*time.Time, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+	date := new(time.Time)
+	err := date.UnmarshalBinary(value)
+	return date, err
+}
@@ -0,0 +1,64 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testTaskStepsExecutions(t *testing.T) {
+	taskID := "tStepsExec"
+
+	exec, err := GetTaskStepExecution(taskID, "install")
+	require.NoError(t, err)
+	require.Nil(t, exec)
+
+	require.NoError(t, UpdateTaskStepWithStatus(taskID, "install", TaskStepStatusRUNNING))
+	require.NoError(t, SetTaskStepInstances(taskID, "install", []string{"0", "1"}))
+	require.NoError(t, UpdateTaskStepWithStatus(taskID, "install", TaskStepStatusERROR))
+	require.NoError(t, SetTaskStepErrorMessage(taskID, "install", "operation failed"))
+
+	status, err := GetTaskStepStatus(taskID, "install")
+	require.NoError(t, err)
+	require.Equal(t, TaskStepStatusERROR, status)
+
+	exec, err = GetTaskStepExecution(taskID, "install")
+	require.NoError(t, err)
+	require.NotNil(t, exec)
+	require.Equal(t, "install", exec.Step)
+	require.NotNil(t, exec.StartDate)
+	require.NotNil(t, exec.EndDate)
+	require.False(t, exec.EndDate.Before(*exec.StartDate))
+	require.Equal(t, []string{"0", "1"}, exec.Instances)
+	require.Equal(t, "operation failed", exec.ErrorMessage)
+
+	// Running the step again resets its end date and error
+	require.NoError(t, UpdateTaskStepWithStatus(taskID, "install", TaskStepStatusRUNNING))
+	exec, err = GetTaskStepExecution(taskID, "install")
+	require.NoError(t, err)
+	require.NotNil(t, exec.StartDate)
+	require.Nil(t, exec.EndDate)
+	require.Empty(t, exec.ErrorMessage)
+
+	// A skipped step has only an end date
+	require.NoError(t, UpdateTaskStepWithStatus(taskID, "configure", TaskStepStatusDONE))
+	executions, err := GetTaskStepsExecutions(taskID)
+	require.NoError(t, err)
+	require.Len(t, executions, 2)
+	require.Nil(t, executions["configure"].StartDate)
+	require.NotNil(t, executions["configure"].EndDate)
+}
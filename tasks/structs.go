@@ -43,6 +43,7 @@ RUNNING
 DONE
 FAILED
 CANCELED
+PENDING_APPROVAL
 )
 */
 type TaskStatus int
@@ -32,9 +32,11 @@ const (
 	TaskStatusFAILED
 	// TaskStatusCANCELED is a TaskStatus of type CANCELED
 	TaskStatusCANCELED
+	// TaskStatusPENDINGAPPROVAL is a TaskStatus of type PENDING_APPROVAL
+	TaskStatusPENDINGAPPROVAL
 )
 
-const _TaskStatusName = "INITIALRUNNINGDONEFAILEDCANCELED"
+const _TaskStatusName = "INITIALRUNNINGDONEFAILEDCANCELEDPENDING_APPROVAL"
 
 var _TaskStatusMap = map[TaskStatus]string{
 	0: _TaskStatusName[0:7],
@@ -42,6 +44,7 @@ var _TaskStatusMap = map[TaskStatus]string{
 	2: _TaskStatusName[14:18],
 	3: _TaskStatusName[18:24],
 	4: _TaskStatusName[24:32],
+	5: _TaskStatusName[32:48],
 }
 
 // String implements the Stringer interface.
@@ -58,6 +61,7 @@ var _TaskStatusValue = map[string]TaskStatus{
 	_TaskStatusName[14:18]: 2,
 	_TaskStatusName[18:24]: 3,
 	_TaskStatusName[24:32]: 4,
+	_TaskStatusName[32:48]: 5,
 }
 
 // ParseTaskStatus attempts to convert a string to a TaskStatus
@@ -24,6 +24,7 @@ RUNNING
 DONE
 ERROR
 CANCELED
+WAITING
 )
 */
 type TaskStepStatus int
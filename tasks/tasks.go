@@ -210,7 +210,7 @@ func GetTaskStatus(taskID string) (TaskStatus, error) {
 	if err != nil {
 		return TaskStatusFAILED, errors.Wrapf(err, "Invalid task status:")
 	}
-	if statusInt < 0 || statusInt > int(TaskStatusCANCELED) {
+	if statusInt < 0 || statusInt > int(TaskStatusPENDINGAPPROVAL) {
 		return TaskStatusFAILED, errors.Errorf("Invalid status for task with id %q: %q", taskID, value)
 	}
 	return TaskStatus(statusInt), nil
@@ -264,6 +264,49 @@ func GetTaskCreationDate(taskID string) (time.Time, error) {
 	return creationDate, nil
 }
 
+// GetTaskEndDate retrieves the date when a task reached its last final status (DONE, FAILED or CANCELED)
+//
+// A zero time is returned if the task never reached a final status or if it was created by a Yorc version
+// that did not record it.
+func GetTaskEndDate(taskID string) (time.Time, error) {
+	endDate := time.Time{}
+	exist, value, err := consulutil.GetValue(path.Join(consulutil.TasksPrefix, taskID, "endDate"))
+	if err != nil {
+		return endDate, errors.Wrap(err, consulutil.ConsulGenericErrMsg)
+	}
+	if !exist || len(value) == 0 {
+		return endDate, nil
+	}
+	err = endDate.UnmarshalBinary(value)
+	return endDate, errors.Wrapf(err, "Failed to get task endDate for task with id %q", taskID)
+}
+
+// SetTaskEndDate stores the date when a task reached a final status
+func SetTaskEndDate(taskID string, endDate time.Time) error {
+	value, err := endDate.MarshalBinary()
+	if err != nil {
+		return errors.Wrapf(err, "Failed to store task endDate for task with id %q", taskID)
+	}
+	return consulutil.StoreConsulKey(path.Join(consulutil.TasksPrefix, taskID, "endDate"), value)
+}
+
+// IsFinalStatus returns true if the given task status is DONE, FAILED or CANCELED
+func IsFinalStatus(status TaskStatus) bool {
+	return status == TaskStatusDONE || status == TaskStatusFAILED || status == TaskStatusCANCELED
+}
+
+// GetTasksIDs returns IDs of all known tasks
+func GetTasksIDs() ([]string, error) {
+	tasksKeys, err := consulutil.GetKeys(consulutil.TasksPrefix)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasksKeys {
+		tasksKeys[i] = path.Base(tasksKeys[i])
+	}
+	return tasksKeys, nil
+}
+
 // TaskExists checks if a task with the given taskID exists
 func TaskExists(taskID string) (bool, error) {
 	exist, value, err := consulutil.GetStringValue(path.Join(consulutil.TasksPrefix, taskID, "targetId"))
@@ -308,8 +351,14 @@ func StoreOperations(taskID string, operations api.KVTxnOps) error {
 }
 
 // CancelTask marks a task as Canceled
+//
+// Workflow steps of this task waiting for a signal stop waiting.
 func CancelTask(taskID string) error {
-	return consulutil.StoreConsulKeyAsString(path.Join(consulutil.TasksPrefix, taskID, ".canceledFlag"), "true")
+	err := consulutil.StoreConsulKeyAsString(path.Join(consulutil.TasksPrefix, taskID, ".canceledFlag"), "true")
+	if err != nil {
+		return err
+	}
+	return cancelTaskStepsSignals(taskID)
 }
 
 // DeleteTask allows to delete a stored task
@@ -317,7 +366,7 @@ func DeleteTask(taskID string) error {
 	return consulutil.Delete(path.Join(consulutil.TasksPrefix, taskID)+"/", true)
 }
 
-// TargetHasLivingTasks checks if a targetID has associated tasks in status INITIAL or RUNNING and returns the id and status of the first one found
+// TargetHasLivingTasks checks if a targetID has associated tasks in status INITIAL, RUNNING or PENDING_APPROVAL and returns the id and status of the first one found
 //
 // The last argument specifies tasks types which should be ignored.
 //
@@ -330,7 +379,7 @@ func TargetHasLivingTasks(targetID string, tasksTypesToIgnore []TaskType) (bool,
 	return HasLivingTasks(taskIDs, tasksTypesToIgnore)
 }
 
-// HasLivingTasks checks if the tasks list contains tasks in status INITIAL or RUNNING and returns the id and status of the first one found
+// HasLivingTasks checks if the tasks list contains tasks in status INITIAL, RUNNING or PENDING_APPROVAL and returns the id and status of the first one found
 //
 // The last argument specifies tasks types which should be ignored.
 func HasLivingTasks(taskIDs []string, tasksTypesToIgnore []TaskType) (bool, string, string, error) {
@@ -341,7 +390,7 @@ func HasLivingTasks(taskIDs []string, tasksTypesToIgnore []TaskType) (bool, stri
 			return false, "", "", err
 		}
 
-		if tStatus == TaskStatusINITIAL || tStatus == TaskStatusRUNNING {
+		if tStatus == TaskStatusINITIAL || tStatus == TaskStatusRUNNING || tStatus == TaskStatusPENDINGAPPROVAL {
 
 			// Check if this task type should be ignored
 			tType, err := GetTaskType(taskID)
@@ -525,8 +574,19 @@ func UpdateTaskStepStatus(taskID string, step *TaskStep) error {
 }
 
 // UpdateTaskStepWithStatus allows to update the task step status
+//
+// Start and end dates of the step execution are recorded along with the status change.
 func UpdateTaskStepWithStatus(taskID, stepName string, status TaskStepStatus) error {
-	return consulutil.StoreConsulKeyAsString(path.Join(consulutil.WorkflowsPrefix, taskID, stepName), status.String())
+	ops, err := stepStatusDatesOperations(taskID, stepName, status, time.Now())
+	if err != nil {
+		return err
+	}
+	ops = append(ops, &api.KVTxnOp{
+		Verb:  api.KVSet,
+		Key:   path.Join(consulutil.WorkflowsPrefix, taskID, stepName),
+		Value: []byte(status.String()),
+	})
+	return consulutil.ExecuteSplittableTransaction(ops, nil, nil)
 }
 
 // CheckTaskStepStatusChange checks if a status change is allowed
@@ -68,10 +68,10 @@ func populateKV(t *testing.T, srv *testutil.TestServer) {
 		consulutil.TasksPrefix + "/tCustomWF/status":    []byte("0"),
 		consulutil.TasksPrefix + "/tCustomWF/type":      []byte("6"),
 		consulutil.TasksPrefix + "/t6/targetId":         []byte("id"),
-		consulutil.TasksPrefix + "/t6/status":           []byte("5"),
+		consulutil.TasksPrefix + "/t6/status":           []byte("6"),
 		consulutil.TasksPrefix + "/t6/type":             []byte("5"),
 		consulutil.TasksPrefix + "/t7/targetId":         []byte("id"),
-		consulutil.TasksPrefix + "/t7/status":           []byte("5"),
+		consulutil.TasksPrefix + "/t7/status":           []byte("6"),
 		consulutil.TasksPrefix + "/t7/type":             []byte("6666"),
 		consulutil.TasksPrefix + "/tNotInt/targetId":    []byte("targetNotInt"),
 		consulutil.TasksPrefix + "/tNotInt/status":      []byte("not a status"),
@@ -110,6 +110,10 @@ func populateKV(t *testing.T, srv *testutil.TestServer) {
 		consulutil.TasksPrefix + "/t18/targetId": []byte("infra_usage:slurm"),
 		consulutil.TasksPrefix + "/t18/status":   []byte("2"),
 		consulutil.TasksPrefix + "/t18/type":     []byte("7"),
+
+		consulutil.TasksPrefix + "/t19/targetId": []byte("id3"),
+		consulutil.TasksPrefix + "/t19/status":   []byte("5"),
+		consulutil.TasksPrefix + "/t19/type":     []byte("4"),
 	})
 }
 
@@ -169,6 +173,7 @@ func testGetTaskStatus(t *testing.T) {
 		{"StatusDONE", args{"t3"}, TaskStatusDONE, false},
 		{"StatusFAILED", args{"t4"}, TaskStatusFAILED, false},
 		{"StatusCANCELED", args{"t5"}, TaskStatusCANCELED, false},
+		{"StatusPENDINGAPPROVAL", args{"t19"}, TaskStatusPENDINGAPPROVAL, false},
 		{"StatusDoesntExist", args{"t6"}, TaskStatusFAILED, true},
 		{"StatusNotInt", args{"tNotInt"}, TaskStatusFAILED, true},
 		{"TaskDoesntExist", args{"TaskDoesntExist"}, TaskStatusFAILED, true},
@@ -327,6 +332,7 @@ func testTargetHasLivingTasks(t *testing.T) {
 		{"TargetHasNoRunningDeployTasks", args{"id1",
 			[]TaskType{TaskTypeDeploy, TaskTypeUnDeploy, TaskTypeScaleIn}}, false, "", "", false},
 		{"TargetHasNoRunningTasks", args{"id2", []TaskType{}}, false, "", "", false},
+		{"TargetHasPendingApprovalTasks", args{"id3", []TaskType{}}, true, "t19", "PENDING_APPROVAL", false},
 		{"TargetDoesntExist", args{"TargetDoesntExist", []TaskType{}}, false, "", "", false},
 		{"TargetNotInt", args{"targetNotInt", []TaskType{}}, false, "", "", true},
 	}
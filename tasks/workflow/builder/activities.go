@@ -25,6 +25,7 @@ delegate
 set-state
 call-operation
 inline
+wait-for-signal
 )
 */
 type ActivityType int
@@ -40,10 +41,12 @@ type Activity interface {
 	// For set-state activities it's the state value.
 	// For call-operation activities it's the operation name.
 	// For inline activities it's the inlined workflow name.
+	// For wait-for-signal activities it's the signal name.
 	Value() string
 
 	// Inputs returns the inputs parameters defined in the activity
-	// It is not relevant for set-state activities
+	// It is not relevant for set-state activities.
+	// For wait-for-signal activities it's the expected signal payload.
 	Inputs() map[string]tosca.ParameterDefinition
 }
 
@@ -105,3 +108,18 @@ func (i inlineActivity) Value() string {
 func (i inlineActivity) Inputs() map[string]tosca.ParameterDefinition {
 	return i.inputs
 }
+
+type waitForSignalActivity struct {
+	signal string
+	inputs map[string]tosca.ParameterDefinition
+}
+
+func (w waitForSignalActivity) Type() ActivityType {
+	return ActivityTypeWaitForSignal
+}
+func (w waitForSignalActivity) Value() string {
+	return w.signal
+}
+func (w waitForSignalActivity) Inputs() map[string]tosca.ParameterDefinition {
+	return w.inputs
+}
@@ -31,15 +31,18 @@ const (
 	ActivityTypeCallOperation
 	// ActivityTypeInline is a ActivityType of type Inline
 	ActivityTypeInline
+	// ActivityTypeWaitForSignal is a ActivityType of type Wait-For-Signal
+	ActivityTypeWaitForSignal
 )
 
-const _ActivityTypeName = "delegateset-statecall-operationinline"
+const _ActivityTypeName = "delegateset-statecall-operationinlinewait-for-signal"
 
 var _ActivityTypeMap = map[ActivityType]string{
 	0: _ActivityTypeName[0:8],
 	1: _ActivityTypeName[8:17],
 	2: _ActivityTypeName[17:31],
 	3: _ActivityTypeName[31:37],
+	4: _ActivityTypeName[37:52],
 }
 
 // String implements the Stringer interface.
@@ -59,6 +62,8 @@ var _ActivityTypeValue = map[string]ActivityType{
 	strings.ToLower(_ActivityTypeName[17:31]): 2,
 	_ActivityTypeName[31:37]:                  3,
 	strings.ToLower(_ActivityTypeName[31:37]): 3,
+	_ActivityTypeName[37:52]:                  4,
+	strings.ToLower(_ActivityTypeName[37:52]): 4,
 }
 
 // ParseActivityType attempts to convert a string to a ActivityType
@@ -113,6 +113,7 @@ func buildStepFromWFStep(deploymentID, wfName, stepName string, wfSteps map[stri
 		OperationHost:      wfStep.OperationHost,
 		TargetRelationship: wfStep.TargetRelationShip,
 		Target:             wfStep.Target,
+		Filter:             wfStep.Filter,
 		Activities:         make([]Activity, 0, len(wfStep.Activities)),
 	}
 
@@ -159,6 +160,11 @@ func buildStepActivities(s *Step, wfStep *tosca.Step) (bool, error) {
 			s.Activities = append(s.Activities, setStateActivity{state: wfActivity.SetState})
 		} else if wfActivity.Inline != nil {
 			s.Activities = append(s.Activities, inlineActivity{wfActivity.Inline.Workflow, wfActivity.Inline.Inputs})
+		} else if wfActivity.WaitForSignal != nil {
+			if len(wfStep.Activities) > 1 {
+				return false, errors.Errorf("Step %q: a wait_for_signal activity should be the only activity of its step", s.Name)
+			}
+			s.Activities = append(s.Activities, waitForSignalActivity{wfActivity.WaitForSignal.Signal, wfActivity.WaitForSignal.Inputs})
 		} else {
 			return false, errors.Errorf("Unsupported activity type for step: %q, activity nb: %d, activity: %+v", s.Name, i, wfActivity)
 		}
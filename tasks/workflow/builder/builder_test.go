@@ -197,3 +197,36 @@ func testBuildWorkFlow(t *testing.T, srv1 *testutil.TestServer) {
 	require.Nil(t, err, "oups")
 	require.Len(t, steps, 6)
 }
+
+func TestBuildStepActivitiesWaitForSignal(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name                  string
+		activities            []tosca.Activity
+		wantTargetIsMandatory bool
+		wantErr               bool
+	}{
+		{"WaitForSignalAlone", []tosca.Activity{
+			{WaitForSignal: &tosca.SignalActivity{Signal: "go_live"}},
+		}, false, false},
+		{"WaitForSignalWithOtherActivities", []tosca.Activity{
+			{WaitForSignal: &tosca.SignalActivity{Signal: "go_live"}},
+			{SetState: "started"},
+		}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Step{Name: tt.name}
+			targetIsMandatory, err := buildStepActivities(s, &tosca.Step{Activities: tt.activities})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantTargetIsMandatory, targetIsMandatory)
+			require.Len(t, s.Activities, 1)
+			require.Equal(t, ActivityTypeWaitForSignal, s.Activities[0].Type())
+			require.Equal(t, "go_live", s.Activities[0].Value())
+		})
+	}
+}
@@ -14,12 +14,15 @@
 
 package builder
 
+import "github.com/ystia/yorc/v4/tosca"
+
 // Step represents the workflow step
 type Step struct {
 	Name               string
 	Target             string
 	TargetRelationship string
 	OperationHost      string
+	Filter             []tosca.ConditionClause
 	Activities         []Activity
 	Next               []*Step
 	OnFailure          []*Step
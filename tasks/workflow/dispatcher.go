@@ -24,6 +24,7 @@ import (
 	"github.com/hashicorp/consul/api"
 	"github.com/pkg/errors"
 
+	"github.com/ystia/yorc/v4/cluster"
 	"github.com/ystia/yorc/v4/config"
 	"github.com/ystia/yorc/v4/helper/consulutil"
 	"github.com/ystia/yorc/v4/log"
@@ -32,6 +33,9 @@ import (
 
 const executionLockPrefix = ".processingLock-"
 
+// drainCheckInterval is the interval between two checks of the server drain status by a draining dispatcher
+const drainCheckInterval = 5 * time.Second
+
 // Dispatcher concern is polling executions task and dispatch them across available workers
 // It has to acquire a lock on the execution task as other distributed dispatchers can try to do the same
 // If it gets the lock, it instantiates an execution task and push it to workers pool
@@ -157,6 +161,18 @@ func (d *Dispatcher) Run() {
 			return
 		default:
 		}
+		if cluster.IsDraining() {
+			// Let other servers of the cluster take task executions.
+			// Reset the wait index to look at all pending executions once resumed.
+			waitIndex = 0
+			select {
+			case <-d.shutdownCh:
+				log.Printf("Dispatcher received shutdown signal. Exiting...")
+				return
+			case <-time.After(drainCheckInterval):
+			}
+			continue
+		}
 		q := &api.QueryOptions{
 			WaitIndex: waitIndex,
 			WaitTime:  d.cfg.Tasks.Dispatcher.LongPollWaitTime,
@@ -177,6 +193,9 @@ func (d *Dispatcher) Run() {
 			if strings.HasPrefix(execID, executionLockPrefix) {
 				continue
 			}
+			if cluster.IsDraining() {
+				break
+			}
 
 			log.Debugf("Try to acquire processing lock for task execution %s", execKey)
 			opts := &api.LockOptions{
@@ -0,0 +1,170 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package graph builds the execution graph of a workflow task and renders it
+// using the GraphViz DOT or Mermaid formats.
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/deployments"
+	"github.com/ystia/yorc/v4/tasks"
+	"github.com/ystia/yorc/v4/tasks/workflow/builder"
+)
+
+const (
+	// EdgeTypeOnSuccess is the type of an edge to a step executed when the previous step succeeds
+	EdgeTypeOnSuccess = "on_success"
+	// EdgeTypeOnFailure is the type of an edge to a step executed when the previous step fails
+	EdgeTypeOnFailure = "on_failure"
+	// EdgeTypeOnCancel is the type of an edge to a step executed when the task is canceled
+	EdgeTypeOnCancel = "on_cancel"
+)
+
+// A Graph is the execution graph of a workflow task
+type Graph struct {
+	TaskID       string `json:"task_id"`
+	DeploymentID string `json:"deployment_id"`
+	WorkflowName string `json:"workflow_name"`
+	TaskStatus   string `json:"task_status"`
+	Steps        []Step `json:"steps"`
+	Edges        []Edge `json:"edges"`
+}
+
+// A Step is a workflow step of an execution graph with its execution status
+type Step struct {
+	Name         string     `json:"name"`
+	Target       string     `json:"target,omitempty"`
+	Activities   []string   `json:"activities,omitempty"`
+	Status       string     `json:"status"`
+	StartDate    *time.Time `json:"start_date,omitempty"`
+	EndDate      *time.Time `json:"end_date,omitempty"`
+	Duration     string     `json:"duration,omitempty"`
+	Instances    []string   `json:"instances,omitempty"`
+	ErrorMessage string     `json:"error_message,omitempty"`
+}
+
+// An Edge links two steps of an execution graph
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"`
+}
+
+// Build returns the execution graph of a workflow task
+//
+// An error checkable using tasks.IsTaskDataNotFoundError is returned if the task is not a workflow task.
+func Build(ctx context.Context, deploymentID, taskID string) (*Graph, error) {
+	workflowName, err := tasks.GetTaskData(taskID, "workflowName")
+	if err != nil {
+		return nil, err
+	}
+	taskStatus, err := tasks.GetTaskStatus(taskID)
+	if err != nil {
+		return nil, err
+	}
+	g := &Graph{
+		TaskID:       taskID,
+		DeploymentID: deploymentID,
+		WorkflowName: workflowName,
+		TaskStatus:   taskStatus.String(),
+		Steps:        make([]Step, 0),
+		Edges:        make([]Edge, 0),
+	}
+
+	wf, err := deployments.GetWorkflow(ctx, deploymentID, workflowName)
+	if err != nil {
+		return nil, err
+	}
+	if wf == nil {
+		return nil, errors.Errorf("Can't build execution graph of task %q, workflow %q not found in deployment %q", taskID, workflowName, deploymentID)
+	}
+	if len(wf.Steps) == 0 {
+		return g, nil
+	}
+	wfSteps, err := builder.BuildWorkFlow(ctx, deploymentID, workflowName)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := tasks.GetTaskRelatedSteps(taskID)
+	if err != nil {
+		return nil, err
+	}
+	stepsStatuses := make(map[string]string, len(statuses))
+	for _, s := range statuses {
+		stepsStatuses[s.Name] = s.Status
+	}
+	executions, err := tasks.GetTaskStepsExecutions(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, wfStep := range wfSteps {
+		step := Step{
+			Name:   wfStep.Name,
+			Target: wfStep.Target,
+			Status: tasks.TaskStepStatusINITIAL.String(),
+		}
+		// Steps not yet registered have no status
+		if status, err := tasks.ParseTaskStepStatus(stepsStatuses[wfStep.Name]); err == nil {
+			step.Status = status.String()
+		}
+		for _, a := range wfStep.Activities {
+			step.Activities = append(step.Activities, fmt.Sprintf("%s %s", a.Type(), a.Value()))
+		}
+		if exec, ok := executions[wfStep.Name]; ok {
+			step.StartDate = exec.StartDate
+			step.EndDate = exec.EndDate
+			step.Instances = exec.Instances
+			step.ErrorMessage = exec.ErrorMessage
+			if exec.StartDate != nil && exec.EndDate != nil {
+				step.Duration = exec.EndDate.Sub(*exec.StartDate).Round(time.Millisecond).String()
+			}
+		}
+		g.Steps = append(g.Steps, step)
+
+		g.Edges = appendEdges(g.Edges, wfStep.Name, wfStep.Next, EdgeTypeOnSuccess)
+		g.Edges = appendEdges(g.Edges, wfStep.Name, wfStep.OnFailure, EdgeTypeOnFailure)
+		g.Edges = appendEdges(g.Edges, wfStep.Name, wfStep.OnCancel, EdgeTypeOnCancel)
+	}
+
+	// Sort steps and edges to get a stable representation
+	sort.Slice(g.Steps, func(i, j int) bool {
+		return g.Steps[i].Name < g.Steps[j].Name
+	})
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		if g.Edges[i].Type != g.Edges[j].Type {
+			return g.Edges[i].Type < g.Edges[j].Type
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+	return g, nil
+}
+
+func appendEdges(edges []Edge, from string, steps []*builder.Step, edgeType string) []Edge {
+	for _, s := range steps {
+		edges = append(edges, Edge{From: from, To: s.Name, Type: edgeType})
+	}
+	return edges
+}
@@ -0,0 +1,101 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/hashicorp/consul/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/helper/consulutil"
+	"github.com/ystia/yorc/v4/storage"
+	"github.com/ystia/yorc/v4/storage/types"
+	"github.com/ystia/yorc/v4/tasks"
+	"github.com/ystia/yorc/v4/tosca"
+)
+
+func testBuild(t *testing.T, srv *testutil.TestServer) {
+	ctx := context.Background()
+	deploymentID := "dep_" + path.Base(t.Name())
+	taskID := "task_" + path.Base(t.Name())
+
+	wf := tosca.Workflow{Steps: map[string]*tosca.Step{
+		"Compute_install": {
+			Target:    "Compute",
+			OnSuccess: []string{"App_start"},
+			Activities: []tosca.Activity{
+				{Delegate: &tosca.WorkflowActivity{Workflow: "install"}},
+			},
+		},
+		"App_start": {
+			Target:    "App",
+			OnFailure: []string{"cleanup"},
+			Activities: []tosca.Activity{
+				{CallOperation: &tosca.OperationActivity{Operation: "Standard.start"}},
+			},
+		},
+		"cleanup": {
+			Activities: []tosca.Activity{
+				{WaitForSignal: &tosca.SignalActivity{Signal: "cleaned"}},
+			},
+		},
+	}}
+	err := storage.GetStore(types.StoreTypeDeployment).Set(ctx, path.Join(consulutil.DeploymentKVPrefix, deploymentID, "workflows", "install"), wf)
+	require.NoError(t, err)
+
+	srv.PopulateKV(t, map[string][]byte{
+		path.Join(consulutil.TasksPrefix, taskID, "targetId"):          []byte(deploymentID),
+		path.Join(consulutil.TasksPrefix, taskID, "status"):            []byte("3"),
+		path.Join(consulutil.TasksPrefix, taskID, "type"):              []byte("0"),
+		path.Join(consulutil.TasksPrefix, taskID, "data/workflowName"): []byte("install"),
+	})
+	require.NoError(t, tasks.UpdateTaskStepWithStatus(taskID, "Compute_install", tasks.TaskStepStatusRUNNING))
+	require.NoError(t, tasks.SetTaskStepInstances(taskID, "Compute_install", []string{"0"}))
+	require.NoError(t, tasks.UpdateTaskStepWithStatus(taskID, "Compute_install", tasks.TaskStepStatusDONE))
+	require.NoError(t, tasks.UpdateTaskStepWithStatus(taskID, "App_start", tasks.TaskStepStatusRUNNING))
+	require.NoError(t, tasks.UpdateTaskStepWithStatus(taskID, "App_start", tasks.TaskStepStatusERROR))
+	require.NoError(t, tasks.SetTaskStepErrorMessage(taskID, "App_start", "start failed"))
+
+	g, err := Build(ctx, deploymentID, taskID)
+	require.NoError(t, err)
+	require.Equal(t, "install", g.WorkflowName)
+	require.Equal(t, tasks.TaskStatusFAILED.String(), g.TaskStatus)
+	require.Len(t, g.Steps, 3)
+
+	require.Equal(t, "App_start", g.Steps[0].Name)
+	require.Equal(t, "ERROR", g.Steps[0].Status)
+	require.Equal(t, "start failed", g.Steps[0].ErrorMessage)
+	require.Equal(t, []string{"call-operation Standard.start"}, g.Steps[0].Activities)
+
+	require.Equal(t, "Compute_install", g.Steps[1].Name)
+	require.Equal(t, "DONE", g.Steps[1].Status)
+	require.Equal(t, []string{"0"}, g.Steps[1].Instances)
+	require.NotEmpty(t, g.Steps[1].Duration)
+
+	require.Equal(t, "cleanup", g.Steps[2].Name)
+	require.Equal(t, "INITIAL", g.Steps[2].Status)
+	require.Nil(t, g.Steps[2].StartDate)
+
+	require.Equal(t, []Edge{
+		{From: "App_start", To: "cleanup", Type: EdgeTypeOnFailure},
+		{From: "Compute_install", To: "App_start", Type: EdgeTypeOnSuccess},
+	}, g.Edges)
+
+	_, err = Build(ctx, deploymentID, "unknownTask")
+	require.True(t, tasks.IsTaskDataNotFoundError(err), "unexpected error %v", err)
+}
@@ -0,0 +1,172 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tmc/dot"
+
+	"github.com/ystia/yorc/v4/tasks"
+)
+
+// maxErrorLength is the maximum length of error messages displayed in steps labels
+const maxErrorLength = 80
+
+var statusColors = map[string]string{
+	tasks.TaskStepStatusINITIAL.String():  "#d3d3d3",
+	tasks.TaskStepStatusRUNNING.String():  "#87cefa",
+	tasks.TaskStepStatusDONE.String():     "#98fb98",
+	tasks.TaskStepStatusERROR.String():    "#ff6347",
+	tasks.TaskStepStatusCANCELED.String(): "#ffa500",
+	tasks.TaskStepStatusWAITING.String():  "#ffd700",
+}
+
+func statusColor(status string) string {
+	if c, ok := statusColors[status]; ok {
+		return c
+	}
+	return statusColors[tasks.TaskStepStatusINITIAL.String()]
+}
+
+// labelLines returns the lines describing a step in graph representations
+func (s Step) labelLines() []string {
+	lines := []string{s.Name}
+	status := s.Status
+	if s.Duration != "" {
+		status += " (" + s.Duration + ")"
+	}
+	lines = append(lines, status)
+	if len(s.Instances) > 0 {
+		lines = append(lines, "instances: "+strings.Join(s.Instances, ","))
+	}
+	if s.ErrorMessage != "" {
+		msg := strings.Join(strings.Fields(s.ErrorMessage), " ")
+		if r := []rune(msg); len(r) > maxErrorLength {
+			msg = string(r[:maxErrorLength-3]) + "..."
+		}
+		lines = append(lines, "error: "+msg)
+	}
+	return lines
+}
+
+// DOT renders the execution graph using the GraphViz DOT format
+//
+// Steps are colored according to their status, steps executed on failure or on cancellation
+// are linked using dashed edges.
+func (g *Graph) DOT(horizontal bool) string {
+	graph := dot.NewGraph("Task " + g.TaskID)
+	graph.SetType(dot.DIGRAPH)
+	graph.Set("label", fmt.Sprintf("%s (%s)", g.WorkflowName, g.TaskStatus))
+	graph.Set("labelloc", "t")
+	if horizontal {
+		graph.Set("rankdir", "LR")
+	}
+	graph.SetGlobalNodeAttr("shape", "box")
+	// Quoted as dot doesn't quote values containing commas
+	graph.SetGlobalNodeAttr("style", `"rounded,filled"`)
+
+	nodes := make(map[string]*dot.Node, len(g.Steps))
+	for _, s := range g.Steps {
+		node := dot.NewNode(s.Name)
+		node.Set("label", strings.Join(s.labelLines(), "\n"))
+		node.Set("fillcolor", statusColor(s.Status))
+		if s.ErrorMessage != "" {
+			node.Set("tooltip", s.ErrorMessage)
+		}
+		nodes[s.Name] = node
+		graph.AddNode(node)
+	}
+	for _, e := range g.Edges {
+		from, to := nodes[e.From], nodes[e.To]
+		if from == nil || to == nil {
+			continue
+		}
+		edge := dot.NewEdge(from, to)
+		switch e.Type {
+		case EdgeTypeOnFailure:
+			edge.Set("style", "dashed")
+			edge.Set("color", "red")
+			edge.Set("label", e.Type)
+		case EdgeTypeOnCancel:
+			edge.Set("style", "dashed")
+			edge.Set("color", "orange")
+			edge.Set("label", e.Type)
+		}
+		graph.AddEdge(edge)
+	}
+	return graph.String()
+}
+
+// Mermaid renders the execution graph using the Mermaid flowchart format
+//
+// Steps are colored according to their status, steps executed on failure or on cancellation
+// are linked using dotted edges.
+func (g *Graph) Mermaid(horizontal bool) string {
+	var b strings.Builder
+	direction := "TD"
+	if horizontal {
+		direction = "LR"
+	}
+	fmt.Fprintf(&b, "flowchart %s\n", direction)
+	fmt.Fprintf(&b, "    %%%% workflow %s - task %s (%s)\n", g.WorkflowName, g.TaskID, g.TaskStatus)
+
+	// Steps names may contain characters not allowed in Mermaid ids
+	ids := make(map[string]string, len(g.Steps))
+	classes := make(map[string][]string)
+	for i, s := range g.Steps {
+		id := fmt.Sprintf("step%d", i)
+		ids[s.Name] = id
+		lines := s.labelLines()
+		for j := range lines {
+			lines[j] = escapeMermaid(lines[j])
+		}
+		fmt.Fprintf(&b, "    %s[\"%s\"]\n", id, strings.Join(lines, "<br/>"))
+		status := s.Status
+		if _, ok := statusColors[status]; !ok {
+			status = tasks.TaskStepStatusINITIAL.String()
+		}
+		classes[status] = append(classes[status], id)
+	}
+	for _, e := range g.Edges {
+		from, to := ids[e.From], ids[e.To]
+		if from == "" || to == "" {
+			continue
+		}
+		switch e.Type {
+		case EdgeTypeOnFailure, EdgeTypeOnCancel:
+			fmt.Fprintf(&b, "    %s -. %s .-> %s\n", from, e.Type, to)
+		default:
+			fmt.Fprintf(&b, "    %s --> %s\n", from, to)
+		}
+	}
+
+	statuses := make([]string, 0, len(classes))
+	for status := range classes {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		fmt.Fprintf(&b, "    classDef %s fill:%s,stroke:#333\n", status, statusColor(status))
+		fmt.Fprintf(&b, "    class %s %s\n", strings.Join(classes[status], ","), status)
+	}
+	return b.String()
+}
+
+func escapeMermaid(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
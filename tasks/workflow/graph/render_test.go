@@ -0,0 +1,84 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testGraph() *Graph {
+	start := time.Date(2019, 6, 12, 11, 0, 0, 0, time.UTC)
+	end := start.Add(90 * time.Second)
+	return &Graph{
+		TaskID:       "t1",
+		DeploymentID: "d1",
+		WorkflowName: "install",
+		TaskStatus:   "FAILED",
+		Steps: []Step{
+			{Name: "Compute_install", Target: "Compute", Status: "DONE", StartDate: &start, EndDate: &end, Duration: "1m30s", Instances: []string{"0", "1"}},
+			{Name: "App_start", Target: "App", Status: "ERROR", ErrorMessage: `operation "start" failed`},
+			{Name: "cleanup", Status: "INITIAL"},
+		},
+		Edges: []Edge{
+			{From: "Compute_install", To: "App_start", Type: EdgeTypeOnSuccess},
+			{From: "App_start", To: "cleanup", Type: EdgeTypeOnFailure},
+		},
+	}
+}
+
+func TestStepLabelLines(t *testing.T) {
+	t.Parallel()
+	g := testGraph()
+	require.Equal(t, []string{"Compute_install", "DONE (1m30s)", "instances: 0,1"}, g.Steps[0].labelLines())
+	require.Equal(t, []string{"App_start", "ERROR", `error: operation "start" failed`}, g.Steps[1].labelLines())
+
+	s := Step{Name: "s", Status: "ERROR", ErrorMessage: strings.Repeat("é", 100) + "\nstack"}
+	lines := s.labelLines()
+	require.Len(t, lines, 3)
+	require.Equal(t, maxErrorLength+len("error: "), len([]rune(lines[2])))
+	require.True(t, strings.HasSuffix(lines[2], "..."))
+}
+
+func TestGraphDOT(t *testing.T) {
+	t.Parallel()
+	out := testGraph().DOT(true)
+	require.Contains(t, out, "digraph \"Task t1\"")
+	require.Contains(t, out, "rankdir=LR")
+	require.Contains(t, out, `style="rounded,filled"`)
+	require.Contains(t, out, `Compute_install [fillcolor="#98fb98", label="Compute_install\nDONE (1m30s)\ninstances: 0,1"]`)
+	require.Contains(t, out, `App_start [fillcolor="#ff6347", label="App_start\nERROR\nerror: operation \"start\" failed", tooltip="operation \"start\" failed"]`)
+	require.Contains(t, out, "Compute_install -> App_start")
+	require.Contains(t, out, "App_start -> cleanup  [ color=red, label=on_failure, style=dashed ]")
+}
+
+func TestGraphMermaid(t *testing.T) {
+	t.Parallel()
+	out := testGraph().Mermaid(false)
+	require.True(t, strings.HasPrefix(out, "flowchart TD\n"))
+	require.Contains(t, out, "%% workflow install - task t1 (FAILED)")
+	require.Contains(t, out, `step0["Compute_install<br/>DONE (1m30s)<br/>instances: 0,1"]`)
+	require.Contains(t, out, `step1["App_start<br/>ERROR<br/>error: operation #quot;start#quot; failed"]`)
+	require.Contains(t, out, "step0 --> step1")
+	require.Contains(t, out, "step1 -. on_failure .-> step2")
+	require.Contains(t, out, "classDef DONE fill:#98fb98,stroke:#333")
+	require.Contains(t, out, "class step0 DONE")
+	require.Contains(t, out, "class step2 INITIAL")
+
+	require.True(t, strings.HasPrefix(testGraph().Mermaid(true), "flowchart LR\n"))
+}
@@ -96,6 +96,7 @@ func isSourceOperationOnTarget(s *step) bool {
 // isRunnable Checks if a Step should be run or bypassed
 //
 // It first checks if the Step is not already done in this workflow instance
+// Then it checks that the step filter conditions, if any, are met by the target node instances
 // And for ScaleOut and ScaleDown it checks if the node or the target node in case of an operation running on the target node is part of the operation
 func (s *step) isRunnable(ctx context.Context) (bool, error) {
 	kv := s.cc.KV()
@@ -123,6 +124,13 @@ func (s *step) isRunnable(ctx context.Context) (bool, error) {
 		}
 	}
 
+	if len(s.Filter) > 0 && s.Target != "" {
+		filterMet, err := deployments.EvaluateNodeConditions(ctx, s.t.targetID, s.Target, s.Filter)
+		if err != nil || !filterMet {
+			return false, err
+		}
+	}
+
 	if s.t.taskType == tasks.TaskTypeScaleOut || s.t.taskType == tasks.TaskTypeScaleIn || s.t.taskType == tasks.TaskTypeAddNodes || s.t.taskType == tasks.TaskTypeRemoveNodes {
 		// If not a relationship check the actual node
 		if s.TargetRelationship == "" {
@@ -169,6 +177,14 @@ func (s *step) run(ctx context.Context, cfg config.Configuration, deploymentID s
 		return nil
 	}
 	s.setStatus(tasks.TaskStepStatusRUNNING)
+	if s.Target != "" {
+		// Record the instances this step runs on, this is informative only
+		if instances, err := tasks.GetInstances(ctx, s.t.taskID, deploymentID, s.Target); err == nil {
+			if err = tasks.SetTaskStepInstances(s.t.taskID, s.Name, instances); err != nil {
+				log.Debugf("Failed to record instances of step %q of task %q: %+v", s.Name, s.t.taskID, err)
+			}
+		}
+	}
 
 	ctx, cancelWf := context.WithCancel(ctx)
 	defer cancelWf()
@@ -214,6 +230,9 @@ func (s *step) run(ctx context.Context, cfg config.Configuration, deploymentID s
 				events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelDEBUG, deploymentID).Registerf("TaskStep %q: error details: %+v", s.Name, err)
 				// Set step in error but continue if needed
 				s.setStatus(tasks.TaskStepStatusERROR)
+				if errMsg := tasks.SetTaskStepErrorMessage(s.t.taskID, s.Name, err.Error()); errMsg != nil {
+					log.Debugf("Failed to record error message of step %q of task %q: %+v", s.Name, s.t.taskID, errMsg)
+				}
 				if !bypassErrors {
 					tasks.NotifyErrorOnTask(s.t.taskID)
 					// only set generic error message here.
@@ -383,6 +402,8 @@ func (s *step) runActivity(wfCtx context.Context, cfg config.Configuration, depl
 	case builder.ActivityTypeInline:
 		// Register inline workflow associated to the original task
 		return s.registerInlineWorkflow(wfCtx, deploymentID, activity)
+	case builder.ActivityTypeWaitForSignal:
+		return s.waitForSignal(wfCtx, deploymentID, activity)
 	}
 	return nil
 }
@@ -432,6 +453,23 @@ func (s *step) getActivityInputParameters(ctx context.Context, activity builder.
 		}
 	}
 
+	// Getting inputs provided by signals received by previous steps
+	signalInputs, err := tasks.GetTaskSignalsInputs(s.t.taskID)
+	if err != nil {
+		return nil, err
+	}
+	for inputName, inputValue := range signalInputs {
+		if _, ok := result[inputName]; ok {
+			// Already defined in activity or workflow
+			continue
+		}
+		valueAssign, err := tosca.ToValueAssignment(inputValue)
+		if err != nil {
+			return result, err
+		}
+		result[inputName] = tosca.ParameterDefinition{Value: valueAssign}
+	}
+
 	// Getting inputs at the topology level that can be used
 	// if inputs aren't defined at lower levels (workflow or activity)
 	err = addTopologyInputs(ctx, deploymentID, result)
@@ -567,6 +605,50 @@ func (s *step) registerInlineWorkflow(ctx context.Context, deploymentID string,
 	return err
 }
 
+// waitForSignal suspends the step until an external signal is received
+//
+// On first execution the step is marked as waiting and asynchronous so it is not considered
+// as done. Once the signal is received the step is executed again and completes.
+func (s *step) waitForSignal(ctx context.Context, deploymentID string, activity builder.Activity) error {
+	signal, err := tasks.GetTaskStepSignal(s.t.taskID, s.Name)
+	if err != nil {
+		return err
+	}
+	if signal != nil {
+		switch signal.Status {
+		case tasks.TaskStepSignalStatusReceived:
+			events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).Registerf("Step %q received signal %q", s.Name, signal.Signal)
+			return nil
+		case tasks.TaskStepSignalStatusCanceled:
+			return errors.Errorf("Task canceled while step %q was waiting for signal %q", s.Name, signal.Signal)
+		}
+		// Already waiting
+		s.Async = true
+		return s.setStatus(tasks.TaskStepStatusWAITING)
+	}
+
+	err = tasks.RegisterStepSignalWait(s.t.taskID, s.Name, activity.Value(), time.Now())
+	if err != nil {
+		return err
+	}
+	// Marking this step as asynchronous as it should not be considered as
+	// done by the caller
+	s.Async = true
+	err = s.setStatus(tasks.TaskStepStatusWAITING)
+	if err != nil {
+		return err
+	}
+	events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelINFO, deploymentID).Registerf("Step %q waiting for signal %q", s.Name, activity.Value())
+
+	// The task may have been canceled before the wait was registered
+	canceled, err := tasks.TaskHasCancellationFlag(s.t.taskID)
+	if err != nil || !canceled {
+		return err
+	}
+	_, err = tasks.CancelTaskStepSignal(s.t.taskID, s.Name)
+	return err
+}
+
 func (s *step) checkIfPreviousOfNextStepAreDone(ctx context.Context, nextStep *step, workflowName string) (bool, error) {
 	cpt := 0
 	for _, step := range nextStep.Previous {
@@ -214,6 +214,13 @@ func setTaskStatus(ctx context.Context, targetID, taskID string, status tasks.Ta
 		return checkAndSetTaskStatus(ctx, targetID, taskID, status, errReason)
 	}
 
+	if tasks.IsFinalStatus(status) {
+		err = tasks.SetTaskEndDate(taskID, time.Now())
+		if err != nil {
+			log.Printf("[WARNING] Failed to store end date for taskID:%q due to error:%+v", taskID, err)
+		}
+	}
+
 	// Emit event for status change
 	// wfName may be empty as this data is not filled for non-workflow task type (as for custom command by instance)
 	wfName, _ := tasks.GetTaskData(taskID, "workflowName")
@@ -29,17 +29,20 @@ import (
 	"github.com/hashicorp/consul/api"
 	"github.com/pkg/errors"
 
+	"github.com/ystia/yorc/v4/cluster"
 	"github.com/ystia/yorc/v4/config"
 	"github.com/ystia/yorc/v4/deployments"
 	"github.com/ystia/yorc/v4/events"
 	"github.com/ystia/yorc/v4/helper/consulutil"
 	"github.com/ystia/yorc/v4/helper/metricsutil"
+	"github.com/ystia/yorc/v4/helper/redactutil"
 	"github.com/ystia/yorc/v4/log"
 	"github.com/ystia/yorc/v4/prov"
 	"github.com/ystia/yorc/v4/prov/operations"
 	"github.com/ystia/yorc/v4/prov/scheduling"
 	"github.com/ystia/yorc/v4/registry"
 	"github.com/ystia/yorc/v4/tasks"
+	"github.com/ystia/yorc/v4/tasks/rollout"
 	"github.com/ystia/yorc/v4/tasks/workflow/builder"
 	"github.com/ystia/yorc/v4/tosca"
 )
@@ -141,6 +144,7 @@ func (w *worker) cleanupScaledDownNodes(ctx context.Context, t *taskExecution) e
 			return err
 		}
 		for _, instance := range instances {
+			revokeSecretLeases(ctx, t.targetID, node, instance)
 			err = deployments.DeleteInstance(ctx, t.targetID, node, instance)
 			if err != nil {
 				return err
@@ -155,6 +159,15 @@ func (w *worker) cleanupScaledDownNodes(ctx context.Context, t *taskExecution) e
 	return nil
 }
 
+// revokeSecretLeases revokes leases of secrets used by a deployment, failures are only logged
+// as they should not prevent the deployment lifecycle to go on
+func revokeSecretLeases(ctx context.Context, deploymentID, nodeName, instanceName string) {
+	err := deployments.RevokeSecretLeases(ctx, deploymentID, nodeName, instanceName)
+	if err != nil {
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, deploymentID).Registerf("[WARNING] %v", err)
+	}
+}
+
 // worker handle a taskExecution
 func (w *worker) handleExecution(t *taskExecution) {
 	log.Debugf("Handle task execution:%+v", t)
@@ -163,11 +176,25 @@ func (w *worker) handleExecution(t *taskExecution) {
 		log.Printf("%+v", err)
 		return
 	}
+	cluster.AddWork(cluster.WorkTaskExecution, t.id)
+	defer cluster.RemoveWork(cluster.WorkTaskExecution, t.id)
 	taskExecutionLabels := []metrics.Label{
 		metrics.Label{Name: "TaskID", Value: t.taskID},
 		metrics.Label{Name: "Deployment", Value: t.targetID},
 		metrics.Label{Name: "Type", Value: t.taskType.String()},
 	}
+	// Deployment labels are propagated to metrics and logs, they are not available for non-deployment tasks
+	depLabels, err := deployments.GetDeploymentLabels(context.Background(), t.targetID)
+	if err != nil {
+		log.Printf("[WARNING] Failed to get labels of deployment %q: %+v", t.targetID, err)
+	}
+	// Only deployment labels allowed in the telemetry configuration are added to metrics labels
+	// to keep the metrics cardinality bounded
+	for _, name := range w.cfg.Telemetry.DeploymentLabels {
+		if value, ok := depLabels[name]; ok {
+			taskExecutionLabels = append(taskExecutionLabels, metrics.Label{Name: metricsutil.CleanupLabelName("Label_" + name), Value: value})
+		}
+	}
 	metrics.MeasureSinceWithLabels([]string{"taskExecution", "wait"}, t.creationDate, taskExecutionLabels)
 	defer func(t *taskExecution, start time.Time, taskExecutionLabels []metrics.Label) {
 		// Remove currently processing execution flag
@@ -198,6 +225,9 @@ func (w *worker) handleExecution(t *taskExecution) {
 		events.WorkFlowID:  wfName,
 		events.ExecutionID: t.taskID,
 	}
+	if len(depLabels) > 0 {
+		logOptFields[events.DeploymentLabels] = deployments.FormatLabels(depLabels)
+	}
 	ctx := events.NewContext(context.Background(), logOptFields)
 	err = checkAndSetTaskStatus(ctx, t.targetID, t.taskID, tasks.TaskStatusRUNNING, nil)
 	if err != nil {
@@ -418,6 +448,12 @@ func (w *worker) endAction(ctx context.Context, t *taskExecution, action *prov.A
 		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelERROR, action.AsyncOperation.DeploymentID).Registerf("%v", err)
 		log.Debugf("%+v", err)
 	}
+	if actionErr != nil && !wasCancelled {
+		err = tasks.SetTaskStepErrorMessage(action.AsyncOperation.TaskID, action.AsyncOperation.StepName, actionErr.Error())
+		if err != nil {
+			log.Debugf("Failed to record error message of step %q of task %q: %+v", action.AsyncOperation.StepName, action.AsyncOperation.TaskID, err)
+		}
+	}
 
 	instances, err := tasks.GetInstances(ctx, action.AsyncOperation.TaskID, action.AsyncOperation.DeploymentID, action.AsyncOperation.NodeName)
 	if err != nil {
@@ -597,6 +633,7 @@ func (w *worker) runUndeploy(ctx context.Context, t *taskExecution) error {
 				return deployments.SetDeploymentStatus(ctx, t.targetID, deployments.UNDEPLOYMENT_FAILED)
 			}
 			deployments.SetDeploymentStatus(ctx, t.targetID, deployments.UNDEPLOYED)
+			revokeSecretLeases(ctx, t.targetID, "", "")
 
 			// if purge has been requested, run it at the end except for un-deployment failure
 			if t.taskType == tasks.TaskTypePurge {
@@ -672,11 +709,24 @@ func (w *worker) runPurge(ctx context.Context, t *taskExecution) error {
 	if err != nil {
 		return errors.Wrapf(err, "failed to remove deployments artifacts stored on disk: %q", overlayPath)
 	}
+	// Revoke secrets leases that may remain if the deployment is purged without being undeployed
+	revokeSecretLeases(ctx, t.targetID, "", "")
+	// Remove schedules of the deployment
+	err = scheduling.DeleteDeploymentSchedules(ctx, t.targetID)
+	if err != nil {
+		return err
+	}
+	// Remove rollouts of the deployment
+	err = rollout.DeleteDeploymentRollouts(ctx, t.targetID)
+	if err != nil {
+		return err
+	}
 	// Remove from KV this purge tasks
 	err = deployments.DeleteDeployment(ctx, t.targetID)
 	if err != nil {
 		return err
 	}
+	redactutil.ForgetDeployment(t.targetID)
 	// Now cleanup: mark it as done so nobody will try to run it, clear the processing lock and finally delete the TaskExecution.
 	checkAndSetTaskStatus(ctx, t.targetID, t.taskID, tasks.TaskStatusDONE, nil)
 	err = tasks.DeleteTask(t.taskID)
@@ -222,7 +222,20 @@ func storeWorkflowOutputs(ctx context.Context, deploymentID, taskID, workflowNam
 		return err
 	}
 
+	// Invalid outputs values are reported but still stored as the workflow is already done
+	err = deployments.CheckWorkflowOutputs(ctx, deploymentID, workflowName, outputs)
+	if err != nil {
+		if !deployments.IsInvalidParameterValueError(err) {
+			return err
+		}
+		events.WithContextOptionalFields(ctx).NewLogEntry(events.LogLevelWARN, deploymentID).Registerf("[WARNING] %v", err)
+	}
+
 	for outputName, outputValue := range outputs {
+		if outputValue == nil {
+			// output not resolved
+			continue
+		}
 		err = tasks.SetTaskData(taskID, path.Join("outputs", outputName), outputValue.RawString())
 		if err != nil {
 			return err
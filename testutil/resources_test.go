@@ -39,6 +39,7 @@ func TestAssets(t *testing.T) {
 		t.Run("GoogleTypes", testAssetYorcGoogleParsing)
 		t.Run("YorcTypes", testAssetYorcParsing)
 		t.Run("SlurmTypes", testAssetYorcSlurmParsing)
+		t.Run("PBSTypes", testAssetYorcPBSParsing)
 		t.Run("HostsPoolTypes", testAssetYorcHostsPoolParsing)
 	})
 }
@@ -84,6 +85,11 @@ func testAssetYorcSlurmParsing(t *testing.T) {
 	checkBuiltinTypesPath(t, "yorc-slurm-types")
 }
 
+func testAssetYorcPBSParsing(t *testing.T) {
+	t.Parallel()
+	checkBuiltinTypesPath(t, "yorc-pbs-types")
+}
+
 func testAssetYorcHostsPoolParsing(t *testing.T) {
 	t.Parallel()
 	checkBuiltinTypesPath(t, "yorc-hostspool-types")
@@ -0,0 +1,284 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tosca
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// A ConstraintClause is the representation of a TOSCA Constraint Clause
+//
+// See http://docs.oasis-open.org/tosca/TOSCA-Simple-Profile-YAML/v1.3/TOSCA-Simple-Profile-YAML-v1.3.html#DEFN_ELEMENT_CONSTRAINTS_CLAUSE
+// for more details
+type ConstraintClause struct {
+	Operator string      `json:"operator"`
+	Value    interface{} `json:"value"`
+}
+
+// UnmarshalYAML unmarshals a yaml into a ConstraintClause
+func (c *ConstraintClause) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var m map[string]interface{}
+	if err := unmarshal(&m); err != nil {
+		return err
+	}
+	if len(m) != 1 {
+		return errors.Errorf("a constraint clause should have exactly one operator, found %d", len(m))
+	}
+	for k, v := range m {
+		switch k {
+		case "equal", "greater_than", "greater_or_equal", "less_than", "less_or_equal", "length", "min_length", "max_length", "pattern":
+		case "in_range", "valid_values":
+			if _, ok := v.([]interface{}); !ok {
+				return errors.Errorf("constraint %q expects a list value", k)
+			}
+		default:
+			return errors.Errorf("unsupported constraint operator %q", k)
+		}
+		c.Operator = k
+		c.Value = v
+	}
+	return nil
+}
+
+// Evaluate checks if a given value satisfies this constraint
+func (c ConstraintClause) Evaluate(value string) (bool, error) {
+	switch c.Operator {
+	case "equal":
+		r, err := compareConstraintValues(value, fmt.Sprint(c.Value))
+		return r == 0, err
+	case "greater_than":
+		r, err := compareConstraintValues(value, fmt.Sprint(c.Value))
+		return r > 0, err
+	case "greater_or_equal":
+		r, err := compareConstraintValues(value, fmt.Sprint(c.Value))
+		return r >= 0, err
+	case "less_than":
+		r, err := compareConstraintValues(value, fmt.Sprint(c.Value))
+		return r < 0, err
+	case "less_or_equal":
+		r, err := compareConstraintValues(value, fmt.Sprint(c.Value))
+		return r <= 0, err
+	case "in_range":
+		l, ok := c.Value.([]interface{})
+		if !ok || len(l) != 2 {
+			return false, errors.Errorf("constraint %q expects a list of two elements", c.Operator)
+		}
+		r, err := compareConstraintValues(value, fmt.Sprint(l[0]))
+		if err != nil || r < 0 {
+			return false, err
+		}
+		if strings.ToUpper(fmt.Sprint(l[1])) == "UNBOUNDED" {
+			return true, nil
+		}
+		r, err = compareConstraintValues(value, fmt.Sprint(l[1]))
+		return r <= 0, err
+	case "valid_values":
+		l, ok := c.Value.([]interface{})
+		if !ok {
+			return false, errors.Errorf("constraint %q expects a list", c.Operator)
+		}
+		for _, v := range l {
+			r, err := compareConstraintValues(value, fmt.Sprint(v))
+			if err != nil {
+				return false, err
+			}
+			if r == 0 {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "length", "min_length", "max_length":
+		expected, err := strconv.Atoi(fmt.Sprint(c.Value))
+		if err != nil {
+			return false, errors.Wrapf(err, "constraint %q expects an integer value", c.Operator)
+		}
+		actual := len(value)
+		switch c.Operator {
+		case "min_length":
+			return actual >= expected, nil
+		case "max_length":
+			return actual <= expected, nil
+		}
+		return actual == expected, nil
+	case "pattern":
+		re, err := regexp.Compile(fmt.Sprint(c.Value))
+		if err != nil {
+			return false, errors.Wrapf(err, "invalid pattern for constraint %q", c.Operator)
+		}
+		return re.MatchString(value), nil
+	}
+	return false, errors.Errorf("unsupported constraint operator %q", c.Operator)
+}
+
+// compareConstraintValues compares two values trying to detect their type:
+// numbers, versions, timestamps and finally strings
+func compareConstraintValues(a, b string) (int, error) {
+	if fa, err := strconv.ParseFloat(a, 64); err == nil {
+		if fb, err := strconv.ParseFloat(b, 64); err == nil {
+			switch {
+			case fa < fb:
+				return -1, nil
+			case fa > fb:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+	if va, err := ParseVersion(a); err == nil {
+		if vb, err := ParseVersion(b); err == nil {
+			return va.Compare(vb), nil
+		}
+	}
+	if ta, err := ParseTimestamp(a); err == nil {
+		if tb, err := ParseTimestamp(b); err == nil {
+			switch {
+			case ta.Before(tb):
+				return -1, nil
+			case ta.After(tb):
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+	return strings.Compare(a, b), nil
+}
+
+// A ConditionClause is the representation of a TOSCA Condition Clause
+//
+// A condition clause is either an assertion on attributes values or a logical
+// combination (and, or, not) of other condition clauses.
+//
+// See http://docs.oasis-open.org/tosca/TOSCA-Simple-Profile-YAML/v1.3/TOSCA-Simple-Profile-YAML-v1.3.html#DEFN_ELEMENT_CONDITION_CLAUSE_DEFN
+// for more details
+type ConditionClause struct {
+	And    []ConditionClause             `json:"and,omitempty"`
+	Or     []ConditionClause             `json:"or,omitempty"`
+	Not    []ConditionClause             `json:"not,omitempty"`
+	Assert map[string][]ConstraintClause `json:"assert,omitempty"`
+}
+
+// UnmarshalYAML unmarshals a yaml into a ConditionClause
+func (c *ConditionClause) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var m map[string]interface{}
+	if err := unmarshal(&m); err != nil {
+		return err
+	}
+	_, isAnd := m["and"]
+	_, isOr := m["or"]
+	_, isNot := m["not"]
+	_, isAssert := m["assert"]
+	if (isAnd || isOr || isNot || isAssert) && len(m) != 1 {
+		return errors.New("a condition clause using and, or, not or assert keywords should not define other keys")
+	}
+	switch {
+	case isAnd || isOr || isNot:
+		var str struct {
+			And []ConditionClause `yaml:"and,omitempty"`
+			Or  []ConditionClause `yaml:"or,omitempty"`
+			Not []ConditionClause `yaml:"not,omitempty"`
+		}
+		if err := unmarshal(&str); err != nil {
+			return err
+		}
+		c.And = str.And
+		c.Or = str.Or
+		c.Not = str.Not
+	case isAssert:
+		var str struct {
+			Assert []map[string][]ConstraintClause `yaml:"assert"`
+		}
+		if err := unmarshal(&str); err != nil {
+			return err
+		}
+		c.Assert = make(map[string][]ConstraintClause)
+		for _, a := range str.Assert {
+			for k, v := range a {
+				c.Assert[k] = append(c.Assert[k], v...)
+			}
+		}
+	default:
+		// Direct assertion definition
+		if err := unmarshal(&c.Assert); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Evaluate checks if this condition is satisfied.
+//
+// getValue is called to retrieve the value of an attribute referenced by an assertion.
+func (c ConditionClause) Evaluate(getValue func(name string) (string, error)) (bool, error) {
+	for _, sub := range c.And {
+		ok, err := sub.Evaluate(getValue)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	if len(c.Or) > 0 {
+		ok, err := EvaluateConditions(c.Or, getValue, false)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	if len(c.Not) > 0 {
+		// not is satisfied if the conjunction of its clauses is not satisfied
+		ok, err := EvaluateConditions(c.Not, getValue, true)
+		if err != nil || ok {
+			return false, err
+		}
+	}
+	for name, constraints := range c.Assert {
+		value, err := getValue(name)
+		if err != nil {
+			return false, err
+		}
+		for _, constraint := range constraints {
+			ok, err := constraint.Evaluate(value)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+	}
+	return true, nil
+}
+
+// EvaluateConditions evaluates a list of condition clauses
+//
+// If all is true, all conditions should be satisfied otherwise only one of them should be.
+// An empty list of conditions is always satisfied.
+func EvaluateConditions(conditions []ConditionClause, getValue func(name string) (string, error), all bool) (bool, error) {
+	if len(conditions) == 0 {
+		return true, nil
+	}
+	for _, c := range conditions {
+		ok, err := c.Evaluate(getValue)
+		if err != nil {
+			return false, err
+		}
+		if ok && !all {
+			return true, nil
+		}
+		if !ok && all {
+			return false, nil
+		}
+	}
+	return all, nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tosca
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestConstraintClauseEvaluate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		yml   string
+		value string
+		want  bool
+	}{
+		{"Equal", `equal: started`, "started", true},
+		{"NotEqual", `equal: started`, "stopped", false},
+		{"GreaterThanNumber", `greater_than: 9`, "10", true},
+		{"GreaterOrEqualVersion", `greater_or_equal: 1.10.0`, "1.9.3", false},
+		{"LessThanTimestamp", `less_than: 2020-01-01`, "2019-12-31T23:59:59Z", true},
+		{"InRange", `in_range: [1, 4]`, "4", true},
+		{"InRangeUnbounded", `in_range: [1, UNBOUNDED]`, "1000", true},
+		{"OutOfRange", `in_range: [1, 4]`, "0", false},
+		{"ValidValues", `valid_values: [started, configured]`, "configured", true},
+		{"InvalidValues", `valid_values: [started, configured]`, "initial", false},
+		{"Length", `length: 3`, "abc", true},
+		{"MinLength", `min_length: 4`, "abc", false},
+		{"MaxLength", `max_length: 4`, "abc", true},
+		{"Pattern", `pattern: "^[a-z]+$"`, "abc", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := ConstraintClause{}
+			err := yaml.Unmarshal([]byte(tt.yml), &c)
+			require.NoError(t, err)
+			got, err := c.Evaluate(tt.value)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConstraintClauseUnmarshalErrors(t *testing.T) {
+	t.Parallel()
+	c := ConstraintClause{}
+	require.Error(t, yaml.Unmarshal([]byte(`unknown_op: 1`), &c))
+	require.Error(t, yaml.Unmarshal([]byte(`{equal: 1, less_than: 2}`), &c))
+	require.Error(t, yaml.Unmarshal([]byte(`in_range: 1`), &c))
+}
+
+func TestConditionClauseEvaluate(t *testing.T) {
+	t.Parallel()
+	attributes := map[string]string{
+		"state":    "started",
+		"replicas": "3",
+	}
+	getValue := func(name string) (string, error) {
+		v, ok := attributes[name]
+		if !ok {
+			return "", errors.Errorf("unknown attribute %q", name)
+		}
+		return v, nil
+	}
+	tests := []struct {
+		name    string
+		yml     string
+		want    bool
+		wantErr bool
+	}{
+		{"DirectAssertion", `[{state: [{equal: started}]}]`, true, false},
+		{"Assert", `[{assert: [{state: [{equal: started}]}, {replicas: [{greater_than: 1}]}]}]`, true, false},
+		{"AssertFalse", `[{assert: [{replicas: [{greater_than: 3}]}]}]`, false, false},
+		{"And", `[{and: [{state: [{equal: started}]}, {replicas: [{equal: 2}]}]}]`, false, false},
+		{"Or", `[{or: [{state: [{equal: stopped}]}, {replicas: [{equal: 3}]}]}]`, true, false},
+		{"Not", `[{not: [{state: [{equal: stopped}]}]}]`, true, false},
+		{"SeveralClauses", `[{state: [{equal: started}]}, {replicas: [{less_than: 2}]}]`, false, false},
+		{"UnknownAttribute", `[{unknown: [{equal: started}]}]`, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var conditions []ConditionClause
+			err := yaml.Unmarshal([]byte(tt.yml), &conditions)
+			require.NoError(t, err)
+			got, err := EvaluateConditions(conditions, getValue, true)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -18,10 +18,83 @@ package tosca
 //
 // See http://docs.oasis-open.org/tosca/TOSCA-Simple-Profile-YAML/v1.2/TOSCA-Simple-Profile-YAML-v1.2.html#DEFN_ELEMENT_INTERFACE_DEF for more details
 type InterfaceDefinition struct {
-	Type        string                         `yaml:"type,omitempty" json:"type,omitempty"`
-	Description string                         `yaml:"description,omitempty" json:"description,omitempty"`
-	Inputs      map[string]Input               `yaml:"inputs,omitempty" json:"inputs,omitempty"`
-	Operations  map[string]OperationDefinition `yaml:",inline,omitempty" json:",inline,omitempty"`
+	Type          string                            `yaml:"type,omitempty" json:"type,omitempty"`
+	Description   string                            `yaml:"description,omitempty" json:"description,omitempty"`
+	Inputs        map[string]Input                  `yaml:"inputs,omitempty" json:"inputs,omitempty"`
+	Operations    map[string]OperationDefinition    `yaml:",inline,omitempty" json:",inline,omitempty"`
+	Notifications map[string]NotificationDefinition `yaml:"notifications,omitempty" json:"notifications,omitempty"`
+}
+
+// UnmarshalYAML unmarshals a yaml into an InterfaceDefinition
+//
+// Operations could be defined either inline (TOSCA 1.2 and earlier) or under
+// the operations keyword (TOSCA 1.3).
+func (i *InterfaceDefinition) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str struct {
+		Type             string                            `yaml:"type,omitempty"`
+		Description      string                            `yaml:"description,omitempty"`
+		Inputs           map[string]Input                  `yaml:"inputs,omitempty"`
+		Operations       map[string]OperationDefinition    `yaml:"operations,omitempty"`
+		Notifications    map[string]NotificationDefinition `yaml:"notifications,omitempty"`
+		InlineOperations map[string]OperationDefinition    `yaml:",inline,omitempty"`
+	}
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	i.Type = str.Type
+	i.Description = str.Description
+	i.Inputs = str.Inputs
+	i.Notifications = str.Notifications
+	i.Operations = str.InlineOperations
+	if len(str.Operations) > 0 && i.Operations == nil {
+		i.Operations = make(map[string]OperationDefinition, len(str.Operations))
+	}
+	for k, v := range str.Operations {
+		i.Operations[k] = v
+	}
+	return nil
+}
+
+// A NotificationDefinition is the representation of a TOSCA Notification Definition
+//
+// Notifications are asynchronous events sent by an external implementation,
+// their outputs are mapped to attributes.
+//
+// See http://docs.oasis-open.org/tosca/TOSCA-Simple-Profile-YAML/v1.3/TOSCA-Simple-Profile-YAML-v1.3.html#DEFN_ELEMENT_NOTIFICATION_DEF for more details
+type NotificationDefinition struct {
+	Description    string            `yaml:"description,omitempty" json:"description,omitempty"`
+	Implementation Implementation    `yaml:"implementation,omitempty" json:"implementation,omitempty"`
+	Outputs        map[string]Output `yaml:"outputs,omitempty" json:"outputs,omitempty"`
+}
+
+// UnmarshalYAML unmarshals a yaml into a NotificationDefinition
+func (n *NotificationDefinition) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err == nil {
+		n.Implementation = Implementation{Primary: s}
+		return nil
+	}
+	var str struct {
+		Description    string              `yaml:"description,omitempty"`
+		Implementation Implementation      `yaml:"implementation,omitempty"`
+		Outputs        map[string][]string `yaml:"outputs,omitempty"`
+	}
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	n.Description = str.Description
+	n.Implementation = str.Implementation
+	if str.Outputs != nil {
+		n.Outputs = make(map[string]Output)
+		for outputName, outputAttributeMapping := range str.Outputs {
+			attributeMapping, err := parseAttributeMapping(outputAttributeMapping)
+			if err != nil {
+				return err
+			}
+			n.Outputs[outputName] = Output{AttributeMapping: attributeMapping}
+		}
+	}
+	return nil
 }
 
 // An OperationDefinition is the representation of a TOSCA Operation Definition
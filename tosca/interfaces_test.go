@@ -367,3 +367,35 @@ start:
 	err := yaml.Unmarshal([]byte(inputYaml), &ifDef)
 	require.NotNil(t, err, "Expecting an error when unmarshaling Interface with expression outputs and less than 2 parameters")
 }
+
+func TestInterfaceOperationsAndNotifications(t *testing.T) {
+	t.Parallel()
+	var inputYaml = `
+type: yorc.test.interfaces.Monitoring
+operations:
+  start: scripts/start_monitor.sh
+  stop:
+    implementation: scripts/stop_monitor.sh
+notifications:
+  alert:
+    description: Sent when an alert is raised
+    implementation: scripts/alert_listener.sh
+    outputs:
+      level: [SELF, alert_level]
+`
+	ifDef := InterfaceDefinition{}
+
+	err := yaml.Unmarshal([]byte(inputYaml), &ifDef)
+	require.Nil(t, err, "Expecting no error when unmarshaling Interface with TOSCA 1.3 grammar")
+	require.Equal(t, "yorc.test.interfaces.Monitoring", ifDef.Type)
+	require.Len(t, ifDef.Operations, 2)
+	require.Equal(t, "scripts/start_monitor.sh", ifDef.Operations["start"].Implementation.Primary)
+	require.Equal(t, "scripts/stop_monitor.sh", ifDef.Operations["stop"].Implementation.Primary)
+	require.Len(t, ifDef.Notifications, 1)
+	require.Contains(t, ifDef.Notifications, "alert")
+	notif := ifDef.Notifications["alert"]
+	require.Equal(t, "Sent when an alert is raised", notif.Description)
+	require.Equal(t, "scripts/alert_listener.sh", notif.Implementation.Primary)
+	require.Contains(t, notif.Outputs, "level")
+	require.Equal(t, []string{"SELF", "alert_level"}, notif.Outputs["level"].AttributeMapping.Parameters)
+}
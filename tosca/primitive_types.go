@@ -0,0 +1,215 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tosca
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// timestampRegexp matches YAML timestamps as defined in http://yaml.org/type/timestamp.html
+var timestampRegexp = regexp.MustCompile(`^([0-9]{4})-([0-9]{1,2})-([0-9]{1,2})(?:(?:[Tt]|[ \t]+)([0-9]{1,2}):([0-9]{2}):([0-9]{2})(?:\.([0-9]*))?(?:[ \t]*(Z|[-+][0-9]{1,2}(?::[0-9]{2})?))?)?$`)
+
+// scalarUnitRegexp matches TOSCA scalar-unit values like "10 GB" or "2.5 s"
+var scalarUnitRegexp = regexp.MustCompile(`^\s*[-+]?[0-9]*\.?[0-9]+\s*[a-zA-Z]+\s*$`)
+
+// versionRegexp matches TOSCA versions <major_version>.<minor_version>[.<fix_version>[.<qualifier>[-<build_version] ] ]
+var versionRegexp = regexp.MustCompile(`^([0-9]+)\.([0-9]+)(?:\.([0-9]+)(?:\.([A-Za-z0-9_]+)(?:-([0-9]+))?)?)?$`)
+
+// ParseTimestamp parses a TOSCA timestamp
+//
+// See http://docs.oasis-open.org/tosca/TOSCA-Simple-Profile-YAML/v1.3/TOSCA-Simple-Profile-YAML-v1.3.html#TYPE_YAML_TIMESTAMP
+// for more details
+func ParseTimestamp(value string) (time.Time, error) {
+	m := timestampRegexp.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil {
+		return time.Time{}, errors.Errorf("%q is not a valid timestamp", value)
+	}
+	fields := make([]int, 6)
+	for i := range fields {
+		if m[i+1] == "" {
+			continue
+		}
+		fields[i], _ = strconv.Atoi(m[i+1])
+	}
+	var nsec int
+	if m[7] != "" {
+		frac := m[7]
+		if len(frac) > 9 {
+			frac = frac[:9]
+		}
+		frac += strings.Repeat("0", 9-len(frac))
+		nsec, _ = strconv.Atoi(frac)
+	}
+	loc := time.UTC
+	if m[8] != "" && m[8] != "Z" {
+		sign := 1
+		if m[8][0] == '-' {
+			sign = -1
+		}
+		parts := strings.SplitN(m[8][1:], ":", 2)
+		hours, _ := strconv.Atoi(parts[0])
+		var minutes int
+		if len(parts) == 2 {
+			minutes, _ = strconv.Atoi(parts[1])
+		}
+		loc = time.FixedZone("", sign*(hours*3600+minutes*60))
+	}
+	t := time.Date(fields[0], time.Month(fields[1]), fields[2], fields[3], fields[4], fields[5], nsec, loc)
+	if t.Month() != time.Month(fields[1]) || t.Day() != fields[2] || t.Hour() != fields[3] || t.Minute() != fields[4] || t.Second() != fields[5] {
+		return time.Time{}, errors.Errorf("%q is not a valid timestamp", value)
+	}
+	return t, nil
+}
+
+// A Version is the representation of a TOSCA Version Type
+//
+// See http://docs.oasis-open.org/tosca/TOSCA-Simple-Profile-YAML/v1.3/TOSCA-Simple-Profile-YAML-v1.3.html#TYPE_TOSCA_VERSION
+// for more details
+type Version struct {
+	Major     uint64
+	Minor     uint64
+	Fix       uint64
+	Qualifier string
+	Build     uint64
+}
+
+// ParseVersion parses a TOSCA version
+func ParseVersion(value string) (Version, error) {
+	v := Version{}
+	m := versionRegexp.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil {
+		return v, errors.Errorf("%q is not a valid version", value)
+	}
+	var err error
+	fields := []*uint64{&v.Major, &v.Minor, &v.Fix}
+	for i, f := range fields {
+		if m[i+1] == "" {
+			continue
+		}
+		*f, err = strconv.ParseUint(m[i+1], 10, 64)
+		if err != nil {
+			return v, errors.Wrapf(err, "%q is not a valid version", value)
+		}
+	}
+	v.Qualifier = m[4]
+	if m[5] != "" {
+		v.Build, err = strconv.ParseUint(m[5], 10, 64)
+		if err != nil {
+			return v, errors.Wrapf(err, "%q is not a valid version", value)
+		}
+	}
+	return v, nil
+}
+
+// Compare returns an integer comparing two versions.
+// The result will be 0 if v==o, -1 if v < o, and +1 if v > o.
+func (v Version) Compare(o Version) int {
+	for _, c := range [][2]uint64{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Fix, o.Fix}} {
+		if c[0] < c[1] {
+			return -1
+		} else if c[0] > c[1] {
+			return 1
+		}
+	}
+	if r := strings.Compare(v.Qualifier, o.Qualifier); r != 0 {
+		return r
+	}
+	if v.Build < o.Build {
+		return -1
+	} else if v.Build > o.Build {
+		return 1
+	}
+	return 0
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Fix)
+	if v.Qualifier != "" {
+		s += "." + v.Qualifier
+		if v.Build != 0 {
+			s += fmt.Sprintf("-%d", v.Build)
+		}
+	}
+	return s
+}
+
+// ParseRange parses a TOSCA range from its list representation
+func ParseRange(value interface{}) (Range, error) {
+	l, ok := value.([]interface{})
+	if !ok {
+		return Range{}, errors.Errorf("%v is not a valid range, expecting a list of two elements", value)
+	}
+	bounds := make([]string, len(l))
+	for i := range l {
+		bounds[i] = strings.TrimSpace(fmt.Sprint(l[i]))
+	}
+	return newRange(bounds)
+}
+
+// CheckPrimitiveValue checks that a given value matches a TOSCA primitive type
+// that has a specific syntax (timestamp, version and range).
+//
+// Other types are not checked and nil is returned.
+func CheckPrimitiveValue(typeName string, value interface{}) error {
+	var err error
+	switch typeName {
+	case "timestamp":
+		_, err = ParseTimestamp(fmt.Sprint(value))
+	case "version":
+		_, err = ParseVersion(fmt.Sprint(value))
+	case "range":
+		_, err = ParseRange(value)
+	}
+	return err
+}
+
+// CheckLiteralValue checks that a literal value matches a TOSCA primitive type.
+//
+// Supported types are integer, float, boolean, scalar-units, timestamp and version.
+// Other types (like string) are not checked and nil is returned, except range that
+// can't be represented as a literal.
+func CheckLiteralValue(typeName, value string) error {
+	switch typeName {
+	case "integer":
+		if _, err := strconv.ParseInt(value, 0, 64); err != nil {
+			return errors.Errorf("%q is not a valid integer", value)
+		}
+	case "float":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return errors.Errorf("%q is not a valid float", value)
+		}
+	case "boolean":
+		switch strings.ToLower(value) {
+		case "true", "false", "yes", "no", "on", "off", "y", "n":
+		default:
+			return errors.Errorf("%q is not a valid boolean", value)
+		}
+	case "scalar-unit.size", "scalar-unit.time", "scalar-unit.frequency", "scalar-unit.bitrate":
+		if !scalarUnitRegexp.MatchString(value) {
+			return errors.Errorf("%q is not a valid %s", value, typeName)
+		}
+	case "timestamp", "version":
+		return CheckPrimitiveValue(typeName, value)
+	case "range":
+		return errors.Errorf("%q is not a valid range, expecting a list of two elements", value)
+	}
+	return nil
+}
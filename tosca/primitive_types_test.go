@@ -0,0 +1,158 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tosca
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"DateOnly", "2002-12-14", time.Date(2002, 12, 14, 0, 0, 0, 0, time.UTC), false},
+		{"Canonical", "2001-12-15T02:59:43.1Z", time.Date(2001, 12, 15, 2, 59, 43, 100000000, time.UTC), false},
+		{"ISO8601", "2001-12-14t21:59:43.10-05:00", time.Date(2001, 12, 15, 2, 59, 43, 100000000, time.UTC), false},
+		{"SpaceSeparated", "2001-12-14 21:59:43.10 -5", time.Date(2001, 12, 15, 2, 59, 43, 100000000, time.UTC), false},
+		{"NoTimeZone", "2001-12-15 2:59:43.10", time.Date(2001, 12, 15, 2, 59, 43, 100000000, time.UTC), false},
+		{"InvalidDate", "2001-02-30", time.Time{}, true},
+		{"InvalidFormat", "yesterday", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTimestamp(tt.value)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, tt.want.Equal(got), "expecting %v, got %v", tt.want, got)
+		})
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		value   string
+		want    Version
+		wantErr bool
+	}{
+		{"MajorMinor", "2.0", Version{Major: 2}, false},
+		{"Fix", "1.3.2", Version{Major: 1, Minor: 3, Fix: 2}, false},
+		{"Qualifier", "18.0.3.beta", Version{Major: 18, Fix: 3, Qualifier: "beta"}, false},
+		{"Build", "18.0.3.beta-1", Version{Major: 18, Fix: 3, Qualifier: "beta", Build: 1}, false},
+		{"MajorOnly", "2", Version{}, true},
+		{"Invalid", "1.a", Version{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseVersion(tt.value)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0.0", 0},
+		{"1.2", "1.10", -1},
+		{"2.0.1", "2.0.0", 1},
+		{"1.0.0.alpha", "1.0.0.beta", -1},
+		{"1.0.0.beta-2", "1.0.0.beta-1", 1},
+	}
+	for _, tt := range tests {
+		a, err := ParseVersion(tt.a)
+		require.NoError(t, err)
+		b, err := ParseVersion(tt.b)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, a.Compare(b), "comparing %s and %s", tt.a, tt.b)
+	}
+}
+
+func TestCheckPrimitiveValue(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		typeName string
+		value    interface{}
+		wantErr  bool
+	}{
+		{"ValidTimestamp", "timestamp", "2019-11-04T10:00:00Z", false},
+		{"InvalidTimestamp", "timestamp", "tomorrow", true},
+		{"ValidVersion", "version", "1.3.0", false},
+		{"InvalidVersion", "version", "v1", true},
+		{"ValidRange", "range", []interface{}{1, "UNBOUNDED"}, false},
+		{"RangeNotAList", "range", "1-4", true},
+		{"RangeInverted", "range", []interface{}{4, 1}, true},
+		{"OtherTypesNotChecked", "string", "whatever", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckPrimitiveValue(tt.typeName, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckPrimitiveValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckLiteralValue(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		typeName string
+		value    string
+		wantErr  bool
+	}{
+		{"ValidInteger", "integer", "42", false},
+		{"InvalidInteger", "integer", "4.2", true},
+		{"ValidFloat", "float", "4.2", false},
+		{"InvalidFloat", "float", "four", true},
+		{"ValidBoolean", "boolean", "yes", false},
+		{"InvalidBoolean", "boolean", "maybe", true},
+		{"ValidScalarUnit", "scalar-unit.size", "10 GB", false},
+		{"InvalidScalarUnit", "scalar-unit.size", "10", true},
+		{"ValidTimestamp", "timestamp", "2019-11-04T10:00:00Z", false},
+		{"InvalidVersion", "version", "v1", true},
+		{"RangeNotALiteral", "range", "1-4", true},
+		{"OtherTypesNotChecked", "string", "whatever", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckLiteralValue(tt.typeName, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckLiteralValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -40,25 +40,37 @@ func (r *Range) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	if err := unmarshal(&v); err != nil {
 		return err
 	}
+	rg, err := newRange(v)
+	if err != nil {
+		return err
+	}
+	*r = rg
+	return nil
+}
+
+func newRange(v []string) (Range, error) {
+	r := Range{}
 	if len(v) != 2 {
-		return errors.Errorf("Invalid range definition expected %d elements, actually found %d", 2, len(v))
+		return r, errors.Errorf("Invalid range definition expected %d elements, actually found %d", 2, len(v))
 	}
 
 	bound, err := strconv.ParseUint(v[0], 10, 0)
 	if err != nil {
-		return errors.Errorf("Expecting a unsigned integer as lower bound of the range")
+		return r, errors.Errorf("Expecting a unsigned integer as lower bound of the range")
 	}
 	r.LowerBound = bound
 	if bound, err := strconv.ParseUint(v[1], 10, 0); err != nil {
 		if strings.ToUpper(v[1]) != "UNBOUNDED" {
-			return errors.Errorf("Expecting a unsigned integer or the 'UNBOUNDED' keyword as upper bound of the range")
+			return r, errors.Errorf("Expecting a unsigned integer or the 'UNBOUNDED' keyword as upper bound of the range")
 		}
 		r.UpperBound = UNBOUNDED
 	} else {
 		r.UpperBound = bound
 	}
-
-	return nil
+	if r.LowerBound > r.UpperBound {
+		return r, errors.Errorf("Invalid range definition lower bound %d is greater than upper bound %d", r.LowerBound, r.UpperBound)
+	}
+	return r, nil
 }
 
 func shouldQuoteYamlString(s string) bool {
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"gopkg.in/yaml.v2"
 	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 
@@ -39,9 +40,14 @@ const (
 	GetOperationOutputOperator Operator = "get_operation_output"
 	// ConcatOperator is the Operator of the concat function
 	ConcatOperator Operator = "concat"
-	// TokenOperator     Operator = "token"
-	// GetNodesOfTypeOperator     Operator = "get_nodes_of_type"
-	// GetArtifactOperator        Operator = "get_artifact"
+	// TokenOperator is the Operator of the token function
+	TokenOperator Operator = "token"
+	// JoinOperator is the Operator of the join function
+	JoinOperator Operator = "join"
+	// GetNodesOfTypeOperator is the Operator of the get_nodes_of_type function
+	GetNodesOfTypeOperator Operator = "get_nodes_of_type"
+	// GetArtifactOperator is the Operator of the get_artifact function
+	GetArtifactOperator Operator = "get_artifact"
 
 	// GetSecretOperator is the Operator of the get_secret function (non-normative)
 	GetSecretOperator Operator = "get_secret"
@@ -54,6 +60,10 @@ func IsOperator(op string) bool {
 		op == string(GetInputOperator) ||
 		op == string(GetOperationOutputOperator) ||
 		op == string(ConcatOperator) ||
+		op == string(TokenOperator) ||
+		op == string(JoinOperator) ||
+		op == string(GetNodesOfTypeOperator) ||
+		op == string(GetArtifactOperator) ||
 		op == string(GetSecretOperator)
 }
 
@@ -69,6 +79,14 @@ func parseOperator(op string) (Operator, error) {
 		return GetOperationOutputOperator, nil
 	case op == string(ConcatOperator):
 		return ConcatOperator, nil
+	case op == string(TokenOperator):
+		return TokenOperator, nil
+	case op == string(JoinOperator):
+		return JoinOperator, nil
+	case op == string(GetNodesOfTypeOperator):
+		return GetNodesOfTypeOperator, nil
+	case op == string(GetArtifactOperator):
+		return GetArtifactOperator, nil
 	case op == string(GetSecretOperator):
 		return GetSecretOperator, nil
 	default:
@@ -77,10 +95,10 @@ func parseOperator(op string) (Operator, error) {
 	}
 }
 
-// Operand represents the parameters part of a TOSCA function it could be a LiteralOperand or a Function
+// Operand represents the parameters part of a TOSCA function it could be a LiteralOperand, a ListOperand or a Function
 type Operand interface {
 	fmt.Stringer
-	// IsLiteral allows to know if an Operand is a LiteralOperand (true) or a TOSCA Function (false)
+	// IsLiteral allows to know if an Operand is a LiteralOperand (true) or a TOSCA Function or a ListOperand (false)
 	IsLiteral() bool
 }
 
@@ -94,13 +112,38 @@ func (l LiteralOperand) IsLiteral() bool {
 
 func (l LiteralOperand) String() string {
 	s := string(l)
-	if shouldQuoteYamlString(s) {
-		// Quote String if it contains YAML special chars
+	if shouldQuoteYamlString(s) || s == "" || s == "-" || s != strings.TrimSpace(s) {
+		// Quote String if it contains YAML special chars or if it is a delimiter
+		// that would be lost otherwise
 		s = strconv.Quote(s)
 	}
 	return s
 }
 
+// ListOperand represents a list of operands in a TOSCA function
+//
+// It is typically used by the join function which takes a list of string
+// value expressions as first parameter.
+type ListOperand []Operand
+
+// IsLiteral allows to know if an Operand is a LiteralOperand (true) or a TOSCA Function or a ListOperand (false)
+func (l ListOperand) IsLiteral() bool {
+	return false
+}
+
+func (l ListOperand) String() string {
+	var b bytes.Buffer
+	b.WriteString("[")
+	for i := range l {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(l[i].String())
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
 // Function models a TOSCA Function
 //
 // A Function is composed by an Operator and a list of Operand
@@ -119,18 +162,13 @@ func (f Function) String() string {
 	b.WriteString(string(f.Operator))
 	b.WriteString(": ")
 	if len(f.Operands) == 1 {
-		// Shortcut
-		b.WriteString(f.Operands[0].String())
-		return b.String()
-	}
-	b.WriteString("[")
-	for i := range f.Operands {
-		if i > 0 {
-			b.WriteString(", ")
+		if _, isList := f.Operands[0].(ListOperand); !isList {
+			// Shortcut
+			b.WriteString(f.Operands[0].String())
+			return b.String()
 		}
-		b.WriteString(f.Operands[i].String())
 	}
-	b.WriteString("]")
+	b.WriteString(ListOperand(f.Operands).String())
 	return b.String()
 }
 
@@ -142,9 +180,18 @@ func (f *Function) GetFunctionsByOperator(o Operator) []*Function {
 	if f.Operator == o {
 		result = append(result, f)
 	}
-	for _, op := range f.Operands {
-		if !op.IsLiteral() {
-			result = append(result, op.(*Function).GetFunctionsByOperator(o)...)
+	result = append(result, getFunctionsByOperatorInOperands(o, f.Operands)...)
+	return result
+}
+
+func getFunctionsByOperatorInOperands(o Operator, operands []Operand) []*Function {
+	result := make([]*Function, 0)
+	for _, op := range operands {
+		switch v := op.(type) {
+		case *Function:
+			result = append(result, v.GetFunctionsByOperator(o)...)
+		case ListOperand:
+			result = append(result, getFunctionsByOperatorInOperands(o, v)...)
 		}
 	}
 	return result
@@ -176,6 +223,15 @@ func parseFunctionOperands(value interface{}) ([]Operand, error) {
 		log.Debugf("Found array value %v %T", v, v)
 		ops = make([]Operand, len(v))
 		for i, op := range v {
+			if l, ok := op.([]interface{}); ok {
+				// Nested list like in join: [[a, b], "-"]
+				lo, err := parseFunctionOperands(l)
+				if err != nil {
+					return nil, err
+				}
+				ops[i] = ListOperand(lo)
+				continue
+			}
 			o, err := parseFunctionOperands(op)
 			if err != nil {
 				return nil, err
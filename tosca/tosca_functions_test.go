@@ -18,6 +18,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	yaml "gopkg.in/yaml.v2"
 
 	"github.com/ystia/yorc/v4/log"
@@ -41,6 +42,11 @@ func TestFunctionParsing(t *testing.T) {
 		{"TestConcatFunction", inputs{yml: "concat: [get_property: [SELF, ip_address], get_attribute: [SELF, port]]"}, false},
 		{"TestGetInputFunction", inputs{yml: "get_input: ip_address"}, false},
 		{"TestConcatFunctionQuoting", inputs{yml: `concat: ["http://", get_property: [SELF, ip_address], get_attribute: [SELF, port], "\"ff\""]`}, false},
+		{"TestTokenFunction", inputs{yml: `token: [get_attribute: [SELF, addresses], ",", 1]`}, false},
+		{"TestJoinFunction", inputs{yml: `join: [[get_input: prefix, "-", get_attribute: [SELF, id]], _]`}, false},
+		{"TestJoinFunctionWithoutDelimiter", inputs{yml: `join: [[a, b, c]]`}, false},
+		{"TestGetNodesOfTypeFunction", inputs{yml: `get_nodes_of_type: tosca.nodes.Compute`}, false},
+		{"TestGetArtifactFunction", inputs{yml: `get_artifact: [SELF, config_file, LOCAL_FILE]`}, false},
 	}
 
 	for _, tt := range tests {
@@ -65,6 +71,20 @@ func TestOperandsImplementation(t *testing.T) {
 	// Checks that LiteralOperand and Function implement the Operand interface
 	var _ Operand = (*LiteralOperand)(nil)
 	var _ Operand = (*Function)(nil)
+	var _ Operand = (ListOperand)(nil)
+}
+
+func TestJoinFunctionOperands(t *testing.T) {
+	t.Parallel()
+	f := generateFunctionFromYaml(t, `join: [[a, get_input: b], "-"]`)
+	require.Equal(t, JoinOperator, f.Operator)
+	require.Len(t, f.Operands, 2)
+	require.IsType(t, ListOperand{}, f.Operands[0])
+	list := f.Operands[0].(ListOperand)
+	require.Len(t, list, 2)
+	require.True(t, list[0].IsLiteral())
+	require.IsType(t, &Function{}, list[1])
+	require.Equal(t, LiteralOperand("-"), f.Operands[1])
 }
 
 func generateFunctionFromYaml(t testing.TB, yml string) *Function {
@@ -90,6 +110,7 @@ func TestFunction_GetFunctionsByOperator(t *testing.T) {
 		{"1stLevel", generateFunctionFromYaml(t, `{get_property: [SELF, port]}`), args{GetPropertyOperator}, []*Function{generateFunctionFromYaml(t, `{get_property: [SELF, port]}`)}},
 		{"nestedLevel", generateFunctionFromYaml(t, `{concat: [get_property: [SELF, port]]}`), args{GetPropertyOperator}, []*Function{generateFunctionFromYaml(t, `{get_property: [SELF, port]}`)}},
 		{"severalNestedLevel", generateFunctionFromYaml(t, `{concat: [get_property: [SELF, port], concat: [get_input: "i", get_property: [SELF, test]]]}`), args{GetPropertyOperator}, []*Function{generateFunctionFromYaml(t, `{get_property: [SELF, port]}`), generateFunctionFromYaml(t, `{get_property: [SELF, test]}`)}},
+		{"joinList", generateFunctionFromYaml(t, `{join: [[get_property: [SELF, port], a], "-"]}`), args{GetPropertyOperator}, []*Function{generateFunctionFromYaml(t, `{get_property: [SELF, port]}`)}},
 		{"notFound", generateFunctionFromYaml(t, `{concat: [get_property: [SELF, port], concat: [get_input: "i", get_property: [SELF, test]]]}`), args{GetAttributeOperator}, []*Function{}},
 	}
 	for _, tt := range tests {
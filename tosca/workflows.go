@@ -17,25 +17,56 @@ package tosca
 // A Workflow is the representation of a TOSCA Workflow
 //
 type Workflow struct {
-	Inputs  map[string]PropertyDefinition  `yaml:"inputs,omitempty" json:"inputs,omitempty"`
-	Steps   map[string]*Step               `yaml:"steps,omitempty" json:"steps,omitempty"`
-	Outputs map[string]ParameterDefinition `yaml:"outputs,omitempty" json:"outputs,omitempty"`
+	Inputs        map[string]PropertyDefinition  `yaml:"inputs,omitempty" json:"inputs,omitempty"`
+	Preconditions []Precondition                 `yaml:"preconditions,omitempty" json:"preconditions,omitempty"`
+	Steps         map[string]*Step               `yaml:"steps,omitempty" json:"steps,omitempty"`
+	Outputs       map[string]ParameterDefinition `yaml:"outputs,omitempty" json:"outputs,omitempty"`
+}
+
+// A Precondition is the representation of a TOSCA Workflow Precondition
+//
+// See http://docs.oasis-open.org/tosca/TOSCA-Simple-Profile-YAML/v1.3/TOSCA-Simple-Profile-YAML-v1.3.html#DEFN_ENTITY_WORKFLOW_PRECONDITION_DEFN
+// for more details
+type Precondition struct {
+	Target             string            `yaml:"target" json:"target"`
+	TargetRelationShip string            `yaml:"target_relationship,omitempty" json:"target_relationship,omitempty"`
+	Condition          []ConditionClause `yaml:"condition,omitempty" json:"condition,omitempty"`
 }
 
 // A Step is the representation of a TOSCA Workflow Step
 //
-// See http://docs.oasis-open.org/tosca/TOSCA-Simple-Profile-YAML/v1.2/TOSCA-Simple-Profile-YAML-v1.2.html#DEFN_ENTITY_WORKFLOW_STEP_DEFN
+// See http://docs.oasis-open.org/tosca/TOSCA-Simple-Profile-YAML/v1.3/TOSCA-Simple-Profile-YAML-v1.3.html#DEFN_ENTITY_WORKFLOW_STEP_DEFN
 // for more details
 type Step struct {
-	Target             string     `yaml:"target,omitempty" json:"target,omitempty"`
-	TargetRelationShip string     `yaml:"target_relationship,omitempty" json:"target_relationship,omitempty"`
-	Activities         []Activity `yaml:"activities" json:"activities"`
-	OnSuccess          []string   `yaml:"on_success,omitempty" json:"on_success,omitempty"`
-	OnFailure          []string   `yaml:"on_failure,omitempty" json:"on_failure,omitempty"`
-	OperationHost      string     `yaml:"operation_host,omitempty" json:"operation_host,omitempty"`
+	Target             string            `yaml:"target,omitempty" json:"target,omitempty"`
+	TargetRelationShip string            `yaml:"target_relationship,omitempty" json:"target_relationship,omitempty"`
+	Filter             []ConditionClause `yaml:"filter,omitempty" json:"filter,omitempty"`
+	Activities         []Activity        `yaml:"activities" json:"activities"`
+	OnSuccess          StepsList         `yaml:"on_success,omitempty" json:"on_success,omitempty"`
+	OnFailure          StepsList         `yaml:"on_failure,omitempty" json:"on_failure,omitempty"`
+	OperationHost      string            `yaml:"operation_host,omitempty" json:"operation_host,omitempty"`
 
 	// Non standard
-	OnCancel []string `yaml:"on_cancel,omitempty" json:"on_cancel,omitempty"`
+	OnCancel StepsList `yaml:"on_cancel,omitempty" json:"on_cancel,omitempty"`
+}
+
+// StepsList is a list of steps names which could be defined in YAML
+// as a single step name
+type StepsList []string
+
+// UnmarshalYAML unmarshals a yaml into a StepsList
+func (l *StepsList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err == nil {
+		*l = StepsList{s}
+		return nil
+	}
+	var list []string
+	if err := unmarshal(&list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
 }
 
 // An Activity is the representation of a TOSCA Workflow Step Activity
@@ -47,6 +78,7 @@ type Activity struct {
 	Delegate      *WorkflowActivity  `yaml:"delegate,omitempty" json:"delegate,omitempty"`
 	CallOperation *OperationActivity `yaml:"call_operation,omitempty" json:"call_operation,omitempty"`
 	Inline        *WorkflowActivity  `yaml:"inline,omitempty" json:"inline,omitempty"`
+	WaitForSignal *SignalActivity    `yaml:"wait_for_signal,omitempty" json:"wait_for_signal,omitempty"`
 }
 
 // WorkflowActivity defines the name of a workflow and optional input assignments
@@ -61,6 +93,14 @@ type OperationActivity struct {
 	Inputs    map[string]ParameterDefinition `yaml:"inputs,omitempty" json:"inputs,omitempty"`
 }
 
+// SignalActivity defines the name of a signal a step waits for and the optional inputs expected in the signal payload
+//
+// This is a Yorc extension to TOSCA workflow activities.
+type SignalActivity struct {
+	Signal string                         `yaml:"signal" json:"signal"`
+	Inputs map[string]ParameterDefinition `yaml:"inputs,omitempty" json:"inputs,omitempty"`
+}
+
 // UnmarshalYAML unmarshals a yaml into a WorkflowActivity
 func (w *WorkflowActivity) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	var err error
@@ -104,3 +144,25 @@ func (o *OperationActivity) UnmarshalYAML(unmarshal func(interface{}) error) err
 
 	return err
 }
+
+// UnmarshalYAML unmarshals a yaml into a SignalActivity
+func (sa *SignalActivity) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var err error
+	var s string
+	if err = unmarshal(&s); err == nil {
+		sa.Signal = s
+		return nil
+	}
+
+	var str struct {
+		Signal string                         `yaml:"signal" json:"signal"`
+		Inputs map[string]ParameterDefinition `yaml:"inputs,omitempty" json:"inputs,omitempty"`
+	}
+	if err = unmarshal(&str); err == nil {
+		sa.Signal = str.Signal
+		sa.Inputs = str.Inputs
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,134 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tosca
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestWorkflowPreconditionsAndFilters(t *testing.T) {
+	t.Parallel()
+	var inputYaml = `
+preconditions:
+  - target: Compute
+    condition:
+      - assert:
+        - state: [{equal: started}]
+steps:
+  step1:
+    target: Compute
+    filter:
+      - or:
+        - state: [{equal: started}]
+        - state: [{equal: configured}]
+    activities:
+      - call_operation: maintenance.enable
+    on_success: step2
+    on_failure:
+      - step3
+  step2:
+    target: Compute
+    activities:
+      - set_state: started
+  step3:
+    target: Compute
+    activities:
+      - set_state: error
+`
+	wf := Workflow{}
+	err := yaml.Unmarshal([]byte(inputYaml), &wf)
+	require.NoError(t, err)
+	require.Len(t, wf.Preconditions, 1)
+	require.Equal(t, "Compute", wf.Preconditions[0].Target)
+	require.Len(t, wf.Preconditions[0].Condition, 1)
+	require.Contains(t, wf.Preconditions[0].Condition[0].Assert, "state")
+	require.Equal(t, ConstraintClause{Operator: "equal", Value: "started"}, wf.Preconditions[0].Condition[0].Assert["state"][0])
+
+	require.Len(t, wf.Steps, 3)
+	step1 := wf.Steps["step1"]
+	require.Equal(t, StepsList{"step2"}, step1.OnSuccess)
+	require.Equal(t, StepsList{"step3"}, step1.OnFailure)
+	require.Len(t, step1.Filter, 1)
+	require.Len(t, step1.Filter[0].Or, 2)
+	require.Len(t, step1.Activities, 1)
+	require.Equal(t, "maintenance.enable", step1.Activities[0].CallOperation.Operation)
+	require.Nil(t, wf.Steps["step2"].OnSuccess)
+}
+
+func TestWorkflowWaitForSignalActivity(t *testing.T) {
+	t.Parallel()
+	var inputYaml = `
+steps:
+  step1:
+    activities:
+      - wait_for_signal: go_live
+    on_success: step2
+  step2:
+    activities:
+      - wait_for_signal:
+          signal: validation
+          inputs:
+            comment:
+              type: string
+              required: false
+`
+	wf := Workflow{}
+	err := yaml.Unmarshal([]byte(inputYaml), &wf)
+	require.NoError(t, err)
+	require.Len(t, wf.Steps, 2)
+	require.Len(t, wf.Steps["step1"].Activities, 1)
+	require.NotNil(t, wf.Steps["step1"].Activities[0].WaitForSignal)
+	require.Equal(t, "go_live", wf.Steps["step1"].Activities[0].WaitForSignal.Signal)
+	require.Len(t, wf.Steps["step1"].Activities[0].WaitForSignal.Inputs, 0)
+	require.NotNil(t, wf.Steps["step2"].Activities[0].WaitForSignal)
+	require.Equal(t, "validation", wf.Steps["step2"].Activities[0].WaitForSignal.Signal)
+	require.Contains(t, wf.Steps["step2"].Activities[0].WaitForSignal.Inputs, "comment")
+	require.Equal(t, "string", wf.Steps["step2"].Activities[0].WaitForSignal.Inputs["comment"].Type)
+}
+
+func TestWorkflowInputsAndOutputsConstraints(t *testing.T) {
+	t.Parallel()
+	var inputYaml = `
+inputs:
+  replicas:
+    type: integer
+    constraints:
+      - in_range: [1, 10]
+  env:
+    type: string
+    default: dev
+    constraints:
+      - valid_values: [dev, prod]
+outputs:
+  endpoint:
+    type: string
+    constraints:
+      - pattern: "^https?://.*"
+    value: { get_attribute: [Compute, public_address] }
+`
+	wf := Workflow{}
+	err := yaml.Unmarshal([]byte(inputYaml), &wf)
+	require.NoError(t, err)
+	require.Len(t, wf.Inputs, 2)
+	require.Equal(t, []ConstraintClause{{Operator: "in_range", Value: []interface{}{1, 10}}}, wf.Inputs["replicas"].Constraints)
+	require.Equal(t, []ConstraintClause{{Operator: "valid_values", Value: []interface{}{"dev", "prod"}}}, wf.Inputs["env"].Constraints)
+	require.NotNil(t, wf.Inputs["env"].Default)
+	require.Len(t, wf.Outputs, 1)
+	require.Equal(t, []ConstraintClause{{Operator: "pattern", Value: "^https?://.*"}}, wf.Outputs["endpoint"].Constraints)
+	require.NotNil(t, wf.Outputs["endpoint"].Value)
+}
@@ -0,0 +1,162 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package envvault provides a vault.Client resolving secrets from environment variables
+// and from files mounted in directories like Kubernetes Secrets volumes.
+package envvault
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/vault"
+	"github.com/ystia/yorc/v4/vault/internal/secret"
+)
+
+// DefaultEnvPrefix is the default prefix of environment variables containing secrets
+const DefaultEnvPrefix = "YORC_SECRET_"
+
+type clientBuilder struct {
+}
+
+func (b *clientBuilder) BuildClient(cfg config.Configuration) (vault.Client, error) {
+	log.Debug("Setting up environment Vault Client")
+	c := &envClient{
+		envPrefix:   cfg.Vault.GetStringOrDefault("env_prefix", DefaultEnvPrefix),
+		directories: cfg.Vault.GetStringSlice("directories"),
+	}
+	for _, d := range c.directories {
+		fi, err := os.Stat(d)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create env vault client")
+		}
+		if !fi.IsDir() {
+			return nil, errors.Errorf("failed to create env vault client: %q is not a directory", d)
+		}
+	}
+	return c, nil
+}
+
+type envClient struct {
+	envPrefix   string
+	directories []string
+}
+
+// envName returns the name of the environment variable for a secret id.
+// Non alphanumeric characters are replaced by '_' and the result is upper-cased.
+func envName(id string) string {
+	return strings.ToUpper(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, strings.Trim(id, "/")))
+}
+
+func (c *envClient) lookupEnv(id string) (interface{}, bool) {
+	name := c.envPrefix + envName(id)
+	if v, ok := os.LookupEnv(name); ok {
+		return v, true
+	}
+	// Variables sharing the id prefix are gathered in a map
+	var values map[string]interface{}
+	for _, e := range os.Environ() {
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) == 2 && strings.HasPrefix(kv[0], name+"_") {
+			if values == nil {
+				values = make(map[string]interface{})
+			}
+			values[strings.ToLower(strings.TrimPrefix(kv[0], name+"_"))] = kv[1]
+		}
+	}
+	return values, values != nil
+}
+
+func readSecretFile(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read secret file %q", path)
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+func (c *envClient) lookupDirectory(dir, id string) (interface{}, bool, error) {
+	p := filepath.Join(dir, filepath.FromSlash(id))
+	if rel, err := filepath.Rel(dir, p); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, false, errors.Errorf("invalid secret id %q", id)
+	}
+	fi, err := os.Stat(p)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to read secret %q", id)
+	}
+	if !fi.IsDir() {
+		v, err := readSecretFile(p)
+		return v, err == nil, err
+	}
+	// Directories are mapped to maps of their files
+	// hidden files like Kubernetes internal '..data' links are ignored
+	infos, err := ioutil.ReadDir(p)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to read secret %q", id)
+	}
+	values := make(map[string]interface{})
+	for _, info := range infos {
+		if strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
+		fp := filepath.Join(p, info.Name())
+		if fi, err := os.Stat(fp); err != nil || fi.IsDir() {
+			continue
+		}
+		v, err := readSecretFile(fp)
+		if err != nil {
+			return nil, false, err
+		}
+		values[info.Name()] = v
+	}
+	return values, true, nil
+}
+
+func (c *envClient) GetSecret(id string, options ...string) (vault.Secret, error) {
+	if strings.Trim(id, "/") == "" {
+		return nil, errors.New("secret id should not be empty")
+	}
+	if v, ok := c.lookupEnv(id); ok {
+		return secret.New(v, options...), nil
+	}
+	for _, d := range c.directories {
+		v, ok, err := c.lookupDirectory(d, id)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return secret.New(v, options...), nil
+		}
+	}
+	return nil, errors.Errorf("secret %q not found", id)
+}
+
+func (c *envClient) Shutdown() error {
+	return nil
+}
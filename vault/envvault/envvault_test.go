@@ -0,0 +1,83 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envvault
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ystia/yorc/v4/config"
+)
+
+func TestEnvVault(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "db", "..data"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "db", "user"), []byte("dbuser\n"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "db", "password"), []byte("dbpass"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "db", ".hidden"), []byte("hidden"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "token"), []byte("file token"), 0600))
+
+	os.Setenv("TEST_YORC_SECRET_API_TOKEN", "env token")
+	os.Setenv("TEST_YORC_SECRET_SSH_USER", "sshuser")
+	os.Setenv("TEST_YORC_SECRET_SSH_KEY", "sshkey")
+	defer func() {
+		os.Unsetenv("TEST_YORC_SECRET_API_TOKEN")
+		os.Unsetenv("TEST_YORC_SECRET_SSH_USER")
+		os.Unsetenv("TEST_YORC_SECRET_SSH_KEY")
+	}()
+
+	_, err := (&clientBuilder{}).BuildClient(config.Configuration{Vault: config.DynamicMap{"directories": filepath.Join(dir, "missing")}})
+	require.Error(t, err)
+
+	client, err := (&clientBuilder{}).BuildClient(config.Configuration{Vault: config.DynamicMap{
+		"env_prefix":  "TEST_YORC_SECRET_",
+		"directories": dir,
+	}})
+	require.NoError(t, err)
+	defer client.Shutdown()
+
+	tests := []struct {
+		name    string
+		id      string
+		options []string
+		want    string
+		wantErr bool
+	}{
+		{"EnvVar", "api/token", nil, "env token", false},
+		{"EnvVarsMap", "ssh", []string{"data=key"}, "sshkey", false},
+		{"File", "token", nil, "file token", false},
+		{"FileTrailingNewLine", "db/user", nil, "dbuser", false},
+		{"Directory", "db", []string{"data=password"}, "dbpass", false},
+		{"HiddenFile", "db", []string{"data=.hidden"}, "<nil>", false},
+		{"NotFound", "unknown", nil, "", true},
+		{"OutsideDirectory", "../secrets", nil, "", true},
+		{"Empty", "/", nil, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := client.GetSecret(tt.id, tt.options...)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, s.String())
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package filevault provides a vault.Client resolving secrets from a local YAML file encrypted with age or OpenPGP.
+package filevault
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/vault"
+	"github.com/ystia/yorc/v4/vault/internal/decrypt"
+	"github.com/ystia/yorc/v4/vault/internal/secret"
+)
+
+type clientBuilder struct {
+}
+
+func (b *clientBuilder) BuildClient(cfg config.Configuration) (vault.Client, error) {
+	log.Debug("Setting up encrypted file Vault Client")
+	path := cfg.Vault.GetString("path")
+	if path == "" {
+		return nil, errors.New("missing mandatory option \"path\" for file vault")
+	}
+	decrypter, err := decrypt.FromConfig(cfg.Vault)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create file vault client")
+	}
+	c := &fileClient{file: &secret.File{Path: path, Decode: func(p string) (map[string]interface{}, error) {
+		b, err := decrypter.DecryptFile(p)
+		if err != nil {
+			return nil, err
+		}
+		return secret.UnmarshalYAML(b)
+	}}}
+	// Check secrets file at startup
+	if _, err = c.file.Secrets(); err != nil {
+		return nil, errors.Wrap(err, "failed to create file vault client")
+	}
+	return c, nil
+}
+
+type fileClient struct {
+	file *secret.File
+}
+
+func (c *fileClient) GetSecret(id string, options ...string) (vault.Secret, error) {
+	return c.file.Get(id, options...)
+}
+
+func (c *fileClient) Shutdown() error {
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filevault
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+
+	"github.com/ystia/yorc/v4/config"
+)
+
+func writeEncryptedFile(t *testing.T, path, content, passphrase string) {
+	buf := new(bytes.Buffer)
+	aw, err := armor.Encode(buf, "PGP MESSAGE", nil)
+	require.NoError(t, err)
+	w, err := openpgp.SymmetricallyEncrypt(aw, []byte(passphrase), nil, nil)
+	require.NoError(t, err)
+	_, err = w.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, aw.Close())
+	require.NoError(t, ioutil.WriteFile(path, buf.Bytes(), 0600))
+}
+
+func TestFileVault(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.yaml.asc")
+	writeEncryptedFile(t, path, `
+secret/ssh:
+  user: me
+  key: my key
+api_token: token1
+`, "my passphrase")
+
+	builder := &clientBuilder{}
+	_, err := builder.BuildClient(config.Configuration{Vault: config.DynamicMap{"pgp_passphrase": "my passphrase"}})
+	require.Error(t, err, "path should be mandatory")
+	_, err = builder.BuildClient(config.Configuration{Vault: config.DynamicMap{"path": path, "pgp_passphrase": "wrong"}})
+	require.Error(t, err)
+
+	client, err := builder.BuildClient(config.Configuration{Vault: config.DynamicMap{"path": path, "pgp_passphrase": "my passphrase"}})
+	require.NoError(t, err)
+	defer client.Shutdown()
+
+	s, err := client.GetSecret("secret/ssh", "data=key")
+	require.NoError(t, err)
+	require.Equal(t, "my key", s.String())
+	require.Equal(t, map[string]interface{}{"user": "me", "key": "my key"}, s.Raw())
+	s, err = client.GetSecret("api_token")
+	require.NoError(t, err)
+	require.Equal(t, "token1", s.String())
+	_, err = client.GetSecret("unknown")
+	require.Error(t, err)
+
+	// Updated file is loaded again
+	writeEncryptedFile(t, path, "api_token: token2", "my passphrase")
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, future, future))
+	s, err = client.GetSecret("api_token")
+	require.NoError(t, err)
+	require.Equal(t, "token2", s.String())
+}
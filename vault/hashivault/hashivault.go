@@ -17,6 +17,7 @@ package hashivault
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/vault/api"
 	"github.com/pkg/errors"
@@ -186,6 +187,18 @@ func (vc *vaultClient) startRenewing() {
 	}()
 }
 
+func (vc *vaultClient) RenewLease(leaseID string, increment time.Duration) (time.Duration, error) {
+	s, err := vc.vClient.Sys().Renew(leaseID, int(increment.Seconds()))
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to renew lease %q", leaseID)
+	}
+	return time.Duration(s.LeaseDuration) * time.Second, nil
+}
+
+func (vc *vaultClient) RevokeLease(leaseID string) error {
+	return errors.Wrapf(vc.vClient.Sys().Revoke(leaseID), "failed to revoke lease %q", leaseID)
+}
+
 func (vc *vaultClient) Shutdown() error {
 	return nil
 }
@@ -205,3 +218,15 @@ func (vs *vaultSecret) String() string {
 func (vs *vaultSecret) Raw() interface{} {
 	return vs.Secret
 }
+
+func (vs *vaultSecret) LeaseID() string {
+	return vs.Secret.LeaseID
+}
+
+func (vs *vaultSecret) LeaseDuration() time.Duration {
+	return time.Duration(vs.Secret.LeaseDuration) * time.Second
+}
+
+func (vs *vaultSecret) Renewable() bool {
+	return vs.Secret.Renewable
+}
@@ -0,0 +1,158 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package decrypt allows to decrypt data encrypted with age (https://age-encryption.org)
+// or OpenPGP using keys defined in the Yorc vault configuration.
+package decrypt
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/openpgp"
+
+	"github.com/ystia/yorc/v4/config"
+)
+
+// A Decrypter decrypts age or OpenPGP encrypted data
+type Decrypter struct {
+	ageIdentities []age.Identity
+	agePassphrase string
+	pgpKeyRing    openpgp.EntityList
+	pgpPassphrase []byte
+}
+
+// FromConfig creates a Decrypter from the keys defined in the given vault configuration.
+//
+// Recognized options are:
+//   - age_identity: age X25519 identities
+//   - age_identity_file: path to a file containing age X25519 identities
+//   - age_passphrase: passphrase of age files encrypted with a passphrase
+//   - pgp_private_key: armored OpenPGP private keys
+//   - pgp_private_key_file: path to a file containing OpenPGP private keys
+//   - pgp_passphrase: passphrase of OpenPGP private keys or of OpenPGP messages symmetrically encrypted
+func FromConfig(vaultCfg config.DynamicMap) (*Decrypter, error) {
+	d := &Decrypter{}
+	if id := vaultCfg.GetString("age_identity"); id != "" {
+		if err := d.AddAgeIdentities([]byte(id)); err != nil {
+			return nil, err
+		}
+	}
+	if f := vaultCfg.GetString("age_identity_file"); f != "" {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read age identity file")
+		}
+		if err = d.AddAgeIdentities(b); err != nil {
+			return nil, errors.Wrapf(err, "invalid age identity file %q", f)
+		}
+	}
+	d.agePassphrase = vaultCfg.GetString("age_passphrase")
+	if k := vaultCfg.GetString("pgp_private_key"); k != "" {
+		if err := d.AddPGPKeyRing([]byte(k)); err != nil {
+			return nil, err
+		}
+	}
+	if f := vaultCfg.GetString("pgp_private_key_file"); f != "" {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read PGP private key file")
+		}
+		if err = d.AddPGPKeyRing(b); err != nil {
+			return nil, errors.Wrapf(err, "invalid PGP private key file %q", f)
+		}
+	}
+	d.pgpPassphrase = []byte(vaultCfg.GetString("pgp_passphrase"))
+	return d, nil
+}
+
+// AddAgeIdentities adds age X25519 identities to the Decrypter.
+//
+// Identities are given one per line, empty lines and lines starting with '#' are ignored.
+func (d *Decrypter) AddAgeIdentities(data []byte) error {
+	ids, err := age.ParseIdentities(bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrap(err, "malformed age identities")
+	}
+	d.ageIdentities = append(d.ageIdentities, ids...)
+	return nil
+}
+
+// AddPGPKeyRing adds OpenPGP keys, armored or not, to the Decrypter
+func (d *Decrypter) AddPGPKeyRing(data []byte) error {
+	keyRing, err := readPGPKeyRing(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to read PGP keys")
+	}
+	d.pgpKeyRing = append(d.pgpKeyRing, keyRing...)
+	return nil
+}
+
+// HasAgeIdentities returns true if age identities or an age passphrase are defined
+func (d *Decrypter) HasAgeIdentities() bool {
+	return len(d.ageIdentities) > 0 || d.agePassphrase != ""
+}
+
+// Decrypt decrypts the given age or OpenPGP data, armored or not.
+//
+// Data that are not in the age format are considered as OpenPGP messages.
+func (d *Decrypter) Decrypt(data []byte) ([]byte, error) {
+	if isAge(data) {
+		return d.decryptAge(data)
+	}
+	return d.decryptPGP(data)
+}
+
+func isAge(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("age-encryption.org/v1\n")) || bytes.HasPrefix(bytes.TrimSpace(data), []byte(armor.Header))
+}
+
+func (d *Decrypter) decryptAge(data []byte) ([]byte, error) {
+	identities := d.ageIdentities
+	if d.agePassphrase != "" {
+		id, err := age.NewScryptIdentity(d.agePassphrase)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid age passphrase")
+		}
+		identities = append(identities[:len(identities):len(identities)], id)
+	}
+	if len(identities) == 0 {
+		return nil, errors.New("data is encrypted with age but no age identity or passphrase is configured")
+	}
+
+	var src io.Reader = bytes.NewReader(data)
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte(armor.Header)) {
+		src = armor.NewReader(bytes.NewReader(trimmed))
+	}
+	r, err := age.Decrypt(src, identities...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt age data")
+	}
+	b, err := ioutil.ReadAll(r)
+	return b, errors.Wrap(err, "failed to decrypt age payload")
+}
+
+// DecryptFile decrypts the content of the given file
+func (d *Decrypter) DecryptFile(path string) ([]byte, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read file %q", path)
+	}
+	b, err = d.Decrypt(b)
+	return b, errors.Wrapf(err, "failed to decrypt file %q", path)
+}
@@ -0,0 +1,194 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package decrypt
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/openpgp"
+	openpgparmor "golang.org/x/crypto/openpgp/armor"
+	// Required by openpgp to encrypt messages for test entities
+	_ "golang.org/x/crypto/ripemd160"
+
+	"github.com/ystia/yorc/v4/config"
+)
+
+// newAgeIdentity returns an age identity and its recipient
+func newAgeIdentity(t *testing.T) (string, age.Recipient) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	return identity.String(), identity.Recipient()
+}
+
+func ageEncrypt(t *testing.T, plaintext []byte, armored bool, recipients ...age.Recipient) []byte {
+	buf := &bytes.Buffer{}
+	var out io.Writer = buf
+	var aw io.WriteCloser
+	if armored {
+		aw = armor.NewWriter(buf)
+		out = aw
+	}
+	w, err := age.Encrypt(out, recipients...)
+	require.NoError(t, err)
+	_, err = w.Write(plaintext)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	if aw != nil {
+		require.NoError(t, aw.Close())
+	}
+	return buf.Bytes()
+}
+
+func ageEncryptX25519(t *testing.T, plaintext []byte, recipients ...age.Recipient) []byte {
+	return ageEncrypt(t, plaintext, false, recipients...)
+}
+
+func ageEncryptScrypt(t *testing.T, plaintext []byte, passphrase string) []byte {
+	recipient, err := age.NewScryptRecipient(passphrase)
+	require.NoError(t, err)
+	recipient.SetWorkFactor(10)
+	return ageEncrypt(t, plaintext, false, recipient)
+}
+
+func TestDecryptAge(t *testing.T) {
+	t.Parallel()
+	identity, recipient := newAgeIdentity(t)
+	otherIdentity, otherRecipient := newAgeIdentity(t)
+	_, unknownRecipient := newAgeIdentity(t)
+	largePlaintext := bytes.Repeat([]byte("0123456789"), 2*64*1024/10+7)
+
+	d := &Decrypter{agePassphrase: "my passphrase"}
+	require.NoError(t, d.AddAgeIdentities([]byte("# created: 2020-06-11\n"+identity+"\n\n"+otherIdentity+"\n")))
+	require.True(t, d.HasAgeIdentities())
+
+	armored := append([]byte("\n"), ageEncrypt(t, []byte("armored"), true, recipient)...)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    []byte
+		wantErr bool
+	}{
+		{"X25519", ageEncryptX25519(t, []byte("secret: value"), recipient), []byte("secret: value"), false},
+		{"X25519SecondRecipient", ageEncryptX25519(t, []byte("other"), unknownRecipient, otherRecipient), []byte("other"), false},
+		{"X25519Empty", ageEncryptX25519(t, []byte{}, recipient), []byte{}, false},
+		{"X25519MultipleChunks", ageEncryptX25519(t, largePlaintext, recipient), largePlaintext, false},
+		{"X25519Armored", armored, []byte("armored"), false},
+		{"X25519UnknownRecipient", ageEncryptX25519(t, []byte("secret"), unknownRecipient), nil, true},
+		{"Scrypt", ageEncryptScrypt(t, []byte("secret"), "my passphrase"), []byte("secret"), false},
+		{"ScryptWrongPassphrase", ageEncryptScrypt(t, []byte("secret"), "other passphrase"), nil, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := d.Decrypt(tt.data)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, string(tt.want), string(got))
+		})
+	}
+}
+
+func TestDecryptAgeTampered(t *testing.T) {
+	t.Parallel()
+	identity, recipient := newAgeIdentity(t)
+	d := &Decrypter{}
+	require.NoError(t, d.AddAgeIdentities([]byte(identity)))
+
+	data := ageEncryptX25519(t, []byte("secret"), recipient)
+	data[len(data)-1] ^= 0xff
+	_, err := d.Decrypt(data)
+	require.Error(t, err)
+
+	data = ageEncryptX25519(t, []byte("secret"), recipient)
+	tampered := bytes.Replace(data, []byte("-> X25519"), []byte("-> X25519 extra\n-> X25519"), 1)
+	_, err = d.Decrypt(tampered)
+	require.Error(t, err)
+}
+
+func pgpEncrypt(t *testing.T, plaintext []byte, to []*openpgp.Entity, passphrase []byte) []byte {
+	buf := new(bytes.Buffer)
+	aw, err := openpgparmor.Encode(buf, "PGP MESSAGE", nil)
+	require.NoError(t, err)
+	var w interface {
+		Write([]byte) (int, error)
+		Close() error
+	}
+	if passphrase != nil {
+		w, err = openpgp.SymmetricallyEncrypt(aw, passphrase, nil, nil)
+	} else {
+		w, err = openpgp.Encrypt(aw, to, nil, nil, nil)
+	}
+	require.NoError(t, err)
+	_, err = w.Write(plaintext)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, aw.Close())
+	return buf.Bytes()
+}
+
+func TestDecryptPGP(t *testing.T) {
+	t.Parallel()
+	entity, err := openpgp.NewEntity("yorc", "test", "yorc@example.com", nil)
+	require.NoError(t, err)
+	other, err := openpgp.NewEntity("other", "test", "other@example.com", nil)
+	require.NoError(t, err)
+
+	keyBuf := new(bytes.Buffer)
+	aw, err := openpgparmor.Encode(keyBuf, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.SerializePrivate(aw, nil))
+	require.NoError(t, aw.Close())
+	keyFile := filepath.Join(t.TempDir(), "key.asc")
+	require.NoError(t, ioutil.WriteFile(keyFile, keyBuf.Bytes(), 0600))
+
+	d, err := FromConfig(config.DynamicMap{"pgp_private_key_file": keyFile, "pgp_passphrase": "sym"})
+	require.NoError(t, err)
+
+	got, err := d.Decrypt(pgpEncrypt(t, []byte("pgp secret"), []*openpgp.Entity{entity}, nil))
+	require.NoError(t, err)
+	require.Equal(t, "pgp secret", string(got))
+
+	got, err = d.Decrypt(pgpEncrypt(t, []byte("symmetric secret"), nil, []byte("sym")))
+	require.NoError(t, err)
+	require.Equal(t, "symmetric secret", string(got))
+
+	_, err = d.Decrypt(pgpEncrypt(t, []byte("pgp secret"), []*openpgp.Entity{other}, nil))
+	require.Error(t, err)
+}
+
+func TestFromConfigErrors(t *testing.T) {
+	t.Parallel()
+	_, err := FromConfig(config.DynamicMap{"age_identity": "AGE-SECRET-KEY-1INVALID"})
+	require.Error(t, err)
+	_, err = FromConfig(config.DynamicMap{"age_identity_file": filepath.Join(t.TempDir(), "missing")})
+	require.Error(t, err)
+	_, err = FromConfig(config.DynamicMap{"pgp_private_key": "not a key"})
+	require.Error(t, err)
+	d, err := FromConfig(config.DynamicMap{})
+	require.NoError(t, err)
+	require.False(t, d.HasAgeIdentities())
+}
@@ -0,0 +1,77 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package decrypt
+
+import (
+	"bytes"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+const pgpMessageArmorHeader = "-----BEGIN PGP MESSAGE-----"
+
+func isArmoredPGP(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte(pgpMessageArmorHeader))
+}
+
+func readPGPKeyRing(data []byte) (openpgp.EntityList, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----BEGIN")) {
+		return openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	}
+	return openpgp.ReadKeyRing(bytes.NewReader(data))
+}
+
+func (d *Decrypter) decryptPGP(data []byte) ([]byte, error) {
+	r := bytes.NewReader(data)
+	if isArmoredPGP(data) {
+		block, err := armor.Decode(bytes.NewReader(bytes.TrimSpace(data)))
+		if err != nil {
+			return nil, errors.Wrap(err, "malformed armored PGP message")
+		}
+		b, err := ioutil.ReadAll(block.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "malformed armored PGP message")
+		}
+		r = bytes.NewReader(b)
+	}
+
+	prompted := false
+	prompt := func(keys []openpgp.Key, symmetric bool) ([]byte, error) {
+		if prompted || len(d.pgpPassphrase) == 0 {
+			return nil, errors.New("no configured PGP key or passphrase allows to decrypt the message")
+		}
+		prompted = true
+		if symmetric {
+			return d.pgpPassphrase, nil
+		}
+		for _, k := range keys {
+			if k.PrivateKey != nil && k.PrivateKey.Encrypted {
+				if err := k.PrivateKey.Decrypt(d.pgpPassphrase); err != nil {
+					return nil, errors.Wrap(err, "failed to decrypt PGP private key with the configured passphrase")
+				}
+			}
+		}
+		return nil, nil
+	}
+	md, err := openpgp.ReadMessage(r, d.pgpKeyRing, prompt, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt PGP message")
+	}
+	b, err := ioutil.ReadAll(md.UnverifiedBody)
+	return b, errors.Wrap(err, "failed to decrypt PGP message")
+}
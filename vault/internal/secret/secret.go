@@ -0,0 +1,180 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secret provides helpers shared by vault implementations resolving secrets from structured data.
+package secret
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ystia/yorc/v4/vault"
+)
+
+// ParseOptions parses vault.Client GetSecret options given in the form "key=value" or "key"
+func ParseOptions(options ...string) map[string]string {
+	opts := make(map[string]string)
+	for _, o := range options {
+		optsList := strings.SplitN(o, "=", 2)
+		if len(optsList) == 2 {
+			opts[optsList[0]] = optsList[1]
+		} else {
+			opts[o] = ""
+		}
+	}
+	return opts
+}
+
+type secret struct {
+	value   interface{}
+	options map[string]string
+}
+
+// New returns a vault.Secret for the given value.
+//
+// Like for HashiCorp Vault secrets, the "data=<field>" option allows to select a field of a map value
+// when converting the secret into a string.
+func New(value interface{}, options ...string) vault.Secret {
+	return &secret{value: value, options: ParseOptions(options...)}
+}
+
+func toString(v interface{}) string {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+func (s *secret) String() string {
+	if d, ok := s.options["data"]; ok {
+		if m, ok := s.value.(map[string]interface{}); ok {
+			return toString(m[d])
+		}
+	}
+	return toString(s.value)
+}
+
+func (s *secret) Raw() interface{} {
+	return s.value
+}
+
+// Lookup looks for a secret id in the given data.
+//
+// The id is first looked up as a top-level key, then as a path of keys separated by '/' in nested maps.
+func Lookup(data map[string]interface{}, id string) (interface{}, bool) {
+	if v, ok := data[id]; ok {
+		return v, true
+	}
+	var current interface{} = data
+	for _, k := range strings.Split(strings.Trim(id, "/"), "/") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[k]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// Normalize converts maps decoded from YAML into map[string]interface{} recursively
+func Normalize(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(value))
+		for k, val := range value {
+			m[fmt.Sprint(k)] = Normalize(val)
+		}
+		return m
+	case map[string]interface{}:
+		for k, val := range value {
+			value[k] = Normalize(val)
+		}
+		return value
+	case []interface{}:
+		for i, val := range value {
+			value[i] = Normalize(val)
+		}
+		return value
+	default:
+		return v
+	}
+}
+
+// UnmarshalYAML decodes YAML or JSON data into a map of secrets
+func UnmarshalYAML(data []byte) (map[string]interface{}, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, errors.Wrap(err, "failed to decode secrets")
+	}
+	if raw == nil {
+		return make(map[string]interface{}), nil
+	}
+	m, ok := Normalize(raw).(map[string]interface{})
+	if !ok {
+		return nil, errors.New("failed to decode secrets: expecting a map")
+	}
+	return m, nil
+}
+
+// A File is a file of secrets which is loaded again when it changes
+type File struct {
+	// Path of the file
+	Path string
+	// Decode decodes the file content into a map of secrets
+	Decode func(path string) (map[string]interface{}, error)
+
+	lock    sync.Mutex
+	modTime time.Time
+	size    int64
+	secrets map[string]interface{}
+}
+
+// Secrets returns the file secrets, loading the file if it changed since the last call
+func (f *File) Secrets() (map[string]interface{}, error) {
+	fi, err := os.Stat(f.Path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read secrets file %q", f.Path)
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.secrets == nil || !fi.ModTime().Equal(f.modTime) || fi.Size() != f.size {
+		secrets, err := f.Decode(f.Path)
+		if err != nil {
+			return nil, err
+		}
+		f.secrets, f.modTime, f.size = secrets, fi.ModTime(), fi.Size()
+	}
+	return f.secrets, nil
+}
+
+// Get returns the secret with the given id from the file
+func (f *File) Get(id string, options ...string) (vault.Secret, error) {
+	secrets, err := f.Secrets()
+	if err != nil {
+		return nil, err
+	}
+	v, ok := Lookup(secrets, id)
+	if !ok {
+		return nil, errors.Errorf("secret %q not found", id)
+	}
+	return New(v, options...), nil
+}
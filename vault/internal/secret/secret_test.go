@@ -0,0 +1,117 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretOptions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		value   interface{}
+		options []string
+		want    string
+	}{
+		{"String", "value", nil, "value"},
+		{"Bytes", []byte("value"), nil, "value"},
+		{"MapField", map[string]interface{}{"user": "me", "password": "pass"}, []string{"data=password"}, "pass"},
+		{"ScalarWithDataOption", "value", []string{"data=password"}, "value"},
+		{"UnknownOption", 12, []string{"other"}, "12"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := New(tt.value, tt.options...)
+			require.Equal(t, tt.want, s.String())
+			require.Equal(t, tt.value, s.Raw())
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+	data, err := UnmarshalYAML([]byte(`
+secret/flat: flat value
+secret:
+  db:
+    user: me
+    password: pass
+list: [1, 2]
+`))
+	require.NoError(t, err)
+
+	v, ok := Lookup(data, "secret/flat")
+	require.True(t, ok)
+	require.Equal(t, "flat value", v)
+	v, ok = Lookup(data, "/secret/db/password")
+	require.True(t, ok)
+	require.Equal(t, "pass", v)
+	v, ok = Lookup(data, "secret/db")
+	require.True(t, ok)
+	require.Equal(t, map[string]interface{}{"user": "me", "password": "pass"}, v)
+	_, ok = Lookup(data, "secret/db/unknown")
+	require.False(t, ok)
+	_, ok = Lookup(data, "list/0")
+	require.False(t, ok)
+
+	_, err = UnmarshalYAML([]byte("- not a map"))
+	require.Error(t, err)
+}
+
+func TestFile(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "secrets.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte("key: value1"), 0600))
+	decodeCalls := 0
+	f := &File{Path: path, Decode: func(p string) (map[string]interface{}, error) {
+		decodeCalls++
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		return UnmarshalYAML(b)
+	}}
+
+	s, err := f.Get("key")
+	require.NoError(t, err)
+	require.Equal(t, "value1", s.String())
+	_, err = f.Get("key")
+	require.NoError(t, err)
+	require.Equal(t, 1, decodeCalls, "file should be decoded once")
+
+	_, err = f.Get("unknown")
+	require.Error(t, err)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("key: value2"), 0600))
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, future, future))
+	s, err = f.Get("key")
+	require.NoError(t, err)
+	require.Equal(t, "value2", s.String())
+	require.Equal(t, 2, decodeCalls)
+
+	require.NoError(t, os.Remove(path))
+	_, err = f.Get("key")
+	require.Error(t, err)
+}
@@ -0,0 +1,436 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sopsvault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ystia/yorc/v4/vault/internal/decrypt"
+	"github.com/ystia/yorc/v4/vault/internal/secret"
+)
+
+const sopsMetadataKey = "sops"
+
+var encryptedValueRegexp = regexp.MustCompile(`^ENC\[AES256_GCM,data:(.*),iv:(.+),tag:(.+),type:(.+)\]$`)
+
+// dataKey decrypts the SOPS data key using age or PGP master keys
+func dataKey(metadata map[string]interface{}, decrypter *decrypt.Decrypter) ([]byte, error) {
+	if cast.ToInt(metadata["shamir_threshold"]) > 1 {
+		return nil, errors.New("sops files using Shamir secret sharing are not supported")
+	}
+	var encKeys []string
+	collect := func(group map[string]interface{}) {
+		for _, keyType := range []string{"age", "pgp"} {
+			keys, _ := group[keyType].([]interface{})
+			for _, k := range keys {
+				if m, ok := k.(map[string]interface{}); ok {
+					if enc := cast.ToString(m["enc"]); enc != "" {
+						encKeys = append(encKeys, enc)
+					}
+				}
+			}
+		}
+	}
+	collect(metadata)
+	groups, _ := metadata["key_groups"].([]interface{})
+	for _, g := range groups {
+		if m, ok := g.(map[string]interface{}); ok {
+			collect(m)
+		}
+	}
+	if len(encKeys) == 0 {
+		return nil, errors.New("no age or pgp key found in sops metadata")
+	}
+	var err error
+	for _, enc := range encKeys {
+		var key []byte
+		key, err = decrypter.Decrypt([]byte(enc))
+		if err == nil {
+			return key, nil
+		}
+	}
+	return nil, errors.Wrap(err, "failed to decrypt sops data key with configured keys")
+}
+
+func decryptValue(value string, key []byte, additionalData string) (interface{}, error) {
+	matches := encryptedValueRegexp.FindStringSubmatch(value)
+	if matches == nil {
+		return nil, errors.New("value is not encrypted")
+	}
+	data, err := base64.StdEncoding.DecodeString(matches[1])
+	if err != nil {
+		return nil, errors.Wrap(err, "malformed encrypted data")
+	}
+	iv, err := base64.StdEncoding.DecodeString(matches[2])
+	if err != nil {
+		return nil, errors.Wrap(err, "malformed encrypted data iv")
+	}
+	tag, err := base64.StdEncoding.DecodeString(matches[3])
+	if err != nil {
+		return nil, errors.Wrap(err, "malformed encrypted data tag")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid sops data key")
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(iv))
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, iv, append(data, tag...), []byte(additionalData))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt value")
+	}
+	p := string(plaintext)
+	switch matches[4] {
+	case "str":
+		return p, nil
+	case "int":
+		return strconv.Atoi(p)
+	case "float":
+		return strconv.ParseFloat(p, 64)
+	case "bool":
+		return strconv.ParseBool(p)
+	case "bytes":
+		return plaintext, nil
+	default:
+		return nil, errors.Errorf("unsupported encrypted value type %q", matches[4])
+	}
+}
+
+// macBytes returns the representation of a value used by SOPS to compute the file MAC
+func macBytes(v interface{}) ([]byte, error) {
+	switch value := v.(type) {
+	case string:
+		return []byte(value), nil
+	case []byte:
+		return value, nil
+	case int:
+		return []byte(strconv.Itoa(value)), nil
+	case float64:
+		return []byte(strconv.FormatFloat(value, 'f', -1, 64)), nil
+	case bool:
+		// SOPS keeps the Python representation of booleans
+		if value {
+			return []byte("True"), nil
+		}
+		return []byte("False"), nil
+	default:
+		return nil, errors.Errorf("unsupported value type %T", v)
+	}
+}
+
+// A sopsTree decrypts the values of a SOPS document in the document order
+// and computes the MAC of the decrypted values like SOPS does.
+type sopsTree struct {
+	key               []byte
+	unencryptedSuffix string
+	encryptedSuffix   string
+	unencryptedRegex  *regexp.Regexp
+	encryptedRegex    *regexp.Regexp
+	macOnlyEncrypted  bool
+	hash              hash.Hash
+}
+
+func newSOPSTree(metadata map[string]interface{}, key []byte) (*sopsTree, error) {
+	t := &sopsTree{
+		key:               key,
+		unencryptedSuffix: cast.ToString(metadata["unencrypted_suffix"]),
+		encryptedSuffix:   cast.ToString(metadata["encrypted_suffix"]),
+		macOnlyEncrypted:  cast.ToBool(metadata["mac_only_encrypted"]),
+		hash:              sha512.New(),
+	}
+	var err error
+	if r := cast.ToString(metadata["unencrypted_regex"]); r != "" {
+		if t.unencryptedRegex, err = regexp.Compile(r); err != nil {
+			return nil, errors.Wrap(err, "invalid sops unencrypted_regex")
+		}
+	}
+	if r := cast.ToString(metadata["encrypted_regex"]); r != "" {
+		if t.encryptedRegex, err = regexp.Compile(r); err != nil {
+			return nil, errors.Wrap(err, "invalid sops encrypted_regex")
+		}
+	}
+	return t, nil
+}
+
+// encrypted returns true if values under the given path should be encrypted according to the sops metadata
+func (t *sopsTree) encrypted(path []string) bool {
+	encrypted := true
+	if t.unencryptedSuffix != "" {
+		for _, k := range path {
+			if strings.HasSuffix(k, t.unencryptedSuffix) {
+				encrypted = false
+				break
+			}
+		}
+	}
+	if t.encryptedSuffix != "" {
+		encrypted = false
+		for _, k := range path {
+			if strings.HasSuffix(k, t.encryptedSuffix) {
+				encrypted = true
+				break
+			}
+		}
+	}
+	if t.unencryptedRegex != nil {
+		for _, k := range path {
+			if t.unencryptedRegex.MatchString(k) {
+				encrypted = false
+				break
+			}
+		}
+	}
+	if t.encryptedRegex != nil {
+		encrypted = false
+		for _, k := range path {
+			if t.encryptedRegex.MatchString(k) {
+				encrypted = true
+				break
+			}
+		}
+	}
+	return encrypted
+}
+
+func (t *sopsTree) addToMAC(v interface{}, encrypted bool) error {
+	if t.macOnlyEncrypted && !encrypted {
+		return nil
+	}
+	b, err := macBytes(v)
+	if err != nil {
+		return err
+	}
+	t.hash.Write(b)
+	return nil
+}
+
+// leaf decrypts a scalar value.
+//
+// As for SOPS, the additional data of a value is the path of map keys leading to this value,
+// each key being followed by ':'. Arrays indexes are not part of the path.
+func (t *sopsTree) leaf(v interface{}, path []string) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	encrypted := t.encrypted(path)
+	if encrypted {
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.Errorf("value of %q is not encrypted", strings.Join(path, "/"))
+		}
+		var err error
+		v, err = decryptValue(s, t.key, strings.Join(path, ":")+":")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decrypt value of %q", strings.Join(path, "/"))
+		}
+	}
+	return v, errors.Wrapf(t.addToMAC(v, encrypted), "invalid value of %q", strings.Join(path, "/"))
+}
+
+// comments adds YAML comments to the MAC, comments are encrypted by SOPS unless they are
+// under an unencrypted path.
+func (t *sopsTree) comments(comments string, path []string) error {
+	for _, line := range strings.Split(comments, "\n") {
+		if line == "" {
+			continue
+		}
+		var v interface{} = line[1:]
+		encrypted := t.encrypted(path)
+		if encrypted {
+			if decrypted, err := decryptValue(line[1:], t.key, strings.Join(path, ":")+":"); err == nil {
+				v = decrypted
+			}
+		}
+		if err := t.addToMAC(v, encrypted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// value decrypts a YAML node, comments are taken into account in the same order than
+// the SOPS YAML store.
+func (t *sopsTree) value(node *yaml.Node, path []string, commentsHandled bool) (interface{}, error) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return t.mapping(node, path, false)
+	case yaml.SequenceNode:
+		var result []interface{}
+		if !commentsHandled {
+			if err := t.comments(node.HeadComment+"\n"+node.LineComment, path); err != nil {
+				return nil, err
+			}
+		}
+		for _, item := range node.Content {
+			if err := t.comments(item.HeadComment+"\n"+item.LineComment, path); err != nil {
+				return nil, err
+			}
+			v, err := t.value(item, path, true)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, v)
+			if err = t.comments(item.FootComment, path); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	case yaml.AliasNode:
+		return t.value(node.Alias, path, false)
+	case yaml.ScalarNode:
+		var v interface{}
+		if err := node.Decode(&v); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode value of %q", strings.Join(path, "/"))
+		}
+		return t.leaf(v, path)
+	default:
+		return nil, errors.Errorf("unexpected YAML node under %q", strings.Join(path, "/"))
+	}
+}
+
+func (t *sopsTree) mapping(node *yaml.Node, path []string, commentsHandled bool) (map[string]interface{}, error) {
+	if !commentsHandled {
+		if err := t.comments(node.HeadComment+"\n"+node.LineComment, path); err != nil {
+			return nil, err
+		}
+	}
+	result := make(map[string]interface{}, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if err := t.comments(key.HeadComment+"\n"+key.LineComment, path); err != nil {
+			return nil, err
+		}
+		valueComments := value.Kind == yaml.ScalarNode || value.Kind == yaml.AliasNode
+		if valueComments {
+			if err := t.comments(value.HeadComment+"\n"+value.LineComment, path); err != nil {
+				return nil, err
+			}
+		}
+		// sops metadata are not part of the MAC
+		if len(path) != 0 || key.Value != sopsMetadataKey {
+			v, err := t.value(value, append(path[:len(path):len(path)], key.Value), valueComments)
+			if err != nil {
+				return nil, err
+			}
+			result[key.Value] = v
+		}
+		if valueComments {
+			if err := t.comments(value.FootComment, path); err != nil {
+				return nil, err
+			}
+		}
+		if err := t.comments(key.FootComment, path); err != nil {
+			return nil, err
+		}
+	}
+	if !commentsHandled {
+		if err := t.comments(node.FootComment, path); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// checkMAC checks the MAC of the decrypted values against the one of the sops metadata
+func (t *sopsTree) checkMAC(metadata map[string]interface{}) error {
+	encMAC := cast.ToString(metadata["mac"])
+	if encMAC == "" {
+		return errors.New("sops MAC not found")
+	}
+	var lastModified time.Time
+	switch lm := metadata["lastmodified"].(type) {
+	case time.Time:
+		lastModified = lm
+	default:
+		var err error
+		lastModified, err = time.Parse(time.RFC3339, cast.ToString(lm))
+		if err != nil {
+			return errors.Wrap(err, "invalid sops lastmodified date")
+		}
+	}
+	mac, err := decryptValue(encMAC, t.key, lastModified.Format(time.RFC3339))
+	if err != nil {
+		return errors.Wrap(err, "failed to decrypt sops MAC")
+	}
+	if cast.ToString(mac) != fmt.Sprintf("%X", t.hash.Sum(nil)) {
+		return errors.New("sops MAC mismatch, the file has been modified")
+	}
+	return nil
+}
+
+// decryptSOPS decrypts a YAML or JSON SOPS file and verifies its MAC
+//
+// Values under a path that should be encrypted according to the sops metadata unencrypted or
+// encrypted suffixes and regular expressions are refused if they are not encrypted.
+func decryptSOPS(data []byte, decrypter *decrypt.Decrypter) (map[string]interface{}, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Wrap(err, "failed to decode secrets")
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("failed to decode secrets: expecting a map")
+	}
+	root := doc.Content[0]
+	var metadata map[string]interface{}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == sopsMetadataKey {
+			var raw interface{}
+			if err := root.Content[i+1].Decode(&raw); err != nil {
+				return nil, errors.Wrap(err, "failed to decode sops metadata")
+			}
+			metadata, _ = secret.Normalize(raw).(map[string]interface{})
+		}
+	}
+	if metadata == nil {
+		return nil, errors.New("sops metadata not found")
+	}
+	key, err := dataKey(metadata, decrypter)
+	if err != nil {
+		return nil, err
+	}
+	t, err := newSOPSTree(metadata, key)
+	if err != nil {
+		return nil, err
+	}
+	if err = t.comments(doc.HeadComment+"\n"+doc.LineComment, nil); err != nil {
+		return nil, err
+	}
+	tree, err := t.mapping(root, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	if err = t.comments(doc.FootComment, nil); err != nil {
+		return nil, err
+	}
+	if err = t.checkMAC(metadata); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
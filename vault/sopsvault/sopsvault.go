@@ -0,0 +1,96 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sopsvault provides a vault.Client resolving secrets from files encrypted with SOPS (https://github.com/mozilla/sops).
+//
+// Only age and PGP keys are supported to decrypt the SOPS data key, cloud KMS, HashiCorp Vault transit
+// and Shamir secret sharing of keys are not supported.
+package sopsvault
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/log"
+	"github.com/ystia/yorc/v4/vault"
+	"github.com/ystia/yorc/v4/vault/internal/decrypt"
+	"github.com/ystia/yorc/v4/vault/internal/secret"
+)
+
+type clientBuilder struct {
+}
+
+func (b *clientBuilder) BuildClient(cfg config.Configuration) (vault.Client, error) {
+	log.Debug("Setting up SOPS Vault Client")
+	path := cfg.Vault.GetString("path")
+	if path == "" {
+		return nil, errors.New("missing mandatory option \"path\" for sops vault")
+	}
+	decrypter, err := decrypt.FromConfig(cfg.Vault)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create sops vault client")
+	}
+	if !decrypter.HasAgeIdentities() {
+		// Like the sops command, fallback to age keys defined in the environment
+		if err = addAgeIdentitiesFromEnv(decrypter); err != nil {
+			return nil, errors.Wrap(err, "failed to create sops vault client")
+		}
+	}
+	c := &sopsClient{file: &secret.File{Path: path, Decode: func(p string) (map[string]interface{}, error) {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read file %q", p)
+		}
+		secrets, err := decryptSOPS(b, decrypter)
+		return secrets, errors.Wrapf(err, "failed to decrypt sops file %q", p)
+	}}}
+	// Check secrets file at startup
+	if _, err = c.file.Secrets(); err != nil {
+		return nil, errors.Wrap(err, "failed to create sops vault client")
+	}
+	return c, nil
+}
+
+func addAgeIdentitiesFromEnv(decrypter *decrypt.Decrypter) error {
+	if k := os.Getenv("SOPS_AGE_KEY"); k != "" {
+		if err := decrypter.AddAgeIdentities([]byte(k)); err != nil {
+			return errors.Wrap(err, "invalid SOPS_AGE_KEY")
+		}
+	}
+	if f := os.Getenv("SOPS_AGE_KEY_FILE"); f != "" {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return errors.Wrap(err, "failed to read SOPS_AGE_KEY_FILE")
+		}
+		if err = decrypter.AddAgeIdentities(b); err != nil {
+			return errors.Wrap(err, "invalid SOPS_AGE_KEY_FILE")
+		}
+	}
+	return nil
+}
+
+type sopsClient struct {
+	file *secret.File
+}
+
+func (c *sopsClient) GetSecret(id string, options ...string) (vault.Secret, error) {
+	return c.file.Get(id, options...)
+}
+
+func (c *sopsClient) Shutdown() error {
+	return nil
+}
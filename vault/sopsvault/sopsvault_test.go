@@ -0,0 +1,201 @@
+// Copyright 2019 Bull S.A.S. Atos Technologies - Bull, Rue Jean Jaures, B.P.68, 78340, Les Clayes-sous-Bois, France.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sopsvault
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	// Required by openpgp to encrypt messages for test entities
+	_ "golang.org/x/crypto/ripemd160"
+
+	"github.com/ystia/yorc/v4/config"
+	"github.com/ystia/yorc/v4/vault/internal/decrypt"
+)
+
+func encryptValue(t *testing.T, key []byte, value, valueType, additionalData string) string {
+	block, err := aes.NewCipher(key)
+	require.NoError(t, err)
+	iv := make([]byte, 32)
+	_, err = rand.Read(iv)
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(iv))
+	require.NoError(t, err)
+	out := gcm.Seal(nil, iv, []byte(value), []byte(additionalData))
+	data, tag := out[:len(out)-gcm.Overhead()], out[len(out)-gcm.Overhead():]
+	return fmt.Sprintf("ENC[AES256_GCM,data:%s,iv:%s,tag:%s,type:%s]", base64.StdEncoding.EncodeToString(data),
+		base64.StdEncoding.EncodeToString(iv), base64.StdEncoding.EncodeToString(tag), valueType)
+}
+
+// sopsMAC returns the encrypted SOPS MAC of the given values
+func sopsMAC(t *testing.T, key []byte, lastModified string, values ...string) string {
+	h := sha512.New()
+	for _, v := range values {
+		h.Write([]byte(v))
+	}
+	return encryptValue(t, key, fmt.Sprintf("%X", h.Sum(nil)), "str", lastModified)
+}
+
+func pgpEncrypt(t *testing.T, plaintext []byte, to *openpgp.Entity) string {
+	buf := new(bytes.Buffer)
+	aw, err := armor.Encode(buf, "PGP MESSAGE", nil)
+	require.NoError(t, err)
+	w, err := openpgp.Encrypt(aw, []*openpgp.Entity{to}, nil, nil, nil)
+	require.NoError(t, err)
+	_, err = w.Write(plaintext)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, aw.Close())
+	return buf.String()
+}
+
+func indent(s string) string {
+	return "          " + strings.Replace(strings.TrimSpace(s), "\n", "\n          ", -1)
+}
+
+func TestSOPSVault(t *testing.T) {
+	entity, err := openpgp.NewEntity("yorc", "test", "yorc@example.com", nil)
+	require.NoError(t, err)
+	keyBuf := new(bytes.Buffer)
+	aw, err := armor.Encode(keyBuf, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.SerializePrivate(aw, nil))
+	require.NoError(t, aw.Close())
+
+	dataKey := make([]byte, 32)
+	_, err = rand.Read(dataKey)
+	require.NoError(t, err)
+
+	sopsFile := fmt.Sprintf(`database:
+    user: %s
+    #%s
+    password: %s
+    port: %s
+    hosts:
+    - %s
+    comment_unencrypted: not encrypted
+enabled: %s
+sops:
+    lastmodified: "2020-06-11T09:10:39Z"
+    mac: %s
+    version: 3.5.0
+    unencrypted_suffix: _unencrypted
+    pgp:
+    -   created_at: "2020-06-11T09:10:39Z"
+        enc: |
+%s
+        fp: 0123456789ABCDEF
+`,
+		encryptValue(t, dataKey, "dbuser", "str", "database:user:"),
+		encryptValue(t, dataKey, " password comment", "str", "database:"),
+		encryptValue(t, dataKey, "dbpass", "str", "database:password:"),
+		encryptValue(t, dataKey, "5432", "int", "database:port:"),
+		encryptValue(t, dataKey, "db1.example.com", "str", "database:hosts:"),
+		encryptValue(t, dataKey, "true", "bool", "enabled:"),
+		sopsMAC(t, dataKey, "2020-06-11T09:10:39Z", "dbuser", " password comment", "dbpass", "5432", "db1.example.com", "not encrypted", "True"),
+		indent(pgpEncrypt(t, dataKey, entity)))
+	dir := t.TempDir()
+	path := filepath.Join(dir, "secrets.enc.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(sopsFile), 0600))
+
+	_, err = (&clientBuilder{}).BuildClient(config.Configuration{Vault: config.DynamicMap{}})
+	require.Error(t, err, "path should be mandatory")
+
+	client, err := (&clientBuilder{}).BuildClient(config.Configuration{Vault: config.DynamicMap{
+		"path":            path,
+		"pgp_private_key": keyBuf.String(),
+	}})
+	require.NoError(t, err)
+	defer client.Shutdown()
+
+	tests := []struct {
+		name    string
+		id      string
+		options []string
+		want    string
+		wantErr bool
+	}{
+		{"Value", "database/password", nil, "dbpass", false},
+		{"Map", "database", []string{"data=user"}, "dbuser", false},
+		{"Int", "database/port", nil, "5432", false},
+		{"List", "database/hosts", nil, "[db1.example.com]", false},
+		{"Bool", "enabled", nil, "true", false},
+		{"Unencrypted", "database/comment_unencrypted", nil, "not encrypted", false},
+		{"Metadata", "sops", nil, "", true},
+		{"NotFound", "database/unknown", nil, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := client.GetSecret(tt.id, tt.options...)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, s.String())
+		})
+	}
+
+	// A value moved to another path can't be decrypted
+	moved := strings.Replace(sopsFile, "enabled:", "other:", 1)
+	_, err = decryptSOPS([]byte(moved), mustDecrypter(t, keyBuf.String()))
+	require.Error(t, err)
+
+	// Removed values are detected by the MAC
+	var removed []string
+	for _, line := range strings.Split(sopsFile, "\n") {
+		if !strings.HasPrefix(line, "    port:") {
+			removed = append(removed, line)
+		}
+	}
+	_, err = decryptSOPS([]byte(strings.Join(removed, "\n")), mustDecrypter(t, keyBuf.String()))
+	require.Error(t, err)
+
+	// An encrypted value replaced by a clear value is refused
+	clear := strings.Replace(sopsFile, encryptedLine(sopsFile, "    user: "), "    user: dbuser", 1)
+	_, err = decryptSOPS([]byte(clear), mustDecrypter(t, keyBuf.String()))
+	require.Error(t, err)
+
+	// Not a sops file
+	_, err = decryptSOPS([]byte("key: value"), mustDecrypter(t, keyBuf.String()))
+	require.Error(t, err)
+}
+
+func encryptedLine(data, prefix string) string {
+	for _, line := range strings.Split(data, "\n") {
+		if strings.HasPrefix(line, prefix) {
+			return line
+		}
+	}
+	return ""
+}
+
+func mustDecrypter(t *testing.T, pgpKey string) *decrypt.Decrypter {
+	d, err := decrypt.FromConfig(config.DynamicMap{"pgp_private_key": pgpKey})
+	require.NoError(t, err)
+	return d
+}
@@ -16,6 +16,7 @@ package vault
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ystia/yorc/v4/config"
 )
@@ -47,6 +48,28 @@ type Secret interface {
 	Raw() interface{}
 }
 
+// A LeasedSecret is a Secret with a lease, like dynamic secrets generated on demand.
+//
+// Leased secrets used by a deployment are renewed while the deployment is live and
+// revoked when it is undeployed if the Client is a LeaseManager.
+type LeasedSecret interface {
+	Secret
+	// LeaseID returns the ID of the lease, an empty string means that the secret has no lease
+	LeaseID() string
+	// LeaseDuration returns the duration of the lease
+	LeaseDuration() time.Duration
+	// Renewable returns true if the lease can be renewed
+	Renewable() bool
+}
+
+// A LeaseManager is a Client able to renew and revoke secrets leases
+type LeaseManager interface {
+	// RenewLease renews a lease for the given increment and returns the new lease duration
+	RenewLease(leaseID string, increment time.Duration) (time.Duration, error)
+	// RevokeLease revokes a lease, the secret is then no longer valid
+	RevokeLease(leaseID string) error
+}
+
 // A ClientBuilder builds a Vault client based on Yorc configuration
 type ClientBuilder interface {
 	// BuildClient builds a Vault client based on Yorc configuration